@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/admin"
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/export"
+	"blob-preconfs/pkg/tlsconfig"
+
+	"github.com/spf13/cobra"
+)
+
+// Talks to a running node's admin API
+type adminClient struct {
+	URL   string
+	Token string
+	TLS   tlsconfig.ClientConfig
+}
+
+func (c *adminClient) addFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&c.URL, "admin-url", "http://127.0.0.1:9200", "Admin API of the running node, from admin.addr in --config if unset")
+	flags.StringVar(&c.Token, "admin-token", "", "Bearer token for the admin API, admin.token from --config if unset")
+	flags.StringVar(&c.TLS.CAFile, "admin-ca-file", "", "CAs to verify the admin API with, the system roots if empty")
+	flags.StringVar(&c.TLS.CertFile, "admin-cert-file", "", "Client certificate, if the admin API requires one")
+	flags.StringVar(&c.TLS.KeyFile, "admin-key-file", "", "Client key, if the admin API requires a certificate")
+}
+
+// Fills the URL and token not set by flag or environment from the node config file, if given
+func (c *adminClient) resolve(cmd *cobra.Command) error {
+	path, _ := cmd.Flags().GetString(configFlag)
+	if path == "" {
+		return nil
+	}
+	node, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("admin-url") && node.Admin.Addr != "" {
+		c.URL = adminURL(node)
+	}
+	if !cmd.Flags().Changed("admin-token") {
+		c.Token = node.Admin.Token
+	}
+	return nil
+}
+
+// Reaches the admin API on the same host, if it listens on every interface
+func adminURL(node config.Config) string {
+	scheme := "http"
+	if tlsSettings(node.TLS).Enabled() {
+		scheme = "https"
+	}
+	host, port, _ := net.SplitHostPort(node.Admin.Addr)
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
+
+// Returns the response if successful, otherwise the API's error
+func (c *adminClient) do(cmd *cobra.Command, method, path string, body io.Reader) (*http.Response, error) {
+	if err := c.resolve(cmd); err != nil {
+		return nil, err
+	}
+	if c.Token == "" {
+		return nil, fmt.Errorf("%w: --admin-token: required", config.ErrInvalidConfig)
+	}
+	tlsConfig, err := c.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	req, err := http.NewRequestWithContext(cmd.Context(), method, strings.TrimSuffix(c.URL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&apiErr)
+		return nil, fmt.Errorf("admin api returned %s: %s", resp.Status, apiErr.Error)
+	}
+	return resp, nil
+}
+
+func newStatusCommand() *cobra.Command {
+	var client adminClient
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether a running node's auctions are paused, its relay access lists, signing keys and settlement key funding",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := client.do(cmd, http.MethodGet, "/admin/v1/status", nil)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			var status admin.StatusResponse
+			if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+				return fmt.Errorf("failed to decode status: %w", err)
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "version:   %s\n", status.Version)
+			fmt.Fprintf(out, "paused:    %t\n", status.Paused)
+			fmt.Fprintf(out, "allowlist: %d relays\n", len(status.Allowlist))
+			for _, address := range status.Allowlist {
+				fmt.Fprintf(out, "  %s\n", address.Hex())
+			}
+			fmt.Fprintf(out, "denylist:  %d relays\n", len(status.Denylist))
+			for _, address := range status.Denylist {
+				fmt.Fprintf(out, "  %s\n", address.Hex())
+			}
+			if signers := status.Signers; signers != nil {
+				fmt.Fprintf(out, "signer:    %s\n", signers.Active.Hex())
+				if signers.Previous != nil {
+					fmt.Fprintf(out, "  previous %s valid until %s\n", signers.Previous.Hex(), signers.PreviousUntil.Format(time.RFC3339))
+				}
+			}
+			if funding := status.Funding; funding != nil {
+				fmt.Fprintf(out, "funding:   %s wei, %d settlements, %s runway", funding.BalanceWei, funding.Settlements, funding.Runway)
+				if funding.Low {
+					fmt.Fprint(out, " (low)")
+				}
+				fmt.Fprintln(out)
+				if funding.Error != "" {
+					fmt.Fprintf(out, "  last check failed: %s\n", funding.Error)
+				}
+			}
+			return nil
+		},
+	}
+	client.addFlags(cmd)
+	return cmd
+}
+
+func newExportCommand() *cobra.Command {
+	var client adminClient
+	var format, output string
+	var fromBlock, toBlock uint64
+	cmd := &cobra.Command{
+		Use:   "export auctions|bids|settlements",
+		Short: "Export a running node's auction history as CSV or Parquet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := export.ParseKind(args[0])
+			if err != nil {
+				return err
+			}
+			if _, err := export.ParseFormat(format); err != nil {
+				return err
+			}
+			query := url.Values{"format": {format}}
+			if fromBlock > 0 {
+				query.Set("fromBlock", strconv.FormatUint(fromBlock, 10))
+			}
+			if toBlock > 0 {
+				query.Set("toBlock", strconv.FormatUint(toBlock, 10))
+			}
+			resp, err := client.do(cmd, http.MethodGet, "/admin/v1/export/"+string(kind)+"?"+query.Encode(), nil)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			return writeOutput(cmd, output, resp.Body)
+		},
+	}
+	client.addFlags(cmd)
+	cmd.Flags().StringVar(&format, "format", "csv", "csv or parquet")
+	cmd.Flags().StringVarP(&output, "output", "o", "-", "File to write, - for stdout")
+	cmd.Flags().Uint64Var(&fromBlock, "from-block", 0, "First L1 block to export, 0 for the earliest")
+	cmd.Flags().Uint64Var(&toBlock, "to-block", 0, "Last L1 block to export, 0 for the latest")
+	return cmd
+}
+
+func newSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Back up or restore a running node's history",
+	}
+	var saveClient adminClient
+	var output string
+	save := &cobra.Command{
+		Use:   "save",
+		Short: "Download a point-in-time snapshot of history, while auctions keep running",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				output = fmt.Sprintf("snapshot-%d.jsonl.gz", time.Now().Unix())
+			}
+			resp, err := saveClient.do(cmd, http.MethodGet, "/admin/v1/store/snapshot", nil)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if err := writeOutput(cmd, output, resp.Body); err != nil {
+				return err
+			}
+			if output != "-" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "snapshot written to %s\n", output)
+			}
+			return nil
+		},
+	}
+	saveClient.addFlags(save)
+	save.Flags().StringVarP(&output, "output", "o", "", "File to write, - for stdout, snapshot-<unix time>.jsonl.gz if empty")
+
+	var restoreClient adminClient
+	restore := &cobra.Command{
+		Use:   "restore FILE",
+		Short: "Replace history with a snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			resp, err := restoreClient.do(cmd, http.MethodPost, "/admin/v1/store/restore", file)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			fmt.Fprintf(cmd.ErrOrStderr(), "history restored from %s\n", args[0])
+			return nil
+		},
+	}
+	restoreClient.addFlags(restore)
+	cmd.AddCommand(save, restore)
+	return cmd
+}
+
+// Removes a partially written file on failure, so a truncated export isn't mistaken for a complete one
+func writeOutput(cmd *cobra.Command, output string, r io.Reader) error {
+	if output == "-" {
+		_, err := io.Copy(cmd.OutOrStdout(), r)
+		return err
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		os.Remove(output)
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return file.Close()
+}
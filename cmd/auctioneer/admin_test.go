@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAdminAPI(t *testing.T) (*httptest.Server, *[]byte) {
+	var restored []byte
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid token"}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/status":
+			w.Write([]byte(`{"paused":true,"allowlist":["0x0000000000000000000000000000000000000001"],"denylist":[],"version":{"version":"v1.2.0","goVersion":"go1.21.4"},"funding":{"balanceWei":1000,"settlements":2,"runwayNs":24000000000,"low":true}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/export/bids":
+			w.Write([]byte(r.URL.RawQuery))
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/store/snapshot":
+			w.Write([]byte("snapshot"))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/store/restore":
+			restored, _ = io.ReadAll(r.Body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &restored
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	root := newRootCommand()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestAdminCommands(t *testing.T) {
+	server, restored := newTestAdminAPI(t)
+	auth := []string{"--admin-url", server.URL, "--admin-token", "secret"}
+
+	out, err := execute(t, append([]string{"status"}, auth...)...)
+	require.NoError(t, err)
+	require.Contains(t, out, "version:   v1.2.0 go1.21.4")
+	require.Contains(t, out, "paused:    true")
+	require.Contains(t, out, "0x0000000000000000000000000000000000000001")
+	require.Contains(t, out, "funding:   1000 wei, 2 settlements, 24s runway (low)")
+
+	out, err = execute(t, append([]string{"export", "bids", "--format", "parquet", "--from-block", "100"}, auth...)...)
+	require.NoError(t, err)
+	require.Equal(t, "format=parquet&fromBlock=100", out)
+	_, err = execute(t, append([]string{"export", "relays"}, auth...)...)
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "snapshot.jsonl.gz")
+	_, err = execute(t, append([]string{"snapshot", "save", "-o", path}, auth...)...)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "snapshot", string(data))
+	_, err = execute(t, append([]string{"snapshot", "restore", path}, auth...)...)
+	require.NoError(t, err)
+	require.Equal(t, "snapshot", string(*restored))
+
+	_, err = execute(t, "status", "--admin-url", server.URL, "--admin-token", "wrong")
+	require.ErrorContains(t, err, "invalid token")
+	_, err = execute(t, "status", "--admin-url", server.URL)
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+
+	host := server.Listener.Addr().String()
+	node := writeConfig(t, "node.yaml", "admin:\n  addr: "+host+"\n  token: secret\n")
+	out, err = execute(t, "status", "--config", node)
+	require.NoError(t, err, "the admin api's address and token are read from the node config")
+	require.Contains(t, out, "paused:    true")
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const configFlag = "config"
+
+// Fills flags not set on the command line from the environment
+func applyConfig(cmd *cobra.Command) error {
+	var errs []error
+	var unset []*pflag.Flag
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			unset = append(unset, f)
+		}
+	})
+	for _, f := range unset {
+		if value, ok := os.LookupEnv(envName(f.Name)); ok {
+			if err := cmd.Flags().Set(f.Name, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", envName(f.Name), err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", config.ErrInvalidConfig, errors.Join(errs...))
+	}
+	return nil
+}
+
+// e.g. AUCTIONEER_L1_RPC_URL for l1-rpc-url, matching config.EnvName for config keys
+func envName(flag string) string {
+	return config.EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Check node configuration",
+	}
+	validate := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the config file passed with --config, with environment overrides, and its engines' files, without starting the node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString(configFlag)
+			c, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			if err := c.Validate(); err != nil {
+				return err
+			}
+			if _, _, err := engineConfigs(c); err != nil {
+				return err
+			}
+			if path == "" {
+				path = "config"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", path)
+			return nil
+		},
+	}
+	cmd.AddCommand(validate)
+	return cmd
+}
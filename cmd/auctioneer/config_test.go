@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestApplyConfig(t *testing.T) {
+	t.Setenv("AUCTIONEER_ADMIN_URL", "https://auctioneer:9200")
+	t.Setenv("AUCTIONEER_FORMAT", "parquet")
+	root := newRootCommand()
+	export, _, err := root.Find([]string{"export"})
+	require.NoError(t, err)
+	export.RunE = func(cmd *cobra.Command, args []string) error { return nil }
+	root.SetArgs([]string{"export", "bids", "--format", "csv"})
+	require.NoError(t, root.Execute())
+	require.Equal(t, "https://auctioneer:9200", export.Flags().Lookup("admin-url").Value.String())
+	require.Equal(t, "csv", export.Flags().Lookup("format").Value.String(), "flags take precedence")
+
+	t.Setenv("AUCTIONEER_FROM_BLOCK", "latest")
+	root = newRootCommand()
+	root.SetArgs([]string{"export", "bids"})
+	err = root.Execute()
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+	require.ErrorContains(t, err, "AUCTIONEER_FROM_BLOCK")
+}
+
+func TestConfigValidateCommand(t *testing.T) {
+	validate := func(args ...string) (string, error) {
+		root := newRootCommand()
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs(append([]string{"config", "validate"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+	valid := "[l1]\nrpc-url = \"http://localhost:8545\"\n[signer]\nkey-file = \"key\"\n[admin]\ntoken = \"secret\"\n"
+	path := writeConfig(t, "node.toml", valid)
+	out, err := validate("--config", path)
+	require.NoError(t, err)
+	require.Equal(t, path+" is valid\n", out)
+
+	t.Setenv("AUCTIONEER_STORE_BACKEND", "redis")
+	_, err = validate("--config", path)
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+	require.ErrorContains(t, err, "store.backend: unknown backend")
+}
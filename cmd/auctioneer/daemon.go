@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/logging"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var errAlreadyRunning = errors.New("auctioneer already running")
+
+// Relays registered on the settlement layer are configured statically, there's no settlement layer client yet
+type staticRegistry struct {
+	mu     sync.RWMutex
+	relays map[common.Address]struct{}
+}
+
+func newStaticRegistry(relays []string) *staticRegistry {
+	r := &staticRegistry{}
+	r.Replace(relays)
+	return r
+}
+
+func (r *staticRegistry) Replace(relays []string) {
+	registered := make(map[common.Address]struct{}, len(relays))
+	for _, relay := range relays {
+		registered[common.HexToAddress(relay)] = struct{}{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.relays = registered
+}
+
+func (r *staticRegistry) IsRegisteredOnSettlementLayer(address common.Address) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.relays[address]
+	return ok
+}
+
+// Bid quotas by relays' tiers in the registry. Satisfies listener.BidQuotas.
+type relayTiers struct {
+	// Quota of relays without a tier
+	quota int
+	// Quotas of relays with a tier, by address
+	tiered map[common.Address]int
+}
+
+// Tiers and quotas are validated with the config
+func newRelayTiers(r config.RegistryConfig) *relayTiers {
+	t := &relayTiers{quota: r.BidQuota, tiered: make(map[common.Address]int, len(r.Tiers))}
+	for relay, tier := range r.Tiers {
+		t.tiered[common.HexToAddress(relay)], _ = strconv.Atoi(r.TierQuotas[tier])
+	}
+	return t
+}
+
+func (t *relayTiers) BidQuota(relay common.Address) int {
+	if quota, ok := t.tiered[relay]; ok {
+		return quota
+	}
+	return t.quota
+}
+
+// Writes the process ID to path, returning a func removing it on exit. Fails if the file
+// names a process that's still running, so two nodes can't run from the same config.
+func writePIDFile(path string) (func(), error) {
+	if data, err := os.ReadFile(path); err == nil {
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && processRunning(pid) {
+			return nil, fmt.Errorf("%w: pid %d in %s", errAlreadyRunning, pid, path)
+		}
+	}
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write pid file: %w", err)
+	}
+	return func() { os.Remove(path) }, nil
+}
+
+func processRunning(pid int) bool {
+	if pid == os.Getpid() {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Signal 0 checks the process exists without signalling it
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// Keys applied to the running node on reload, others take effect on restart
+var reloadableKeys = map[string]bool{
+	"log.level":         true,
+	"log.modules":       true,
+	"auction.allowlist": true,
+	"auction.denylist":  true,
+	"registry.relays":   true,
+}
+
+// Re-reads the config on SIGHUP or from the admin API. Satisfies admin.ConfigReloader.
+type reloader struct {
+	logger     *slog.Logger
+	load       func() (config.Config, error)
+	logs       *logging.Logging
+	accessList *auction.AccessList
+	registry   *staticRegistry
+
+	mu      sync.Mutex // Serializes reloads
+	current config.Config
+}
+
+// Access lists are only replaced if the configured ones changed, so changes made from the admin API survive
+// reloads for other keys
+func (r *reloader) Reload(ctx context.Context) error {
+	next, err := r.load()
+	if err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.logs.SetLevels(next.Log.Level, next.Log.Modules); err != nil {
+		return err
+	}
+	var changed, restart []string
+	currentFields := r.current.Fields()
+	for i, field := range next.Fields() {
+		if reflect.DeepEqual(reflect.ValueOf(field.Value).Elem().Interface(), reflect.ValueOf(currentFields[i].Value).Elem().Interface()) {
+			continue
+		}
+		if reloadableKeys[field.Key] {
+			changed = append(changed, field.Key)
+		} else {
+			restart = append(restart, field.Key)
+		}
+	}
+	if !reflect.DeepEqual(next.Auction.Allowlist, r.current.Auction.Allowlist) || !reflect.DeepEqual(next.Auction.Denylist, r.current.Auction.Denylist) {
+		r.accessList.Replace(accessLists(next.Auction))
+	}
+	if !reflect.DeepEqual(next.Registry.Relays, r.current.Registry.Relays) {
+		r.registry.Replace(next.Registry.Relays)
+	}
+	r.current = next
+	r.logger.Info("config reloaded", "changed", changed)
+	if len(restart) > 0 {
+		r.logger.Warn("changed config keys take effect on restart", "keys", restart)
+	}
+	return nil
+}
+
+// The built-in whitelist is allowed if no allowlist is configured
+func accessLists(c config.AuctionConfig) ([]common.Address, []common.Address) {
+	allowed := addresses(c.Allowlist)
+	if len(allowed) == 0 {
+		allowed = auction.DefaultAccessList().Allowed()
+	}
+	return allowed, addresses(c.Denylist)
+}
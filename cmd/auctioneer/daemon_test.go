@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/logging"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPIDFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auctioneer.pid")
+	remove, err := writePIDFile(path)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(data))
+	remove()
+	require.NoFileExists(t, path)
+
+	// The parent of the test process is running
+	require.NoError(t, os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())), 0o644))
+	_, err = writePIDFile(path)
+	require.ErrorIs(t, err, errAlreadyRunning)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a pid"), 0o644))
+	remove, err = writePIDFile(path)
+	require.NoError(t, err, "a stale pid file is replaced")
+	remove()
+}
+
+func TestReloader(t *testing.T) {
+	relay := "0x0000000000000000000000000000000000000001"
+	current := config.Default()
+	current.Log.Level = "warn"
+	next := current
+	var logs bytes.Buffer
+	r := &reloader{
+		logger:     slog.New(slog.NewTextHandler(&logs, nil)),
+		load:       func() (config.Config, error) { return next, nil },
+		logs:       newTestLogging(t),
+		accessList: auction.DefaultAccessList(),
+		registry:   newStaticRegistry(nil),
+		current:    current,
+	}
+	next.L1.RPCURL = "http://localhost:8545"
+	next.Signer.KeyFile = "key"
+	next.Admin.Token = "secret"
+	next.Log.Level = "debug"
+	next.Registry.Relays = []string{relay}
+	next.Auction.Denylist = []string{relay}
+	require.NoError(t, r.Reload(context.Background()))
+
+	require.True(t, r.registry.IsRegisteredOnSettlementLayer(common.HexToAddress(relay)))
+	require.True(t, r.accessList.IsDenied(common.HexToAddress(relay)))
+	require.NotEmpty(t, r.accessList.Allowed(), "the built-in whitelist stays allowed")
+	require.True(t, r.logs.Module("listener").Enabled(context.Background(), slog.LevelDebug))
+	require.Contains(t, logs.String(), "changed config keys take effect on restart")
+	require.Contains(t, logs.String(), "l1.rpc-url")
+
+	// Admin API changes survive reloads that don't change the access lists
+	r.accessList.RemoveDenied(common.HexToAddress(relay))
+	require.NoError(t, r.Reload(context.Background()))
+	require.False(t, r.accessList.IsDenied(common.HexToAddress(relay)))
+
+	next.L1.RPCURL = ""
+	require.ErrorIs(t, r.Reload(context.Background()), config.ErrInvalidConfig)
+	require.Equal(t, "http://localhost:8545", r.current.L1.RPCURL, "an invalid config isn't applied")
+}
+
+func newTestLogging(t *testing.T) *logging.Logging {
+	logs, err := logging.New(logging.Config{Level: "warn", File: filepath.Join(t.TempDir(), "auctioneer.log")})
+	require.NoError(t, err)
+	t.Cleanup(func() { logs.Close() })
+	return logs
+}
+
+func TestRelayTiers(t *testing.T) {
+	premium, basic := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	tiers := newRelayTiers(config.RegistryConfig{
+		BidQuota:   10,
+		Tiers:      map[string]string{premium.Hex(): "premium", common.HexToAddress("0x03").Hex(): "unlimited"},
+		TierQuotas: map[string]string{"premium": "20", "unlimited": "0"},
+	})
+	require.Equal(t, 20, tiers.BidQuota(premium))
+	require.Equal(t, 10, tiers.BidQuota(basic), "relays without a tier get the default quota")
+	require.Equal(t, 0, tiers.BidQuota(common.HexToAddress("0x03")))
+}
@@ -0,0 +1,676 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/admin"
+	"blob-preconfs/pkg/alerting"
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/audit"
+	"blob-preconfs/pkg/award"
+	"blob-preconfs/pkg/bulletin"
+	"blob-preconfs/pkg/chaos"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/crosscheck"
+	"blob-preconfs/pkg/election"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/eventstream"
+	"blob-preconfs/pkg/funding"
+	"blob-preconfs/pkg/gasoracle"
+	"blob-preconfs/pkg/gossip"
+	"blob-preconfs/pkg/heartbeat"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/issuer"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/logging"
+	"blob-preconfs/pkg/metrics"
+	"blob-preconfs/pkg/missedslot"
+	"blob-preconfs/pkg/policy"
+	"blob-preconfs/pkg/pricing"
+	"blob-preconfs/pkg/recovery"
+	"blob-preconfs/pkg/replay"
+	"blob-preconfs/pkg/reputation"
+	"blob-preconfs/pkg/retention"
+	"blob-preconfs/pkg/simulation"
+	"blob-preconfs/pkg/store"
+	"blob-preconfs/pkg/timesync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	libp2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+)
+
+// One chain's auctions: its L1 node, history, relay registry, listener and commitment coordinator. A process
+// runs an engine for its own chain, and one for each of config.Engines, behind shared servers.
+type engine struct {
+	// Empty in a single chain process, whose logs and metrics aren't labelled with the chain
+	name string
+	c    config.Config
+	logs *logging.Logging
+
+	logger      *slog.Logger
+	ethClient   *ethclient.Client
+	history     store.Store
+	static      *staticRegistry
+	registry    auction.RelayRegistry
+	resyncer    admin.RegistryResyncer
+	listener    *listener.Listener
+	relays      relayBackend
+	coordinator *commitment.Coordinator
+	metrics     *metrics.Metrics
+	faults      *chaos.Injector
+	// Nil unless the registry source bonds relays
+	escrow *escrow.Ledger
+	// Nil if clock.max-drift is 0
+	clock *timesync.Guard
+	// Prices settlement txs
+	gas *gasoracle.Oracle
+	// Nil if funding.interval is 0
+	funding *funding.Watcher
+	// Nil unless heartbeat.enabled
+	heartbeat *heartbeat.Beacon
+	// Nil without watchers.addresses
+	attestations *attestation.Quorum
+	// Nil without award.endpoints
+	awards *award.Notifier
+	// Nil unless intake.enabled
+	intake     *intake.Pool
+	reputation *reputation.Tracker
+	// Nil without alert webhooks
+	notifier *alerting.Notifier
+	// Nil unless federated with other replicas
+	elector    *election.Elector
+	crossCheck *crosscheck.Checker
+
+	shadowMu sync.Mutex // Protects shadowed
+	// Latest winner a follower left to the leader, settled if it takes over mid-slot
+	shadowed *auction.SignedBid
+
+	done       <-chan struct{}
+	auctionWon <-chan auction.SignedBid
+	// Hands won auctions to settlement, once the winner accepted its award if there's a handshake
+	settle func(auction.SignedBid)
+	submit func(auction.SignedBid)
+	// Run in reverse order once the engine stopped
+	closers []func()
+}
+
+// Configs of the process's engines by name, its own chain's first. A single chain's engine is unnamed.
+func engineConfigs(c config.Config) ([]string, map[string]config.Config, error) {
+	if len(c.Engines) == 0 {
+		return []string{""}, map[string]config.Config{"": c}, nil
+	}
+	names := make([]string, 0, len(c.Engines))
+	configs := map[string]config.Config{c.EngineName(): c}
+	for name, path := range c.Engines {
+		engineConfig, err := config.LoadEngine(c, path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("engine %s: %w", name, err)
+		}
+		if err := engineConfig.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("engine %s: %w", name, err)
+		}
+		names = append(names, name)
+		configs[name] = engineConfig
+	}
+	sort.Strings(names)
+	return append([]string{c.EngineName()}, names...), configs, nil
+}
+
+func (e *engine) module(name string) *slog.Logger {
+	logger := e.logs.Module(name)
+	if e.name != "" {
+		logger = logger.With("chain", e.name)
+	}
+	return logger
+}
+
+func (e *engine) onClose(close func()) {
+	e.closers = append(e.closers, close)
+}
+
+// Opens the engine's L1 node, history and registry, and recovers its state. Whatever was opened is closed by
+// close, even if opening failed.
+func (e *engine) open(ctx context.Context, signingKey *ecdsa.PrivateKey) error {
+	c := e.c
+	e.logger = e.logs.Logger()
+	if e.name != "" {
+		e.logger = e.logger.With("chain", e.name)
+	}
+	history, err := openStore(ctx, c)
+	if err != nil {
+		return err
+	}
+	e.history = history
+	e.onClose(func() { history.Close() })
+	ethClient, err := ethclient.DialContext(ctx, c.L1.RPCURL)
+	if err != nil {
+		return fmt.Errorf("failed to dial l1 rpc: %w", err)
+	}
+	e.ethClient = ethClient
+	e.onClose(ethClient.Close)
+	if err := checkChainID(ctx, ethClient, c.Network().ChainID); err != nil {
+		return err
+	}
+	e.static = newStaticRegistry(c.Registry.Relays)
+	e.registry, e.resyncer, err = openRegistry(ctx, e.module, c, e.static, ethClient)
+	if err != nil {
+		return err
+	}
+	if bonds, ok := e.registry.(escrow.Bonds); ok {
+		e.escrow = escrow.NewLedger(bonds, history)
+	}
+
+	l := listener.NewListener(e.module("listener"), ethClient, e.registry, nil)
+	l.SetPollInterval(c.L1.PollInterval)
+	l.SetAuctionPeriod(c.Auction.Period)
+	if c.Auction.EscrowCheck && e.escrow != nil {
+		l.SetEscrowCheck(escrow.NewCache(e.escrow, c.Auction.EscrowCacheTTL))
+	}
+	if c.Auction.BondLowPercent > 0 && e.escrow != nil {
+		prompter := escrow.NewPrompter(e.module("escrow"), e.escrow, c.Auction.BondLowPercent, l)
+		l.SetBidCaps(prompter)
+		events, sub := l.SubscribeEvents(64)
+		e.onClose(sub.Unsubscribe)
+		go prompter.Watch(ctx, events)
+	}
+	l.SetBidVerifiers(c.Auction.Verifiers)
+	l.SetBidShards(c.Auction.Shards)
+	l.SetEarlyClose(c.Auction.MinOpen, c.Auction.QuietPeriod)
+	l.SetPreAuctionWindow(c.Auction.PreOpenWindow)
+	if c.Registry.BidQuota > 0 || len(c.Registry.Tiers) > 0 {
+		l.SetBidQuotas(newRelayTiers(c.Registry))
+	}
+	if c.Sealed.Enabled() {
+		opener, err := openSealed(c.Sealed)
+		if err != nil {
+			return err
+		}
+		l.SetSealedBids(opener)
+	}
+	if c.Auction.CloseOffset > 0 {
+		network := c.Network()
+		l.SetSlotSchedule(network.GenesisTime, network.SlotTime, c.Auction.OpenOffset, c.Auction.CloseOffset)
+	}
+	if a := c.Auction; a.MissedSlotPeriod > 0 || a.ReserveWei > 0 || a.BlobFeeSpikeWei > 0 || c.Reserve.Dynamic {
+		config := policy.Config{SlotTime: c.Network().SlotTime, MissedSlotPeriod: a.MissedSlotPeriod}
+		if a.ReserveWei > 0 {
+			config.ReservePriceWei = new(big.Int).SetUint64(a.ReserveWei)
+		}
+		if a.BlobFeeSpikeWei > 0 {
+			config.BlobFeeSpikeWei, config.SpikeReservePriceWei = new(big.Int).SetUint64(a.BlobFeeSpikeWei), new(big.Int).SetUint64(a.SpikeReserveWei)
+		}
+		p := policy.New(e.module("policy"), config, ethClient)
+		if c.Reserve.Dynamic {
+			controller := policy.NewController(e.module("policy"), reserveControl(a, c.Reserve))
+			p.SetReserveController(controller)
+			events, sub := l.SubscribeEvents(64)
+			e.onClose(sub.Unsubscribe)
+			go controller.Watch(ctx, events)
+		}
+		l.SetAuctionPolicy(p)
+	}
+	l.SetRecorder(history)
+	l.SetMetrics(e.metrics)
+	if len(c.Auction.Allowlist) > 0 || len(c.Auction.Denylist) > 0 {
+		l.AccessList().Replace(accessLists(c.Auction))
+	}
+	if c.Clock.MaxDrift > 0 {
+		e.clock = timesync.NewGuard(e.module("timesync"), timesync.Config{
+			MaxDrift:  c.Clock.MaxDrift,
+			NTPServer: c.Clock.NTPServer,
+			Interval:  c.Clock.Interval,
+		}, ethClient)
+		e.clock.SetMetrics(e.metrics)
+		l.SetClockGuard(e.clock)
+	}
+	if len(c.Award.Endpoints) > 0 {
+		endpoints := make(map[common.Address]string, len(c.Award.Endpoints))
+		for relay, endpoint := range c.Award.Endpoints {
+			endpoints[common.HexToAddress(relay)] = endpoint
+		}
+		e.awards, err = award.NewNotifier(e.module("award"), award.Config{
+			Endpoints:      endpoints,
+			Attempts:       c.Award.Attempts,
+			Timeout:        c.Award.Timeout,
+			AcceptDeadline: c.Award.AcceptDeadline,
+		}, signingKey)
+		if err != nil {
+			return err
+		}
+		e.awards.SetObserver(e)
+		e.onClose(e.awards.Close)
+	}
+	e.reputation = reputation.NewTracker()
+	e.listener = l
+	// Commitments missed with their slot are the proposer's fault, not the relay's, once missed slots are detected
+	var classifier commitment.MissClassifier
+	if outcome := c.Auction.MissedSlotOutcome; outcome != "" {
+		detector := missedslot.NewDetector(e.module("missedslot"), missedslot.Config{
+			SlotTime: c.Network().SlotTime,
+			Outcome:  slotOutcome(outcome),
+			Grace:    c.Auction.MissedSlotGrace,
+		}, ethClient, l)
+		detector.SetRecorder(history)
+		detector.SetReputation(e.reputation)
+		events, sub := l.SubscribeEvents(64)
+		e.onClose(sub.Unsubscribe)
+		go detector.Watch(ctx, events)
+		classifier = detector
+	}
+	// Renewals are re-quoted, keeping their original fee without pricing
+	var quoter *pricing.BlobQuoter
+	var renewalQuoter commitment.Quoter
+	if c.Pricing.FeePerBlobWei > 0 {
+		quoter = pricing.NewBlobQuoter(pricing.Config{
+			FeePerBlobWei:    new(big.Int).SetUint64(c.Pricing.FeePerBlobWei),
+			AtomicPremiumBps: c.Pricing.AtomicPremiumBps,
+		})
+		renewalQuoter = quoter
+	}
+	e.coordinator = commitment.NewCoordinator(e.module("commitment"), commitment.Config{
+		EscalateProposerFaults: c.Commitment.EscalateProposerFaults,
+		MaxEscalations:         c.Commitment.MaxEscalations,
+		AutoRenew:              c.Commitment.AutoRenew,
+		MaxRenewals:            c.Commitment.MaxRenewals,
+	}, classifier, renewalQuoter, signingKey)
+	e.coordinator.SetRecorder(history)
+
+	var auditors auction.MultiAuditor
+	// Relays' commitment misses count against their reputation
+	observers := commitment.MultiObserver{e.reputation}
+	if c.Audit.Log != "" {
+		auditLog, err := audit.NewLog(e.module("audit"), c.Audit.Log)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log: %w", err)
+		}
+		e.onClose(func() { auditLog.Close() })
+		auditors = append(auditors, auditLog)
+	}
+	if c.Audit.Recording != "" {
+		recorder, err := replay.NewRecorder(e.module("replay"), c.Audit.Recording)
+		if err != nil {
+			return fmt.Errorf("failed to open recording: %w", err)
+		}
+		e.onClose(func() { recorder.Close() })
+		auditors = append(auditors, recorder)
+		events, sub := l.SubscribeEvents(64)
+		e.onClose(sub.Unsubscribe)
+		go recorder.Watch(ctx, events)
+	}
+	var emitter *eventstream.Emitter
+	if c.Event.Sink != "" {
+		sink, err := eventstream.NewSink(eventSink(c.Event))
+		if err != nil {
+			return err
+		}
+		emitter = eventstream.NewEmitter(e.module("eventstream"), sink, c.Event.BufferSize)
+		e.onClose(func() { emitter.Close() })
+		auditors = append(auditors, emitter)
+		observers = append(observers, emitter)
+		events, sub := l.SubscribeEvents(c.Event.BufferSize)
+		e.onClose(sub.Unsubscribe)
+		go emitter.Watch(ctx, events)
+	}
+	if webhooks := webhooks(c.Alert); len(webhooks) > 0 {
+		notifier, err := alerting.NewNotifier(e.module("alerting"), alerting.Config{
+			Webhooks:          webhooks,
+			DedupInterval:     c.Alert.DedupInterval,
+			RPCErrorThreshold: c.Alert.RPCErrors,
+			RPCErrorWindow:    c.Alert.RPCWindow,
+		})
+		if err != nil {
+			return err
+		}
+		e.onClose(notifier.Close)
+		e.notifier = notifier
+		l.SetAlerter(notifier)
+		if e.clock != nil {
+			e.clock.SetAlerter(notifier)
+		}
+		observers = append(observers, notifier)
+		events, sub := l.SubscribeEvents(64)
+		e.onClose(sub.Unsubscribe)
+		go notifier.Watch(ctx, events)
+	}
+	e.gas = gasoracle.New(gasConfig(c.Gas), ethClient)
+	if c.Funding.Interval > 0 {
+		e.funding = funding.NewWatcher(e.module("funding"), funding.Config{
+			Interval:      c.Funding.Interval,
+			SlotTime:      c.Network().SlotTime,
+			SettlementGas: c.Funding.SettlementGas,
+			MinBalanceWei: gwei(c.Funding.MinBalanceGwei),
+			MinRunway:     c.Funding.MinRunway,
+		}, ethClient, e.gas, crypto.PubkeyToAddress(signingKey.PublicKey))
+		e.funding.SetMetrics(e.metrics)
+		if e.notifier != nil {
+			e.funding.SetAlerter(e.notifier)
+		}
+	}
+	if len(auditors) > 0 {
+		l.SetAuditor(auditors)
+	}
+	e.coordinator.SetObserver(observers)
+
+	result, err := recovery.Recover(e.module("recovery"), recovery.Config{FromBlock: c.Recovery.FromBlock}, history, l, e.coordinator)
+	if err != nil {
+		return err
+	}
+	e.logger.Info("recovered state from history", "lastAuctionBlock", result.LastAuctionBlock,
+		"unsettledAuctions", result.UnsettledAuctions, "commitments", result.Commitments)
+	// Commitments are fulfilled, missed, renewed and escalated as each new head's transactions are observed
+	follower := commitment.NewFollower(e.module("commitment"), e.coordinator, ethClient)
+	events, sub := l.SubscribeEvents(64)
+	e.onClose(sub.Unsubscribe)
+	go follower.Watch(ctx, events)
+	if c.Intake.Enabled {
+		e.intake, err = intake.NewPool(e.module("intake"), intake.Config{
+			MaxPendingPerAddress: c.Intake.MaxPending,
+			MaxRequestsPerWindow: c.Intake.MaxRequests,
+			QuotaWindow:          c.Intake.Window,
+		}, nil)
+		if err != nil {
+			return err
+		}
+		// Requests are committed to as their target block's auction opens, binding its winner to include them
+		requestIssuer := issuer.NewIssuer(e.module("issuer"), issuer.Config{
+			BlobsPerBlock:  c.Intake.BlobsPerBlock,
+			ValidityBlocks: c.Intake.ValidityBlocks,
+		}, e.intake, quoter, e.coordinator)
+		if c.Intake.Simulate {
+			requestIssuer.SetSimulator(simulation.NewSimulator(e.module("simulation"), ethClient))
+		}
+		events, sub := l.SubscribeEvents(64)
+		e.onClose(sub.Unsubscribe)
+		go requestIssuer.Watch(ctx, events)
+		if e.awards != nil {
+			e.awards.SetKeyReleaser(e.intake)
+		}
+	}
+
+	// Bids are acknowledged with receipts signed by the auctioneer's key, see SubmitBidWithReceipt
+	l.SetReceiptKey(signingKey)
+	e.relays = l
+	if c.Chaos.Enabled() {
+		e.faults, err = chaos.NewInjector(e.module("chaos"), chaos.Config{
+			DropBidsPercent:        c.Chaos.DropBidsPercent,
+			WinnerDelay:            c.Chaos.WinnerDelay,
+			FailSettlementsPercent: c.Chaos.FailSettlementsPercent,
+		})
+		if err != nil {
+			return err
+		}
+		e.logger.Warn("fault injection enabled", "dropBidsPercent", c.Chaos.DropBidsPercent,
+			"winnerDelay", c.Chaos.WinnerDelay, "failSettlementsPercent", c.Chaos.FailSettlementsPercent)
+		e.relays = &chaos.Listener{Listener: l, Faults: e.faults}
+	}
+	if c.Federation.Enabled() {
+		if err := e.federate(ctx, l); err != nil {
+			return err
+		}
+	}
+	if c.Bulletin.Enabled() {
+		var sinks []bulletin.Sink
+		if c.Bulletin.URL != "" {
+			sinks = append(sinks, bulletin.NewHTTPSink(c.Bulletin.URL))
+		}
+		if c.Bulletin.Path != "" {
+			file, err := bulletin.NewFileSink(c.Bulletin.Path)
+			if err != nil {
+				return err
+			}
+			sinks = append(sinks, file)
+		}
+		publisher := bulletin.NewPublisher(e.module("bulletin"), signingKey, l, e.coordinator, sinks...)
+		e.onClose(func() { publisher.Close() })
+		if e.elector != nil {
+			publisher.SetLeader(e.elector)
+		}
+		events, sub := l.SubscribeEvents(64)
+		e.onClose(sub.Unsubscribe)
+		go publisher.Watch(ctx, events)
+	}
+	if c.Heartbeat.Enabled {
+		network := c.Network()
+		e.heartbeat = heartbeat.NewBeacon(e.module("heartbeat"), heartbeat.Config{GenesisTime: network.GenesisTime, SlotTime: network.SlotTime},
+			signingKey, l, e.coordinator)
+		events, sub := l.SubscribeEvents(64)
+		e.onClose(sub.Unsubscribe)
+		go e.heartbeat.Watch(ctx, events)
+	}
+	if c.Watchers.Enabled() {
+		watchers := make([]common.Address, len(c.Watchers.Addresses))
+		for i, watcher := range c.Watchers.Addresses {
+			watchers[i] = common.HexToAddress(watcher)
+		}
+		e.attestations, err = attestation.NewQuorum(e.module("attestation"),
+			attestation.Config{Watchers: watchers, Threshold: c.Watchers.Quorum}, e.coordinator)
+		if err != nil {
+			return err
+		}
+		if emitter != nil {
+			e.attestations.SetObserver(emitter)
+		}
+	}
+	return nil
+}
+
+// Joins the other replicas: bids are gossiped between them so followers shadow the leader's auctions, and the
+// leader elected by lease alone awards and settles winners. Replicas exchange each auction's state hash to detect
+// divergence.
+func (e *engine) federate(ctx context.Context, l *listener.Listener) error {
+	f := e.c.Federation
+	var lease *election.SQLLease
+	var err error
+	if f.LeaseBackend == "sqlite" {
+		lease, err = election.NewSQLiteLease(ctx, f.LeasePath)
+	} else {
+		lease, err = election.NewPostgresLease(ctx, f.LeaseURL)
+	}
+	if err != nil {
+		return err
+	}
+	e.onClose(func() { lease.Close() })
+
+	gossipConfig := gossip.Config{ListenAddrs: f.GossipListen, Peers: f.GossipPeers}
+	if f.GossipKeyFile != "" {
+		key, err := crypto.LoadECDSA(f.GossipKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load gossip key: %w", err)
+		}
+		if gossipConfig.PrivateKey, err = libp2pcrypto.UnmarshalSecp256k1PrivateKey(crypto.FromECDSA(key)); err != nil {
+			return fmt.Errorf("invalid gossip key: %w", err)
+		}
+	}
+	g, err := gossip.NewGossip(ctx, e.module("gossip"), gossipConfig, l)
+	if err != nil {
+		return err
+	}
+	e.onClose(func() { g.Close() })
+	e.relays = &gossip.ReplicatedListener{Listener: l, Gossip: g}
+
+	e.crossCheck = crosscheck.NewChecker(e.module("crosscheck"), f.ReplicaID, g, l, e.coordinator)
+	if e.notifier != nil {
+		e.crossCheck.SetAlerter(e.notifier)
+	}
+	if err := g.JoinStates(ctx, e.crossCheck); err != nil {
+		return err
+	}
+	events, sub := l.SubscribeEvents(64)
+	e.onClose(sub.Unsubscribe)
+	go e.crossCheck.Watch(ctx, events)
+
+	e.elector = election.NewElector(e.module("election"), election.Config{ID: f.ReplicaID, Name: e.c.EngineName(), TTL: f.LeaseTTL}, lease)
+	e.elector.SetObserver(e)
+	e.onClose(e.elector.Stop)
+	return nil
+}
+
+// Starts history pruning, the clock guard, the funding watcher, heartbeats and auctions, once the servers are up
+func (e *engine) start(ctx context.Context) error {
+	if e.clock != nil {
+		e.clock.Start(ctx)
+	}
+	if e.funding != nil {
+		e.funding.Start(ctx)
+	}
+	if e.heartbeat != nil {
+		e.heartbeat.Start(ctx)
+	}
+	if e.c.Retention.Bids > 0 {
+		retention.NewPruner(e.module("retention"), retention.Config{BidRetention: e.c.Retention.Bids, Interval: e.c.Retention.Interval},
+			e.history, e.ethClient, nil).Start(ctx)
+	}
+	done, won, err := e.listener.Start(ctx)
+	if err != nil {
+		return err
+	}
+	e.done, e.auctionWon = done, won
+	// There's no settlement worker yet, winners stay unsettled in history for recovery to resume
+	e.submit = func(bid auction.SignedBid) {
+		e.logger.Info("auction won, awaiting settlement", "blockNumber", bid.L1Block, "winner", bid.Address, "amount", bid.AmountWei)
+	}
+	if e.faults != nil {
+		// Delayed winners still pending on shutdown are left unsettled in history, for recovery to resume
+		e.auctionWon = e.faults.DelayWinners(context.WithoutCancel(ctx), e.auctionWon)
+		submit := e.submit
+		e.submit = func(bid auction.SignedBid) {
+			if err := e.faults.FailSettlement(bid); err != nil {
+				e.listener.PublishEvent(auction.Event{Type: auction.EventSettlementFailed, L1Block: bid.L1Block, Error: err.Error(), Timestamp: time.Now()})
+				e.fallBack(bid, auction.StagePayment, err.Error())
+				return
+			}
+			submit(bid)
+		}
+	}
+	e.settle = e.submit
+	if e.awards != nil {
+		// Winners with an award endpoint are settled once they accept, see AwardAccepted
+		e.settle = func(bid auction.SignedBid) {
+			if !e.awards.Notify(bid) {
+				e.submit(bid)
+			}
+		}
+	}
+	if e.elector != nil {
+		settle := e.settle
+		e.settle = func(bid auction.SignedBid) {
+			if e.elector.IsLeader() {
+				settle(bid)
+				return
+			}
+			e.logger.Info("following the leader, leaving the winner to it", "blockNumber", bid.L1Block, "winner", bid.Address)
+			e.shadowMu.Lock()
+			e.shadowed = &bid
+			e.shadowMu.Unlock()
+		}
+		e.elector.Start(ctx)
+	}
+	return nil
+}
+
+// To satisfy election.Observer. A follower taking over settles the last auction's winner, in case the leader
+// failed after it closed. Winners may thus be awarded and settled twice.
+func (e *engine) LeadershipChanged(leader bool) {
+	e.shadowMu.Lock()
+	shadowed := e.shadowed
+	e.shadowed = nil
+	e.shadowMu.Unlock()
+	if !leader || shadowed == nil {
+		return
+	}
+	if state, ok := e.listener.GetAuction(shadowed.L1Block.Uint64()); !ok || state.InProgress || state.LeadingBid == nil || state.LeadingBid.Address != shadowed.Address {
+		return
+	}
+	e.logger.Warn("took over mid-slot, settling the last auction's winner", "blockNumber", shadowed.L1Block, "winner", shadowed.Address)
+	e.settle(*shadowed)
+}
+
+// Hands won auctions to settlement until stop is closed or ctx is done, then shuts down. Fails if the listener
+// stops by itself.
+func (e *engine) run(ctx context.Context, stop <-chan struct{}) error {
+	for {
+		select {
+		case <-stop:
+			return shutdown(e.logger, e.listener, e.auctionWon, e.settle, e.c.Daemon.ShutdownTimeout)
+		case <-ctx.Done():
+			return shutdown(e.logger, e.listener, e.auctionWon, e.settle, e.c.Daemon.ShutdownTimeout)
+		case <-e.done:
+			if e.name != "" {
+				return fmt.Errorf("%s listener stopped", e.name)
+			}
+			return fmt.Errorf("listener stopped")
+		case bid := <-e.auctionWon:
+			e.settle(bid)
+		}
+	}
+}
+
+// To satisfy award.Observer
+func (e *engine) AwardAccepted(a *award.Award) {
+	e.reputation.RecordAccepted(a.Bid.Address)
+	e.submit(a.Bid)
+}
+
+// To satisfy award.Observer
+func (e *engine) AwardDefaulted(a *award.Award, reason string) {
+	e.fallBack(a.Bid, auction.StageAcceptance, reason)
+}
+
+// Records the winner's failure in its reputation, and hands the auction to the next highest bid, which is awarded
+// and settled in turn, cascading until a winner succeeds or the slot is over
+func (e *engine) fallBack(failed auction.SignedBid, stage auction.FallbackStage, reason string) {
+	l1Block := failed.L1Block.Uint64()
+	e.reputation.RecordDefault(failed.Address, l1Block, string(stage)+": "+reason)
+	next, err := e.listener.FallBack(l1Block, failed.Address, stage, reason)
+	if err != nil {
+		e.logger.Error("winner failed with no bid to fall back to in the slot", "blockNumber", l1Block, "winner", failed.Address,
+			"stage", stage, "reason", reason, "error", err)
+		return
+	}
+	e.settle(next)
+}
+
+func (e *engine) close() {
+	for i := len(e.closers) - 1; i >= 0; i-- {
+		e.closers[i]()
+	}
+}
+
+func reserveControl(a config.AuctionConfig, r config.ReserveConfig) policy.ControllerConfig {
+	control := policy.ControllerConfig{
+		InitialWei:          new(big.Int).SetUint64(a.ReserveWei),
+		MinWei:              new(big.Int).SetUint64(r.MinWei),
+		Window:              r.Window,
+		TargetPercent:       r.TargetPercent,
+		BlobFeeMultiple:     r.BlobFeeMultiple,
+		ProportionalPercent: r.GainPPercent,
+		IntegralPercent:     r.GainIPercent,
+		DerivativePercent:   r.GainDPercent,
+	}
+	if r.MaxWei > 0 {
+		control.MaxWei = new(big.Int).SetUint64(r.MaxWei)
+	}
+	if r.MaxStepWei > 0 {
+		control.MaxStepWei = new(big.Int).SetUint64(r.MaxStepWei)
+	}
+	return control
+}
+
+// Outcome of auctions whose slot was missed, from the validated auction.missed-slot-outcome
+func slotOutcome(outcome string) auction.SlotOutcome {
+	if outcome == "carry-over" {
+		return auction.SlotCarryOver
+	}
+	return auction.SlotRefund
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineConfigs(t *testing.T) {
+	c := config.Default()
+	c.L1.RPCURL, c.Signer.KeyFile, c.Admin.Token = "http://localhost:8545", "signer.key", "token"
+	names, configs, err := engineConfigs(c)
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, names, "a single chain's engine is unnamed")
+	require.Equal(t, c, configs[""])
+
+	c.NetworkName = "sepolia"
+	c.REST.Addr = ":9000"
+	c.Engines = map[string]string{
+		"holesky": writeConfig(t, "holesky.yaml", "network: holesky\nl1:\n  rpc-url: http://holesky:8545\n"),
+		"local":   writeConfig(t, "local.yaml", "network: local\nl1:\n  rpc-url: http://local:8545\n"),
+	}
+	names, configs, err = engineConfigs(c)
+	require.NoError(t, err)
+	require.Equal(t, []string{"sepolia", "holesky", "local"}, names, "the node's own chain first")
+	require.Equal(t, "http://holesky:8545", configs["holesky"].L1.RPCURL)
+	require.Equal(t, ":9000", configs["holesky"].REST.Addr)
+
+	c.Engines["local"] = writeConfig(t, "local.yaml", "network: local\n")
+	_, _, err = engineConfigs(c)
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+	require.ErrorContains(t, err, "engine local")
+}
+
+func TestCheckName(t *testing.T) {
+	require.Equal(t, "rpc", checkName("rpc", ""))
+	require.Equal(t, "rpc-holesky", checkName("rpc", "holesky"))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/keys"
+	"blob-preconfs/pkg/sealed"
+	"blob-preconfs/pkg/version"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "auctioneer",
+		Short: "Blob preconf relay auctioneer",
+		Long: `Runs relay auctions for blob preconfirmations every L1 block, and operates a running node via its admin API.
+
+The node is configured with the YAML or TOML file passed with --config, e.g. l1.rpc-url for the L1 node.
+Every key can be overridden by an AUCTIONEER_ environment variable, e.g. AUCTIONEER_L1_RPC_URL,
+and by a flag, e.g. --l1-rpc-url. Admin commands read the admin API's address and token from the same file.`,
+		SilenceUsage: true,
+		// Errors are printed by main, once
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return applyConfig(cmd)
+		},
+	}
+	root.PersistentFlags().String(configFlag, "", "Node config file, .yaml, .yml or .toml")
+	root.AddCommand(newRunCommand(), newStatusCommand(), newExportCommand(), newSnapshotCommand(), newConfigCommand(), newReplayCommand(), keys.NewCommand(), sealed.NewCommand(), attestation.NewCommand(), version.NewCommand())
+	return root
+}
@@ -0,0 +1,537 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"blob-preconfs/pkg/admin"
+	"blob-preconfs/pkg/alerting"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/avs"
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/export"
+	"blob-preconfs/pkg/gasoracle"
+	"blob-preconfs/pkg/graphql"
+	"blob-preconfs/pkg/health"
+	"blob-preconfs/pkg/jsonrpc"
+	"blob-preconfs/pkg/keys"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/logging"
+	"blob-preconfs/pkg/metrics"
+	"blob-preconfs/pkg/mevboost"
+	"blob-preconfs/pkg/ratelimit"
+	"blob-preconfs/pkg/relaygrpc"
+	"blob-preconfs/pkg/rest"
+	"blob-preconfs/pkg/sealed"
+	"blob-preconfs/pkg/store"
+	"blob-preconfs/pkg/version"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Relay requests signed longer ago or further ahead are rejected
+const authMaxSkew = 30 * time.Second
+
+// Timeout of requests to mev-boost relays
+const mevBoostTimeout = 2 * time.Second
+
+// Servers started so far, stopped in reverse order on shutdown
+type servers []func(ctx context.Context) error
+
+func (s *servers) start(start func() error, stop func(ctx context.Context) error) error {
+	if err := start(); err != nil {
+		return err
+	}
+	*s = append(*s, stop)
+	return nil
+}
+
+func (s servers) stop(logger *slog.Logger, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for i := len(s) - 1; i >= 0; i-- {
+		if err := s[i](ctx); err != nil {
+			logger.Error("failed to stop server", "error", err)
+		}
+	}
+}
+
+// Runs until SIGINT or SIGTERM, reloading config from load on SIGHUP
+func runNode(ctx context.Context, c config.Config, load func() (config.Config, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	logs, err := logging.New(logging.Config{
+		Level:      c.Log.Level,
+		Format:     c.Log.Format,
+		Modules:    c.Log.Modules,
+		File:       c.Log.File,
+		MaxSizeMB:  c.Log.MaxSizeMB,
+		MaxBackups: c.Log.MaxBackups,
+		Sampling:   logging.SamplingConfig{Interval: c.Log.SampleInterval, First: 1, Thereafter: 100},
+	})
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+	logger := logs.Logger()
+	if c.Daemon.PIDFile != "" {
+		remove, err := writePIDFile(c.Daemon.PIDFile)
+		if err != nil {
+			return err
+		}
+		defer remove()
+	}
+
+	signingKey, err := c.Signer.Source().Load()
+	if err != nil {
+		return fmt.Errorf("failed to load signing key: %w", err)
+	}
+	signers, err := c.Signer.Source().Signers(crypto.PubkeyToAddress(signingKey.PublicKey), c.Signer.GracePeriod)
+	if err != nil {
+		return fmt.Errorf("failed to read key rotation: %w", err)
+	}
+	if signers.Previous != nil {
+		logger.Info("signing key rotated, previous key remains valid", "active", signers.Active,
+			"previous", *signers.Previous, "previousUntil", *signers.PreviousUntil)
+	}
+	tlsConfig, err := tlsSettings(c.TLS).Build()
+	if err != nil {
+		return err
+	}
+	names, configs, err := engineConfigs(c)
+	if err != nil {
+		return err
+	}
+	// Engines share a metrics registry, labelled with their chain
+	registry := metrics.NewRegistry()
+	engines := make([]*engine, len(names))
+	for i, name := range names {
+		e := &engine{name: name, c: configs[name], logs: logs, metrics: metrics.NewForChain(registry, name)}
+		defer e.close()
+		if err := e.open(ctx, signingKey); err != nil {
+			if name != "" {
+				return fmt.Errorf("engine %s: %w", name, err)
+			}
+			return err
+		}
+		engines[i] = e
+	}
+	primary := engines[0]
+	reloader := &reloader{
+		logger:     logs.Module("config"),
+		load:       load,
+		logs:       logs,
+		accessList: primary.listener.AccessList(),
+		registry:   primary.static,
+		current:    c,
+	}
+
+	var running servers
+	defer running.stop(logger, c.Daemon.ShutdownTimeout)
+	if err := startServers(&running, logs, c, tlsConfig, engines, reloader, signers); err != nil {
+		return err
+	}
+	for _, e := range engines {
+		if err := e.start(ctx); err != nil {
+			return err
+		}
+	}
+	logger.Info("auctioneer started", "version", version.Get().String(), "chains", len(engines))
+
+	// Engines stop together, when the process is signalled or one of them fails
+	stop := make(chan struct{})
+	stopped := make(chan error, len(engines))
+	for _, e := range engines {
+		go func(e *engine) { stopped <- e.run(ctx, stop) }(e)
+	}
+	var failed error
+	remaining := len(engines)
+wait:
+	for {
+		select {
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				if err := reloader.Reload(ctx); err != nil {
+					logger.Error("failed to reload config, keeping the current one", "error", err)
+				}
+				continue
+			}
+			logger.Info("shutting down", "signal", sig.String())
+		case <-ctx.Done():
+			logger.Info("shutting down")
+		case failed = <-stopped:
+			remaining--
+		}
+		break wait
+	}
+	close(stop)
+	for ; remaining > 0; remaining-- {
+		if err := <-stopped; failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// Waits for the auction in progress to close, handing won auctions to settle meanwhile, before servers stop
+func shutdown(logger *slog.Logger, l *listener.Listener, auctionWon <-chan auction.SignedBid, settle func(auction.SignedBid), timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- l.Stop(ctx) }()
+	for {
+		select {
+		case err := <-stopped:
+			if err != nil {
+				logger.Warn("auction in progress didn't close before the shutdown timeout", "error", err)
+			}
+			return nil
+		case bid := <-auctionWon:
+			settle(bid)
+		}
+	}
+}
+
+// Served to relays in place of the listener, e.g. with faults injected
+type relayBackend interface {
+	rest.AuctionBackend
+	jsonrpc.AuctionBackend
+	relaygrpc.Backend
+	jsonrpc.ReceiptBackend
+}
+
+// The primary engine's APIs are served at the root, other engines' REST and JSON-RPC APIs under /chains/<name>.
+// gRPC, GraphQL and admin serve the primary engine only.
+func startServers(
+	running *servers,
+	logs *logging.Logging,
+	c config.Config,
+	tlsConfig *tls.Config,
+	engines []*engine,
+	reloader admin.ConfigReloader,
+	signers keys.Signers,
+) error {
+	primary := engines[0]
+	verifiers := make([]*auth.Verifier, len(engines))
+	if c.Auction.RequireAuth {
+		for i, e := range engines {
+			verifiers[i] = auth.NewVerifier(e.registry, authMaxSkew)
+		}
+	}
+	if c.REST.Addr != "" {
+		server := rest.NewServer(logs.Module("rest"), c.REST.Addr, primary.relays, primary.coordinator, primary.history, nil, verifiers[0], tlsConfig)
+		server.SetReceipts(primary.relays)
+		if primary.escrow != nil {
+			server.SetEscrow(primary.escrow)
+		}
+		if primary.heartbeat != nil {
+			server.SetHeartbeats(primary.heartbeat)
+		}
+		if primary.attestations != nil {
+			server.SetAttestations(primary.attestations)
+		}
+		if primary.intake != nil {
+			server.SetIntake(primary.intake)
+		}
+		for i, e := range engines[1:] {
+			mounted := rest.NewServer(e.module("rest"), "", e.relays, e.coordinator, e.history, nil, verifiers[i+1], tlsConfig)
+			mounted.SetReceipts(e.relays)
+			if e.escrow != nil {
+				mounted.SetEscrow(e.escrow)
+			}
+			if e.heartbeat != nil {
+				mounted.SetHeartbeats(e.heartbeat)
+			}
+			if e.attestations != nil {
+				mounted.SetAttestations(e.attestations)
+			}
+			if e.intake != nil {
+				mounted.SetIntake(e.intake)
+			}
+			server.Mount(chainPrefix(e.name), mounted)
+			*running = append(*running, mounted.Stop)
+		}
+		if err := running.start(server.Start, server.Stop); err != nil {
+			return fmt.Errorf("failed to start rest server: %w", err)
+		}
+	}
+	if c.JSONRPC.Addr != "" {
+		server, err := jsonrpc.NewServer(logs.Module("jsonrpc"), c.JSONRPC.Addr, primary.relays, []string{"*"}, nil, verifiers[0], tlsConfig)
+		if err != nil {
+			return err
+		}
+		server.SetMetrics(primary.metrics)
+		server.SetRejections(primary.listener)
+		server.SetReceipts(primary.relays)
+		server.SetLimits(transportLimits(c.Transport))
+		if primary.escrow != nil {
+			server.SetEscrow(primary.escrow)
+		}
+		if primary.c.Sealed.Enabled() {
+			server.SetSealedBids(primary.listener)
+		}
+		for i, e := range engines[1:] {
+			mounted, err := jsonrpc.NewServer(e.module("jsonrpc"), "", e.relays, []string{"*"}, nil, verifiers[i+1], tlsConfig)
+			if err != nil {
+				return err
+			}
+			mounted.SetMetrics(e.metrics)
+			mounted.SetRejections(e.listener)
+			mounted.SetReceipts(e.relays)
+			mounted.SetLimits(transportLimits(c.Transport))
+			if e.escrow != nil {
+				mounted.SetEscrow(e.escrow)
+			}
+			if e.c.Sealed.Enabled() {
+				mounted.SetSealedBids(e.listener)
+			}
+			server.Mount(chainPrefix(e.name), mounted)
+			*running = append(*running, mounted.Stop)
+		}
+		if err := running.start(server.Start, server.Stop); err != nil {
+			return fmt.Errorf("failed to start jsonrpc server: %w", err)
+		}
+	}
+	if c.GRPC.Addr != "" {
+		server := relaygrpc.NewServer(logs.Module("relaygrpc"), c.GRPC.Addr, primary.relays, nil, verifiers[0], tlsConfig,
+			relaygrpc.WithLimits(transportLimits(c.Transport))...)
+		server.SetMetrics(primary.metrics)
+		server.SetRejections(primary.listener)
+		server.SetReceipts(primary.relays)
+		stop := func(ctx context.Context) error {
+			server.Stop(ctx)
+			return nil
+		}
+		if err := running.start(server.Start, stop); err != nil {
+			return fmt.Errorf("failed to start grpc server: %w", err)
+		}
+	}
+	if c.GraphQL.Addr != "" {
+		server := graphql.NewServer(logs.Module("graphql"), c.GraphQL.Addr, primary.history, tlsConfig)
+		if err := running.start(server.Start, server.Stop); err != nil {
+			return fmt.Errorf("failed to start graphql server: %w", err)
+		}
+	}
+	if c.Metrics.Addr != "" {
+		checker := health.NewChecker(logs.Module("health"))
+		for _, e := range engines {
+			checker.AddLiveness(checkName("auctions", e.name), health.AuctionRecency(e.listener, e.c.Health.MaxAuctionAge))
+			checker.AddReadiness(checkName("rpc", e.name), health.RPC(e.ethClient))
+			if e.c.Health.MaxUnsettled > 0 {
+				checker.AddReadiness(checkName("settlements", e.name), health.SettlementQueue(e.history, e.c.Health.MaxUnsettled))
+			}
+		}
+		// Engines' metrics share a registry, served by any of them
+		server := metrics.NewServer(logs.Module("metrics"), c.Metrics.Addr, primary.metrics, tlsConfig)
+		checker.Register(server.Mux())
+		if err := running.start(server.Start, server.Stop); err != nil {
+			return fmt.Errorf("failed to start metrics server: %w", err)
+		}
+	}
+	if c.Admin.Addr != "" {
+		l := primary.listener
+		server, err := admin.NewServer(logs.Module("admin"), c.Admin.Addr, l, reloader, primary.resyncer, export.NewExporter(primary.history), primary.history, c.Admin.Token, tlsConfig)
+		if err != nil {
+			return err
+		}
+		server.AddDiagnostics("listener", func() any { return l.Diagnostics() })
+		if awards := primary.awards; awards != nil {
+			server.AddDiagnostics("awards", func() any { return awards.Deliveries() })
+		}
+		server.AddDiagnostics("reputation", func() any { return primary.reputation.All() })
+		if elector := primary.elector; elector != nil {
+			server.AddDiagnostics("federation", func() any { return elector.Status() })
+			server.AddDiagnostics("crossCheck", func() any { return primary.crossCheck.Status() })
+		}
+		server.SetAuctionMonitor(l)
+		server.SetSettlementHistory(primary.history)
+		server.SetRelayRecords(primary.reputation)
+		if primary.awards != nil {
+			server.SetAwardDeliveries(primary.awards)
+		}
+		server.SetSigners(signers)
+		if primary.funding != nil {
+			server.SetFunding(primary.funding)
+		}
+		if err := running.start(server.Start, server.Stop); err != nil {
+			return fmt.Errorf("failed to start admin server: %w", err)
+		}
+	}
+	return nil
+}
+
+// Path other engines' APIs are served under
+func chainPrefix(name string) string {
+	return "/chains/" + name
+}
+
+// Health check name, e.g. rpc-holesky for an engine's rpc check
+func checkName(check, engine string) string {
+	if engine == "" {
+		return check
+	}
+	return check + "-" + engine
+}
+
+// Registry for the configured source, and the admin API's resync hook if the source has one. mev-boost relays
+// are checked once before the first auction, then every refresh interval.
+func openRegistry(ctx context.Context, module func(string) *slog.Logger, c config.Config, static *staticRegistry, caller avs.Caller) (auction.RelayRegistry, admin.RegistryResyncer, error) {
+	switch c.Registry.Source {
+	case config.RegistryMevBoost:
+		return openMevBoostRegistry(ctx, module, c)
+	case config.RegistryAVS:
+		return openAVSRegistry(ctx, module, c, caller)
+	default:
+		return static, nil, nil
+	}
+}
+
+func openMevBoostRegistry(ctx context.Context, module func(string) *slog.Logger, c config.Config) (auction.RelayRegistry, admin.RegistryResyncer, error) {
+	relays := make([]mevboost.Relay, 0, len(c.Registry.MevBoostRelays))
+	for bidder, relayURL := range c.Registry.MevBoostRelays {
+		relays = append(relays, mevboost.Relay{URL: relayURL, Bidder: common.HexToAddress(bidder)})
+	}
+	adapter, err := mevboost.NewAdapter(module("mevboost"), relays, mevBoostTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Relays that are down are logged, and registered once they're back
+	adapter.Resync(ctx)
+	go adapter.Run(ctx, c.Registry.RefreshInterval)
+	return adapter, adapter, nil
+}
+
+func openAVSRegistry(ctx context.Context, module func(string) *slog.Logger, c config.Config, caller avs.Caller) (auction.RelayRegistry, admin.RegistryResyncer, error) {
+	operators := make([]common.Address, len(c.Registry.Relays))
+	for i, operator := range c.Registry.Relays {
+		operators[i] = common.HexToAddress(operator)
+	}
+	registry, err := avs.NewRegistry(module("avs"), caller, avs.Config{
+		RegistryCoordinator: common.HexToAddress(c.Registry.AVS.RegistryCoordinator),
+		StakeRegistry:       common.HexToAddress(c.Registry.AVS.StakeRegistry),
+		Quorum:              uint8(c.Registry.AVS.Quorum),
+		MinStake:            new(big.Int).Mul(new(big.Int).SetUint64(c.Registry.AVS.MinStakeGwei), big.NewInt(params.GWei)),
+		Operators:           operators,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Operators that can't be read are logged, and registered once their stake is
+	registry.Resync(ctx)
+	go registry.Run(ctx, c.Registry.RefreshInterval)
+	return registry, registry, nil
+}
+
+func openStore(ctx context.Context, c config.Config) (store.Store, error) {
+	var history store.Store
+	var err error
+	switch c.Store.Backend {
+	case "memory":
+		history = store.NewMemoryStore()
+	case "leveldb":
+		history, err = store.NewLevelDBStore(c.Store.Path)
+	case "sqlite":
+		history, err = store.NewSQLiteStore(c.Store.Path)
+	case "postgres":
+		history, err = store.NewPostgresStore(ctx, c.Store.URL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s store: %w", c.Store.Backend, err)
+	}
+	return history, nil
+}
+
+// Opens sealed bids with sealed.key-file, or with the committee's decryption shares
+func openSealed(c config.SealedConfig) (listener.SealedBidOpener, error) {
+	if c.KeyFile != "" {
+		key, err := crypto.LoadECDSA(c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sealed.key-file: %w", err)
+		}
+		return sealed.NewKeyOpener(key), nil
+	}
+	sealingKey, err := crypto.DecompressPubkey(common.FromHex(c.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sealed.public-key: %w", err)
+	}
+	return sealed.NewCommittee(sealingKey, c.Threshold, c.Committee)
+}
+
+var errWrongChain = errors.New("l1 node is on the wrong chain")
+
+// Fails if the L1 node isn't on the configured chain, e.g. a mainnet config pointed at a testnet node
+func checkChainID(ctx context.Context, client interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}, expected uint64) error {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get l1 chain id: %w", err)
+	}
+	if !chainID.IsUint64() || chainID.Uint64() != expected {
+		return fmt.Errorf("%w: chain id %s, expected %d (see network and chain.chain-id)", errWrongChain, chainID, expected)
+	}
+	return nil
+}
+
+func webhooks(c config.AlertConfig) []alerting.WebhookConfig {
+	var webhooks []alerting.WebhookConfig
+	for _, url := range c.Webhooks {
+		webhooks = append(webhooks, alerting.WebhookConfig{Format: "json", URL: url})
+	}
+	for _, url := range c.SlackURLs {
+		webhooks = append(webhooks, alerting.WebhookConfig{Format: "slack", URL: url})
+	}
+	if c.PagerDutyKey != "" {
+		webhooks = append(webhooks, alerting.WebhookConfig{Format: "pagerduty", RoutingKey: c.PagerDutyKey})
+	}
+	return webhooks
+}
+
+func gasConfig(c config.GasConfig) gasoracle.Config {
+	return gasoracle.Config{
+		Blocks:            c.Blocks,
+		Percentile:        float64(c.Percentile),
+		BaseFeeMultiplier: c.BaseFeeMultiplier,
+		MinPriorityFeeWei: gwei(c.MinPriorityFeeGwei),
+		MaxPriorityFeeWei: gwei(c.MaxPriorityFeeGwei),
+		MaxFeeWei:         gwei(c.MaxFeeGwei),
+	}
+}
+
+func transportLimits(c config.TransportConfig) ratelimit.TransportConfig {
+	return ratelimit.TransportConfig{
+		MaxMessageSize: c.MaxMessageSize,
+		PerConn:        ratelimit.Config{Rate: float64(c.MessageRate), Burst: c.MessageBurst},
+	}
+}
+
+// Nil if 0, for unset limits
+func gwei(amount uint64) *big.Int {
+	if amount == 0 {
+		return nil
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(params.GWei))
+}
+
+func addresses(hexes []string) []common.Address {
+	addresses := make([]common.Address, len(hexes))
+	for i, hex := range hexes {
+		addresses[i] = common.HexToAddress(hex)
+	}
+	return addresses
+}
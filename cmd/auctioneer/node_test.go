@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type chainIDClient int64
+
+func (c chainIDClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(int64(c)), nil
+}
+
+func TestCheckChainID(t *testing.T) {
+	require.NoError(t, checkChainID(context.Background(), chainIDClient(17000), 17000))
+	err := checkChainID(context.Background(), chainIDClient(11155111), 1)
+	require.ErrorIs(t, err, errWrongChain)
+	require.ErrorContains(t, err, "chain id 11155111, expected 1")
+}
+
+func TestOpenSealed(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keyFile := filepath.Join(t.TempDir(), "sealing.key")
+	require.NoError(t, crypto.SaveECDSA(keyFile, key))
+	opener, err := openSealed(config.SealedConfig{KeyFile: keyFile})
+	require.NoError(t, err)
+	require.Equal(t, key.PublicKey, *opener.SealingKey())
+
+	committee := config.SealedConfig{PublicKey: hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)), Threshold: 2,
+		Committee: []string{"https://a.example.com", "https://b.example.com"}}
+	opener, err = openSealed(committee)
+	require.NoError(t, err)
+	require.Equal(t, key.PublicKey, *opener.SealingKey())
+}
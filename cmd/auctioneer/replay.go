@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/replay"
+
+	"github.com/spf13/cobra"
+)
+
+func newReplayCommand() *cobra.Command {
+	var speed float64
+	var period time.Duration
+	cmd := &cobra.Command{
+		Use:   "replay <recording>",
+		Short: "Replay recorded bid traffic (audit.recording) through the auction, reporting blocks whose winner changed",
+		Long: `Replays bid traffic recorded with audit.recording through the auction, at the original speed or faster
+with --speed. Bidders are checked against the allow and deny lists in --config, and every relay is treated
+as registered. Blocks whose winner or price differs from the recording are reported.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString(configFlag)
+			c, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("period") {
+				period = c.Auction.Period
+			}
+			accessList := auction.DefaultAccessList()
+			if len(c.Auction.Allowlist) > 0 || len(c.Auction.Denylist) > 0 {
+				accessList.Replace(accessLists(c.Auction))
+			}
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			entries, err := replay.Read(file)
+			if err != nil {
+				return err
+			}
+			logger := slog.New(slog.NewTextHandler(cmd.ErrOrStderr(), &slog.HandlerOptions{Level: slog.LevelWarn}))
+			replayer, err := replay.NewReplayer(logger, replay.Config{Speed: speed, Period: period, AccessList: accessList})
+			if err != nil {
+				return err
+			}
+			outcomes, err := replayer.Replay(cmd.Context(), entries)
+			if err != nil {
+				return err
+			}
+			writeOutcomes(cmd.OutOrStdout(), outcomes)
+			return nil
+		},
+	}
+	cmd.Flags().Float64Var(&speed, "speed", 1, "Playback speed relative to the recording, e.g. 10 for 10x")
+	cmd.Flags().DurationVar(&period, "period", 0, "Bidding period of each auction at original speed, auction.period from --config if unset")
+	return cmd
+}
+
+func writeOutcomes(w io.Writer, outcomes []replay.Outcome) {
+	changed := 0
+	for _, o := range outcomes {
+		if !o.Changed() {
+			continue
+		}
+		changed++
+		fmt.Fprintf(w, "block %d: %s, recorded %s\n", o.L1Block, describeWinner(o.Winner), describeWinner(o.RecordedWinner))
+	}
+	fmt.Fprintf(w, "%d auctions replayed, %d winners changed\n", len(outcomes), changed)
+}
+
+func describeWinner(bid *auction.SignedBid) string {
+	if bid == nil {
+		return "no winner"
+	}
+	return fmt.Sprintf("%s won with %s wei", bid.Address, bid.AmountWei)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/replay"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayCommand(t *testing.T) {
+	// On the built-in whitelist
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	opened := time.Now().UTC()
+	var recording bytes.Buffer
+	for _, entry := range []replay.Entry{
+		{Type: replay.EntryAuctionOpened, At: opened, L1Block: 100},
+		{Type: replay.EntryBid, At: opened.Add(time.Second), L1Block: 100, Bid: auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)},
+		{Type: replay.EntryAuctionOpened, At: opened.Add(12 * time.Second), L1Block: 101},
+	} {
+		data, err := json.Marshal(entry)
+		require.NoError(t, err)
+		recording.Write(append(data, '\n'))
+	}
+	path := filepath.Join(t.TempDir(), "bids.jsonl")
+	require.NoError(t, os.WriteFile(path, recording.Bytes(), 0o644))
+
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"replay", path, "--speed", "100"})
+	require.NoError(t, root.Execute())
+	require.Equal(t, "block 100: 0xDeFEA225C9e43F1A4Ccb561867Be9c9bf3142a98 won with 43 wei, recorded no winner\n"+
+		"2 auctions replayed, 1 winners changed\n", out.String())
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/eventstream"
+	"blob-preconfs/pkg/tlsconfig"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Every config key has a flag, named after the key with dots as dashes, e.g. --l1-rpc-url for l1.rpc-url
+var flagUsage = map[string]string{
+	"l1.rpc-url":                "L1 execution node RPC URL (required)",
+	"l1.poll-interval":          "Interval the L1 node is polled for new blocks in",
+	"network":                   "Network preset the chain flags default to: holesky, local, mainnet or sepolia, none if empty",
+	"chain.chain-id":            "L1 chain ID, verified against the L1 node on startup",
+	"chain.slot-time":           "L1 slot time, auctions must close within it",
+	"chain.genesis-time":        "Beacon chain genesis in Unix seconds, slots start every slot time from it",
+	"chain.max-blobs-per-block": "Max blobs per L1 block",
+	"chain.registry-contract":   "Relay registry contract on the settlement layer",
+	"signer.keystore-dir":       "Keystore commitments are signed with a key from, see the keys command",
+	"signer.address":            "Keystore key to sign with, the active key if empty",
+	"signer.password-file":      "File with the keystore password",
+	"signer.key-file":           "File with an unencrypted hex private key to sign with, instead of a keystore",
+	"signer.grace-period":       "How long the previous key remains valid after a keystore rotation",
+
+	"auction.period":                    "Bidding period of each L1 block's auction",
+	"auction.allowlist":                 "Relay addresses allowed to bid, replacing the built-in whitelist",
+	"auction.denylist":                  "Relay addresses denied from bidding",
+	"auction.require-auth":              "Require relay request signatures on bid submission endpoints",
+	"auction.verifiers":                 "Workers verifying bid signatures in each auction, one per CPU if 0",
+	"auction.shards":                    "Shards of bid intake by relay address, evaluated concurrently, unsharded if 0 or 1",
+	"auction.min-open":                  "Minimum time auctions are open before closing early",
+	"auction.quiet-period":              "Close auctions early once there's no new leader for this long, disabled if 0",
+	"auction.open-offset":               "Time into the slot auctions open at, with auction.close-offset",
+	"auction.close-offset":              "Time into the slot auctions close at, instead of after auction.period, disabled if 0",
+	"auction.escrow-check":              "Reject bids the bidder's escrow doesn't cover on submission, for the avs registry source",
+	"auction.escrow-cache-ttl":          "How long escrow balances are cached for auction.escrow-check",
+	"auction.bond-low-percent":          "Cap a relay's bids and prompt it to top up once pending wins leave less than this percent of its bond, disabled if 0",
+	"auction.missed-slot-period":        "Bidding period of the auction for the block after a missed slot, disabled if 0",
+	"auction.missed-slot-outcome":       "What becomes of a won auction whose slot is missed: refund or carry-over, not detected if empty",
+	"auction.missed-slot-grace":         "How long after its slot a block may still arrive before the slot counts as missed, 2s if 0",
+	"auction.reserve-wei":               "Lowest bid accepted, none if 0",
+	"auction.blob-fee-spike-wei":        "Blob base fee at which auctions use auction.spike-reserve-wei, disabled if 0",
+	"auction.spike-reserve-wei":         "Reserve price while the blob base fee is at least auction.blob-fee-spike-wei",
+	"reserve.dynamic":                   "Adjust the reserve price each slot from auction.reserve-wei, tracking clearing prices and the blob base fee",
+	"reserve.min-wei":                   "Lowest the dynamic reserve price goes",
+	"reserve.max-wei":                   "Highest the dynamic reserve price goes, unbounded if 0",
+	"reserve.window":                    "Recent auctions whose clearing prices the dynamic reserve price tracks",
+	"reserve.target-percent":            "Percent of the median recent clearing price the dynamic reserve price tracks",
+	"reserve.blob-fee-multiple":         "The dynamic reserve price tracks at least the blob base fee times this, disabled if 0",
+	"reserve.gain-p-percent":            "Proportional gain of the reserve price controller, in percent",
+	"reserve.gain-i-percent":            "Integral gain of the reserve price controller, in percent",
+	"reserve.gain-d-percent":            "Derivative gain of the reserve price controller, in percent",
+	"reserve.max-step-wei":              "Most the dynamic reserve price moves per slot, unbounded if 0",
+	"auction.pre-open-window":           "Queue bids for the next block this long before its auction opens, disabled if 0",
+	"registry.source":                   "Where registered relays are read from: static, mev-boost or avs",
+	"registry.relays":                   "Relay addresses registered on the settlement layer, for the static source, or relays' operators for avs",
+	"registry.mev-boost-relays":         "mev-boost relay URLs by the address they bid with, e.g. 0x...=https://0x...@relay.example.com",
+	"registry.refresh-interval":         "How often mev-boost relays' status, or AVS operators' stakes, are checked",
+	"registry.avs.registry-coordinator": "EigenLayer AVS RegistryCoordinator contract address",
+	"registry.avs.stake-registry":       "EigenLayer AVS StakeRegistry contract address",
+	"registry.avs.quorum":               "AVS quorum relays restake in",
+	"registry.avs.min-stake-gwei":       "Restaked collateral, in gwei, an operator needs for its relay to be registered",
+	"registry.bid-quota":                "Most distinct bids a relay may submit per auction, unlimited if 0, unless its tier sets another quota",
+	"registry.tiers":                    "Relays' tiers by address, e.g. 0x...=premium",
+	"registry.tier-quotas":              "Bids per auction each tier's relays may submit, by tier, e.g. premium=20, unlimited if 0",
+	"award.endpoints":                   "Callback URLs winning relays are notified at with their signed award, by the address they bid with",
+	"award.attempts":                    "Delivery attempts per award before the relay is left to poll for the result",
+	"award.timeout":                     "Timeout of each award delivery attempt",
+	"award.accept-deadline":             "Time winners have to counter-sign their award before it falls back to the runner-up",
+
+	"commitment.escalate-proposer-faults": "Carry commitments missed due to proposer faults forward to the next block's auction, requires auction.missed-slot-outcome",
+	"commitment.max-escalations":          "Times each commitment may be carried forward after proposer faults",
+	"commitment.auto-renew":               "Renew commitments missed due to proposer faults for the next block unless escalated, requires auction.missed-slot-outcome",
+	"commitment.max-renewals":             "Times each commitment may be renewed",
+
+	"pricing.fee-per-blob-wei":   "Preconf fee quoted per blob, renewals keep their original fee if zero",
+	"pricing.atomic-premium-bps": "Premium on atomic bundles, in basis points",
+	"intake.enabled":             "Accept preconf requests on the REST API, committed to as their target block's auction opens, requires pricing.fee-per-blob-wei",
+	"intake.max-pending":         "Pending requests per address, unlimited if zero",
+	"intake.max-requests":        "Requests per address per intake.window, unlimited if zero",
+	"intake.window":              "Window of intake.max-requests",
+	"intake.blobs-per-block":     "Blobs committed to per block, escalated commitments' included",
+	"intake.validity-blocks":     "Blocks past their target block commitments stay valid for",
+	"intake.simulate":            "Commit only to requests whose blob txs simulate against L1 state, requests without their txs aren't committed to",
+
+	"store.backend":   "History store: memory, leveldb, sqlite or postgres",
+	"store.path":      "Database directory for leveldb, or file for sqlite",
+	"store.url":       "Connection string for postgres",
+	"audit.log":       "Hash-chained audit log of bid traffic, disabled if empty",
+	"audit.recording": "Recording of bid traffic for the replay command, disabled if empty",
+
+	"rest.addr":               "REST API address, disabled if empty",
+	"jsonrpc.addr":            "JSON-RPC and websocket API address, disabled if empty",
+	"grpc.addr":               "Relay gRPC API address, disabled if empty",
+	"graphql.addr":            "GraphQL history API address, disabled if empty",
+	"metrics.addr":            "Metrics and health probe address, disabled if empty",
+	"admin.addr":              "Admin API address, disabled if empty",
+	"admin.token":             "Bearer token for the admin API, required if it's enabled",
+	"tls.cert-file":           "TLS certificate for the APIs",
+	"tls.key-file":            "TLS key for the APIs",
+	"tls.acme-domains":        "Domains to obtain TLS certificates for via ACME",
+	"tls.acme-cache-dir":      "Directory ACME certificates are cached in",
+	"tls.acme-email":          "Contact email for the ACME account",
+	"tls.client-ca-file":      "CAs client certificates are verified against",
+	"tls.require-client-cert": "Require client certificates",
+	"log.level":               "Log level: debug, info, warn or error",
+	"log.format":              "Log format: text or json",
+	"log.modules":             "Per-module log levels, e.g. listener=debug",
+	"log.file":                "Log file, rotated by size, stderr if empty",
+	"log.max-size-mb":         "Size at which the log file is rotated",
+	"log.max-backups":         "Rotated log files kept, 0 keeps all",
+	"log.sample-interval":     "Window repeated debug logs are sampled over, 0 disables sampling",
+	"event.sink":              "Domain event stream sink: stdout, file, nats or kafka, disabled if empty",
+	"event.path":              "File events are appended to, for file",
+	"event.url":               "Server URL, for nats",
+	"event.subject":           "Subject prefix for nats, published to <subject>.<l1 block>, topic for kafka",
+	"event.brokers":           "Brokers, for kafka",
+	"event.buffer-size":       "Events queued for the sink before dropping",
+	"alert.webhooks":          "Webhook URLs alerts are posted to as JSON",
+	"alert.slack-urls":        "Slack incoming webhook URLs alerts are posted to",
+	"alert.pagerduty-key":     "PagerDuty Events API routing key alerts are triggered with",
+	"alert.dedup-interval":    "Interval repeated alerts are sent once in",
+	"alert.rpc-errors":        "RPC errors within the window that raise an alert, 0 disables",
+	"alert.rpc-window":        "Window RPC errors are counted in",
+	"health.max-auction-age":  "Liveness fails if no auction closed for this long",
+	"health.max-unsettled":    "Readiness fails if more won auctions await settlement, 0 to disable",
+	"retention.bids":          "Bids received longer ago are pruned, 0 keeps bids forever",
+	"retention.interval":      "Interval between history prune runs",
+	"clock.max-drift":         "Auctions aren't opened while the clock drifts further from NTP or L1 slot boundaries, 0 disables",
+	"clock.ntp-server":        "SNTP server host:port the clock is checked against, e.g. pool.ntp.org:123, disabled if empty",
+	"clock.interval":          "Interval between NTP queries and clock step checks",
+	"daemon.pid-file":         "File the process ID is written to while running, disabled if empty",
+	"daemon.shutdown-timeout": "Time allowed on SIGTERM for the auction in progress to close and servers to drain",
+
+	"chaos.drop-bids-percent":        "Fault injection: percentage of submitted bids silently dropped",
+	"chaos.winner-delay":             "Fault injection: delay before won auctions are handed to settlement",
+	"chaos.fail-settlements-percent": "Fault injection: percentage of settlement submits failed",
+	"federation.replica-id":          "Unique ID of this replica, e.g. its hostname",
+	"federation.lease-backend":       "Where replicas elect their leader by lease: sqlite or postgres, disabled if empty",
+	"federation.lease-path":          "Lease database file for sqlite",
+	"federation.lease-url":           "Lease database connection string for postgres",
+	"federation.lease-ttl":           "How long the leader holds the lease without renewing it",
+	"federation.gossip-listen":       "libp2p multiaddrs to gossip bids between replicas on",
+	"federation.gossip-peers":        "Multiaddrs of the other replicas, including their /p2p/ peer ID",
+	"federation.gossip-key-file":     "Hex secp256k1 private key of the replica's gossip identity, random if empty",
+	"funding.interval":               "Interval between checks of the settlement key's balance, 0 disables",
+	"funding.settlement-gas":         "Gas a settlement tx is projected to use",
+	"funding.min-balance-gwei":       "Alert below this settlement key balance, 0 disables",
+	"funding.min-runway":             "Alert once the settlement key's balance covers settling every slot for less than this, 0 disables",
+	"gas.blocks":                     "Recent blocks the settlement tx priority fee is estimated from",
+	"gas.percentile":                 "Percentile of each block's priority fees the estimate takes",
+	"gas.base-fee-multiplier":        "Settlement txs' max fee covers the next block's base fee times this, plus the priority fee",
+	"gas.min-priority-fee-gwei":      "Lowest priority fee settlement txs pay, none if 0",
+	"gas.max-priority-fee-gwei":      "Highest priority fee settlement txs pay, none if 0",
+	"gas.max-fee-gwei":               "Cap of settlement txs' max fee, none if 0",
+	"bulletin.url":                   "HTTP bulletin endpoint signed auction results are posted to, disabled if empty",
+	"bulletin.path":                  "File signed auction results are appended to, disabled if empty",
+	"heartbeat.enabled":              "Publish a signed heartbeat on the event feed every slot, for relays to prove liveness with",
+	"sealed.key-file":                "Hex secp256k1 private key sealed bids are opened with as their auction closes, disabled if empty",
+	"sealed.public-key":              "Compressed hex sealing key split among the committee, from the sealed split command",
+	"sealed.committee":               "Committee members' share server URLs sealed bids are opened with, instead of sealed.key-file",
+	"sealed.threshold":               "Committee members whose decryption shares open a sealed bid",
+	"transport.max-message-size":     "Largest JSON-RPC request body, websocket message or gRPC message accepted from a connection, in bytes",
+	"transport.message-rate":         "Sustained messages per second allowed per relay connection, 0 disables the limit",
+	"transport.message-burst":        "Messages allowed per relay connection in a burst above transport.message-rate",
+	"watchers.addresses":             "Third-party watchers whose attestations of issued commitments are accepted, disabled if empty",
+	"watchers.quorum":                "Watchers' attestations a commitment needs to be multi-attested",
+	"recovery.from-block":            "L1 block history is scanned from on startup, 0 scans all history",
+	"engines":                        "Other chains' auctions run in this process, config files by engine name, e.g. holesky=holesky.yaml",
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(key, ".", "-")
+}
+
+func newRunCommand() *cobra.Command {
+	flagConfig := config.Default()
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the auctioneer node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := loadConfig(cmd, &flagConfig)
+			if err != nil {
+				return err
+			}
+			if err := c.Validate(); err != nil {
+				return err
+			}
+			return runNode(cmd.Context(), c, func() (config.Config, error) { return loadConfig(cmd, &flagConfig) })
+		},
+	}
+	bindFlags(cmd.Flags(), &flagConfig)
+	return cmd
+}
+
+// Defaults are c's values
+func bindFlags(flags *pflag.FlagSet, c *config.Config) {
+	for _, field := range c.Fields() {
+		name, usage := flagName(field.Key), flagUsage[field.Key]
+		switch v := field.Value.(type) {
+		case *string:
+			flags.StringVar(v, name, *v, usage)
+		case *bool:
+			flags.BoolVar(v, name, *v, usage)
+		case *int:
+			flags.IntVar(v, name, *v, usage)
+		case *uint64:
+			flags.Uint64Var(v, name, *v, usage)
+		case *time.Duration:
+			flags.DurationVar(v, name, *v, usage)
+		case *[]string:
+			flags.StringSliceVar(v, name, *v, usage)
+		case *map[string]string:
+			flags.StringToStringVar(v, name, *v, usage)
+		default:
+			panic(fmt.Sprintf("no flag type for %s (%T)", field.Key, field.Value))
+		}
+	}
+}
+
+// Loads --config and the environment, overridden by the flags that were set
+func loadConfig(cmd *cobra.Command, flagConfig *config.Config) (config.Config, error) {
+	path, _ := cmd.Flags().GetString(configFlag)
+	c, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, err
+	}
+	flagFields := flagConfig.Fields()
+	for i, field := range c.Fields() {
+		if cmd.Flags().Changed(flagName(field.Key)) {
+			reflect.ValueOf(field.Value).Elem().Set(reflect.ValueOf(flagFields[i].Value).Elem())
+		}
+	}
+	return c, nil
+}
+
+func tlsSettings(c config.TLSConfig) tlsconfig.Config {
+	return tlsconfig.Config{
+		CertFile:          c.CertFile,
+		KeyFile:           c.KeyFile,
+		ACMEDomains:       c.ACMEDomains,
+		ACMECacheDir:      c.ACMECacheDir,
+		ACMEEmail:         c.ACMEEmail,
+		ClientCAFile:      c.ClientCAFile,
+		RequireClientCert: c.RequireClientCert,
+	}
+}
+
+func eventSink(c config.EventConfig) eventstream.SinkConfig {
+	return eventstream.SinkConfig{
+		Type:    c.Sink,
+		Path:    c.Path,
+		URL:     c.URL,
+		Subject: c.Subject,
+		Brokers: c.Brokers,
+	}
+}
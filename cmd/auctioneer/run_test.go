@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryKeyHasAFlag(t *testing.T) {
+	c := config.Default()
+	run := newRunCommand()
+	for _, field := range c.Fields() {
+		require.NotEmpty(t, flagUsage[field.Key], field.Key)
+		require.NotNil(t, run.Flags().Lookup(flagName(field.Key)), field.Key)
+	}
+	require.Len(t, flagUsage, len(c.Fields()), "usage for keys that don't exist")
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := writeConfig(t, "node.yaml", `
+l1:
+  rpc-url: http://file:8545
+store:
+  backend: sqlite
+  path: /var/lib/auctioneer.db
+auction:
+  allowlist:
+    - "0x0000000000000000000000000000000000000001"
+log:
+  modules:
+    listener: debug
+`)
+	t.Setenv("AUCTIONEER_STORE_BACKEND", "leveldb")
+	t.Setenv("AUCTIONEER_L1_RPC_URL", "http://env:8545")
+
+	var loaded config.Config
+	root := newRootCommand()
+	run, _, err := root.Find([]string{"run"})
+	require.NoError(t, err)
+	flagConfig := config.Default()
+	run.ResetFlags()
+	bindFlags(run.Flags(), &flagConfig)
+	run.RunE = func(cmd *cobra.Command, args []string) error {
+		loaded, err = loadConfig(cmd, &flagConfig)
+		return err
+	}
+	root.SetArgs([]string{"run", "--config", path, "--l1-rpc-url", "http://flag:8545", "--auction-denylist", "0x0000000000000000000000000000000000000002"})
+	require.NoError(t, root.Execute())
+
+	require.Equal(t, "http://flag:8545", loaded.L1.RPCURL, "flags take precedence")
+	require.Equal(t, "leveldb", loaded.Store.Backend, "the environment takes precedence over the file")
+	require.Equal(t, "/var/lib/auctioneer.db", loaded.Store.Path)
+	require.Equal(t, []string{"0x0000000000000000000000000000000000000001"}, loaded.Auction.Allowlist)
+	require.Equal(t, []string{"0x0000000000000000000000000000000000000002"}, loaded.Auction.Denylist)
+	require.Equal(t, map[string]string{"listener": "debug"}, loaded.Log.Modules)
+	require.Equal(t, config.Default().Event.BufferSize, loaded.Event.BufferSize, "defaults where nothing is set")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/heartbeat"
+	"blob-preconfs/pkg/relayclient"
+	"blob-preconfs/pkg/strategy"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Bids according to the strategy as auction events arrive, and logs the outcomes. Heartbeats are checked with
+// monitor, if non-nil, logging the slots the auctioneer was down or didn't auction.
+func handlers(ctx context.Context, logger *slog.Logger, bidder *strategy.Bidder, monitor *heartbeat.Monitor) relayclient.Handlers {
+	h := relayclient.Handlers{
+		OnEvent: func(ev auction.Event) {
+			bidder.HandleEvent(ctx, ev)
+		},
+		OnWon: func(winner *auction.SignedBid) {
+			logger.Info("won auction", "l1Block", winner.L1Block, "amountWei", winner.AmountWei)
+		},
+		OnLost: func(l1Block *big.Int, winner *auction.SignedBid) {
+			if winner == nil {
+				logger.Info("auction closed with no winner", "l1Block", l1Block)
+				return
+			}
+			logger.Info("lost auction", "l1Block", l1Block, "winner", winner.Address, "amountWei", winner.AmountWei)
+		},
+		OnSettled: func(winner *auction.SignedBid, settlementTx common.Hash) {
+			logger.Info("winning bid settled", "l1Block", winner.L1Block, "settlementTx", settlementTx)
+		},
+	}
+	if monitor != nil {
+		h.OnHeartbeat = func(hb *auction.Heartbeat) {
+			observation, err := monitor.Observe(*hb)
+			switch {
+			case err != nil:
+				logger.Warn("invalid auctioneer heartbeat", "slot", hb.Slot, "auctioneer", hb.Auctioneer, "error", err)
+			case observation.MissedSlots > 0:
+				logger.Warn("auctioneer heartbeats missed", "slot", hb.Slot, "missedSlots", observation.MissedSlots)
+			case observation.Stalled:
+				logger.Warn("no auction closed since the auctioneer's last heartbeat", "slot", hb.Slot, "l1Block", hb.L1Block)
+			}
+		}
+	}
+	return h
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/heartbeat"
+	"blob-preconfs/pkg/strategy"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBidClient struct {
+	privateKey *ecdsa.PrivateKey
+	bids       []*auction.SignedBid
+}
+
+func (m *mockBidClient) Address() common.Address {
+	return crypto.PubkeyToAddress(m.privateKey.PublicKey)
+}
+
+func (m *mockBidClient) Bid(ctx context.Context, amountWei *big.Int, l1Block *big.Int) (*auction.SignedBid, error) {
+	bid := auction.MustCreateSignedBid(amountWei, l1Block, m.privateKey)
+	m.bids = append(m.bids, bid)
+	return bid, nil
+}
+
+func TestHandlers(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	client := &mockBidClient{privateKey: pk}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := &strategy.Incremental{MaxPriceWei: big.NewInt(100), IncrementWei: big.NewInt(10), OpeningBidWei: big.NewInt(5)}
+	h := handlers(context.Background(), logger, strategy.NewBidder(logger, client, s), nil)
+
+	h.OnEvent(auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)})
+	h.OnEvent(auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), other)})
+	require.Len(t, client.bids, 2, "events drive the strategy")
+	require.Equal(t, big.NewInt(5), client.bids[0].AmountWei)
+	require.Equal(t, big.NewInt(60), client.bids[1].AmountWei)
+}
+
+func TestHandlersHeartbeats(t *testing.T) {
+	auctioneer, _ := crypto.GenerateKey()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	monitor := heartbeat.NewMonitor(crypto.PubkeyToAddress(auctioneer.PublicKey))
+	h := handlers(context.Background(), logger, nil, monitor)
+	beat := func(slot uint64, l1Block uint64) *auction.Heartbeat {
+		hb, err := auction.CreateSignedHeartbeat(slot, l1Block, common.Hash{}, false, "dev", time.Now(), auctioneer)
+		require.NoError(t, err)
+		return hb
+	}
+
+	h.OnHeartbeat(beat(10, 100))
+	h.OnHeartbeat(beat(11, 101))
+	require.Empty(t, logs.String())
+	h.OnHeartbeat(beat(13, 102))
+	require.Contains(t, logs.String(), "auctioneer heartbeats missed")
+	h.OnHeartbeat(beat(14, 102))
+	require.Contains(t, logs.String(), "no auction closed")
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"blob-preconfs/pkg/heartbeat"
+	"blob-preconfs/pkg/keys"
+	"blob-preconfs/pkg/relayclient"
+	"blob-preconfs/pkg/strategy"
+	"blob-preconfs/pkg/tlsconfig"
+	"blob-preconfs/pkg/version"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	Endpoint  string
+	Signer    keys.Source
+	Strategy  string
+	TLS       tlsconfig.ClientConfig
+	Reconnect time.Duration
+	LogLevel  string
+	// Auctioneer keys heartbeats are checked against, unchecked if empty
+	Auctioneers []string
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	var config config
+	cmd := &cobra.Command{
+		Use:   "bidder",
+		Short: "Bid in the auctioneer's relay auctions according to a strategy file",
+		Long: `Watches the auctioneer's auction stream, bidding as the strategy decides.
+
+The strategy file is YAML, with amounts in wei. Incremental strategies bid the opening bid when an auction opens,
+and outbid other relays by the increment up to the max price:
+
+  type: incremental # the default
+  max-price-wei: 1000000000000000
+  increment-wei: 1000000000
+  opening-bid-wei: 1000000000 # defaults to increment-wei
+
+Snipe strategies stay out of the auction until shortly before it closes, then outbid the leader once:
+
+  type: snipe
+  max-price-wei: 1000000000000000
+  increment-wei: 1000000000
+  snipe-before: 300ms
+
+Value strategies bid incrementally up to the value of the relay's queued blobs for the block, less a margin,
+read from the relay's own endpoint at demand-url?l1Block=N returning {"valueWei": "N"}:
+
+  type: value
+  increment-wei: 1000000000
+  demand-url: http://localhost:8080/demand
+  margin-percent: 20`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return run(ctx, config)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&config.Endpoint, "endpoint", "ws://localhost:8545", "Auctioneer websocket endpoint, ws:// or wss://")
+	flags.StringVar(&config.Signer.KeystoreDir, "keystore-dir", "", "Keystore with the relay's key, see the keys command")
+	flags.StringVar(&config.Signer.Address, "address", "", "Keystore key to bid with, the active key if empty")
+	flags.StringVar(&config.Signer.PasswordFile, "password-file", "", "File with the keystore password")
+	flags.StringVar(&config.Signer.KeyFile, "key-file", "", "File with the relay's unencrypted hex private key, instead of a keystore")
+	flags.StringVar(&config.Strategy, "strategy", "strategy.yaml", "Strategy file")
+	flags.StringVar(&config.TLS.CAFile, "tls-ca-file", "", "CAs to verify the auctioneer with, the system roots if empty")
+	flags.StringVar(&config.TLS.CertFile, "tls-cert-file", "", "Client certificate, if the auctioneer requires one")
+	flags.StringVar(&config.TLS.KeyFile, "tls-key-file", "", "Client key, if the auctioneer requires a certificate")
+	flags.DurationVar(&config.Reconnect, "reconnect-interval", 5*time.Second, "Interval between reconnects when the stream fails")
+	flags.StringVar(&config.LogLevel, "log-level", "info", "Log level: debug, info, warn or error")
+	flags.StringSliceVar(&config.Auctioneers, "auctioneer", nil, "Auctioneer signing addresses heartbeats are checked against, unchecked if empty")
+	cmd.AddCommand(keys.NewCommand(), version.NewCommand())
+	return cmd
+}
+
+func run(ctx context.Context, config config) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.LogLevel)); err != nil {
+		return fmt.Errorf("--log-level: %w", err)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	strategy, err := loadStrategy(config.Strategy)
+	if err != nil {
+		return err
+	}
+	signer, err := config.Signer.Load()
+	if err != nil {
+		return err
+	}
+	tlsConfig, err := config.TLS.Build()
+	if err != nil {
+		return err
+	}
+	var monitor *heartbeat.Monitor
+	if len(config.Auctioneers) > 0 {
+		trusted := make([]common.Address, len(config.Auctioneers))
+		for i, address := range config.Auctioneers {
+			if !common.IsHexAddress(address) {
+				return fmt.Errorf("--auctioneer: invalid address %q", address)
+			}
+			trusted[i] = common.HexToAddress(address)
+		}
+		monitor = heartbeat.NewMonitor(trusted...)
+	}
+	logger.Info("bidding", "relay", crypto.PubkeyToAddress(signer.PublicKey), "endpoint", config.Endpoint, "strategy", fmt.Sprintf("%T", strategy))
+
+	for {
+		err := stream(ctx, logger, config.Endpoint, signer, tlsConfig, strategy, monitor)
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.Warn("auction stream failed, reconnecting", "error", err, "in", config.Reconnect)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(config.Reconnect):
+		}
+	}
+}
+
+// Bids until the stream fails or ctx is done
+func stream(
+	ctx context.Context,
+	logger *slog.Logger,
+	endpoint string,
+	signer *ecdsa.PrivateKey,
+	tlsConfig *tls.Config,
+	s strategy.Strategy,
+	monitor *heartbeat.Monitor,
+) error {
+	client, err := relayclient.NewBidderClient(ctx, logger, endpoint, signer, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Run(ctx, handlers(ctx, logger, strategy.NewBidder(logger, client, s), monitor))
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"blob-preconfs/pkg/strategy"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errInvalidStrategy = errors.New("invalid strategy")
+
+// Amounts are integers in wei, quoted or not, since they may overflow YAML's integers
+type strategyFile struct {
+	// incremental, snipe or value, incremental if empty
+	Type          string        `yaml:"type"`
+	MaxPriceWei   string        `yaml:"max-price-wei"`
+	IncrementWei  string        `yaml:"increment-wei"`
+	OpeningBidWei string        `yaml:"opening-bid-wei"`
+	SnipeBefore   time.Duration `yaml:"snipe-before"`
+	DemandURL     string        `yaml:"demand-url"`
+	MarginPercent uint64        `yaml:"margin-percent"`
+}
+
+func loadStrategy(path string) (strategy.Strategy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read strategy file: %w", err)
+	}
+	var file strategyFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errInvalidStrategy, path, err)
+	}
+	var errs []error
+	parse := func(key, value string, required bool) *big.Int {
+		if value == "" {
+			if required {
+				errs = append(errs, fmt.Errorf("%s: required", key))
+			}
+			return nil
+		}
+		amount, ok := new(big.Int).SetString(value, 10)
+		if !ok || amount.Sign() <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be a positive integer, not %q", key, value))
+			return nil
+		}
+		return amount
+	}
+	// Keys of other types are rejected rather than silently ignored
+	unused := func(key string, set bool) {
+		if set {
+			errs = append(errs, fmt.Errorf("%s: not used by %s strategies", key, file.Type))
+		}
+	}
+
+	var s strategy.Strategy
+	increment := parse("increment-wei", file.IncrementWei, true)
+	switch file.Type {
+	case "", "incremental":
+		file.Type = "incremental"
+		incremental := &strategy.Incremental{
+			MaxPriceWei:   parse("max-price-wei", file.MaxPriceWei, true),
+			IncrementWei:  increment,
+			OpeningBidWei: parse("opening-bid-wei", file.OpeningBidWei, false),
+		}
+		if incremental.OpeningBidWei == nil {
+			incremental.OpeningBidWei = increment
+		}
+		if len(errs) == 0 && incremental.OpeningBidWei.Cmp(incremental.MaxPriceWei) > 0 {
+			errs = append(errs, fmt.Errorf("opening-bid-wei: exceeds max-price-wei"))
+		}
+		unused("snipe-before", file.SnipeBefore != 0)
+		unused("demand-url", file.DemandURL != "")
+		unused("margin-percent", file.MarginPercent != 0)
+		s = incremental
+	case "snipe":
+		if file.SnipeBefore <= 0 {
+			errs = append(errs, fmt.Errorf("snipe-before: must be positive"))
+		}
+		s = &strategy.Snipe{MaxPriceWei: parse("max-price-wei", file.MaxPriceWei, true), IncrementWei: increment, Before: file.SnipeBefore}
+		unused("opening-bid-wei", file.OpeningBidWei != "")
+		unused("demand-url", file.DemandURL != "")
+		unused("margin-percent", file.MarginPercent != 0)
+	case "value":
+		if file.DemandURL == "" {
+			errs = append(errs, fmt.Errorf("demand-url: required"))
+		}
+		if file.MarginPercent >= 100 {
+			errs = append(errs, fmt.Errorf("margin-percent: must be below 100"))
+		}
+		s = &strategy.ValueBased{Demand: &strategy.HTTPDemand{URL: file.DemandURL}, MarginPercent: file.MarginPercent, IncrementWei: increment}
+		unused("max-price-wei", file.MaxPriceWei != "")
+		unused("opening-bid-wei", file.OpeningBidWei != "")
+		unused("snipe-before", file.SnipeBefore != 0)
+	default:
+		errs = append(errs, fmt.Errorf("type: must be incremental, snipe or value, not %q", file.Type))
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %s: %w", errInvalidStrategy, path, errors.Join(errs...))
+	}
+	return s, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/strategy"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeStrategy(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "strategy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadStrategy(t *testing.T) {
+	s, err := loadStrategy(writeStrategy(t, "max-price-wei: \"100000000000000000000\"\nincrement-wei: 10\n"))
+	require.NoError(t, err)
+	incremental := s.(*strategy.Incremental)
+	require.Equal(t, "100000000000000000000", incremental.MaxPriceWei.String(), "amounts may overflow int64")
+	require.Equal(t, big.NewInt(10), incremental.IncrementWei)
+	require.Equal(t, big.NewInt(10), incremental.OpeningBidWei, "opening bid defaults to the increment")
+
+	s, err = loadStrategy(writeStrategy(t, "type: snipe\nmax-price-wei: 100\nincrement-wei: 10\nsnipe-before: 300ms\n"))
+	require.NoError(t, err)
+	require.Equal(t, &strategy.Snipe{MaxPriceWei: big.NewInt(100), IncrementWei: big.NewInt(10), Before: 300 * time.Millisecond}, s)
+
+	s, err = loadStrategy(writeStrategy(t, "type: value\nincrement-wei: 10\ndemand-url: http://localhost:8080/demand\nmargin-percent: 20\n"))
+	require.NoError(t, err)
+	value := s.(*strategy.ValueBased)
+	require.Equal(t, &strategy.HTTPDemand{URL: "http://localhost:8080/demand"}, value.Demand)
+	require.Equal(t, uint64(20), value.MarginPercent)
+
+	for name, content := range map[string]string{
+		"no max price":        "increment-wei: 10\n",
+		"no increment":        "max-price-wei: 100\n",
+		"not an integer":      "max-price-wei: 1.5\nincrement-wei: 10\n",
+		"negative":            "max-price-wei: 100\nincrement-wei: -10\n",
+		"opening exceeds max": "max-price-wei: 100\nincrement-wei: 10\nopening-bid-wei: 200\n",
+		"unknown key":         "max-price-wei: 100\nincrement-wei: 10\nmax-price: 100\n",
+		"unknown type":        "type: random\nmax-price-wei: 100\nincrement-wei: 10\n",
+		"no snipe-before":     "type: snipe\nmax-price-wei: 100\nincrement-wei: 10\n",
+		"no demand-url":       "type: value\nincrement-wei: 10\n",
+		"margin over 100":     "type: value\nincrement-wei: 10\ndemand-url: http://localhost\nmargin-percent: 100\n",
+		"unused key":          "type: value\nincrement-wei: 10\ndemand-url: http://localhost\nmax-price-wei: 100\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadStrategy(writeStrategy(t, content))
+			require.ErrorIs(t, err, errInvalidStrategy)
+		})
+	}
+}
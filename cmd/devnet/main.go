@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"blob-preconfs/pkg/localnet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/spf13/cobra"
+)
+
+type devnet struct {
+	Chain         string
+	RPCPort       int
+	BlockTime     time.Duration
+	Relays        int
+	MaxBidWei     int64
+	AuctioneerBin string
+	Dir           string
+	LogLevel      string
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	var d devnet
+	cmd := &cobra.Command{
+		Use:   "devnet",
+		Short: "Run a dev chain, the auctioneer and simulated relays for a local preconf flow",
+		Long: `Starts an anvil or geth --dev chain, funds an auctioneer key and one key per relay, then runs the
+auctioneer against the chain and relays bidding against each other in every auction, until interrupted.
+
+Keys and the node config are written to --dir, so the auctioneer and bidder CLIs can be pointed at the devnet.
+Run from within the repository, unless --auctioneer-bin is set.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return d.run(ctx)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&d.Chain, "chain", localnet.ChainAnvil, "Dev chain: anvil or geth")
+	flags.IntVar(&d.RPCPort, "rpc-port", 8546, "Dev chain HTTP RPC port")
+	flags.DurationVar(&d.BlockTime, "block-time", 12*time.Second, "Dev chain block time, whole seconds")
+	flags.IntVar(&d.Relays, "relays", 3, "Number of simulated relays")
+	flags.Int64Var(&d.MaxBidWei, "max-bid-wei", params.GWei, "Highest bid of the simulated relays, each relay's max is a share of it")
+	flags.StringVar(&d.AuctioneerBin, "auctioneer-bin", "", "Auctioneer binary, built from ./cmd/auctioneer if empty")
+	flags.StringVar(&d.Dir, "dir", "", "Directory for keys, config and chain data, a temporary directory removed on exit if empty")
+	flags.StringVar(&d.LogLevel, "log-level", "info", "Relay log level: debug, info, warn or error")
+	return cmd
+}
+
+func (d devnet) run(ctx context.Context) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(d.LogLevel)); err != nil {
+		return fmt.Errorf("--log-level: %w", err)
+	}
+	if d.Relays < 1 {
+		return fmt.Errorf("--relays must be at least 1")
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	dir := d.Dir
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "devnet-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	} else if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	bin := d.AuctioneerBin
+	if bin == "" {
+		var err error
+		if bin, err = localnet.BuildAuctioneer(ctx, dir); err != nil {
+			return err
+		}
+	}
+
+	signerKey, signerFile, err := newKey(dir, "auctioneer.key")
+	if err != nil {
+		return err
+	}
+	relayKeys := make([]*ecdsa.PrivateKey, d.Relays)
+	addresses := []common.Address{crypto.PubkeyToAddress(signerKey.PublicKey)}
+	for i := range relayKeys {
+		if relayKeys[i], _, err = newKey(dir, "relay-"+strconv.Itoa(i)+".key"); err != nil {
+			return err
+		}
+		addresses = append(addresses, crypto.PubkeyToAddress(relayKeys[i].PublicKey))
+	}
+
+	chainCtx, stopChain := context.WithCancel(context.Background())
+	defer stopChain()
+	chain, err := localnet.StartChain(chainCtx, d.Chain, d.RPCPort, d.BlockTime, dir)
+	if err != nil {
+		return err
+	}
+	defer wait(logger, d.Chain, chain, stopChain)
+	rpcURL := "http://127.0.0.1:" + strconv.Itoa(d.RPCPort)
+	if err := localnet.WaitForRPC(ctx, rpcURL, 30*time.Second); err != nil {
+		return err
+	}
+	if err := localnet.Fund(ctx, d.Chain, rpcURL, addresses, new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))); err != nil {
+		return err
+	}
+
+	c := nodeConfig(d.Chain, rpcURL, signerFile, addresses[1:], d.BlockTime)
+	configFile := filepath.Join(dir, "node.yaml")
+	if err := writeNodeConfig(configFile, c); err != nil {
+		return err
+	}
+	nodeCtx, stopNode := context.WithCancel(context.Background())
+	defer stopNode()
+	node, err := localnet.StartAuctioneer(nodeCtx, bin, configFile)
+	if err != nil {
+		return err
+	}
+	defer wait(logger, "auctioneer", node, stopNode)
+	if err := localnet.WaitForListener(ctx, c.JSONRPC.Addr, 30*time.Second); err != nil {
+		return err
+	}
+
+	var relays sync.WaitGroup
+	defer relays.Wait()
+	endpoint := "ws://" + c.JSONRPC.Addr
+	for i, key := range relayKeys {
+		relay := &simulatedRelay{
+			logger:       logger.With("relay", crypto.PubkeyToAddress(key.PublicKey)),
+			key:          key,
+			maxWei:       big.NewInt(d.MaxBidWei / int64(d.Relays) * int64(i+1)),
+			incrementWei: big.NewInt(max(1, d.MaxBidWei/100)),
+		}
+		relays.Add(1)
+		go func() {
+			defer relays.Done()
+			relay.run(ctx, endpoint)
+		}()
+	}
+	logger.Info("devnet running, interrupt to stop", "chain", rpcURL, "auctioneer", endpoint,
+		"config", configFile, "adminToken", adminToken, "relays", d.Relays, "dir", dir)
+	<-ctx.Done()
+	return nil
+}
+
+// Generates a key, saved as hex to name in dir
+func newKey(dir string, name string) (*ecdsa.PrivateKey, string, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, "", err
+	}
+	path := filepath.Join(dir, name)
+	return key, path, crypto.SaveECDSA(path, key)
+}
+
+// Stops the process and waits for it to exit
+func wait(logger *slog.Logger, name string, cmd *exec.Cmd, stop context.CancelFunc) {
+	stop()
+	if err := cmd.Wait(); err != nil && cmd.ProcessState != nil && !cmd.ProcessState.Success() {
+		logger.Warn("process exited", "name", name, "error", err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/localnet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Admin token of the devnet auctioneer, only reachable on localhost
+const adminToken = "devnet"
+
+// Auctioneer config for the kind of dev chain at rpcURL, signing with the key in keyFile and allowing only relays.
+// Auctions close halfway through each block.
+func nodeConfig(kind string, rpcURL string, keyFile string, relays []common.Address, blockTime time.Duration) config.Config {
+	c := config.Default()
+	c.NetworkName = "local"
+	c.Chain.SlotTime = blockTime
+	if kind == localnet.ChainGeth {
+		c.Chain.ChainID = localnet.GethDevChainID
+	}
+	c.L1.RPCURL = rpcURL
+	c.Signer.KeyFile = keyFile
+	c.Auction.Period = min(c.Auction.Period, blockTime/2)
+	for _, relay := range relays {
+		c.Auction.Allowlist = append(c.Auction.Allowlist, relay.Hex())
+		c.Registry.Relays = append(c.Registry.Relays, relay.Hex())
+	}
+	c.REST.Addr = "127.0.0.1:8080"
+	c.JSONRPC.Addr = "127.0.0.1:8545"
+	c.GRPC.Addr = "127.0.0.1:9090"
+	c.Metrics.Addr = "127.0.0.1:9100"
+	c.Admin.Token = adminToken
+	return c
+}
+
+func writeNodeConfig(path string, c config.Config) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/localnet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeConfig(t *testing.T) {
+	relays := []common.Address{{0x01}, {0x02}}
+	c := nodeConfig(localnet.ChainAnvil, "http://127.0.0.1:8546", "/tmp/auctioneer.key", relays, 2*time.Second)
+	require.NoError(t, c.Validate())
+	require.Equal(t, time.Second, c.Auction.Period, "auctions close before the next block")
+	require.Equal(t, uint64(31337), c.Network().ChainID)
+	require.Equal(t, uint64(localnet.GethDevChainID), nodeConfig(localnet.ChainGeth, "http://127.0.0.1:8546", "/tmp/auctioneer.key", relays, 2*time.Second).Network().ChainID)
+	require.Equal(t, []string{relays[0].Hex(), relays[1].Hex()}, c.Auction.Allowlist)
+
+	path := filepath.Join(t.TempDir(), "node.yaml")
+	require.NoError(t, writeNodeConfig(path, c))
+	loaded, err := config.Load(path)
+	require.NoError(t, err)
+	require.Equal(t, c.Auction.Period, loaded.Auction.Period)
+	require.Equal(t, c.Auction.Allowlist, loaded.Auction.Allowlist)
+	require.Equal(t, c.Signer, loaded.Signer)
+	require.Equal(t, c.Network(), loaded.Network())
+	require.Equal(t, c.Registry, loaded.Registry)
+	require.Equal(t, c.JSONRPC, loaded.JSONRPC)
+	require.Equal(t, c.Admin, loaded.Admin)
+}
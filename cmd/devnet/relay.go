@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/relayclient"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Relay bidding against the others: opens each auction with a random bid below its max price,
+// then outbids the leader by increment while that stays within it
+type simulatedRelay struct {
+	logger       *slog.Logger
+	key          *ecdsa.PrivateKey
+	maxWei       *big.Int
+	incrementWei *big.Int
+}
+
+// Bids until ctx is done, reconnecting if the stream fails
+func (r *simulatedRelay) run(ctx context.Context, endpoint string) {
+	for {
+		client, err := relayclient.NewBidderClient(ctx, r.logger, endpoint, r.key, nil)
+		if err == nil {
+			err = client.Run(ctx, r.handlers(ctx, client))
+			client.Close()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		r.logger.Debug("auction stream failed, reconnecting", "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (r *simulatedRelay) handlers(ctx context.Context, client *relayclient.BidderClient) relayclient.Handlers {
+	address := crypto.PubkeyToAddress(r.key.PublicKey)
+	bid := func(amount, l1Block *big.Int) {
+		if _, err := client.Bid(ctx, amount, l1Block); err != nil {
+			r.logger.Debug("bid rejected", "l1Block", l1Block, "amountWei", amount, "error", err)
+		}
+	}
+	return relayclient.Handlers{
+		OnAuctionOpened: func(l1Block *big.Int) {
+			bid(new(big.Int).Add(r.incrementWei, new(big.Int).Rand(rand.New(rand.NewSource(time.Now().UnixNano())), r.maxWei)), l1Block)
+		},
+		OnLeaderChanged: func(leader *auction.SignedBid) {
+			if leader.Address == address {
+				return
+			}
+			if amount := new(big.Int).Add(leader.AmountWei, r.incrementWei); amount.Cmp(r.maxWei) <= 0 {
+				bid(amount, leader.L1Block)
+			}
+		},
+		OnWon: func(winner *auction.SignedBid) {
+			r.logger.Info("won auction", "l1Block", winner.L1Block, "amountWei", winner.AmountWei)
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"io"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+
+	"golang.org/x/time/rate"
+)
+
+// Submits signed bids from random synthetic relays to the auctioneer's REST API
+type generator struct {
+	client    *http.Client
+	bidsURL   string
+	path      string
+	keys      []*ecdsa.PrivateKey
+	maxBidWei int64
+	stats     *stats
+	// L1 block of the auction in progress, bids aren't sent before one opens
+	l1Block atomic.Uint64
+}
+
+func newGenerator(client *http.Client, endpoint string, keys []*ecdsa.PrivateKey, maxBidWei int64, stats *stats) (*generator, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	// Signed over the path the server sees, which is absolute even if the endpoint has none
+	u = u.JoinPath("/v1/bids")
+	if !strings.HasPrefix(u.Path, "/") {
+		u.Path = "/" + u.Path
+	}
+	return &generator{client: client, bidsURL: u.String(), path: u.Path, keys: keys, maxBidWei: maxBidWei, stats: stats}, nil
+}
+
+// Sends bids at bidsPerSecond from workers until ctx is done
+func (g *generator) run(ctx context.Context, bidsPerSecond float64, workers int) {
+	limiter := rate.NewLimiter(rate.Limit(bidsPerSecond), max(1, workers))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for limiter.Wait(ctx) == nil {
+				l1Block := g.l1Block.Load()
+				if l1Block == 0 {
+					continue
+				}
+				key := g.keys[rng.Intn(len(g.keys))]
+				g.send(ctx, key, big.NewInt(1+rng.Int63n(g.maxBidWei)), new(big.Int).SetUint64(l1Block))
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+}
+
+// Signs and submits one bid, recording the response. The latency excludes signing.
+func (g *generator) send(ctx context.Context, key *ecdsa.PrivateKey, amountWei *big.Int, l1Block *big.Int) {
+	bid, err := auction.CreateSignedBid(amountWei, l1Block, key)
+	if err != nil {
+		g.stats.record(0, 0)
+		return
+	}
+	body, _ := json.Marshal(bid)
+	header, err := auth.Headers(g.path, body, key)
+	if err != nil {
+		g.stats.record(0, 0)
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.bidsURL, bytes.NewReader(body))
+	if err != nil {
+		g.stats.record(0, 0)
+		return
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	sent := time.Now()
+	resp, err := g.client.Do(req)
+	if err != nil {
+		if ctx.Err() == nil {
+			g.stats.record(0, 0)
+		}
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	g.stats.record(resp.StatusCode, time.Since(sent))
+}
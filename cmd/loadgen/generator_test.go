@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type allRegistered struct{}
+
+func (allRegistered) IsRegisteredOnSettlementLayer(address common.Address) bool { return true }
+
+// REST bid endpoint accepting signed, authenticated bids for block 100
+func newBidServer(t *testing.T) (*httptest.Server, *[]auction.SignedBid) {
+	var mu sync.Mutex
+	var bids []auction.SignedBid
+	verifier := auth.NewVerifier(allRegistered{}, time.Minute)
+	handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var bid auction.SignedBid
+		if r.URL.Path != "/v1/bids" || json.NewDecoder(r.Body).Decode(&bid) != nil || bid.Validate() != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := auth.CheckSigner(r.Context(), bid.Address); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if bid.L1Block.Uint64() != 100 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		mu.Lock()
+		bids = append(bids, bid)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, &bids
+}
+
+func TestRelayKeys(t *testing.T) {
+	keys := relayKeys(1, 3)
+	require.Len(t, keys, 3)
+	require.Equal(t, crypto.FromECDSA(keys[2]), crypto.FromECDSA(relayKeys(1, 3)[2]), "keys are deterministic")
+	require.NotEqual(t, crypto.FromECDSA(keys[0]), crypto.FromECDSA(keys[1]))
+	require.NotEqual(t, crypto.FromECDSA(keys[0]), crypto.FromECDSA(relayKeys(2, 1)[0]))
+}
+
+func TestRun(t *testing.T) {
+	server, bids := newBidServer(t)
+	var out, progress bytes.Buffer
+	err := run(context.Background(), config{
+		Endpoint:  server.URL,
+		L1Block:   100,
+		Relays:    5,
+		KeySeed:   1,
+		Rate:      200,
+		Workers:   4,
+		Duration:  300 * time.Millisecond,
+		MaxBidWei: 1e9, // Repeated amounts from a relay in the same second are rejected as replays
+	}, &out, &progress)
+	require.NoError(t, err)
+	require.NotEmpty(t, *bids)
+	relays := addresses(relayKeys(1, 5))
+	for _, bid := range *bids {
+		require.Contains(t, relays, bid.Address)
+	}
+	require.Contains(t, out.String(), fmt.Sprintf("accepted %d (100.0%%)", len(*bids)))
+	require.Contains(t, out.String(), "latency p50")
+}
+
+func TestRunRejected(t *testing.T) {
+	server, bids := newBidServer(t)
+	var out bytes.Buffer
+	err := run(context.Background(), config{
+		Endpoint: server.URL, L1Block: 101, Relays: 1, KeySeed: 1, Rate: 100, Workers: 1, Duration: 100 * time.Millisecond, MaxBidWei: 1e9,
+	}, &out, &bytes.Buffer{})
+	require.NoError(t, err)
+	require.Empty(t, *bids)
+	require.Contains(t, out.String(), "accepted 0 (0.0%)")
+	require.Contains(t, out.String(), "409 Conflict")
+}
+
+func addresses(keys []*ecdsa.PrivateKey) []common.Address {
+	addresses := make([]common.Address, len(keys))
+	for i, key := range keys {
+		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return addresses
+}
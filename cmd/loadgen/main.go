@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"blob-preconfs/pkg/relayclient"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	Endpoint       string
+	Events         string
+	L1Block        uint64
+	Relays         int
+	KeySeed        uint64
+	AddressesFile  string
+	Rate           float64
+	Workers        int
+	Duration       time.Duration
+	MaxBidWei      int64
+	ReportInterval time.Duration
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	var c config
+	cmd := &cobra.Command{
+		Use:   "loadgen",
+		Short: "Submit signed bids from synthetic relays to a running auctioneer at a fixed rate",
+		Long: `Submits bids to the auctioneer's REST API at --rate from --relays synthetic relay keys, for the auction in
+progress, then reports accepted and rejected bids with submission latency.
+
+Keys are derived from --key-seed, so they're the same across runs. Write their addresses with --addresses-file to
+allowlist and register them on the node, otherwise bids are only rejected by the auction.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return run(ctx, c, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&c.Endpoint, "endpoint", "http://127.0.0.1:8080", "Auctioneer REST API URL bids are submitted to")
+	flags.StringVar(&c.Events, "events", "ws://127.0.0.1:8545", "Auctioneer websocket endpoint the auction in progress is followed on")
+	flags.Uint64Var(&c.L1Block, "l1-block", 0, "Bid for this L1 block instead of following auctions")
+	flags.IntVar(&c.Relays, "relays", 100, "Number of synthetic relay keys")
+	flags.Uint64Var(&c.KeySeed, "key-seed", 1, "Seed relay keys are derived from")
+	flags.StringVar(&c.AddressesFile, "addresses-file", "", "File the relay addresses are written to, one per line")
+	flags.Float64Var(&c.Rate, "rate", 1000, "Bids per second")
+	flags.IntVar(&c.Workers, "workers", 64, "Concurrent submissions")
+	flags.DurationVar(&c.Duration, "duration", time.Minute, "How long to send bids for")
+	flags.Int64Var(&c.MaxBidWei, "max-bid-wei", 1_000_000_000, "Bids are random amounts up to this")
+	flags.DurationVar(&c.ReportInterval, "report-interval", 5*time.Second, "Interval progress is reported in, 0 to only report at the end")
+	return cmd
+}
+
+func run(ctx context.Context, c config, out io.Writer, progress io.Writer) error {
+	if c.Relays < 1 || c.Rate <= 0 || c.Workers < 1 || c.MaxBidWei < 1 {
+		return fmt.Errorf("--relays, --rate, --workers and --max-bid-wei must be positive")
+	}
+	keys := relayKeys(c.KeySeed, c.Relays)
+	if c.AddressesFile != "" {
+		if err := writeAddresses(c.AddressesFile, keys); err != nil {
+			return err
+		}
+	}
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: c.Workers, IdleConnTimeout: 90 * time.Second},
+	}
+	stats := newStats(time.Now())
+	g, err := newGenerator(client, c.Endpoint, keys, c.MaxBidWei, stats)
+	if err != nil {
+		return fmt.Errorf("invalid --endpoint: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.Duration)
+	defer cancel()
+	if c.L1Block != 0 {
+		g.l1Block.Store(c.L1Block)
+	} else {
+		logger := slog.New(slog.NewTextHandler(progress, &slog.HandlerOptions{Level: slog.LevelWarn}))
+		go follow(ctx, logger, c.Events, keys[0], func(l1Block *big.Int) { g.l1Block.Store(l1Block.Uint64()) })
+	}
+	if c.ReportInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(c.ReportInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					stats.summary(now).write(progress)
+				}
+			}
+		}()
+	}
+	g.run(ctx, c.Rate, c.Workers)
+	stats.summary(time.Now()).write(out)
+	return nil
+}
+
+// Deterministic keys, so the same relays can be allowlisted across runs
+func relayKeys(seed uint64, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		var data [16]byte
+		binary.BigEndian.PutUint64(data[:8], seed)
+		binary.BigEndian.PutUint64(data[8:], uint64(i))
+		for {
+			key, err := crypto.ToECDSA(crypto.Keccak256(data[:]))
+			if err == nil {
+				keys[i] = key
+				break
+			}
+			// Out of the curve order, vanishingly unlikely
+			data[0]++
+		}
+	}
+	return keys
+}
+
+func writeAddresses(path string, keys []*ecdsa.PrivateKey) error {
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(crypto.PubkeyToAddress(key.PublicKey).Hex())
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// Calls opened with the block of each auction as it opens, until ctx is done
+func follow(ctx context.Context, logger *slog.Logger, endpoint string, key *ecdsa.PrivateKey, opened func(l1Block *big.Int)) {
+	for {
+		client, err := relayclient.NewBidderClient(ctx, logger, endpoint, key, nil)
+		if err == nil {
+			err = client.Run(ctx, relayclient.Handlers{OnAuctionOpened: opened})
+			client.Close()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("auction stream failed, reconnecting", "endpoint", endpoint, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
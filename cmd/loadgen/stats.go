@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Outcomes and latencies of submitted bids
+type stats struct {
+	mu        sync.Mutex
+	started   time.Time
+	statuses  map[int]int
+	errors    int
+	latencies []time.Duration
+}
+
+func newStats(started time.Time) *stats {
+	return &stats{started: started, statuses: make(map[int]int)}
+}
+
+// Records a response, or a request failure if status is 0
+func (s *stats) record(status int, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status == 0 {
+		s.errors++
+		return
+	}
+	s.statuses[status]++
+	s.latencies = append(s.latencies, latency)
+}
+
+type summary struct {
+	Elapsed  time.Duration
+	Sent     int
+	Accepted int
+	// Responses other than 202 Accepted, by status code
+	Rejected map[int]int
+	Errors   int
+	P50      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+func (s *stats) summary(now time.Time) summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sum := summary{Elapsed: now.Sub(s.started), Errors: s.errors, Rejected: make(map[int]int)}
+	for status, n := range s.statuses {
+		if status == http.StatusAccepted {
+			sum.Accepted += n
+		} else {
+			sum.Rejected[status] += n
+		}
+		sum.Sent += n
+	}
+	sum.Sent += s.errors
+	latencies := append([]time.Duration(nil), s.latencies...)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	sum.P50, sum.P99 = percentile(latencies, 0.50), percentile(latencies, 0.99)
+	if len(latencies) > 0 {
+		sum.Max = latencies[len(latencies)-1]
+	}
+	return sum
+}
+
+// Nearest rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func (s summary) write(w io.Writer) {
+	rate := 0.0
+	if s.Elapsed > 0 {
+		rate = float64(s.Sent) / s.Elapsed.Seconds()
+	}
+	fmt.Fprintf(w, "elapsed %s, sent %d (%.0f/s), accepted %d (%.1f%%), rejected %d (%.1f%%), errors %d\n",
+		s.Elapsed.Round(time.Millisecond), s.Sent, rate, s.Accepted, share(s.Accepted, s.Sent),
+		s.Sent-s.Accepted-s.Errors, share(s.Sent-s.Accepted-s.Errors, s.Sent), s.Errors)
+	statuses := make([]int, 0, len(s.Rejected))
+	for status := range s.Rejected {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(w, "  %d %s: %d\n", status, http.StatusText(status), s.Rejected[status])
+	}
+	fmt.Fprintf(w, "latency p50 %s, p99 %s, max %s\n", s.P50.Round(time.Microsecond), s.P99.Round(time.Microsecond), s.Max.Round(time.Microsecond))
+}
+
+func share(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 50*time.Millisecond, percentile(latencies, 0.5))
+	require.Equal(t, 99*time.Millisecond, percentile(latencies, 0.99))
+	require.Equal(t, time.Millisecond, percentile(latencies[:1], 0.99))
+	require.Zero(t, percentile(nil, 0.99))
+}
+
+func TestSummary(t *testing.T) {
+	started := time.Unix(0, 0)
+	s := newStats(started)
+	for i := 1; i <= 8; i++ {
+		s.record(http.StatusAccepted, time.Duration(i)*time.Millisecond)
+	}
+	s.record(http.StatusConflict, 9*time.Millisecond)
+	s.record(http.StatusTooManyRequests, 10*time.Millisecond)
+	s.record(0, 0)
+	sum := s.summary(started.Add(2 * time.Second))
+	require.Equal(t, summary{
+		Elapsed:  2 * time.Second,
+		Sent:     11,
+		Accepted: 8,
+		Rejected: map[int]int{http.StatusConflict: 1, http.StatusTooManyRequests: 1},
+		Errors:   1,
+		P50:      5 * time.Millisecond,
+		P99:      10 * time.Millisecond,
+		Max:      10 * time.Millisecond,
+	}, sum)
+
+	var out bytes.Buffer
+	sum.write(&out)
+	require.Equal(t, `elapsed 2s, sent 11 (6/s), accepted 8 (72.7%), rejected 2 (18.2%), errors 1
+  409 Conflict: 1
+  429 Too Many Requests: 1
+latency p50 5ms, p99 10ms, max 10ms
+`, out.String())
+}
@@ -0,0 +1,3 @@
+// Package e2e runs the full preconf flow against a real execution client. Its tests are behind the e2e
+// build tag: go test -tags e2e ./e2e
+package e2e
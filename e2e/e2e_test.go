@@ -0,0 +1,324 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/config"
+	"blob-preconfs/pkg/localnet"
+	"blob-preconfs/pkg/relayclient"
+	"blob-preconfs/pkg/rest"
+	"blob-preconfs/pkg/simulation"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	blockTime = 2 * time.Second
+	// Bounds each step waiting on the chain or the auctioneer
+	stepTimeout = time.Minute
+)
+
+// Running chain and auctioneer, stopped on cleanup
+type network struct {
+	chain   string
+	eth     *ethclient.Client
+	config  config.Config
+	relay   *ecdsa.PrivateKey
+	sender  *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+// anvil or geth, from E2E_CHAIN or whichever is on the PATH. Skips the test if neither is.
+func chainKind(t *testing.T) string {
+	if kind := os.Getenv("E2E_CHAIN"); kind != "" {
+		return kind
+	}
+	for _, kind := range []string{localnet.ChainAnvil, localnet.ChainGeth} {
+		if _, err := exec.LookPath(kind); err == nil {
+			return kind
+		}
+	}
+	t.Skip("neither anvil nor geth on the PATH")
+	return ""
+}
+
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return key
+}
+
+// Starts the chain, then the auctioneer built from this tree, or E2E_AUCTIONEER_BIN if set
+func start(t *testing.T) *network {
+	kind := chainKind(t)
+	dir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), stepTimeout)
+	defer cancel()
+
+	n := &network{chain: kind, relay: generateKey(t), sender: generateKey(t)}
+	signer := generateKey(t)
+	signerFile := filepath.Join(dir, "auctioneer.key")
+	require.NoError(t, crypto.SaveECDSA(signerFile, signer))
+
+	rpcAddr := freeAddr(t)
+	_, port, _ := net.SplitHostPort(rpcAddr)
+	rpcPort, _ := strconv.Atoi(port)
+	chainCtx, stopChain := context.WithCancel(context.Background())
+	chain, err := localnet.StartChain(chainCtx, kind, rpcPort, blockTime, dir)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		stopChain()
+		chain.Wait()
+	})
+	rpcURL := "http://" + rpcAddr
+	require.NoError(t, localnet.WaitForRPC(ctx, rpcURL, stepTimeout))
+	accounts := []common.Address{crypto.PubkeyToAddress(n.relay.PublicKey), crypto.PubkeyToAddress(n.sender.PublicKey)}
+	require.NoError(t, localnet.Fund(ctx, kind, rpcURL, accounts, new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))))
+	n.eth, err = ethclient.DialContext(ctx, rpcURL)
+	require.NoError(t, err)
+	t.Cleanup(n.eth.Close)
+	n.chainID, err = n.eth.ChainID(ctx)
+	require.NoError(t, err)
+
+	c := config.Default()
+	c.NetworkName = "local"
+	c.Chain.ChainID = n.chainID.Uint64()
+	c.Chain.SlotTime = blockTime
+	c.L1.RPCURL = rpcURL
+	c.Signer.KeyFile = signerFile
+	c.Auction.Period = blockTime / 2
+	c.Auction.Allowlist = []string{accounts[0].Hex()}
+	c.Registry.Relays = []string{accounts[0].Hex()}
+	c.REST.Addr, c.JSONRPC.Addr = freeAddr(t), freeAddr(t)
+	c.GRPC.Addr, c.Metrics.Addr, c.Admin.Addr = "", "", ""
+	require.NoError(t, c.Validate())
+	n.config = c
+	data, err := yaml.Marshal(c)
+	require.NoError(t, err)
+	configFile := filepath.Join(dir, "node.yaml")
+	require.NoError(t, os.WriteFile(configFile, data, 0o600))
+
+	bin := os.Getenv("E2E_AUCTIONEER_BIN")
+	if bin == "" {
+		bin, err = localnet.BuildAuctioneer(ctx, dir)
+		require.NoError(t, err)
+	}
+	nodeCtx, stopNode := context.WithCancel(context.Background())
+	node, err := localnet.StartAuctioneer(nodeCtx, bin, configFile)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		stopNode()
+		require.NoError(t, node.Wait(), "the auctioneer shuts down cleanly")
+	})
+	require.NoError(t, localnet.WaitForListener(ctx, c.JSONRPC.Addr, stepTimeout))
+	require.NoError(t, localnet.WaitForListener(ctx, c.REST.Addr, stepTimeout))
+	return n
+}
+
+func TestFullFlow(t *testing.T) {
+	n := start(t)
+	relay := crypto.PubkeyToAddress(n.relay.PublicKey)
+
+	// The relay wins an auction, bidding in each until it does
+	won := winAuction(t, n)
+	require.Equal(t, relay, won.Bid.Address)
+	require.True(t, won.Bid.Verify())
+	head, err := n.eth.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.LessOrEqual(t, won.Bid.L1Block.Uint64(), head, "auctioned blocks are ones the chain produced")
+
+	// The won auction is recorded in history
+	auctions := listAuctions(t, n, relay)
+	require.NotEmpty(t, auctions)
+	var record *store.AuctionRecord
+	for i := range auctions {
+		if auctions[i].L1Block == won.Bid.L1Block.Uint64() {
+			record = &auctions[i]
+		}
+	}
+	require.NotNil(t, record, "won auction for block %d recorded", won.Bid.L1Block)
+	require.Equal(t, won.Bid.AmountWei, record.Winner.AmountWei)
+
+	// A blob tx, as committed to by the winning relay, is simulated, then included
+	tx := newBlobTx(t, n)
+	sim := simulation.NewSimulator(slog.Default(), n.eth)
+	ctx, cancel := context.WithTimeout(context.Background(), stepTimeout)
+	defer cancel()
+	require.NoError(t, sim.Simulate(ctx, []*types.Transaction{tx}))
+	require.NoError(t, n.eth.SendTransaction(ctx, tx))
+	receipt := waitForReceipt(ctx, t, n, tx.Hash())
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	require.Equal(t, uint64(params.BlobTxBlobGasPerBlob), receipt.BlobGasUsed)
+	block, err := n.eth.BlockByNumber(ctx, receipt.BlockNumber)
+	require.NoError(t, err)
+	included := block.Transaction(tx.Hash())
+	require.NotNil(t, included, "blob tx included in block %d", receipt.BlockNumber)
+	require.Equal(t, tx.BlobHashes(), included.BlobHashes())
+	require.NotNil(t, block.BlobGasUsed())
+	require.GreaterOrEqual(t, *block.BlobGasUsed(), receipt.BlobGasUsed)
+
+	// Every settlement recorded, and every one streamed to the winner, matches chain state
+	for _, settlement := range append(won.Settlements, settledAuctions(listAuctions(t, n, relay))...) {
+		settled, err := n.eth.TransactionReceipt(ctx, settlement.Tx)
+		require.NoError(t, err, "settlement tx %s of block %d on chain", settlement.Tx, settlement.L1Block)
+		require.Equal(t, types.ReceiptStatusSuccessful, settled.Status)
+		require.GreaterOrEqual(t, settled.BlockNumber.Uint64(), settlement.L1Block, "settled after the auctioned block")
+	}
+}
+
+// Winning bid, and the settlements streamed for this relay's wins until the winning auction closed
+type win struct {
+	Bid         *auction.SignedBid
+	Settlements []settlement
+}
+
+type settlement struct {
+	L1Block uint64
+	Tx      common.Hash
+}
+
+func winAuction(t *testing.T, n *network) win {
+	ctx, cancel := context.WithTimeout(context.Background(), stepTimeout)
+	defer cancel()
+	client, err := relayclient.NewBidderClient(ctx, slog.Default(), "ws://"+n.config.JSONRPC.Addr, n.relay, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	result := make(chan win, 1)
+	var settlements []settlement
+	go client.Run(ctx, relayclient.Handlers{
+		OnAuctionOpened: func(l1Block *big.Int) {
+			if _, err := client.Bid(ctx, big.NewInt(params.GWei), l1Block); err != nil {
+				t.Logf("bid for block %d rejected: %v", l1Block, err)
+			}
+		},
+		OnSettled: func(winner *auction.SignedBid, tx common.Hash) {
+			settlements = append(settlements, settlement{L1Block: winner.L1Block.Uint64(), Tx: tx})
+		},
+		OnWon: func(winner *auction.SignedBid) {
+			select {
+			case result <- win{Bid: winner, Settlements: settlements}:
+			default:
+			}
+		},
+	})
+	select {
+	case w := <-result:
+		return w
+	case <-ctx.Done():
+		t.Fatal("no auction won")
+		return win{}
+	}
+}
+
+func listAuctions(t *testing.T, n *network, winner common.Address) []store.AuctionRecord {
+	resp, err := http.Get(fmt.Sprintf("http://%s/v1/auctions?winner=%s", n.config.REST.Addr, winner.Hex()))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page rest.AuctionsPage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	return page.Auctions
+}
+
+// There's no settlement worker yet, so won auctions stay unsettled, and there are none
+func settledAuctions(auctions []store.AuctionRecord) []settlement {
+	var settled []settlement
+	for _, a := range auctions {
+		if a.SettlementTx != nil {
+			settled = append(settled, settlement{L1Block: a.L1Block, Tx: *a.SettlementTx})
+		}
+	}
+	return settled
+}
+
+// Blob tx from the funded sender carrying one blob, priced for the next block
+func newBlobTx(t *testing.T, n *network) *types.Transaction {
+	ctx, cancel := context.WithTimeout(context.Background(), stepTimeout)
+	defer cancel()
+	var blob kzg4844.Blob
+	copy(blob[:], "blob-preconfs e2e")
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	require.NoError(t, err)
+	sidecar := &types.BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{blob},
+		Commitments: []kzg4844.Commitment{commitment},
+		Proofs:      []kzg4844.Proof{proof},
+	}
+
+	from := crypto.PubkeyToAddress(n.sender.PublicKey)
+	nonce, err := n.eth.PendingNonceAt(ctx, from)
+	require.NoError(t, err)
+	head, err := n.eth.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.NotNil(t, head.ExcessBlobGas, "the chain runs Cancun")
+	tip := big.NewInt(params.GWei)
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	blobFeeCap := new(big.Int).Mul(eip4844.CalcBlobFee(*head.ExcessBlobGas), big.NewInt(2))
+	if blobFeeCap.Cmp(big.NewInt(params.GWei)) < 0 {
+		blobFeeCap = big.NewInt(params.GWei)
+	}
+	tx, err := types.SignNewTx(n.sender, types.NewCancunSigner(n.chainID), &types.BlobTx{
+		ChainID:    uint256.MustFromBig(n.chainID),
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(tip),
+		GasFeeCap:  uint256.MustFromBig(feeCap),
+		Gas:        params.TxGas,
+		To:         from,
+		Value:      uint256.NewInt(0),
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func waitForReceipt(ctx context.Context, t *testing.T, n *network, hash common.Hash) *types.Receipt {
+	for {
+		receipt, err := n.eth.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("tx %s not included: %v", hash, err)
+		case <-time.After(blockTime / 4):
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package admin
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"blob-preconfs/pkg/award"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/reputation"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// Violations returned when the request doesn't set a limit
+	defaultViolations = 50
+	// Unsettled auctions counted for the settlement queue, beyond which its depth is reported as truncated
+	maxSettlementScan = 10 * store.MaxPageLimit
+)
+
+// Satisfied by *listener.Listener
+type AuctionMonitor interface {
+	Diagnostics() listener.Diagnostics
+}
+
+// Satisfied by store.Store implementations
+type SettlementHistory interface {
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+}
+
+// Satisfied by *reputation.Tracker
+type RelayRecords interface {
+	All() []reputation.Record
+	Violations(limit int) []reputation.Violation
+}
+
+// Satisfied by *award.Notifier
+type AwardDeliveries interface {
+	Delivery(l1Block uint64) (award.Delivery, bool)
+	Deliveries() []award.Delivery
+}
+
+// Auction in progress, or the last one if none is, with its winner's award handshake
+type AuctionView struct {
+	Auction listener.Diagnostics `json:"auction"`
+	// Handshake of the last auction's award, nil without award delivery or if its winner wasn't notified
+	Award *award.Delivery `json:"award,omitempty"`
+}
+
+type ViolationsView struct {
+	Violations []reputation.Violation `json:"violations"`
+}
+
+// Won auctions awaiting settlement
+type SettlementQueueView struct {
+	// Winners handed off by the listener and not yet picked up for settlement
+	Pending int `json:"pending"`
+	// Won auctions recorded in history without a settlement tx, counted up to a limit if Truncated
+	Unsettled int  `json:"unsettled"`
+	Truncated bool `json:"truncated,omitempty"`
+	// Oldest unsettled auction, how long settlement lags behind
+	OldestBlock    uint64     `json:"oldestBlock,omitempty"`
+	OldestClosedAt *time.Time `json:"oldestClosedAt,omitempty"`
+}
+
+// Relay's record of honoring its wins and commitments, with its latest award handshake
+type RelayHealth struct {
+	reputation.Record
+	LastAward *award.Delivery `json:"lastAward,omitempty"`
+}
+
+type RelaysView struct {
+	Relays []RelayHealth `json:"relays"`
+}
+
+// Serves the live auction view under /admin/v1/console/auction, and the settlement queue's pending winners. Must be
+// called before Start.
+func (s *Server) SetAuctionMonitor(monitor AuctionMonitor) {
+	s.monitor = monitor
+}
+
+// Serves the settlement queue's depth under /admin/v1/console/settlements. Must be called before Start.
+func (s *Server) SetSettlementHistory(history SettlementHistory) {
+	s.settlements = history
+}
+
+// Serves recent violations and the relay health table under /admin/v1/console/violations and
+// /admin/v1/console/relays. Must be called before Start.
+func (s *Server) SetRelayRecords(records RelayRecords) {
+	s.relayRecords = records
+}
+
+// Includes award handshakes in the live auction view and relay health table. Must be called before Start.
+func (s *Server) SetAwardDeliveries(awards AwardDeliveries) {
+	s.awards = awards
+}
+
+func (s *Server) handleConsoleAuction(w http.ResponseWriter, r *http.Request) {
+	if !requireGet(w, r) {
+		return
+	}
+	if s.monitor == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("auction view not supported"))
+		return
+	}
+	view := AuctionView{Auction: s.monitor.Diagnostics()}
+	if s.awards != nil && view.Auction.LastAuctionBlock != 0 {
+		if delivery, ok := s.awards.Delivery(view.Auction.LastAuctionBlock); ok {
+			view.Award = &delivery
+		}
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+// GET /admin/v1/console/violations?limit= returns the most recent violations, newest first
+func (s *Server) handleConsoleViolations(w http.ResponseWriter, r *http.Request) {
+	if !requireGet(w, r) {
+		return
+	}
+	if s.relayRecords == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("violations not supported"))
+		return
+	}
+	limit := defaultViolations
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit"))
+			return
+		}
+		limit = n
+	}
+	writeJSON(w, http.StatusOK, ViolationsView{Violations: s.relayRecords.Violations(limit)})
+}
+
+func (s *Server) handleConsoleSettlements(w http.ResponseWriter, r *http.Request) {
+	if !requireGet(w, r) {
+		return
+	}
+	if s.settlements == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("settlement queue not supported"))
+		return
+	}
+	var view SettlementQueueView
+	if s.monitor != nil {
+		view.Pending = s.monitor.Diagnostics().AuctionWonQueue.Len
+	}
+	page := store.Page{Limit: store.MaxPageLimit}
+	for {
+		unsettled, next, err := s.settlements.ListAuctions(store.AuctionFilter{Unsettled: true}, page)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to list unsettled auctions: %w", err))
+			return
+		}
+		if view.Unsettled == 0 && len(unsettled) > 0 {
+			oldest := unsettled[0]
+			view.OldestBlock = oldest.L1Block
+			view.OldestClosedAt = &oldest.ClosedAt
+		}
+		view.Unsettled += len(unsettled)
+		if next == "" {
+			break
+		}
+		if view.Unsettled >= maxSettlementScan {
+			view.Truncated = true
+			break
+		}
+		page.Cursor = next
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+// Relays that won or violated since the auctioneer started, by address
+func (s *Server) handleConsoleRelays(w http.ResponseWriter, r *http.Request) {
+	if !requireGet(w, r) {
+		return
+	}
+	if s.relayRecords == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("relay health not supported"))
+		return
+	}
+	relays := make(map[common.Address]*RelayHealth)
+	for _, record := range s.relayRecords.All() {
+		relays[record.Relay] = &RelayHealth{Record: record}
+	}
+	if s.awards != nil {
+		// Deliveries are by L1 block, so the last one seen is each relay's latest
+		for _, delivery := range s.awards.Deliveries() {
+			delivery := delivery
+			relay, ok := relays[delivery.Relay]
+			if !ok {
+				relay = &RelayHealth{Record: reputation.Record{Relay: delivery.Relay}}
+				relays[delivery.Relay] = relay
+			}
+			relay.LastAward = &delivery
+		}
+	}
+	view := RelaysView{Relays: make([]RelayHealth, 0, len(relays))}
+	for _, relay := range relays {
+		view.Relays = append(view.Relays, *relay)
+	}
+	sort.Slice(view.Relays, func(i, j int) bool { return bytes.Compare(view.Relays[i].Relay[:], view.Relays[j].Relay[:]) < 0 })
+	writeJSON(w, http.StatusOK, view)
+}
+
+func requireGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return false
+	}
+	return true
+}
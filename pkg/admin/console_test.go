@@ -0,0 +1,122 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/admin"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/award"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/reputation"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMonitor struct{ diagnostics listener.Diagnostics }
+
+func (m mockMonitor) Diagnostics() listener.Diagnostics { return m.diagnostics }
+
+type mockAwards []award.Delivery
+
+func (m mockAwards) Delivery(l1Block uint64) (award.Delivery, bool) {
+	for _, d := range m {
+		if d.L1Block == l1Block {
+			return d, true
+		}
+	}
+	return award.Delivery{}, false
+}
+
+func (m mockAwards) Deliveries() []award.Delivery { return m }
+
+func TestConsole(t *testing.T) {
+	relay1, relay2, relay3 := common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	server, err := admin.NewServer(slog.Default(), "127.0.0.1:0", controller, nil, nil, nil, nil, token, nil)
+	require.NoError(t, err)
+
+	monitor := mockMonitor{listener.Diagnostics{LastAuctionBlock: 101, AuctionWonQueue: listener.ChannelDepth{Len: 1, Cap: 16}}}
+	history := store.NewMemoryStore()
+	closedAt := time.Now().Add(-time.Minute).UTC()
+	for block := uint64(99); block <= 101; block++ {
+		winner := &auction.SignedBid{L1Block: new(big.Int).SetUint64(block), AmountWei: big.NewInt(1), Address: relay1}
+		require.NoError(t, history.SaveAuctionResult(block, winner, closedAt.Add(time.Duration(block)*time.Second)))
+	}
+	require.NoError(t, history.SaveAuctionResult(102, nil, time.Now()))
+	require.NoError(t, history.SaveSettlement(99, common.HexToHash("0x99"), time.Now()))
+	records := reputation.NewTracker()
+	records.RecordAccepted(relay1)
+	records.RecordDefault(relay2, 100, "declined")
+	records.RecordDefault(relay2, 101, "timed out")
+	awards := mockAwards{
+		{L1Block: 100, Relay: relay2, Status: award.StatusDefaulted},
+		{L1Block: 101, Relay: relay3, Status: award.StatusAccepted},
+	}
+	server.SetAuctionMonitor(monitor)
+	server.SetSettlementHistory(history)
+	server.SetRelayRecords(records)
+	server.SetAwardDeliveries(awards)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	get := func(path string, view any) int {
+		req, _ := http.NewRequest(http.MethodGet, "http://"+server.Addr().String()+path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(view))
+		}
+		return resp.StatusCode
+	}
+
+	var auctionView admin.AuctionView
+	require.Equal(t, http.StatusOK, get("/admin/v1/console/auction", &auctionView))
+	require.Equal(t, uint64(101), auctionView.Auction.LastAuctionBlock)
+	require.Equal(t, relay3, auctionView.Award.Relay, "the last auction's award")
+
+	var violations admin.ViolationsView
+	require.Equal(t, http.StatusOK, get("/admin/v1/console/violations?limit=1", &violations))
+	require.Len(t, violations.Violations, 1)
+	require.Equal(t, "timed out", violations.Violations[0].Reason)
+	require.Equal(t, http.StatusBadRequest, get("/admin/v1/console/violations?limit=-1", nil))
+
+	var settlements admin.SettlementQueueView
+	require.Equal(t, http.StatusOK, get("/admin/v1/console/settlements", &settlements))
+	require.Equal(t, 1, settlements.Pending)
+	require.Equal(t, 2, settlements.Unsettled, "settled auctions and auctions without a winner aren't queued")
+	require.Equal(t, uint64(100), settlements.OldestBlock)
+	require.True(t, closedAt.Add(100*time.Second).Equal(*settlements.OldestClosedAt))
+
+	var relays admin.RelaysView
+	require.Equal(t, http.StatusOK, get("/admin/v1/console/relays", &relays))
+	require.Len(t, relays.Relays, 3)
+	require.Equal(t, relay1, relays.Relays[0].Relay)
+	require.Equal(t, 1, relays.Relays[0].Accepted)
+	require.Nil(t, relays.Relays[0].LastAward)
+	require.Equal(t, 2, relays.Relays[1].Defaults)
+	require.Equal(t, award.StatusDefaulted, relays.Relays[1].LastAward.Status)
+	require.Equal(t, relay3, relays.Relays[2].Relay, "relays with an award handshake are included")
+}
+
+func TestConsoleWithoutSources(t *testing.T) {
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	do := startServer(t, controller, nil, nil)
+	for _, path := range []string{
+		"/admin/v1/console/auction",
+		"/admin/v1/console/violations",
+		"/admin/v1/console/settlements",
+		"/admin/v1/console/relays",
+	} {
+		require.Equal(t, http.StatusNotImplemented, do(http.MethodGet, path).StatusCode, path)
+		require.Equal(t, http.StatusMethodNotAllowed, do(http.MethodPost, path).StatusCode, path)
+	}
+}
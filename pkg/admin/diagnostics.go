@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+type RuntimeStats struct {
+	Goroutines     int    `json:"goroutines"`
+	GOMAXPROCS     int    `json:"gomaxprocs"`
+	HeapAllocBytes uint64 `json:"heapAllocBytes"`
+	HeapObjects    uint64 `json:"heapObjects"`
+	NumGC          uint32 `json:"numGC"`
+	// Most recent stop-the-world GC pause, which stalls bid handling
+	LastGCPause time.Duration `json:"lastGCPauseNs"`
+}
+
+type DiagnosticsResponse struct {
+	Time    time.Time     `json:"time"`
+	Uptime  time.Duration `json:"uptimeNs"`
+	Runtime RuntimeStats  `json:"runtime"`
+	// Component name to its state, see AddDiagnostics
+	Components map[string]any `json:"components"`
+}
+
+// Includes the component's state in the diagnostics dump, e.g. listener.Diagnostics. Must be called before Start.
+func (s *Server) AddDiagnostics(name string, source func() any) {
+	s.diagnostics[name] = source
+}
+
+// Serves net/http/pprof under /debug/pprof/, whose index expects that prefix
+func registerPprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+func (s *Server) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	resp := DiagnosticsResponse{
+		Time:   time.Now(),
+		Uptime: time.Since(s.startedAt),
+		Runtime: RuntimeStats{
+			Goroutines:     runtime.NumGoroutine(),
+			GOMAXPROCS:     runtime.GOMAXPROCS(0),
+			HeapAllocBytes: mem.HeapAlloc,
+			HeapObjects:    mem.HeapObjects,
+			NumGC:          mem.NumGC,
+			LastGCPause:    time.Duration(mem.PauseNs[(mem.NumGC+255)%256]),
+		},
+		Components: make(map[string]any, len(s.diagnostics)),
+	}
+	for name, source := range s.diagnostics {
+		resp.Components[name] = source()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
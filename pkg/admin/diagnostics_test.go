@@ -0,0 +1,55 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"blob-preconfs/pkg/admin"
+	"blob-preconfs/pkg/auction"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	server, err := admin.NewServer(slog.Default(), "127.0.0.1:0", controller, nil, nil, nil, nil, token, nil)
+	require.NoError(t, err)
+	server.AddDiagnostics("listener", func() any { return map[string]bool{"auctionInProgress": true} })
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	get := func(path string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, "http://"+server.Addr().String()+path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := get("/admin/v1/diagnostics")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var diagnostics struct {
+		admin.DiagnosticsResponse
+		Components map[string]map[string]bool `json:"components"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&diagnostics))
+	require.Positive(t, diagnostics.Runtime.Goroutines)
+	require.Positive(t, diagnostics.Runtime.HeapAllocBytes)
+	require.True(t, diagnostics.Components["listener"]["auctionInProgress"])
+
+	resp = get("/debug/pprof/goroutine?debug=1")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "goroutine profile")
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+server.Addr().String()+"/debug/pprof/", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode, "pprof requires the token")
+}
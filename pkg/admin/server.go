@@ -0,0 +1,444 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/export"
+	"blob-preconfs/pkg/funding"
+	"blob-preconfs/pkg/keys"
+	"blob-preconfs/pkg/store"
+	"blob-preconfs/pkg/version"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrNoToken = errors.New("admin api requires a token")
+
+// Satisfied by *listener.Listener
+type AuctionController interface {
+	Pause()
+	Resume()
+	Paused() bool
+	CancelAuction() (l1Block uint64, cancelled bool)
+	AccessList() *auction.AccessList
+}
+
+// Re-reads configuration from its source and applies it to the running process
+type ConfigReloader interface {
+	Reload(ctx context.Context) error
+}
+
+// Refreshes cached relay registrations from the settlement layer
+type RegistryResyncer interface {
+	Resync(ctx context.Context) error
+}
+
+// Satisfied by *export.Exporter
+type Exporter interface {
+	Export(w io.Writer, kind export.Kind, format export.Format, blocks store.BlockRange) (int, error)
+}
+
+// Satisfied by *funding.Watcher
+type FundingWatcher interface {
+	Status() funding.Status
+}
+
+// Satisfied by store.Store implementations
+type Snapshotter interface {
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
+}
+
+type Server struct {
+	logger     *slog.Logger
+	controller AuctionController
+	reloader   ConfigReloader
+	resyncer   RegistryResyncer
+	exporter   Exporter
+	snapshots  Snapshotter
+	signers    *keys.Signers
+	funding    FundingWatcher
+	// Sources of the console's aggregated views, see console.go
+	monitor      AuctionMonitor
+	settlements  SettlementHistory
+	relayRecords RelayRecords
+	awards       AwardDeliveries
+	token        []byte
+	// Component diagnostics, by name
+	diagnostics map[string]func() any
+	startedAt   time.Time
+	httpServer  *http.Server
+	listener    net.Listener
+}
+
+type StatusResponse struct {
+	Paused    bool             `json:"paused"`
+	Allowlist []common.Address `json:"allowlist"`
+	Denylist  []common.Address `json:"denylist"`
+	Version   version.Info     `json:"version"`
+	// Keys results are signed with, the previous one during a rotation's grace period
+	Signers *keys.Signers `json:"signers,omitempty"`
+	// Settlement key's balance and projected runway, as of the last check
+	Funding *funding.Status `json:"funding,omitempty"`
+}
+
+type CancelResponse struct {
+	L1Block uint64 `json:"l1Block"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Requests must carry "Authorization: Bearer <token>". reloader, resyncer, exporter and snapshots may be nil,
+// in which case their endpoints respond 501. Served over TLS if tlsConfig is non-nil, see tlsconfig.
+func NewServer(
+	logger *slog.Logger,
+	addr string,
+	controller AuctionController,
+	reloader ConfigReloader,
+	resyncer RegistryResyncer,
+	exporter Exporter,
+	snapshots Snapshotter,
+	token string,
+	tlsConfig *tls.Config,
+) (*Server, error) {
+	if token == "" {
+		return nil, ErrNoToken
+	}
+	s := &Server{
+		logger:     logger,
+		controller: controller,
+		reloader:   reloader,
+		resyncer:   resyncer,
+		exporter:   exporter,
+		snapshots:  snapshots,
+		token:      []byte(token),
+
+		diagnostics: make(map[string]func() any),
+		startedAt:   time.Now(),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/v1/status", s.handleStatus)
+	mux.HandleFunc("/admin/v1/auctions/pause", s.handlePause)
+	mux.HandleFunc("/admin/v1/auctions/resume", s.handleResume)
+	mux.HandleFunc("/admin/v1/auctions/cancel", s.handleCancel)
+	mux.HandleFunc("/admin/v1/config/reload", s.handleReload)
+	mux.HandleFunc("/admin/v1/registry/resync", s.handleResync)
+	mux.HandleFunc("/admin/v1/export/", s.handleExport)
+	mux.HandleFunc("/admin/v1/store/snapshot", s.handleSnapshot)
+	mux.HandleFunc("/admin/v1/store/restore", s.handleRestore)
+	mux.HandleFunc("/admin/v1/allowlist/", s.handleAllowlist)
+	mux.HandleFunc("/admin/v1/denylist/", s.handleDenylist)
+	mux.HandleFunc("/admin/v1/diagnostics", s.handleDiagnostics)
+	mux.HandleFunc("/admin/v1/console/auction", s.handleConsoleAuction)
+	mux.HandleFunc("/admin/v1/console/violations", s.handleConsoleViolations)
+	mux.HandleFunc("/admin/v1/console/settlements", s.handleConsoleSettlements)
+	mux.HandleFunc("/admin/v1/console/relays", s.handleConsoleRelays)
+	registerPprof(mux)
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.authenticate(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+		TLSConfig:         tlsConfig,
+	}
+	return s, nil
+}
+
+// Announces the auctioneer's signing keys in the status. Must be called before Start.
+func (s *Server) SetSigners(signers keys.Signers) {
+	s.signers = &signers
+}
+
+// Reports the settlement key's funding in the status. Must be called before Start.
+func (s *Server) SetFunding(funding FundingWatcher) {
+	s.funding = funding
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	if s.httpServer.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.httpServer.TLSConfig)
+	}
+	s.listener = listener
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("admin server failed", "error", err)
+		}
+	}()
+	s.logger.Info("admin server started", "addr", listener.Addr(), "tls", s.httpServer.TLSConfig != nil)
+	return nil
+}
+
+// Address the server is listening on, useful when started on port 0
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stops accepting new requests and waits for in-flight ones until ctx is done
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.logger.Info("admin server stopped")
+	return err
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), s.token) != 1 {
+			s.logger.Warn("unauthorized admin request", "path", r.URL.Path, "remoteAddr", r.RemoteAddr)
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	accessList := s.controller.AccessList()
+	status := StatusResponse{
+		Paused:    s.controller.Paused(),
+		Allowlist: accessList.Allowed(),
+		Denylist:  accessList.Denied(),
+		Version:   version.Get(),
+		Signers:   s.signers,
+	}
+	if s.funding != nil {
+		funding := s.funding.Status()
+		status.Funding = &funding
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	s.controller.Pause()
+	s.logger.Warn("auctions paused by admin", "remoteAddr", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	s.controller.Resume()
+	s.logger.Warn("auctions resumed by admin", "remoteAddr", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	l1Block, cancelled := s.controller.CancelAuction()
+	if !cancelled {
+		writeError(w, http.StatusConflict, auction.ErrNoActiveAuction)
+		return
+	}
+	s.logger.Warn("auction cancelled by admin", "l1Block", l1Block, "remoteAddr", r.RemoteAddr)
+	writeJSON(w, http.StatusOK, CancelResponse{L1Block: l1Block})
+}
+
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	if s.reloader == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("config reload not supported"))
+		return
+	}
+	if err := s.reloader.Reload(r.Context()); err != nil {
+		s.logger.Error("config reload failed", "error", err)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.logger.Warn("config reloaded by admin", "remoteAddr", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleResync(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	if s.resyncer == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("registry resync not supported"))
+		return
+	}
+	if err := s.resyncer.Resync(r.Context()); err != nil {
+		s.logger.Error("registry resync failed", "error", err)
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.logger.Warn("registry resynced by admin", "remoteAddr", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+var contentTypes = map[export.Format]string{
+	export.FormatCSV:     "text/csv",
+	export.FormatParquet: "application/vnd.apache.parquet",
+}
+
+// GET /admin/v1/export/{kind}?format=csv|parquet&fromBlock=&toBlock= streams history as a file download
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	if s.exporter == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("export not supported"))
+		return
+	}
+	kind, err := export.ParseKind(strings.TrimPrefix(r.URL.Path, "/admin/v1/export/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	query := r.URL.Query()
+	formatName := query.Get("format")
+	if formatName == "" {
+		formatName = string(export.FormatCSV)
+	}
+	format, err := export.ParseFormat(formatName)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var blocks store.BlockRange
+	for key, block := range map[string]*uint64{"fromBlock": &blocks.From, "toBlock": &blocks.To} {
+		if value := query.Get(key); value != "" {
+			if *block, err = strconv.ParseUint(value, 10, 64); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s", key))
+				return
+			}
+		}
+	}
+	if blocks.To != 0 && blocks.To < blocks.From {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("toBlock before fromBlock"))
+		return
+	}
+	w.Header().Set("Content-Type", contentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%d-%d.%s"`, kind, blocks.From, blocks.To, format))
+	// Headers are sent with the first row, so a failure midway can only be logged
+	rows, err := s.exporter.Export(w, kind, format, blocks)
+	if err != nil {
+		s.logger.Error("export failed", "kind", kind, "format", format, "rows", rows, "error", err)
+		return
+	}
+	s.logger.Info("history exported by admin", "kind", kind, "format", format,
+		"fromBlock", blocks.From, "toBlock", blocks.To, "rows", rows, "remoteAddr", r.RemoteAddr)
+}
+
+// Streams a point-in-time backup of history as a file download, auctions keep running meanwhile
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	if s.snapshots == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("snapshots not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="snapshot-%d.jsonl.gz"`, time.Now().Unix()))
+	// Headers are sent with the first record, so a failure midway can only be logged. Clients detect the
+	// truncated snapshot, which fails to restore.
+	if err := s.snapshots.Snapshot(w); err != nil {
+		s.logger.Error("snapshot failed", "error", err)
+		return
+	}
+	s.logger.Info("snapshot taken by admin", "remoteAddr", r.RemoteAddr)
+}
+
+// Replaces history with the snapshot in the request body
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if !requirePost(w, r) {
+		return
+	}
+	if s.snapshots == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("snapshots not supported"))
+		return
+	}
+	if err := s.snapshots.Restore(r.Body); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, store.ErrInvalidSnapshot) {
+			status = http.StatusBadRequest
+		}
+		s.logger.Error("restore failed", "error", err)
+		writeError(w, status, err)
+		return
+	}
+	s.logger.Warn("history restored from snapshot by admin", "remoteAddr", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleAllowlist(w http.ResponseWriter, r *http.Request) {
+	accessList := s.controller.AccessList()
+	s.handleList(w, r, "/admin/v1/allowlist/", accessList.Allow, accessList.RemoveAllowed)
+}
+
+func (s *Server) handleDenylist(w http.ResponseWriter, r *http.Request) {
+	accessList := s.controller.AccessList()
+	s.handleList(w, r, "/admin/v1/denylist/", accessList.Deny, accessList.RemoveDenied)
+}
+
+// PUT adds the address in the path to the list, DELETE removes it
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request, prefix string, add, remove func(common.Address)) {
+	addressHex := strings.TrimPrefix(r.URL.Path, prefix)
+	if !common.IsHexAddress(addressHex) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid address"))
+		return
+	}
+	address := common.HexToAddress(addressHex)
+	switch r.Method {
+	case http.MethodPut:
+		add(address)
+	case http.MethodDelete:
+		remove(address)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	s.logger.Warn("access list updated by admin", "list", strings.Trim(strings.TrimPrefix(prefix, "/admin/v1/"), "/"),
+		"method", r.Method, "address", address, "remoteAddr", r.RemoteAddr)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func requirePost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,269 @@
+package admin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/admin"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/export"
+	"blob-preconfs/pkg/funding"
+	"blob-preconfs/pkg/keys"
+	"blob-preconfs/pkg/store"
+	"blob-preconfs/pkg/version"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const token = "secret"
+
+type mockController struct {
+	paused     bool
+	inProgress bool
+	accessList *auction.AccessList
+}
+
+func (m *mockController) Pause()       { m.paused = true }
+func (m *mockController) Resume()      { m.paused = false }
+func (m *mockController) Paused() bool { return m.paused }
+
+func (m *mockController) CancelAuction() (uint64, bool) {
+	if !m.inProgress {
+		return 0, false
+	}
+	m.inProgress = false
+	return 100, true
+}
+
+func (m *mockController) AccessList() *auction.AccessList { return m.accessList }
+
+type mockReloader struct {
+	reloads int
+	err     error
+}
+
+func (m *mockReloader) Reload(ctx context.Context) error {
+	m.reloads++
+	return m.err
+}
+
+type mockResyncer struct{ resyncs int }
+
+func (m *mockResyncer) Resync(ctx context.Context) error {
+	m.resyncs++
+	return nil
+}
+
+func startServer(t *testing.T, controller admin.AuctionController, reloader admin.ConfigReloader, resyncer admin.RegistryResyncer) func(method, path string) *http.Response {
+	server, err := admin.NewServer(slog.Default(), "127.0.0.1:0", controller, reloader, resyncer, nil, nil, token, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	return func(method, path string) *http.Response {
+		req, err := http.NewRequest(method, "http://"+server.Addr().String()+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+}
+
+func TestRequiresToken(t *testing.T) {
+	_, err := admin.NewServer(slog.Default(), "127.0.0.1:0", &mockController{}, nil, nil, nil, nil, "", nil)
+	require.ErrorIs(t, err, admin.ErrNoToken)
+
+	server, err := admin.NewServer(slog.Default(), "127.0.0.1:0", &mockController{}, nil, nil, nil, nil, token, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	for _, header := range []string{"", "Bearer wrong", token} {
+		req, _ := http.NewRequest(http.MethodPost, "http://"+server.Addr().String()+"/admin/v1/auctions/pause", nil)
+		req.Header.Set("Authorization", header)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode, header)
+	}
+}
+
+func TestAuctionControl(t *testing.T) {
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	do := startServer(t, controller, nil, nil)
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/admin/v1/auctions/pause").StatusCode)
+	require.True(t, controller.paused)
+	require.Equal(t, http.StatusMethodNotAllowed, do(http.MethodGet, "/admin/v1/auctions/resume").StatusCode)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/admin/v1/auctions/resume").StatusCode)
+	require.False(t, controller.paused)
+
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/admin/v1/auctions/cancel").StatusCode)
+	controller.inProgress = true
+	resp := do(http.MethodPost, "/admin/v1/auctions/cancel")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var cancelled admin.CancelResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&cancelled))
+	require.Equal(t, uint64(100), cancelled.L1Block)
+}
+
+func TestAccessLists(t *testing.T) {
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	do := startServer(t, controller, nil, nil)
+	a, b := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+
+	require.Equal(t, http.StatusNoContent, do(http.MethodPut, "/admin/v1/allowlist/"+a.Hex()).StatusCode)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPut, "/admin/v1/allowlist/"+b.Hex()).StatusCode)
+	require.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/admin/v1/allowlist/"+a.Hex()).StatusCode)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPut, "/admin/v1/denylist/"+a.Hex()).StatusCode)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/admin/v1/denylist/0x12").StatusCode)
+	require.Equal(t, http.StatusMethodNotAllowed, do(http.MethodPost, "/admin/v1/denylist/"+a.Hex()).StatusCode)
+
+	resp := do(http.MethodGet, "/admin/v1/status")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var status admin.StatusResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	require.Equal(t, admin.StatusResponse{Allowlist: []common.Address{b}, Denylist: []common.Address{a}, Version: version.Get()}, status)
+}
+
+type mockFunding funding.Status
+
+func (m mockFunding) Status() funding.Status {
+	return funding.Status(m)
+}
+
+func TestStatusSignersAndFunding(t *testing.T) {
+	server, err := admin.NewServer(slog.Default(), "127.0.0.1:0", &mockController{accessList: auction.NewAccessList(nil, nil)}, nil, nil, nil, nil, token, nil)
+	require.NoError(t, err)
+	previous, until := common.Address{0x01}, time.Now().Add(time.Hour).UTC()
+	server.SetSigners(keys.Signers{Active: common.Address{0x02}, Previous: &previous, PreviousUntil: &until})
+	server.SetFunding(mockFunding{Address: common.Address{0x02}, BalanceWei: big.NewInt(1000), Runway: time.Hour, Low: true})
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+server.Addr().String()+"/admin/v1/status", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var status admin.StatusResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	require.Equal(t, common.Address{0x02}, status.Signers.Active)
+	require.Equal(t, previous, *status.Signers.Previous)
+	require.True(t, until.Equal(*status.Signers.PreviousUntil))
+	require.Equal(t, big.NewInt(1000), status.Funding.BalanceWei)
+	require.Equal(t, time.Hour, status.Funding.Runway)
+	require.True(t, status.Funding.Low)
+}
+
+func TestReloadAndResync(t *testing.T) {
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	do := startServer(t, controller, nil, nil)
+	require.Equal(t, http.StatusNotImplemented, do(http.MethodPost, "/admin/v1/config/reload").StatusCode)
+	require.Equal(t, http.StatusNotImplemented, do(http.MethodPost, "/admin/v1/registry/resync").StatusCode)
+
+	reloader, resyncer := &mockReloader{}, &mockResyncer{}
+	do = startServer(t, controller, reloader, resyncer)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/admin/v1/config/reload").StatusCode)
+	require.Equal(t, http.StatusNoContent, do(http.MethodPost, "/admin/v1/registry/resync").StatusCode)
+	reloader.err = errors.New("invalid config")
+	require.Equal(t, http.StatusInternalServerError, do(http.MethodPost, "/admin/v1/config/reload").StatusCode)
+	require.Equal(t, 2, reloader.reloads)
+	require.Equal(t, 1, resyncer.resyncs)
+}
+
+type mockExporter struct {
+	blocks store.BlockRange
+}
+
+func (m *mockExporter) Export(w io.Writer, kind export.Kind, format export.Format, blocks store.BlockRange) (int, error) {
+	m.blocks = blocks
+	_, err := io.WriteString(w, "l1_block\n100\n")
+	return 1, err
+}
+
+func TestExport(t *testing.T) {
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	do := startServer(t, controller, nil, nil)
+	require.Equal(t, http.StatusNotImplemented, do(http.MethodGet, "/admin/v1/export/bids").StatusCode)
+
+	exporter := &mockExporter{}
+	server, err := admin.NewServer(slog.Default(), "127.0.0.1:0", controller, nil, nil, exporter, nil, token, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	get := func(path string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, "http://"+server.Addr().String()+path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := get("/admin/v1/export/bids?fromBlock=100&toBlock=200")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	require.Equal(t, `attachment; filename="bids-100-200.csv"`, resp.Header.Get("Content-Disposition"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "l1_block\n100\n", string(body))
+	require.Equal(t, store.BlockRange{From: 100, To: 200}, exporter.blocks)
+
+	require.Equal(t, "application/vnd.apache.parquet", get("/admin/v1/export/auctions?format=parquet").Header.Get("Content-Type"))
+	for _, path := range []string{
+		"/admin/v1/export/relays",
+		"/admin/v1/export/bids?format=xlsx",
+		"/admin/v1/export/bids?fromBlock=abc",
+		"/admin/v1/export/bids?fromBlock=200&toBlock=100",
+	} {
+		require.Equal(t, http.StatusBadRequest, get(path).StatusCode, path)
+	}
+}
+
+func TestSnapshotAndRestore(t *testing.T) {
+	controller := &mockController{accessList: auction.NewAccessList(nil, nil)}
+	do := startServer(t, controller, nil, nil)
+	require.Equal(t, http.StatusNotImplemented, do(http.MethodGet, "/admin/v1/store/snapshot").StatusCode)
+
+	history := store.NewMemoryStore()
+	require.NoError(t, history.SaveAuctionResult(100, nil, time.Now()))
+	server, err := admin.NewServer(slog.Default(), "127.0.0.1:0", controller, nil, nil, nil, history, token, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	send := func(method, path string, body io.Reader) *http.Response {
+		req, _ := http.NewRequest(method, "http://"+server.Addr().String()+path, body)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := send(http.MethodGet, "/admin/v1/store/snapshot", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/gzip", resp.Header.Get("Content-Type"))
+	snapshot, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.NoError(t, history.SaveAuctionResult(101, nil, time.Now()))
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/admin/v1/store/restore", strings.NewReader("invalid")).StatusCode)
+	require.Equal(t, http.StatusMethodNotAllowed, send(http.MethodGet, "/admin/v1/store/restore", nil).StatusCode)
+	require.Equal(t, http.StatusNoContent, send(http.MethodPost, "/admin/v1/store/restore", bytes.NewReader(snapshot)).StatusCode)
+	auctions, _, err := history.ListAuctions(store.AuctionFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, auctions, 1)
+	require.Equal(t, uint64(100), auctions[0].L1Block)
+}
@@ -0,0 +1,366 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	queueSize       = 64
+	deliverAttempts = 3
+	// Per delivery attempt, and for alerts sent right before the process exits
+	sendTimeout = 10 * time.Second
+)
+
+var ErrInvalidConfig = errors.New("invalid alerting config")
+
+type Kind string
+
+const (
+	KindAuctionTimeout   Kind = "auctionTimeout"
+	KindSettlementFailed Kind = "settlementFailed"
+	KindRPCErrors        Kind = "rpcErrors"
+	KindViolation        Kind = "violation"
+	KindClockDrift       Kind = "clockDrift"
+	KindStateDivergence  Kind = "stateDivergence"
+	KindFundsLow         Kind = "fundsLow"
+)
+
+// PagerDuty severities, which Slack payloads show as is
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+)
+
+type Alert struct {
+	Kind     Kind     `json:"kind"`
+	Severity Severity `json:"severity"`
+	Summary  string   `json:"summary"`
+	// What the alert is about within its kind, e.g. the L1 block or RPC method. Alerts of the same kind and
+	// subject are deduplicated.
+	Subject string            `json:"subject"`
+	Details map[string]string `json:"details,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+func (a Alert) key() string {
+	return string(a.Kind) + "/" + a.Subject
+}
+
+type Config struct {
+	Webhooks []WebhookConfig
+	// Alerts with the same kind and subject within this interval are only sent once, 0 sends every alert
+	DedupInterval time.Duration
+	// Alert once an RPC method fails RPCErrorThreshold times within RPCErrorWindow, 0 disables RPC alerts
+	RPCErrorThreshold int
+	RPCErrorWindow    time.Duration
+	// Identifies this auctioneer in alerts, defaults to the hostname
+	Source string
+}
+
+// Sends alerts on critical auction failures to webhooks. Satisfies listener.Alerter and commitment.Observer,
+// and alerts on settlement failures from the listener's event feed with Watch.
+type Notifier struct {
+	logger   *slog.Logger
+	config   Config
+	webhooks []*webhook
+	alerts   chan Alert
+	done     chan struct{}
+
+	mu        sync.Mutex // Protects access to fields below
+	closed    bool
+	lastSent  map[string]time.Time
+	rpcErrors map[string][]time.Time
+}
+
+func NewNotifier(logger *slog.Logger, config Config) (*Notifier, error) {
+	if len(config.Webhooks) == 0 {
+		return nil, fmt.Errorf("%w: no webhooks", ErrInvalidConfig)
+	}
+	if config.RPCErrorThreshold > 0 && config.RPCErrorWindow <= 0 {
+		return nil, fmt.Errorf("%w: rpc error alerts require a window", ErrInvalidConfig)
+	}
+	if config.Source == "" {
+		config.Source, _ = os.Hostname()
+	}
+	n := &Notifier{
+		logger:    logger,
+		config:    config,
+		alerts:    make(chan Alert, queueSize),
+		done:      make(chan struct{}),
+		lastSent:  make(map[string]time.Time),
+		rpcErrors: make(map[string][]time.Time),
+	}
+	for _, webhookConfig := range config.Webhooks {
+		w, err := newWebhook(webhookConfig, config.Source)
+		if err != nil {
+			return nil, err
+		}
+		n.webhooks = append(n.webhooks, w)
+	}
+	go n.deliverQueued()
+	return n, nil
+}
+
+// Queues the alert without blocking, dropping it if the queue is full or it's a duplicate
+func (n *Notifier) Notify(alert Alert) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed || !n.claim(&alert) {
+		return
+	}
+	select {
+	case n.alerts <- alert:
+	default:
+		n.logger.Error("dropping alert, queue full", "kind", alert.Kind, "summary", alert.Summary)
+	}
+}
+
+// Delivers the alert to every webhook before returning, unless it's a duplicate. For alerts raised
+// right before the process exits.
+func (n *Notifier) Send(ctx context.Context, alert Alert) error {
+	n.mu.Lock()
+	claimed := n.claim(&alert)
+	n.mu.Unlock()
+	if !claimed {
+		return nil
+	}
+	return n.deliver(ctx, alert)
+}
+
+// Must be called with mu held. Fills in the time and records the alert as sent, returning false for duplicates.
+func (n *Notifier) claim(alert *Alert) bool {
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	if n.config.DedupInterval > 0 {
+		if last, ok := n.lastSent[alert.key()]; ok && alert.Time.Sub(last) < n.config.DedupInterval {
+			return false
+		}
+		n.lastSent[alert.key()] = alert.Time
+	}
+	return true
+}
+
+func (n *Notifier) deliverQueued() {
+	defer close(n.done)
+	for alert := range n.alerts {
+		n.deliver(context.Background(), alert)
+	}
+}
+
+// Retries each webhook with backoff, failures are logged as there's nowhere else to report them
+func (n *Notifier) deliver(ctx context.Context, alert Alert) error {
+	var errs []error
+	for _, w := range n.webhooks {
+		var err error
+		for attempt := 0; attempt < deliverAttempts; attempt++ {
+			if attempt > 0 && !sleep(ctx, time.Duration(attempt)*time.Second) {
+				break
+			}
+			attemptCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+			err = w.post(attemptCtx, alert)
+			cancel()
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			n.logger.Error("failed to deliver alert", "kind", alert.Kind, "webhook", w.config.Format, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// To satisfy listener.Alerter. Sent synchronously, as the listener exits after an auction timeout.
+func (n *Notifier) AuctionTimedOut(l1Block uint64, after time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	n.Send(ctx, Alert{
+		Kind:     KindAuctionTimeout,
+		Severity: SeverityCritical,
+		Summary:  fmt.Sprintf("Auction for L1 block %d did not end within %s", l1Block, after),
+		Subject:  strconv.FormatUint(l1Block, 10),
+		Details:  map[string]string{"l1Block": strconv.FormatUint(l1Block, 10)},
+	})
+}
+
+// To satisfy listener.Alerter, and for other RPC clients. Alerts once a method fails RPCErrorThreshold
+// times within RPCErrorWindow.
+func (n *Notifier) ObserveRPC(method string, err error) {
+	if err == nil || n.config.RPCErrorThreshold <= 0 {
+		return
+	}
+	now := time.Now()
+	n.mu.Lock()
+	recent := n.rpcErrors[method][:0]
+	for _, at := range n.rpcErrors[method] {
+		if now.Sub(at) < n.config.RPCErrorWindow {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	if len(recent) < n.config.RPCErrorThreshold {
+		n.rpcErrors[method] = recent
+		n.mu.Unlock()
+		return
+	}
+	delete(n.rpcErrors, method)
+	n.mu.Unlock()
+	n.Notify(Alert{
+		Kind:     KindRPCErrors,
+		Severity: SeverityError,
+		Summary:  fmt.Sprintf("%s failed %d times within %s", method, len(recent), n.config.RPCErrorWindow),
+		Subject:  method,
+		Details:  map[string]string{"method": method, "lastError": err.Error()},
+	})
+}
+
+// Alerts on settlement failures from the listener's event feed (see Listener.SubscribeEvents),
+// until ctx is done or events is closed
+func (n *Notifier) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type != auction.EventSettlementFailed {
+				continue
+			}
+			block := blockNumber(ev.L1Block)
+			details := map[string]string{"l1Block": block, "error": ev.Error}
+			if ev.Bid != nil {
+				details["winner"] = ev.Bid.Address.Hex()
+			}
+			n.Notify(Alert{
+				Kind:     KindSettlementFailed,
+				Severity: SeverityCritical,
+				Summary:  fmt.Sprintf("Settlement of the auction for L1 block %s failed: %s", block, ev.Error),
+				Subject:  block,
+				Details:  details,
+				Time:     ev.Timestamp,
+			})
+		}
+	}
+}
+
+// To satisfy commitment.Observer
+func (n *Notifier) CommitmentIssued(c commitment.Commitment) {}
+
+// To satisfy commitment.Observer. Misses for external reasons, e.g. a missed slot, aren't violations.
+func (n *Notifier) CommitmentMissed(c commitment.Commitment, reason commitment.MissReason, block *big.Int) {
+	severity := SeverityError
+	switch reason {
+	case commitment.MissReasonExternal:
+		return
+	case commitment.MissReasonProposerFault:
+		severity = SeverityWarning
+	}
+	hash := c.Hash().Hex()
+	n.Notify(Alert{
+		Kind:     KindViolation,
+		Severity: severity,
+		Summary:  fmt.Sprintf("Preconf commitment %s missed at L1 block %s (%s)", hash, blockNumber(block), reason),
+		Subject:  hash,
+		Details: map[string]string{
+			"commitment":  hash,
+			"l1Block":     blockNumber(block),
+			"targetBlock": blockNumber(c.TargetBlock),
+			"reason":      reason.String(),
+		},
+	})
+}
+
+// To satisfy timesync.Alerter. Drift from NTP or slot boundaries stops auctions, so is critical, while clock
+// steps only warn.
+func (n *Notifier) ClockDrifted(source string, drift time.Duration, max time.Duration) {
+	severity := SeverityCritical
+	if source == "step" {
+		severity = SeverityWarning
+	}
+	n.Notify(Alert{
+		Kind:     KindClockDrift,
+		Severity: severity,
+		Summary:  fmt.Sprintf("Local clock drifted %s by %s, over the max of %s", source, drift, max),
+		Subject:  source,
+		Details:  map[string]string{"source": source, "drift": drift.String(), "max": max.String()},
+	})
+}
+
+// To satisfy crosscheck.Alerter. Replicas diverging on an auction point to a bug or a replica censoring bids.
+func (n *Notifier) StateDiverged(l1Block uint64, replica string, local common.Hash, remote common.Hash) {
+	block := strconv.FormatUint(l1Block, 10)
+	n.Notify(Alert{
+		Kind:     KindStateDivergence,
+		Severity: SeverityError,
+		Summary:  fmt.Sprintf("Replica %s diverged on the auction for L1 block %s", replica, block),
+		Subject:  block + "/" + replica,
+		Details:  map[string]string{"l1Block": block, "replica": replica, "localHash": local.Hex(), "remoteHash": remote.Hex()},
+	})
+}
+
+// To satisfy funding.Alerter. Settlements fail once the key can't pay for gas, critical once it can't cover the next.
+func (n *Notifier) FundsLow(address common.Address, balanceWei *big.Int, runway time.Duration) {
+	severity := SeverityWarning
+	if runway == 0 {
+		severity = SeverityCritical
+	}
+	n.Notify(Alert{
+		Kind:     KindFundsLow,
+		Severity: severity,
+		Summary:  fmt.Sprintf("Settlement key %s is low on funds, %s wei left for %s of settlements", address.Hex(), balanceWei, runway),
+		Subject:  address.Hex(),
+		Details:  map[string]string{"address": address.Hex(), "balanceWei": balanceWei.String(), "runway": runway.String()},
+	})
+}
+
+// Delivers queued alerts, then stops. Alerts notified afterwards are dropped.
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	n.closed = true
+	close(n.alerts)
+	n.mu.Unlock()
+	<-n.done
+}
+
+// Returns false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func blockNumber(block *big.Int) string {
+	if block == nil {
+		return ""
+	}
+	return block.String()
+}
@@ -0,0 +1,168 @@
+package alerting_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/alerting"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/listener"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var _ listener.Alerter = (*alerting.Notifier)(nil)
+var _ commitment.Observer = (*alerting.Notifier)(nil)
+
+type receiver struct {
+	mu       sync.Mutex
+	bodies   []json.RawMessage
+	failures int // Requests to fail before succeeding
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var body json.RawMessage
+	json.NewDecoder(req.Body).Decode(&body)
+	r.bodies = append(r.bodies, body)
+}
+
+func (r *receiver) alerts(t *testing.T) []alerting.Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var alerts []alerting.Alert
+	for _, body := range r.bodies {
+		var alert alerting.Alert
+		require.NoError(t, json.Unmarshal(body, &alert))
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+func newNotifier(t *testing.T, config alerting.Config) (*alerting.Notifier, *receiver) {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	config.Webhooks = []alerting.WebhookConfig{{URL: server.URL}}
+	n, err := alerting.NewNotifier(slog.Default(), config)
+	require.NoError(t, err)
+	return n, r
+}
+
+func TestDeduplicates(t *testing.T) {
+	n, r := newNotifier(t, alerting.Config{DedupInterval: time.Hour})
+	for _, subject := range []string{"100", "100", "101"} {
+		n.Notify(alerting.Alert{Kind: alerting.KindAuctionTimeout, Severity: alerting.SeverityCritical, Subject: subject})
+	}
+	n.Close()
+	n.Notify(alerting.Alert{Kind: alerting.KindAuctionTimeout, Subject: "102"}) // dropped after close
+	alerts := r.alerts(t)
+	require.Len(t, alerts, 2)
+	require.Equal(t, "100", alerts[0].Subject)
+	require.Equal(t, "101", alerts[1].Subject)
+	require.False(t, alerts[0].Time.IsZero())
+}
+
+func TestRepeatedRPCErrors(t *testing.T) {
+	n, r := newNotifier(t, alerting.Config{RPCErrorThreshold: 3, RPCErrorWindow: time.Minute})
+	n.ObserveRPC("eth_blockNumber", errors.New("connection refused"))
+	n.ObserveRPC("eth_blockNumber", nil)
+	n.ObserveRPC("eth_chainId", errors.New("connection refused"))
+	n.ObserveRPC("eth_blockNumber", errors.New("connection refused"))
+	n.ObserveRPC("eth_blockNumber", errors.New("timeout"))
+	n.Close()
+	alerts := r.alerts(t)
+	require.Len(t, alerts, 1)
+	require.Equal(t, alerting.KindRPCErrors, alerts[0].Kind)
+	require.Equal(t, "eth_blockNumber", alerts[0].Subject)
+	require.Equal(t, "timeout", alerts[0].Details["lastError"])
+}
+
+func TestAuctionTimedOutDeliveredSynchronously(t *testing.T) {
+	n, r := newNotifier(t, alerting.Config{})
+	defer n.Close()
+	r.failures = 1
+	n.AuctionTimedOut(100, 6*time.Second)
+	alerts := r.alerts(t)
+	require.Len(t, alerts, 1, "retried after failure")
+	require.Equal(t, alerting.KindAuctionTimeout, alerts[0].Kind)
+	require.Equal(t, "Auction for L1 block 100 did not end within 6s", alerts[0].Summary)
+}
+
+func TestSettlementFailuresAndViolations(t *testing.T) {
+	n, r := newNotifier(t, alerting.Config{})
+	events := make(chan auction.Event, 2)
+	events <- auction.Event{Type: auction.EventSettlement, L1Block: big.NewInt(99), Timestamp: time.Now()}
+	events <- auction.Event{Type: auction.EventSettlementFailed, L1Block: big.NewInt(100), Error: "reverted", Timestamp: time.Now()}
+	close(events)
+	n.Watch(context.Background(), events)
+
+	c := commitment.Commitment{VersionedHashes: []common.Hash{{0x01}}, TargetBlock: big.NewInt(101), ExpiryBlock: big.NewInt(101), FeeWei: big.NewInt(1)}
+	n.CommitmentIssued(c)
+	n.CommitmentMissed(c, commitment.MissReasonExternal, big.NewInt(102))
+	n.CommitmentMissed(c, commitment.MissReasonRelayFault, big.NewInt(102))
+	n.Close()
+
+	alerts := r.alerts(t)
+	require.Len(t, alerts, 2)
+	require.Equal(t, alerting.KindSettlementFailed, alerts[0].Kind)
+	require.Equal(t, "reverted", alerts[0].Details["error"])
+	require.Equal(t, alerting.KindViolation, alerts[1].Kind)
+	require.Equal(t, alerting.SeverityError, alerts[1].Severity)
+	require.Equal(t, "relayFault", alerts[1].Details["reason"])
+}
+
+func TestClockDrifted(t *testing.T) {
+	n, r := newNotifier(t, alerting.Config{})
+	n.ClockDrifted("ntp", 3*time.Second, 2*time.Second)
+	n.ClockDrifted("step", -5*time.Second, 2*time.Second)
+	n.Close()
+	alerts := r.alerts(t)
+	require.Len(t, alerts, 2)
+	require.Equal(t, alerting.KindClockDrift, alerts[0].Kind)
+	require.Equal(t, alerting.SeverityCritical, alerts[0].Severity)
+	require.Equal(t, "Local clock drifted ntp by 3s, over the max of 2s", alerts[0].Summary)
+	require.Equal(t, alerting.SeverityWarning, alerts[1].Severity)
+	require.Equal(t, "-5s", alerts[1].Details["drift"])
+}
+
+func TestStateDiverged(t *testing.T) {
+	n, r := newNotifier(t, alerting.Config{})
+	n.StateDiverged(100, "replica-b", common.HexToHash("0x01"), common.HexToHash("0x02"))
+	n.Close()
+	alerts := r.alerts(t)
+	require.Len(t, alerts, 1)
+	require.Equal(t, alerting.KindStateDivergence, alerts[0].Kind)
+	require.Equal(t, "Replica replica-b diverged on the auction for L1 block 100", alerts[0].Summary)
+	require.Equal(t, "100/replica-b", alerts[0].Subject)
+	require.Equal(t, common.HexToHash("0x02").Hex(), alerts[0].Details["remoteHash"])
+}
+
+func TestFundsLow(t *testing.T) {
+	n, r := newNotifier(t, alerting.Config{})
+	n.FundsLow(common.HexToAddress("0x01"), big.NewInt(1000), 40*time.Minute)
+	n.Close()
+	alerts := r.alerts(t)
+	require.Len(t, alerts, 1)
+	require.Equal(t, alerting.KindFundsLow, alerts[0].Kind)
+	require.Equal(t, alerting.SeverityWarning, alerts[0].Severity)
+	require.Equal(t, common.HexToAddress("0x01").Hex(), alerts[0].Subject)
+	require.Equal(t, "1000", alerts[0].Details["balanceWei"])
+	require.Equal(t, "40m0s", alerts[0].Details["runway"])
+}
@@ -0,0 +1,127 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+type WebhookConfig struct {
+	// json (default) posts alerts as is, slack as incoming webhook messages, pagerduty as Events API v2 events
+	Format string
+	// Defaults to the PagerDuty Events API for pagerduty
+	URL string
+	// Integration key, for pagerduty
+	RoutingKey string
+}
+
+type webhook struct {
+	config     WebhookConfig
+	source     string
+	httpClient *http.Client
+}
+
+func newWebhook(config WebhookConfig, source string) (*webhook, error) {
+	switch config.Format {
+	case "":
+		config.Format = "json"
+	case "json", "slack":
+	case "pagerduty":
+		if config.RoutingKey == "" {
+			return nil, fmt.Errorf("%w: pagerduty webhook requires a routing key", ErrInvalidConfig)
+		}
+		if config.URL == "" {
+			config.URL = pagerDutyEventsURL
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown webhook format %q", ErrInvalidConfig, config.Format)
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("%w: %s webhook requires a url", ErrInvalidConfig, config.Format)
+	}
+	return &webhook{config: config, source: source, httpClient: &http.Client{}}, nil
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      Severity          `json:"severity"`
+	Timestamp     string            `json:"timestamp"`
+	Component     string            `json:"component"`
+	Class         Kind              `json:"class"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+func (w *webhook) payload(alert Alert) any {
+	switch w.config.Format {
+	case "slack":
+		var text strings.Builder
+		fmt.Fprintf(&text, "*[%s] %s* on %s", strings.ToUpper(string(alert.Severity)), alert.Summary, w.source)
+		keys := make([]string, 0, len(alert.Details))
+		for key := range alert.Details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&text, "\n• %s: `%s`", key, alert.Details[key])
+		}
+		return slackMessage{Text: text.String()}
+	case "pagerduty":
+		return pagerDutyEvent{
+			RoutingKey:  w.config.RoutingKey,
+			EventAction: "trigger",
+			// PagerDuty groups triggers with the same key into one incident
+			DedupKey: alert.key(),
+			Payload: pagerDutyPayload{
+				Summary:       alert.Summary,
+				Source:        w.source,
+				Severity:      alert.Severity,
+				Timestamp:     alert.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+				Component:     "auctioneer",
+				Class:         alert.Kind,
+				CustomDetails: alert.Details,
+			},
+		}
+	}
+	return alert
+}
+
+func (w *webhook) post(ctx context.Context, alert Alert) error {
+	data, err := json.Marshal(w.payload(alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package alerting_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/alerting"
+
+	"github.com/stretchr/testify/require"
+)
+
+var timeout = alerting.Alert{
+	Kind:     alerting.KindAuctionTimeout,
+	Severity: alerting.SeverityCritical,
+	Summary:  "Auction for L1 block 100 did not end within 6s",
+	Subject:  "100",
+	Details:  map[string]string{"l1Block": "100", "error": "timeout"},
+	Time:     time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func deliver(t *testing.T, webhook alerting.WebhookConfig) map[string]any {
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+	webhook.URL = server.URL
+	n, err := alerting.NewNotifier(slog.Default(), alerting.Config{Webhooks: []alerting.WebhookConfig{webhook}, Source: "auctioneer-1"})
+	require.NoError(t, err)
+	n.Notify(timeout)
+	n.Close()
+	require.Len(t, r.bodies, 1)
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(r.bodies[0], &payload))
+	return payload
+}
+
+func TestSlackPayload(t *testing.T) {
+	payload := deliver(t, alerting.WebhookConfig{Format: "slack"})
+	require.Equal(t, "*[CRITICAL] Auction for L1 block 100 did not end within 6s* on auctioneer-1\n• error: `timeout`\n• l1Block: `100`", payload["text"])
+}
+
+func TestPagerDutyPayload(t *testing.T) {
+	payload := deliver(t, alerting.WebhookConfig{Format: "pagerduty", RoutingKey: "key"})
+	require.Equal(t, "key", payload["routing_key"])
+	require.Equal(t, "trigger", payload["event_action"])
+	require.Equal(t, "auctionTimeout/100", payload["dedup_key"])
+	require.Equal(t, map[string]any{
+		"summary":        timeout.Summary,
+		"source":         "auctioneer-1",
+		"severity":       "critical",
+		"timestamp":      "2024-03-01T12:00:00.000Z",
+		"component":      "auctioneer",
+		"class":          "auctionTimeout",
+		"custom_details": map[string]any{"l1Block": "100", "error": "timeout"},
+	}, payload["payload"])
+}
+
+func TestInvalidWebhooks(t *testing.T) {
+	for _, config := range []alerting.Config{
+		{},
+		{Webhooks: []alerting.WebhookConfig{{Format: "slack"}}},
+		{Webhooks: []alerting.WebhookConfig{{Format: "pagerduty"}}},
+		{Webhooks: []alerting.WebhookConfig{{Format: "teams", URL: "http://localhost"}}},
+		{Webhooks: []alerting.WebhookConfig{{URL: "http://localhost"}}, RPCErrorThreshold: 3},
+	} {
+		_, err := alerting.NewNotifier(slog.Default(), config)
+		require.ErrorIs(t, err, alerting.ErrInvalidConfig, "%+v", config)
+	}
+}
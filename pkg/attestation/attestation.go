@@ -0,0 +1,46 @@
+package attestation
+
+import (
+	"crypto/ecdsa"
+
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Watcher's counter-signature of an issued commitment, vouching it saw the commitment issued with a valid relay
+// signature
+type Attestation struct {
+	CommitmentHash common.Hash    `json:"commitmentHash"`
+	Watcher        common.Address `json:"watcher"`
+	Signature      hexutil.Bytes  `json:"signature"`
+}
+
+func CreateSignedAttestation(c commitment.Commitment, privateKey *ecdsa.PrivateKey) (*Attestation, error) {
+	a := Attestation{
+		CommitmentHash: c.Hash(),
+		Watcher:        crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+	signature, err := crypto.Sign(a.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	a.Signature = signature
+	return &a, nil
+}
+
+// Hash of the signed fields
+func (a *Attestation) Hash() common.Hash {
+	return crypto.Keccak256Hash(a.CommitmentHash.Bytes(), a.Watcher.Bytes())
+}
+
+// Checks the attestation is signed by its watcher, which must be checked against the watchers trusted
+func (a *Attestation) Verify() bool {
+	sigPublicKey, err := crypto.SigToPub(a.Hash().Bytes(), a.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == a.Watcher
+}
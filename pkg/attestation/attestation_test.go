@@ -0,0 +1,43 @@
+package attestation_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func signedCommitment(t *testing.T, targetBlock int64) commitment.Commitment {
+	relay, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(targetBlock),
+		ExpiryBlock:     big.NewInt(targetBlock + 1),
+		FeeWei:          big.NewInt(5),
+	}, relay)
+	require.NoError(t, err)
+	return *c
+}
+
+func TestAttestation(t *testing.T) {
+	watcher, _ := crypto.GenerateKey()
+	c := signedCommitment(t, 100)
+	a, err := attestation.CreateSignedAttestation(c, watcher)
+	require.NoError(t, err)
+	require.Equal(t, c.Hash(), a.CommitmentHash)
+	require.Equal(t, crypto.PubkeyToAddress(watcher.PublicKey), a.Watcher)
+	require.True(t, a.Verify())
+
+	other := *a
+	next := signedCommitment(t, 101)
+	other.CommitmentHash = next.Hash()
+	require.False(t, other.Verify(), "signature covers the commitment")
+	other = *a
+	other.Watcher = common.HexToAddress("0x01")
+	require.False(t, other.Verify(), "signature covers the watcher")
+}
@@ -0,0 +1,52 @@
+package attestation
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+// attest command running a third-party watcher that counter-signs issued commitments
+func NewCommand() *cobra.Command {
+	var keyFile, url, events string
+	cmd := &cobra.Command{
+		Use:   "attest",
+		Short: "Counter-sign issued commitments from the event stream as a watcher",
+		Long: `Reads the auctioneer's event stream, one JSON event per line, and counter-signs the commitment of every
+commitment.issued event with a valid relay signature, posting the attestation to the auctioneer's REST API.
+Events are read from --events, or stdin if "-", e.g. piped from the stream's NATS subject or the tail of its
+file sink. The watcher's address must be in the auctioneer's watchers.addresses.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := crypto.LoadECDSA(keyFile)
+			if err != nil {
+				return err
+			}
+			var r io.Reader = cmd.InOrStdin()
+			if events != "-" {
+				file, err := os.Open(events)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				r = file
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			signer := NewSigner(slog.Default(), key, url)
+			slog.Default().Info("attesting commitments", "watcher", signer.Address(), "auctioneer", url)
+			return signer.Run(ctx, r)
+		},
+	}
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "Watcher's hex private key file")
+	cmd.Flags().StringVar(&url, "url", "", "Auctioneer's REST API, with the chain's prefix in a multi-chain process")
+	cmd.Flags().StringVar(&events, "events", "-", `Event stream file, or "-" for stdin`)
+	cmd.MarkFlagRequired("key-file")
+	cmd.MarkFlagRequired("url")
+	return cmd
+}
@@ -0,0 +1,162 @@
+package attestation
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Commitments whose attestations are kept at most, the oldest forgotten first
+const maxTracked = 16384
+
+var (
+	ErrUnknownWatcher    = errors.New("unknown watcher")
+	ErrInvalidSignature  = errors.New("invalid attestation signature")
+	ErrUnknownCommitment = errors.New("unknown commitment")
+)
+
+type Config struct {
+	// Watchers whose attestations are accepted
+	Watchers []common.Address
+	// Attestations making a commitment multi-attested, at most len(Watchers)
+	Threshold int
+}
+
+// Satisfied by *commitment.Coordinator
+type Commitments interface {
+	Get(hash common.Hash) (commitment.Commitment, commitment.State, bool)
+}
+
+// Notified once a commitment reaches its quorum, e.g. the eventstream emitter
+type Observer interface {
+	CommitmentAttested(attested Attested)
+}
+
+// Commitment with the attestations of the watchers that counter-signed it
+type Attested struct {
+	Commitment   commitment.Commitment `json:"commitment"`
+	Attestations []Attestation         `json:"attestations"`
+	// Whether at least the threshold of watchers counter-signed it
+	Quorum    bool `json:"quorum"`
+	Threshold int  `json:"threshold"`
+}
+
+// Aggregates watchers' attestations of issued commitments, until a quorum of them multi-attests each
+type Quorum struct {
+	logger      *slog.Logger
+	config      Config
+	watchers    map[common.Address]struct{}
+	commitments Commitments
+	observer    Observer
+
+	mu sync.Mutex // Protects attestations and order
+	// Attestations of each commitment by watcher
+	attestations map[common.Hash]map[common.Address]Attestation
+	// Commitments attested, oldest first
+	order []common.Hash
+}
+
+func NewQuorum(logger *slog.Logger, config Config, commitments Commitments) (*Quorum, error) {
+	if len(config.Watchers) == 0 {
+		return nil, fmt.Errorf("no watchers")
+	}
+	watchers := make(map[common.Address]struct{}, len(config.Watchers))
+	for _, watcher := range config.Watchers {
+		watchers[watcher] = struct{}{}
+	}
+	if config.Threshold < 1 || config.Threshold > len(watchers) {
+		return nil, fmt.Errorf("threshold %d must be between 1 and the %d watchers", config.Threshold, len(watchers))
+	}
+	return &Quorum{
+		logger:       logger,
+		config:       config,
+		watchers:     watchers,
+		commitments:  commitments,
+		attestations: make(map[common.Hash]map[common.Address]Attestation),
+	}, nil
+}
+
+// Commitments reaching their quorum are notified, if set before attestations are added
+func (q *Quorum) SetObserver(observer Observer) {
+	q.observer = observer
+}
+
+// Adds a watcher's attestation of an issued commitment. Attesting a commitment again is a no-op.
+func (q *Quorum) Add(a Attestation) error {
+	if _, ok := q.watchers[a.Watcher]; !ok {
+		return ErrUnknownWatcher
+	}
+	if !a.Verify() {
+		return ErrInvalidSignature
+	}
+	c, _, found := q.commitments.Get(a.CommitmentHash)
+	if !found {
+		return ErrUnknownCommitment
+	}
+
+	q.mu.Lock()
+	attestations, ok := q.attestations[a.CommitmentHash]
+	if !ok {
+		attestations = make(map[common.Address]Attestation)
+		q.attestations[a.CommitmentHash] = attestations
+		q.order = append(q.order, a.CommitmentHash)
+		if len(q.order) > maxTracked {
+			delete(q.attestations, q.order[0])
+			q.order = q.order[1:]
+		}
+	}
+	if _, ok := attestations[a.Watcher]; ok {
+		q.mu.Unlock()
+		return nil
+	}
+	attestations[a.Watcher] = a
+	reached := len(attestations) == q.config.Threshold
+	var attested Attested
+	if reached {
+		attested = q.attested(c, attestations)
+	}
+	q.mu.Unlock()
+
+	q.logger.Debug("commitment attested", "commitment", a.CommitmentHash, "watcher", a.Watcher, "quorum", reached)
+	if reached {
+		q.logger.Info("commitment reached attestation quorum", "commitment", a.CommitmentHash, "threshold", q.config.Threshold)
+		if q.observer != nil {
+			q.observer.CommitmentAttested(attested)
+		}
+	}
+	return nil
+}
+
+// Commitment with its attestations so far, false if it isn't known
+func (q *Quorum) Get(hash common.Hash) (Attested, bool) {
+	c, _, found := q.commitments.Get(hash)
+	if !found {
+		return Attested{}, false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.attested(c, q.attestations[hash]), true
+}
+
+// Must be called with mu held
+func (q *Quorum) attested(c commitment.Commitment, attestations map[common.Address]Attestation) Attested {
+	attested := Attested{
+		Commitment:   c,
+		Attestations: make([]Attestation, 0, len(attestations)),
+		Quorum:       len(attestations) >= q.config.Threshold,
+		Threshold:    q.config.Threshold,
+	}
+	for _, a := range attestations {
+		attested.Attestations = append(attested.Attestations, a)
+	}
+	sort.Slice(attested.Attestations, func(i, j int) bool {
+		return attested.Attestations[i].Watcher.Cmp(attested.Attestations[j].Watcher) < 0
+	})
+	return attested
+}
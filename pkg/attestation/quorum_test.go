@@ -0,0 +1,93 @@
+package attestation_test
+
+import (
+	"crypto/ecdsa"
+	"log/slog"
+	"testing"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCommitments map[common.Hash]commitment.Commitment
+
+func (m mockCommitments) Get(hash common.Hash) (commitment.Commitment, commitment.State, bool) {
+	c, ok := m[hash]
+	return c, commitment.StateActive, ok
+}
+
+type mockObserver []attestation.Attested
+
+func (m *mockObserver) CommitmentAttested(attested attestation.Attested) {
+	*m = append(*m, attested)
+}
+
+func newWatchers(n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	keys := make([]*ecdsa.PrivateKey, n)
+	addresses := make([]common.Address, n)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addresses[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	return keys, addresses
+}
+
+func TestQuorum(t *testing.T) {
+	keys, watchers := newWatchers(3)
+	c := signedCommitment(t, 100)
+	commitments := mockCommitments{c.Hash(): c}
+	quorum, err := attestation.NewQuorum(slog.Default(), attestation.Config{Watchers: watchers, Threshold: 2}, commitments)
+	require.NoError(t, err)
+	observer := &mockObserver{}
+	quorum.SetObserver(observer)
+	attest := func(c commitment.Commitment, key *ecdsa.PrivateKey) attestation.Attestation {
+		a, err := attestation.CreateSignedAttestation(c, key)
+		require.NoError(t, err)
+		return *a
+	}
+
+	attested, found := quorum.Get(c.Hash())
+	require.True(t, found)
+	require.Empty(t, attested.Attestations)
+	require.False(t, attested.Quorum)
+	_, found = quorum.Get(common.Hash{0x01})
+	require.False(t, found)
+
+	stranger, _ := crypto.GenerateKey()
+	require.ErrorIs(t, quorum.Add(attest(c, stranger)), attestation.ErrUnknownWatcher)
+	forged := attest(c, keys[0])
+	forged.Watcher = watchers[1]
+	require.ErrorIs(t, quorum.Add(forged), attestation.ErrInvalidSignature)
+	require.ErrorIs(t, quorum.Add(attest(signedCommitment(t, 101), keys[0])), attestation.ErrUnknownCommitment)
+
+	require.NoError(t, quorum.Add(attest(c, keys[0])))
+	require.NoError(t, quorum.Add(attest(c, keys[0])), "attesting again is a no-op")
+	attested, _ = quorum.Get(c.Hash())
+	require.Len(t, attested.Attestations, 1)
+	require.False(t, attested.Quorum)
+	require.Empty(t, *observer)
+
+	require.NoError(t, quorum.Add(attest(c, keys[1])))
+	require.NoError(t, quorum.Add(attest(c, keys[2])))
+	attested, _ = quorum.Get(c.Hash())
+	require.Len(t, attested.Attestations, 3)
+	require.True(t, attested.Quorum)
+	require.Equal(t, 2, attested.Threshold)
+	require.Len(t, *observer, 1, "notified once, as the quorum is reached")
+	require.Equal(t, c, (*observer)[0].Commitment)
+	require.Len(t, (*observer)[0].Attestations, 2)
+}
+
+func TestQuorumConfig(t *testing.T) {
+	_, watchers := newWatchers(2)
+	_, err := attestation.NewQuorum(slog.Default(), attestation.Config{Threshold: 1}, mockCommitments{})
+	require.Error(t, err, "no watchers")
+	_, err = attestation.NewQuorum(slog.Default(), attestation.Config{Watchers: watchers, Threshold: 3}, mockCommitments{})
+	require.Error(t, err, "threshold above watchers")
+	_, err = attestation.NewQuorum(slog.Default(), attestation.Config{Watchers: watchers}, mockCommitments{})
+	require.Error(t, err, "no threshold")
+}
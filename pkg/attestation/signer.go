@@ -0,0 +1,121 @@
+package attestation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Path attestations are posted to on the auctioneer's REST API
+const AttestationsPath = "/v1/attestations"
+
+// Type of the event stream's events carrying issued commitments, see eventstream
+const issuedEventType = "commitment.issued"
+
+// Bounds posting one attestation, so a slow auctioneer doesn't hold back later commitments
+const postTimeout = 5 * time.Second
+
+// Events larger than this are skipped
+const maxEventSize = 1024 * 1024
+
+// Fields of the event stream's commitment.issued events a watcher needs
+type issuedEvent struct {
+	Type           string                 `json:"type"`
+	CommitmentHash *common.Hash           `json:"commitmentHash"`
+	Commitment     *commitment.Commitment `json:"commitment"`
+}
+
+// Third-party watcher counter-signing commitments it sees issued on the auctioneer's event stream, and posting its
+// attestations to the auctioneer
+type Signer struct {
+	logger     *slog.Logger
+	key        *ecdsa.PrivateKey
+	url        string
+	httpClient *http.Client
+}
+
+// url is the auctioneer's REST API, e.g. https://auctioneer.example or https://auctioneer.example/chains/holesky
+func NewSigner(logger *slog.Logger, key *ecdsa.PrivateKey, url string) *Signer {
+	return &Signer{logger: logger, key: key, url: strings.TrimSuffix(url, "/"), httpClient: &http.Client{}}
+}
+
+func (s *Signer) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// Counter-signs the commitments of commitment.issued events read from r, one JSON event per line as published by
+// the event stream's sinks, until r ends or ctx is done. Other events are skipped, and commitments failing to be
+// attested are logged rather than stopping the watcher.
+func (s *Signer) Run(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var ev issuedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			s.logger.Warn("skipping undecodable event", "error", err)
+			continue
+		}
+		if ev.Type != issuedEventType || ev.Commitment == nil {
+			continue
+		}
+		if ev.CommitmentHash != nil && *ev.CommitmentHash != ev.Commitment.Hash() {
+			s.logger.Warn("skipping commitment not matching its event's hash", "commitment", *ev.CommitmentHash)
+			continue
+		}
+		postCtx, cancel := context.WithTimeout(ctx, postTimeout)
+		a, err := s.Attest(postCtx, *ev.Commitment)
+		cancel()
+		if err != nil {
+			s.logger.Warn("failed to attest commitment", "commitment", ev.Commitment.Hash(), "error", err)
+			continue
+		}
+		s.logger.Info("attested commitment", "commitment", a.CommitmentHash, "targetBlock", ev.Commitment.TargetBlock)
+	}
+	return scanner.Err()
+}
+
+// Counter-signs the commitment if its relay signature is valid, and posts the attestation to the auctioneer
+func (s *Signer) Attest(ctx context.Context, c commitment.Commitment) (*Attestation, error) {
+	if !c.Verify() {
+		return nil, fmt.Errorf("invalid commitment signature")
+	}
+	a, err := CreateSignedAttestation(c, s.key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+AttestationsPath, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("auctioneer returned status %d: %s", resp.StatusCode, msg)
+	}
+	return a, nil
+}
@@ -0,0 +1,63 @@
+package attestation_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"blob-preconfs/pkg/attestation"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	keys, watchers := newWatchers(1)
+	c := signedCommitment(t, 100)
+	forged := signedCommitment(t, 101)
+	forged.FeeWei.SetInt64(1)
+	quorum, err := attestation.NewQuorum(slog.Default(), attestation.Config{Watchers: watchers, Threshold: 1}, mockCommitments{c.Hash(): c})
+	require.NoError(t, err)
+	var posted int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, attestation.AttestationsPath, r.URL.Path)
+		posted++
+		var a attestation.Attestation
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&a))
+		if err := quorum.Add(a); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	var events strings.Builder
+	for _, ev := range []any{
+		map[string]any{"type": "auction.opened", "l1Block": 100},
+		map[string]any{"type": "commitment.issued", "commitmentHash": forged.Hash(), "commitment": forged},
+		map[string]any{"type": "commitment.issued", "commitmentHash": c.Hash(), "commitment": c},
+	} {
+		data, err := json.Marshal(ev)
+		require.NoError(t, err)
+		events.Write(append(data, '\n'))
+	}
+	events.WriteString("not json\n")
+
+	signer := attestation.NewSigner(slog.Default(), keys[0], server.URL+"/")
+	require.Equal(t, watchers[0], signer.Address())
+	require.NoError(t, signer.Run(context.Background(), strings.NewReader(events.String())))
+	require.Equal(t, 1, posted, "only validly signed commitments are attested")
+	attested, _ := quorum.Get(c.Hash())
+	require.True(t, attested.Quorum)
+
+	_, err = signer.Attest(context.Background(), signedCommitment(t, 102))
+	require.Error(t, err, "rejected by the auctioneer")
+	stranger, _ := crypto.GenerateKey()
+	_, err = attestation.NewSigner(slog.Default(), stranger, server.URL).Attest(context.Background(), c)
+	require.ErrorContains(t, err, "unknown watcher")
+}
@@ -0,0 +1,104 @@
+package auction
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Relays allowed to bid, managed at runtime e.g. by the admin API. Denied relays are rejected even if allowed.
+type AccessList struct {
+	mu      sync.RWMutex
+	allowed map[common.Address]struct{}
+	denied  map[common.Address]struct{}
+}
+
+func NewAccessList(allowed []common.Address, denied []common.Address) *AccessList {
+	list := &AccessList{}
+	list.Replace(allowed, denied)
+	return list
+}
+
+// Access list allowing the default relay whitelist
+func DefaultAccessList() *AccessList {
+	return NewAccessList(relayWhitelist, nil)
+}
+
+func (a *AccessList) IsAllowed(address common.Address) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	_, allowed := a.allowed[address]
+	_, denied := a.denied[address]
+	return allowed && !denied
+}
+
+func (a *AccessList) IsDenied(address common.Address) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	_, denied := a.denied[address]
+	return denied
+}
+
+func (a *AccessList) Allow(address common.Address) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.allowed[address] = struct{}{}
+}
+
+func (a *AccessList) RemoveAllowed(address common.Address) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.allowed, address)
+}
+
+func (a *AccessList) Deny(address common.Address) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.denied[address] = struct{}{}
+}
+
+func (a *AccessList) RemoveDenied(address common.Address) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.denied, address)
+}
+
+// Replaces both lists, e.g. on config reload
+func (a *AccessList) Replace(allowed []common.Address, denied []common.Address) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.allowed = toSet(allowed)
+	a.denied = toSet(denied)
+}
+
+// Sorted allowed addresses
+func (a *AccessList) Allowed() []common.Address {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return sorted(a.allowed)
+}
+
+// Sorted denied addresses
+func (a *AccessList) Denied() []common.Address {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return sorted(a.denied)
+}
+
+func toSet(addresses []common.Address) map[common.Address]struct{} {
+	set := make(map[common.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		set[address] = struct{}{}
+	}
+	return set
+}
+
+func sorted(set map[common.Address]struct{}) []common.Address {
+	addresses := make([]common.Address, 0, len(set))
+	for address := range set {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Cmp(addresses[j]) < 0 })
+	return addresses
+}
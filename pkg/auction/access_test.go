@@ -0,0 +1,34 @@
+package auction_test
+
+import (
+	"testing"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessList(t *testing.T) {
+	a, b, c := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	list := auction.NewAccessList([]common.Address{b, a}, nil)
+	require.True(t, list.IsAllowed(a))
+	require.False(t, list.IsAllowed(c))
+	require.Equal(t, []common.Address{a, b}, list.Allowed())
+
+	list.Deny(a)
+	require.False(t, list.IsAllowed(a), "denied takes precedence")
+	require.True(t, list.IsDenied(a))
+	list.RemoveDenied(a)
+	require.True(t, list.IsAllowed(a))
+
+	list.Allow(c)
+	list.RemoveAllowed(b)
+	require.Equal(t, []common.Address{a, c}, list.Allowed())
+
+	list.Replace([]common.Address{b}, []common.Address{c})
+	require.Equal(t, []common.Address{b}, list.Allowed())
+	require.Equal(t, []common.Address{c}, list.Denied())
+
+	require.True(t, auction.DefaultAccessList().IsAllowed(common.HexToAddress("0xDeFEA225C9e43F1A4Ccb561867Be9c9bf3142a98")))
+}
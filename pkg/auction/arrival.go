@@ -0,0 +1,37 @@
+package auction
+
+import (
+	"sync"
+	"time"
+)
+
+// Stamps bids with the time they reached the auctioneer. Stamps follow the monotonic clock from when the clock was
+// created, so they never step back with the wall clock, and each is later than the last, so no two bids tie.
+type ArrivalClock struct {
+	start time.Time
+
+	mu   sync.Mutex // Protects last
+	last int64
+}
+
+func NewArrivalClock() *ArrivalClock {
+	return &ArrivalClock{start: time.Now()}
+}
+
+// Arrival of a bid received now, without a monotonic reading so it round trips through encodings unchanged
+func (c *ArrivalClock) Now() time.Time {
+	nanos := c.start.Round(0).Add(time.Since(c.start)).UnixNano()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if nanos <= c.last {
+		nanos = c.last + 1
+	}
+	c.last = nanos
+	return time.Unix(0, nanos)
+}
+
+// Bid with the time it reached the auctioneer, which breaks ties between bids of the same amount
+type ReceivedBid struct {
+	SignedBid
+	ReceivedAt time.Time
+}
@@ -0,0 +1,22 @@
+package auction_test
+
+import (
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrivalClock(t *testing.T) {
+	clock := auction.NewArrivalClock()
+	last := clock.Now()
+	for i := 0; i < 1000; i++ {
+		now := clock.Now()
+		require.True(t, now.After(last), "strictly increasing")
+		require.Equal(t, now, now.Round(0), "no monotonic reading")
+		last = now
+	}
+	require.WithinDuration(t, time.Now(), last, time.Second)
+}
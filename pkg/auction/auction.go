@@ -3,28 +3,111 @@ package auction
 import (
 	"context"
 	"log/slog"
+	"math/big"
+	"runtime"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 )
 
 type RelayAuction struct {
 	logger            *slog.Logger
-	bidSubmissionChan chan SignedBid
-	currentBid        SignedBid
-	currentBidMutex   sync.RWMutex // Protects access to currentBid
+	bidSubmissionChan chan submission
+	// Leading bid, nil until the first valid one. Swapped rather than locked, so reads never contend with bids.
+	currentBid        atomic.Pointer[ReceivedBid]
 	auctionResultChan chan SignedBid
 	relayRegistry     RelayRegistry
+	eventFeed         *event.Feed
+	rejectionFeed     *event.Feed
+	accessList        *AccessList
+	auditor           Auditor
+	metrics           Metrics
+	verifiers         int
+	// Bids per shard of relays, if sharded, see SetShards
+	shards []chan submission
+	// Bids submitted and not yet evaluated, whether queued or being verified
+	queued atomic.Int64
+	// Orders leader change events from concurrent shards
+	publishMu sync.Mutex
+	// Closing early once no new leader for quietPeriod after minOpen, if set, see SetEarlyClose
+	minOpen         time.Duration
+	quietPeriod     time.Duration
+	leaderChangedAt atomic.Int64
+	// Lowest amount bids may be, none if nil, see SetReservePrice
+	reservePrice *big.Int
+	// Reveals sealed bids at close, if set, see SetRevealer
+	revealer Revealer
+	// Closed by Cancel
+	cancelled  chan struct{}
+	cancelOnce sync.Once
+	// Closed once bidding is over, the bidding period ended or the auction stopped, so submitters blocked on a full
+	// queue are released rather than left waiting on an auction no longer reading it
+	biddingOver chan struct{}
+	overOnce    sync.Once
+
+	rankMu sync.Mutex // Protects ranked, written by concurrent shards
+	// Each relay's best valid bid, whether it led or was outbid, for falling back on runners-up, see Ranked
+	ranked map[common.Address]ReceivedBid
+}
+
+// Records the outcome of every bid received, accepted or rejected with the reason, e.g. *audit.Log
+type Auditor interface {
+	RecordBid(bid SignedBid, accepted bool, reason string)
+}
+
+// Fans bid outcomes out to several auditors, e.g. the audit log and the event stream
+type MultiAuditor []Auditor
+
+func (m MultiAuditor) RecordBid(bid SignedBid, accepted bool, reason string) {
+	for _, auditor := range m {
+		auditor.RecordBid(bid, accepted, reason)
+	}
+}
+
+// Observes bid handling, e.g. *metrics.Metrics
+type Metrics interface {
+	ObserveBidVerification(duration time.Duration, valid bool)
+	// Latency from the bid's submission to the auction to reaching the stage
+	ObserveBidLatency(stage BidStage, latency time.Duration, bid SignedBid)
+	// Bids submitted to the auction and not yet evaluated
+	ObserveBidQueueDepth(depth int)
+}
+
+type BidStage string
+
+const (
+	// Signature verified, whether valid or not. Includes time queued behind earlier bids.
+	BidStageVerified BidStage = "verified"
+	// Became the leader, just before the leader change is published
+	BidStageAccepted BidStage = "accepted"
+)
+
+// Bids submitted and not yet verified, beyond which SubmitBid blocks. Bids being verified are on top.
+const bidQueueSize = 64
+
+type submission struct {
+	bid SignedBid
+	// Arrival at the auctioneer, breaking ties, and submission to the auction, which latencies are observed from
+	receivedAt  time.Time
+	submittedAt time.Time
+	// Whether the signature is valid, sent once verified
+	verified chan bool
 }
 
 func NewRelayAuction(logger *slog.Logger, relayRegistry RelayRegistry) *RelayAuction {
 	return &RelayAuction{
 		logger:            logger,
-		bidSubmissionChan: make(chan SignedBid, 10),
-		currentBid:        SignedBid{},
+		bidSubmissionChan: make(chan submission, bidQueueSize),
 		auctionResultChan: make(chan SignedBid),
 		relayRegistry:     relayRegistry,
+		verifiers:         runtime.GOMAXPROCS(0),
+		ranked:            make(map[common.Address]ReceivedBid),
+		cancelled:         make(chan struct{}),
+		biddingOver:       make(chan struct{}),
 	}
 }
 
@@ -32,78 +115,395 @@ type RelayRegistry interface {
 	IsRegisteredOnSettlementLayer(address common.Address) bool
 }
 
-func (r *RelayAuction) StartAsync(ctx context.Context, biddingPeriod time.Duration) chan SignedBid {
+// Leader changes are published on the feed, if set before the auction starts
+func (r *RelayAuction) SetEventFeed(feed *event.Feed) {
+	r.eventFeed = feed
+}
+
+// Rejected bids are published on the feed with the leader at the time, if set before the auction starts
+func (r *RelayAuction) SetRejectionFeed(feed *event.Feed) {
+	r.rejectionFeed = feed
+}
+
+// Bidders are checked against the access list, if set before the auction starts, otherwise the default whitelist
+func (r *RelayAuction) SetAccessList(accessList *AccessList) {
+	r.accessList = accessList
+}
+
+// Evaluated bids are recorded, if set before the auction starts. Accepted bids are those that became the leader.
+func (r *RelayAuction) SetAuditor(auditor Auditor) {
+	r.auditor = auditor
+}
+
+// Bid verification and handling latency is observed, if set before the auction starts
+func (r *RelayAuction) SetMetrics(metrics Metrics) {
+	r.metrics = metrics
+}
+
+// Overrides verifying bid signatures on one worker per CPU, if set before the auction starts. Bids are
+// still evaluated in the order submitted.
+func (r *RelayAuction) SetVerifiers(n int) {
+	r.verifiers = max(n, 1)
+}
+
+// Closes the auction before the bidding period is over once there's a leader and no new one for quietPeriod,
+// after it's been open for at least minOpen, if set before the auction starts. Reduces preconf latency when few
+// relays are bidding.
+func (r *RelayAuction) SetEarlyClose(minOpen time.Duration, quietPeriod time.Duration) {
+	r.minOpen = minOpen
+	r.quietPeriod = quietPeriod
+}
+
+// Bids below the reserve price are rejected, if set before the auction starts
+func (r *RelayAuction) SetReservePrice(reservePriceWei *big.Int) {
+	r.reservePrice = reservePriceWei
+}
+
+// Runs the auction for the bidding period, then sends the winner, the zero bid if none, unless ctx is done or
+// the auction is cancelled first
+func (r *RelayAuction) StartAsync(ctx context.Context, biddingPeriod time.Duration) <-chan SignedBid {
 	go r.runAuction(ctx, biddingPeriod)
 	return r.auctionResultChan
 }
 
-func (r *RelayAuction) SubmitBid(signedBid SignedBid) {
-	r.bidSubmissionChan <- signedBid
+// Stops the auction, which then sends no winner, even if it's yet to start. Safe to call more than once.
+func (r *RelayAuction) Cancel() {
+	r.cancelOnce.Do(func() { close(r.cancelled) })
+	r.endBidding()
+}
+
+// Blocks while the auction's queue is full, so a flood of bids slows relays down rather than growing memory.
+// Fails with ErrNoActiveAuction once bidding is over.
+func (r *RelayAuction) SubmitBid(signedBid SignedBid) error {
+	return r.SubmitBidAt(signedBid, time.Now())
+}
+
+// Submits a bid that reached the auctioneer at receivedAt, see ArrivalClock. Ties go to the bid received first.
+func (r *RelayAuction) SubmitBidAt(signedBid SignedBid, receivedAt time.Time) error {
+	select {
+	case <-r.biddingOver:
+		return ErrNoActiveAuction
+	default:
+	}
+	sub := submission{bid: signedBid, receivedAt: receivedAt, submittedAt: time.Now()}
+	queue := r.bidSubmissionChan
+	if len(r.shards) > 0 {
+		queue = r.shards[shardOf(signedBid.Address, len(r.shards))]
+	} else {
+		sub.verified = make(chan bool, 1)
+	}
+	r.observeQueueDepth(r.queued.Add(1))
+	select {
+	case queue <- sub:
+		return nil
+	case <-r.biddingOver:
+		r.observeQueueDepth(r.queued.Add(-1))
+		return ErrNoActiveAuction
+	}
+}
+
+func (r *RelayAuction) endBidding() {
+	r.overOnce.Do(func() { close(r.biddingOver) })
+}
+
+// Bids submitted and not yet evaluated
+func (r *RelayAuction) QueueDepth() int {
+	return int(r.queued.Load())
 }
 
 func (r *RelayAuction) GetCurrentBid() SignedBid {
-	r.currentBidMutex.RLock()
-	defer r.currentBidMutex.RUnlock()
-	return r.currentBid
+	if leader := r.currentBid.Load(); leader != nil {
+		return leader.SignedBid
+	}
+	return SignedBid{}
+}
+
+// Each relay's best valid bid, best first, the winner's leading once the auction closed. Runners-up are the
+// bids awards fall back to when the winner defaults.
+func (r *RelayAuction) Ranked() []SignedBid {
+	r.rankMu.Lock()
+	received := make([]ReceivedBid, 0, len(r.ranked))
+	for _, bid := range r.ranked {
+		received = append(received, bid)
+	}
+	r.rankMu.Unlock()
+	sort.Slice(received, func(i, j int) bool { return beats(received[i], received[j]) })
+	ranked := make([]SignedBid, len(received))
+	for i, bid := range received {
+		ranked[i] = bid.SignedBid
+	}
+	return ranked
+}
+
+func (r *RelayAuction) rank(bid ReceivedBid) {
+	r.rankMu.Lock()
+	defer r.rankMu.Unlock()
+	if best, ok := r.ranked[bid.Address]; !ok || beats(bid, best) {
+		r.ranked[bid.Address] = bid
+	}
 }
 
 func (r *RelayAuction) runAuction(ctx context.Context, biddingPeriod time.Duration) {
 	r.logger.Info("starting auction")
-	auctionTimer := time.NewTimer(biddingPeriod)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.cancelled:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	closed := r.closing(ctx, biddingPeriod)
+	// Bids left unevaluated once the auction is over are dropped
+	defer r.observeQueueDepth(0)
+	if len(r.shards) > 0 {
+		r.runShards(ctx, closed)
+		return
+	}
 
+	// Signatures are verified concurrently, while bids are evaluated in the order dispatched
+	toVerify := make(chan submission)
+	ordered := make(chan submission, r.verifiers)
+	for i := 0; i < r.verifiers; i++ {
+		go r.verify(ctx, toVerify)
+	}
+	go r.dispatch(ctx, toVerify, ordered)
+
+	seen := make(map[string]struct{})
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-auctionTimer.C:
-			r.currentBidMutex.RLock()
-			winner := r.currentBid
-			r.currentBidMutex.RUnlock()
+		case <-closed:
+			r.reveal(ctx, seen)
+			winner := r.GetCurrentBid()
 
 			r.logger.Info("auction ended, winner", "bid", winner)
-			r.auctionResultChan <- winner
+			select {
+			case r.auctionResultChan <- winner:
+			case <-ctx.Done():
+			}
 			return
-		case bid := <-r.bidSubmissionChan:
-			r.logger.Info("new bid received, it will be evaluated", "bid", bid)
-			if r.evaluateBid(bid) {
-				r.currentBidMutex.Lock()
-				r.currentBid = bid
-				r.currentBidMutex.Unlock()
+		case sub := <-ordered:
+			var valid bool
+			select {
+			case valid = <-sub.verified:
+			case <-ctx.Done():
+				return
 			}
+			r.handle(sub, valid, seen)
 		}
 	}
 }
 
+// Evaluates a verified bid, then records and publishes the outcome. Returns the bid as stored if it became the leader.
+// Bids already seen, by signature, are rejected as duplicates.
+func (r *RelayAuction) handle(sub submission, valid bool, seen map[string]struct{}) *ReceivedBid {
+	bid := sub.bid
+	r.logger.Info("new bid received, it will be evaluated", "bid", bid)
+	code := r.evaluateBid(bid, valid)
+	if code == "" {
+		if _, ok := seen[string(bid.Signature)]; ok {
+			r.logger.Warn("duplicate bid received", "bid", bid)
+			code = RejectDuplicate
+		}
+		seen[string(bid.Signature)] = struct{}{}
+	}
+	var leader *ReceivedBid
+	if code == "" {
+		received := ReceivedBid{SignedBid: bid, ReceivedAt: sub.receivedAt}
+		if leader = r.lead(received); leader != nil {
+			r.leaderChangedAt.Store(time.Now().UnixNano())
+			r.logger.Info("higher or first valid bid received", "bid", bid)
+		} else {
+			code = RejectOutbid
+		}
+		r.rank(received)
+	}
+	r.observeQueueDepth(r.queued.Add(-1))
+	if r.auditor != nil {
+		r.auditor.RecordBid(bid, code == "", code.Reason())
+	}
+	if code != "" && r.rejectionFeed != nil {
+		var current *SignedBid
+		if leader := r.currentBid.Load(); leader != nil {
+			current = &leader.SignedBid
+		}
+		r.rejectionFeed.Send(Rejection{Bid: bid, Code: code, Reason: code.Reason(), Leader: current, Timestamp: time.Now()})
+	}
+	if leader != nil {
+		if r.metrics != nil {
+			r.metrics.ObserveBidLatency(BidStageAccepted, time.Since(sub.submittedAt), bid)
+		}
+		r.publishLeader(leader)
+	}
+	return leader
+}
+
+// Publishes the leader change unless the bid was already overtaken, e.g. by another shard, whose change is published instead
+func (r *RelayAuction) publishLeader(leader *ReceivedBid) {
+	if r.eventFeed == nil {
+		return
+	}
+	r.publishMu.Lock()
+	defer r.publishMu.Unlock()
+	if r.currentBid.Load() != leader {
+		return
+	}
+	bid := leader.SignedBid
+	r.eventFeed.Send(Event{Type: EventLeaderChanged, L1Block: bid.L1Block, Bid: &bid, Timestamp: time.Now()})
+}
+
+// Closed once the bidding period is over, or once bids quiesce if closing early
+func (r *RelayAuction) closing(ctx context.Context, biddingPeriod time.Duration) <-chan struct{} {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		defer r.endBidding()
+		deadline := time.NewTimer(biddingPeriod)
+		defer deadline.Stop()
+		// Nil unless closing early
+		var check *time.Timer
+		var checkC <-chan time.Time
+		if r.quietPeriod > 0 {
+			check = time.NewTimer(r.minOpen)
+			defer check.Stop()
+			checkC = check.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-deadline.C:
+				return
+			case <-checkC:
+				wait := r.quietPeriod
+				if r.currentBid.Load() != nil {
+					quiet := time.Since(time.Unix(0, r.leaderChangedAt.Load()))
+					if quiet >= r.quietPeriod {
+						r.logger.Info("closing auction early, no new leader", "quiet", quiet)
+						return
+					}
+					wait -= quiet
+				}
+				check.Reset(wait)
+			}
+		}
+	}()
+	return closed
+}
+
+// Hands each submitted bid to a free verifier, then queues it for evaluation. Blocks while every verifier is
+// busy and the evaluation queue is full, leaving bids in the submission channel.
+func (r *RelayAuction) dispatch(ctx context.Context, toVerify chan<- submission, ordered chan<- submission) {
+	for {
+		var sub submission
+		select {
+		case sub = <-r.bidSubmissionChan:
+		case <-ctx.Done():
+			return
+		}
+		for _, ch := range []chan<- submission{toVerify, ordered} {
+			select {
+			case ch <- sub:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (r *RelayAuction) verify(ctx context.Context, toVerify <-chan submission) {
+	for {
+		select {
+		case sub := <-toVerify:
+			sub.verified <- r.verifyBid(sub)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *RelayAuction) verifyBid(sub submission) bool {
+	started := time.Now()
+	valid := sub.bid.Verify()
+	if r.metrics != nil {
+		verified := time.Now()
+		r.metrics.ObserveBidVerification(verified.Sub(started), valid)
+		r.metrics.ObserveBidLatency(BidStageVerified, verified.Sub(sub.submittedAt), sub.bid)
+	}
+	return valid
+}
+
+func (r *RelayAuction) observeQueueDepth(depth int64) {
+	if r.metrics != nil {
+		r.metrics.ObserveBidQueueDepth(int(depth))
+	}
+}
+
 var relayWhitelist = []common.Address{
 	common.HexToAddress("0xDeFEA225C9e43F1A4Ccb561867Be9c9bf3142a98"),
 	common.HexToAddress("0xE882aFBf387B7C487b3C17159ad46E13474D9e1E"),
 }
 
-func (r *RelayAuction) evaluateBid(bid SignedBid) bool {
-	if !bid.Verify() {
+// Returns why the bid was rejected, empty if it may bid
+func (r *RelayAuction) evaluateBid(bid SignedBid, valid bool) RejectCode {
+	if !valid {
 		r.logger.Warn("invalid bid received", "bid", bid)
-		return false
+		return RejectInvalidSignature
 	}
 
-	if !contains(relayWhitelist, bid.Address) {
+	if r.accessList != nil {
+		if r.accessList.IsDenied(bid.Address) {
+			r.logger.Warn("bidder on denylist", "bid", bid)
+			return RejectDenied
+		}
+		if !r.accessList.IsAllowed(bid.Address) {
+			r.logger.Warn("bidder not on whitelist", "bid", bid)
+			return RejectNotAllowed
+		}
+	} else if !contains(relayWhitelist, bid.Address) {
 		r.logger.Warn("bidder not on whitelist", "bid", bid)
-		return false
+		return RejectNotAllowed
 	}
 
 	if !r.relayRegistry.IsRegisteredOnSettlementLayer(bid.Address) {
 		r.logger.Warn("bidder not registered or prepaid on settlement layer", "bid", bid)
-		return false
+		return RejectNotRegistered
 	}
 
-	r.currentBidMutex.RLock()
-	isFirstOrHigherBid := SignedBid{}.Address == r.currentBid.Address || bid.AmountWei.Cmp(r.currentBid.AmountWei) > 0
-	r.currentBidMutex.RUnlock()
-	if isFirstOrHigherBid {
-		r.logger.Info("higher or first valid bid received", "bid", bid)
-		return true
+	if r.reservePrice != nil && bid.AmountWei.Cmp(r.reservePrice) < 0 {
+		r.logger.Warn("bid below the reserve price", "bid", bid, "reservePrice", r.reservePrice)
+		return RejectBelowReserve
 	}
+	return ""
+}
 
-	return false
+// Makes bid the leader if it beats the current one, retrying if the leader changed meanwhile.
+// Returns the bid as stored, nil if it doesn't beat the leader.
+func (r *RelayAuction) lead(bid ReceivedBid) *ReceivedBid {
+	for {
+		leader := r.currentBid.Load()
+		if leader != nil && !beats(bid, *leader) {
+			return nil
+		}
+		if r.currentBid.CompareAndSwap(leader, &bid) {
+			return &bid
+		}
+	}
+}
+
+// Ties go to the bid received first, then the lower address. Arrivals are stamped where bids reach the auctioneer
+// and gossiped with them, so replicas receiving bids in different order agree.
+func beats(bid ReceivedBid, leader ReceivedBid) bool {
+	if cmp := bid.AmountWei.Cmp(leader.AmountWei); cmp != 0 {
+		return cmp > 0
+	}
+	if !bid.ReceivedAt.Equal(leader.ReceivedAt) {
+		return bid.ReceivedAt.Before(leader.ReceivedAt)
+	}
+	return bid.Address.Cmp(leader.Address) < 0
 }
 
 func contains(slice []common.Address, item common.Address) bool {
@@ -3,14 +3,20 @@ package auction_test
 import (
 	"blob-preconfs/pkg/auction"
 	"context"
+	"crypto/ecdsa"
 	"fmt"
+	"io"
 	"log/slog"
 	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,7 +46,7 @@ func TestAuctionEndsAfterDuration(t *testing.T) {
 
 			select {
 			case bid := <-auctionResultChan:
-				assert.Nil(t, bid, "Auction should end without any bids")
+				assert.Zero(t, bid, "Auction should end without any bids")
 			case <-time.After(period + 10*time.Millisecond):
 				t.Fatalf("Auction did not end within the expected time")
 			}
@@ -64,7 +70,7 @@ func TestInvalidBidsIgnored(t *testing.T) {
 
 	select {
 	case bid := <-auctionResultChan:
-		assert.Nil(t, bid, "Auction should end without any bids")
+		assert.Zero(t, bid, "Auction should end without any bids")
 	case <-time.After(6 * time.Second):
 		assert.Fail(t, "Auction did not end within the expected time")
 	}
@@ -114,3 +120,628 @@ func TestValidBids(t *testing.T) {
 		assert.Fail(t, "Auction did not end within the expected time")
 	}
 }
+
+func TestAccessListApplied(t *testing.T) {
+	mockRegistry := &mockRegistry{
+		isRegisteredCallback: func(address common.Address) bool {
+			return true
+		},
+	}
+	allowedPk, _ := crypto.GenerateKey()
+	deniedPk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	accessList := auction.DefaultAccessList()
+	accessList.Allow(crypto.PubkeyToAddress(allowedPk.PublicKey))
+	accessList.Deny(crypto.PubkeyToAddress(deniedPk.PublicKey))
+
+	relayAuction := auction.NewRelayAuction(slog.Default(), mockRegistry)
+	relayAuction.SetAccessList(accessList)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auctionResultChan := relayAuction.StartAsync(ctx, 500*time.Millisecond)
+
+	allowed := auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), allowedPk)
+	relayAuction.SubmitBid(*allowed)
+	relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(200), big.NewInt(999), deniedPk))
+
+	select {
+	case bid := <-auctionResultChan:
+		assert.Equal(t, allowed.Address, bid.Address, "Denied bidder should not win despite a higher bid")
+	case <-time.After(time.Second):
+		assert.Fail(t, "Auction did not end within the expected time")
+	}
+}
+
+func TestReservePrice(t *testing.T) {
+	mockRegistry := &mockRegistry{
+		isRegisteredCallback: func(address common.Address) bool {
+			return true
+		},
+	}
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	relayAuction := auction.NewRelayAuction(slog.Default(), mockRegistry)
+	relayAuction.SetReservePrice(big.NewInt(100))
+	var feed event.Feed
+	rejections := make(chan auction.Rejection, 1)
+	sub := feed.Subscribe(rejections)
+	defer sub.Unsubscribe()
+	relayAuction.SetRejectionFeed(&feed)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auctionResultChan := relayAuction.StartAsync(ctx, 300*time.Millisecond)
+
+	relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(99), big.NewInt(999), pk1))
+	atReserve := auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk2)
+	relayAuction.SubmitBid(*atReserve)
+	assert.Equal(t, auction.RejectBelowReserve, (<-rejections).Code)
+
+	select {
+	case bid := <-auctionResultChan:
+		assert.Equal(t, *atReserve, bid)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Auction did not end within the expected time")
+	}
+}
+
+func TestTiedBidsGoToFirstReceived(t *testing.T) {
+	mockRegistry := &mockRegistry{
+		isRegisteredCallback: func(address common.Address) bool {
+			return true
+		},
+	}
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	bid1 := auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk1)
+	bid2 := auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk2)
+	lower, higher := bid1, bid2
+	if bid2.Address.Cmp(bid1.Address) < 0 {
+		lower, higher = bid2, bid1
+	}
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name     string
+		arrivals map[*auction.SignedBid]time.Time
+		winner   common.Address
+	}{
+		{"earlier arrival", map[*auction.SignedBid]time.Time{lower: now, higher: now.Add(-time.Millisecond)}, higher.Address},
+		{"same arrival", map[*auction.SignedBid]time.Time{lower: now, higher: now}, lower.Address},
+	} {
+		// Replicas receiving the same bids in different order must agree on the winner
+		for _, order := range [][]*auction.SignedBid{{bid1, bid2}, {bid2, bid1}} {
+			relayAuction := auction.NewRelayAuction(slog.Default(), mockRegistry)
+			ctx, cancel := context.WithCancel(context.Background())
+			auctionResultChan := relayAuction.StartAsync(ctx, 300*time.Millisecond)
+			for _, bid := range order {
+				relayAuction.SubmitBidAt(*bid, tc.arrivals[bid])
+			}
+			select {
+			case bid := <-auctionResultChan:
+				assert.Equal(t, tc.winner, bid.Address, tc.name)
+			case <-time.After(time.Second):
+				assert.Fail(t, "Auction did not end within the expected time")
+			}
+			cancel()
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	results := relayAuction.StartAsync(context.Background(), 300*time.Millisecond)
+	relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk))
+	relayAuction.Cancel()
+	relayAuction.Cancel()
+	select {
+	case bid := <-results:
+		t.Fatalf("cancelled auction sent a winner: %v", bid)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	notStarted := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	notStarted.Cancel()
+	select {
+	case bid := <-notStarted.StartAsync(context.Background(), 100*time.Millisecond):
+		t.Fatalf("auction cancelled before it started sent a winner: %v", bid)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestRankedBids(t *testing.T) {
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := relayAuction.StartAsync(ctx, 300*time.Millisecond)
+
+	invalid := *auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk2)
+	invalid.AmountWei = big.NewInt(500)
+	for _, bid := range []auction.SignedBid{
+		*auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk1),
+		*auction.MustCreateSignedBid(big.NewInt(150), big.NewInt(999), pk2),
+		*auction.MustCreateSignedBid(big.NewInt(120), big.NewInt(999), pk1), // outbid, still the relay's best
+		*auction.MustCreateSignedBid(big.NewInt(110), big.NewInt(999), pk2),
+		invalid,
+	} {
+		relayAuction.SubmitBid(bid)
+	}
+	winner := <-results
+	ranked := relayAuction.Ranked()
+	assert.Len(t, ranked, 2, "one bid per relay")
+	assert.Equal(t, winner, ranked[0])
+	assert.Equal(t, big.NewInt(150), ranked[0].AmountWei)
+	assert.Equal(t, big.NewInt(120), ranked[1].AmountWei)
+	assert.Equal(t, crypto.PubkeyToAddress(pk1.PublicKey), ranked[1].Address)
+}
+
+type mockAuditor struct {
+	mu      sync.Mutex
+	reasons []string
+}
+
+func (m *mockAuditor) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if accepted {
+		reason = "accepted"
+	}
+	m.reasons = append(m.reasons, reason)
+}
+
+func TestBidsAudited(t *testing.T) {
+	mockRegistry := &mockRegistry{
+		isRegisteredCallback: func(address common.Address) bool {
+			return true
+		},
+	}
+	allowedPk, _ := crypto.GenerateKey()
+	deniedPk, _ := crypto.GenerateKey()
+	outsiderPk, _ := crypto.GenerateKey()
+	accessList := auction.NewAccessList([]common.Address{crypto.PubkeyToAddress(allowedPk.PublicKey)}, []common.Address{crypto.PubkeyToAddress(deniedPk.PublicKey)})
+	auditor := &mockAuditor{}
+
+	relayAuction := auction.NewRelayAuction(slog.Default(), mockRegistry)
+	relayAuction.SetAccessList(accessList)
+	relayAuction.SetAuditor(auditor)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auctionResultChan := relayAuction.StartAsync(ctx, 300*time.Millisecond)
+
+	tampered := *auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), allowedPk)
+	tampered.AmountWei = big.NewInt(101)
+	for _, bid := range []auction.SignedBid{
+		*auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), allowedPk),
+		*auction.MustCreateSignedBid(big.NewInt(99), big.NewInt(999), allowedPk),
+		*auction.MustCreateSignedBid(big.NewInt(200), big.NewInt(999), deniedPk),
+		*auction.MustCreateSignedBid(big.NewInt(200), big.NewInt(999), outsiderPk),
+		tampered,
+	} {
+		relayAuction.SubmitBid(bid)
+	}
+	select {
+	case <-auctionResultChan:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Auction did not end within the expected time")
+	}
+	auditor.mu.Lock()
+	defer auditor.mu.Unlock()
+	assert.Equal(t, []string{
+		"accepted",
+		"bid does not beat the leading bid",
+		"bidder on denylist",
+		"bidder not on whitelist",
+		"invalid signature",
+	}, auditor.reasons)
+}
+
+func TestRejectionsPublished(t *testing.T) {
+	mockRegistry := &mockRegistry{
+		isRegisteredCallback: func(address common.Address) bool {
+			return true
+		},
+	}
+	leaderPk, _ := crypto.GenerateKey()
+	loserPk, _ := crypto.GenerateKey()
+	accessList := auction.NewAccessList([]common.Address{crypto.PubkeyToAddress(leaderPk.PublicKey), crypto.PubkeyToAddress(loserPk.PublicKey)}, nil)
+	var feed event.Feed
+	rejections := make(chan auction.Rejection, 8)
+	sub := feed.Subscribe(rejections)
+	defer sub.Unsubscribe()
+
+	relayAuction := auction.NewRelayAuction(slog.Default(), mockRegistry)
+	relayAuction.SetAccessList(accessList)
+	relayAuction.SetRejectionFeed(&feed)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auctionResultChan := relayAuction.StartAsync(ctx, 300*time.Millisecond)
+
+	leader := auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), leaderPk)
+	relayAuction.SubmitBid(*leader)
+	relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(999), loserPk))
+	relayAuction.SubmitBid(*leader)
+	select {
+	case <-auctionResultChan:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Auction did not end within the expected time")
+	}
+
+	outbid := <-rejections
+	assert.Equal(t, crypto.PubkeyToAddress(loserPk.PublicKey), outbid.Bid.Address)
+	assert.Equal(t, auction.RejectOutbid, outbid.Code)
+	assert.Equal(t, "bid does not beat the leading bid", outbid.Reason)
+	assert.Equal(t, leader.Signature, outbid.Leader.Signature)
+	duplicate := <-rejections
+	assert.Equal(t, auction.RejectDuplicate, duplicate.Code)
+	assert.Empty(t, rejections)
+}
+
+type mockMetrics struct {
+	mu     sync.Mutex
+	stages map[auction.BidStage][]time.Duration
+	depths []int
+}
+
+func (m *mockMetrics) ObserveBidVerification(duration time.Duration, valid bool) {}
+
+func (m *mockMetrics) ObserveBidLatency(stage auction.BidStage, latency time.Duration, bid auction.SignedBid) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stages[stage] = append(m.stages[stage], latency)
+}
+
+func (m *mockMetrics) ObserveBidQueueDepth(depth int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.depths = append(m.depths, depth)
+}
+
+func TestBidLatencyObserved(t *testing.T) {
+	mockRegistry := &mockRegistry{
+		isRegisteredCallback: func(address common.Address) bool {
+			return true
+		},
+	}
+	pk, _ := crypto.GenerateKey()
+	metrics := &mockMetrics{stages: make(map[auction.BidStage][]time.Duration)}
+	relayAuction := auction.NewRelayAuction(slog.Default(), mockRegistry)
+	relayAuction.SetAccessList(auction.NewAccessList([]common.Address{crypto.PubkeyToAddress(pk.PublicKey)}, nil))
+	relayAuction.SetMetrics(metrics)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auctionResultChan := relayAuction.StartAsync(ctx, 300*time.Millisecond)
+	relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk))
+	relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(99), big.NewInt(999), pk))
+	select {
+	case <-auctionResultChan:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Auction did not end within the expected time")
+	}
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Len(t, metrics.stages[auction.BidStageVerified], 2)
+	assert.Len(t, metrics.stages[auction.BidStageAccepted], 1, "only the leader is accepted")
+	assert.GreaterOrEqual(t, metrics.stages[auction.BidStageAccepted][0], metrics.stages[auction.BidStageVerified][0])
+}
+
+func TestLeaderReadsDuringBidding(t *testing.T) {
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := relayAuction.StartAsync(ctx, 500*time.Millisecond)
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			last := big.NewInt(0)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if leader := relayAuction.GetCurrentBid(); leader.AmountWei != nil {
+					assert.True(t, leader.Verify(), "leaders are read whole")
+					assert.GreaterOrEqual(t, leader.AmountWei.Cmp(last), 0, "the leading amount never decreases")
+					last = leader.AmountWei
+				}
+			}
+		}()
+	}
+	for amount := int64(1); amount <= 50; amount++ {
+		relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(100), pk))
+	}
+	winner := <-results
+	close(done)
+	readers.Wait()
+	assert.Equal(t, big.NewInt(50), winner.AmountWei)
+}
+
+func TestEarlyClose(t *testing.T) {
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	for name, shards := range map[string]int{"ordered": 0, "sharded": 4} {
+		t.Run(name, func(t *testing.T) {
+			relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+			relayAuction.SetShards(shards)
+			relayAuction.SetEarlyClose(200*time.Millisecond, 300*time.Millisecond)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			opened := time.Now()
+			results := relayAuction.StartAsync(ctx, 5*time.Second)
+
+			relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk))
+			time.Sleep(250 * time.Millisecond)
+			// Raises the leader before bids quiesce, delaying the close
+			relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(200), big.NewInt(999), pk))
+			select {
+			case winner := <-results:
+				assert.Equal(t, big.NewInt(200), winner.AmountWei)
+				assert.GreaterOrEqual(t, time.Since(opened), 550*time.Millisecond)
+				assert.Less(t, time.Since(opened), time.Second)
+			case <-time.After(time.Second):
+				assert.Fail(t, "Auction did not close early")
+			}
+		})
+	}
+}
+
+func TestNoEarlyCloseWithoutLeader(t *testing.T) {
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{})
+	relayAuction.SetEarlyClose(0, 50*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opened := time.Now()
+	assert.Zero(t, <-relayAuction.StartAsync(ctx, 300*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(opened), 300*time.Millisecond, "runs the full period")
+}
+
+func TestDuplicateBidsRejected(t *testing.T) {
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	auditor := &mockAuditor{}
+	relayAuction.SetAuditor(auditor)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := relayAuction.StartAsync(ctx, 300*time.Millisecond)
+
+	bid := *auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), pk)
+	tampered := bid
+	tampered.AmountWei = big.NewInt(200)
+	for _, bid := range []auction.SignedBid{tampered, bid, bid} {
+		relayAuction.SubmitBid(bid)
+	}
+	assert.Equal(t, big.NewInt(100), (<-results).AmountWei)
+	auditor.mu.Lock()
+	defer auditor.mu.Unlock()
+	assert.Equal(t, []string{"invalid signature", "accepted", "duplicate bid"}, auditor.reasons, "invalid bids don't count as seen")
+}
+
+func TestBidsEvaluatedInSubmissionOrder(t *testing.T) {
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	auditor := &mockAuditor{}
+	relayAuction.SetAuditor(auditor)
+	relayAuction.SetVerifiers(8)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := relayAuction.StartAsync(ctx, 500*time.Millisecond)
+
+	var expected []string
+	for amount := int64(1); amount <= 60; amount++ {
+		bid := *auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(100), pk)
+		if amount%3 == 0 {
+			bid.AmountWei = big.NewInt(amount + 1000)
+			expected = append(expected, "invalid signature")
+		} else {
+			expected = append(expected, "accepted")
+		}
+		relayAuction.SubmitBid(bid)
+	}
+	winner := <-results
+	assert.Equal(t, big.NewInt(59), winner.AmountWei)
+	auditor.mu.Lock()
+	defer auditor.mu.Unlock()
+	assert.Equal(t, expected, auditor.reasons, "every increasing valid bid leads, in the order submitted")
+}
+
+func TestSubmitBlocksOnFullQueue(t *testing.T) {
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	metrics := &mockMetrics{stages: make(map[auction.BidStage][]time.Duration)}
+	relayAuction.SetMetrics(metrics)
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+
+	// Nothing is consumed until the auction starts
+	submitted := make(chan struct{})
+	go func() {
+		defer close(submitted)
+		for amount := int64(1); amount <= 200; amount++ {
+			relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(100), pk))
+		}
+	}()
+	select {
+	case <-submitted:
+		assert.Fail(t, "Submitting didn't block on a full queue")
+	case <-time.After(200 * time.Millisecond):
+	}
+	assert.Greater(t, relayAuction.QueueDepth(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := relayAuction.StartAsync(ctx, 500*time.Millisecond)
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Submitting didn't resume once the auction started")
+	}
+	winner := <-results
+	assert.Equal(t, big.NewInt(200), winner.AmountWei)
+	assert.Equal(t, 0, relayAuction.QueueDepth())
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	assert.Equal(t, 0, metrics.depths[len(metrics.depths)-1])
+}
+
+func TestSubmitReleasedOnClose(t *testing.T) {
+	for _, shards := range []int{0, 4} {
+		t.Run(fmt.Sprintf("%d shards", shards), func(t *testing.T) {
+			// Evaluation stalls on the first bid, so the queue fills up
+			release := make(chan struct{})
+			defer close(release)
+			relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+			relayAuction.SetAuditor(blockingAuditor(release))
+			relayAuction.SetShards(shards)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			relayAuction.StartAsync(ctx, 200*time.Millisecond)
+
+			var wg sync.WaitGroup
+			var closedErrs atomic.Int32
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					pk, _ := crypto.GenerateKey()
+					for amount := int64(1); amount <= 100; amount++ {
+						if err := relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(100), pk)); err != nil {
+							assert.ErrorIs(t, err, auction.ErrNoActiveAuction)
+							closedErrs.Add(1)
+							return
+						}
+					}
+				}()
+			}
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("submitters still blocked after the auction closed")
+			}
+			assert.Equal(t, int32(8), closedErrs.Load(), "submitting fails once bidding is over")
+			assert.ErrorIs(t, relayAuction.SubmitBid(auction.SignedBid{}), auction.ErrNoActiveAuction)
+		})
+	}
+}
+
+type blockingAuditor chan struct{}
+
+func (b blockingAuditor) RecordBid(bid auction.SignedBid, accepted bool, reason string) { <-b }
+
+func TestSubmitAfterCancel(t *testing.T) {
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{})
+	relayAuction.Cancel()
+	assert.ErrorIs(t, relayAuction.SubmitBid(auction.SignedBid{}), auction.ErrNoActiveAuction, "even if never started")
+}
+
+// Counts evaluated bids, signalling once every bid submitted was
+type countingAuditor struct {
+	remaining int
+	done      chan struct{}
+}
+
+func (c *countingAuditor) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	if c.remaining--; c.remaining == 0 {
+		close(c.done)
+	}
+}
+
+type latencyMetrics struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+}
+
+func (m *latencyMetrics) ObserveBidVerification(duration time.Duration, valid bool) {}
+
+func (m *latencyMetrics) ObserveBidQueueDepth(depth int) {}
+
+func (m *latencyMetrics) ObserveBidLatency(stage auction.BidStage, latency time.Duration, bid auction.SignedBid) {
+	if stage == auction.BidStageAccepted {
+		m.mu.Lock()
+		m.latencies = append(m.latencies, latency)
+		m.mu.Unlock()
+	}
+}
+
+// Blocks bid for by benchmarks, never repeated across runs
+var benchmarkBlock atomic.Int64
+
+// Increasing bids for a fresh block, so none are served from the recovered signer cache and each becomes the leader
+func benchmarkBids(b *testing.B, pk *ecdsa.PrivateKey, n int) []auction.SignedBid {
+	b.Helper()
+	block := big.NewInt(benchmarkBlock.Add(1))
+	bids := make([]auction.SignedBid, n)
+	for i := range bids {
+		bids[i] = *auction.MustCreateSignedBid(big.NewInt(int64(i+1)), block, pk)
+	}
+	return bids
+}
+
+// Runs one auction of the given bids, returning once every bid is evaluated
+func runBenchmarkAuction(b *testing.B, pk *ecdsa.PrivateKey, bids []auction.SignedBid, metrics auction.Metrics) {
+	b.Helper()
+	relayAuction := auction.NewRelayAuction(slog.New(slog.NewTextHandler(io.Discard, nil)), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	relayAuction.SetAccessList(auction.NewAccessList([]common.Address{crypto.PubkeyToAddress(pk.PublicKey)}, nil))
+	auditor := &countingAuditor{remaining: len(bids), done: make(chan struct{})}
+	relayAuction.SetAuditor(auditor)
+	if metrics != nil {
+		relayAuction.SetMetrics(metrics)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	relayAuction.StartAsync(ctx, time.Minute)
+	for _, bid := range bids {
+		relayAuction.SubmitBid(bid)
+	}
+	<-auditor.done
+	if leader := relayAuction.GetCurrentBid(); leader.AmountWei.Cmp(bids[len(bids)-1].AmountWei) != 0 {
+		b.Fatalf("leader %s, expected the last bid", leader.AmountWei)
+	}
+}
+
+// Bids through SubmitBid, verification and leader updates, per second. Compare runs with benchstat,
+// e.g. go test ./pkg/auction -run '^$' -bench BenchmarkBidThroughput -count 10.
+func BenchmarkBidThroughput(b *testing.B) {
+	pk, _ := crypto.GenerateKey()
+	for _, load := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("bids=%d", load), func(b *testing.B) {
+			var elapsed time.Duration
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				bids := benchmarkBids(b, pk, load)
+				b.StartTimer()
+				started := time.Now()
+				runBenchmarkAuction(b, pk, bids, nil)
+				elapsed += time.Since(started)
+			}
+			b.ReportMetric(float64(load*b.N)/elapsed.Seconds(), "bids/s")
+		})
+	}
+}
+
+// Latency from each bid's submission to it leading the auction, as percentiles across every bid
+func BenchmarkAuctionResolution(b *testing.B) {
+	pk, _ := crypto.GenerateKey()
+	for _, load := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("bids=%d", load), func(b *testing.B) {
+			metrics := &latencyMetrics{}
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				bids := benchmarkBids(b, pk, load)
+				b.StartTimer()
+				runBenchmarkAuction(b, pk, bids, metrics)
+			}
+			latencies := metrics.latencies
+			sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+			b.ReportMetric(float64(latencies[len(latencies)/2].Nanoseconds()), "p50-ns")
+			b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns")
+		})
+	}
+}
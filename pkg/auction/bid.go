@@ -3,12 +3,15 @@ package auction
 import (
 	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -27,14 +30,10 @@ func CreateSignedBid(amountWei *big.Int, l1Block *big.Int, privateKey *ecdsa.Pri
 	if err != nil {
 		return nil, err
 	}
-	address, err := getAddressFromSig(signature, hash)
-	if err != nil {
-		return nil, err
-	}
 	return &SignedBid{
 		AmountWei: amountWei,
 		L1Block:   l1Block,
-		Address:   address,
+		Address:   crypto.PubkeyToAddress(privateKey.PublicKey),
 		Signature: signature,
 	}, nil
 }
@@ -47,16 +46,51 @@ func MustCreateSignedBid(amountWei *big.Int, l1Block *big.Int, privateKey *ecdsa
 	return bid
 }
 
+// Whether the bid is signed by its address. Bids missing an amount or block never verify.
 func (b *SignedBid) Verify() bool {
+	if b.AmountWei == nil || b.L1Block == nil {
+		return false
+	}
 	hash := getDataHash(b.AmountWei, b.L1Block)
-	sigPublicKey, err := crypto.SigToPub(hash.Bytes(), b.Signature)
+	signerAddress, err := getAddressFromSig(b.Signature, hash)
 	if err != nil {
 		return false
 	}
-	signerAddress := crypto.PubkeyToAddress(*sigPublicKey)
 	return signerAddress == b.Address
 }
 
+// Identifies the bid by its bidder, amount and signature, e.g. in receipts and a result's bids root (see bulletin).
+// A missing amount hashes as zero, so unvalidated bids can still be hashed, e.g. for logging.
+func (b *SignedBid) Hash() common.Hash {
+	var amount common.Hash
+	if b.AmountWei != nil {
+		amount = common.BigToHash(b.AmountWei)
+	}
+	data := append(b.Address.Bytes(), amount.Bytes()...)
+	data = append(data, b.Signature...)
+	return crypto.Keccak256Hash(data)
+}
+
+// Malformed bid, as opposed to a well formed one rejected by the auction, see RejectError
+var ErrInvalidBid = errors.New("invalid bid")
+
+// Checks the bid is well formed and signed by its address. Errors wrap ErrInvalidBid.
+func (b *SignedBid) Validate() error {
+	if b.AmountWei == nil || b.AmountWei.Sign() <= 0 {
+		return fmt.Errorf("%w: invalid amountWei", ErrInvalidBid)
+	}
+	if b.L1Block == nil || b.L1Block.Sign() < 0 {
+		return fmt.Errorf("%w: invalid l1Block", ErrInvalidBid)
+	}
+	if len(b.Signature) != crypto.SignatureLength {
+		return fmt.Errorf("%w: invalid signature length %d", ErrInvalidBid, len(b.Signature))
+	}
+	if !b.Verify() {
+		return fmt.Errorf("%w: signature does not match address", ErrInvalidBid)
+	}
+	return nil
+}
+
 func EncodeSignedBid(bid *SignedBid) string {
 	jsonData, err := json.Marshal(bid)
 	if err != nil {
@@ -75,15 +109,54 @@ func DecodeSignedBid(jsonData string) (*SignedBid, error) {
 	return &bid, nil
 }
 
+// Scratch buffers for the signed data, sized for a uint256 amount and block in decimal, and hashers,
+// reused across bids as every bid is hashed at least once
+var (
+	dataBuffers = sync.Pool{New: func() any { buf := make([]byte, 0, 2*78); return &buf }}
+	hashers     = sync.Pool{New: func() any { return crypto.NewKeccakState() }}
+)
+
+// Keccak of the amount and block in decimal, concatenated. Missing values hash as "<nil>".
 func getDataHash(amountWei *big.Int, l1Block *big.Int) common.Hash {
-	data := fmt.Sprintf("%s%s", amountWei.String(), l1Block.String())
-	return crypto.Keccak256Hash([]byte(data))
+	buf := dataBuffers.Get().(*[]byte)
+	data := amountWei.Append((*buf)[:0], 10)
+	data = l1Block.Append(data, 10)
+
+	hasher := hashers.Get().(crypto.KeccakState)
+	hasher.Reset()
+	hasher.Write(data)
+	var hash common.Hash
+	hasher.Read(hash[:])
+	hashers.Put(hasher)
+
+	*buf = data
+	dataBuffers.Put(buf)
+	return hash
+}
+
+// Signers recovered from recent signatures, so the same bid verified again, e.g. by relay APIs, the auction
+// and settlement, skips recovery. Keyed by the signed hash too, as a signature recovers a different address
+// for every hash.
+var recoveredSigners = lru.NewCache[recoveryKey, common.Address](4096)
+
+type recoveryKey struct {
+	hash      common.Hash
+	signature [crypto.SignatureLength]byte
 }
 
 func getAddressFromSig(signature hexutil.Bytes, hash common.Hash) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	key := recoveryKey{hash: hash, signature: [crypto.SignatureLength]byte(signature)}
+	if address, ok := recoveredSigners.Get(key); ok {
+		return address, nil
+	}
 	sigPublicKey, err := crypto.SigToPub(hash.Bytes(), signature)
 	if err != nil {
 		return common.Address{}, err
 	}
-	return crypto.PubkeyToAddress(*sigPublicKey), nil
+	address := crypto.PubkeyToAddress(*sigPublicKey)
+	recoveredSigners.Add(key, address)
+	return address, nil
 }
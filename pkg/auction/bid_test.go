@@ -42,6 +42,49 @@ func TestVerifySignedBid(t *testing.T) {
 		Signature: hexutil.MustDecode(sig),
 	}
 	assert.True(t, signedBid.Verify())
+
+	// Would otherwise verify, as missing values hash as "<nil>"
+	assert.False(t, auction.MustCreateSignedBid(nil, nil, privateKey).Verify())
+}
+
+func TestHashMissingAmount(t *testing.T) {
+	bid := auction.MustCreateSignedBid(big.NewInt(0), big.NewInt(1234567), privateKey)
+	missing := *bid
+	missing.AmountWei = nil
+	assert.Equal(t, bid.Hash(), missing.Hash())
+}
+
+func TestVerifyRecoveredSignerCached(t *testing.T) {
+	bid := auction.MustCreateSignedBid(big.NewInt(500), big.NewInt(100), privateKey)
+	assert.True(t, bid.Verify())
+	assert.True(t, bid.Verify(), "verifies again from the cache")
+
+	// The cached signer is only reused for the same signed hash
+	tampered := *bid
+	tampered.AmountWei = big.NewInt(501)
+	assert.False(t, tampered.Verify())
+	assert.True(t, bid.Verify())
+}
+
+func BenchmarkVerify(b *testing.B) {
+	bid := auction.MustCreateSignedBid(big.NewInt(500), big.NewInt(100), privateKey)
+	b.Run("repeated", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			bid.Verify()
+		}
+	})
+	b.Run("distinct", func(b *testing.B) {
+		bids := make([]*auction.SignedBid, 8192)
+		for i := range bids {
+			bids[i] = auction.MustCreateSignedBid(big.NewInt(int64(i+1)), big.NewInt(100), privateKey)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			bids[i%len(bids)].Verify()
+		}
+	})
 }
 
 func TestEncodeSignedBid(t *testing.T) {
@@ -64,3 +107,83 @@ func TestDecodeSignedBid(t *testing.T) {
 	assert.Equal(t, "0x654f553afe2f8eca87582a23817e40a3cdff28e07995136503a999bc5d18b8f62857d603662355e6bc4963cccd426298d771c065d8d9aa880345dc88a4e791ce00", hexutil.Encode(signedBid.Signature))
 	assert.True(t, signedBid.Verify())
 }
+
+func TestValidateSignedBid(t *testing.T) {
+	signedBid, err := auction.CreateSignedBid(big.NewInt(677), big.NewInt(1234567), privateKey)
+	assert.NoError(t, err)
+	assert.NoError(t, signedBid.Validate())
+
+	zeroAmount, err := auction.CreateSignedBid(big.NewInt(0), big.NewInt(1234567), privateKey)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, zeroAmount.Validate(), "invalid amountWei")
+	assert.ErrorIs(t, zeroAmount.Validate(), auction.ErrInvalidBid)
+
+	truncated := *signedBid
+	truncated.Signature = truncated.Signature[:64]
+	assert.ErrorContains(t, truncated.Validate(), "invalid signature length")
+
+	tampered := *signedBid
+	tampered.L1Block = big.NewInt(1234568)
+	assert.ErrorContains(t, tampered.Validate(), "signature does not match address")
+}
+
+const validBidJSON = `{"amountWei":677,"l1Block":1234567,"address":"0xdefea225c9e43f1a4ccb561867be9c9bf3142a98","signature":"0x654f553afe2f8eca87582a23817e40a3cdff28e07995136503a999bc5d18b8f62857d603662355e6bc4963cccd426298d771c065d8d9aa880345dc88a4e791ce00"}`
+
+// Bids decoded from untrusted JSON are never accepted unless well formed, and round trip unchanged
+func FuzzDecodeSignedBid(f *testing.F) {
+	f.Add(validBidJSON)
+	f.Add(`{"amountWei":677,"l1Block":1234567,"address":"0xdefea225c9e43f1a4ccb561867be9c9bf3142a98"}`)
+	f.Add(`{"amountWei":-1,"l1Block":"0x10","signature":"0x"}`)
+	f.Add(`{"amountWei":1e400,"l1Block":null}`)
+	f.Add(`null`)
+	f.Fuzz(func(t *testing.T, data string) {
+		bid, err := auction.DecodeSignedBid(data)
+		if err != nil {
+			return
+		}
+		verified := bid.Verify()
+		if err := bid.Validate(); err != nil {
+			return
+		}
+		if !verified {
+			t.Fatalf("valid bid doesn't verify: %s", data)
+		}
+		decoded, err := auction.DecodeSignedBid(auction.EncodeSignedBid(bid))
+		if err != nil {
+			t.Fatalf("valid bid doesn't round trip: %v", err)
+		}
+		assert.Equal(t, *bid, *decoded)
+		assert.NoError(t, decoded.Validate())
+	})
+}
+
+// Verification never panics, and only accepts the signer of the exact amount and block
+func FuzzVerify(f *testing.F) {
+	bid := auction.MustCreateSignedBid(big.NewInt(677), big.NewInt(1234567), privateKey)
+	f.Add(bid.AmountWei.Bytes(), bid.L1Block.Bytes(), []byte(bid.Signature), bid.Address.Bytes(), false, false)
+	f.Add([]byte{}, []byte{}, []byte{}, []byte{}, true, true)
+	f.Add(bid.AmountWei.Bytes(), bid.L1Block.Bytes(), append([]byte(bid.Signature), 0x00), bid.Address.Bytes(), false, true)
+	f.Fuzz(func(t *testing.T, amount []byte, l1Block []byte, signature []byte, address []byte, nilAmount bool, nilBlock bool) {
+		fuzzed := auction.SignedBid{
+			AmountWei: new(big.Int).SetBytes(amount),
+			L1Block:   new(big.Int).SetBytes(l1Block),
+			Address:   common.BytesToAddress(address),
+			Signature: signature,
+		}
+		if nilAmount {
+			fuzzed.AmountWei = nil
+		}
+		if nilBlock {
+			fuzzed.L1Block = nil
+		}
+		if !fuzzed.Verify() {
+			return
+		}
+		if fuzzed.AmountWei == nil || fuzzed.L1Block == nil {
+			t.Fatal("bid without an amount or block verified")
+		}
+		if fuzzed.Address == bid.Address && (fuzzed.AmountWei.Cmp(bid.AmountWei) != 0 || fuzzed.L1Block.Cmp(bid.L1Block) != 0) {
+			t.Fatalf("signature verified for amount %s block %s", fuzzed.AmountWei, fuzzed.L1Block)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package auction
+
+import (
+	"math/big"
+	"time"
+
+	"blob-preconfs/pkg/version"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type EventType string
+
+const (
+	EventAuctionOpened EventType = "auctionOpened"
+	EventLeaderChanged EventType = "leaderChanged"
+	EventAuctionClosed EventType = "auctionClosed"
+	// Published by the settlement worker, once the winner is settled on the settlement layer
+	EventSettlement EventType = "settlement"
+	// Published by the settlement worker when settling the winner fails, with the error
+	EventSettlementFailed EventType = "settlementFailed"
+	// Published when the winner fails at a stage and the auction falls back to the next highest bid, the new
+	// winner, with the failed relay, stage and reason
+	EventWinnerFallback EventType = "winnerFallback"
+	// Published once per beacon chain slot with the auctioneer's signed heartbeat, see heartbeat.Beacon
+	EventHeartbeat EventType = "heartbeat"
+	// Published when no block was proposed in the slot a won auction was for, with the winner and the auction's
+	// outcome
+	EventSlotMissed EventType = "slotMissed"
+	// Published when a relay's bond runs low with its pending wins and its bids are capped until it tops up, and
+	// again if the cap changes
+	EventBondLow EventType = "bondLow"
+	// Published when a low bond is topped up or its wins settle, and the relay's bids are no longer capped
+	EventBondRestored EventType = "bondRestored"
+)
+
+// Stage of the hand-off to settlement a winner failed at, for winnerFallback events
+type FallbackStage string
+
+const (
+	// The winner defaulted on its award, declining it or not accepting it in time
+	StageAcceptance FallbackStage = "acceptance"
+	// Settling the winner's payment failed
+	StagePayment FallbackStage = "payment"
+)
+
+// What becomes of a won auction whose slot was missed, a proposer fault the winner isn't held to, for slotMissed
+// events
+type SlotOutcome string
+
+const (
+	// The win is voided and the winner isn't charged
+	SlotRefund SlotOutcome = "refund"
+	// The win stands and carries over to the next block proposed, e.g. with its commitments escalated
+	SlotCarryOver SlotOutcome = "carryOver"
+)
+
+// A relay's bond and the cap on its bids, for bondLow and bondRestored events, see escrow.Prompter
+type BondStatus struct {
+	Relay      common.Address `json:"relay"`
+	BalanceWei *big.Int       `json:"balanceWei"`
+	// Winning bids not yet settled, which will be debited from the balance
+	PendingDebitsWei *big.Int `json:"pendingDebitsWei"`
+	UnsettledWins    int      `json:"unsettledWins"`
+	// Highest bid accepted from the relay until it tops up, nil once restored
+	CapWei *big.Int `json:"capWei,omitempty"`
+}
+
+// Auction lifecycle event, published on the listener's event feed
+type Event struct {
+	Type    EventType `json:"type"`
+	L1Block *big.Int  `json:"l1Block"`
+	// New leading bid for leaderChanged, winning bid for auctionClosed (nil if no winner), winnerFallback,
+	// settlement and slotMissed
+	Bid       *SignedBid `json:"bid,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+	// Lowest bid the auction accepts, for auctionOpened events, nil if there's none
+	ReservePriceWei *big.Int `json:"reservePriceWei,omitempty"`
+	// When the auction's bidding period ends, for auctionOpened events. Auctions closing early once bidding quiets
+	// down may close before.
+	ClosesAt *time.Time `json:"closesAt,omitempty"`
+	// Settlement layer tx finalizing the auction, for settlement events
+	SettlementTx *common.Hash `json:"settlementTx,omitempty"`
+	// Failure reason, for settlementFailed and winnerFallback events
+	Error string `json:"error,omitempty"`
+	// Winner that failed and the stage it failed at, for winnerFallback events
+	Failed *common.Address `json:"failed,omitempty"`
+	Stage  FallbackStage   `json:"stage,omitempty"`
+	// Outcome of the won auction, for slotMissed events
+	Outcome SlotOutcome `json:"outcome,omitempty"`
+	// Auctioneer build that published the event, for settlement events
+	Build *version.Info `json:"build,omitempty"`
+	// For heartbeat events, whose L1Block is the heartbeat's latest auction
+	Heartbeat *Heartbeat `json:"heartbeat,omitempty"`
+	// For bondLow and bondRestored events, whose L1Block is the auction the bond was checked at
+	Bond *BondStatus `json:"bond,omitempty"`
+}
+
+// Whether the event may be delivered to a subscriber streaming as relay, nil if anonymous. Bond events reveal their
+// relay's balance, so are delivered to it alone.
+func (ev Event) DeliverableTo(relay *common.Address) bool {
+	if ev.Type != EventBondLow && ev.Type != EventBondRestored {
+		return true
+	}
+	return relay != nil && ev.Bond != nil && ev.Bond.Relay == *relay
+}
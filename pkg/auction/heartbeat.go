@@ -0,0 +1,81 @@
+package auction
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Auctioneer's signed proof it was up at a beacon chain slot, published once per slot on heartbeat events. A slot
+// with no heartbeat shows the auctioneer was down, and one whose latest auction didn't advance while auctions
+// weren't paused shows an auction was missed or withheld.
+type Heartbeat struct {
+	Slot uint64 `json:"slot"`
+	// Latest auction closed and the hash of its bids, winner and commitments (see crosscheck.Hash), zero if none
+	// has closed since the auctioneer started
+	L1Block   uint64      `json:"l1Block"`
+	StateHash common.Hash `json:"stateHash"`
+	Paused    bool        `json:"paused"`
+	// Auctioneer build, see version
+	Version string `json:"version"`
+	// Unix milliseconds
+	SentAt     int64          `json:"sentAt"`
+	Auctioneer common.Address `json:"auctioneer"`
+	Signature  hexutil.Bytes  `json:"signature"`
+}
+
+func CreateSignedHeartbeat(
+	slot uint64,
+	l1Block uint64,
+	stateHash common.Hash,
+	paused bool,
+	version string,
+	sentAt time.Time,
+	privateKey *ecdsa.PrivateKey,
+) (*Heartbeat, error) {
+	h := Heartbeat{
+		Slot:       slot,
+		L1Block:    l1Block,
+		StateHash:  stateHash,
+		Paused:     paused,
+		Version:    version,
+		SentAt:     sentAt.UnixMilli(),
+		Auctioneer: crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+	signature, err := crypto.Sign(h.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	h.Signature = signature
+	return &h, nil
+}
+
+// Hash of the signed fields
+func (h *Heartbeat) Hash() common.Hash {
+	data := binary.BigEndian.AppendUint64(nil, h.Slot)
+	data = binary.BigEndian.AppendUint64(data, h.L1Block)
+	data = append(data, h.StateHash.Bytes()...)
+	if h.Paused {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	data = binary.BigEndian.AppendUint64(data, uint64(len(h.Version)))
+	data = append(data, h.Version...)
+	data = binary.BigEndian.AppendUint64(data, uint64(h.SentAt))
+	data = append(data, h.Auctioneer.Bytes()...)
+	return crypto.Keccak256Hash(data)
+}
+
+// Checks the heartbeat is signed by its auctioneer, which relays and watchers should check is one they trust
+func (h *Heartbeat) Verify() bool {
+	sigPublicKey, err := crypto.SigToPub(h.Hash().Bytes(), h.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == h.Auctioneer
+}
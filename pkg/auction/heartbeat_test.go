@@ -0,0 +1,38 @@
+package auction_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeat(t *testing.T) {
+	h, err := auction.CreateSignedHeartbeat(100, 12, common.HexToHash("0xabc"), false, "v1.2.0", time.UnixMilli(1700000000000), privateKey)
+	require.NoError(t, err)
+	require.Equal(t, expectedAddr, h.Auctioneer)
+	require.True(t, h.Verify())
+
+	data, err := json.Marshal(h)
+	require.NoError(t, err)
+	var decoded auction.Heartbeat
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, decoded.Verify(), "round trips")
+
+	tampered := *h
+	tampered.Paused = true
+	require.False(t, tampered.Verify())
+	tampered = *h
+	tampered.StateHash = common.HexToHash("0xdef")
+	require.False(t, tampered.Verify())
+	tampered = *h
+	tampered.Slot++
+	require.False(t, tampered.Verify())
+	tampered = *h
+	tampered.Auctioneer = common.HexToAddress("0x1")
+	require.False(t, tampered.Verify())
+}
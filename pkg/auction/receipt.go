@@ -0,0 +1,60 @@
+package auction
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Auctioneer's signed acknowledgment of when a bid reached it, returned to the submitting relay so it can prove its
+// bid arrived, and when, e.g. if it's missing from the auction's published bids (see bulletin) or lost a tie
+type Receipt struct {
+	// Bid's hash, see SignedBid.Hash
+	BidHash common.Hash `json:"bidHash"`
+	L1Block uint64      `json:"l1Block"`
+	// Unix nanoseconds, see ArrivalClock
+	ReceivedAt int64          `json:"receivedAt"`
+	Auctioneer common.Address `json:"auctioneer"`
+	Signature  hexutil.Bytes  `json:"signature"`
+}
+
+func CreateSignedReceipt(bid SignedBid, receivedAt time.Time, privateKey *ecdsa.PrivateKey) (*Receipt, error) {
+	r := Receipt{
+		BidHash:    bid.Hash(),
+		L1Block:    bid.L1Block.Uint64(),
+		ReceivedAt: receivedAt.UnixNano(),
+		Auctioneer: crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+	signature, err := crypto.Sign(r.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	r.Signature = signature
+	return &r, nil
+}
+
+// Hash of the signed fields
+func (r *Receipt) Hash() common.Hash {
+	data := append(r.BidHash.Bytes(), binary.BigEndian.AppendUint64(nil, r.L1Block)...)
+	data = binary.BigEndian.AppendUint64(data, uint64(r.ReceivedAt))
+	data = append(data, r.Auctioneer.Bytes()...)
+	return crypto.Keccak256Hash(data)
+}
+
+// Checks the receipt is signed by its auctioneer, which relays should check is one they trust
+func (r *Receipt) Verify() bool {
+	sigPublicKey, err := crypto.SigToPub(r.Hash().Bytes(), r.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == r.Auctioneer
+}
+
+// Whether the receipt acknowledges bid
+func (r *Receipt) Covers(bid SignedBid) bool {
+	return r.BidHash == bid.Hash()
+}
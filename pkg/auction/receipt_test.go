@@ -0,0 +1,43 @@
+package auction_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceipt(t *testing.T) {
+	bid := *auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(999), privateKey)
+	receivedAt := time.Unix(1700000000, 123456789)
+	r, err := auction.CreateSignedReceipt(bid, receivedAt, privateKey)
+	require.NoError(t, err)
+	require.Equal(t, expectedAddr, r.Auctioneer)
+	require.Equal(t, uint64(999), r.L1Block)
+	require.Equal(t, receivedAt.UnixNano(), r.ReceivedAt)
+	require.True(t, r.Verify())
+	require.True(t, r.Covers(bid))
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	var decoded auction.Receipt
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, decoded.Verify(), "round trips")
+
+	other := *auction.MustCreateSignedBid(big.NewInt(101), big.NewInt(999), privateKey)
+	require.False(t, r.Covers(other))
+
+	tampered := *r
+	tampered.ReceivedAt--
+	require.False(t, tampered.Verify())
+	tampered = *r
+	tampered.BidHash = other.Hash()
+	require.False(t, tampered.Verify())
+	tampered = *r
+	tampered.L1Block++
+	require.False(t, tampered.Verify())
+}
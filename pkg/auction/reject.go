@@ -0,0 +1,98 @@
+package auction
+
+import (
+	"errors"
+	"time"
+)
+
+// Machine readable reason a bid was rejected, stable across releases unlike the audit log's reasons
+type RejectCode string
+
+const (
+	RejectNoAuction        RejectCode = "noAuction"
+	RejectWrongBlock       RejectCode = "wrongBlock"
+	RejectInvalidSignature RejectCode = "invalidSignature"
+	RejectDenied           RejectCode = "denied"
+	RejectNotAllowed       RejectCode = "notAllowed"
+	RejectNotRegistered    RejectCode = "notRegistered"
+	RejectDuplicate        RejectCode = "duplicate"
+	RejectOutbid           RejectCode = "outbid"
+	RejectBelowReserve     RejectCode = "belowReserve"
+	RejectUncovered        RejectCode = "uncovered"
+	RejectOverQuota        RejectCode = "overQuota"
+	RejectOverBondCap      RejectCode = "overBondCap"
+)
+
+var rejectReasons = map[RejectCode]string{
+	RejectNoAuction:        "no auction in progress",
+	RejectWrongBlock:       "bid is for a different block",
+	RejectInvalidSignature: "invalid signature",
+	RejectDenied:           "bidder on denylist",
+	RejectNotAllowed:       "bidder not on whitelist",
+	RejectNotRegistered:    "bidder not registered or prepaid on settlement layer",
+	RejectDuplicate:        "duplicate bid",
+	RejectOutbid:           "bid does not beat the leading bid",
+	RejectBelowReserve:     "bid below the reserve price",
+	RejectUncovered:        "bid exceeds the bidder's escrow balance",
+	RejectOverQuota:        "bidder submitted its quota of bids for the auction",
+	RejectOverBondCap:      "bid exceeds the bidder's cap until it tops up its bond",
+}
+
+// Human readable reason, as recorded by the auditor
+func (c RejectCode) Reason() string {
+	return rejectReasons[c]
+}
+
+// Bid rejected for a reason, returned by bid submissions. Matches the Err variable of its code with errors.Is, and
+// is unwrapped with errors.As by transports mapping it to their stable error codes.
+type RejectError struct {
+	Code RejectCode
+}
+
+func (e *RejectError) Error() string {
+	return e.Code.Reason()
+}
+
+func (e *RejectError) Is(target error) bool {
+	t, ok := target.(*RejectError)
+	return ok && t.Code == e.Code
+}
+
+// Rejection error of the code
+func (c RejectCode) Err() error {
+	return &RejectError{Code: c}
+}
+
+var (
+	ErrNoActiveAuction   = RejectNoAuction.Err()
+	ErrWrongBlock        = RejectWrongBlock.Err()
+	ErrInvalidSignature  = RejectInvalidSignature.Err()
+	ErrDenied            = RejectDenied.Err()
+	ErrNotAllowed        = RejectNotAllowed.Err()
+	ErrUnregisteredRelay = RejectNotRegistered.Err()
+	ErrDuplicate         = RejectDuplicate.Err()
+	ErrOutbid            = RejectOutbid.Err()
+	ErrBelowReserve      = RejectBelowReserve.Err()
+	ErrUncovered         = RejectUncovered.Err()
+	ErrOverQuota         = RejectOverQuota.Err()
+	ErrOverBondCap       = RejectOverBondCap.Err()
+)
+
+// Code of a rejection error, false if err isn't one
+func RejectCodeOf(err error) (RejectCode, bool) {
+	var rejected *RejectError
+	if errors.As(err, &rejected) {
+		return rejected.Code, true
+	}
+	return "", false
+}
+
+// Rejected bid, published on the rejection feed for the bidding relay to debug why it keeps losing
+type Rejection struct {
+	Bid    SignedBid  `json:"bid"`
+	Code   RejectCode `json:"code"`
+	Reason string     `json:"reason"`
+	// Leading bid when the bid was rejected, nil if there was none or no auction for the bid's block
+	Leader    *SignedBid `json:"leader,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
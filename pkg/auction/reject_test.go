@@ -0,0 +1,23 @@
+package auction_test
+
+import (
+	"fmt"
+	"testing"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectError(t *testing.T) {
+	err := fmt.Errorf("upstream rejected bid: %w", auction.RejectBelowReserve.Err())
+	require.ErrorIs(t, err, auction.ErrBelowReserve)
+	require.NotErrorIs(t, err, auction.ErrOutbid)
+	require.EqualError(t, auction.ErrUnregisteredRelay, "bidder not registered or prepaid on settlement layer")
+
+	code, ok := auction.RejectCodeOf(err)
+	require.True(t, ok)
+	require.Equal(t, auction.RejectBelowReserve, code)
+	_, ok = auction.RejectCodeOf(auction.ErrInvalidBid)
+	require.False(t, ok, "malformed bids aren't rejected by the auction")
+}
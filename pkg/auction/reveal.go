@@ -0,0 +1,34 @@
+package auction
+
+import (
+	"context"
+	"time"
+)
+
+// Reveals bids sealed while the auction was open, e.g. encrypted bids, once it closes. Revealed bids keep the
+// arrival of their sealed submission.
+type Revealer interface {
+	Reveal(ctx context.Context) []ReceivedBid
+}
+
+// Bids revealed as the auction closes are evaluated like submitted ones before the winner is chosen, if set before
+// the auction starts. The winner waits for the reveal.
+func (r *RelayAuction) SetRevealer(revealer Revealer) {
+	r.revealer = revealer
+}
+
+// Evaluates the revealed bids, returning the last to become the leader
+func (r *RelayAuction) reveal(ctx context.Context, seen map[string]struct{}) *ReceivedBid {
+	if r.revealer == nil {
+		return nil
+	}
+	var best *ReceivedBid
+	for _, bid := range r.revealer.Reveal(ctx) {
+		r.observeQueueDepth(r.queued.Add(1))
+		sub := submission{bid: bid.SignedBid, receivedAt: bid.ReceivedAt, submittedAt: time.Now()}
+		if leader := r.handle(sub, r.verifyBid(sub), seen); leader != nil {
+			best = leader
+		}
+	}
+	return best
+}
@@ -0,0 +1,46 @@
+package auction_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type revealer []auction.ReceivedBid
+
+func (r revealer) Reveal(ctx context.Context) []auction.ReceivedBid {
+	return r
+}
+
+func TestReveal(t *testing.T) {
+	open, _ := crypto.GenerateKey()
+	sealed, _ := crypto.GenerateKey()
+	relays := []common.Address{crypto.PubkeyToAddress(open.PublicKey), crypto.PubkeyToAddress(sealed.PublicKey)}
+	revealed := auction.MustCreateSignedBid(big.NewInt(200), big.NewInt(7), sealed)
+	forged := *auction.MustCreateSignedBid(big.NewInt(300), big.NewInt(7), sealed)
+	forged.AmountWei = big.NewInt(1000)
+
+	for _, shards := range []int{1, 4} {
+		relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+		relayAuction.SetAccessList(auction.NewAccessList(relays, nil))
+		relayAuction.SetShards(shards)
+		relayAuction.SetRevealer(revealer{{SignedBid: *revealed, ReceivedAt: time.Now()}, {SignedBid: forged, ReceivedAt: time.Now()}})
+		ctx, cancel := context.WithCancel(context.Background())
+		results := relayAuction.StartAsync(ctx, 100*time.Millisecond)
+		relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(150), big.NewInt(7), open))
+
+		winner := <-results
+		require.Equal(t, *revealed, winner, "shards %d", shards)
+		require.Len(t, relayAuction.Ranked(), 2, "forged bid rejected")
+		require.Zero(t, relayAuction.QueueDepth())
+		cancel()
+	}
+}
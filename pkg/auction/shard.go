@@ -0,0 +1,87 @@
+package auction
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Overrides evaluating bids in the order submitted, if set before the auction starts and before bids are submitted.
+// Bids are routed by signer address to one of n shards, each verifying, deduplicating and evaluating its relays'
+// bids on a goroutine of its own, so intake scales beyond a single core with thousands of relays bidding. Bids
+// from a relay are still evaluated in the order submitted, but not across relays, which leaves the winner
+// unchanged as ties go by arrival, then address. The verifier pool isn't used.
+func (r *RelayAuction) SetShards(n int) {
+	if n <= 1 {
+		r.shards = nil
+		return
+	}
+	r.shards = make([]chan submission, n)
+	for i := range r.shards {
+		r.shards[i] = make(chan submission, bidQueueSize)
+	}
+}
+
+func shardOf(address common.Address, shards int) int {
+	return int(binary.BigEndian.Uint32(address[common.AddressLength-4:]) % uint32(shards))
+}
+
+// Runs the shards until the bidding period is over, then reduces their last accepted bids and any revealed bid
+// to become the leader to the winner, once bids being evaluated are done
+func (r *RelayAuction) runShards(ctx context.Context, closed <-chan struct{}) {
+	stop := make(chan struct{})
+	bests := make([]*ReceivedBid, len(r.shards))
+	var wg sync.WaitGroup
+	for i, bids := range r.shards {
+		wg.Add(1)
+		go func(i int, bids <-chan submission) {
+			defer wg.Done()
+			bests[i] = r.runShard(stop, bids)
+		}(i, bids)
+	}
+	select {
+	case <-ctx.Done():
+		close(stop)
+		wg.Wait()
+		return
+	case <-closed:
+	}
+	close(stop)
+	wg.Wait()
+	bests = append(bests, r.reveal(ctx, make(map[string]struct{})))
+
+	var winner ReceivedBid
+	for _, best := range bests {
+		if best != nil && (winner.AmountWei == nil || beats(*best, winner)) {
+			winner = *best
+		}
+	}
+	r.logger.Info("auction ended, winner", "bid", winner.SignedBid)
+	select {
+	case r.auctionResultChan <- winner.SignedBid:
+	case <-ctx.Done():
+	}
+}
+
+// Evaluates the shard's bids until stopped, returning the last to become the leader
+func (r *RelayAuction) runShard(stop <-chan struct{}, bids <-chan submission) *ReceivedBid {
+	seen := make(map[string]struct{})
+	var best *ReceivedBid
+	for {
+		select {
+		case <-stop:
+			return best
+		default:
+		}
+		select {
+		case <-stop:
+			return best
+		case sub := <-bids:
+			if leader := r.handle(sub, r.verifyBid(sub), seen); leader != nil {
+				best = leader
+			}
+		}
+	}
+}
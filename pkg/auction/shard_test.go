@@ -0,0 +1,96 @@
+package auction_test
+
+import (
+	"blob-preconfs/pkg/auction"
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type outcomeAuditor struct {
+	mu       sync.Mutex
+	accepted map[common.Address][]*big.Int
+	reasons  map[string]int
+}
+
+func (a *outcomeAuditor) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if accepted {
+		a.accepted[bid.Address] = append(a.accepted[bid.Address], bid.AmountWei)
+		return
+	}
+	a.reasons[reason]++
+}
+
+func TestShardedIntake(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 20)
+	relays := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		relays[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	relayAuction := auction.NewRelayAuction(slog.Default(), &mockRegistry{isRegisteredCallback: func(common.Address) bool { return true }})
+	relayAuction.SetAccessList(auction.NewAccessList(relays, nil))
+	auditor := &outcomeAuditor{accepted: make(map[common.Address][]*big.Int), reasons: make(map[string]int)}
+	relayAuction.SetAuditor(auditor)
+	feed := &event.Feed{}
+	events := make(chan auction.Event, 1000)
+	sub := feed.Subscribe(events)
+	defer sub.Unsubscribe()
+	relayAuction.SetEventFeed(feed)
+	relayAuction.SetShards(4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := relayAuction.StartAsync(ctx, 500*time.Millisecond)
+
+	// Each relay raises its bid, the highest being the last relay's last bid
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key *ecdsa.PrivateKey) {
+			defer wg.Done()
+			for round := int64(1); round <= 5; round++ {
+				relayAuction.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(round*100+int64(i)), big.NewInt(999), key))
+			}
+		}(i, key)
+	}
+	wg.Wait()
+	duplicate := *auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(999), keys[0])
+	relayAuction.SubmitBid(duplicate)
+	relayAuction.SubmitBid(duplicate)
+
+	winner := <-results
+	require.Equal(t, relays[len(relays)-1], winner.Address)
+	assert.Equal(t, big.NewInt(519), winner.AmountWei)
+	assert.Equal(t, winner, relayAuction.GetCurrentBid())
+	assert.Zero(t, relayAuction.QueueDepth())
+
+	auditor.mu.Lock()
+	defer auditor.mu.Unlock()
+	assert.Equal(t, 1, auditor.reasons["duplicate bid"])
+	for relay, amounts := range auditor.accepted {
+		for i := 1; i < len(amounts); i++ {
+			assert.Equal(t, 1, amounts[i].Cmp(amounts[i-1]), "relay %s bids evaluated in the order submitted", relay)
+		}
+	}
+
+	// Leader changes are published in order, even from concurrent shards
+	last := big.NewInt(0)
+	for len(events) > 0 {
+		leader := (<-events).Bid
+		assert.Equal(t, 1, leader.AmountWei.Cmp(last))
+		last = leader.AmountWei
+	}
+	assert.Equal(t, winner.AmountWei, last)
+}
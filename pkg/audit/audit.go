@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Sized for entries, which hold a single bid
+const maxEntrySize = 64 * 1024
+
+var ErrBrokenChain = errors.New("audit log hash chain broken")
+
+// One line of the log. Hash is keccak256 of the entry's JSON encoding without it, which includes the
+// previous entry's hash, so altering, removing or reordering entries breaks the chain.
+type Entry struct {
+	Seq        uint64            `json:"seq"`
+	ReceivedAt time.Time         `json:"receivedAt"`
+	Bid        auction.SignedBid `json:"bid"`
+	Accepted   bool              `json:"accepted"`
+	Reason     string            `json:"reason,omitempty"`
+	PrevHash   common.Hash       `json:"prevHash"`
+	Hash       common.Hash       `json:"hash"`
+}
+
+func (e Entry) computeHash() (common.Hash, error) {
+	e.Hash = common.Hash{}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// Append-only, hash-chained log of bid traffic, one JSON entry per line
+type Log struct {
+	logger *slog.Logger
+
+	mu   sync.Mutex // Protects access to fields below
+	file *os.File
+	seq  uint64
+	head common.Hash
+}
+
+// Opens the log at path, creating it if missing. An existing log is verified and appended to.
+func NewLog(logger *slog.Logger, path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{logger: logger, file: file}
+	last, err := Verify(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to verify existing audit log: %w", err)
+	}
+	if last != nil {
+		l.seq, l.head = last.Seq, last.Hash
+	}
+	return l, nil
+}
+
+// To satisfy auction.Auditor. Failing to write is logged, as bid processing must not depend on the audit log.
+func (l *Log) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	if err := l.Append(bid, accepted, reason); err != nil {
+		l.logger.Error("failed to write audit log entry", "bid", bid, "error", err)
+	}
+}
+
+func (l *Log) Append(bid auction.SignedBid, accepted bool, reason string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry := Entry{
+		Seq:        l.seq + 1,
+		ReceivedAt: time.Now().UTC(),
+		Bid:        bid,
+		Accepted:   accepted,
+		Reason:     reason,
+		PrevHash:   l.head,
+	}
+	hash, err := entry.computeHash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	l.seq, l.head = entry.Seq, entry.Hash
+	return nil
+}
+
+// Hash of the last entry, to be published or anchored elsewhere so truncation of the log can be detected
+func (l *Log) Head() (seq uint64, hash common.Hash) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq, l.head
+}
+
+// Flushes the log to disk and closes it
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.file.Sync(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+// Checks the hash chain of a log, returning its last entry, nil if empty
+func Verify(r io.Reader) (*Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxEntrySize)
+	var last *Entry
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrBrokenChain, line, err)
+		}
+		var prevHash common.Hash
+		var prevSeq uint64
+		if last != nil {
+			prevHash, prevSeq = last.Hash, last.Seq
+		}
+		if entry.Seq != prevSeq+1 || entry.PrevHash != prevHash {
+			return nil, fmt.Errorf("%w: line %d doesn't follow entry %d", ErrBrokenChain, line, prevSeq)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return nil, err
+		}
+		if hash != entry.Hash {
+			return nil, fmt.Errorf("%w: line %d hash mismatch", ErrBrokenChain, line)
+		}
+		last = &entry
+	}
+	return last, scanner.Err()
+}
@@ -0,0 +1,56 @@
+package audit_test
+
+import (
+	"bytes"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/audit"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogHashChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	log, err := audit.NewLog(slog.Default(), path)
+	require.NoError(t, err)
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.NoError(t, log.Append(*bid, true, ""))
+	log.RecordBid(*bid, false, "bid does not beat the leading bid")
+	require.NoError(t, log.Close())
+
+	// Reopening continues the chain
+	log, err = audit.NewLog(slog.Default(), path)
+	require.NoError(t, err)
+	seq, head := log.Head()
+	require.Equal(t, uint64(2), seq)
+	require.NoError(t, log.Append(*bid, false, "bid is for a different block"))
+	require.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	last, err := audit.Verify(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), last.Seq)
+	require.Equal(t, head, last.PrevHash)
+	require.Equal(t, "bid is for a different block", last.Reason)
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for name, tampered := range map[string][]byte{
+		"altered":   bytes.Replace(data, []byte(`"accepted":true`), []byte(`"accepted":false`), 1),
+		"removed":   append(append([]byte{}, lines[0]...), lines[2]...),
+		"reordered": append(append(append([]byte{}, lines[1]...), lines[0]...), lines[2]...),
+	} {
+		_, err := audit.Verify(bytes.NewReader(tampered))
+		require.ErrorIs(t, err, audit.ErrBrokenChain, name)
+	}
+	require.NoError(t, os.WriteFile(path, lines[1], 0o644))
+	_, err = audit.NewLog(slog.Default(), path)
+	require.ErrorIs(t, err, audit.ErrBrokenChain, "existing broken log isn't appended to")
+}
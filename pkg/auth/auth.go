@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	TimestampHeader = "X-Relay-Timestamp"
+	SignatureHeader = "X-Relay-Signature"
+
+	maxBodySize = 32 * 1024
+)
+
+var (
+	ErrUnauthorized   = errors.New("unauthorized")
+	ErrSignerMismatch = errors.New("bid signer does not match authenticated relay")
+)
+
+type (
+	contextKey          struct{}
+	forwarderContextKey struct{}
+)
+
+// Digest signed by relays: keccak256(timestamp || "\n" || target || "\n" || keccak256(body)),
+// where target is the HTTP path or gRPC method
+func Digest(timestamp int64, target string, body []byte) common.Hash {
+	if target == "" {
+		target = "/"
+	}
+	bodyHash := crypto.Keccak256(body)
+	data := fmt.Sprintf("%d\n%s\n", timestamp, target)
+	return crypto.Keccak256Hash([]byte(data), bodyHash)
+}
+
+func Sign(timestamp int64, target string, body []byte, privateKey *ecdsa.PrivateKey) (hexutil.Bytes, error) {
+	return crypto.Sign(Digest(timestamp, target, body).Bytes(), privateKey)
+}
+
+// Auth headers for a request to target, e.g. for a websocket handshake
+func Headers(target string, body []byte, privateKey *ecdsa.PrivateKey) (http.Header, error) {
+	timestamp := time.Now().Unix()
+	signature, err := Sign(timestamp, target, body, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make(http.Header)
+	header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	header.Set(SignatureHeader, signature.String())
+	return header, nil
+}
+
+// Signs every outgoing request with privateKey, for relay clients
+type Transport struct {
+	PrivateKey *ecdsa.PrivateKey
+	Base       http.RoundTripper // http.DefaultTransport if nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	header, err := Headers(req.URL.Path, body, t.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	// RoundTrippers must not modify the original request
+	signed := req.Clone(req.Context())
+	signed.Body = io.NopCloser(bytes.NewReader(body))
+	for key := range header {
+		signed.Header.Set(key, header.Get(key))
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
+
+// Relay authenticated for the request, if any
+func RelayFromContext(ctx context.Context) (common.Address, bool) {
+	relay, ok := ctx.Value(contextKey{}).(common.Address)
+	return relay, ok
+}
+
+// Rejects bids signed by a different relay than the one that authenticated the request.
+// Passes when the request wasn't authenticated, e.g. with auth disabled, or was sent by a trusted forwarder.
+func CheckSigner(ctx context.Context, signer common.Address) error {
+	if IsForwarder(ctx) {
+		return nil
+	}
+	relay, ok := RelayFromContext(ctx)
+	if ok && relay != signer {
+		return fmt.Errorf("%w: signed by %s, authenticated as %s", ErrSignerMismatch, signer.Hex(), relay.Hex())
+	}
+	return nil
+}
+
+// Whether the request was sent by a trusted forwarder, see Verifier.TrustForwarders
+func IsForwarder(ctx context.Context) bool {
+	forwarder, _ := ctx.Value(forwarderContextKey{}).(bool)
+	return forwarder
+}
+
+func ContextWithRelay(ctx context.Context, relay common.Address) context.Context {
+	return context.WithValue(ctx, contextKey{}, relay)
+}
+
+// Verifies relay request signatures against the relay registry
+type Verifier struct {
+	registry auction.RelayRegistry
+	maxSkew  time.Duration
+
+	mu         sync.Mutex
+	seen       map[string]time.Time // Signatures seen within the skew window, to reject replays
+	forwarders map[common.Address]struct{}
+}
+
+func NewVerifier(registry auction.RelayRegistry, maxSkew time.Duration) *Verifier {
+	return &Verifier{
+		registry:   registry,
+		maxSkew:    maxSkew,
+		seen:       make(map[string]time.Time),
+		forwarders: make(map[common.Address]struct{}),
+	}
+}
+
+// Forwarding instances (see forwarder) may submit bids signed by other relays, without being registered relays themselves
+func (v *Verifier) TrustForwarders(addresses ...common.Address) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, address := range addresses {
+		v.forwarders[address] = struct{}{}
+	}
+}
+
+func (v *Verifier) isForwarder(address common.Address) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	_, ok := v.forwarders[address]
+	return ok
+}
+
+// Verifies the request and returns ctx carrying the authenticated relay, for CheckSigner
+func (v *Verifier) Authenticate(ctx context.Context, timestamp string, signature string, target string, body []byte) (context.Context, error) {
+	relay, err := v.Verify(timestamp, signature, target, body)
+	if err != nil {
+		return nil, err
+	}
+	ctx = ContextWithRelay(ctx, relay)
+	if v.isForwarder(relay) {
+		ctx = context.WithValue(ctx, forwarderContextKey{}, true)
+	}
+	return ctx, nil
+}
+
+// Returns the registered relay or trusted forwarder that signed the request
+func (v *Verifier) Verify(timestampStr string, signatureHex string, target string, body []byte) (common.Address, error) {
+	timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: invalid timestamp", ErrUnauthorized)
+	}
+	now := time.Now()
+	signedAt := time.Unix(timestamp, 0)
+	if signedAt.Before(now.Add(-v.maxSkew)) || signedAt.After(now.Add(v.maxSkew)) {
+		return common.Address{}, fmt.Errorf("%w: timestamp outside allowed skew", ErrUnauthorized)
+	}
+	signature, err := hexutil.Decode(signatureHex)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: invalid signature", ErrUnauthorized)
+	}
+	pubkey, err := crypto.SigToPub(Digest(timestamp, target, body).Bytes(), signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: invalid signature", ErrUnauthorized)
+	}
+	relay := crypto.PubkeyToAddress(*pubkey)
+	if !v.isForwarder(relay) && !v.registry.IsRegisteredOnSettlementLayer(relay) {
+		return common.Address{}, fmt.Errorf("%w: relay %s not registered", ErrUnauthorized, relay.Hex())
+	}
+	if err := v.checkReplay(signatureHex, now); err != nil {
+		return common.Address{}, err
+	}
+	return relay, nil
+}
+
+func (v *Verifier) checkReplay(signature string, now time.Time) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for sig, seenAt := range v.seen {
+		if now.Sub(seenAt) > 2*v.maxSkew {
+			delete(v.seen, sig)
+		}
+	}
+	if _, ok := v.seen[signature]; ok {
+		return fmt.Errorf("%w: replayed signature", ErrUnauthorized)
+	}
+	v.seen[signature] = now
+	return nil
+}
+
+// Rejects unsigned or invalid requests with 401, and sets the authenticated relay on the request context. The path
+// verified is the one the relay requested and signed, also behind http.StripPrefix.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil || len(body) > maxBodySize {
+			http.Error(w, "invalid request body", http.StatusRequestEntityTooLarge)
+			return
+		}
+		ctx, err := v.Authenticate(r.Context(), r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), requestedPath(r), body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func requestedPath(r *http.Request) string {
+	if requested, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		return requested.Path
+	}
+	return r.URL.Path
+}
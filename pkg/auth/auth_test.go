@@ -0,0 +1,154 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auth"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRegistry struct {
+	registered map[common.Address]bool
+}
+
+func (r *mockRegistry) IsRegisteredOnSettlementLayer(address common.Address) bool {
+	return r.registered[address]
+}
+
+func newVerifier() (*auth.Verifier, *mockRegistry) {
+	registry := &mockRegistry{registered: make(map[common.Address]bool)}
+	return auth.NewVerifier(registry, 30*time.Second), registry
+}
+
+func TestVerify(t *testing.T) {
+	verifier, registry := newVerifier()
+	pk, _ := crypto.GenerateKey()
+	relay := crypto.PubkeyToAddress(pk.PublicKey)
+	body := []byte(`{"method":"auction_submitBid"}`)
+
+	sign := func(timestamp int64, target string) (string, string) {
+		signature, err := auth.Sign(timestamp, target, body, pk)
+		require.NoError(t, err)
+		return strconv.FormatInt(timestamp, 10), signature.String()
+	}
+
+	timestamp, signature := sign(time.Now().Unix(), "/")
+	_, err := verifier.Verify(timestamp, signature, "/", body)
+	require.ErrorIs(t, err, auth.ErrUnauthorized, "unregistered relay")
+
+	registry.registered[relay] = true
+	timestamp, signature = sign(time.Now().Unix(), "/")
+	got, err := verifier.Verify(timestamp, signature, "/", body)
+	require.NoError(t, err)
+	require.Equal(t, relay, got)
+
+	_, err = verifier.Verify(timestamp, signature, "/", body)
+	require.ErrorContains(t, err, "replayed signature")
+
+	timestamp, signature = sign(time.Now().Unix(), "/v1/bids")
+	_, err = verifier.Verify(timestamp, signature, "/", body)
+	require.ErrorIs(t, err, auth.ErrUnauthorized, "different target")
+
+	timestamp, signature = sign(time.Now().Unix(), "/")
+	_, err = verifier.Verify(timestamp, signature, "/", []byte(`{}`))
+	require.ErrorIs(t, err, auth.ErrUnauthorized, "different body")
+
+	timestamp, signature = sign(time.Now().Add(-time.Minute).Unix(), "/")
+	_, err = verifier.Verify(timestamp, signature, "/", body)
+	require.ErrorContains(t, err, "outside allowed skew")
+
+	_, err = verifier.Verify("", "", "/", body)
+	require.ErrorIs(t, err, auth.ErrUnauthorized)
+}
+
+func TestMiddlewareAndTransport(t *testing.T) {
+	verifier, registry := newVerifier()
+	pk, _ := crypto.GenerateKey()
+	relay := crypto.PubkeyToAddress(pk.PublicKey)
+	registry.registered[relay] = true
+
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := auth.RelayFromContext(r.Context())
+		require.True(t, ok)
+		require.Equal(t, relay, got)
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/rpc", "application/json", bytes.NewBufferString("hello"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	client := &http.Client{Transport: &auth.Transport{PrivateKey: pk}}
+	resp, err = client.Post(server.URL+"/rpc", "application/json", bytes.NewBufferString("hello"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	require.Equal(t, "hello", string(body))
+}
+
+func TestMiddlewareBehindStripPrefix(t *testing.T) {
+	verifier, registry := newVerifier()
+	pk, _ := crypto.GenerateKey()
+	registry.registered[crypto.PubkeyToAddress(pk.PublicKey)] = true
+	var path string
+	handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { path = r.URL.Path }))
+	server := httptest.NewServer(http.StripPrefix("/chains/holesky", handler))
+	defer server.Close()
+
+	client := &http.Client{Transport: &auth.Transport{PrivateKey: pk}}
+	resp, err := client.Post(server.URL+"/chains/holesky/v1/bids", "application/json", bytes.NewBufferString("hello"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "signed path verified")
+	require.Equal(t, "/v1/bids", path)
+}
+
+func TestCheckSigner(t *testing.T) {
+	relay := common.HexToAddress("0x1")
+	ctx := auth.ContextWithRelay(context.Background(), relay)
+	require.NoError(t, auth.CheckSigner(ctx, relay))
+	require.ErrorIs(t, auth.CheckSigner(ctx, common.HexToAddress("0x2")), auth.ErrSignerMismatch)
+	require.NoError(t, auth.CheckSigner(context.Background(), common.HexToAddress("0x2")))
+}
+
+func TestTrustedForwarders(t *testing.T) {
+	verifier, _ := newVerifier()
+	pk, _ := crypto.GenerateKey()
+	forwarder := crypto.PubkeyToAddress(pk.PublicKey)
+	body := []byte(`{"method":"auction_submitBid"}`)
+	sign := func() (string, string) {
+		timestamp := time.Now().Unix()
+		signature, err := auth.Sign(timestamp, "/", body, pk)
+		require.NoError(t, err)
+		return strconv.FormatInt(timestamp, 10), signature.String()
+	}
+
+	timestamp, signature := sign()
+	_, err := verifier.Authenticate(context.Background(), timestamp, signature, "/", body)
+	require.ErrorIs(t, err, auth.ErrUnauthorized, "unregistered and untrusted")
+
+	verifier.TrustForwarders(forwarder)
+	timestamp, signature = sign()
+	ctx, err := verifier.Authenticate(context.Background(), timestamp, signature, "/", body)
+	require.NoError(t, err)
+	relay, ok := auth.RelayFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, forwarder, relay)
+	require.NoError(t, auth.CheckSigner(ctx, common.HexToAddress("0x2")), "forwarders submit bids signed by other relays")
+	require.True(t, auth.IsForwarder(ctx))
+	require.False(t, auth.IsForwarder(auth.ContextWithRelay(context.Background(), forwarder)))
+}
@@ -0,0 +1,178 @@
+package availability
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// Verifies the committed blobs of a slot are available on the beacon network.
+// Commitment flow only depends on this, so the checking strategy can change with PeerDAS (EIP-7594).
+type Checker interface {
+	CheckAvailable(ctx context.Context, slot uint64, versionedHashes []common.Hash) error
+}
+
+type BlobSidecar struct {
+	Index         uint64             `json:"index,string"`
+	Blob          kzg4844.Blob       `json:"blob"`
+	KZGCommitment kzg4844.Commitment `json:"kzg_commitment"`
+	KZGProof      kzg4844.Proof      `json:"kzg_proof"`
+}
+
+type SidecarSource interface {
+	GetBlobSidecars(ctx context.Context, slot uint64) ([]BlobSidecar, error)
+}
+
+// Pre-PeerDAS check: downloads every blob of the slot and verifies its KZG proof.
+type FullBlobChecker struct {
+	logger *slog.Logger
+	source SidecarSource
+}
+
+func NewFullBlobChecker(logger *slog.Logger, source SidecarSource) *FullBlobChecker {
+	return &FullBlobChecker{
+		logger: logger,
+		source: source,
+	}
+}
+
+func (c *FullBlobChecker) CheckAvailable(ctx context.Context, slot uint64, versionedHashes []common.Hash) error {
+	sidecars, err := c.source.GetBlobSidecars(ctx, slot)
+	if err != nil {
+		return fmt.Errorf("failed to get blob sidecars: %w", err)
+	}
+	available := make(map[common.Hash]struct{}, len(sidecars))
+	hasher := sha256.New()
+	for _, sidecar := range sidecars {
+		if err := kzg4844.VerifyBlobProof(sidecar.Blob, sidecar.KZGCommitment, sidecar.KZGProof); err != nil {
+			c.logger.Warn("invalid blob sidecar", "slot", slot, "index", sidecar.Index, "error", err)
+			continue
+		}
+		hasher.Reset()
+		available[kzg4844.CalcBlobHashV1(hasher, &sidecar.KZGCommitment)] = struct{}{}
+	}
+	return requireAll(versionedHashes, available)
+}
+
+// Number of columns in the extended blob matrix, per EIP-7594.
+const NumberOfColumns = 128
+
+// A column of the extended blob matrix: one cell per blob in the block.
+type DataColumn struct {
+	Index          uint64
+	Cells          [][]byte
+	KZGCommitments []kzg4844.Commitment
+	KZGProofs      []kzg4844.Proof
+}
+
+type ColumnSource interface {
+	GetDataColumns(ctx context.Context, slot uint64, indices []uint64) ([]DataColumn, error)
+}
+
+// Cell proof verification is left as a hook until a KZG library with EIP-7594 support is adopted.
+type ColumnVerifier interface {
+	VerifyColumn(column DataColumn) error
+}
+
+// PeerDAS check: samples random columns, rather than downloading full blobs.
+type SamplingChecker struct {
+	logger      *slog.Logger
+	source      ColumnSource
+	verifier    ColumnVerifier
+	sampleCount int
+}
+
+// Samples sampleCount columns per check, clamped to at least one as sampling none would pass anything
+func NewSamplingChecker(
+	logger *slog.Logger,
+	source ColumnSource,
+	verifier ColumnVerifier,
+	sampleCount int,
+) *SamplingChecker {
+	return &SamplingChecker{
+		logger:      logger,
+		source:      source,
+		verifier:    verifier,
+		sampleCount: max(1, min(sampleCount, NumberOfColumns)),
+	}
+}
+
+func (c *SamplingChecker) CheckAvailable(ctx context.Context, slot uint64, versionedHashes []common.Hash) error {
+	indices := make([]uint64, c.sampleCount)
+	for i, idx := range rand.Perm(NumberOfColumns)[:c.sampleCount] {
+		indices[i] = uint64(idx)
+	}
+	columns, err := c.source.GetDataColumns(ctx, slot, indices)
+	if err != nil {
+		return fmt.Errorf("failed to get data columns: %w", err)
+	}
+
+	sampled := make(map[uint64]struct{}, len(columns))
+	var commitments []kzg4844.Commitment
+	for _, column := range columns {
+		if len(column.Cells) != len(column.KZGCommitments) || len(column.Cells) != len(column.KZGProofs) {
+			return fmt.Errorf("malformed data column %d", column.Index)
+		}
+		// Every column carries the commitments of all blobs in the block. They must agree, as only one list
+		// is checked against the versioned hashes.
+		if len(sampled) > 0 && !slices.Equal(column.KZGCommitments, commitments) {
+			return fmt.Errorf("data column %d commitments differ from other columns", column.Index)
+		}
+		if err := c.verifier.VerifyColumn(column); err != nil {
+			return fmt.Errorf("invalid data column %d: %w", column.Index, err)
+		}
+		sampled[column.Index] = struct{}{}
+		commitments = column.KZGCommitments
+	}
+	for _, idx := range indices {
+		if _, ok := sampled[idx]; !ok {
+			return fmt.Errorf("sampled data column %d unavailable", idx)
+		}
+	}
+
+	available := make(map[common.Hash]struct{}, len(commitments))
+	hasher := sha256.New()
+	for i := range commitments {
+		hasher.Reset()
+		available[kzg4844.CalcBlobHashV1(hasher, &commitments[i])] = struct{}{}
+	}
+	c.logger.Debug("data column sampling succeeded", "slot", slot, "numSamples", len(indices))
+	return requireAll(versionedHashes, available)
+}
+
+// Delegates to the sampling checker from the PeerDAS activation slot onwards.
+type ForkAwareChecker struct {
+	preFork           Checker
+	postFork          Checker
+	peerDASActivation uint64
+}
+
+func NewForkAwareChecker(preFork Checker, postFork Checker, peerDASActivationSlot uint64) *ForkAwareChecker {
+	return &ForkAwareChecker{
+		preFork:           preFork,
+		postFork:          postFork,
+		peerDASActivation: peerDASActivationSlot,
+	}
+}
+
+func (c *ForkAwareChecker) CheckAvailable(ctx context.Context, slot uint64, versionedHashes []common.Hash) error {
+	if c.postFork != nil && slot >= c.peerDASActivation {
+		return c.postFork.CheckAvailable(ctx, slot, versionedHashes)
+	}
+	return c.preFork.CheckAvailable(ctx, slot, versionedHashes)
+}
+
+func requireAll(versionedHashes []common.Hash, available map[common.Hash]struct{}) error {
+	for _, vh := range versionedHashes {
+		if _, ok := available[vh]; !ok {
+			return fmt.Errorf("blob with versioned hash %s unavailable", vh.Hex())
+		}
+	}
+	return nil
+}
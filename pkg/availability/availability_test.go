@@ -0,0 +1,147 @@
+package availability_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"blob-preconfs/pkg/availability"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSidecarSource struct {
+	sidecars []availability.BlobSidecar
+}
+
+func (m *mockSidecarSource) GetBlobSidecars(ctx context.Context, slot uint64) ([]availability.BlobSidecar, error) {
+	return m.sidecars, nil
+}
+
+type mockColumnSource struct {
+	commitments []kzg4844.Commitment
+	withhold    map[uint64]bool
+	// Commitments served instead by some columns
+	overrides map[uint64][]kzg4844.Commitment
+}
+
+func (m *mockColumnSource) GetDataColumns(ctx context.Context, slot uint64, indices []uint64) ([]availability.DataColumn, error) {
+	var columns []availability.DataColumn
+	for _, idx := range indices {
+		if m.withhold[idx] {
+			continue
+		}
+		commitments := m.commitments
+		if override, ok := m.overrides[idx]; ok {
+			commitments = override
+		}
+		columns = append(columns, availability.DataColumn{
+			Index:          idx,
+			Cells:          make([][]byte, len(commitments)),
+			KZGCommitments: commitments,
+			KZGProofs:      make([]kzg4844.Proof, len(commitments)),
+		})
+	}
+	return columns, nil
+}
+
+type mockColumnVerifier struct {
+	err error
+}
+
+func (m *mockColumnVerifier) VerifyColumn(column availability.DataColumn) error {
+	return m.err
+}
+
+func versionedHash(c kzg4844.Commitment) common.Hash {
+	return kzg4844.CalcBlobHashV1(sha256.New(), &c)
+}
+
+func TestFullBlobChecker(t *testing.T) {
+	var blob kzg4844.Blob
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	require.NoError(t, err)
+
+	source := &mockSidecarSource{sidecars: []availability.BlobSidecar{
+		{Index: 0, Blob: blob, KZGCommitment: commitment, KZGProof: proof},
+	}}
+	checker := availability.NewFullBlobChecker(slog.Default(), source)
+
+	require.NoError(t, checker.CheckAvailable(context.Background(), 1, []common.Hash{versionedHash(commitment)}))
+	require.Error(t, checker.CheckAvailable(context.Background(), 1, []common.Hash{{0x01}}))
+}
+
+func TestSamplingChecker(t *testing.T) {
+	var commitment kzg4844.Commitment
+	commitment[0] = 1
+	committed := []common.Hash{versionedHash(commitment)}
+
+	source := &mockColumnSource{commitments: []kzg4844.Commitment{commitment}}
+	checker := availability.NewSamplingChecker(slog.Default(), source, &mockColumnVerifier{}, 8)
+	require.NoError(t, checker.CheckAvailable(context.Background(), 1, committed))
+
+	withholdAll := make(map[uint64]bool)
+	for i := uint64(0); i < availability.NumberOfColumns; i++ {
+		withholdAll[i] = true
+	}
+	source.withhold = withholdAll
+	require.ErrorContains(t, checker.CheckAvailable(context.Background(), 1, committed), "unavailable")
+
+	source.withhold = nil
+	invalid := availability.NewSamplingChecker(slog.Default(), source, &mockColumnVerifier{err: errors.New("bad proof")}, 8)
+	require.ErrorContains(t, invalid.CheckAvailable(context.Background(), 1, committed), "bad proof")
+}
+
+func TestSamplingCheckerSamplesAtLeastOneColumn(t *testing.T) {
+	var commitment kzg4844.Commitment
+	commitment[0] = 1
+	committed := []common.Hash{versionedHash(commitment)}
+
+	source := &mockColumnSource{commitments: []kzg4844.Commitment{commitment}}
+	for _, sampleCount := range []int{-1, 0} {
+		checker := availability.NewSamplingChecker(slog.Default(), source, &mockColumnVerifier{}, sampleCount)
+		source.withhold = nil
+		require.NoError(t, checker.CheckAvailable(context.Background(), 1, committed))
+
+		withholdAll := make(map[uint64]bool)
+		for i := uint64(0); i < availability.NumberOfColumns; i++ {
+			withholdAll[i] = true
+		}
+		source.withhold = withholdAll
+		require.ErrorContains(t, checker.CheckAvailable(context.Background(), 1, committed), "unavailable", sampleCount)
+	}
+}
+
+func TestSamplingCheckerMismatchedColumns(t *testing.T) {
+	var committed, other kzg4844.Commitment
+	committed[0] = 1
+	other[0] = 2
+	// A single column disagreeing fails the check, wherever it's sampled
+	source := &mockColumnSource{
+		commitments: []kzg4844.Commitment{committed},
+		overrides:   map[uint64][]kzg4844.Commitment{7: {other}},
+	}
+	checker := availability.NewSamplingChecker(slog.Default(), source, &mockColumnVerifier{}, availability.NumberOfColumns)
+	require.ErrorContains(t, checker.CheckAvailable(context.Background(), 1, []common.Hash{versionedHash(committed)}),
+		"commitments differ")
+}
+
+func TestForkAwareChecker(t *testing.T) {
+	var commitment kzg4844.Commitment
+	commitment[0] = 1
+	committed := []common.Hash{versionedHash(commitment)}
+
+	preFork := availability.NewFullBlobChecker(slog.Default(), &mockSidecarSource{})
+	postFork := availability.NewSamplingChecker(slog.Default(),
+		&mockColumnSource{commitments: []kzg4844.Commitment{commitment}}, &mockColumnVerifier{}, 8)
+	checker := availability.NewForkAwareChecker(preFork, postFork, 100)
+
+	require.Error(t, checker.CheckAvailable(context.Background(), 99, committed))
+	require.NoError(t, checker.CheckAvailable(context.Background(), 100, committed))
+}
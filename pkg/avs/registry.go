@@ -0,0 +1,182 @@
+package avs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidConfig = errors.New("invalid avs config")
+
+// Subset of the EigenLayer middleware RegistryCoordinator and StakeRegistry ABIs
+const registryABI = `[
+	{"type":"function","name":"getOperatorStatus","stateMutability":"view","inputs":[{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"getOperatorId","stateMutability":"view","inputs":[{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"getCurrentStake","stateMutability":"view","inputs":[{"name":"operatorId","type":"bytes32"},{"name":"quorumNumber","type":"uint8"}],"outputs":[{"name":"","type":"uint96"}]}
+]`
+
+var parsedRegistryABI = mustParseABI(registryABI)
+
+// RegistryCoordinator's OperatorStatus enum value for registered operators
+const operatorRegistered = 1
+
+// Calls view functions on L1, e.g. *ethclient.Client
+type Caller interface {
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+type Config struct {
+	RegistryCoordinator common.Address
+	StakeRegistry       common.Address
+	// Quorum relays restake in
+	Quorum uint8
+	// Restaked collateral, in wei, a relay's operator needs to be bonded
+	MinStake *big.Int
+	// Relays' operator addresses, also the addresses they bid with
+	Operators []common.Address
+}
+
+// Relay registry backed by restaked collateral: a relay is registered while its operator is registered with the
+// AVS and its stake in the quorum is at least the minimum bond. Stakes are read from the contracts by Resync, as
+// registry lookups are on the bid path.
+type Registry struct {
+	logger *slog.Logger
+	caller Caller
+	config Config
+
+	mu     sync.RWMutex // Protects access to fields below
+	stakes map[common.Address]*big.Int
+}
+
+func NewRegistry(logger *slog.Logger, caller Caller, config Config) (*Registry, error) {
+	if config.RegistryCoordinator == (common.Address{}) || config.StakeRegistry == (common.Address{}) {
+		return nil, fmt.Errorf("%w: registry coordinator and stake registry required", ErrInvalidConfig)
+	}
+	if config.MinStake == nil {
+		config.MinStake = new(big.Int)
+	}
+	return &Registry{
+		logger: logger,
+		caller: caller,
+		config: config,
+		stakes: make(map[common.Address]*big.Int),
+	}, nil
+}
+
+// To satisfy auction.RelayRegistry
+func (r *Registry) IsRegisteredOnSettlementLayer(address common.Address) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.stakes[address]
+	return ok
+}
+
+// Stake of a bonded relay's operator as of the last resync, nil if it isn't bonded
+func (r *Registry) Stake(address common.Address) *big.Int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if stake, ok := r.stakes[address]; ok {
+		return new(big.Int).Set(stake)
+	}
+	return nil
+}
+
+// Reads every operator's registration and stake. Operators that can't be read keep their previous state.
+// Satisfies admin.RegistryResyncer.
+func (r *Registry) Resync(ctx context.Context) error {
+	r.mu.RLock()
+	stakes := make(map[common.Address]*big.Int, len(r.stakes))
+	for operator, stake := range r.stakes {
+		stakes[operator] = stake
+	}
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, operator := range r.config.Operators {
+		stake, err := r.bondedStake(ctx, operator)
+		if err != nil {
+			r.logger.Warn("failed to read relay operator stake", "operator", operator, "error", err)
+			errs = append(errs, fmt.Errorf("operator %s: %w", operator.Hex(), err))
+			continue
+		}
+		if stake == nil {
+			delete(stakes, operator)
+		} else {
+			stakes[operator] = stake
+		}
+	}
+	r.mu.Lock()
+	r.stakes = stakes
+	r.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// Resyncs every interval until ctx is done
+func (r *Registry) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Resync(ctx)
+		}
+	}
+}
+
+// The operator's stake if registered with at least the minimum bond, otherwise nil
+func (r *Registry) bondedStake(ctx context.Context, operator common.Address) (*big.Int, error) {
+	var status uint8
+	if err := r.call(ctx, r.config.RegistryCoordinator, &status, "getOperatorStatus", operator); err != nil {
+		return nil, err
+	}
+	if status != operatorRegistered {
+		return nil, nil
+	}
+	var operatorID [32]byte
+	if err := r.call(ctx, r.config.RegistryCoordinator, &operatorID, "getOperatorId", operator); err != nil {
+		return nil, err
+	}
+	var stake *big.Int
+	if err := r.call(ctx, r.config.StakeRegistry, &stake, "getCurrentStake", operatorID, r.config.Quorum); err != nil {
+		return nil, err
+	}
+	if stake.Cmp(r.config.MinStake) < 0 {
+		return nil, nil
+	}
+	return stake, nil
+}
+
+func (r *Registry) call(ctx context.Context, contract common.Address, out any, method string, args ...any) error {
+	data, err := parsedRegistryABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	result, err := r.caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	values, err := parsedRegistryABI.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return parsedRegistryABI.Methods[method].Outputs.Copy(out, values)
+}
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
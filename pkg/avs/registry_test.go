@@ -0,0 +1,128 @@
+package avs_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+
+	"blob-preconfs/pkg/admin"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/avs"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ auction.RelayRegistry  = (*avs.Registry)(nil)
+	_ admin.RegistryResyncer = (*avs.Registry)(nil)
+)
+
+const registryABI = `[
+	{"type":"function","name":"getOperatorStatus","inputs":[{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"getOperatorId","inputs":[{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"getCurrentStake","inputs":[{"name":"operatorId","type":"bytes32"},{"name":"quorumNumber","type":"uint8"}],"outputs":[{"name":"","type":"uint96"}]}
+]`
+
+var (
+	coordinator   = common.HexToAddress("0xc0")
+	stakeRegistry = common.HexToAddress("0x5e")
+)
+
+type operatorState struct {
+	status uint8
+	stake  *big.Int
+	failed bool
+}
+
+// Serves the AVS contracts' view functions from operators' state, with operator ids being their addresses
+type mockContracts struct {
+	t         *testing.T
+	abi       abi.ABI
+	operators map[common.Address]*operatorState
+}
+
+func newMockContracts(t *testing.T) *mockContracts {
+	parsed, err := abi.JSON(strings.NewReader(registryABI))
+	require.NoError(t, err)
+	return &mockContracts{t: t, abi: parsed, operators: make(map[common.Address]*operatorState)}
+}
+
+func (m *mockContracts) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := m.abi.MethodById(call.Data[:4])
+	require.NoError(m.t, err)
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	require.NoError(m.t, err)
+
+	var operator common.Address
+	switch method.Name {
+	case "getOperatorStatus", "getOperatorId":
+		require.Equal(m.t, coordinator, *call.To)
+		operator = args[0].(common.Address)
+	case "getCurrentStake":
+		require.Equal(m.t, stakeRegistry, *call.To)
+		require.Equal(m.t, uint8(2), args[1].(uint8), "quorum")
+		id := args[0].([32]byte)
+		operator = common.BytesToAddress(id[:])
+	}
+	state, ok := m.operators[operator]
+	if !ok {
+		state = &operatorState{}
+	}
+	if state.failed {
+		return nil, errors.New("execution reverted")
+	}
+	switch method.Name {
+	case "getOperatorStatus":
+		return method.Outputs.Pack(state.status)
+	case "getOperatorId":
+		return method.Outputs.Pack(common.BytesToHash(operator.Bytes()))
+	default:
+		return method.Outputs.Pack(state.stake)
+	}
+}
+
+func TestRegistryReadsBonds(t *testing.T) {
+	contracts := newMockContracts(t)
+	bonded, deregistered, underbonded, unreadable := common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03"), common.HexToAddress("0x04")
+	contracts.operators[bonded] = &operatorState{status: 1, stake: big.NewInt(32e9)}
+	contracts.operators[deregistered] = &operatorState{status: 2, stake: big.NewInt(32e9)}
+	contracts.operators[underbonded] = &operatorState{status: 1, stake: big.NewInt(1e9)}
+	contracts.operators[unreadable] = &operatorState{status: 1, stake: big.NewInt(32e9)}
+
+	registry, err := avs.NewRegistry(slog.Default(), contracts, avs.Config{
+		RegistryCoordinator: coordinator,
+		StakeRegistry:       stakeRegistry,
+		Quorum:              2,
+		MinStake:            big.NewInt(16e9),
+		Operators:           []common.Address{bonded, deregistered, underbonded, unreadable},
+	})
+	require.NoError(t, err)
+	require.False(t, registry.IsRegisteredOnSettlementLayer(bonded), "unregistered until resynced")
+
+	require.NoError(t, registry.Resync(context.Background()))
+	require.True(t, registry.IsRegisteredOnSettlementLayer(bonded))
+	require.Equal(t, big.NewInt(32e9), registry.Stake(bonded))
+	require.False(t, registry.IsRegisteredOnSettlementLayer(deregistered))
+	require.False(t, registry.IsRegisteredOnSettlementLayer(underbonded))
+	require.Nil(t, registry.Stake(underbonded))
+	require.True(t, registry.IsRegisteredOnSettlementLayer(unreadable))
+
+	contracts.operators[bonded].stake = big.NewInt(8e9)
+	contracts.operators[underbonded].stake = big.NewInt(16e9)
+	contracts.operators[unreadable].failed = true
+	require.ErrorContains(t, registry.Resync(context.Background()), "execution reverted")
+	require.False(t, registry.IsRegisteredOnSettlementLayer(bonded), "stake fell below the bond")
+	require.True(t, registry.IsRegisteredOnSettlementLayer(underbonded))
+	require.True(t, registry.IsRegisteredOnSettlementLayer(unreadable), "keeps its previous state")
+}
+
+func TestRegistryConfig(t *testing.T) {
+	_, err := avs.NewRegistry(slog.Default(), newMockContracts(t), avs.Config{RegistryCoordinator: coordinator})
+	require.ErrorIs(t, err, avs.ErrInvalidConfig)
+}
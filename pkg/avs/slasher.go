@@ -0,0 +1,99 @@
+package avs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Subset of the EigenLayer middleware InstantSlasher ABI
+const slasherABI = `[
+	{"type":"function","name":"fulfillSlashingRequest","stateMutability":"nonpayable","inputs":[{"name":"params","type":"tuple","components":[
+		{"name":"operator","type":"address"},
+		{"name":"operatorSetId","type":"uint32"},
+		{"name":"strategies","type":"address[]"},
+		{"name":"wadsToSlash","type":"uint256[]"},
+		{"name":"description","type":"string"}]}],"outputs":[]}
+]`
+
+var parsedSlasherABI = mustParseABI(slasherABI)
+
+// AllocationManager SlashingParams, as taken by the slasher
+type slashingParams struct {
+	Operator      common.Address
+	OperatorSetId uint32
+	Strategies    []common.Address
+	WadsToSlash   []*big.Int
+	Description   string
+}
+
+// A relay's misbehavior, e.g. dropping a committed blob, to slash its restaked bond for
+type Evidence struct {
+	Operator common.Address
+	// Share of the operator's allocation slashed in every strategy, in WAD, 1e18 being all of it
+	Wad *big.Int
+	// What the relay did, e.g. the slot and the versioned hash of the blob it dropped
+	Description string
+}
+
+type SlasherConfig struct {
+	Slasher common.Address
+	// Operator set relays allocate their bond to, and the strategies slashed
+	OperatorSetID uint32
+	Strategies    []common.Address
+}
+
+// Routes slashing evidence through the AVS slasher rather than a bespoke staking contract. The key must be the
+// slasher's authorized caller.
+type Slasher struct {
+	logger   *slog.Logger
+	contract *bind.BoundContract
+	opts     *bind.TransactOpts
+	config   SlasherConfig
+}
+
+func NewSlasher(logger *slog.Logger, backend bind.ContractBackend, key *ecdsa.PrivateKey, chainID *big.Int, config SlasherConfig) (*Slasher, error) {
+	if config.Slasher == (common.Address{}) || len(config.Strategies) == 0 {
+		return nil, fmt.Errorf("%w: slasher and strategies required", ErrInvalidConfig)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &Slasher{
+		logger:   logger,
+		contract: bind.NewBoundContract(config.Slasher, parsedSlasherABI, backend, backend, backend),
+		opts:     opts,
+		config:   config,
+	}, nil
+}
+
+// Sends the slashing request, returning its tx hash once sent
+func (s *Slasher) Slash(ctx context.Context, evidence Evidence) (common.Hash, error) {
+	if evidence.Wad == nil || evidence.Wad.Sign() <= 0 {
+		return common.Hash{}, fmt.Errorf("invalid wad to slash %v", evidence.Wad)
+	}
+	wads := make([]*big.Int, len(s.config.Strategies))
+	for i := range wads {
+		wads[i] = evidence.Wad
+	}
+	opts := *s.opts
+	opts.Context = ctx
+	tx, err := s.contract.Transact(&opts, "fulfillSlashingRequest", slashingParams{
+		Operator:      evidence.Operator,
+		OperatorSetId: s.config.OperatorSetID,
+		Strategies:    s.config.Strategies,
+		WadsToSlash:   wads,
+		Description:   evidence.Description,
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send slashing request: %w", err)
+	}
+	s.logger.Warn("relay operator slashing requested", "operator", evidence.Operator, "wad", evidence.Wad, "tx", tx.Hash(), "description", evidence.Description)
+	return tx.Hash(), nil
+}
@@ -0,0 +1,100 @@
+package avs_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+
+	"blob-preconfs/pkg/avs"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// Accepts every transaction, keeping the last one sent
+type mockBackend struct {
+	bind.ContractBackend
+	sent *types.Transaction
+}
+
+func (b *mockBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return []byte{0x00}, nil
+}
+
+func (b *mockBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 7, nil
+}
+
+func (b *mockBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: big.NewInt(1)}, nil
+}
+
+func (b *mockBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *mockBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 100_000, nil
+}
+
+func (b *mockBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = tx
+	return nil
+}
+
+const slasherABI = `[{"type":"function","name":"fulfillSlashingRequest","inputs":[{"name":"params","type":"tuple","components":[
+	{"name":"operator","type":"address"},{"name":"operatorSetId","type":"uint32"},{"name":"strategies","type":"address[]"},
+	{"name":"wadsToSlash","type":"uint256[]"},{"name":"description","type":"string"}]}],"outputs":[]}]`
+
+func TestSlash(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	backend := &mockBackend{}
+	slasherAddress := common.HexToAddress("0x5a")
+	strategies := []common.Address{common.HexToAddress("0x51"), common.HexToAddress("0x52")}
+	slasher, err := avs.NewSlasher(slog.Default(), backend, key, big.NewInt(17000), avs.SlasherConfig{Slasher: slasherAddress, OperatorSetID: 3, Strategies: strategies})
+	require.NoError(t, err)
+
+	operator := common.HexToAddress("0x01")
+	wad := big.NewInt(5e16)
+	hash, err := slasher.Slash(context.Background(), avs.Evidence{Operator: operator, Wad: wad, Description: "dropped committed blob in slot 10"})
+	require.NoError(t, err)
+	require.NotNil(t, backend.sent)
+	require.Equal(t, backend.sent.Hash(), hash)
+	require.Equal(t, slasherAddress, *backend.sent.To())
+	require.Equal(t, uint64(7), backend.sent.Nonce())
+
+	parsed, err := abi.JSON(strings.NewReader(slasherABI))
+	require.NoError(t, err)
+	method, err := parsed.MethodById(backend.sent.Data()[:4])
+	require.NoError(t, err)
+	args, err := method.Inputs.Unpack(backend.sent.Data()[4:])
+	require.NoError(t, err)
+	params := args[0].(struct {
+		Operator      common.Address   `json:"operator"`
+		OperatorSetId uint32           `json:"operatorSetId"`
+		Strategies    []common.Address `json:"strategies"`
+		WadsToSlash   []*big.Int       `json:"wadsToSlash"`
+		Description   string           `json:"description"`
+	})
+	require.Equal(t, operator, params.Operator)
+	require.Equal(t, uint32(3), params.OperatorSetId)
+	require.Equal(t, strategies, params.Strategies)
+	require.Equal(t, []*big.Int{wad, wad}, params.WadsToSlash, "every strategy is slashed")
+	require.Equal(t, "dropped committed blob in slot 10", params.Description)
+
+	_, err = slasher.Slash(context.Background(), avs.Evidence{Operator: operator})
+	require.Error(t, err, "nothing to slash")
+}
+
+func TestSlasherConfig(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	_, err := avs.NewSlasher(slog.Default(), &mockBackend{}, key, big.NewInt(1), avs.SlasherConfig{Slasher: common.HexToAddress("0x5a")})
+	require.ErrorIs(t, err, avs.ErrInvalidConfig)
+}
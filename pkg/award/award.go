@@ -0,0 +1,133 @@
+package award
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Auctioneer's signed notice to a relay that its bid won the auction for the bid's L1 block
+type Award struct {
+	Bid auction.SignedBid `json:"bid"`
+	// Unix milliseconds
+	AwardedAt  int64          `json:"awardedAt"`
+	Auctioneer common.Address `json:"auctioneer"`
+	Signature  hexutil.Bytes  `json:"signature"`
+}
+
+func CreateSignedAward(bid auction.SignedBid, awardedAt time.Time, privateKey *ecdsa.PrivateKey) (*Award, error) {
+	a := Award{Bid: bid, AwardedAt: awardedAt.UnixMilli(), Auctioneer: crypto.PubkeyToAddress(privateKey.PublicKey)}
+	signature, err := crypto.Sign(a.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	a.Signature = signature
+	return &a, nil
+}
+
+// Hash of the signed fields. The winning bid is bound by its signature, which covers its amount and block.
+func (a *Award) Hash() common.Hash {
+	data := make([]byte, 0, 2*common.HashLength+2*common.AddressLength+8+len(a.Bid.Signature))
+	data = append(data, common.BigToHash(a.Bid.L1Block).Bytes()...)
+	data = append(data, common.BigToHash(a.Bid.AmountWei).Bytes()...)
+	data = append(data, a.Bid.Address.Bytes()...)
+	data = append(data, a.Bid.Signature...)
+	data = binary.BigEndian.AppendUint64(data, uint64(a.AwardedAt))
+	data = append(data, a.Auctioneer.Bytes()...)
+	return crypto.Keccak256Hash(data)
+}
+
+// Checks the award is signed by its auctioneer, which relays should check is one they trust, see keys.Signers
+func (a *Award) Verify() bool {
+	if a.Bid.L1Block == nil || a.Bid.AmountWei == nil {
+		return false
+	}
+	sigPublicKey, err := crypto.SigToPub(a.Hash().Bytes(), a.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == a.Auctioneer
+}
+
+// Relay's counter-signature of a delivered award, accepting or declining it
+type Ack struct {
+	AwardHash common.Hash   `json:"awardHash"`
+	Accepted  bool          `json:"accepted"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// To be used by the winning relay's account to accept or decline the award
+func CreateSignedAck(awardHash common.Hash, accepted bool, privateKey *ecdsa.PrivateKey) (*Ack, error) {
+	ack := Ack{AwardHash: awardHash, Accepted: accepted}
+	signature, err := crypto.Sign(ack.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	ack.Signature = signature
+	return &ack, nil
+}
+
+func (a *Ack) Hash() common.Hash {
+	accepted := byte(0)
+	if a.Accepted {
+		accepted = 1
+	}
+	return crypto.Keccak256Hash(a.AwardHash.Bytes(), []byte{accepted})
+}
+
+// Checks the ack is signed by relay, the winner of the award it acknowledges
+func (a *Ack) Verify(relay common.Address) bool {
+	sigPublicKey, err := crypto.SigToPub(a.Hash().Bytes(), a.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == relay
+}
+
+// Auctioneer's signed release of the content keys of encrypted blobs targeting an award's block, sealed to the
+// winner, sent once it accepts the award (see intake.Pool.ReleaseKeys)
+type Release struct {
+	AwardHash  common.Hash         `json:"awardHash"`
+	L1Block    uint64              `json:"l1Block"`
+	Keys       []intake.KeyRelease `json:"keys"`
+	Auctioneer common.Address      `json:"auctioneer"`
+	Signature  hexutil.Bytes       `json:"signature"`
+}
+
+func CreateSignedRelease(a *Award, keys []intake.KeyRelease, privateKey *ecdsa.PrivateKey) (*Release, error) {
+	r := Release{AwardHash: a.Hash(), L1Block: a.Bid.L1Block.Uint64(), Keys: keys, Auctioneer: crypto.PubkeyToAddress(privateKey.PublicKey)}
+	signature, err := crypto.Sign(r.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	r.Signature = signature
+	return &r, nil
+}
+
+func (r *Release) Hash() common.Hash {
+	data := make([]byte, 0, common.HashLength+8+common.AddressLength+len(r.Keys)*2*common.HashLength)
+	data = append(data, r.AwardHash.Bytes()...)
+	data = binary.BigEndian.AppendUint64(data, r.L1Block)
+	data = append(data, r.Auctioneer.Bytes()...)
+	for _, key := range r.Keys {
+		data = append(data, key.RequestID.Bytes()...)
+		data = append(data, crypto.Keccak256(key.SealedKey)...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+// Checks the release is signed by its auctioneer, which relays should check is one they trust
+func (r *Release) Verify() bool {
+	sigPublicKey, err := crypto.SigToPub(r.Hash().Bytes(), r.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == r.Auctioneer
+}
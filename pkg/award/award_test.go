@@ -0,0 +1,172 @@
+package award_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/award"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func winningBid(t *testing.T, l1Block int64) (auction.SignedBid, *ecdsa.PrivateKey) {
+	relayKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return *auction.MustCreateSignedBid(big.NewInt(1000), big.NewInt(l1Block), relayKey), relayKey
+}
+
+func TestSignedAward(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bid, relayKey := winningBid(t, 100)
+	a, err := award.CreateSignedAward(bid, time.Now(), key)
+	require.NoError(t, err)
+	require.True(t, a.Verify())
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), a.Auctioneer)
+
+	tampered := *a
+	tampered.Bid.AmountWei = big.NewInt(1)
+	require.False(t, tampered.Verify(), "the winning bid is signed")
+	tampered = *a
+	tampered.Auctioneer = common.HexToAddress("0x01")
+	require.False(t, tampered.Verify())
+
+	ack, err := award.CreateSignedAck(a.Hash(), true, relayKey)
+	require.NoError(t, err)
+	require.True(t, ack.Verify(bid.Address))
+	require.False(t, ack.Verify(a.Auctioneer))
+	ack.Accepted = false
+	require.False(t, ack.Verify(bid.Address), "the answer is signed")
+}
+
+// Answers awards after failing the first failures requests
+type relay struct {
+	mu       sync.Mutex
+	key      *ecdsa.PrivateKey
+	awards   []award.Award
+	failures int
+	decline  bool
+	unsigned bool
+	delay    time.Duration
+}
+
+func (r *relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	time.Sleep(r.delay)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var a award.Award
+	json.NewDecoder(req.Body).Decode(&a)
+	r.awards = append(r.awards, a)
+	ack, _ := award.CreateSignedAck(a.Hash(), !r.decline, r.key)
+	if r.unsigned {
+		ack.Signature = nil
+	}
+	json.NewEncoder(w).Encode(ack)
+}
+
+type outcomes struct {
+	mu        sync.Mutex
+	accepted  []uint64
+	defaulted map[uint64]string
+}
+
+func (o *outcomes) AwardAccepted(a *award.Award) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.accepted = append(o.accepted, a.Bid.L1Block.Uint64())
+}
+
+func (o *outcomes) AwardDefaulted(a *award.Award, reason string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.defaulted[a.Bid.L1Block.Uint64()] = reason
+}
+
+func newNotifier(t *testing.T, r *relay, relayAddress common.Address, config award.Config) (*award.Notifier, *outcomes) {
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	config.Endpoints = map[common.Address]string{relayAddress: server.URL}
+	n, err := award.NewNotifier(slog.Default(), config, key)
+	require.NoError(t, err)
+	o := &outcomes{defaulted: make(map[uint64]string)}
+	n.SetObserver(o)
+	return n, o
+}
+
+func TestNotifyRetriesUntilAccepted(t *testing.T) {
+	bid, relayKey := winningBid(t, 100)
+	r := &relay{key: relayKey, failures: 1}
+	n, o := newNotifier(t, r, bid.Address, award.Config{})
+	require.True(t, n.Notify(bid))
+	other, _ := winningBid(t, 101)
+	require.False(t, n.Notify(other), "no endpoint")
+	n.Close()
+
+	require.Len(t, r.awards, 1)
+	require.True(t, r.awards[0].Verify())
+	require.Equal(t, bid.Signature, r.awards[0].Bid.Signature)
+	d, ok := n.Delivery(100)
+	require.True(t, ok)
+	require.Equal(t, award.StatusAccepted, d.Status)
+	require.Equal(t, 2, d.Attempts)
+	require.Equal(t, r.awards[0].Hash(), d.AwardHash)
+	require.NotNil(t, d.AcceptedAt)
+	require.Empty(t, d.Error)
+	require.Equal(t, []uint64{100}, o.accepted)
+	_, ok = n.Delivery(101)
+	require.False(t, ok, "relays without an endpoint poll for results")
+	require.Len(t, n.Deliveries(), 1)
+}
+
+func TestDefaults(t *testing.T) {
+	for name, test := range map[string]struct {
+		relay  *relay
+		reason string
+		err    string
+	}{
+		"declined":      {&relay{decline: true}, award.ReasonDeclined, ""},
+		"unsigned ack":  {&relay{unsigned: true}, award.ReasonTimedOut, "ack not signed by the winner"},
+		"unavailable":   {&relay{failures: 10}, award.ReasonTimedOut, "relay returned status 503"},
+		"past deadline": {&relay{delay: 300 * time.Millisecond}, award.ReasonTimedOut, "deadline exceeded"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			bid, relayKey := winningBid(t, 100)
+			test.relay.key = relayKey
+			n, o := newNotifier(t, test.relay, bid.Address, award.Config{AcceptDeadline: 200 * time.Millisecond})
+			n.Notify(bid)
+			n.Close()
+
+			d, _ := n.Delivery(100)
+			require.Equal(t, award.StatusDefaulted, d.Status)
+			require.Equal(t, test.reason, d.Reason)
+			require.Contains(t, d.Error, test.err)
+			require.Nil(t, d.AcceptedAt)
+			require.Equal(t, map[uint64]string{100: test.reason}, o.defaulted)
+			require.Empty(t, o.accepted)
+		})
+	}
+}
+
+func TestNotifierRejectsInvalidEndpoint(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = award.NewNotifier(slog.Default(), award.Config{Endpoints: map[common.Address]string{{}: "relay.example.com"}}, key)
+	require.ErrorIs(t, err, award.ErrInvalidConfig)
+}
@@ -0,0 +1,368 @@
+package award
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// Deliveries of the most recent blocks kept for status queries
+	deliveryRetention = 64
+	retryBackoff      = 500 * time.Millisecond
+)
+
+var ErrInvalidConfig = errors.New("invalid award config")
+
+// Reasons a winner defaulted on its award
+const (
+	ReasonDeclined = "declined"
+	// Not accepted before the deadline, e.g. the relay is down
+	ReasonTimedOut = "timed out"
+)
+
+type Config struct {
+	// Callback URLs awards are posted to, by the address relays bid with. Relays without one poll for results.
+	Endpoints map[common.Address]string
+	// Delivery attempts per award, 3 if 0
+	Attempts int
+	// Per delivery attempt, 5s if 0
+	Timeout time.Duration
+	// Time the winner has to accept its award from when it's awarded, 2s if 0
+	AcceptDeadline time.Duration
+}
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusAccepted Status = "accepted"
+	// The winner defaulted, declining its award or not accepting it before the deadline
+	StatusDefaulted Status = "defaulted"
+)
+
+// Handshake of one auction's award with its winner
+type Delivery struct {
+	L1Block    uint64         `json:"l1Block"`
+	Relay      common.Address `json:"relay"`
+	Endpoint   string         `json:"endpoint"`
+	AwardHash  common.Hash    `json:"awardHash"`
+	Status     Status         `json:"status"`
+	Attempts   int            `json:"attempts"`
+	AcceptedAt *time.Time     `json:"acceptedAt,omitempty"`
+	// Why the winner defaulted, for defaulted awards
+	Reason string `json:"reason,omitempty"`
+	// Last failed attempt's error
+	Error string `json:"error,omitempty"`
+	// Content keys of encrypted blobs released to the winner once it accepted
+	KeysReleased int `json:"keysReleased,omitempty"`
+}
+
+// Told the outcome of each award's handshake, e.g. to settle accepted awards and fall back to the runner-up on
+// defaults. Called from the notifier's goroutines.
+type Observer interface {
+	AwardAccepted(a *Award)
+	AwardDefaulted(a *Award, reason string)
+}
+
+// Releases the content keys of encrypted blobs targeting a block to its winner, sealed to its key. Satisfied by
+// *intake.Pool.
+type KeyReleaser interface {
+	ReleaseKeys(targetBlock *big.Int, winner *ecdsa.PublicKey) ([]intake.KeyRelease, error)
+}
+
+// Notifies winning relays of their award at their callback endpoint, instead of relying on them to poll for
+// results, and requires them to accept it. Awards are signed and delivered in the background, retried with
+// backoff until the relay counter-signs an ack or the accept deadline passes.
+type Notifier struct {
+	logger     *slog.Logger
+	config     Config
+	signingKey *ecdsa.PrivateKey
+	httpClient *http.Client
+	observer   Observer
+	releaser   KeyReleaser
+	wg         sync.WaitGroup
+
+	mu         sync.Mutex // Protects access to fields below
+	closed     bool
+	deliveries map[uint64]*Delivery
+}
+
+// Awards are signed with signingKey, the auctioneer's commitment signing key
+func NewNotifier(logger *slog.Logger, config Config, signingKey *ecdsa.PrivateKey) (*Notifier, error) {
+	for relay, endpoint := range config.Endpoints {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("%w: invalid endpoint %q for relay %s", ErrInvalidConfig, endpoint, relay)
+		}
+	}
+	if config.Attempts == 0 {
+		config.Attempts = 3
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.AcceptDeadline == 0 {
+		config.AcceptDeadline = 2 * time.Second
+	}
+	return &Notifier{
+		logger:     logger,
+		config:     config,
+		signingKey: signingKey,
+		httpClient: &http.Client{},
+		deliveries: make(map[uint64]*Delivery),
+	}, nil
+}
+
+// Handshake outcomes are reported to the observer, if set before awards are notified
+func (n *Notifier) SetObserver(observer Observer) {
+	n.observer = observer
+}
+
+// Winners that accept their award are sent the content keys of encrypted blobs targeting its block, from releaser,
+// if set before awards are notified
+func (n *Notifier) SetKeyReleaser(releaser KeyReleaser) {
+	n.releaser = releaser
+}
+
+// Signs the award of the winning bid and delivers it in the background. Returns false, without notifying, if
+// the winner has no endpoint, in which case there's no handshake.
+func (n *Notifier) Notify(winner auction.SignedBid) bool {
+	endpoint, ok := n.config.Endpoints[winner.Address]
+	if !ok {
+		n.logger.Debug("no award endpoint for winner, skipping notification", "winner", winner.Address)
+		return false
+	}
+	a, err := CreateSignedAward(winner, time.Now(), n.signingKey)
+	if err != nil {
+		n.logger.Error("failed to sign award", "blockNumber", winner.L1Block, "error", err)
+		return false
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return false
+	}
+	l1Block := winner.L1Block.Uint64()
+	n.deliveries[l1Block] = &Delivery{L1Block: l1Block, Relay: winner.Address, Endpoint: endpoint, AwardHash: a.Hash(), Status: StatusPending}
+	for block := range n.deliveries {
+		if block+deliveryRetention < l1Block {
+			delete(n.deliveries, block)
+		}
+	}
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.handshake(a, endpoint)
+	}()
+	return true
+}
+
+// Handshake of the auction for the L1 block, if its winner was notified recently
+func (n *Notifier) Delivery(l1Block uint64) (Delivery, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	d, ok := n.deliveries[l1Block]
+	if !ok {
+		return Delivery{}, false
+	}
+	return *d, true
+}
+
+// Recent handshakes, by L1 block, e.g. for diagnostics
+func (n *Notifier) Deliveries() []Delivery {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	deliveries := make([]Delivery, 0, len(n.deliveries))
+	for _, d := range n.deliveries {
+		deliveries = append(deliveries, *d)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].L1Block < deliveries[j].L1Block })
+	return deliveries
+}
+
+// Delivers the award until the relay acks it, retrying failed attempts until the deadline, then reports the outcome
+func (n *Notifier) handshake(a *Award, endpoint string) {
+	l1Block := a.Bid.L1Block.Uint64()
+	ctx, cancel := context.WithDeadline(context.Background(), time.UnixMilli(a.AwardedAt).Add(n.config.AcceptDeadline))
+	defer cancel()
+	var ack *Ack
+	var err error
+	for attempt := 0; attempt < n.config.Attempts && ctx.Err() == nil; attempt++ {
+		if attempt > 0 && !sleep(ctx, time.Duration(attempt)*retryBackoff) {
+			break
+		}
+		attemptCtx, cancelAttempt := context.WithTimeout(ctx, n.config.Timeout)
+		ack, err = n.post(attemptCtx, endpoint, a)
+		cancelAttempt()
+		n.update(l1Block, func(d *Delivery) {
+			d.Attempts++
+			if err != nil {
+				d.Error = err.Error()
+			}
+		})
+		if err == nil {
+			break
+		}
+	}
+
+	if ack != nil && ack.Accepted {
+		now := time.Now()
+		n.update(l1Block, func(d *Delivery) { d.Status, d.AcceptedAt, d.Error = StatusAccepted, &now, "" })
+		n.logger.Info("award accepted", "blockNumber", l1Block, "winner", a.Bid.Address)
+		if n.observer != nil {
+			n.observer.AwardAccepted(a)
+		}
+		if n.releaser != nil {
+			n.releaseKeys(a, ack, endpoint)
+		}
+		return
+	}
+	reason := ReasonTimedOut
+	if ack != nil {
+		reason = ReasonDeclined
+	}
+	n.update(l1Block, func(d *Delivery) { d.Status, d.Reason = StatusDefaulted, reason })
+	n.logger.Warn("winner defaulted on its award", "blockNumber", l1Block, "winner", a.Bid.Address, "reason", reason, "error", err)
+	if n.observer != nil {
+		n.observer.AwardDefaulted(a, reason)
+	}
+}
+
+// Posts the content keys of encrypted blobs targeting the award's block to the winner, sealed to the key it signed
+// its ack with, retrying failed attempts with backoff
+func (n *Notifier) releaseKeys(a *Award, ack *Ack, endpoint string) {
+	l1Block := a.Bid.L1Block.Uint64()
+	winner, err := crypto.SigToPub(ack.Hash().Bytes(), ack.Signature)
+	if err != nil {
+		n.logger.Error("failed to recover winner's key", "blockNumber", l1Block, "error", err)
+		return
+	}
+	keys, err := n.releaser.ReleaseKeys(a.Bid.L1Block, winner)
+	if err != nil {
+		n.logger.Error("failed to release content keys", "blockNumber", l1Block, "error", err)
+		return
+	}
+	if len(keys) == 0 {
+		return
+	}
+	r, err := CreateSignedRelease(a, keys, n.signingKey)
+	if err != nil {
+		n.logger.Error("failed to sign key release", "blockNumber", l1Block, "error", err)
+		return
+	}
+	for attempt := 0; attempt < n.config.Attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * retryBackoff)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), n.config.Timeout)
+		err = n.postRelease(ctx, endpoint, r)
+		cancel()
+		if err == nil {
+			n.update(l1Block, func(d *Delivery) { d.KeysReleased = len(keys) })
+			n.logger.Info("content keys released to winner", "blockNumber", l1Block, "winner", a.Bid.Address, "keys", len(keys))
+			return
+		}
+	}
+	n.update(l1Block, func(d *Delivery) { d.Error = err.Error() })
+	n.logger.Error("failed to release content keys to winner", "blockNumber", l1Block, "winner", a.Bid.Address, "error", err)
+}
+
+func (n *Notifier) update(l1Block uint64, update func(d *Delivery)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if d, ok := n.deliveries[l1Block]; ok {
+		update(d)
+	}
+}
+
+// Succeeds once the winner returns an ack of the award it signed, accepting or declining it
+func (n *Notifier) post(ctx context.Context, endpoint string, a *Award) (*Ack, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("relay returned status %d: %s", resp.StatusCode, msg)
+	}
+	var ack Ack
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024)).Decode(&ack); err != nil {
+		return nil, fmt.Errorf("invalid ack: %w", err)
+	}
+	if ack.AwardHash != a.Hash() {
+		return nil, fmt.Errorf("relay acked award %s, expected %s", ack.AwardHash, a.Hash())
+	}
+	if !ack.Verify(a.Bid.Address) {
+		return nil, fmt.Errorf("ack not signed by the winner")
+	}
+	return &ack, nil
+}
+
+func (n *Notifier) postRelease(ctx context.Context, endpoint string, r *Release) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("relay returned status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+// Stops notifying awards, and waits for handshakes in progress to conclude
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+	n.wg.Wait()
+}
+
+// Returns false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,152 @@
+package batcher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/keys"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid batcher config")
+	ErrNoBlobs       = errors.New("batch tx carries no blobs")
+)
+
+// Preconf request intake, e.g. *intake.Pool
+type Intake interface {
+	Submit(req intake.PreconfRequest) (common.Hash, error)
+	Remove(id common.Hash)
+}
+
+// Sends blob txs to L1, e.g. *ethclient.Client
+type Sender interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+type Config struct {
+	// Fee offered per batch, for all its blobs
+	MaxFeeWei *big.Int
+	// How long to wait for a commitment before submitting the batch directly, 4s if 0
+	Deadline time.Duration
+	// Keys commitments must be signed with, e.g. the auctioneer's announced in its status, any if nil
+	Signers *keys.Signers
+}
+
+type Result struct {
+	RequestHash common.Hash
+	// Commitment obtained by the deadline, nil if the batch fell back to direct submission
+	Commitment *commitment.Commitment
+	TxHash     common.Hash
+}
+
+// Submits OP stack or Arbitrum style batch blob txs for preconfirmation, falling back to plain L1 submission if
+// no relay commits to them by the deadline. Commitments are observed from the coordinator, so the batcher must be
+// set as (one of) its observers.
+type Batcher struct {
+	logger *slog.Logger
+	intake Intake
+	sender Sender
+	key    *ecdsa.PrivateKey
+	config Config
+
+	mu      sync.Mutex // Protects access to waiting
+	waiting map[common.Hash]chan commitment.Commitment
+}
+
+func NewBatcher(logger *slog.Logger, intake Intake, sender Sender, key *ecdsa.PrivateKey, config Config) (*Batcher, error) {
+	if config.MaxFeeWei == nil || config.MaxFeeWei.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: max fee required", ErrInvalidConfig)
+	}
+	if config.Deadline < 0 {
+		return nil, fmt.Errorf("%w: negative deadline", ErrInvalidConfig)
+	}
+	if config.Deadline == 0 {
+		config.Deadline = 4 * time.Second
+	}
+	return &Batcher{
+		logger:  logger,
+		intake:  intake,
+		sender:  sender,
+		key:     key,
+		config:  config,
+		waiting: make(map[common.Hash]chan commitment.Commitment),
+	}, nil
+}
+
+// Requests a commitment to include the batch tx's blobs in targetBlock, then sends the tx. Without a commitment
+// by the deadline the request is withdrawn and the tx is sent anyway, to land like any other blob tx. All of a
+// tx's blobs land together, so they're requested as an atomic bundle.
+func (b *Batcher) Submit(ctx context.Context, tx *types.Transaction, targetBlock *big.Int) (*Result, error) {
+	versionedHashes := tx.BlobHashes()
+	if len(versionedHashes) == 0 {
+		return nil, ErrNoBlobs
+	}
+	req, err := intake.CreateSignedTxRequest([]*types.Transaction{tx}, targetBlock, b.config.MaxFeeWei, b.key)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{RequestHash: req.Hash(), TxHash: tx.Hash()}
+
+	committed := make(chan commitment.Commitment, 1)
+	b.mu.Lock()
+	b.waiting[result.RequestHash] = committed
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.waiting, result.RequestHash)
+		b.mu.Unlock()
+	}()
+
+	if _, err := b.intake.Submit(*req); err != nil {
+		b.logger.Warn("preconf request rejected, submitting batch directly", "tx", result.TxHash, "error", err)
+	} else {
+		deadline := time.NewTimer(b.config.Deadline)
+		defer deadline.Stop()
+		select {
+		case c := <-committed:
+			result.Commitment = &c
+		case <-deadline.C:
+			b.intake.Remove(result.RequestHash)
+			b.logger.Warn("no commitment by the deadline, submitting batch directly", "tx", result.TxHash, "targetBlock", targetBlock)
+		case <-ctx.Done():
+			b.intake.Remove(result.RequestHash)
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := b.sender.SendTransaction(ctx, tx); err != nil {
+		return result, fmt.Errorf("failed to send batch tx: %w", err)
+	}
+	b.logger.Info("batch submitted", "tx", result.TxHash, "blobs", len(versionedHashes), "committed", result.Commitment != nil)
+	return result, nil
+}
+
+// To satisfy commitment.Observer. Commitments to requests other than this batcher's are ignored.
+func (b *Batcher) CommitmentIssued(c commitment.Commitment) {
+	if !c.Verify() || (b.config.Signers != nil && !b.config.Signers.Valid(c.Committer, time.Now())) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if committed, ok := b.waiting[c.RequestHash]; ok {
+		select {
+		case committed <- c:
+		default:
+		}
+	}
+}
+
+// To satisfy commitment.Observer
+func (b *Batcher) CommitmentMissed(c commitment.Commitment, reason commitment.MissReason, block *big.Int) {
+}
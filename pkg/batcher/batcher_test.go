@@ -0,0 +1,142 @@
+package batcher_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/batcher"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/keys"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var _ commitment.Observer = (*batcher.Batcher)(nil)
+
+type mockSender struct {
+	mu   sync.Mutex
+	sent []common.Hash
+}
+
+func (s *mockSender) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, tx.Hash())
+	return nil
+}
+
+func batchTx() *types.Transaction {
+	return types.NewTx(&types.BlobTx{BlobHashes: []common.Hash{{0x01, 0x01}, {0x01, 0x02}}})
+}
+
+func newBatcher(t *testing.T, config intake.Config, deadline time.Duration) (*batcher.Batcher, *intake.Pool, *commitment.Coordinator, *mockSender) {
+	relayKey, _ := crypto.GenerateKey()
+	batcherKey, _ := crypto.GenerateKey()
+	pool, err := intake.NewPool(slog.Default(), config, nil)
+	require.NoError(t, err)
+	coordinator := commitment.NewCoordinator(slog.Default(), commitment.Config{}, nil, nil, relayKey)
+	sender := &mockSender{}
+	b, err := batcher.NewBatcher(slog.Default(), pool, sender, batcherKey, batcher.Config{MaxFeeWei: big.NewInt(1e9), Deadline: deadline})
+	require.NoError(t, err)
+	coordinator.SetObserver(b)
+	return b, pool, coordinator, sender
+}
+
+func TestSubmitCommitted(t *testing.T) {
+	b, pool, coordinator, sender := newBatcher(t, intake.Config{}, 5*time.Second)
+	targetBlock := big.NewInt(100)
+	go func() {
+		// Relay winning the auction commits to the pending request
+		for {
+			if reqs := pool.Pending(targetBlock); len(reqs) > 0 {
+				coordinator.Issue(reqs[0], big.NewInt(1e9), big.NewInt(101))
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	tx := batchTx()
+	result, err := b.Submit(context.Background(), tx, targetBlock)
+	require.NoError(t, err)
+	require.NotNil(t, result.Commitment)
+	require.Equal(t, result.RequestHash, result.Commitment.RequestHash)
+	require.True(t, result.Commitment.Atomic)
+	require.Equal(t, tx.BlobHashes(), result.Commitment.VersionedHashes)
+	require.Equal(t, []common.Hash{tx.Hash()}, sender.sent)
+}
+
+func TestSubmitChecksSigners(t *testing.T) {
+	previousKey, _ := crypto.GenerateKey()
+	previous := crypto.PubkeyToAddress(previousKey.PublicKey)
+	for name, test := range map[string]struct {
+		until     time.Time
+		committed bool
+	}{
+		"grace period": {time.Now().Add(time.Hour), true},
+		"expired":      {time.Now().Add(-time.Second), false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			batcherKey, _ := crypto.GenerateKey()
+			pool, err := intake.NewPool(slog.Default(), intake.Config{}, nil)
+			require.NoError(t, err)
+			coordinator := commitment.NewCoordinator(slog.Default(), commitment.Config{}, nil, nil, previousKey)
+			signers := &keys.Signers{Active: common.Address{0x01}, Previous: &previous, PreviousUntil: &test.until}
+			b, err := batcher.NewBatcher(slog.Default(), pool, &mockSender{}, batcherKey, batcher.Config{MaxFeeWei: big.NewInt(1e9), Deadline: 200 * time.Millisecond, Signers: signers})
+			require.NoError(t, err)
+			coordinator.SetObserver(b)
+			targetBlock := big.NewInt(100)
+			go func() {
+				for {
+					if reqs := pool.Pending(targetBlock); len(reqs) > 0 {
+						coordinator.Issue(reqs[0], big.NewInt(1e9), big.NewInt(101))
+						return
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+
+			result, err := b.Submit(context.Background(), batchTx(), targetBlock)
+			require.NoError(t, err)
+			require.Equal(t, test.committed, result.Commitment != nil)
+		})
+	}
+}
+
+func TestSubmitFallsBackAfterDeadline(t *testing.T) {
+	b, pool, _, sender := newBatcher(t, intake.Config{}, 100*time.Millisecond)
+	targetBlock := big.NewInt(100)
+	tx := batchTx()
+	start := time.Now()
+	result, err := b.Submit(context.Background(), tx, targetBlock)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	require.Nil(t, result.Commitment)
+	require.Equal(t, []common.Hash{tx.Hash()}, sender.sent, "submitted directly")
+	require.Empty(t, pool.Pending(targetBlock), "request withdrawn")
+}
+
+func TestSubmitFallsBackOnRejection(t *testing.T) {
+	b, _, _, sender := newBatcher(t, intake.Config{MaxRequestsPerWindow: 1, QuotaWindow: time.Minute}, 100*time.Millisecond)
+	first, second := batchTx(), types.NewTx(&types.BlobTx{Nonce: 1, BlobHashes: []common.Hash{{0x01, 0x03}}})
+	_, err := b.Submit(context.Background(), first, big.NewInt(100))
+	require.NoError(t, err)
+
+	start := time.Now()
+	result, err := b.Submit(context.Background(), second, big.NewInt(101))
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), 100*time.Millisecond, "quota exceeded, so no wait for the deadline")
+	require.Nil(t, result.Commitment)
+	require.Equal(t, []common.Hash{first.Hash(), second.Hash()}, sender.sent)
+
+	_, err = b.Submit(context.Background(), types.NewTx(&types.BlobTx{}), big.NewInt(100))
+	require.ErrorIs(t, err, batcher.ErrNoBlobs)
+}
@@ -0,0 +1,193 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"blob-preconfs/pkg/availability"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid beacon client config")
+	// Every endpoint responded 404, e.g. for a slot without a block
+	ErrNotFound = errors.New("not found")
+)
+
+type Config struct {
+	// Beacon node URLs. Requests go to the last one that responded, failing over to the others in order.
+	Endpoints []string
+	// Timeout of each request, 5s if 0
+	Timeout time.Duration
+	// Rounds over every endpoint before giving up, 3 if 0
+	Attempts int
+	// Wait before the next round, multiplied by the rounds so far, 500ms if 0
+	Backoff time.Duration
+}
+
+// Validator proposing the slot
+type ProposerDuty struct {
+	Pubkey         hexutil.Bytes `json:"pubkey"`
+	ValidatorIndex uint64        `json:"validator_index,string"`
+	Slot           uint64        `json:"slot,string"`
+}
+
+type BeaconBlockHeader struct {
+	Slot          uint64      `json:"slot,string"`
+	ProposerIndex uint64      `json:"proposer_index,string"`
+	ParentRoot    common.Hash `json:"parent_root"`
+	StateRoot     common.Hash `json:"state_root"`
+	BodyRoot      common.Hash `json:"body_root"`
+}
+
+type SignedBeaconBlockHeader struct {
+	Message   BeaconBlockHeader `json:"message"`
+	Signature hexutil.Bytes     `json:"signature"`
+}
+
+type Header struct {
+	Root      common.Hash             `json:"root"`
+	Canonical bool                    `json:"canonical"`
+	Header    SignedBeaconBlockHeader `json:"header"`
+}
+
+type response[T any] struct {
+	Data T `json:"data"`
+}
+
+// Minimal beacon node API client, for proposer duties, block headers and blob sidecars.
+// Satisfies availability.SidecarSource.
+type Client struct {
+	logger     *slog.Logger
+	config     Config
+	httpClient *http.Client
+	// Endpoint tried first, the last one that responded
+	preferred atomic.Int32
+}
+
+func NewClient(logger *slog.Logger, config Config) (*Client, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("%w: no endpoints", ErrInvalidConfig)
+	}
+	endpoints := make([]string, len(config.Endpoints))
+	for i, endpoint := range config.Endpoints {
+		if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+			return nil, fmt.Errorf("%w: invalid endpoint %q", ErrInvalidConfig, endpoint)
+		}
+		endpoints[i] = strings.TrimSuffix(endpoint, "/")
+	}
+	config.Endpoints = endpoints
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.Attempts <= 0 {
+		config.Attempts = 3
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = 500 * time.Millisecond
+	}
+	return &Client{
+		logger:     logger,
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Proposers of every slot in the epoch
+func (c *Client) ProposerDuties(ctx context.Context, epoch uint64) ([]ProposerDuty, error) {
+	var resp response[[]ProposerDuty]
+	if err := c.get(ctx, "/eth/v1/validator/duties/proposer/"+strconv.FormatUint(epoch, 10), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Header of the block, by slot, root, or head, genesis or finalized
+func (c *Client) Header(ctx context.Context, blockID string) (*Header, error) {
+	var resp response[Header]
+	if err := c.get(ctx, "/eth/v1/beacon/headers/"+blockID, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// To satisfy availability.SidecarSource
+func (c *Client) GetBlobSidecars(ctx context.Context, slot uint64) ([]availability.BlobSidecar, error) {
+	var resp response[[]availability.BlobSidecar]
+	if err := c.get(ctx, "/eth/v1/beacon/blob_sidecars/"+strconv.FormatUint(slot, 10), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Tries every endpoint, starting from the preferred one, for each attempt. Endpoints responding 404 aren't
+// retried, as long as none fails otherwise.
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	var lastErr error
+	for attempt := 0; attempt < c.config.Attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * c.config.Backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		notFound := 0
+		start := int(c.preferred.Load())
+		for i := range c.config.Endpoints {
+			index := (start + i) % len(c.config.Endpoints)
+			endpoint := c.config.Endpoints[index]
+			err := c.getFrom(ctx, endpoint+path, out)
+			if err == nil {
+				c.preferred.Store(int32(index))
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, ErrNotFound) {
+				notFound++
+			} else {
+				c.logger.Warn("beacon node request failed", "endpoint", endpoint, "path", path, "attempt", attempt+1, "error", err)
+			}
+			lastErr = err
+		}
+		if notFound == len(c.config.Endpoints) {
+			return fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+	}
+	return fmt.Errorf("beacon node request %s failed: %w", path, lastErr)
+}
+
+func (c *Client) getFrom(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("beacon node returned status %d: %s", resp.StatusCode, msg)
+	}
+}
@@ -0,0 +1,136 @@
+package beacon_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/availability"
+	"blob-preconfs/pkg/beacon"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var _ availability.SidecarSource = (*beacon.Client)(nil)
+
+// Beacon node serving fixed responses by path, or failing with status if set
+type mockNode struct {
+	server *httptest.Server
+	status atomic.Int32
+	calls  atomic.Int32
+}
+
+func newMockNode(t *testing.T, responses map[string]string) *mockNode {
+	node := &mockNode{}
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.calls.Add(1)
+		if status := node.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+func newClient(t *testing.T, nodes ...*mockNode) *beacon.Client {
+	var endpoints []string
+	for _, node := range nodes {
+		endpoints = append(endpoints, node.server.URL)
+	}
+	client, err := beacon.NewClient(slog.Default(), beacon.Config{Endpoints: endpoints, Attempts: 2, Backoff: 10 * time.Millisecond})
+	require.NoError(t, err)
+	return client
+}
+
+const dutiesResponse = `{"dependent_root":"0x00","execution_optimistic":false,"data":[
+	{"pubkey":"0x93247f2209abcacf57b75a51dafae777f9dd38bc7053d1af526f220a7489a6d3a2753e5f3e8b1cfe39b56f43611df74a","validator_index":"1","slot":"320"}]}`
+
+const headerResponse = `{"data":{"root":"0x0000000000000000000000000000000000000000000000000000000000000001","canonical":true,
+	"header":{"message":{"slot":"321","proposer_index":"1","parent_root":"0x0000000000000000000000000000000000000000000000000000000000000002",
+	"state_root":"0x0000000000000000000000000000000000000000000000000000000000000003","body_root":"0x0000000000000000000000000000000000000000000000000000000000000004"},
+	"signature":"0x01"}}}`
+
+func TestClientDecodes(t *testing.T) {
+	sidecars, err := json.Marshal(map[string]any{"data": []availability.BlobSidecar{{Index: 2}}})
+	require.NoError(t, err)
+	client := newClient(t, newMockNode(t, map[string]string{
+		"/eth/v1/validator/duties/proposer/10": dutiesResponse,
+		"/eth/v1/beacon/headers/head":          headerResponse,
+		"/eth/v1/beacon/blob_sidecars/321":     string(sidecars),
+	}))
+	ctx := context.Background()
+
+	duties, err := client.ProposerDuties(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, []beacon.ProposerDuty{{Pubkey: duties[0].Pubkey, ValidatorIndex: 1, Slot: 320}}, duties)
+	require.Len(t, duties[0].Pubkey, 48)
+
+	header, err := client.Header(ctx, "head")
+	require.NoError(t, err)
+	require.True(t, header.Canonical)
+	require.Equal(t, common.HexToHash("0x01"), header.Root)
+	require.Equal(t, uint64(321), header.Header.Message.Slot)
+	require.Equal(t, common.HexToHash("0x02"), header.Header.Message.ParentRoot)
+
+	blobs, err := client.GetBlobSidecars(ctx, 321)
+	require.NoError(t, err)
+	require.Len(t, blobs, 1)
+	require.Equal(t, uint64(2), blobs[0].Index)
+}
+
+func TestClientFailsOver(t *testing.T) {
+	responses := map[string]string{"/eth/v1/beacon/headers/head": headerResponse}
+	down, up := newMockNode(t, responses), newMockNode(t, responses)
+	down.status.Store(http.StatusServiceUnavailable)
+	client := newClient(t, down, up)
+
+	_, err := client.Header(context.Background(), "head")
+	require.NoError(t, err)
+	_, err = client.Header(context.Background(), "head")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), down.calls.Load(), "sticks to the endpoint that responded")
+	require.Equal(t, int32(2), up.calls.Load())
+}
+
+func TestClientRetries(t *testing.T) {
+	node := newMockNode(t, map[string]string{"/eth/v1/beacon/headers/head": headerResponse})
+	node.status.Store(http.StatusInternalServerError)
+	client := newClient(t, node)
+
+	_, err := client.Header(context.Background(), "head")
+	require.ErrorContains(t, err, "status 500")
+	require.Equal(t, int32(2), node.calls.Load(), "retried once")
+
+	node.status.Store(0)
+	_, err = client.Header(context.Background(), "head")
+	require.NoError(t, err)
+}
+
+func TestClientNotFound(t *testing.T) {
+	first, second := newMockNode(t, nil), newMockNode(t, nil)
+	client := newClient(t, first, second)
+	_, err := client.GetBlobSidecars(context.Background(), 5)
+	require.ErrorIs(t, err, beacon.ErrNotFound)
+	require.Equal(t, int32(1), first.calls.Load(), "not retried")
+	require.Equal(t, int32(1), second.calls.Load(), "asked every endpoint")
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, endpoints := range [][]string{nil, {"localhost:5052"}} {
+		_, err := beacon.NewClient(slog.Default(), beacon.Config{Endpoints: endpoints})
+		require.ErrorIs(t, err, beacon.ErrInvalidConfig)
+	}
+}
@@ -0,0 +1,174 @@
+package builder
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// Blob constraints the winning relay must honor when building the block for a slot
+type BlobConstraints struct {
+	Slot            uint64        `json:"slot,string"`
+	VersionedHashes []common.Hash `json:"versioned_hashes"`
+}
+
+// Subset of builder-specs BuilderBid. Header is kept opaque, as it's forwarded to the proposer unmodified.
+type BuilderBid struct {
+	Header             json.RawMessage      `json:"header"`
+	BlobKZGCommitments []kzg4844.Commitment `json:"blob_kzg_commitments"`
+	Value              string               `json:"value"`
+	Pubkey             hexutil.Bytes        `json:"pubkey"`
+}
+
+type SignedBuilderBid struct {
+	Message   BuilderBid    `json:"message"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+type BlobsBundle struct {
+	Commitments []kzg4844.Commitment `json:"commitments"`
+	Proofs      []kzg4844.Proof      `json:"proofs"`
+	Blobs       []kzg4844.Blob       `json:"blobs"`
+}
+
+type ExecutionPayloadAndBlobsBundle struct {
+	ExecutionPayload json.RawMessage `json:"execution_payload"`
+	BlobsBundle      BlobsBundle     `json:"blobs_bundle"`
+}
+
+type versionedResponse[T any] struct {
+	Version string `json:"version"`
+	Data    T      `json:"data"`
+}
+
+// Client for the builder-specs getHeader/getPayload flow, extended with a constraints endpoint.
+type Client struct {
+	logger     *slog.Logger
+	httpClient *http.Client
+	baseURL    string
+}
+
+func NewClient(logger *slog.Logger, baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		logger:     logger,
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    baseURL,
+	}
+}
+
+// Checks the relay is up, as mev-boost does before registering validators with it
+func (c *Client) Status(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/eth/v1/builder/status", nil, nil)
+}
+
+// Asks the relay to only produce blocks for the slot that include the committed blobs.
+func (c *Client) SubmitConstraints(ctx context.Context, constraints BlobConstraints) error {
+	return c.do(ctx, http.MethodPost, "/eth/v1/builder/constraints", constraints, nil)
+}
+
+// Fetches the relay's header for the slot and verifies it commits to every constrained blob.
+func (c *Client) GetHeader(
+	ctx context.Context,
+	constraints BlobConstraints,
+	parentHash common.Hash,
+	proposerPubkey hexutil.Bytes,
+) (*SignedBuilderBid, error) {
+	path := fmt.Sprintf("/eth/v1/builder/header/%d/%s/%s", constraints.Slot, parentHash.Hex(), proposerPubkey.String())
+	var resp versionedResponse[SignedBuilderBid]
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	if err := VerifyConstraints(constraints, resp.Data.Message.BlobKZGCommitments); err != nil {
+		return nil, fmt.Errorf("header violates blob constraints: %w", err)
+	}
+	c.logger.Info("builder header satisfies blob constraints",
+		"slot", constraints.Slot, "numBlobs", len(constraints.VersionedHashes))
+	return &resp.Data, nil
+}
+
+// Submits the signed blinded block and verifies the unblinded payload carries the constrained blobs.
+func (c *Client) GetPayload(
+	ctx context.Context,
+	constraints BlobConstraints,
+	signedBlindedBlock json.RawMessage,
+) (*ExecutionPayloadAndBlobsBundle, error) {
+	var resp versionedResponse[ExecutionPayloadAndBlobsBundle]
+	if err := c.do(ctx, http.MethodPost, "/eth/v1/builder/blinded_blocks", signedBlindedBlock, &resp); err != nil {
+		return nil, err
+	}
+	bundle := resp.Data.BlobsBundle
+	if len(bundle.Blobs) != len(bundle.Commitments) || len(bundle.Proofs) != len(bundle.Commitments) {
+		return nil, fmt.Errorf("malformed blobs bundle")
+	}
+	for i := range bundle.Blobs {
+		if err := kzg4844.VerifyBlobProof(bundle.Blobs[i], bundle.Commitments[i], bundle.Proofs[i]); err != nil {
+			return nil, fmt.Errorf("invalid blob proof at index %d: %w", i, err)
+		}
+	}
+	if err := VerifyConstraints(constraints, bundle.Commitments); err != nil {
+		return nil, fmt.Errorf("payload violates blob constraints: %w", err)
+	}
+	return &resp.Data, nil
+}
+
+// Checks every constrained versioned hash is derived from one of the given KZG commitments.
+func VerifyConstraints(constraints BlobConstraints, commitments []kzg4844.Commitment) error {
+	included := make(map[common.Hash]struct{}, len(commitments))
+	hasher := sha256.New()
+	for i := range commitments {
+		hasher.Reset()
+		included[kzg4844.CalcBlobHashV1(hasher, &commitments[i])] = struct{}{}
+	}
+	for _, vh := range constraints.VersionedHashes {
+		if _, ok := included[vh]; !ok {
+			return fmt.Errorf("missing blob with versioned hash %s", vh.Hex())
+		}
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		if out != nil {
+			return fmt.Errorf("relay has no content for %s", path)
+		}
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("relay returned status %d: %s", resp.StatusCode, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
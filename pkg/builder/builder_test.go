@@ -0,0 +1,112 @@
+package builder_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/builder"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func newRelay(t *testing.T, commitments []kzg4844.Commitment, bundle builder.BlobsBundle) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/builder/constraints", func(w http.ResponseWriter, r *http.Request) {
+		var c builder.BlobConstraints
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&c))
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/eth/v1/builder/header/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"version": "deneb",
+			"data": builder.SignedBuilderBid{
+				Message: builder.BuilderBid{
+					Header:             json.RawMessage(`{}`),
+					BlobKZGCommitments: commitments,
+					Value:              "1",
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/eth/v1/builder/blinded_blocks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"version": "deneb",
+			"data": builder.ExecutionPayloadAndBlobsBundle{
+				ExecutionPayload: json.RawMessage(`{}`),
+				BlobsBundle:      bundle,
+			},
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func versionedHash(c kzg4844.Commitment) common.Hash {
+	return kzg4844.CalcBlobHashV1(sha256.New(), &c)
+}
+
+func TestStatus(t *testing.T) {
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/builder/status", r.URL.Path)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	client := builder.NewClient(slog.Default(), server.URL, time.Second)
+	require.NoError(t, client.Status(context.Background()))
+	down.Store(true)
+	require.ErrorContains(t, client.Status(context.Background()), "status 503")
+}
+
+func TestGetHeaderVerifiesConstraints(t *testing.T) {
+	var included, missing kzg4844.Commitment
+	included[0], missing[0] = 1, 2
+
+	server := newRelay(t, []kzg4844.Commitment{included}, builder.BlobsBundle{})
+	defer server.Close()
+	client := builder.NewClient(slog.Default(), server.URL, time.Second)
+
+	satisfied := builder.BlobConstraints{Slot: 10, VersionedHashes: []common.Hash{versionedHash(included)}}
+	require.NoError(t, client.SubmitConstraints(context.Background(), satisfied))
+	bid, err := client.GetHeader(context.Background(), satisfied, common.Hash{}, hexutil.Bytes{0x01})
+	require.NoError(t, err)
+	require.Equal(t, "1", bid.Message.Value)
+
+	violated := builder.BlobConstraints{Slot: 10, VersionedHashes: []common.Hash{versionedHash(missing)}}
+	_, err = client.GetHeader(context.Background(), violated, common.Hash{}, hexutil.Bytes{0x01})
+	require.ErrorContains(t, err, fmt.Sprintf("missing blob with versioned hash %s", versionedHash(missing).Hex()))
+}
+
+func TestGetPayloadVerifiesBlobsBundle(t *testing.T) {
+	var blob kzg4844.Blob
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+	require.NoError(t, err)
+
+	bundle := builder.BlobsBundle{
+		Commitments: []kzg4844.Commitment{commitment},
+		Proofs:      []kzg4844.Proof{proof},
+		Blobs:       []kzg4844.Blob{blob},
+	}
+	server := newRelay(t, nil, bundle)
+	defer server.Close()
+	client := builder.NewClient(slog.Default(), server.URL, 5*time.Second)
+
+	constraints := builder.BlobConstraints{Slot: 10, VersionedHashes: []common.Hash{versionedHash(commitment)}}
+	payload, err := client.GetPayload(context.Background(), constraints, json.RawMessage(`{}`))
+	require.NoError(t, err)
+	require.Len(t, payload.BlobsBundle.Blobs, 1)
+}
@@ -0,0 +1,209 @@
+package bulletin
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+)
+
+const (
+	publishAttempts = 3
+	// Per publish attempt
+	publishTimeout = 5 * time.Second
+	retryBackoff   = 500 * time.Millisecond
+)
+
+// Public, append-only location results are published to
+type Sink interface {
+	Publish(ctx context.Context, result Result) error
+	Close() error
+}
+
+// Satisfied by *listener.Listener
+type Rankings interface {
+	Ranking(l1Block uint64) []auction.SignedBid
+}
+
+// Satisfied by *commitment.Coordinator
+type Commitments interface {
+	ForBlock(targetBlock *big.Int) []commitment.Commitment
+}
+
+// Whether this replica publishes, e.g. *election.Elector
+type Leader interface {
+	IsLeader() bool
+}
+
+// Publishes each auction's signed result to the sinks as it closes, and again whenever its winner falls back to
+// the runner-up
+type Publisher struct {
+	logger      *slog.Logger
+	key         *ecdsa.PrivateKey
+	rankings    Rankings
+	commitments Commitments
+	sinks       []Sink
+	leader      Leader
+}
+
+func NewPublisher(logger *slog.Logger, key *ecdsa.PrivateKey, rankings Rankings, commitments Commitments, sinks ...Sink) *Publisher {
+	return &Publisher{logger: logger, key: key, rankings: rankings, commitments: commitments, sinks: sinks}
+}
+
+// Only the leader publishes, if set before watching, so replicas don't publish a block's result several times
+func (p *Publisher) SetLeader(leader Leader) {
+	p.leader = leader
+}
+
+// Publishes results from auctionClosed and winnerFallback events until ctx is done or events is closed
+func (p *Publisher) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type != auction.EventAuctionClosed && ev.Type != auction.EventWinnerFallback {
+				continue
+			}
+			if p.leader != nil && !p.leader.IsLeader() {
+				continue
+			}
+			if _, err := p.Publish(ctx, ev.L1Block.Uint64(), ev.Bid); err != nil {
+				p.logger.Warn("failed to publish auction result", "blockNumber", ev.L1Block, "error", err)
+			}
+		}
+	}
+}
+
+// Signs the block's result with its ranked bids and commitments, and publishes it to every sink, retrying failed
+// attempts. Fails if any sink failed.
+func (p *Publisher) Publish(ctx context.Context, l1Block uint64, winner *auction.SignedBid) (*Result, error) {
+	result, err := CreateSignedResult(l1Block, winner, p.rankings.Ranking(l1Block), p.commitments.ForBlock(new(big.Int).SetUint64(l1Block)),
+		time.Now(), p.key)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, sink := range p.sinks {
+		if err := publish(ctx, sink, *result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("failed to publish to %d of %d sinks: %w", len(errs), len(p.sinks), errs[0])
+	}
+	p.logger.Debug("published auction result", "blockNumber", l1Block, "bids", result.Bids, "commitments", result.Commitments)
+	return result, nil
+}
+
+func publish(ctx context.Context, sink Sink, result Result) error {
+	var err error
+	for attempt := 0; attempt < publishAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * retryBackoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+		err = sink.Publish(attemptCtx, result)
+		cancel()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (p *Publisher) Close() error {
+	var err error
+	for _, sink := range p.sinks {
+		if closeErr := sink.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Posts results as JSON to a bulletin endpoint, which must only append them
+type HTTPSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{url: url, httpClient: &http.Client{}}
+}
+
+func (s *HTTPSink) Publish(ctx context.Context, result Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("bulletin returned status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+func (s *HTTPSink) Close() error {
+	return nil
+}
+
+// Appends results as JSON lines to a file, e.g. one served statically or synced to object storage
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bulletin file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Returns once the result is synced to disk
+func (s *FileSink) Publish(ctx context.Context, result Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
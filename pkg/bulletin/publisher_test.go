@@ -0,0 +1,143 @@
+package bulletin_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/bulletin"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRankings map[uint64][]auction.SignedBid
+
+func (m mockRankings) Ranking(l1Block uint64) []auction.SignedBid {
+	return m[l1Block]
+}
+
+type mockCommitments struct{}
+
+func (mockCommitments) ForBlock(targetBlock *big.Int) []commitment.Commitment {
+	return nil
+}
+
+type mockLeader bool
+
+func (m mockLeader) IsLeader() bool {
+	return bool(m)
+}
+
+// Bulletin endpoint keeping the results posted to it
+type bulletinServer struct {
+	mu      sync.Mutex
+	results []bulletin.Result
+}
+
+func (s *bulletinServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var result bulletin.Result
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, result)
+}
+
+func (s *bulletinServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.results)
+}
+
+func TestPublisherWatch(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	relay1, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	relay2, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	winner := *auction.MustCreateSignedBid(big.NewInt(20), big.NewInt(100), relay1)
+	runnerUp := *auction.MustCreateSignedBid(big.NewInt(10), big.NewInt(100), relay2)
+
+	server := &bulletinServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	path := filepath.Join(t.TempDir(), "bulletin.jsonl")
+	file, err := bulletin.NewFileSink(path)
+	require.NoError(t, err)
+	p := bulletin.NewPublisher(slog.Default(), key, mockRankings{100: {winner, runnerUp}}, mockCommitments{}, bulletin.NewHTTPSink(httpServer.URL), file)
+
+	events := make(chan auction.Event, 3)
+	events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: &winner}
+	events <- auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(101), Bid: &winner}
+	events <- auction.Event{Type: auction.EventWinnerFallback, L1Block: big.NewInt(100), Bid: &runnerUp}
+	close(events)
+	p.Watch(context.Background(), events)
+	require.NoError(t, p.Close())
+
+	require.Len(t, server.results, 2)
+	require.Equal(t, winner.Address, server.results[0].Winner.Address)
+	require.Equal(t, runnerUp.Address, server.results[1].Winner.Address, "fallback supersedes the result")
+	for _, result := range server.results {
+		require.True(t, result.Verify())
+		require.Equal(t, 2, result.Bids)
+	}
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines int
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		var result bulletin.Result
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		require.True(t, result.Verify())
+	}
+	require.Equal(t, 2, lines)
+}
+
+func TestPublisherFollower(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	server := &bulletinServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	p := bulletin.NewPublisher(slog.Default(), key, mockRankings{}, mockCommitments{}, bulletin.NewHTTPSink(httpServer.URL))
+	p.SetLeader(mockLeader(false))
+
+	events := make(chan auction.Event, 1)
+	events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100)}
+	close(events)
+	p.Watch(context.Background(), events)
+	require.Zero(t, server.count(), "followers leave publishing to the leader")
+}
+
+func TestPublishFails(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer httpServer.Close()
+	p := bulletin.NewPublisher(slog.Default(), key, mockRankings{}, mockCommitments{}, bulletin.NewHTTPSink(httpServer.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := p.Publish(ctx, 100, nil)
+	require.ErrorContains(t, err, "bulletin returned status 503")
+	require.Nil(t, result.Winner)
+	require.True(t, result.Verify())
+}
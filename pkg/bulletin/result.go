@@ -0,0 +1,167 @@
+package bulletin
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Auctioneer's signed result of an L1 block's auction, published for third parties to audit. A block's later
+// results, after its winner fell back to the runner-up, supersede earlier ones.
+type Result struct {
+	L1Block uint64 `json:"l1Block"`
+	// Nil if the auction had no winner
+	Winner *auction.SignedBid `json:"winner,omitempty"`
+	// Bids ranked at close, and the Merkle root of their hashes, see BidHash
+	Bids     int         `json:"bids"`
+	BidsRoot common.Hash `json:"bidsRoot"`
+	// Commitments targeting the block, and the Merkle root of their hashes
+	Commitments     int         `json:"commitments"`
+	CommitmentsRoot common.Hash `json:"commitmentsRoot"`
+	// Unix milliseconds
+	PublishedAt int64          `json:"publishedAt"`
+	Auctioneer  common.Address `json:"auctioneer"`
+	Signature   hexutil.Bytes  `json:"signature"`
+}
+
+func CreateSignedResult(
+	l1Block uint64,
+	winner *auction.SignedBid,
+	bids []auction.SignedBid,
+	commitments []commitment.Commitment,
+	publishedAt time.Time,
+	privateKey *ecdsa.PrivateKey,
+) (*Result, error) {
+	bidHashes := make([]common.Hash, len(bids))
+	for i, bid := range bids {
+		bidHashes[i] = BidHash(bid)
+	}
+	commitmentHashes := make([]common.Hash, len(commitments))
+	for i, c := range commitments {
+		commitmentHashes[i] = c.Hash()
+	}
+	r := Result{
+		L1Block:         l1Block,
+		Winner:          winner,
+		Bids:            len(bids),
+		BidsRoot:        MerkleRoot(bidHashes),
+		Commitments:     len(commitments),
+		CommitmentsRoot: MerkleRoot(commitmentHashes),
+		PublishedAt:     publishedAt.UnixMilli(),
+		Auctioneer:      crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+	signature, err := crypto.Sign(r.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	r.Signature = signature
+	return &r, nil
+}
+
+// Hash of the signed fields. The winning bid is bound by its hash, which covers its signature.
+func (r *Result) Hash() common.Hash {
+	data := binary.BigEndian.AppendUint64(nil, r.L1Block)
+	if r.Winner != nil {
+		data = append(data, BidHash(*r.Winner).Bytes()...)
+	} else {
+		data = append(data, common.Hash{}.Bytes()...)
+	}
+	data = binary.BigEndian.AppendUint64(data, uint64(r.Bids))
+	data = append(data, r.BidsRoot.Bytes()...)
+	data = binary.BigEndian.AppendUint64(data, uint64(r.Commitments))
+	data = append(data, r.CommitmentsRoot.Bytes()...)
+	data = binary.BigEndian.AppendUint64(data, uint64(r.PublishedAt))
+	data = append(data, r.Auctioneer.Bytes()...)
+	return crypto.Keccak256Hash(data)
+}
+
+// Checks the result is signed by its auctioneer, which auditors should check is one they trust, see keys.Signers
+func (r *Result) Verify() bool {
+	if r.Winner != nil && (r.Winner.L1Block == nil || r.Winner.AmountWei == nil) {
+		return false
+	}
+	sigPublicKey, err := crypto.SigToPub(r.Hash().Bytes(), r.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == r.Auctioneer
+}
+
+// Leaf of a bid in a result's bids root, its hash
+func BidHash(bid auction.SignedBid) common.Hash {
+	return bid.Hash()
+}
+
+// Root of a Merkle tree over the leaves in ascending order, with each pair of nodes hashed in ascending order so
+// proofs need no positions. An odd node out is carried up a level. Zero if there are no leaves.
+func MerkleRoot(leaves []common.Hash) common.Hash {
+	if len(leaves) == 0 {
+		return common.Hash{}
+	}
+	level := sortedLeaves(leaves)
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+	return level[0]
+}
+
+// Sibling hashes proving leaf is one of leaves, from the bottom of the tree up, false if it isn't
+func MerkleProof(leaves []common.Hash, leaf common.Hash) ([]common.Hash, bool) {
+	level := sortedLeaves(leaves)
+	index := sort.Search(len(level), func(i int) bool { return bytes.Compare(level[i].Bytes(), leaf.Bytes()) >= 0 })
+	if index == len(level) || level[index] != leaf {
+		return nil, false
+	}
+	var proof []common.Hash
+	for len(level) > 1 {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		level = nextLevel(level)
+		index /= 2
+	}
+	return proof, true
+}
+
+// Checks proof, from MerkleProof, leads from leaf to root
+func VerifyMerkleProof(root common.Hash, leaf common.Hash, proof []common.Hash) bool {
+	node := leaf
+	for _, sibling := range proof {
+		node = hashPair(node, sibling)
+	}
+	return node == root
+}
+
+func sortedLeaves(leaves []common.Hash) []common.Hash {
+	sorted := append([]common.Hash(nil), leaves...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].Bytes(), sorted[j].Bytes()) < 0 })
+	return sorted
+}
+
+func nextLevel(level []common.Hash) []common.Hash {
+	next := make([]common.Hash, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+		} else {
+			next = append(next, hashPair(level[i], level[i+1]))
+		}
+	}
+	return next
+}
+
+func hashPair(a common.Hash, b common.Hash) common.Hash {
+	if bytes.Compare(a.Bytes(), b.Bytes()) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a.Bytes(), b.Bytes())
+}
@@ -0,0 +1,63 @@
+package bulletin_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/bulletin"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedResult(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	relay, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bids := []auction.SignedBid{
+		*auction.MustCreateSignedBid(big.NewInt(20), big.NewInt(100), relay),
+		*auction.MustCreateSignedBid(big.NewInt(10), big.NewInt(100), key),
+	}
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(101), FeeWei: big.NewInt(1)}, relay)
+	require.NoError(t, err)
+
+	result, err := bulletin.CreateSignedResult(100, &bids[0], bids, []commitment.Commitment{*c}, time.Now(), key)
+	require.NoError(t, err)
+	require.True(t, result.Verify())
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), result.Auctioneer)
+	require.Equal(t, 2, result.Bids)
+	require.Equal(t, c.Hash(), result.CommitmentsRoot, "a single leaf is the root")
+
+	proof, ok := bulletin.MerkleProof([]common.Hash{bulletin.BidHash(bids[0]), bulletin.BidHash(bids[1])}, bulletin.BidHash(bids[1]))
+	require.True(t, ok)
+	require.True(t, bulletin.VerifyMerkleProof(result.BidsRoot, bulletin.BidHash(bids[1]), proof), "bid proven to be in the result")
+
+	tampered := *result
+	tampered.Winner = &bids[1]
+	require.False(t, tampered.Verify())
+	tampered = *result
+	tampered.Commitments = 0
+	require.False(t, tampered.Verify())
+}
+
+func TestMerkleProof(t *testing.T) {
+	var leaves []common.Hash
+	for n := 1; n <= 9; n++ {
+		leaves = append(leaves, crypto.Keccak256Hash([]byte{byte(n)}))
+		root := bulletin.MerkleRoot(leaves)
+		for _, leaf := range leaves {
+			proof, ok := bulletin.MerkleProof(leaves, leaf)
+			require.True(t, ok)
+			require.True(t, bulletin.VerifyMerkleProof(root, leaf, proof), "leaf of %d", n)
+		}
+		_, ok := bulletin.MerkleProof(leaves, common.Hash{0x01})
+		require.False(t, ok)
+		require.False(t, bulletin.VerifyMerkleProof(root, common.Hash{0x01}, nil))
+	}
+	require.Equal(t, common.Hash{}, bulletin.MerkleRoot(nil))
+}
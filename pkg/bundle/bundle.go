@@ -0,0 +1,73 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrBlobMismatch = errors.New("txs don't carry the committed blobs")
+
+// Flashbots eth_sendBundle params
+type Bundle struct {
+	// Signed txs, blob txs in their network encoding with the sidecar
+	Txs         []hexutil.Bytes `json:"txs"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	// Txs the bundle may land with even if they revert. Committed blob txs are never listed, so the bundle is
+	// dropped rather than landing without them.
+	RevertingTxHashes []common.Hash `json:"revertingTxHashes,omitempty"`
+}
+
+// Wraps the committed blob txs as bundles for block, which must be within the commitment's target and expiry
+// blocks. Atomic commitments get a single bundle, landing all blobs or none, others a bundle per tx. The txs must
+// carry exactly the committed blobs.
+func FromCommitment(c commitment.Commitment, txs []*types.Transaction, block uint64) ([]Bundle, error) {
+	if b := new(big.Int).SetUint64(block); b.Cmp(c.TargetBlock) < 0 || b.Cmp(c.ExpiryBlock) > 0 {
+		return nil, fmt.Errorf("block %d outside commitment blocks %v to %v", block, c.TargetBlock, c.ExpiryBlock)
+	}
+	committed := make(map[common.Hash]bool, len(c.VersionedHashes))
+	for _, vh := range c.VersionedHashes {
+		committed[vh] = false
+	}
+	encoded := make([]hexutil.Bytes, len(txs))
+	for i, tx := range txs {
+		if tx.Type() != types.BlobTxType || tx.BlobTxSidecar() == nil {
+			return nil, fmt.Errorf("%w: tx %s isn't a blob tx with its sidecar", ErrBlobMismatch, tx.Hash().Hex())
+		}
+		for _, vh := range tx.BlobHashes() {
+			seen, ok := committed[vh]
+			if !ok {
+				return nil, fmt.Errorf("%w: blob %s isn't committed", ErrBlobMismatch, vh.Hex())
+			}
+			if seen {
+				return nil, fmt.Errorf("%w: blob %s carried twice", ErrBlobMismatch, vh.Hex())
+			}
+			committed[vh] = true
+		}
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = data
+	}
+	for vh, seen := range committed {
+		if !seen {
+			return nil, fmt.Errorf("%w: blob %s missing", ErrBlobMismatch, vh.Hex())
+		}
+	}
+
+	if c.Atomic {
+		return []Bundle{{Txs: encoded, BlockNumber: hexutil.Uint64(block)}}, nil
+	}
+	bundles := make([]Bundle, len(encoded))
+	for i, tx := range encoded {
+		bundles[i] = Bundle{Txs: []hexutil.Bytes{tx}, BlockNumber: hexutil.Uint64(block)}
+	}
+	return bundles, nil
+}
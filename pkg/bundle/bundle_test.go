@@ -0,0 +1,64 @@
+package bundle_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/bundle"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/testnet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func blobTx(t *testing.T, nonce uint64, blobs int) *types.Transaction {
+	sidecar, err := testnet.RandomSidecar(rand.Reader, blobs)
+	require.NoError(t, err)
+	return types.NewTx(&types.BlobTx{Nonce: nonce, BlobHashes: sidecar.BlobHashes(), Sidecar: sidecar})
+}
+
+func committedTo(atomic bool, txs ...*types.Transaction) commitment.Commitment {
+	var versionedHashes []common.Hash
+	for _, tx := range txs {
+		versionedHashes = append(versionedHashes, tx.BlobHashes()...)
+	}
+	return commitment.Commitment{VersionedHashes: versionedHashes, Atomic: atomic, TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(102)}
+}
+
+func TestFromCommitment(t *testing.T) {
+	first, second := blobTx(t, 0, 2), blobTx(t, 1, 1)
+
+	bundles, err := bundle.FromCommitment(committedTo(true, first, second), []*types.Transaction{first, second}, 101)
+	require.NoError(t, err)
+	require.Len(t, bundles, 1, "atomic commitments land together")
+	require.Len(t, bundles[0].Txs, 2)
+	require.Equal(t, uint64(101), uint64(bundles[0].BlockNumber))
+	require.Empty(t, bundles[0].RevertingTxHashes, "committed blob txs may not revert")
+	var decoded types.Transaction
+	require.NoError(t, decoded.UnmarshalBinary(bundles[0].Txs[0]))
+	require.Equal(t, first.Hash(), decoded.Hash())
+	require.NotNil(t, decoded.BlobTxSidecar(), "network encoding")
+
+	bundles, err = bundle.FromCommitment(committedTo(false, first, second), []*types.Transaction{first, second}, 100)
+	require.NoError(t, err)
+	require.Len(t, bundles, 2, "a bundle per tx")
+}
+
+func TestFromCommitmentRejectsMismatch(t *testing.T) {
+	first, second := blobTx(t, 0, 1), blobTx(t, 1, 1)
+	c := committedTo(true, first, second)
+
+	_, err := bundle.FromCommitment(c, []*types.Transaction{first}, 100)
+	require.ErrorIs(t, err, bundle.ErrBlobMismatch, "missing blob")
+	_, err = bundle.FromCommitment(committedTo(true, first), []*types.Transaction{first, second}, 100)
+	require.ErrorIs(t, err, bundle.ErrBlobMismatch, "uncommitted blob")
+	_, err = bundle.FromCommitment(c, []*types.Transaction{first, second, first}, 100)
+	require.ErrorIs(t, err, bundle.ErrBlobMismatch, "duplicate blob")
+	_, err = bundle.FromCommitment(c, []*types.Transaction{first, second.WithoutBlobTxSidecar()}, 100)
+	require.ErrorIs(t, err, bundle.ErrBlobMismatch, "no sidecar")
+	_, err = bundle.FromCommitment(c, []*types.Transaction{first, second}, 103)
+	require.ErrorContains(t, err, "outside commitment blocks")
+}
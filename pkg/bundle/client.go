@@ -0,0 +1,106 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type rpcRequest struct {
+	JSONRPC string   `json:"jsonrpc"`
+	ID      int      `json:"id"`
+	Method  string   `json:"method"`
+	Params  []Bundle `json:"params"`
+}
+
+type rpcResponse struct {
+	Result *struct {
+		BundleHash common.Hash `json:"bundleHash"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Sends bundles to a relay's Flashbots style bundle endpoint, signing each request with the X-Flashbots-Signature
+// header so the relay can attribute them
+type Client struct {
+	logger     *slog.Logger
+	httpClient *http.Client
+	url        string
+	key        *ecdsa.PrivateKey
+}
+
+func NewClient(logger *slog.Logger, url string, key *ecdsa.PrivateKey, timeout time.Duration) *Client {
+	return &Client{
+		logger:     logger,
+		httpClient: &http.Client{Timeout: timeout},
+		url:        url,
+		key:        key,
+	}
+}
+
+// Submits the bundle with eth_sendBundle, returning the bundle hash
+func (c *Client) SendBundle(ctx context.Context, bundle Bundle) (common.Hash, error) {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: "eth_sendBundle", Params: []Bundle{bundle}})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signature, err := Sign(body, c.key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Flashbots-Signature", signature)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return common.Hash{}, fmt.Errorf("relay returned status %d: %s", resp.StatusCode, msg)
+	}
+	var result rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return common.Hash{}, err
+	}
+	if result.Error != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendBundle failed: %d %s", result.Error.Code, result.Error.Message)
+	}
+	if result.Result == nil {
+		return common.Hash{}, fmt.Errorf("eth_sendBundle returned no bundle hash")
+	}
+	c.logger.Info("bundle sent", "block", uint64(bundle.BlockNumber), "txs", len(bundle.Txs), "bundleHash", result.Result.BundleHash)
+	return result.Result.BundleHash, nil
+}
+
+// X-Flashbots-Signature header value for the request body: the signer's address and its EIP-191 signature of
+// the hex encoded keccak256 hash of the body
+func Sign(body []byte, key *ecdsa.PrivateKey) (string, error) {
+	signature, err := crypto.Sign(hashBody(body), key)
+	if err != nil {
+		return "", err
+	}
+	return crypto.PubkeyToAddress(key.PublicKey).Hex() + ":" + hexutil.Encode(signature), nil
+}
+
+func hashBody(body []byte) []byte {
+	return accounts.TextHash([]byte(crypto.Keccak256Hash(body).Hex()))
+}
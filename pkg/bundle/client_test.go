@@ -0,0 +1,68 @@
+package bundle_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/bundle"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendBundle(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	tx := blobTx(t, 0, 1)
+	bundleHash := common.HexToHash("0xb0")
+	var received bundle.Bundle
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		// Signature recovered as a Flashbots relay does
+		address, signature, ok := strings.Cut(r.Header.Get("X-Flashbots-Signature"), ":")
+		require.True(t, ok)
+		pubkey, err := crypto.SigToPub(accounts.TextHash([]byte(crypto.Keccak256Hash(body).Hex())), hexutil.MustDecode(signature))
+		require.NoError(t, err)
+		require.Equal(t, address, crypto.PubkeyToAddress(*pubkey).Hex())
+
+		var req struct {
+			Method string          `json:"method"`
+			Params []bundle.Bundle `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(body, &req))
+		require.Equal(t, "eth_sendBundle", req.Method)
+		received = req.Params[0]
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": map[string]any{"bundleHash": bundleHash}})
+	}))
+	defer server.Close()
+
+	data, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	sent := bundle.Bundle{Txs: []hexutil.Bytes{data}, BlockNumber: 100}
+	client := bundle.NewClient(slog.Default(), server.URL, key, time.Second)
+	hash, err := client.SendBundle(context.Background(), sent)
+	require.NoError(t, err)
+	require.Equal(t, bundleHash, hash)
+	require.Equal(t, sent, received)
+}
+
+func TestSendBundleError(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "error": map[string]any{"code": -32000, "message": "bundle reverts"}})
+	}))
+	defer server.Close()
+
+	_, err := bundle.NewClient(slog.Default(), server.URL, key, time.Second).SendBundle(context.Background(), bundle.Bundle{BlockNumber: 100})
+	require.ErrorContains(t, err, "bundle reverts")
+}
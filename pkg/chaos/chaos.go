@@ -0,0 +1,157 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/listener"
+)
+
+// Won auctions awaiting their delay before the listener is held up, far more than are won within any delay
+const maxPendingWinners = 1024
+
+var (
+	ErrInvalidConfig = errors.New("invalid fault injection config")
+	// Returned for faults injected in place of the real outcome
+	ErrInjected = errors.New("injected fault")
+)
+
+type Config struct {
+	// Percentage of submitted bids silently dropped, from 0 to 100
+	DropBidsPercent int
+	// Delay before won auctions are handed to settlement
+	WinnerDelay time.Duration
+	// Percentage of settlement submits failed, from 0 to 100
+	FailSettlementsPercent int
+	// Seeds fault decisions, so a rehearsal can be repeated. A random seed is used if 0.
+	Seed int64
+}
+
+// Injects faults into bid intake, winner notification and settlement, so operators can rehearse incident
+// handling and verify recovery paths. Every fault injected is logged.
+type Injector struct {
+	logger *slog.Logger
+	config Config
+
+	mu   sync.Mutex // Protects rand
+	rand *rand.Rand
+}
+
+func NewInjector(logger *slog.Logger, config Config) (*Injector, error) {
+	if config.DropBidsPercent < 0 || config.DropBidsPercent > 100 {
+		return nil, fmt.Errorf("%w: bid drop percentage %d out of range", ErrInvalidConfig, config.DropBidsPercent)
+	}
+	if config.FailSettlementsPercent < 0 || config.FailSettlementsPercent > 100 {
+		return nil, fmt.Errorf("%w: settlement failure percentage %d out of range", ErrInvalidConfig, config.FailSettlementsPercent)
+	}
+	if config.WinnerDelay < 0 {
+		return nil, fmt.Errorf("%w: negative winner delay", ErrInvalidConfig)
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{logger: logger, config: config, rand: rand.New(rand.NewSource(seed))}, nil
+}
+
+func (i *Injector) roll(percent int) bool {
+	if percent <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Intn(100) < percent
+}
+
+// Whether to drop the bid, as if lost before reaching the auction
+func (i *Injector) DropBid(bid auction.SignedBid) bool {
+	if !i.roll(i.config.DropBidsPercent) {
+		return false
+	}
+	i.logger.Warn("fault injected: dropping bid", "bid", bid)
+	return true
+}
+
+// Hands won auctions from won to the returned channel, each after the winner delay, until ctx is done.
+// Won auctions keep being received meanwhile, so the listener isn't held up, and are handed over in order.
+func (i *Injector) DelayWinners(ctx context.Context, won <-chan auction.SignedBid) <-chan auction.SignedBid {
+	if i.config.WinnerDelay <= 0 {
+		return won
+	}
+	type pendingBid struct {
+		bid auction.SignedBid
+		due time.Time
+	}
+	pending := make(chan pendingBid, maxPendingWinners)
+	delayed := make(chan auction.SignedBid)
+	go func() {
+		defer close(pending)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case bid := <-won:
+				i.logger.Warn("fault injected: delaying winner notification", "blockNumber", bid.L1Block, "delay", i.config.WinnerDelay)
+				select {
+				case pending <- pendingBid{bid: bid, due: time.Now().Add(i.config.WinnerDelay)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	go func() {
+		for p := range pending {
+			timer := time.NewTimer(time.Until(p.due))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			select {
+			case delayed <- p.bid:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return delayed
+}
+
+// Fails the submission of the won auction's settlement with ErrInjected, or returns nil to submit it
+func (i *Injector) FailSettlement(bid auction.SignedBid) error {
+	if !i.roll(i.config.FailSettlementsPercent) {
+		return nil
+	}
+	i.logger.Warn("fault injected: failing settlement", "blockNumber", bid.L1Block, "winner", bid.Address)
+	return fmt.Errorf("%w: settlement submit failed", ErrInjected)
+}
+
+// Listener whose submitted bids are dropped by the injector, served in place of the listener
+type Listener struct {
+	*listener.Listener
+	Faults *Injector
+}
+
+// Dropped bids are reported accepted, as if lost after submission
+func (l *Listener) SubmitBid(bid auction.SignedBid) error {
+	if l.Faults.DropBid(bid) {
+		return nil
+	}
+	return l.Listener.SubmitBid(bid)
+}
+
+// Dropped bids aren't acknowledged, as if lost before they arrived
+func (l *Listener) SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error) {
+	if l.Faults.DropBid(bid) {
+		return nil, fmt.Errorf("%w: bid dropped", ErrInjected)
+	}
+	return l.Listener.SubmitBidWithReceipt(bid)
+}
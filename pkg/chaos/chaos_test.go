@@ -0,0 +1,107 @@
+package chaos_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/chaos"
+	"blob-preconfs/pkg/listener"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInjector(t *testing.T) {
+	for _, config := range []chaos.Config{
+		{DropBidsPercent: -1},
+		{DropBidsPercent: 101},
+		{FailSettlementsPercent: 101},
+		{WinnerDelay: -time.Second},
+	} {
+		_, err := chaos.NewInjector(slog.Default(), config)
+		require.ErrorIs(t, err, chaos.ErrInvalidConfig, "%+v", config)
+	}
+}
+
+func TestDropBids(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	bid := *auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	count := func(config chaos.Config) int {
+		config.Seed = 1
+		faults, err := chaos.NewInjector(slog.Default(), config)
+		require.NoError(t, err)
+		dropped := 0
+		for i := 0; i < 1000; i++ {
+			if faults.DropBid(bid) {
+				dropped++
+			}
+		}
+		return dropped
+	}
+	require.Zero(t, count(chaos.Config{}))
+	require.Equal(t, 1000, count(chaos.Config{DropBidsPercent: 100}))
+	dropped := count(chaos.Config{DropBidsPercent: 30})
+	require.InDelta(t, 300, dropped, 60)
+	require.Equal(t, dropped, count(chaos.Config{DropBidsPercent: 30}), "seeded")
+}
+
+func TestFailSettlement(t *testing.T) {
+	faults, err := chaos.NewInjector(slog.Default(), chaos.Config{FailSettlementsPercent: 100})
+	require.NoError(t, err)
+	require.ErrorIs(t, faults.FailSettlement(auction.SignedBid{L1Block: big.NewInt(100)}), chaos.ErrInjected)
+	faults, err = chaos.NewInjector(slog.Default(), chaos.Config{})
+	require.NoError(t, err)
+	require.NoError(t, faults.FailSettlement(auction.SignedBid{L1Block: big.NewInt(100)}))
+}
+
+func TestDelayWinners(t *testing.T) {
+	faults, err := chaos.NewInjector(slog.Default(), chaos.Config{WinnerDelay: 100 * time.Millisecond})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	won := make(chan auction.SignedBid)
+	delayed := faults.DelayWinners(ctx, won)
+
+	started := time.Now()
+	for block := int64(100); block < 103; block++ {
+		select {
+		case won <- auction.SignedBid{L1Block: big.NewInt(block)}:
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("won auctions held up by the delay")
+		}
+	}
+	for block := int64(100); block < 103; block++ {
+		bid := <-delayed
+		require.Equal(t, block, bid.L1Block.Int64())
+		require.GreaterOrEqual(t, time.Since(started), 100*time.Millisecond)
+	}
+
+	undelayed, err := chaos.NewInjector(slog.Default(), chaos.Config{})
+	require.NoError(t, err)
+	require.Equal(t, (<-chan auction.SignedBid)(won), undelayed.DelayWinners(ctx, won))
+}
+
+type mockRelayRegistry struct{}
+
+func (mockRelayRegistry) IsRegisteredOnSettlementLayer(common.Address) bool {
+	return true
+}
+
+func TestListenerDropsBids(t *testing.T) {
+	faults, err := chaos.NewInjector(slog.Default(), chaos.Config{DropBidsPercent: 100})
+	require.NoError(t, err)
+	l := &chaos.Listener{Listener: listener.NewListener(slog.Default(), nil, mockRelayRegistry{}, nil), Faults: faults}
+	pk, _ := crypto.GenerateKey()
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)),
+		"dropped bids are reported accepted, though no auction is in progress")
+
+	faults, err = chaos.NewInjector(slog.Default(), chaos.Config{})
+	require.NoError(t, err)
+	l.Faults = faults
+	require.Error(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)))
+}
@@ -0,0 +1,77 @@
+package commitment
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Relay's signed promise to include the blobs of a preconf request by ExpiryBlock
+type Commitment struct {
+	RequestHash     common.Hash   `json:"requestHash"`
+	VersionedHashes []common.Hash `json:"versionedHashes"`
+	// Atomic commitments are only fulfilled if all blobs land in the same block
+	Atomic      bool     `json:"atomic"`
+	TargetBlock *big.Int `json:"targetBlock"`
+	ExpiryBlock *big.Int `json:"expiryBlock"`
+	FeeWei      *big.Int `json:"feeWei"`
+	// Hash of the commitment this one renews, zero for original commitments
+	RenewalOf common.Hash `json:"renewalOf"`
+	// Times the blobs were carried forward after a proposer fault, boosting priority in later auctions
+	Escalations uint64         `json:"escalations"`
+	Committer   common.Address `json:"committer"`
+	Signature   hexutil.Bytes  `json:"signature"`
+}
+
+func CreateSignedCommitment(c Commitment, privateKey *ecdsa.PrivateKey) (*Commitment, error) {
+	c.Committer = crypto.PubkeyToAddress(privateKey.PublicKey)
+	signature, err := crypto.Sign(c.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	c.Signature = signature
+	return &c, nil
+}
+
+// Hash of the signed fields, also used as the commitment ID
+func (c *Commitment) Hash() common.Hash {
+	data := make([]byte, 0, 2*common.HashLength+common.AddressLength+105+len(c.VersionedHashes)*common.HashLength)
+	data = append(data, c.RequestHash.Bytes()...)
+	if c.Atomic {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	data = append(data, c.RenewalOf.Bytes()...)
+	data = binary.BigEndian.AppendUint64(data, c.Escalations)
+	data = append(data, c.Committer.Bytes()...)
+	data = append(data, common.BigToHash(c.TargetBlock).Bytes()...)
+	data = append(data, common.BigToHash(c.ExpiryBlock).Bytes()...)
+	data = append(data, common.BigToHash(c.FeeWei).Bytes()...)
+	for _, vh := range c.VersionedHashes {
+		data = append(data, vh.Bytes()...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+func (c *Commitment) Verify() bool {
+	if c.TargetBlock == nil || c.ExpiryBlock == nil || c.FeeWei == nil {
+		return false
+	}
+	// The hash drops the sign, so a negative value would verify with the signature of its absolute value
+	if c.TargetBlock.Sign() < 0 || c.ExpiryBlock.Sign() < 0 || c.FeeWei.Sign() < 0 {
+		return false
+	}
+	if c.ExpiryBlock.Cmp(c.TargetBlock) < 0 {
+		return false
+	}
+	sigPublicKey, err := crypto.SigToPub(c.Hash().Bytes(), c.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == c.Committer
+}
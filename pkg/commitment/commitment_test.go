@@ -0,0 +1,52 @@
+package commitment_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAndVerifySignedCommitment(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		RequestHash:     common.Hash{0x01},
+		VersionedHashes: []common.Hash{{0x02}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(102),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(pk.PublicKey), c.Committer)
+	assert.True(t, c.Verify())
+
+	c.ExpiryBlock = big.NewInt(103)
+	assert.False(t, c.Verify())
+}
+
+func TestVerifyRejectsNegativeValuesAndEarlyExpiry(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	valid := commitment.Commitment{
+		RequestHash:     common.Hash{0x01},
+		VersionedHashes: []common.Hash{{0x02}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(102),
+		FeeWei:          big.NewInt(5),
+	}
+	for name, mutate := range map[string]func(c *commitment.Commitment){
+		"negative target block": func(c *commitment.Commitment) { c.TargetBlock = big.NewInt(-100) },
+		"negative expiry block": func(c *commitment.Commitment) { c.ExpiryBlock = big.NewInt(-102) },
+		"negative fee":          func(c *commitment.Commitment) { c.FeeWei = big.NewInt(-5) },
+		"expiry before target":  func(c *commitment.Commitment) { c.ExpiryBlock = big.NewInt(99) },
+	} {
+		c := valid
+		mutate(&c)
+		signed, err := commitment.CreateSignedCommitment(c, pk)
+		assert.NoError(t, err)
+		assert.False(t, signed.Verify(), name)
+	}
+}
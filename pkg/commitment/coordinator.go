@@ -0,0 +1,580 @@
+package commitment
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"sync"
+
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type State int
+
+const (
+	StateActive State = iota
+	// Blobs were included by the expiry block
+	StateFulfilled
+	// Expiry block passed without inclusion
+	StateMissed
+	// Superseded by a renewal commitment for a later block
+	StateRenewed
+	// Carried forward to the next auction after a proposer fault
+	StateEscalated
+)
+
+func (s State) String() string {
+	switch s {
+	case StateActive:
+		return "active"
+	case StateFulfilled:
+		return "fulfilled"
+	case StateMissed:
+		return "missed"
+	case StateRenewed:
+		return "renewed"
+	case StateEscalated:
+		return "escalated"
+	}
+	return "unknown"
+}
+
+func ParseState(s string) (State, error) {
+	for state := StateActive; state <= StateEscalated; state++ {
+		if state.String() == s {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown commitment state %q", s)
+}
+
+type MissReason int
+
+const (
+	MissReasonRelayFault MissReason = iota
+	// Missed for reasons outside the relay's control, e.g. reorg. These are renewed.
+	MissReasonExternal
+	// Missed as the proposer didn't include the relay's block, e.g. missed slot. These are escalated, or renewed if
+	// escalation is disabled.
+	MissReasonProposerFault
+)
+
+func (r MissReason) String() string {
+	switch r {
+	case MissReasonRelayFault:
+		return "relayFault"
+	case MissReasonExternal:
+		return "external"
+	case MissReasonProposerFault:
+		return "proposerFault"
+	}
+	return "unknown"
+}
+
+// Attributes a missed commitment to the relay or to external causes
+type MissClassifier interface {
+	ClassifyMiss(c Commitment, block *big.Int) MissReason
+}
+
+// Re-quotes the fee of a renewal commitment, drafted with the fee of the commitment it renews
+type Quoter interface {
+	Quote(c Commitment, targetBlock *big.Int) (*big.Int, error)
+}
+
+type Config struct {
+	// Automatically renew commitments missed for external reasons, or due to proposer faults unless escalated
+	AutoRenew   bool
+	MaxRenewals int
+	// Automatically escalate commitments missed due to proposer faults
+	EscalateProposerFaults bool
+	MaxEscalations         uint64
+}
+
+// Persists commitments on issuance and every state transition, e.g. *store.MemoryStore
+type Recorder interface {
+	SaveCommitment(c Commitment, state State) error
+}
+
+// Notified of issued commitments, including renewals and escalations, and of missed ones, e.g. *eventstream.Emitter.
+// Called with the coordinator locked, so must not block or call back into it.
+type Observer interface {
+	CommitmentIssued(c Commitment)
+	CommitmentMissed(c Commitment, reason MissReason, block *big.Int)
+}
+
+// Notifies several observers, e.g. the event stream and alerting
+type MultiObserver []Observer
+
+func (m MultiObserver) CommitmentIssued(c Commitment) {
+	for _, observer := range m {
+		observer.CommitmentIssued(c)
+	}
+}
+
+func (m MultiObserver) CommitmentMissed(c Commitment, reason MissReason, block *big.Int) {
+	for _, observer := range m {
+		observer.CommitmentMissed(c, reason, block)
+	}
+}
+
+type Transition struct {
+	From  State
+	To    State
+	Block *big.Int
+}
+
+type tracked struct {
+	commitment Commitment
+	state      State
+	missReason MissReason
+	renewals   int
+	history    []Transition
+	// Blobs of non-atomic commitments included so far, possibly across blocks
+	included map[common.Hash]struct{}
+}
+
+// Tracks issued commitments through their lifecycle, issuing renewals when configured
+type Coordinator struct {
+	logger     *slog.Logger
+	config     Config
+	classifier MissClassifier
+	quoter     Quoter
+	privateKey *ecdsa.PrivateKey
+	recorder   Recorder
+	observer   Observer
+
+	mu          sync.Mutex
+	commitments map[common.Hash]*tracked
+	// Hashes of the commitments to each blob, by versioned hash, in issuance order
+	byVersionedHash map[common.Hash][]common.Hash
+	// Versioned hashes of the blobs of included blob txs carrying committed blobs, by tx hash
+	byTx map[common.Hash][]common.Hash
+}
+
+func NewCoordinator(
+	logger *slog.Logger,
+	config Config,
+	classifier MissClassifier,
+	quoter Quoter,
+	privateKey *ecdsa.PrivateKey,
+) *Coordinator {
+	return &Coordinator{
+		logger:          logger,
+		config:          config,
+		classifier:      classifier,
+		quoter:          quoter,
+		privateKey:      privateKey,
+		commitments:     make(map[common.Hash]*tracked),
+		byVersionedHash: make(map[common.Hash][]common.Hash),
+		byTx:            make(map[common.Hash][]common.Hash),
+	}
+}
+
+// Commitments are recorded, if set before any is issued
+func (c *Coordinator) SetRecorder(recorder Recorder) {
+	c.recorder = recorder
+}
+
+// Issued and missed commitments are observed, if set before any is issued
+func (c *Coordinator) SetObserver(observer Observer) {
+	c.observer = observer
+}
+
+// Issues a commitment for the request, valid until expiryBlock (inclusive)
+func (c *Coordinator) Issue(req intake.PreconfRequest, feeWei *big.Int, expiryBlock *big.Int) (*Commitment, error) {
+	if expiryBlock.Cmp(req.TargetBlock) < 0 {
+		return nil, fmt.Errorf("expiry block %v before target block %v", expiryBlock, req.TargetBlock)
+	}
+	commitment, err := CreateSignedCommitment(Commitment{
+		RequestHash:     req.Hash(),
+		VersionedHashes: req.VersionedHashes,
+		Atomic:          req.Atomic,
+		TargetBlock:     req.TargetBlock,
+		ExpiryBlock:     expiryBlock,
+		FeeWei:          feeWei,
+	}, c.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.track(*commitment, 0)
+	c.logger.Info("commitment issued", "hash", commitment.Hash(), "targetBlock", commitment.TargetBlock, "expiryBlock", expiryBlock)
+	return commitment, nil
+}
+
+// Tracks a commitment persisted before a restart, see recovery. Commitments must be restored in issuance order,
+// so renewal counts are rebuilt from the commitments they renew. Blobs of a non-atomic commitment included
+// before the restart aren't known, only blobs observed from then on count towards fulfilling it.
+func (c *Coordinator) Restore(commitment Commitment, state State) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	renewals := 0
+	if parent, ok := c.commitments[commitment.RenewalOf]; ok && commitment.RenewalOf != (common.Hash{}) {
+		renewals = parent.renewals
+		if parent.state == StateRenewed {
+			renewals++
+		}
+	}
+	t := newTracked(commitment, renewals)
+	t.state = state
+	if state == StateMissed && c.classifier != nil {
+		// The block the miss was observed at isn't persisted, the expiry block is the earliest it could be
+		t.missReason = c.classifier.ClassifyMiss(commitment, commitment.ExpiryBlock)
+	}
+	c.commitments[commitment.Hash()] = t
+	c.index(commitment)
+}
+
+func (c *Coordinator) State(hash common.Hash) (State, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.commitments[hash]
+	if !ok {
+		return 0, false
+	}
+	return t.state, true
+}
+
+func (c *Coordinator) Get(hash common.Hash) (Commitment, State, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.commitments[hash]
+	if !ok {
+		return Commitment{}, 0, false
+	}
+	return t.commitment, t.state, true
+}
+
+func (c *Coordinator) History(hash common.Hash) []Transition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.commitments[hash]
+	if !ok {
+		return nil
+	}
+	return append([]Transition(nil), t.history...)
+}
+
+// Processes a new L1 block and the versioned hashes of blobs it included. Returns renewal and escalation commitments issued.
+func (c *Coordinator) OnBlock(block *big.Int, includedVersionedHashes []common.Hash) []Commitment {
+	included := make(map[common.Hash]struct{}, len(includedVersionedHashes))
+	for _, vh := range includedVersionedHashes {
+		included[vh] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var renewals []Commitment
+	for hash, t := range c.commitments {
+		if t.state != StateActive || block.Cmp(t.commitment.TargetBlock) < 0 {
+			continue
+		}
+		if t.commitment.Atomic {
+			if containsAll(included, t.commitment.VersionedHashes) {
+				c.transition(hash, t, StateFulfilled, block)
+				continue
+			}
+		} else {
+			for _, vh := range t.commitment.VersionedHashes {
+				if _, ok := included[vh]; ok {
+					t.included[vh] = struct{}{}
+				}
+			}
+			if len(t.included) == len(t.commitment.VersionedHashes) {
+				c.transition(hash, t, StateFulfilled, block)
+				continue
+			}
+		}
+		if block.Cmp(t.commitment.ExpiryBlock) < 0 {
+			continue
+		}
+		t.missReason = MissReasonRelayFault
+		if c.classifier != nil {
+			t.missReason = c.classifier.ClassifyMiss(t.commitment, block)
+		}
+		c.transition(hash, t, StateMissed, block)
+		if c.observer != nil {
+			c.observer.CommitmentMissed(t.commitment, t.missReason, block)
+		}
+
+		switch {
+		case c.config.EscalateProposerFaults && t.missReason == MissReasonProposerFault:
+			escalation, err := c.escalate(hash, t, block)
+			if err != nil {
+				c.logger.Warn("failed to escalate commitment", "hash", hash, "error", err)
+				continue
+			}
+			renewals = append(renewals, *escalation)
+		case c.config.AutoRenew && renewable(t.missReason):
+			renewal, err := c.renew(hash, t, block)
+			if err != nil {
+				c.logger.Warn("failed to renew commitment", "hash", hash, "error", err)
+				continue
+			}
+			renewals = append(renewals, *renewal)
+		}
+	}
+	return renewals
+}
+
+// Like OnBlock, taking the block's transactions, and indexing the blob txs carrying committed blobs by hash, see ForTx
+func (c *Coordinator) OnBlockTransactions(block *big.Int, txs []*types.Transaction) []Commitment {
+	var included []common.Hash
+	c.mu.Lock()
+	for _, tx := range txs {
+		blobs := tx.BlobHashes()
+		included = append(included, blobs...)
+		for _, vh := range blobs {
+			if _, ok := c.byVersionedHash[vh]; ok {
+				c.byTx[tx.Hash()] = blobs
+				break
+			}
+		}
+	}
+	c.mu.Unlock()
+	return c.OnBlock(block, included)
+}
+
+// Commitments to the blob with the versioned hash in any state, including renewals and escalations, in issuance
+// order, e.g. for users checking a blob's preconf without knowing its commitment
+func (c *Coordinator) ForVersionedHash(versionedHash common.Hash) []Commitment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var commitments []Commitment
+	for _, hash := range c.byVersionedHash[versionedHash] {
+		commitments = append(commitments, c.commitments[hash].commitment)
+	}
+	return commitments
+}
+
+// Commitments to the blobs of an included blob tx, observed with OnBlockTransactions, by the tx's blobs in order,
+// then in issuance order
+func (c *Coordinator) ForTx(txHash common.Hash) []Commitment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var commitments []Commitment
+	seen := make(map[common.Hash]bool)
+	for _, vh := range c.byTx[txHash] {
+		for _, hash := range c.byVersionedHash[vh] {
+			if !seen[hash] {
+				seen[hash] = true
+				commitments = append(commitments, c.commitments[hash].commitment)
+			}
+		}
+	}
+	return commitments
+}
+
+// Manually renews a commitment missed for external reasons or due to a proposer fault, targeting the block after
+// currentBlock
+func (c *Coordinator) Renew(hash common.Hash, currentBlock *big.Int) (*Commitment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.commitments[hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown commitment %s", hash.Hex())
+	}
+	return c.renew(hash, t, currentBlock)
+}
+
+// Must be called with mu held
+func (c *Coordinator) renew(hash common.Hash, t *tracked, currentBlock *big.Int) (*Commitment, error) {
+	if t.state != StateMissed || !renewable(t.missReason) {
+		return nil, fmt.Errorf("commitment %s is %s, only commitments missed through no fault of the relay can be renewed",
+			hash.Hex(), t.state)
+	}
+	if t.renewals >= c.config.MaxRenewals {
+		return nil, fmt.Errorf("commitment %s reached max renewals %d", hash.Hex(), c.config.MaxRenewals)
+	}
+
+	validity := new(big.Int).Sub(t.commitment.ExpiryBlock, t.commitment.TargetBlock)
+	targetBlock := new(big.Int).Add(currentBlock, big.NewInt(1))
+	draft := Commitment{
+		RequestHash:     t.commitment.RequestHash,
+		VersionedHashes: t.remaining(),
+		Atomic:          t.commitment.Atomic,
+		TargetBlock:     targetBlock,
+		ExpiryBlock:     new(big.Int).Add(targetBlock, validity),
+		FeeWei:          t.commitment.FeeWei,
+		RenewalOf:       hash,
+	}
+	if c.quoter != nil {
+		quoted, err := c.quoter.Quote(draft, targetBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-quote: %w", err)
+		}
+		draft.FeeWei = quoted
+	}
+
+	renewal, err := CreateSignedCommitment(draft, c.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	c.transition(hash, t, StateRenewed, currentBlock)
+	c.track(*renewal, t.renewals+1)
+	c.logger.Info("commitment renewed", "hash", hash, "renewal", renewal.Hash(), "targetBlock", targetBlock)
+	return renewal, nil
+}
+
+// Must be called with mu held
+func (c *Coordinator) escalate(hash common.Hash, t *tracked, currentBlock *big.Int) (*Commitment, error) {
+	if t.commitment.Escalations >= c.config.MaxEscalations {
+		return nil, fmt.Errorf("commitment %s reached max escalations %d", hash.Hex(), c.config.MaxEscalations)
+	}
+	// The original commitment is carried forward as is, the user doesn't pay again for a proposer fault
+	validity := new(big.Int).Sub(t.commitment.ExpiryBlock, t.commitment.TargetBlock)
+	targetBlock := new(big.Int).Add(currentBlock, big.NewInt(1))
+	escalation, err := CreateSignedCommitment(Commitment{
+		RequestHash:     t.commitment.RequestHash,
+		VersionedHashes: t.remaining(),
+		Atomic:          t.commitment.Atomic,
+		TargetBlock:     targetBlock,
+		ExpiryBlock:     new(big.Int).Add(targetBlock, validity),
+		FeeWei:          t.commitment.FeeWei,
+		RenewalOf:       hash,
+		Escalations:     t.commitment.Escalations + 1,
+	}, c.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	c.transition(hash, t, StateEscalated, currentBlock)
+	c.track(*escalation, t.renewals)
+	c.logger.Info("commitment escalated", "hash", hash, "escalation", escalation.Hash(),
+		"targetBlock", targetBlock, "escalations", escalation.Escalations)
+	return escalation, nil
+}
+
+// Escalated commitments carried into the auction for the target block, highest priority first.
+// The block's winning relay must include these before any new requests.
+func (c *Coordinator) Escalated(targetBlock *big.Int) []Commitment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var escalated []Commitment
+	for _, t := range c.commitments {
+		if t.state == StateActive && t.commitment.Escalations > 0 && t.commitment.TargetBlock.Cmp(targetBlock) == 0 {
+			escalated = append(escalated, t.commitment)
+		}
+	}
+	sort.Slice(escalated, func(i, j int) bool {
+		if escalated[i].Escalations != escalated[j].Escalations {
+			return escalated[i].Escalations > escalated[j].Escalations
+		}
+		return escalated[i].Hash().Cmp(escalated[j].Hash()) < 0
+	})
+	return escalated
+}
+
+// Commitments targeting the block in any state, in hash order, e.g. for comparing replicas' state
+func (c *Coordinator) ForBlock(targetBlock *big.Int) []Commitment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var commitments []Commitment
+	for _, t := range c.commitments {
+		if t.commitment.TargetBlock.Cmp(targetBlock) == 0 {
+			commitments = append(commitments, t.commitment)
+		}
+	}
+	sort.Slice(commitments, func(i, j int) bool {
+		return commitments[i].Hash().Cmp(commitments[j].Hash()) < 0
+	})
+	return commitments
+}
+
+// Commitments from the original to the given one, following renewals and escalations. Used for refund accounting.
+func (c *Coordinator) Chain(hash common.Hash) []Commitment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var chain []Commitment
+	for {
+		t, ok := c.commitments[hash]
+		if !ok {
+			break
+		}
+		chain = append([]Commitment{t.commitment}, chain...)
+		if t.commitment.RenewalOf == (common.Hash{}) {
+			break
+		}
+		hash = t.commitment.RenewalOf
+	}
+	return chain
+}
+
+func newTracked(c Commitment, renewals int) *tracked {
+	return &tracked{
+		commitment: c,
+		state:      StateActive,
+		renewals:   renewals,
+		included:   make(map[common.Hash]struct{}),
+	}
+}
+
+// Atomic bundles are carried forward whole, non-atomic commitments only for the blobs still missing
+func (t *tracked) remaining() []common.Hash {
+	if t.commitment.Atomic {
+		return t.commitment.VersionedHashes
+	}
+	var remaining []common.Hash
+	for _, vh := range t.commitment.VersionedHashes {
+		if _, ok := t.included[vh]; !ok {
+			remaining = append(remaining, vh)
+		}
+	}
+	return remaining
+}
+
+// Must be called with mu held
+func (c *Coordinator) track(commitment Commitment, renewals int) {
+	t := newTracked(commitment, renewals)
+	c.commitments[commitment.Hash()] = t
+	c.index(commitment)
+	c.record(t)
+	if c.observer != nil {
+		c.observer.CommitmentIssued(commitment)
+	}
+}
+
+// Must be called with mu held
+func (c *Coordinator) index(commitment Commitment) {
+	hash := commitment.Hash()
+	for _, vh := range commitment.VersionedHashes {
+		c.byVersionedHash[vh] = append(c.byVersionedHash[vh], hash)
+	}
+}
+
+// Must be called with mu held
+func (c *Coordinator) transition(hash common.Hash, t *tracked, to State, block *big.Int) {
+	t.history = append(t.history, Transition{From: t.state, To: to, Block: block})
+	c.logger.Debug("commitment state transition", "hash", hash, "from", t.state, "to", to, "block", block)
+	t.state = to
+	c.record(t)
+}
+
+func (c *Coordinator) record(t *tracked) {
+	if c.recorder == nil {
+		return
+	}
+	if err := c.recorder.SaveCommitment(t.commitment, t.state); err != nil {
+		c.logger.Error("failed to record commitment", "hash", t.commitment.Hash(), "error", err)
+	}
+}
+
+// Misses the relay isn't at fault for
+func renewable(reason MissReason) bool {
+	return reason == MissReasonExternal || reason == MissReasonProposerFault
+}
+
+func containsAll(set map[common.Hash]struct{}, hashes []common.Hash) bool {
+	for _, h := range hashes {
+		if _, ok := set[h]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,297 @@
+package commitment_test
+
+import (
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClassifier struct {
+	reason commitment.MissReason
+}
+
+func (m *mockClassifier) ClassifyMiss(c commitment.Commitment, block *big.Int) commitment.MissReason {
+	return m.reason
+}
+
+type mockQuoter struct{}
+
+func (m *mockQuoter) Quote(c commitment.Commitment, targetBlock *big.Int) (*big.Int, error) {
+	return new(big.Int).Add(c.FeeWei, big.NewInt(1)), nil
+}
+
+func newRequest(t *testing.T, targetBlock int64) intake.PreconfRequest {
+	pk, _ := crypto.GenerateKey()
+	req, err := intake.CreateSignedRequest([]common.Hash{{0x01}, {0x02}}, big.NewInt(targetBlock), big.NewInt(10), pk)
+	require.NoError(t, err)
+	return *req
+}
+
+func newCoordinator(config commitment.Config, reason commitment.MissReason) *commitment.Coordinator {
+	relayKey, _ := crypto.GenerateKey()
+	return commitment.NewCoordinator(slog.Default(), config, &mockClassifier{reason: reason}, &mockQuoter{}, relayKey)
+}
+
+func TestCoordinatorFulfilled(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{}, commitment.MissReasonRelayFault)
+	c, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(101))
+	require.NoError(t, err)
+	require.True(t, c.Verify())
+
+	coordinator.OnBlock(big.NewInt(100), []common.Hash{{0x01}})
+	state, _ := coordinator.State(c.Hash())
+	require.Equal(t, commitment.StateActive, state)
+
+	coordinator.OnBlock(big.NewInt(101), []common.Hash{{0x01}, {0x02}})
+	state, _ = coordinator.State(c.Hash())
+	require.Equal(t, commitment.StateFulfilled, state)
+	require.Equal(t, []commitment.Transition{
+		{From: commitment.StateActive, To: commitment.StateFulfilled, Block: big.NewInt(101)},
+	}, coordinator.History(c.Hash()))
+}
+
+func TestCoordinatorMissedByRelayIsNotRenewed(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{AutoRenew: true, MaxRenewals: 1}, commitment.MissReasonRelayFault)
+	c, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+
+	require.Empty(t, coordinator.OnBlock(big.NewInt(100), nil))
+	state, _ := coordinator.State(c.Hash())
+	require.Equal(t, commitment.StateMissed, state)
+
+	_, err = coordinator.Renew(c.Hash(), big.NewInt(100))
+	require.Error(t, err)
+}
+
+func TestCoordinatorAutoRenewal(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{AutoRenew: true, MaxRenewals: 1}, commitment.MissReasonExternal)
+	c, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(101))
+	require.NoError(t, err)
+
+	renewals := coordinator.OnBlock(big.NewInt(101), nil)
+	require.Len(t, renewals, 1)
+	renewal := renewals[0]
+	require.True(t, renewal.Verify())
+	require.Equal(t, c.Hash(), renewal.RenewalOf)
+	require.Equal(t, big.NewInt(102), renewal.TargetBlock)
+	require.Equal(t, big.NewInt(103), renewal.ExpiryBlock)
+	require.Equal(t, big.NewInt(6), renewal.FeeWei)
+
+	state, _ := coordinator.State(c.Hash())
+	require.Equal(t, commitment.StateRenewed, state)
+	require.Len(t, coordinator.History(c.Hash()), 2)
+
+	// Max renewals reached
+	require.Empty(t, coordinator.OnBlock(big.NewInt(103), nil))
+	state, _ = coordinator.State(renewal.Hash())
+	require.Equal(t, commitment.StateMissed, state)
+}
+
+func TestCoordinatorManualRenewal(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{MaxRenewals: 1}, commitment.MissReasonExternal)
+	c, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+
+	require.Empty(t, coordinator.OnBlock(big.NewInt(100), nil))
+	renewal, err := coordinator.Renew(c.Hash(), big.NewInt(105))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(106), renewal.TargetBlock)
+	require.Equal(t, big.NewInt(106), renewal.ExpiryBlock)
+}
+
+func TestCoordinatorRejectsExpiryBeforeTarget(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{}, commitment.MissReasonRelayFault)
+	_, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(99))
+	require.Error(t, err)
+}
+
+func TestCoordinatorAtomicBundleSplitAcrossBlocks(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	bundle, err := intake.CreateSignedBundleRequest([]common.Hash{{0x01}, {0x02}}, big.NewInt(100), big.NewInt(10), pk)
+	require.NoError(t, err)
+	loose := newRequest(t, 100)
+
+	coordinator := newCoordinator(commitment.Config{}, commitment.MissReasonRelayFault)
+	atomic, err := coordinator.Issue(*bundle, big.NewInt(5), big.NewInt(101))
+	require.NoError(t, err)
+	require.True(t, atomic.Atomic)
+	nonAtomic, err := coordinator.Issue(loose, big.NewInt(5), big.NewInt(101))
+	require.NoError(t, err)
+
+	coordinator.OnBlock(big.NewInt(100), []common.Hash{{0x01}})
+	coordinator.OnBlock(big.NewInt(101), []common.Hash{{0x02}})
+
+	state, _ := coordinator.State(atomic.Hash())
+	require.Equal(t, commitment.StateMissed, state)
+	state, _ = coordinator.State(nonAtomic.Hash())
+	require.Equal(t, commitment.StateFulfilled, state)
+}
+
+func TestCoordinatorRenewsOnlyMissingBlobsOfNonAtomicCommitments(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{AutoRenew: true, MaxRenewals: 1}, commitment.MissReasonExternal)
+	_, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+
+	renewals := coordinator.OnBlock(big.NewInt(100), []common.Hash{{0x01}})
+	require.Len(t, renewals, 1)
+	require.Equal(t, []common.Hash{{0x02}}, renewals[0].VersionedHashes)
+}
+
+func TestCoordinatorEscalatesProposerFaults(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{
+		AutoRenew:              true,
+		MaxRenewals:            1,
+		EscalateProposerFaults: true,
+		MaxEscalations:         2,
+	}, commitment.MissReasonProposerFault)
+	original, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+	other, err := coordinator.Issue(newRequest(t, 101), big.NewInt(5), big.NewInt(101))
+	require.NoError(t, err)
+
+	escalations := coordinator.OnBlock(big.NewInt(100), nil)
+	require.Len(t, escalations, 1)
+	first := escalations[0]
+	require.True(t, first.Verify())
+	require.Equal(t, uint64(1), first.Escalations)
+	require.Equal(t, big.NewInt(5), first.FeeWei, "escalations carry the original fee")
+	state, _ := coordinator.State(original.Hash())
+	require.Equal(t, commitment.StateEscalated, state)
+	require.Equal(t, []commitment.Commitment{first}, coordinator.Escalated(big.NewInt(101)))
+
+	escalations = coordinator.OnBlock(big.NewInt(101), nil)
+	require.Len(t, escalations, 2)
+	escalated := coordinator.Escalated(big.NewInt(102))
+	require.Len(t, escalated, 2)
+	require.Equal(t, uint64(2), escalated[0].Escalations, "twice escalated commitment has highest priority")
+	require.Equal(t, uint64(1), escalated[1].Escalations)
+	require.Equal(t, other.RequestHash, escalated[1].RequestHash)
+
+	chain := coordinator.Chain(escalated[0].Hash())
+	require.Len(t, chain, 3)
+	require.Equal(t, original.Hash(), chain[0].Hash())
+	require.Equal(t, first.Hash(), chain[1].Hash())
+
+	// Max escalations reached
+	require.Len(t, coordinator.OnBlock(big.NewInt(102), nil), 1)
+}
+
+func TestCoordinatorRenewsProposerFaultsUnlessEscalated(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{AutoRenew: true, MaxRenewals: 1}, commitment.MissReasonProposerFault)
+	c, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+
+	renewals := coordinator.OnBlock(big.NewInt(100), nil)
+	require.Len(t, renewals, 1)
+	require.Equal(t, c.Hash(), renewals[0].RenewalOf)
+	require.Zero(t, renewals[0].Escalations)
+	require.Equal(t, big.NewInt(6), renewals[0].FeeWei, "renewals are re-quoted")
+	state, _ := coordinator.State(c.Hash())
+	require.Equal(t, commitment.StateRenewed, state)
+}
+
+type mockRecorder struct {
+	saved []commitment.State
+}
+
+func (m *mockRecorder) SaveCommitment(c commitment.Commitment, state commitment.State) error {
+	m.saved = append(m.saved, state)
+	return nil
+}
+
+func TestCoordinatorRecordsCommitments(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{AutoRenew: true, MaxRenewals: 1}, commitment.MissReasonExternal)
+	recorder := &mockRecorder{}
+	coordinator.SetRecorder(recorder)
+	_, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+	coordinator.OnBlock(big.NewInt(100), nil)
+	require.Equal(t, []commitment.State{
+		commitment.StateActive,  // issued
+		commitment.StateMissed,  // expired
+		commitment.StateRenewed, // superseded
+		commitment.StateActive,  // renewal issued
+	}, recorder.saved)
+}
+
+type mockObserver struct {
+	observed []string
+}
+
+func (m *mockObserver) CommitmentIssued(c commitment.Commitment) {
+	m.observed = append(m.observed, "issued")
+}
+
+func (m *mockObserver) CommitmentMissed(c commitment.Commitment, reason commitment.MissReason, block *big.Int) {
+	m.observed = append(m.observed, "missed "+reason.String())
+}
+
+func TestCoordinatorObserver(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{AutoRenew: true, MaxRenewals: 1}, commitment.MissReasonExternal)
+	observer := &mockObserver{}
+	coordinator.SetObserver(observer)
+	_, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+	coordinator.OnBlock(big.NewInt(100), nil)
+	require.Equal(t, []string{"issued", "missed external", "issued"}, observer.observed)
+}
+
+func TestParseState(t *testing.T) {
+	for state := commitment.StateActive; state <= commitment.StateEscalated; state++ {
+		parsed, err := commitment.ParseState(state.String())
+		require.NoError(t, err)
+		require.Equal(t, state, parsed)
+	}
+	_, err := commitment.ParseState("unknown")
+	require.Error(t, err)
+}
+
+func TestCoordinatorRestore(t *testing.T) {
+	config := commitment.Config{AutoRenew: true, MaxRenewals: 1}
+	coordinator := newCoordinator(config, commitment.MissReasonExternal)
+	original, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+	renewals := coordinator.OnBlock(big.NewInt(100), nil)
+	require.Len(t, renewals, 1)
+
+	restarted := newCoordinator(config, commitment.MissReasonExternal)
+	restarted.Restore(*original, commitment.StateRenewed)
+	restarted.Restore(renewals[0], commitment.StateActive)
+	state, found := restarted.State(renewals[0].Hash())
+	require.True(t, found)
+	require.Equal(t, commitment.StateActive, state)
+	require.Len(t, restarted.Chain(renewals[0].Hash()), 2)
+
+	// The renewal count is rebuilt, so max renewals still applies
+	require.Empty(t, restarted.OnBlock(renewals[0].ExpiryBlock, nil))
+	state, _ = restarted.State(renewals[0].Hash())
+	require.Equal(t, commitment.StateMissed, state)
+}
+
+func TestCoordinatorLookupByBlob(t *testing.T) {
+	coordinator := newCoordinator(commitment.Config{AutoRenew: true, MaxRenewals: 1}, commitment.MissReasonExternal)
+	c, err := coordinator.Issue(newRequest(t, 100), big.NewInt(5), big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, []commitment.Commitment{*c}, coordinator.ForVersionedHash(common.Hash{0x01}))
+	require.Empty(t, coordinator.ForVersionedHash(common.Hash{0x03}))
+
+	renewals := coordinator.OnBlockTransactions(big.NewInt(100), nil)
+	require.Len(t, renewals, 1)
+	require.Equal(t, []commitment.Commitment{*c, renewals[0]}, coordinator.ForVersionedHash(common.Hash{0x02}), "renewals, in issuance order")
+
+	tx := types.NewTx(&types.BlobTx{BlobHashes: []common.Hash{{0x02}, {0x01}}})
+	other := types.NewTx(&types.BlobTx{BlobHashes: []common.Hash{{0x03}}})
+	coordinator.OnBlockTransactions(big.NewInt(101), []*types.Transaction{tx, other})
+	state, _ := coordinator.State(renewals[0].Hash())
+	require.Equal(t, commitment.StateFulfilled, state)
+	require.Equal(t, []commitment.Commitment{*c, renewals[0]}, coordinator.ForTx(tx.Hash()))
+	require.Empty(t, coordinator.ForTx(other.Hash()), "txs without committed blobs aren't indexed")
+}
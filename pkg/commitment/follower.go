@@ -0,0 +1,93 @@
+package commitment
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// Per block query
+	blockQueryTimeout = 5 * time.Second
+	// Blocks skipped since the last one processed, e.g. while auctions were paused, are caught up to this many
+	maxCatchUp = 64
+)
+
+// Satisfied by *ethclient.Client
+type BlockSource interface {
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+}
+
+// Feeds the coordinator each new L1 block's transactions, so commitments are fulfilled, missed, renewed and
+// escalated as the chain progresses
+type Follower struct {
+	logger      *slog.Logger
+	coordinator *Coordinator
+	blocks      BlockSource
+	// Last block processed, zero until the first
+	last uint64
+}
+
+func NewFollower(logger *slog.Logger, coordinator *Coordinator, blocks BlockSource) *Follower {
+	return &Follower{
+		logger:      logger,
+		coordinator: coordinator,
+		blocks:      blocks,
+	}
+}
+
+// Processes the head each auction opens at, and the blocks since the last one processed, until ctx is done or events
+// is closed
+func (f *Follower) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type == auction.EventAuctionOpened && ev.L1Block != nil {
+				f.follow(ctx, ev.L1Block.Uint64())
+			}
+		}
+	}
+}
+
+// Processes the blocks after the last one processed up to head. A block failing to load is retried with the next head.
+func (f *Follower) follow(ctx context.Context, head uint64) {
+	if head <= f.last {
+		return
+	}
+	from := f.last + 1
+	switch {
+	case f.last == 0:
+		from = head
+	case head-f.last > maxCatchUp:
+		from = head - maxCatchUp + 1
+		f.logger.Warn("too many blocks since the last processed, skipping", "from", f.last+1, "to", from-1)
+	}
+	for number := from; number <= head; number++ {
+		block, err := f.block(ctx, number)
+		if err != nil {
+			f.logger.Warn("failed to read block, commitments not updated", "blockNumber", number, "error", err)
+			return
+		}
+		issued := f.coordinator.OnBlockTransactions(block.Number(), block.Transactions())
+		f.last = number
+		if len(issued) > 0 {
+			f.logger.Info("commitments carried forward", "blockNumber", number, "commitments", len(issued))
+		}
+	}
+}
+
+func (f *Follower) block(ctx context.Context, number uint64) (*types.Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, blockQueryTimeout)
+	defer cancel()
+	return f.blocks.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+}
@@ -0,0 +1,103 @@
+package commitment_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/missedslot"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// Blocks by number, serving their headers too
+type mockChain struct {
+	mu     sync.Mutex
+	blocks map[uint64]*types.Block
+}
+
+func (m *mockChain) add(number, timestamp uint64, txs ...*types.Transaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Time: timestamp}
+	m.blocks[number] = types.NewBlockWithHeader(header).WithBody(txs, nil)
+}
+
+func (m *mockChain) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	block, ok := m.blocks[number.Uint64()]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return block, nil
+}
+
+func (m *mockChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	block, err := m.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+type discardPublisher struct{}
+
+func (discardPublisher) PublishEvent(ev auction.Event) {}
+
+func blobTx(versionedHashes ...common.Hash) *types.Transaction {
+	return types.NewTx(&types.BlobTx{BlobHashes: versionedHashes})
+}
+
+func TestFollowerEscalatesProposerFaults(t *testing.T) {
+	chain := &mockChain{blocks: make(map[uint64]*types.Block)}
+	chain.add(100, 1200)
+	detector := missedslot.NewDetector(slog.Default(), missedslot.Config{SlotTime: 12 * time.Second, Outcome: auction.SlotCarryOver},
+		chain, discardPublisher{})
+	relayKey, _ := crypto.GenerateKey()
+	coordinator := commitment.NewCoordinator(slog.Default(), commitment.Config{EscalateProposerFaults: true, MaxEscalations: 3},
+		detector, nil, relayKey)
+
+	userKey, _ := crypto.GenerateKey()
+	included, err := intake.CreateSignedRequest([]common.Hash{{0x01}}, big.NewInt(101), big.NewInt(10), userKey)
+	require.NoError(t, err)
+	fulfilled, err := coordinator.Issue(*included, big.NewInt(5), big.NewInt(101))
+	require.NoError(t, err)
+	dropped, err := intake.CreateSignedRequest([]common.Hash{{0x02}}, big.NewInt(102), big.NewInt(10), userKey)
+	require.NoError(t, err)
+	missed, err := coordinator.Issue(*dropped, big.NewInt(5), big.NewInt(102))
+	require.NoError(t, err)
+
+	events := make(chan auction.Event, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go commitment.NewFollower(slog.Default(), coordinator, chain).Watch(ctx, events)
+	events <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)}
+
+	// No auction opened at 101, caught up as the auction opens at 102, proposed a slot late
+	chain.add(101, 1212, blobTx(common.Hash{0x01}))
+	chain.add(102, 1236)
+	events <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(102)}
+
+	require.Eventually(t, func() bool {
+		return len(coordinator.Escalated(big.NewInt(103))) == 1
+	}, time.Second, 10*time.Millisecond)
+	escalation := coordinator.Escalated(big.NewInt(103))[0]
+	require.Equal(t, missed.Hash(), escalation.RenewalOf)
+	require.Equal(t, uint64(1), escalation.Escalations)
+	require.Equal(t, []common.Hash{{0x02}}, escalation.VersionedHashes)
+	state, _ := coordinator.State(missed.Hash())
+	require.Equal(t, commitment.StateEscalated, state)
+	state, _ = coordinator.State(fulfilled.Hash())
+	require.Equal(t, commitment.StateFulfilled, state)
+}
@@ -0,0 +1,944 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/keys"
+	"blob-preconfs/pkg/mevboost"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Engine names are used in URL paths and metric labels
+var engineName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Full auctioneer node configuration. Keys are the yaml and toml tags, nested by section, e.g. l1.rpc-url.
+type Config struct {
+	L1 L1Config `yaml:"l1" toml:"l1"`
+	// Preset the chain keys default to: mainnet, holesky, sepolia or local. Empty for none, see Network.
+	NetworkName string           `yaml:"network" toml:"network"`
+	Chain       ChainConfig      `yaml:"chain" toml:"chain"`
+	Signer      SignerConfig     `yaml:"signer" toml:"signer"`
+	Auction     AuctionConfig    `yaml:"auction" toml:"auction"`
+	Reserve     ReserveConfig    `yaml:"reserve" toml:"reserve"`
+	Registry    RegistryConfig   `yaml:"registry" toml:"registry"`
+	Award       AwardConfig      `yaml:"award" toml:"award"`
+	Commitment  CommitmentConfig `yaml:"commitment" toml:"commitment"`
+	Pricing     PricingConfig    `yaml:"pricing" toml:"pricing"`
+	Intake      IntakeConfig     `yaml:"intake" toml:"intake"`
+	Store       StoreConfig      `yaml:"store" toml:"store"`
+	Audit       AuditConfig      `yaml:"audit" toml:"audit"`
+	REST        ServerConfig     `yaml:"rest" toml:"rest"`
+	JSONRPC     ServerConfig     `yaml:"jsonrpc" toml:"jsonrpc"`
+	GRPC        ServerConfig     `yaml:"grpc" toml:"grpc"`
+	GraphQL     ServerConfig     `yaml:"graphql" toml:"graphql"`
+	Metrics     ServerConfig     `yaml:"metrics" toml:"metrics"`
+	Admin       AdminConfig      `yaml:"admin" toml:"admin"`
+	TLS         TLSConfig        `yaml:"tls" toml:"tls"`
+	Transport   TransportConfig  `yaml:"transport" toml:"transport"`
+	Log         LogConfig        `yaml:"log" toml:"log"`
+	Event       EventConfig      `yaml:"event" toml:"event"`
+	Alert       AlertConfig      `yaml:"alert" toml:"alert"`
+	Health      HealthConfig     `yaml:"health" toml:"health"`
+	Retention   RetentionConfig  `yaml:"retention" toml:"retention"`
+	Recovery    RecoveryConfig   `yaml:"recovery" toml:"recovery"`
+	Clock       ClockConfig      `yaml:"clock" toml:"clock"`
+	Funding     FundingConfig    `yaml:"funding" toml:"funding"`
+	Gas         GasConfig        `yaml:"gas" toml:"gas"`
+	Bulletin    BulletinConfig   `yaml:"bulletin" toml:"bulletin"`
+	Heartbeat   HeartbeatConfig  `yaml:"heartbeat" toml:"heartbeat"`
+	Sealed      SealedConfig     `yaml:"sealed" toml:"sealed"`
+	Watchers    WatchersConfig   `yaml:"watchers" toml:"watchers"`
+	Daemon      DaemonConfig     `yaml:"daemon" toml:"daemon"`
+	Chaos       ChaosConfig      `yaml:"chaos" toml:"chaos"`
+	Federation  FederationConfig `yaml:"federation" toml:"federation"`
+	// Other chains' auctions run in this process, by engine name, each configured by its own file, see LoadEngine
+	Engines map[string]string `yaml:"engines,omitempty" toml:"engines"`
+}
+
+type L1Config struct {
+	// Execution node RPC endpoint
+	RPCURL string `yaml:"rpc-url" toml:"rpc-url"`
+	// Interval the node is polled for new blocks in
+	PollInterval time.Duration `yaml:"poll-interval" toml:"poll-interval"`
+}
+
+// Overrides of the network preset's chain parameters, unset if zero
+type ChainConfig struct {
+	// Verified against the L1 node on startup
+	ChainID  uint64        `yaml:"chain-id" toml:"chain-id"`
+	SlotTime time.Duration `yaml:"slot-time" toml:"slot-time"`
+	// Beacon chain genesis in Unix seconds
+	GenesisTime      uint64 `yaml:"genesis-time" toml:"genesis-time"`
+	MaxBlobsPerBlock int    `yaml:"max-blobs-per-block" toml:"max-blobs-per-block"`
+	RegistryContract string `yaml:"registry-contract" toml:"registry-contract"`
+}
+
+// Key commitments are signed with, from a keystore (see keys.Store) or a raw key file
+type SignerConfig struct {
+	KeystoreDir string `yaml:"keystore-dir" toml:"keystore-dir"`
+	// Key to unlock, the keystore's active key if empty
+	Address      string `yaml:"address" toml:"address"`
+	PasswordFile string `yaml:"password-file" toml:"password-file"`
+	// Unencrypted hex private key, instead of a keystore
+	KeyFile string `yaml:"key-file" toml:"key-file"`
+	// How long the key rotated out of the keystore remains valid after a rotation, see keys.Source.Signers
+	GracePeriod time.Duration `yaml:"grace-period" toml:"grace-period"`
+}
+
+func (c SignerConfig) Source() keys.Source {
+	return keys.Source{KeystoreDir: c.KeystoreDir, Address: c.Address, PasswordFile: c.PasswordFile, KeyFile: c.KeyFile}
+}
+
+type AuctionConfig struct {
+	// Bidding period of each L1 block's auction
+	Period time.Duration `yaml:"period" toml:"period"`
+	// Relays allowed to bid, replacing the built-in whitelist if set
+	Allowlist []string `yaml:"allowlist" toml:"allowlist"`
+	// Relays denied from bidding, even if allowed
+	Denylist []string `yaml:"denylist" toml:"denylist"`
+	// Require relay request signatures on bid submission endpoints
+	RequireAuth bool `yaml:"require-auth" toml:"require-auth"`
+	// Workers verifying bid signatures in each auction, one per CPU if 0
+	Verifiers int `yaml:"verifiers" toml:"verifiers"`
+	// Shards of bid intake by relay address, evaluated concurrently, unsharded if 0 or 1
+	Shards int `yaml:"shards" toml:"shards"`
+	// Close auctions early once there's no new leader for QuietPeriod, after at least MinOpen. Disabled if 0.
+	MinOpen     time.Duration `yaml:"min-open" toml:"min-open"`
+	QuietPeriod time.Duration `yaml:"quiet-period" toml:"quiet-period"`
+	// Open each auction OpenOffset and close it CloseOffset into the slot the block is seen in, instead of on
+	// seeing the block and after Period. Disabled if CloseOffset is 0.
+	OpenOffset  time.Duration `yaml:"open-offset" toml:"open-offset"`
+	CloseOffset time.Duration `yaml:"close-offset" toml:"close-offset"`
+	// Reject bids the bidder's escrow doesn't cover on submission, for the avs registry source. Balances are
+	// cached for EscrowCacheTTL.
+	EscrowCheck    bool          `yaml:"escrow-check" toml:"escrow-check"`
+	EscrowCacheTTL time.Duration `yaml:"escrow-cache-ttl" toml:"escrow-cache-ttl"`
+	// Cap a relay's bids at what its bond still backs, and prompt it to top up, once its pending wins leave less
+	// than this percent of the bond, for the avs registry source. Disabled if 0.
+	BondLowPercent uint64 `yaml:"bond-low-percent" toml:"bond-low-percent"`
+	// Bidding period of the auction for the block after a missed slot, instead of Period. Disabled if 0.
+	MissedSlotPeriod time.Duration `yaml:"missed-slot-period" toml:"missed-slot-period"`
+	// What becomes of a won auction whose slot is missed, no block proposed: refund or carry-over. Missed slots
+	// aren't detected if empty. Blocks may arrive up to MissedSlotGrace after their slot, 2s if 0.
+	MissedSlotOutcome string        `yaml:"missed-slot-outcome" toml:"missed-slot-outcome"`
+	MissedSlotGrace   time.Duration `yaml:"missed-slot-grace" toml:"missed-slot-grace"`
+	// Lowest bid accepted, none if 0
+	ReserveWei uint64 `yaml:"reserve-wei" toml:"reserve-wei"`
+	// Reserve price while the blob base fee is at least BlobFeeSpikeWei. Disabled if 0.
+	BlobFeeSpikeWei uint64 `yaml:"blob-fee-spike-wei" toml:"blob-fee-spike-wei"`
+	SpikeReserveWei uint64 `yaml:"spike-reserve-wei" toml:"spike-reserve-wei"`
+	// Queue bids for the next block submitted up to this long before its auction opens, submitting them as it
+	// opens. Disabled if 0.
+	PreOpenWindow time.Duration `yaml:"pre-open-window" toml:"pre-open-window"`
+}
+
+const (
+	// Relays are listed in the config, there's no settlement layer client yet
+	RegistryStatic = "static"
+	// mev-boost relays, registered while their status check passes, see mevboost
+	RegistryMevBoost = "mev-boost"
+	// Relays bonded with an EigenLayer AVS, registered while their operator's stake covers the bond, see avs
+	RegistryAVS = "avs"
+)
+
+type RegistryConfig struct {
+	// Where registered relays are read from, static, mev-boost or avs
+	Source string `yaml:"source" toml:"source"`
+	// Relays registered on the settlement layer, for the static source, or relays' operators for the avs source
+	Relays []string `yaml:"relays" toml:"relays"`
+	// mev-boost relay URLs, e.g. https://0xac6e...@boost-relay.example.com, by the address they bid with
+	MevBoostRelays map[string]string `yaml:"mev-boost-relays,omitempty" toml:"mev-boost-relays"`
+	// How often mev-boost relays' status, or AVS operators' stakes, are checked
+	RefreshInterval time.Duration `yaml:"refresh-interval" toml:"refresh-interval"`
+	AVS             AVSConfig     `yaml:"avs" toml:"avs"`
+	// Most distinct bids a relay may submit per auction, unlimited if 0, unless its tier sets another quota
+	BidQuota int `yaml:"bid-quota" toml:"bid-quota"`
+	// Relays' tiers, by address, and each tier's bid quota, by name
+	Tiers      map[string]string `yaml:"tiers,omitempty" toml:"tiers"`
+	TierQuotas map[string]string `yaml:"tier-quotas,omitempty" toml:"tier-quotas"`
+}
+
+type AVSConfig struct {
+	// EigenLayer middleware RegistryCoordinator and StakeRegistry contracts on L1
+	RegistryCoordinator string `yaml:"registry-coordinator" toml:"registry-coordinator"`
+	StakeRegistry       string `yaml:"stake-registry" toml:"stake-registry"`
+	// Quorum relays restake in
+	Quorum int `yaml:"quorum" toml:"quorum"`
+	// Restaked collateral an operator needs for its relay to be registered
+	MinStakeGwei uint64 `yaml:"min-stake-gwei" toml:"min-stake-gwei"`
+}
+
+// See award.Config
+type AwardConfig struct {
+	// Callback URLs winning relays are notified at, by the address they bid with, disabled if empty
+	Endpoints map[string]string `yaml:"endpoints,omitempty" toml:"endpoints"`
+	Attempts  int               `yaml:"attempts" toml:"attempts"`
+	Timeout   time.Duration     `yaml:"timeout" toml:"timeout"`
+	// Time winners have to counter-sign their award before it falls back to the runner-up
+	AcceptDeadline time.Duration `yaml:"accept-deadline" toml:"accept-deadline"`
+}
+
+// See commitment.Config
+type CommitmentConfig struct {
+	// Carry commitments missed due to proposer faults forward to the next block's auction, at most MaxEscalations
+	// times each. Proposer faults are told apart once missed slots are detected, see auction.missed-slot-outcome.
+	EscalateProposerFaults bool   `yaml:"escalate-proposer-faults" toml:"escalate-proposer-faults"`
+	MaxEscalations         uint64 `yaml:"max-escalations" toml:"max-escalations"`
+	// Renew commitments missed due to proposer faults for the next block, re-quoted, at most MaxRenewals times each,
+	// unless escalated. Also requires auction.missed-slot-outcome.
+	AutoRenew   bool `yaml:"auto-renew" toml:"auto-renew"`
+	MaxRenewals int  `yaml:"max-renewals" toml:"max-renewals"`
+}
+
+// See pricing.Config. Zero disables quoting, renewals keeping their original fee.
+type PricingConfig struct {
+	FeePerBlobWei    uint64 `yaml:"fee-per-blob-wei" toml:"fee-per-blob-wei"`
+	AtomicPremiumBps uint64 `yaml:"atomic-premium-bps" toml:"atomic-premium-bps"`
+}
+
+// Preconf requests submitted to the REST API, committed to as the auction for their target block opens, see intake
+// and issuer
+type IntakeConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Per address limits, see intake.Config. Zero disables a limit.
+	MaxPending  int           `yaml:"max-pending" toml:"max-pending"`
+	MaxRequests int           `yaml:"max-requests" toml:"max-requests"`
+	Window      time.Duration `yaml:"window" toml:"window"`
+	// See issuer.Config
+	BlobsPerBlock  int    `yaml:"blobs-per-block" toml:"blobs-per-block"`
+	ValidityBlocks uint64 `yaml:"validity-blocks" toml:"validity-blocks"`
+	// Simulate requests' blob txs against L1 state before committing to them, see simulation
+	Simulate bool `yaml:"simulate" toml:"simulate"`
+}
+
+type StoreConfig struct {
+	// memory, leveldb, sqlite or postgres
+	Backend string `yaml:"backend" toml:"backend"`
+	// Database directory for leveldb, or file for sqlite
+	Path string `yaml:"path" toml:"path"`
+	// Connection string for postgres
+	URL string `yaml:"url" toml:"url"`
+}
+
+type AuditConfig struct {
+	// Hash-chained audit log of bid traffic, disabled if empty
+	Log string `yaml:"log" toml:"log"`
+	// Recording of bid traffic for replay (see replay), disabled if empty
+	Recording string `yaml:"recording" toml:"recording"`
+}
+
+type ServerConfig struct {
+	// Listen address, disabled if empty
+	Addr string `yaml:"addr" toml:"addr"`
+}
+
+type AdminConfig struct {
+	// Listen address, disabled if empty
+	Addr string `yaml:"addr" toml:"addr"`
+	// Bearer token, required if enabled
+	Token string `yaml:"token" toml:"token"`
+}
+
+// See tlsconfig.Config
+type TLSConfig struct {
+	CertFile          string   `yaml:"cert-file" toml:"cert-file"`
+	KeyFile           string   `yaml:"key-file" toml:"key-file"`
+	ACMEDomains       []string `yaml:"acme-domains" toml:"acme-domains"`
+	ACMECacheDir      string   `yaml:"acme-cache-dir" toml:"acme-cache-dir"`
+	ACMEEmail         string   `yaml:"acme-email" toml:"acme-email"`
+	ClientCAFile      string   `yaml:"client-ca-file" toml:"client-ca-file"`
+	RequireClientCert bool     `yaml:"require-client-cert" toml:"require-client-cert"`
+}
+
+// Limits on each relay connection to the JSON-RPC, websocket and gRPC servers, see ratelimit.TransportConfig
+type TransportConfig struct {
+	// Largest request body, websocket message or gRPC message accepted, in bytes
+	MaxMessageSize int `yaml:"max-message-size" toml:"max-message-size"`
+	// Sustained messages per second allowed per connection, 0 disables the limit
+	MessageRate  int `yaml:"message-rate" toml:"message-rate"`
+	MessageBurst int `yaml:"message-burst" toml:"message-burst"`
+}
+
+// See logging.Config
+type LogConfig struct {
+	Level          string            `yaml:"level" toml:"level"`
+	Format         string            `yaml:"format" toml:"format"`
+	Modules        map[string]string `yaml:"modules" toml:"modules"`
+	File           string            `yaml:"file" toml:"file"`
+	MaxSizeMB      int               `yaml:"max-size-mb" toml:"max-size-mb"`
+	MaxBackups     int               `yaml:"max-backups" toml:"max-backups"`
+	SampleInterval time.Duration     `yaml:"sample-interval" toml:"sample-interval"`
+}
+
+// See eventstream.SinkConfig
+type EventConfig struct {
+	// stdout, file, nats or kafka, disabled if empty
+	Sink       string   `yaml:"sink" toml:"sink"`
+	Path       string   `yaml:"path" toml:"path"`
+	URL        string   `yaml:"url" toml:"url"`
+	Subject    string   `yaml:"subject" toml:"subject"`
+	Brokers    []string `yaml:"brokers" toml:"brokers"`
+	BufferSize int      `yaml:"buffer-size" toml:"buffer-size"`
+}
+
+// See alerting.Config
+type AlertConfig struct {
+	Webhooks      []string      `yaml:"webhooks" toml:"webhooks"`
+	SlackURLs     []string      `yaml:"slack-urls" toml:"slack-urls"`
+	PagerDutyKey  string        `yaml:"pagerduty-key" toml:"pagerduty-key"`
+	DedupInterval time.Duration `yaml:"dedup-interval" toml:"dedup-interval"`
+	RPCErrors     int           `yaml:"rpc-errors" toml:"rpc-errors"`
+	RPCWindow     time.Duration `yaml:"rpc-window" toml:"rpc-window"`
+}
+
+type HealthConfig struct {
+	// Liveness fails if no auction closed for this long
+	MaxAuctionAge time.Duration `yaml:"max-auction-age" toml:"max-auction-age"`
+	// Readiness fails if more won auctions await settlement, 0 disables the check
+	MaxUnsettled int `yaml:"max-unsettled" toml:"max-unsettled"`
+}
+
+type RetentionConfig struct {
+	// Bids received longer ago are pruned, 0 keeps bids forever
+	Bids     time.Duration `yaml:"bids" toml:"bids"`
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+}
+
+type RecoveryConfig struct {
+	// L1 block history is scanned from on startup, 0 scans all history
+	FromBlock uint64 `yaml:"from-block" toml:"from-block"`
+}
+
+// See timesync.Config
+type ClockConfig struct {
+	// Auctions aren't opened while the clock drifts further from NTP or L1 slot boundaries, 0 disables the guard
+	MaxDrift time.Duration `yaml:"max-drift" toml:"max-drift"`
+	// SNTP server host:port, NTP drift isn't measured if empty
+	NTPServer string        `yaml:"ntp-server" toml:"ntp-server"`
+	Interval  time.Duration `yaml:"interval" toml:"interval"`
+}
+
+// Reserve price adjusted each slot from auction.reserve-wei, instead of fixed, see policy.ControllerConfig
+type ReserveConfig struct {
+	Dynamic bool `yaml:"dynamic" toml:"dynamic"`
+	// Bounds of the reserve price, unbounded above if MaxWei is 0
+	MinWei uint64 `yaml:"min-wei" toml:"min-wei"`
+	MaxWei uint64 `yaml:"max-wei" toml:"max-wei"`
+	// The reserve price tracks TargetPercent of the median clearing price of the last Window auctions, and at
+	// least the blob base fee times BlobFeeMultiple, if set
+	Window          int    `yaml:"window" toml:"window"`
+	TargetPercent   uint64 `yaml:"target-percent" toml:"target-percent"`
+	BlobFeeMultiple uint64 `yaml:"blob-fee-multiple" toml:"blob-fee-multiple"`
+	// Proportional, integral and derivative gains, in percent
+	GainPPercent uint64 `yaml:"gain-p-percent" toml:"gain-p-percent"`
+	GainIPercent uint64 `yaml:"gain-i-percent" toml:"gain-i-percent"`
+	GainDPercent uint64 `yaml:"gain-d-percent" toml:"gain-d-percent"`
+	// Most the reserve price moves per slot, unbounded if 0
+	MaxStepWei uint64 `yaml:"max-step-wei" toml:"max-step-wei"`
+}
+
+// See funding.Config
+type FundingConfig struct {
+	// Between checks of the settlement key's balance, 0 disables the watcher
+	Interval time.Duration `yaml:"interval" toml:"interval"`
+	// Gas a settlement tx is projected to use
+	SettlementGas uint64 `yaml:"settlement-gas" toml:"settlement-gas"`
+	// Alerted below this balance, or once it covers settling every slot for less than MinRunway. Disabled if 0.
+	MinBalanceGwei uint64        `yaml:"min-balance-gwei" toml:"min-balance-gwei"`
+	MinRunway      time.Duration `yaml:"min-runway" toml:"min-runway"`
+}
+
+// Pricing of settlement txs from fee history, see gasoracle.Config
+type GasConfig struct {
+	// Recent blocks the priority fee is estimated from, at this percentile of each block's priority fees
+	Blocks     uint64 `yaml:"blocks" toml:"blocks"`
+	Percentile int    `yaml:"percentile" toml:"percentile"`
+	// The max fee covers the next block's base fee times this, plus the priority fee
+	BaseFeeMultiplier uint64 `yaml:"base-fee-multiplier" toml:"base-fee-multiplier"`
+	// Bounds of the priority fee and cap of the max fee, none if 0
+	MinPriorityFeeGwei uint64 `yaml:"min-priority-fee-gwei" toml:"min-priority-fee-gwei"`
+	MaxPriorityFeeGwei uint64 `yaml:"max-priority-fee-gwei" toml:"max-priority-fee-gwei"`
+	MaxFeeGwei         uint64 `yaml:"max-fee-gwei" toml:"max-fee-gwei"`
+}
+
+// Public, append-only locations signed auction results are published to each slot, see bulletin. Disabled if
+// both are empty.
+type BulletinConfig struct {
+	// HTTP bulletin endpoint results are posted to
+	URL string `yaml:"url" toml:"url"`
+	// File results are appended to as JSON lines, e.g. one served statically
+	Path string `yaml:"path" toml:"path"`
+}
+
+func (c BulletinConfig) Enabled() bool {
+	return c.URL != "" || c.Path != ""
+}
+
+type HeartbeatConfig struct {
+	// Publish a signed heartbeat on the event feed every slot, see heartbeat
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+}
+
+// Bids encrypted to a sealing key, opened only as their auction closes, see sealed. Held by the auctioneer with
+// KeyFile, or split among a committee, for bids hidden from the operator too. Disabled unless either is set.
+type SealedConfig struct {
+	// Hex secp256k1 private key sealed bids are opened with
+	KeyFile string `yaml:"key-file" toml:"key-file"`
+	// Compressed hex sealing key split among the committee, see the sealed split command
+	PublicKey string `yaml:"public-key" toml:"public-key"`
+	// Base URLs of the committee members' share servers, any Threshold of which open bids
+	Committee []string `yaml:"committee" toml:"committee"`
+	Threshold int      `yaml:"threshold" toml:"threshold"`
+}
+
+func (c SealedConfig) Enabled() bool {
+	return c.KeyFile != "" || len(c.Committee) > 0
+}
+
+// Third-party watchers counter-signing issued commitments, see attestation. Disabled unless Addresses is set.
+type WatchersConfig struct {
+	// Watchers whose attestations are accepted
+	Addresses []string `yaml:"addresses" toml:"addresses"`
+	// Attestations a commitment needs to be multi-attested
+	Quorum int `yaml:"quorum" toml:"quorum"`
+}
+
+func (c WatchersConfig) Enabled() bool {
+	return len(c.Addresses) > 0
+}
+
+type DaemonConfig struct {
+	// File the process ID is written to while running, e.g. for systemd's PIDFile, disabled if empty
+	PIDFile string `yaml:"pid-file" toml:"pid-file"`
+	// Time allowed on SIGTERM for the auction in progress to close and servers to drain
+	ShutdownTimeout time.Duration `yaml:"shutdown-timeout" toml:"shutdown-timeout"`
+}
+
+// Fault injection for rehearsing incidents, see chaos. Refused on mainnet.
+type ChaosConfig struct {
+	// Percentage of submitted bids silently dropped
+	DropBidsPercent int `yaml:"drop-bids-percent" toml:"drop-bids-percent"`
+	// Delay before won auctions are handed to settlement
+	WinnerDelay time.Duration `yaml:"winner-delay" toml:"winner-delay"`
+	// Percentage of settlement submits failed
+	FailSettlementsPercent int `yaml:"fail-settlements-percent" toml:"fail-settlements-percent"`
+}
+
+func (c ChaosConfig) Enabled() bool {
+	return c.DropBidsPercent > 0 || c.WinnerDelay > 0 || c.FailSettlementsPercent > 0
+}
+
+// Replicas of the auctioneer electing a leader by lease (see election), which alone awards and settles winners,
+// while followers shadow the bids gossiped between replicas (see gossip). Disabled if LeaseBackend is empty.
+type FederationConfig struct {
+	// Unique ID of this replica, e.g. its hostname
+	ReplicaID string `yaml:"replica-id" toml:"replica-id"`
+	// sqlite, for replicas on one host, or postgres
+	LeaseBackend string `yaml:"lease-backend" toml:"lease-backend"`
+	// Database file for sqlite
+	LeasePath string `yaml:"lease-path" toml:"lease-path"`
+	// Connection string for postgres
+	LeaseURL string `yaml:"lease-url" toml:"lease-url"`
+	// How long the leader holds the lease without renewing it, and thus how long a failover takes
+	LeaseTTL time.Duration `yaml:"lease-ttl" toml:"lease-ttl"`
+	// libp2p multiaddrs to gossip bids on, e.g. /ip4/0.0.0.0/tcp/9000
+	GossipListen []string `yaml:"gossip-listen" toml:"gossip-listen"`
+	// Multiaddrs of the other replicas, including their /p2p/ peer ID
+	GossipPeers []string `yaml:"gossip-peers" toml:"gossip-peers"`
+	// Hex secp256k1 private key of the replica's stable gossip identity, a random one if empty
+	GossipKeyFile string `yaml:"gossip-key-file" toml:"gossip-key-file"`
+}
+
+func (c FederationConfig) Enabled() bool {
+	return c.LeaseBackend != ""
+}
+
+func Default() Config {
+	return Config{
+		L1:          L1Config{PollInterval: 200 * time.Millisecond},
+		NetworkName: "mainnet",
+		Signer:      SignerConfig{GracePeriod: time.Hour},
+		Auction:     AuctionConfig{Period: 5 * time.Second, EscrowCacheTTL: time.Second},
+		Reserve:     ReserveConfig{Window: 32, TargetPercent: 80, GainPPercent: 50, GainIPercent: 10},
+		Registry:    RegistryConfig{Source: RegistryStatic, RefreshInterval: time.Minute},
+		Award:       AwardConfig{Attempts: 3, Timeout: 5 * time.Second, AcceptDeadline: 2 * time.Second},
+		Commitment:  CommitmentConfig{MaxEscalations: 3, MaxRenewals: 1},
+		Intake:      IntakeConfig{MaxPending: 16, MaxRequests: 64, Window: time.Minute, BlobsPerBlock: 6, ValidityBlocks: 1},
+		Store:       StoreConfig{Backend: "memory"},
+		REST:        ServerConfig{Addr: ":8080"},
+		JSONRPC:     ServerConfig{Addr: ":8545"},
+		GRPC:        ServerConfig{Addr: ":9090"},
+		Metrics:     ServerConfig{Addr: ":9100"},
+		Admin:       AdminConfig{Addr: "127.0.0.1:9200"},
+		Log: LogConfig{
+			Level:          "info",
+			Format:         "text",
+			MaxSizeMB:      100,
+			MaxBackups:     10,
+			SampleInterval: time.Second,
+		},
+		Transport:  TransportConfig{MaxMessageSize: 32 * 1024, MessageRate: 100, MessageBurst: 200},
+		Event:      EventConfig{Subject: "auctioneer.events", BufferSize: 1024},
+		Alert:      AlertConfig{DedupInterval: 10 * time.Minute, RPCErrors: 5, RPCWindow: time.Minute},
+		Health:     HealthConfig{MaxAuctionAge: time.Minute},
+		Retention:  RetentionConfig{Interval: time.Hour},
+		Clock:      ClockConfig{MaxDrift: 2 * time.Second, Interval: 30 * time.Second},
+		Funding:    FundingConfig{Interval: time.Minute, SettlementGas: 150_000, MinRunway: time.Hour},
+		Gas:        GasConfig{Blocks: 20, Percentile: 50, BaseFeeMultiplier: 2, MaxPriorityFeeGwei: 10, MaxFeeGwei: 500},
+		Heartbeat:  HeartbeatConfig{Enabled: true},
+		Daemon:     DaemonConfig{ShutdownTimeout: 30 * time.Second},
+		Federation: FederationConfig{LeaseTTL: 4 * time.Second},
+	}
+}
+
+// Reports every invalid key, wrapping ErrInvalidConfig
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(key string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+	if c.L1.RPCURL == "" {
+		fail("l1.rpc-url", "required")
+	} else if u, err := url.Parse(c.L1.RPCURL); err != nil || u.Scheme == "" {
+		fail("l1.rpc-url", "invalid url %q", c.L1.RPCURL)
+	}
+	if c.L1.PollInterval <= 0 {
+		fail("l1.poll-interval", "must be positive")
+	}
+	if err := c.Signer.Source().Validate(); err != nil {
+		fail("signer", "%s", strings.TrimPrefix(err.Error(), keys.ErrInvalidSource.Error()+": "))
+	}
+	if c.Signer.GracePeriod < 0 {
+		fail("signer.grace-period", "must not be negative")
+	}
+	if _, ok := networks[c.NetworkName]; c.NetworkName != "" && !ok {
+		fail("network", "unknown network %q, expected one of %s", c.NetworkName, strings.Join(Networks(), ", "))
+	}
+	network := c.Network()
+	if network.ChainID == 0 {
+		fail("chain.chain-id", "required without a network preset")
+	}
+	if network.SlotTime <= 0 {
+		fail("chain.slot-time", "must be positive")
+	}
+	if network.MaxBlobsPerBlock <= 0 {
+		fail("chain.max-blobs-per-block", "must be positive")
+	}
+	if c.Chain.RegistryContract != "" && !common.IsHexAddress(c.Chain.RegistryContract) {
+		fail("chain.registry-contract", "invalid address %q", c.Chain.RegistryContract)
+	}
+	if c.Auction.Period <= 0 {
+		fail("auction.period", "must be positive")
+	} else if network.SlotTime > 0 && c.Auction.Period >= network.SlotTime {
+		fail("auction.period", "must be shorter than the %s slot time", network.SlotTime)
+	}
+	if c.Auction.OpenOffset < 0 {
+		fail("auction.open-offset", "must not be negative")
+	}
+	if c.Auction.CloseOffset < 0 {
+		fail("auction.close-offset", "must not be negative")
+	} else if c.Auction.CloseOffset > 0 {
+		if c.Auction.CloseOffset <= c.Auction.OpenOffset {
+			fail("auction.close-offset", "must be after auction.open-offset")
+		}
+		if network.SlotTime > 0 && c.Auction.CloseOffset >= network.SlotTime {
+			fail("auction.close-offset", "must be within the %s slot time", network.SlotTime)
+		}
+		if network.GenesisTime.IsZero() {
+			fail("chain.genesis-time", "required for auction.close-offset")
+		}
+	}
+	if c.Auction.Verifiers < 0 {
+		fail("auction.verifiers", "must not be negative")
+	}
+	if c.Auction.Shards < 0 {
+		fail("auction.shards", "must not be negative")
+	}
+	if c.Auction.MinOpen < 0 || (c.Auction.Period > 0 && c.Auction.MinOpen >= c.Auction.Period) {
+		fail("auction.min-open", "must be shorter than the auction period")
+	}
+	if c.Auction.QuietPeriod < 0 {
+		fail("auction.quiet-period", "must not be negative")
+	}
+	if c.Auction.PreOpenWindow < 0 {
+		fail("auction.pre-open-window", "must not be negative")
+	}
+	if c.Auction.MissedSlotPeriod < 0 {
+		fail("auction.missed-slot-period", "must not be negative")
+	} else if network.SlotTime > 0 && c.Auction.MissedSlotPeriod >= network.SlotTime {
+		fail("auction.missed-slot-period", "must be shorter than the %s slot time", network.SlotTime)
+	}
+	switch c.Auction.MissedSlotOutcome {
+	case "", "refund", "carry-over":
+	default:
+		fail("auction.missed-slot-outcome", "must be refund or carry-over")
+	}
+	if c.Auction.MissedSlotGrace < 0 {
+		fail("auction.missed-slot-grace", "must not be negative")
+	} else if network.SlotTime > 0 && c.Auction.MissedSlotGrace >= network.SlotTime {
+		fail("auction.missed-slot-grace", "must be shorter than the %s slot time", network.SlotTime)
+	}
+	if (c.Auction.BlobFeeSpikeWei == 0) != (c.Auction.SpikeReserveWei == 0) {
+		fail("auction.spike-reserve-wei", "must be set with auction.blob-fee-spike-wei")
+	}
+	if r := c.Reserve; r.Dynamic {
+		if r.Window <= 0 {
+			fail("reserve.window", "must be positive")
+		}
+		if r.TargetPercent == 0 {
+			fail("reserve.target-percent", "must be positive")
+		}
+		if r.MaxWei > 0 && r.MaxWei < r.MinWei {
+			fail("reserve.max-wei", "must be at least reserve.min-wei")
+		}
+		if r.GainPPercent == 0 && r.GainIPercent == 0 && r.GainDPercent == 0 {
+			fail("reserve.gain-p-percent", "a gain must be positive for the reserve price to move")
+		}
+	}
+	if c.Auction.EscrowCheck {
+		if c.Registry.Source != RegistryAVS {
+			fail("auction.escrow-check", "requires registry.source %s", RegistryAVS)
+		}
+		if c.Auction.EscrowCacheTTL <= 0 {
+			fail("auction.escrow-cache-ttl", "must be positive")
+		}
+	}
+	if c.Auction.BondLowPercent > 100 {
+		fail("auction.bond-low-percent", "must be between 0 and 100")
+	} else if c.Auction.BondLowPercent > 0 && c.Registry.Source != RegistryAVS {
+		fail("auction.bond-low-percent", "requires registry.source %s", RegistryAVS)
+	}
+	for _, list := range []struct {
+		key       string
+		addresses []string
+	}{{"auction.allowlist", c.Auction.Allowlist}, {"auction.denylist", c.Auction.Denylist}, {"registry.relays", c.Registry.Relays}} {
+		for _, address := range list.addresses {
+			if !common.IsHexAddress(address) {
+				fail(list.key, "invalid address %q", address)
+			}
+		}
+	}
+	switch c.Registry.Source {
+	case RegistryStatic:
+	case RegistryMevBoost:
+		if len(c.Registry.MevBoostRelays) == 0 {
+			fail("registry.mev-boost-relays", "required for mev-boost")
+		}
+		for bidder, relayURL := range c.Registry.MevBoostRelays {
+			if !common.IsHexAddress(bidder) {
+				fail("registry.mev-boost-relays", "invalid address %q", bidder)
+			}
+			if _, _, err := mevboost.ParseRelayURL(relayURL); err != nil {
+				fail("registry.mev-boost-relays", "%v", err)
+			}
+		}
+		if c.Registry.RefreshInterval <= 0 {
+			fail("registry.refresh-interval", "must be positive")
+		}
+	case RegistryAVS:
+		for _, key := range []struct {
+			name, value string
+		}{{"registry.avs.registry-coordinator", c.Registry.AVS.RegistryCoordinator}, {"registry.avs.stake-registry", c.Registry.AVS.StakeRegistry}} {
+			if !common.IsHexAddress(key.value) {
+				fail(key.name, "invalid address %q", key.value)
+			}
+		}
+		if c.Registry.AVS.Quorum < 0 || c.Registry.AVS.Quorum > 255 {
+			fail("registry.avs.quorum", "must be between 0 and 255")
+		}
+		if len(c.Registry.Relays) == 0 {
+			fail("registry.relays", "required for avs")
+		}
+		if c.Registry.RefreshInterval <= 0 {
+			fail("registry.refresh-interval", "must be positive")
+		}
+	default:
+		fail("registry.source", "unknown source %q", c.Registry.Source)
+	}
+	if c.Registry.BidQuota < 0 {
+		fail("registry.bid-quota", "must not be negative")
+	}
+	for relay, tier := range c.Registry.Tiers {
+		if !common.IsHexAddress(relay) {
+			fail("registry.tiers", "invalid address %q", relay)
+		}
+		if _, ok := c.Registry.TierQuotas[tier]; !ok {
+			fail("registry.tiers", "tier %q has no quota in registry.tier-quotas", tier)
+		}
+	}
+	for tier, quota := range c.Registry.TierQuotas {
+		if n, err := strconv.Atoi(quota); err != nil || n < 0 {
+			fail("registry.tier-quotas", "invalid quota %q for tier %q", quota, tier)
+		}
+	}
+	for relay, endpoint := range c.Award.Endpoints {
+		if !common.IsHexAddress(relay) {
+			fail("award.endpoints", "invalid address %q", relay)
+		}
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			fail("award.endpoints", "invalid url %q, expected http(s)", endpoint)
+		}
+	}
+	if c.Award.Attempts <= 0 {
+		fail("award.attempts", "must be positive")
+	}
+	if c.Award.Timeout <= 0 {
+		fail("award.timeout", "must be positive")
+	}
+	if c.Award.AcceptDeadline <= 0 {
+		fail("award.accept-deadline", "must be positive")
+	} else if len(c.Award.Endpoints) > 0 && network.SlotTime > 0 && c.Award.AcceptDeadline >= network.SlotTime {
+		fail("award.accept-deadline", "must be shorter than the %s slot time", network.SlotTime)
+	}
+	if c.Commitment.EscalateProposerFaults {
+		if c.Auction.MissedSlotOutcome == "" {
+			fail("commitment.escalate-proposer-faults", "requires auction.missed-slot-outcome")
+		}
+		if c.Commitment.MaxEscalations == 0 {
+			fail("commitment.max-escalations", "must be positive")
+		}
+	}
+	if c.Commitment.AutoRenew {
+		if c.Auction.MissedSlotOutcome == "" {
+			fail("commitment.auto-renew", "requires auction.missed-slot-outcome")
+		}
+		if c.Commitment.MaxRenewals <= 0 {
+			fail("commitment.max-renewals", "must be positive")
+		}
+	}
+	if c.Intake.Enabled {
+		if c.Pricing.FeePerBlobWei == 0 {
+			fail("pricing.fee-per-blob-wei", "required for intake")
+		}
+		if c.Intake.MaxPending < 0 {
+			fail("intake.max-pending", "must not be negative")
+		}
+		if c.Intake.MaxRequests < 0 {
+			fail("intake.max-requests", "must not be negative")
+		} else if c.Intake.MaxRequests > 0 && c.Intake.Window <= 0 {
+			fail("intake.window", "must be positive to limit intake.max-requests")
+		}
+		if c.Intake.BlobsPerBlock <= 0 {
+			fail("intake.blobs-per-block", "must be positive")
+		}
+		// Commitments issued as the auction opens, after its block may already be observed
+		if c.Intake.ValidityBlocks == 0 {
+			fail("intake.validity-blocks", "must be positive")
+		}
+	}
+	switch c.Store.Backend {
+	case "memory":
+	case "leveldb", "sqlite":
+		if c.Store.Path == "" {
+			fail("store.path", "required for %s", c.Store.Backend)
+		}
+	case "postgres":
+		if c.Store.URL == "" {
+			fail("store.url", "required for postgres")
+		}
+	default:
+		fail("store.backend", "unknown backend %q", c.Store.Backend)
+	}
+	for _, server := range []struct {
+		key  string
+		addr string
+	}{
+		{"rest.addr", c.REST.Addr}, {"jsonrpc.addr", c.JSONRPC.Addr}, {"grpc.addr", c.GRPC.Addr},
+		{"graphql.addr", c.GraphQL.Addr}, {"metrics.addr", c.Metrics.Addr}, {"admin.addr", c.Admin.Addr},
+	} {
+		if server.addr == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(server.addr); err != nil {
+			fail(server.key, "invalid address %q", server.addr)
+		}
+	}
+	if c.Admin.Addr != "" && c.Admin.Token == "" {
+		fail("admin.token", "required when the admin api is enabled")
+	}
+	if c.Transport.MaxMessageSize < 1024 {
+		fail("transport.max-message-size", "must be at least 1024 bytes")
+	}
+	if c.Transport.MessageRate < 0 {
+		fail("transport.message-rate", "must not be negative")
+	}
+	if c.Transport.MessageRate > 0 && c.Transport.MessageBurst < 1 {
+		fail("transport.message-burst", "must be positive with a message rate")
+	}
+	switch c.Event.Sink {
+	case "", "stdout":
+	case "file":
+		if c.Event.Path == "" {
+			fail("event.path", "required for the file sink")
+		}
+	case "nats":
+		if c.Event.URL == "" {
+			fail("event.url", "required for the nats sink")
+		}
+	case "kafka":
+		if len(c.Event.Brokers) == 0 {
+			fail("event.brokers", "required for the kafka sink")
+		}
+	default:
+		fail("event.sink", "unknown sink %q", c.Event.Sink)
+	}
+	if c.Event.BufferSize <= 0 {
+		fail("event.buffer-size", "must be positive")
+	}
+	if c.Alert.RPCErrors > 0 && c.Alert.RPCWindow <= 0 {
+		fail("alert.rpc-window", "must be positive to alert on rpc errors")
+	}
+	if c.Health.MaxAuctionAge <= 0 {
+		fail("health.max-auction-age", "must be positive")
+	}
+	if c.Retention.Bids > 0 && c.Retention.Interval <= 0 {
+		fail("retention.interval", "must be positive to prune bids")
+	}
+	if c.Clock.MaxDrift < 0 {
+		fail("clock.max-drift", "must not be negative")
+	}
+	if c.Clock.MaxDrift > 0 && c.Clock.Interval <= 0 {
+		fail("clock.interval", "must be positive to check the clock")
+	}
+	if c.Clock.NTPServer != "" {
+		if _, _, err := net.SplitHostPort(c.Clock.NTPServer); err != nil {
+			fail("clock.ntp-server", "invalid address %q, expected host:port", c.Clock.NTPServer)
+		}
+	}
+	if c.Funding.Interval < 0 {
+		fail("funding.interval", "must not be negative")
+	} else if c.Funding.Interval > 0 && c.Funding.SettlementGas == 0 {
+		fail("funding.settlement-gas", "must be positive to watch the settlement key")
+	}
+	if c.Funding.MinRunway < 0 {
+		fail("funding.min-runway", "must not be negative")
+	}
+	if c.Gas.Blocks == 0 || c.Gas.Blocks > 1024 {
+		fail("gas.blocks", "must be between 1 and 1024")
+	}
+	if c.Gas.Percentile <= 0 || c.Gas.Percentile > 100 {
+		fail("gas.percentile", "must be between 1 and 100")
+	}
+	if c.Gas.BaseFeeMultiplier == 0 {
+		fail("gas.base-fee-multiplier", "must be positive")
+	}
+	if c.Gas.MaxPriorityFeeGwei > 0 && c.Gas.MinPriorityFeeGwei > c.Gas.MaxPriorityFeeGwei {
+		fail("gas.min-priority-fee-gwei", "must not exceed gas.max-priority-fee-gwei")
+	}
+	if c.Gas.MaxFeeGwei > 0 && max(c.Gas.MinPriorityFeeGwei, c.Gas.MaxPriorityFeeGwei) > c.Gas.MaxFeeGwei {
+		fail("gas.max-fee-gwei", "must cover the priority fee bounds")
+	}
+	if c.Bulletin.URL != "" {
+		if u, err := url.Parse(c.Bulletin.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			fail("bulletin.url", "invalid url %q, expected http(s)", c.Bulletin.URL)
+		}
+	}
+	if c.Sealed.KeyFile != "" && len(c.Sealed.Committee) > 0 {
+		fail("sealed.key-file", "must not be set with sealed.committee")
+	}
+	if len(c.Sealed.Committee) > 0 {
+		if key, err := hexutil.Decode(c.Sealed.PublicKey); err != nil {
+			fail("sealed.public-key", "invalid compressed public key %q", c.Sealed.PublicKey)
+		} else if _, err := crypto.DecompressPubkey(key); err != nil {
+			fail("sealed.public-key", "invalid compressed public key %q", c.Sealed.PublicKey)
+		}
+		if c.Sealed.Threshold < 1 || c.Sealed.Threshold > len(c.Sealed.Committee) {
+			fail("sealed.threshold", "must be between 1 and the %d committee members", len(c.Sealed.Committee))
+		}
+		for _, member := range c.Sealed.Committee {
+			if u, err := url.Parse(member); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				fail("sealed.committee", "invalid url %q, expected http(s)", member)
+			}
+		}
+	}
+	for _, watcher := range c.Watchers.Addresses {
+		if !common.IsHexAddress(watcher) {
+			fail("watchers.addresses", "invalid address %q", watcher)
+		}
+	}
+	if c.Watchers.Enabled() && (c.Watchers.Quorum < 1 || c.Watchers.Quorum > len(c.Watchers.Addresses)) {
+		fail("watchers.quorum", "must be between 1 and the %d watchers", len(c.Watchers.Addresses))
+	}
+	if c.Daemon.ShutdownTimeout <= 0 {
+		fail("daemon.shutdown-timeout", "must be positive")
+	}
+	for _, percent := range []struct {
+		key   string
+		value int
+	}{{"chaos.drop-bids-percent", c.Chaos.DropBidsPercent}, {"chaos.fail-settlements-percent", c.Chaos.FailSettlementsPercent}} {
+		if percent.value < 0 || percent.value > 100 {
+			fail(percent.key, "must be between 0 and 100")
+		}
+	}
+	if c.Chaos.WinnerDelay < 0 {
+		fail("chaos.winner-delay", "must not be negative")
+	}
+	if c.Chaos.Enabled() && network.ChainID == networks["mainnet"].ChainID {
+		fail("chaos", "fault injection refused on mainnet")
+	}
+	if c.Federation.Enabled() {
+		if c.Chaos.Enabled() {
+			fail("chaos", "fault injection unsupported with federation")
+		}
+		if c.Intake.Enabled {
+			fail("intake.enabled", "unsupported with federation")
+		}
+		if c.Federation.ReplicaID == "" {
+			fail("federation.replica-id", "required for federation")
+		}
+		switch c.Federation.LeaseBackend {
+		case "sqlite":
+			if c.Federation.LeasePath == "" {
+				fail("federation.lease-path", "required for sqlite")
+			}
+		case "postgres":
+			if c.Federation.LeaseURL == "" {
+				fail("federation.lease-url", "required for postgres")
+			}
+		default:
+			fail("federation.lease-backend", "unknown backend %q", c.Federation.LeaseBackend)
+		}
+		if c.Federation.LeaseTTL <= 0 {
+			fail("federation.lease-ttl", "must be positive")
+		} else if network.SlotTime > 0 && c.Federation.LeaseTTL >= network.SlotTime {
+			fail("federation.lease-ttl", "must be shorter than the %s slot time, for followers to take over mid-slot", network.SlotTime)
+		}
+		if len(c.Federation.GossipListen) == 0 {
+			fail("federation.gossip-listen", "required for followers to shadow bids")
+		}
+	}
+	for name, path := range c.Engines {
+		if !engineName.MatchString(name) {
+			fail("engines", "invalid engine name %q, expected lowercase letters, digits and dashes", name)
+		} else if name == c.EngineName() {
+			fail("engines", "engine name %q is the node's own", name)
+		}
+		if path == "" {
+			fail("engines", "config file required for engine %q", name)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w:\n%w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Name of the node's own chain among its engines: its network preset, or primary without one
+func (c Config) EngineName() string {
+	if c.NetworkName != "" {
+		return c.NetworkName
+	}
+	return "primary"
+}
@@ -0,0 +1,155 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() config.Config {
+	c := config.Default()
+	c.L1.RPCURL = "http://localhost:8545"
+	c.Signer.KeyFile = "/etc/auctioneer/key"
+	c.Admin.Token = "secret"
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, validConfig().Validate())
+	require.ErrorIs(t, config.Default().Validate(), config.ErrInvalidConfig, "the rpc url and signer have no defaults")
+
+	for name, test := range map[string]struct {
+		change func(c *config.Config)
+		err    string
+	}{
+		"no rpc":              {func(c *config.Config) { c.L1.RPCURL = "" }, "l1.rpc-url: required"},
+		"invalid rpc":         {func(c *config.Config) { c.L1.RPCURL = "localhost" }, "l1.rpc-url: invalid url"},
+		"no poll interval":    {func(c *config.Config) { c.L1.PollInterval = 0 }, "l1.poll-interval: must be positive"},
+		"no signer":           {func(c *config.Config) { c.Signer.KeyFile = "" }, "signer: keystore or key file required"},
+		"keystore and file":   {func(c *config.Config) { c.Signer.KeystoreDir = "keystore" }, "signer: keystore and key file are mutually exclusive"},
+		"no password":         {func(c *config.Config) { c.Signer.KeyFile, c.Signer.KeystoreDir = "", "keystore" }, "signer: password file required"},
+		"negative grace":      {func(c *config.Config) { c.Signer.GracePeriod = -time.Second }, "signer.grace-period: must not be negative"},
+		"no auction period":   {func(c *config.Config) { c.Auction.Period = 0 }, "auction.period: must be positive"},
+		"negative verifiers":  {func(c *config.Config) { c.Auction.Verifiers = -1 }, "auction.verifiers: must not be negative"},
+		"negative shards":     {func(c *config.Config) { c.Auction.Shards = -1 }, "auction.shards: must not be negative"},
+		"long min open":       {func(c *config.Config) { c.Auction.MinOpen = c.Auction.Period }, "auction.min-open: must be shorter than the auction period"},
+		"invalid relay":       {func(c *config.Config) { c.Auction.Denylist = []string{"0x01"} }, "auction.denylist: invalid address"},
+		"no mev-boost relays": {func(c *config.Config) { c.Registry.Source = "mev-boost" }, "registry.mev-boost-relays: required for mev-boost"},
+		"invalid mev-boost relay": {func(c *config.Config) {
+			c.Registry.Source, c.Registry.MevBoostRelays = "mev-boost", map[string]string{"0x0000000000000000000000000000000000000001": "https://relay.example.com"}
+		}, "registry.mev-boost-relays: invalid mev-boost relay"},
+		"invalid avs contract": {func(c *config.Config) {
+			c.Registry.Source, c.Registry.AVS.StakeRegistry = "avs", "0x0000000000000000000000000000000000000001"
+		}, "registry.avs.registry-coordinator: invalid address"},
+		"no avs operators": {func(c *config.Config) { c.Registry.Source = "avs" }, "registry.relays: required for avs"},
+		"escrow check":     {func(c *config.Config) { c.Auction.EscrowCheck = true }, "auction.escrow-check: requires registry.source avs"},
+		"bond low source":  {func(c *config.Config) { c.Auction.BondLowPercent = 20 }, "auction.bond-low-percent: requires registry.source avs"},
+		"bond low range":   {func(c *config.Config) { c.Auction.BondLowPercent = 101 }, "auction.bond-low-percent: must be between 0 and 100"},
+		"spike reserve":    {func(c *config.Config) { c.Auction.BlobFeeSpikeWei = 1000 }, "auction.spike-reserve-wei: must be set with auction.blob-fee-spike-wei"},
+		"unknown registry": {func(c *config.Config) { c.Registry.Source = "contract" }, "registry.source: unknown source"},
+		"undefined tier": {func(c *config.Config) {
+			c.Registry.Tiers = map[string]string{"0x0000000000000000000000000000000000000001": "premium"}
+		}, `registry.tiers: tier "premium" has no quota`},
+		"invalid tier quota": {func(c *config.Config) { c.Registry.TierQuotas = map[string]string{"premium": "-1"} }, "registry.tier-quotas: invalid quota"},
+		"dynamic reserve bounds": {func(c *config.Config) {
+			c.Reserve = config.ReserveConfig{Dynamic: true, Window: 32, TargetPercent: 80, GainPPercent: 50, MinWei: 100, MaxWei: 10}
+		}, "reserve.max-wei: must be at least reserve.min-wei"},
+		"dynamic reserve gains": {func(c *config.Config) {
+			c.Reserve = config.ReserveConfig{Dynamic: true, Window: 32, TargetPercent: 80}
+		}, "reserve.gain-p-percent: a gain must be positive"},
+		"escalation without missed slots": {func(c *config.Config) {
+			c.Commitment.EscalateProposerFaults = true
+		}, "commitment.escalate-proposer-faults: requires auction.missed-slot-outcome"},
+		"no escalations": {func(c *config.Config) {
+			c.Auction.MissedSlotOutcome = "refund"
+			c.Commitment = config.CommitmentConfig{EscalateProposerFaults: true}
+		}, "commitment.max-escalations: must be positive"},
+		"renewal without missed slots": {func(c *config.Config) {
+			c.Commitment.AutoRenew = true
+		}, "commitment.auto-renew: requires auction.missed-slot-outcome"},
+		"no renewals": {func(c *config.Config) {
+			c.Auction.MissedSlotOutcome = "refund"
+			c.Commitment = config.CommitmentConfig{AutoRenew: true}
+		}, "commitment.max-renewals: must be positive"},
+		"intake without pricing": {func(c *config.Config) { c.Intake.Enabled = true }, "pricing.fee-per-blob-wei: required for intake"},
+		"intake without window": {func(c *config.Config) {
+			c.Pricing.FeePerBlobWei, c.Intake.Enabled, c.Intake.Window = 1000, true, 0
+		}, "intake.window: must be positive to limit intake.max-requests"},
+		"intake without validity": {func(c *config.Config) {
+			c.Pricing.FeePerBlobWei, c.Intake.Enabled, c.Intake.ValidityBlocks = 1000, true, 0
+		}, "intake.validity-blocks: must be positive"},
+		"award endpoint": {func(c *config.Config) {
+			c.Award.Endpoints = map[string]string{"0x0000000000000000000000000000000000000001": "relay.example.com"}
+		}, "award.endpoints: invalid url"},
+		"award attempts":   {func(c *config.Config) { c.Award.Attempts = 0 }, "award.attempts: must be positive"},
+		"unknown store":    {func(c *config.Config) { c.Store.Backend = "redis" }, "store.backend: unknown backend"},
+		"no store path":    {func(c *config.Config) { c.Store.Backend = "sqlite" }, "store.path: required for sqlite"},
+		"no store url":     {func(c *config.Config) { c.Store.Backend = "postgres" }, "store.url: required for postgres"},
+		"invalid addr":     {func(c *config.Config) { c.GRPC.Addr = "9090" }, "grpc.addr: invalid address"},
+		"no admin token":   {func(c *config.Config) { c.Admin.Token = "" }, "admin.token: required"},
+		"unknown sink":     {func(c *config.Config) { c.Event.Sink = "redis" }, "event.sink: unknown sink"},
+		"no kafka brokers": {func(c *config.Config) { c.Event.Sink = "kafka" }, "event.brokers: required"},
+		"no retention run": {func(c *config.Config) { c.Retention.Bids, c.Retention.Interval = time.Hour, 0 }, "retention.interval: must be positive"},
+		"close before open": {func(c *config.Config) {
+			c.Auction.OpenOffset, c.Auction.CloseOffset = 8*time.Second, 2*time.Second
+		}, "auction.close-offset: must be after auction.open-offset"},
+		"close after slot": {func(c *config.Config) { c.Auction.CloseOffset = 12 * time.Second }, "auction.close-offset: must be within the 12s slot time"},
+		"no genesis time": {func(c *config.Config) {
+			c.NetworkName, c.Auction.CloseOffset = "local", 8*time.Second
+		}, "chain.genesis-time: required for auction.close-offset"},
+		"pre-open window":  {func(c *config.Config) { c.Auction.PreOpenWindow = -time.Second }, "auction.pre-open-window: must not be negative"},
+		"missed outcome":   {func(c *config.Config) { c.Auction.MissedSlotOutcome = "rebid" }, "auction.missed-slot-outcome: must be refund or carry-over"},
+		"missed grace":     {func(c *config.Config) { c.Auction.MissedSlotGrace = 12 * time.Second }, "auction.missed-slot-grace: must be shorter than the 12s slot time"},
+		"negative drift":   {func(c *config.Config) { c.Clock.MaxDrift = -time.Second }, "clock.max-drift: must not be negative"},
+		"ntp server":       {func(c *config.Config) { c.Clock.NTPServer = "pool.ntp.org" }, "clock.ntp-server: invalid address"},
+		"settlement gas":   {func(c *config.Config) { c.Funding.SettlementGas = 0 }, "funding.settlement-gas: must be positive"},
+		"gas percentile":   {func(c *config.Config) { c.Gas.Percentile = 101 }, "gas.percentile: must be between 1 and 100"},
+		"bulletin url":     {func(c *config.Config) { c.Bulletin.URL = "ipfs://results" }, "bulletin.url: invalid url"},
+		"gas fee cap":      {func(c *config.Config) { c.Gas.MaxFeeGwei = 5 }, "gas.max-fee-gwei: must cover the priority fee bounds"},
+		"bids drop range":  {func(c *config.Config) { c.NetworkName, c.Chaos.DropBidsPercent = "sepolia", 101 }, "chaos.drop-bids-percent: must be between 0 and 100"},
+		"chaos on mainnet": {func(c *config.Config) { c.Chaos.WinnerDelay = time.Second }, "chaos: fault injection refused on mainnet"},
+		"sealed committee": {func(c *config.Config) {
+			c.Sealed.Committee, c.Sealed.PublicKey, c.Sealed.Threshold = []string{"https://member.example.com"}, "0x02", 1
+		}, "sealed.public-key: invalid compressed public key"},
+		"message burst": {func(c *config.Config) {
+			c.Transport.MessageRate, c.Transport.MessageBurst = 10, 0
+		}, "transport.message-burst: must be positive with a message rate"},
+		"watchers quorum": {func(c *config.Config) {
+			c.Watchers.Addresses, c.Watchers.Quorum = []string{"0x9f4a2e3f7b7a5c7f1ab0c5f1fcd2bd8b0c2e1b44"}, 2
+		}, "watchers.quorum: must be between 1 and the 1 watchers"},
+		"no replica id": {func(c *config.Config) {
+			c.Federation.LeaseBackend, c.Federation.LeasePath, c.Federation.GossipListen = "sqlite", "leases.db", []string{"/ip4/0.0.0.0/tcp/9000"}
+		}, "federation.replica-id: required for federation"},
+		"long lease": {func(c *config.Config) {
+			c.Federation.LeaseBackend, c.Federation.LeaseURL, c.Federation.LeaseTTL = "postgres", "postgres://localhost/auctioneer", 12*time.Second
+		}, "federation.lease-ttl: must be shorter than the 12s slot time"},
+		"engine name": {func(c *config.Config) { c.Engines = map[string]string{"Holesky": "holesky.yaml"} }, `engines: invalid engine name "Holesky"`},
+		"engine is node": {func(c *config.Config) {
+			c.NetworkName, c.Engines = "sepolia", map[string]string{"sepolia": "sepolia.yaml"}
+		}, `engines: engine name "sepolia" is the node's own`},
+		"engine file": {func(c *config.Config) { c.Engines = map[string]string{"holesky": ""} }, `engines: config file required for engine "holesky"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := validConfig()
+			test.change(&c)
+			err := c.Validate()
+			require.ErrorIs(t, err, config.ErrInvalidConfig)
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+
+	c := validConfig()
+	c.Admin.Addr, c.Admin.Token = "", ""
+	require.NoError(t, c.Validate(), "the admin token is only required if the admin api is enabled")
+
+	c = validConfig()
+	c.NetworkName, c.Chaos.FailSettlementsPercent = "holesky", 50
+	require.NoError(t, c.Validate(), "fault injection off mainnet")
+
+	c = validConfig()
+	c.L1.RPCURL, c.Store.Backend = "", "redis"
+	require.EqualError(t, c.Validate(), "invalid config:\nl1.rpc-url: required\nstore.backend: unknown backend \"redis\"", "every error is reported in key order")
+}
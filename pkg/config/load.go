@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables override config file keys, e.g. AUCTIONEER_L1_RPC_URL for l1.rpc-url
+const EnvPrefix = "AUCTIONEER_"
+
+// Leaf config key, e.g. l1.rpc-url
+type Field struct {
+	Key string
+	// Pointer to the value: *string, *bool, *int, *uint64, *time.Duration, *[]string or *map[string]string
+	Value any
+}
+
+// Loads the defaults, overridden by the file at path in yaml (.yaml, .yml) or toml (.toml), then by the environment.
+// Unknown keys in the file are an error. The file is skipped if path is empty. The result isn't validated.
+func Load(path string) (Config, error) {
+	c, err := LoadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := c.ApplyEnv(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// Like Load, without environment overrides
+func LoadFile(path string) (Config, error) {
+	c := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := decode(path, data, &c); err != nil {
+			return Config{}, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, path, err)
+		}
+	}
+	return c, nil
+}
+
+// Loads the config of another chain's engine from its file, without environment overrides, which configure the
+// node's own chain. Only the chain's sections are read from the file, the signer, servers, TLS, logging, admin
+// and daemon sections are the node's. The result isn't validated.
+func LoadEngine(node Config, path string) (Config, error) {
+	c, err := LoadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	c.Signer, c.TLS, c.Transport, c.Log, c.Admin, c.Daemon = node.Signer, node.TLS, node.Transport, node.Log, node.Admin, node.Daemon
+	c.REST, c.JSONRPC, c.GRPC, c.GraphQL, c.Metrics = node.REST, node.JSONRPC, node.GRPC, node.GraphQL, node.Metrics
+	c.Engines = nil
+	return c, nil
+}
+
+func decode(path string, data []byte, c *Config) error {
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// An empty file is valid, and leaves the defaults
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), c)
+		if err != nil {
+			return err
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, key := range undecoded {
+				keys[i] = key.String()
+			}
+			sort.Strings(keys)
+			return fmt.Errorf("unknown keys %s", strings.Join(keys, ", "))
+		}
+	default:
+		return fmt.Errorf("unsupported format %q, expected .yaml, .yml or .toml", ext)
+	}
+	return nil
+}
+
+// Every leaf key, in declaration order
+func (c *Config) Fields() []Field {
+	return fields("", reflect.ValueOf(c).Elem())
+}
+
+func fields(prefix string, v reflect.Value) []Field {
+	var result []Field
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		key := prefix + name
+		if field := v.Field(i); field.Kind() == reflect.Struct {
+			result = append(result, fields(key+".", field)...)
+		} else {
+			result = append(result, Field{Key: key, Value: field.Addr().Interface()})
+		}
+	}
+	return result
+}
+
+// Environment variable overriding key, e.g. AUCTIONEER_L1_RPC_URL for l1.rpc-url
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Overrides keys set in the environment. Lists are comma separated, and maps comma separated k=v pairs.
+func (c *Config) ApplyEnv() error {
+	var errs []string
+	for _, field := range c.Fields() {
+		value, ok := os.LookupEnv(EnvName(field.Key))
+		if !ok {
+			continue
+		}
+		if err := field.Set(value); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", EnvName(field.Key), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Parses value as it's given in the environment
+func (f Field) Set(value string) error {
+	switch v := f.Value.(type) {
+	case *string:
+		*v = value
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*v = b
+	case *int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*v = i
+	case *uint64:
+		u, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*v = u
+	case *time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*v = d
+	case *[]string:
+		*v = splitList(value)
+	case *map[string]string:
+		m := make(map[string]string)
+		for _, pair := range splitList(value) {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("expected k=v pairs, got %q", pair)
+			}
+			m[strings.TrimSpace(k)] = strings.TrimSpace(val)
+		}
+		*v = m
+	default:
+		return fmt.Errorf("unsupported type %T", f.Value)
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,150 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const yamlConfig = `
+l1:
+  rpc-url: http://localhost:8545
+auction:
+  period: 3s
+  allowlist:
+    - "0x0000000000000000000000000000000000000001"
+store:
+  backend: sqlite
+  path: history.db
+log:
+  modules:
+    listener: debug
+recovery:
+  from-block: 100
+`
+
+const tomlConfig = `
+[l1]
+rpc-url = "http://localhost:8545"
+
+[auction]
+period = "3s"
+allowlist = ["0x0000000000000000000000000000000000000001"]
+
+[store]
+backend = "sqlite"
+path = "history.db"
+
+[log.modules]
+listener = "debug"
+
+[recovery]
+from-block = 100
+`
+
+func TestLoad(t *testing.T) {
+	for _, path := range []string{writeFile(t, "node.yaml", yamlConfig), writeFile(t, "node.yml", yamlConfig), writeFile(t, "node.toml", tomlConfig)} {
+		t.Run(filepath.Ext(path), func(t *testing.T) {
+			c, err := config.Load(path)
+			require.NoError(t, err)
+			require.Equal(t, "http://localhost:8545", c.L1.RPCURL)
+			require.Equal(t, 3*time.Second, c.Auction.Period)
+			require.Equal(t, []string{"0x0000000000000000000000000000000000000001"}, c.Auction.Allowlist)
+			require.Equal(t, config.StoreConfig{Backend: "sqlite", Path: "history.db"}, c.Store)
+			require.Equal(t, map[string]string{"listener": "debug"}, c.Log.Modules)
+			require.Equal(t, uint64(100), c.Recovery.FromBlock)
+			require.Equal(t, config.Default().L1.PollInterval, c.L1.PollInterval, "unset keys keep their defaults")
+		})
+	}
+
+	c, err := config.Load("")
+	require.NoError(t, err)
+	require.Equal(t, config.Default(), c)
+	c, err = config.Load(writeFile(t, "empty.yaml", ""))
+	require.NoError(t, err)
+	require.Equal(t, config.Default(), c)
+
+	for name, path := range map[string]string{
+		"unknown yaml key":   writeFile(t, "node.yaml", "l1:\n  rpc: http://localhost:8545\n"),
+		"unknown toml key":   writeFile(t, "node.toml", "[l1]\nrpc = \"http://localhost:8545\"\n"),
+		"invalid duration":   writeFile(t, "node.yaml", "auction:\n  period: soon\n"),
+		"unsupported format": writeFile(t, "node.json", "{}"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.Load(path)
+			require.ErrorIs(t, err, config.ErrInvalidConfig)
+		})
+	}
+	_, err = config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestLoadEngine(t *testing.T) {
+	t.Setenv("AUCTIONEER_L1_RPC_URL", "http://env:8545")
+	node, err := config.Load("")
+	require.NoError(t, err)
+	node.REST.Addr, node.Engines = ":9000", map[string]string{"holesky": "holesky.yaml"}
+	node.Transport.MessageRate = 5
+
+	c, err := config.LoadEngine(node, writeFile(t, "holesky.yaml", yamlConfig+"rest:\n  addr: \":9999\"\n"))
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:8545", c.L1.RPCURL, "the environment configures the node's own chain")
+	require.Equal(t, 3*time.Second, c.Auction.Period)
+	require.Equal(t, ":9000", c.REST.Addr, "servers are shared")
+	require.Equal(t, node.Signer, c.Signer)
+	require.Equal(t, node.Transport, c.Transport, "transport limits are the shared servers'")
+	require.Nil(t, c.Engines)
+
+	_, err = config.LoadEngine(node, writeFile(t, "holesky.yaml", "l1:\n  rpc: http://localhost:8545\n"))
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+}
+
+func TestApplyEnv(t *testing.T) {
+	require.Equal(t, "AUCTIONEER_L1_RPC_URL", config.EnvName("l1.rpc-url"))
+
+	t.Setenv("AUCTIONEER_L1_RPC_URL", "http://env:8545")
+	t.Setenv("AUCTIONEER_AUCTION_PERIOD", "2s")
+	t.Setenv("AUCTIONEER_AUCTION_REQUIRE_AUTH", "true")
+	t.Setenv("AUCTIONEER_REGISTRY_RELAYS", "0x0000000000000000000000000000000000000001, 0x0000000000000000000000000000000000000002")
+	t.Setenv("AUCTIONEER_LOG_MODULES", "listener=debug,rest=warn")
+	t.Setenv("AUCTIONEER_EVENT_BUFFER_SIZE", "10")
+	c, err := config.Load(writeFile(t, "node.yaml", yamlConfig))
+	require.NoError(t, err)
+	require.Equal(t, "http://env:8545", c.L1.RPCURL, "the environment takes precedence over the file")
+	require.Equal(t, 2*time.Second, c.Auction.Period)
+	require.True(t, c.Auction.RequireAuth)
+	require.Equal(t, []string{"0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002"}, c.Registry.Relays)
+	require.Equal(t, map[string]string{"listener": "debug", "rest": "warn"}, c.Log.Modules)
+	require.Equal(t, 10, c.Event.BufferSize)
+	require.Equal(t, "history.db", c.Store.Path)
+
+	t.Setenv("AUCTIONEER_EVENT_BUFFER_SIZE", "lots")
+	_, err = config.Load("")
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+	require.ErrorContains(t, err, "AUCTIONEER_EVENT_BUFFER_SIZE")
+}
+
+func TestFields(t *testing.T) {
+	c := config.Default()
+	fields := c.Fields()
+	require.Equal(t, "l1.rpc-url", fields[0].Key)
+	require.NoError(t, fields[0].Set("http://localhost:8545"))
+	require.Equal(t, "http://localhost:8545", c.L1.RPCURL, "fields point into the config")
+	keys := make(map[string]bool)
+	for _, field := range fields {
+		require.False(t, keys[field.Key], "duplicate key %s", field.Key)
+		keys[field.Key] = true
+	}
+	require.True(t, keys["tls.require-client-cert"])
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Chain parameters of a network
+type Network struct {
+	ChainID  uint64
+	SlotTime time.Duration
+	// Beacon chain genesis, slots start every SlotTime from it. Zero if unknown, e.g. for local dev chains.
+	GenesisTime      time.Time
+	MaxBlobsPerBlock int
+	// Relay registry on the settlement layer, zero until deployed
+	RegistryContract common.Address
+}
+
+// Presets selected with the network key. Public networks have the blob limit of their latest blob parameter fork,
+// and local matches the dev chains of the bundled go-ethereum (chain ID is anvil's, override it for geth --dev).
+var networks = map[string]Network{
+	"mainnet": {ChainID: 1, SlotTime: 12 * time.Second, GenesisTime: time.Unix(1606824023, 0), MaxBlobsPerBlock: 21},
+	"holesky": {ChainID: 17000, SlotTime: 12 * time.Second, GenesisTime: time.Unix(1695902400, 0), MaxBlobsPerBlock: 21},
+	"sepolia": {ChainID: 11155111, SlotTime: 12 * time.Second, GenesisTime: time.Unix(1655733600, 0), MaxBlobsPerBlock: 21},
+	"local":   {ChainID: 31337, SlotTime: 12 * time.Second, MaxBlobsPerBlock: params.MaxBlobGasPerBlock / params.BlobTxBlobGasPerBlob},
+}
+
+// Names of the network presets, sorted
+func Networks() []string {
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the named preset
+func NetworkPreset(name string) (Network, bool) {
+	n, ok := networks[name]
+	return n, ok
+}
+
+// Chain parameters of the network preset, overridden by the chain keys that are set.
+// Without a preset (network empty, or unknown), only the chain keys apply.
+func (c Config) Network() Network {
+	n := networks[c.NetworkName]
+	if c.Chain.ChainID != 0 {
+		n.ChainID = c.Chain.ChainID
+	}
+	if c.Chain.SlotTime != 0 {
+		n.SlotTime = c.Chain.SlotTime
+	}
+	if c.Chain.GenesisTime != 0 {
+		n.GenesisTime = time.Unix(int64(c.Chain.GenesisTime), 0)
+	}
+	if c.Chain.MaxBlobsPerBlock != 0 {
+		n.MaxBlobsPerBlock = c.Chain.MaxBlobsPerBlock
+	}
+	if c.Chain.RegistryContract != "" {
+		n.RegistryContract = common.HexToAddress(c.Chain.RegistryContract)
+	}
+	return n
+}
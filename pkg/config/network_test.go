@@ -0,0 +1,45 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetwork(t *testing.T) {
+	require.Equal(t, []string{"holesky", "local", "mainnet", "sepolia"}, config.Networks())
+	c := validConfig()
+	require.Equal(t, uint64(1), c.Network().ChainID, "mainnet by default")
+
+	c.NetworkName = "sepolia"
+	c.Chain.SlotTime = 6 * time.Second
+	c.Chain.RegistryContract = "0x0000000000000000000000000000000000000001"
+	sepolia, ok := config.NetworkPreset("sepolia")
+	require.True(t, ok)
+	require.Equal(t, config.Network{
+		ChainID:          11155111,
+		SlotTime:         6 * time.Second,
+		GenesisTime:      time.Unix(1655733600, 0),
+		MaxBlobsPerBlock: sepolia.MaxBlobsPerBlock,
+		RegistryContract: common.HexToAddress("0x01"),
+	}, c.Network(), "chain keys override the preset field by field")
+	require.NoError(t, c.Validate())
+
+	c = validConfig()
+	c.NetworkName = ""
+	require.ErrorContains(t, c.Validate(), "chain.chain-id: required without a network preset")
+	c.Chain = config.ChainConfig{ChainID: 1337, SlotTime: 2 * time.Second, MaxBlobsPerBlock: 6}
+	c.Auction.Period = time.Second
+	require.NoError(t, c.Validate(), "custom networks set every chain key")
+
+	c = validConfig()
+	c.NetworkName = "goerli"
+	require.ErrorContains(t, c.Validate(), `network: unknown network "goerli", expected one of holesky, local, mainnet, sepolia`)
+	c = validConfig()
+	c.Auction.Period = 12 * time.Second
+	require.ErrorContains(t, c.Validate(), "auction.period: must be shorter than the 12s slot time")
+}
@@ -0,0 +1,242 @@
+package crosscheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// States of this many most recent auctions are kept for comparison, enough to span gossip latency
+const retention = 64
+
+// Replica's state hash for an auction, as exchanged between replicas
+type State struct {
+	Replica string      `json:"replica"`
+	L1Block uint64      `json:"l1Block"`
+	Hash    common.Hash `json:"hash"`
+}
+
+// Hashes an auction's bid set, winner and commitments targeting its block. Independent of the order bids and
+// commitments are given in, so replicas that saw the same bids and issued the same commitments agree.
+func Hash(l1Block uint64, bids []auction.SignedBid, winner *auction.SignedBid, commitments []commitment.Commitment) common.Hash {
+	bidHashes := make([][]byte, 0, len(bids))
+	for _, bid := range bids {
+		bidHashes = append(bidHashes, bidHash(bid))
+	}
+	sort.Slice(bidHashes, func(i, j int) bool { return bytes.Compare(bidHashes[i], bidHashes[j]) < 0 })
+	commitmentHashes := make([]common.Hash, 0, len(commitments))
+	for _, c := range commitments {
+		commitmentHashes = append(commitmentHashes, c.Hash())
+	}
+	sort.Slice(commitmentHashes, func(i, j int) bool { return commitmentHashes[i].Cmp(commitmentHashes[j]) < 0 })
+
+	data := binary.BigEndian.AppendUint64(nil, l1Block)
+	data = binary.BigEndian.AppendUint64(data, uint64(len(bidHashes)))
+	for _, h := range bidHashes {
+		data = append(data, h...)
+	}
+	if winner != nil {
+		data = append(data, bidHash(*winner)...)
+	} else {
+		data = append(data, common.Hash{}.Bytes()...)
+	}
+	data = binary.BigEndian.AppendUint64(data, uint64(len(commitmentHashes)))
+	for _, h := range commitmentHashes {
+		data = append(data, h.Bytes()...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+func bidHash(bid auction.SignedBid) []byte {
+	data := append(bid.Address.Bytes(), common.BigToHash(bid.AmountWei).Bytes()...)
+	data = append(data, bid.Signature...)
+	return crypto.Keccak256(data)
+}
+
+// Satisfied by *gossip.Gossip
+type Publisher interface {
+	PublishState(ctx context.Context, state State) error
+}
+
+// Satisfied by *listener.Listener
+type Rankings interface {
+	Ranking(l1Block uint64) []auction.SignedBid
+}
+
+// Satisfied by *commitment.Coordinator
+type Commitments interface {
+	ForBlock(targetBlock *big.Int) []commitment.Commitment
+}
+
+// Satisfied by *alerting.Notifier
+type Alerter interface {
+	StateDiverged(l1Block uint64, replica string, local common.Hash, remote common.Hash)
+}
+
+type Divergence struct {
+	L1Block uint64      `json:"l1Block"`
+	Replica string      `json:"replica"`
+	Local   common.Hash `json:"local"`
+	Remote  common.Hash `json:"remote"`
+	At      time.Time   `json:"at"`
+}
+
+// Cross-checks so far, e.g. for diagnostics
+type Status struct {
+	// Comparisons with other replicas' states, one per replica and auction
+	Compared uint64 `json:"compared"`
+	Diverged uint64 `json:"diverged"`
+	// Latest divergence, nil if none
+	Last *Divergence `json:"last,omitempty"`
+}
+
+// Exchanges this replica's state hash for each auction with the other replicas, and compares theirs with it,
+// alerting on divergence. Satisfies gossip.StateReceiver.
+type Checker struct {
+	logger      *slog.Logger
+	replica     string
+	publisher   Publisher
+	rankings    Rankings
+	commitments Commitments
+	alerter     Alerter
+
+	mu     sync.Mutex // Protects access to fields below
+	local  map[uint64]common.Hash
+	remote map[uint64]map[string]common.Hash
+	status Status
+}
+
+// replica is this replica's unique ID, its states are published with. Auctions' bids and commitments are read
+// from rankings and commitments.
+func NewChecker(logger *slog.Logger, replica string, publisher Publisher, rankings Rankings, commitments Commitments) *Checker {
+	return &Checker{
+		logger:      logger,
+		replica:     replica,
+		publisher:   publisher,
+		rankings:    rankings,
+		commitments: commitments,
+		local:       make(map[uint64]common.Hash),
+		remote:      make(map[uint64]map[string]common.Hash),
+	}
+}
+
+// Divergences are alerted on, if set before any state is recorded
+func (c *Checker) SetAlerter(alerter Alerter) {
+	c.alerter = alerter
+}
+
+// Records the state of each auction as it closes, from the listener's event feed (see Listener.SubscribeEvents),
+// until ctx is done or events is closed
+func (c *Checker) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type != auction.EventAuctionClosed {
+				continue
+			}
+			l1Block := ev.L1Block.Uint64()
+			c.Record(l1Block, Hash(l1Block, c.rankings.Ranking(l1Block), ev.Bid, c.commitments.ForBlock(ev.L1Block)))
+		}
+	}
+}
+
+// Records this replica's state hash for an auction, publishes it to the other replicas, and compares it with
+// those already received
+func (c *Checker) Record(l1Block uint64, hash common.Hash) {
+	c.mu.Lock()
+	c.local[l1Block] = hash
+	c.prune(l1Block)
+	var divergences []Divergence
+	for replica, remote := range c.remote[l1Block] {
+		if d, diverged := c.compare(l1Block, replica, hash, remote); diverged {
+			divergences = append(divergences, d)
+		}
+	}
+	c.mu.Unlock()
+	c.alert(divergences...)
+
+	state := State{Replica: c.replica, L1Block: l1Block, Hash: hash}
+	if err := c.publisher.PublishState(context.Background(), state); err != nil {
+		c.logger.Warn("failed to publish state hash", "blockNumber", l1Block, "error", err)
+	}
+}
+
+// To satisfy gossip.StateReceiver. Compared once this replica recorded its own state for the auction.
+func (c *Checker) ReceiveState(state State) {
+	if state.Replica == c.replica {
+		return
+	}
+	c.mu.Lock()
+	if c.remote[state.L1Block] == nil {
+		c.remote[state.L1Block] = make(map[string]common.Hash)
+	}
+	c.remote[state.L1Block][state.Replica] = state.Hash
+	local, recorded := c.local[state.L1Block]
+	var d Divergence
+	var diverged bool
+	if recorded {
+		d, diverged = c.compare(state.L1Block, state.Replica, local, state.Hash)
+	}
+	c.mu.Unlock()
+	if diverged {
+		c.alert(d)
+	}
+}
+
+func (c *Checker) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// Must be called with mu held
+func (c *Checker) compare(l1Block uint64, replica string, local common.Hash, remote common.Hash) (Divergence, bool) {
+	c.status.Compared++
+	if local == remote {
+		return Divergence{}, false
+	}
+	d := Divergence{L1Block: l1Block, Replica: replica, Local: local, Remote: remote, At: time.Now()}
+	c.status.Diverged++
+	c.status.Last = &d
+	return d, true
+}
+
+func (c *Checker) alert(divergences ...Divergence) {
+	for _, d := range divergences {
+		c.logger.Error("replica state diverged", "blockNumber", d.L1Block, "replica", d.Replica, "local", d.Local, "remote", d.Remote)
+		if c.alerter != nil {
+			c.alerter.StateDiverged(d.L1Block, d.Replica, d.Local, d.Remote)
+		}
+	}
+}
+
+// Must be called with mu held. States received for blocks well ahead of the latest recorded are dropped too,
+// so a faulty replica can't grow them unbounded.
+func (c *Checker) prune(latest uint64) {
+	for block := range c.local {
+		if block+retention <= latest {
+			delete(c.local, block)
+		}
+	}
+	for block := range c.remote {
+		if block+retention <= latest || block > latest+retention {
+			delete(c.remote, block)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package crosscheck_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/crosscheck"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPublisher struct {
+	mu        sync.Mutex
+	published []crosscheck.State
+}
+
+func (m *mockPublisher) PublishState(ctx context.Context, state crosscheck.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.published = append(m.published, state)
+	return nil
+}
+
+type mockAlerter struct {
+	diverged []string
+}
+
+func (m *mockAlerter) StateDiverged(l1Block uint64, replica string, local common.Hash, remote common.Hash) {
+	m.diverged = append(m.diverged, replica)
+}
+
+type mockRankings map[uint64][]auction.SignedBid
+
+func (m mockRankings) Ranking(l1Block uint64) []auction.SignedBid {
+	return m[l1Block]
+}
+
+type mockCommitments map[uint64][]commitment.Commitment
+
+func (m mockCommitments) ForBlock(targetBlock *big.Int) []commitment.Commitment {
+	return m[targetBlock.Uint64()]
+}
+
+func TestHash(t *testing.T) {
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	bid1 := *auction.MustCreateSignedBid(big.NewInt(10), big.NewInt(100), pk1)
+	bid2 := *auction.MustCreateSignedBid(big.NewInt(20), big.NewInt(100), pk2)
+	c1, err := commitment.CreateSignedCommitment(commitment.Commitment{TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(101), FeeWei: big.NewInt(1)}, pk2)
+	require.NoError(t, err)
+	c2, err := commitment.CreateSignedCommitment(commitment.Commitment{TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(102), FeeWei: big.NewInt(1)}, pk2)
+	require.NoError(t, err)
+
+	hash := crosscheck.Hash(100, []auction.SignedBid{bid2, bid1}, &bid2, []commitment.Commitment{*c1, *c2})
+	require.Equal(t, hash, crosscheck.Hash(100, []auction.SignedBid{bid1, bid2}, &bid2, []commitment.Commitment{*c2, *c1}), "independent of order")
+	require.NotEqual(t, hash, crosscheck.Hash(101, []auction.SignedBid{bid2, bid1}, &bid2, []commitment.Commitment{*c1, *c2}), "block differs")
+	require.NotEqual(t, hash, crosscheck.Hash(100, []auction.SignedBid{bid2}, &bid2, []commitment.Commitment{*c1, *c2}), "bid missing")
+	require.NotEqual(t, hash, crosscheck.Hash(100, []auction.SignedBid{bid2, bid1}, &bid1, []commitment.Commitment{*c1, *c2}), "winner differs")
+	require.NotEqual(t, hash, crosscheck.Hash(100, []auction.SignedBid{bid2, bid1}, nil, []commitment.Commitment{*c1, *c2}), "no winner")
+	require.NotEqual(t, hash, crosscheck.Hash(100, []auction.SignedBid{bid2, bid1}, &bid2, []commitment.Commitment{*c1}), "commitment missing")
+}
+
+func TestChecker(t *testing.T) {
+	publisher, alerter := &mockPublisher{}, &mockAlerter{}
+	c := crosscheck.NewChecker(slog.Default(), "a", publisher, nil, nil)
+	c.SetAlerter(alerter)
+	agreed, diverged := common.HexToHash("0x01"), common.HexToHash("0x02")
+
+	// Received before this replica closed the auction, compared once it records its own state
+	c.ReceiveState(crosscheck.State{Replica: "b", L1Block: 100, Hash: agreed})
+	c.ReceiveState(crosscheck.State{Replica: "c", L1Block: 100, Hash: diverged})
+	require.Zero(t, c.Status().Compared)
+	c.Record(100, agreed)
+	require.Equal(t, []crosscheck.State{{Replica: "a", L1Block: 100, Hash: agreed}}, publisher.published)
+	require.Equal(t, []string{"c"}, alerter.diverged)
+
+	// Received after
+	c.ReceiveState(crosscheck.State{Replica: "d", L1Block: 100, Hash: diverged})
+	c.ReceiveState(crosscheck.State{Replica: "a", L1Block: 100, Hash: diverged})
+	require.Equal(t, []string{"c", "d"}, alerter.diverged, "own states are ignored")
+
+	status := c.Status()
+	require.Equal(t, uint64(3), status.Compared)
+	require.Equal(t, uint64(2), status.Diverged)
+	require.Equal(t, "d", status.Last.Replica)
+	require.Equal(t, agreed, status.Last.Local)
+	require.Equal(t, diverged, status.Last.Remote)
+}
+
+func TestWatch(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	winner := *auction.MustCreateSignedBid(big.NewInt(10), big.NewInt(100), pk)
+	c1, err := commitment.CreateSignedCommitment(commitment.Commitment{TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(101), FeeWei: big.NewInt(1)}, pk)
+	require.NoError(t, err)
+	rankings := mockRankings{100: {winner}}
+	commitments := mockCommitments{100: {*c1}}
+	publisher := &mockPublisher{}
+	c := crosscheck.NewChecker(slog.Default(), "a", publisher, rankings, commitments)
+
+	events := make(chan auction.Event, 3)
+	events <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)}
+	events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: &winner}
+	events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(101)}
+	close(events)
+	c.Watch(context.Background(), events)
+
+	require.Equal(t, []crosscheck.State{
+		{Replica: "a", L1Block: 100, Hash: crosscheck.Hash(100, []auction.SignedBid{winner}, &winner, []commitment.Commitment{*c1})},
+		{Replica: "a", L1Block: 101, Hash: crosscheck.Hash(101, nil, nil, nil)},
+	}, publisher.published, "states are recorded as auctions close, with or without a winner")
+}
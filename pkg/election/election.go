@@ -0,0 +1,170 @@
+package election
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Per lease call
+const queryTimeout = 5 * time.Second
+
+// Lease replicas contend for, satisfied by *SQLLease and *MemoryLease
+type Lease interface {
+	// Takes the lease for ttl if it's free or expired, or renews it if holder already holds it. Returns false if
+	// another replica holds it.
+	Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	// Gives the lease up if holder holds it, so another replica takes over without waiting for it to expire
+	Release(ctx context.Context, name string, holder string) error
+	// Current holder, false if the lease is free or expired
+	Holder(ctx context.Context, name string) (Holder, bool, error)
+}
+
+type Config struct {
+	// Unique ID of this replica, e.g. its hostname
+	ID string
+	// Lease the replicas of one auctioneer contend for, e.g. its chain
+	Name string
+	// How long the lease is held without renewal, renewed every third of it. 4s if 0.
+	TTL time.Duration
+}
+
+// Told when this replica gains or loses leadership, from the elector's goroutine
+type Observer interface {
+	LeadershipChanged(leader bool)
+}
+
+// Leadership of this replica, e.g. for diagnostics
+type Status struct {
+	ID     string `json:"id"`
+	Leader bool   `json:"leader"`
+	// Replica currently holding the lease, nil if none or it couldn't be read
+	Holder *Holder `json:"holder,omitempty"`
+	// Last failure to acquire or renew the lease
+	Error string `json:"error,omitempty"`
+}
+
+// Elects one leader among replicas by lease: the replica holding it leads until it fails to renew it, then another
+// takes over once it expires. A leader only counts itself as such until its lease would expire counting from when
+// it last asked for it, so two replicas never both count themselves leaders as long as clocks are in sync.
+type Elector struct {
+	logger   *slog.Logger
+	config   Config
+	lease    Lease
+	observer Observer
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu          sync.Mutex // Protects access to fields below
+	leaderUntil time.Time
+	leader      bool
+	err         error
+}
+
+func NewElector(logger *slog.Logger, config Config, lease Lease) *Elector {
+	if config.TTL == 0 {
+		config.TTL = 4 * time.Second
+	}
+	return &Elector{logger: logger, config: config, lease: lease, done: make(chan struct{})}
+}
+
+// Leadership changes are reported to the observer, if set before the elector starts
+func (e *Elector) SetObserver(observer Observer) {
+	e.observer = observer
+}
+
+// Campaigns for the lease in the background until Stop
+func (e *Elector) Start(ctx context.Context) {
+	ctx, e.cancel = context.WithCancel(ctx)
+	go func() {
+		defer close(e.done)
+		ticker := time.NewTicker(e.config.TTL / 3)
+		defer ticker.Stop()
+		for {
+			e.campaign(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (e *Elector) campaign(ctx context.Context) {
+	askedAt := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, min(queryTimeout, e.config.TTL/3))
+	acquired, err := e.lease.Acquire(ctx, e.config.Name, e.config.ID, e.config.TTL)
+	cancel()
+	e.mu.Lock()
+	e.err = err
+	switch {
+	case err != nil:
+		// Leads on until the lease it holds expires, unless renewed in time
+		e.logger.Warn("failed to renew leadership lease", "error", err)
+	case acquired:
+		e.leaderUntil = askedAt.Add(e.config.TTL)
+	default:
+		e.leaderUntil = time.Time{}
+	}
+	leader := time.Now().Before(e.leaderUntil)
+	changed := leader != e.leader
+	e.leader = leader
+	e.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if leader {
+		e.logger.Info("elected leader", "id", e.config.ID)
+	} else {
+		e.logger.Warn("lost leadership", "id", e.config.ID)
+	}
+	if e.observer != nil {
+		e.observer.LeadershipChanged(leader)
+	}
+}
+
+// Whether this replica leads, until its lease would expire
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().Before(e.leaderUntil)
+}
+
+func (e *Elector) Status() Status {
+	status := Status{ID: e.config.ID, Leader: e.IsLeader()}
+	e.mu.Lock()
+	if e.err != nil {
+		status.Error = e.err.Error()
+	}
+	e.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	if holder, ok, err := e.lease.Holder(ctx, e.config.Name); err == nil && ok {
+		status.Holder = &holder
+	}
+	return status
+}
+
+// Stops campaigning and releases the lease if held, handing over to another replica right away
+func (e *Elector) Stop() {
+	if e.cancel == nil {
+		return
+	}
+	e.cancel()
+	<-e.done
+	e.mu.Lock()
+	wasLeader := time.Now().Before(e.leaderUntil)
+	e.leaderUntil, e.leader = time.Time{}, false
+	e.mu.Unlock()
+	if !wasLeader {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	if err := e.lease.Release(ctx, e.config.Name, e.config.ID); err != nil {
+		e.logger.Warn("failed to release leadership lease", "error", err)
+	}
+}
@@ -0,0 +1,89 @@
+package election_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/election"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockObserver struct {
+	mu      sync.Mutex
+	changes []bool
+}
+
+func (m *mockObserver) LeadershipChanged(leader bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.changes = append(m.changes, leader)
+}
+
+func (m *mockObserver) get() []bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]bool(nil), m.changes...)
+}
+
+func TestOneLeaderAndHandOver(t *testing.T) {
+	lease := election.NewMemoryLease()
+	first := election.NewElector(slog.Default(), election.Config{ID: "a", Name: "holesky", TTL: 300 * time.Millisecond}, lease)
+	observer := &mockObserver{}
+	first.SetObserver(observer)
+	first.Start(context.Background())
+	require.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+
+	second := election.NewElector(slog.Default(), election.Config{ID: "b", Name: "holesky", TTL: 300 * time.Millisecond}, lease)
+	second.Start(context.Background())
+	defer second.Stop()
+	time.Sleep(400 * time.Millisecond)
+	require.True(t, first.IsLeader())
+	require.False(t, second.IsLeader(), "the lease is renewed")
+	require.Equal(t, "a", second.Status().Holder.ID)
+
+	first.Stop()
+	require.False(t, first.IsLeader())
+	require.Eventually(t, second.IsLeader, 200*time.Millisecond, 10*time.Millisecond, "the released lease is taken over right away")
+	require.Equal(t, []bool{true}, observer.get())
+}
+
+// Fails every call once broken
+type flakyLease struct {
+	*election.MemoryLease
+	mu     sync.Mutex
+	broken bool
+}
+
+func (f *flakyLease) Acquire(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.broken {
+		return false, errors.New("connection refused")
+	}
+	return f.MemoryLease.Acquire(ctx, name, holder, ttl)
+}
+
+func TestLeadershipExpiresWithoutRenewal(t *testing.T) {
+	lease := &flakyLease{MemoryLease: election.NewMemoryLease()}
+	e := election.NewElector(slog.Default(), election.Config{ID: "a", Name: "holesky", TTL: 300 * time.Millisecond}, lease)
+	observer := &mockObserver{}
+	e.SetObserver(observer)
+	e.Start(context.Background())
+	defer e.Stop()
+	require.Eventually(t, e.IsLeader, time.Second, 10*time.Millisecond)
+
+	lease.mu.Lock()
+	lease.broken = true
+	lease.mu.Unlock()
+	brokenAt := time.Now()
+	require.Eventually(t, func() bool { return !e.IsLeader() }, time.Second, 10*time.Millisecond)
+	require.Less(t, time.Since(brokenAt), 350*time.Millisecond, "no longer leads once the lease would expire")
+	require.Equal(t, "connection refused", e.Status().Error)
+	require.Eventually(t, func() bool { return len(observer.get()) == 2 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, []bool{true, false}, observer.get())
+}
@@ -0,0 +1,56 @@
+package election_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/election"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteLease(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "leases.db")
+	lease, err := election.NewSQLiteLease(ctx, path)
+	require.NoError(t, err)
+	defer lease.Close()
+	// Another replica on the same host
+	other, err := election.NewSQLiteLease(ctx, path)
+	require.NoError(t, err)
+	defer other.Close()
+
+	_, held, err := lease.Holder(ctx, "holesky")
+	require.NoError(t, err)
+	require.False(t, held)
+	acquired, err := lease.Acquire(ctx, "holesky", "a", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	acquired, err = other.Acquire(ctx, "holesky", "b", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.False(t, acquired, "held by a")
+	acquired, err = other.Acquire(ctx, "sepolia", "b", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired, "leases are by name")
+	acquired, err = lease.Acquire(ctx, "holesky", "a", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired, "renewed")
+	holder, held, err := other.Holder(ctx, "holesky")
+	require.NoError(t, err)
+	require.True(t, held)
+	require.Equal(t, "a", holder.ID)
+
+	time.Sleep(250 * time.Millisecond)
+	acquired, err = other.Acquire(ctx, "holesky", "b", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired, "taken over once expired")
+	require.NoError(t, lease.Release(ctx, "holesky", "a"), "releasing a lease no longer held is a no-op")
+	holder, _, _ = lease.Holder(ctx, "holesky")
+	require.Equal(t, "b", holder.ID)
+	require.NoError(t, other.Release(ctx, "holesky", "b"))
+	acquired, err = lease.Acquire(ctx, "holesky", "a", 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired, "free once released")
+}
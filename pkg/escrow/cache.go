@@ -0,0 +1,64 @@
+package escrow
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type cachedBalance struct {
+	balance   Balance
+	err       error
+	fetchedAt time.Time
+}
+
+// Cached view of the ledger's balances, to check bids against on submission without listing history for each bid.
+// Balances are up to ttl stale, so bids may still exceed a relay's backing at settlement.
+type Cache struct {
+	ledger *Ledger
+	ttl    time.Duration
+
+	mu       sync.Mutex // Protects access to balances
+	balances map[common.Address]cachedBalance
+}
+
+func NewCache(ledger *Ledger, ttl time.Duration) *Cache {
+	return &Cache{ledger: ledger, ttl: ttl, balances: make(map[common.Address]cachedBalance)}
+}
+
+// Whether relay's balance backs a bid of amountWei, false if it isn't bonded. Fails if its balance can't be read.
+func (c *Cache) Covers(relay common.Address, amountWei *big.Int) (bool, error) {
+	balance, err := c.Balance(relay)
+	if errors.Is(err, ErrNotBonded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return amountWei.Cmp(balance.MaxBidWei) <= 0, nil
+}
+
+// Relay's balance as of at most ttl ago
+func (c *Cache) Balance(relay common.Address) (Balance, error) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.balances[relay]; ok && now.Sub(cached.fetchedAt) < c.ttl {
+		return cached.balance, cached.err
+	}
+	balance, err := c.ledger.Balance(relay)
+	// Failures reading history aren't cached, unlike missing bonds
+	if err != nil && !errors.Is(err, ErrNotBonded) {
+		return Balance{}, err
+	}
+	c.balances[relay] = cachedBalance{balance: balance, err: err, fetchedAt: now}
+	for address, cached := range c.balances {
+		if now.Sub(cached.fetchedAt) >= c.ttl {
+			delete(c.balances, address)
+		}
+	}
+	return balance, err
+}
@@ -0,0 +1,42 @@
+package escrow_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheCovers(t *testing.T) {
+	relayKey, _ := crypto.GenerateKey()
+	relay := crypto.PubkeyToAddress(relayKey.PublicKey)
+	history := store.NewMemoryStore()
+	cache := escrow.NewCache(escrow.NewLedger(mockBonds{relay: big.NewInt(1000)}, history), 100*time.Millisecond)
+
+	covered, err := cache.Covers(relay, big.NewInt(1000))
+	require.NoError(t, err)
+	require.True(t, covered)
+	covered, err = cache.Covers(relay, big.NewInt(1001))
+	require.NoError(t, err)
+	require.False(t, covered)
+	covered, err = cache.Covers(common.Address{0x01}, big.NewInt(1))
+	require.NoError(t, err)
+	require.False(t, covered, "unbonded relays back no bids")
+
+	require.NoError(t, history.SaveAuctionResult(100, auction.MustCreateSignedBid(big.NewInt(600), big.NewInt(100), relayKey), time.Now()))
+	covered, _ = cache.Covers(relay, big.NewInt(1000))
+	require.True(t, covered, "the balance is cached")
+	require.Eventually(t, func() bool {
+		covered, _ := cache.Covers(relay, big.NewInt(1000))
+		return !covered
+	}, time.Second, 20*time.Millisecond, "the unsettled win is debited once the cache expires")
+	covered, _ = cache.Covers(relay, big.NewInt(400))
+	require.True(t, covered)
+}
@@ -0,0 +1,72 @@
+package escrow
+
+import (
+	"errors"
+	"math/big"
+
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrNotBonded = errors.New("relay not bonded")
+
+// Bonded balance of relays, e.g. *avs.Registry, nil if a relay isn't bonded
+type Bonds interface {
+	Stake(relay common.Address) *big.Int
+}
+
+// Won auctions awaiting settlement, e.g. a store.Store
+type History interface {
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+}
+
+// A relay's backing for its bids
+type Balance struct {
+	Relay      common.Address `json:"relay"`
+	BalanceWei *big.Int       `json:"balanceWei"`
+	// Winning bids not yet settled, which will be debited from the balance
+	PendingDebitsWei *big.Int `json:"pendingDebitsWei"`
+	UnsettledWins    int      `json:"unsettledWins"`
+	// Highest bid the balance still backs, the balance less pending debits, and never negative
+	MaxBidWei *big.Int `json:"maxBidWei"`
+}
+
+// Relays' escrow balances, from their bonds and the unsettled wins in history
+type Ledger struct {
+	bonds   Bonds
+	history History
+}
+
+func NewLedger(bonds Bonds, history History) *Ledger {
+	return &Ledger{bonds: bonds, history: history}
+}
+
+// Current balance of relay, ErrNotBonded if it has no bond
+func (l *Ledger) Balance(relay common.Address) (Balance, error) {
+	bond := l.bonds.Stake(relay)
+	if bond == nil {
+		return Balance{}, ErrNotBonded
+	}
+	balance := Balance{Relay: relay, BalanceWei: bond, PendingDebitsWei: new(big.Int)}
+	page := store.Page{Limit: store.MaxPageLimit}
+	for {
+		records, next, err := l.history.ListAuctions(store.AuctionFilter{Winner: &relay, Unsettled: true}, page)
+		if err != nil {
+			return Balance{}, err
+		}
+		for _, record := range records {
+			balance.PendingDebitsWei.Add(balance.PendingDebitsWei, record.Winner.AmountWei)
+			balance.UnsettledWins++
+		}
+		if next == "" {
+			break
+		}
+		page.Cursor = next
+	}
+	balance.MaxBidWei = new(big.Int).Sub(bond, balance.PendingDebitsWei)
+	if balance.MaxBidWei.Sign() < 0 {
+		balance.MaxBidWei.SetInt64(0)
+	}
+	return balance, nil
+}
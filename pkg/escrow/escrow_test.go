@@ -0,0 +1,65 @@
+package escrow_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBonds map[common.Address]*big.Int
+
+func (m mockBonds) Stake(relay common.Address) *big.Int { return m[relay] }
+
+func TestBalance(t *testing.T) {
+	relayKey, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	relay := crypto.PubkeyToAddress(relayKey.PublicKey)
+	history := store.NewMemoryStore()
+	for block, win := range map[uint64]struct {
+		amount  int64
+		key     bool
+		settled bool
+	}{
+		100: {300, true, true},
+		101: {400, true, false},
+		102: {500, false, false},
+		103: {200, true, false},
+	} {
+		key := otherKey
+		if win.key {
+			key = relayKey
+		}
+		bid := auction.MustCreateSignedBid(big.NewInt(win.amount), new(big.Int).SetUint64(block), key)
+		require.NoError(t, history.SaveAuctionResult(block, bid, time.Now()))
+		if win.settled {
+			require.NoError(t, history.SaveSettlement(block, common.Hash{0x01}, time.Now()))
+		}
+	}
+	ledger := escrow.NewLedger(mockBonds{relay: big.NewInt(1000)}, history)
+
+	balance, err := ledger.Balance(relay)
+	require.NoError(t, err)
+	require.Equal(t, escrow.Balance{
+		Relay:            relay,
+		BalanceWei:       big.NewInt(1000),
+		PendingDebitsWei: big.NewInt(600),
+		UnsettledWins:    2,
+		MaxBidWei:        big.NewInt(400),
+	}, balance, "settled and others' wins aren't pending")
+
+	ledger = escrow.NewLedger(mockBonds{relay: big.NewInt(500)}, history)
+	balance, err = ledger.Balance(relay)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), balance.MaxBidWei.Int64(), "never negative")
+
+	_, err = ledger.Balance(crypto.PubkeyToAddress(otherKey.PublicKey))
+	require.ErrorIs(t, err, escrow.ErrNotBonded)
+}
@@ -0,0 +1,145 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Satisfied by *listener.Listener
+type Publisher interface {
+	PublishEvent(ev auction.Event)
+}
+
+// Prompts relays to top up their bond once their pending wins leave it running low, publishing a bondLow event
+// and capping their bids at what the bond still backs, so they learn why their bids fail before settlement does.
+// The cap lifts once the relay's wins settle or it tops up. Satisfies listener.BidCaps.
+type Prompter struct {
+	logger    *slog.Logger
+	ledger    *Ledger
+	publisher Publisher
+	// A relay's bond runs low once its max bid is below this percent of its balance
+	lowPercent uint64
+
+	mu sync.Mutex // Protects capped
+	// Max bids of relays whose bond runs low
+	capped map[common.Address]*big.Int
+}
+
+func NewPrompter(logger *slog.Logger, ledger *Ledger, lowPercent uint64, publisher Publisher) *Prompter {
+	return &Prompter{
+		logger:     logger,
+		ledger:     ledger,
+		publisher:  publisher,
+		lowPercent: lowPercent,
+		capped:     make(map[common.Address]*big.Int),
+	}
+}
+
+// Checks the bond of each relay whose pending wins change, until ctx is done or events is closed: winners as their
+// auction closes or falls back to them, and relays whose win settles, falls back to another relay or is refunded.
+// Capped relays are checked again as each auction opens, for top-ups.
+func (p *Prompter) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.L1Block == nil {
+				continue
+			}
+			switch ev.Type {
+			case auction.EventAuctionOpened:
+				p.mu.Lock()
+				relays := make([]common.Address, 0, len(p.capped))
+				for relay := range p.capped {
+					relays = append(relays, relay)
+				}
+				p.mu.Unlock()
+				for _, relay := range relays {
+					p.Check(relay, ev.L1Block.Uint64())
+				}
+			case auction.EventAuctionClosed, auction.EventSettlement, auction.EventSlotMissed:
+				if ev.Bid != nil {
+					p.Check(ev.Bid.Address, ev.L1Block.Uint64())
+				}
+			case auction.EventWinnerFallback:
+				if ev.Failed != nil {
+					p.Check(*ev.Failed, ev.L1Block.Uint64())
+				}
+				if ev.Bid != nil {
+					p.Check(ev.Bid.Address, ev.L1Block.Uint64())
+				}
+			}
+		}
+	}
+}
+
+// Caps or uncaps the relay's bids by its current balance, publishing the change as of the auction for l1Block.
+// Relays without a bond aren't capped, the registry doesn't bond them.
+func (p *Prompter) Check(relay common.Address, l1Block uint64) {
+	balance, err := p.ledger.Balance(relay)
+	if errors.Is(err, ErrNotBonded) {
+		p.uncap(relay)
+		return
+	}
+	if err != nil {
+		p.logger.Warn("failed to read escrow balance, not checking bond", "relay", relay, "error", err)
+		return
+	}
+	status := &auction.BondStatus{
+		Relay:            relay,
+		BalanceWei:       balance.BalanceWei,
+		PendingDebitsWei: balance.PendingDebitsWei,
+		UnsettledWins:    balance.UnsettledWins,
+	}
+	threshold := new(big.Int).Mul(balance.BalanceWei, new(big.Int).SetUint64(p.lowPercent))
+	low := balance.UnsettledWins > 0 && new(big.Int).Mul(balance.MaxBidWei, big.NewInt(100)).Cmp(threshold) < 0
+
+	p.mu.Lock()
+	previous, wasCapped := p.capped[relay]
+	if low {
+		p.capped[relay] = balance.MaxBidWei
+	} else {
+		delete(p.capped, relay)
+	}
+	p.mu.Unlock()
+
+	ev := auction.Event{L1Block: new(big.Int).SetUint64(l1Block), Timestamp: time.Now(), Bond: status}
+	switch {
+	case low && (!wasCapped || previous.Cmp(balance.MaxBidWei) != 0):
+		p.logger.Info("relay's bond running low, capping its bids", "relay", relay, "balance", balance.BalanceWei,
+			"pendingDebits", balance.PendingDebitsWei, "cap", balance.MaxBidWei)
+		status.CapWei = balance.MaxBidWei
+		ev.Type = auction.EventBondLow
+	case !low && wasCapped:
+		p.logger.Info("relay's bond restored, no longer capping its bids", "relay", relay, "balance", balance.BalanceWei)
+		ev.Type = auction.EventBondRestored
+	default:
+		return
+	}
+	p.publisher.PublishEvent(ev)
+}
+
+// To satisfy listener.BidCaps. The relay's max bid while its bond runs low, nil otherwise.
+func (p *Prompter) BidCap(relay common.Address) *big.Int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.capped[relay]
+}
+
+func (p *Prompter) uncap(relay common.Address) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.capped, relay)
+}
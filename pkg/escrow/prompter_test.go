@@ -0,0 +1,84 @@
+package escrow_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPublisher struct{ events chan auction.Event }
+
+func (m mockPublisher) PublishEvent(ev auction.Event) { m.events <- ev }
+
+func TestPrompter(t *testing.T) {
+	relayKey, _ := crypto.GenerateKey()
+	relay := crypto.PubkeyToAddress(relayKey.PublicKey)
+	history := store.NewMemoryStore()
+	bonds := mockBonds{relay: big.NewInt(1000)}
+	published := make(chan auction.Event, 4)
+	prompter := escrow.NewPrompter(slog.Default(), escrow.NewLedger(bonds, history), 50, mockPublisher{published})
+
+	events := make(chan auction.Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go prompter.Watch(ctx, events)
+	win := func(block uint64, amount int64) *auction.SignedBid {
+		bid := auction.MustCreateSignedBid(big.NewInt(amount), new(big.Int).SetUint64(block), relayKey)
+		require.NoError(t, history.SaveAuctionResult(block, bid, time.Now()))
+		events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: new(big.Int).SetUint64(block), Bid: bid}
+		return bid
+	}
+	next := func() auction.Event {
+		select {
+		case ev := <-published:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("bond change not published")
+			return auction.Event{}
+		}
+	}
+
+	first := win(100, 400)
+	win(101, 200)
+	ev := next()
+	require.Equal(t, auction.EventBondLow, ev.Type, "the first win leaves 60%, the second 40%")
+	require.Equal(t, uint64(101), ev.L1Block.Uint64())
+	require.Equal(t, &auction.BondStatus{
+		Relay:            relay,
+		BalanceWei:       big.NewInt(1000),
+		PendingDebitsWei: big.NewInt(600),
+		UnsettledWins:    2,
+		CapWei:           big.NewInt(400),
+	}, ev.Bond)
+	require.Equal(t, big.NewInt(400), prompter.BidCap(relay))
+
+	require.NoError(t, history.SaveSettlement(100, common.Hash{0x01}, time.Now()))
+	events <- auction.Event{Type: auction.EventSettlement, L1Block: big.NewInt(100), Bid: first}
+	ev = next()
+	require.Equal(t, auction.EventBondRestored, ev.Type)
+	require.Nil(t, ev.Bond.CapWei)
+	require.Nil(t, prompter.BidCap(relay))
+
+	win(102, 500)
+	require.Equal(t, big.NewInt(300), next().Bond.CapWei)
+	bonds[relay] = big.NewInt(2000)
+	events <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(103)}
+	require.Equal(t, auction.EventBondRestored, next().Type, "capped relays are checked for top-ups")
+	require.Nil(t, prompter.BidCap(relay))
+
+	// Unbonded relays aren't capped
+	other := common.HexToAddress("0x02")
+	prompter.Check(other, 103)
+	require.Nil(t, prompter.BidCap(other))
+	require.Empty(t, published)
+}
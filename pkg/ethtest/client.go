@@ -0,0 +1,150 @@
+package ethtest
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Changes the client's behavior once applied
+type Step func(s *state)
+
+type state struct {
+	head    uint64
+	err     error
+	latency time.Duration
+}
+
+// Reports the given head block from then on
+func Head(block uint64) Step {
+	return func(s *state) { s.head = block }
+}
+
+// Fails every call with err from then on, until Recover
+func Fail(err error) Step {
+	return func(s *state) { s.err = err }
+}
+
+// Stops failing calls
+func Recover() Step {
+	return func(s *state) { s.err = nil }
+}
+
+// Delays every call by d from then on, or until the call's context is done
+func Latency(d time.Duration) Step {
+	return func(s *state) { s.latency = d }
+}
+
+// Steps applied once After has passed since the client was created
+type Event struct {
+	After time.Duration
+	Steps []Step
+}
+
+func At(after time.Duration, steps ...Step) Event {
+	return Event{After: after, Steps: steps}
+}
+
+// Result of a call to BlockNumber
+type Call struct {
+	At   time.Duration
+	Head uint64
+	Err  error
+}
+
+// EthClient whose block progression, errors and latency are scripted from a timeline, for unit testing
+// components that poll L1 (e.g. listener.EthClient). Events are applied in order once due, when called.
+type Client struct {
+	mu      sync.Mutex
+	start   time.Time
+	chainID uint64
+	state   state
+	pending []Event
+	calls   []Call
+}
+
+func NewClient(head uint64, timeline ...Event) *Client {
+	return &Client{
+		start:   time.Now(),
+		chainID: 31337,
+		state:   state{head: head},
+		pending: timeline,
+	}
+}
+
+// Overrides the 31337 chain ID reported by ChainID
+func (c *Client) SetChainID(chainID uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chainID = chainID
+}
+
+// Applies steps immediately, e.g. to advance the head in lockstep with a test
+func (c *Client) Apply(steps ...Step) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, step := range steps {
+		step(&c.state)
+	}
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	at := time.Since(c.start)
+	c.advance(at)
+	s := c.state
+	c.mu.Unlock()
+
+	if s.latency > 0 {
+		select {
+		case <-time.After(s.latency):
+		case <-ctx.Done():
+			s.err = ctx.Err()
+		}
+	}
+	if s.err != nil {
+		s.head = 0
+	}
+	c.mu.Lock()
+	c.calls = append(c.calls, Call{At: at, Head: s.head, Err: s.err})
+	c.mu.Unlock()
+	return s.head, s.err
+}
+
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	at := time.Since(c.start)
+	c.advance(at)
+	s := c.state
+	chainID := c.chainID
+	c.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return new(big.Int).SetUint64(chainID), nil
+}
+
+// Calls made to BlockNumber, in the order they returned
+func (c *Client) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// Whether every event in the timeline was applied
+func (c *Client) Done() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending) == 0
+}
+
+// Must be called with mu held
+func (c *Client) advance(at time.Duration) {
+	for len(c.pending) > 0 && c.pending[0].After <= at {
+		for _, step := range c.pending[0].Steps {
+			step(&c.state)
+		}
+		c.pending = c.pending[1:]
+	}
+}
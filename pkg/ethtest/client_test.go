@@ -0,0 +1,71 @@
+package ethtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/ethtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTimeline(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	client := ethtest.NewClient(100,
+		ethtest.At(50*time.Millisecond, ethtest.Head(101)),
+		ethtest.At(100*time.Millisecond, ethtest.Fail(errUnavailable)),
+		ethtest.At(150*time.Millisecond, ethtest.Recover(), ethtest.Head(99), ethtest.Latency(20*time.Millisecond)),
+	)
+	ctx := context.Background()
+
+	head, err := client.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), head)
+
+	time.Sleep(60 * time.Millisecond)
+	head, err = client.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(101), head)
+	require.False(t, client.Done())
+
+	time.Sleep(50 * time.Millisecond)
+	_, err = client.BlockNumber(ctx)
+	require.ErrorIs(t, err, errUnavailable)
+	_, err = client.ChainID(ctx)
+	require.ErrorIs(t, err, errUnavailable)
+
+	time.Sleep(50 * time.Millisecond)
+	started := time.Now()
+	head, err = client.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), head, "reorged to a lower head")
+	require.GreaterOrEqual(t, time.Since(started), 20*time.Millisecond)
+	require.True(t, client.Done())
+
+	calls := client.Calls()
+	require.Len(t, calls, 4)
+	require.Equal(t, []uint64{100, 101, 0, 99}, []uint64{calls[0].Head, calls[1].Head, calls[2].Head, calls[3].Head})
+	require.ErrorIs(t, calls[2].Err, errUnavailable)
+}
+
+func TestClientLatencyCancelled(t *testing.T) {
+	client := ethtest.NewClient(100)
+	client.Apply(ethtest.Latency(time.Minute))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.BlockNumber(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClientChainID(t *testing.T) {
+	client := ethtest.NewClient(100)
+	chainID, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(31337), chainID.Uint64())
+	client.SetChainID(1)
+	chainID, err = client.ChainID(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), chainID.Uint64())
+}
@@ -0,0 +1,202 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+)
+
+// Bounds how long a sink may take to publish one event, so a stuck sink can't back up the queue indefinitely
+const publishTimeout = 10 * time.Second
+
+// Wait before publishing a failed event again, doubling up to maxRetryBackoff
+const (
+	retryBackoff    = 100 * time.Millisecond
+	maxRetryBackoff = 5 * time.Second
+)
+
+// Bounds how long Close keeps retrying failed events before dropping them
+const closeTimeout = 10 * time.Second
+
+// Publishes domain events to a sink in the background, in the order emitted. Queued events are published at least
+// once: failed publishes are retried until the sink accepts them, though events emitted while the queue is full
+// are dropped. Satisfies auction.Auditor, commitment.Observer and attestation.Observer, and translates listener auction events with Watch.
+type Emitter struct {
+	logger *slog.Logger
+	sink   Sink
+	events chan Event
+	done   chan struct{}
+	// Closed once Close stops retrying
+	abandon chan struct{}
+
+	mu     sync.RWMutex // Protects closed, so events aren't sent on a closed channel
+	closed bool
+}
+
+// Buffers up to bufferSize events, beyond which events are dropped so a slow sink can't stall auctions
+func NewEmitter(logger *slog.Logger, sink Sink, bufferSize int) *Emitter {
+	e := &Emitter{
+		logger:  logger,
+		sink:    sink,
+		events:  make(chan Event, bufferSize),
+		done:    make(chan struct{}),
+		abandon: make(chan struct{}),
+	}
+	go e.publish()
+	return e
+}
+
+func (e *Emitter) publish() {
+	defer close(e.done)
+	for ev := range e.events {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			e.logger.Error("failed to encode event", "type", ev.Type, "error", err)
+			continue
+		}
+		e.deliver(ev, data)
+	}
+}
+
+// Publishes the event until the sink accepts it, or Close stops retrying
+func (e *Emitter) deliver(ev Event, data []byte) {
+	backoff := retryBackoff
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		err := e.sink.Publish(ctx, ev.key(), data)
+		cancel()
+		if err == nil {
+			return
+		}
+		select {
+		case <-e.abandon:
+			e.logger.Error("dropping unpublished event on close", "type", ev.Type, "l1Block", ev.L1Block, "error", err)
+			return
+		default:
+		}
+		e.logger.Warn("failed to publish event, retrying", "type", ev.Type, "l1Block", ev.L1Block, "backoff", backoff, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-e.abandon:
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+// Queues the event without blocking. Version and time are set if missing.
+func (e *Emitter) Emit(ev Event) {
+	if ev.SchemaVersion == 0 {
+		ev.SchemaVersion = SchemaVersion
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.events <- ev:
+	default:
+		e.logger.Warn("dropping event for slow sink", "type", ev.Type, "l1Block", ev.L1Block)
+	}
+}
+
+// Emits auction opened and closed events from the listener's event feed (see Listener.SubscribeEvents),
+// until ctx is done or events is closed
+func (e *Emitter) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			var typ Type
+			switch ev.Type {
+			case auction.EventAuctionOpened:
+				typ = TypeAuctionOpened
+			case auction.EventAuctionClosed:
+				typ = TypeAuctionClosed
+			default:
+				continue
+			}
+			e.Emit(Event{Type: typ, Time: ev.Timestamp, L1Block: blockNumber(ev.L1Block), Bid: ev.Bid})
+		}
+	}
+}
+
+// To satisfy auction.Auditor
+func (e *Emitter) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	ev := Event{Type: TypeBidAccepted, L1Block: blockNumber(bid.L1Block), Bid: &bid}
+	if !accepted {
+		ev.Type, ev.Reason = TypeBidRejected, reason
+	}
+	e.Emit(ev)
+}
+
+// To satisfy commitment.Observer
+func (e *Emitter) CommitmentIssued(c commitment.Commitment) {
+	hash := c.Hash()
+	e.Emit(Event{Type: TypeCommitmentIssued, L1Block: blockNumber(c.TargetBlock), CommitmentHash: &hash, Commitment: &c})
+}
+
+// To satisfy attestation.Observer
+func (e *Emitter) CommitmentAttested(attested attestation.Attested) {
+	hash := attested.Commitment.Hash()
+	e.Emit(Event{
+		Type:           TypeCommitmentAttested,
+		L1Block:        blockNumber(attested.Commitment.TargetBlock),
+		CommitmentHash: &hash,
+		Commitment:     &attested.Commitment,
+		Attestations:   attested.Attestations,
+	})
+}
+
+// To satisfy commitment.Observer. Misses are violations whatever the cause, with the reason attributing them.
+func (e *Emitter) CommitmentMissed(c commitment.Commitment, reason commitment.MissReason, block *big.Int) {
+	hash := c.Hash()
+	e.Emit(Event{
+		Type:           TypeViolationDetected,
+		L1Block:        blockNumber(block),
+		Reason:         reason.String(),
+		CommitmentHash: &hash,
+		Commitment:     &c,
+	})
+}
+
+// Publishes queued events, retrying failed ones for up to closeTimeout, then closes the sink. Events emitted
+// afterwards are discarded.
+func (e *Emitter) Close() error {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	close(e.events)
+	e.mu.Unlock()
+	select {
+	case <-e.done:
+	case <-time.After(closeTimeout):
+		close(e.abandon)
+		<-e.done
+	}
+	return e.sink.Close()
+}
+
+func blockNumber(block *big.Int) uint64 {
+	if block == nil {
+		return 0
+	}
+	return block.Uint64()
+}
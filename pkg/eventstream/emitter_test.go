@@ -0,0 +1,166 @@
+package eventstream_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/eventstream"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []eventstream.Event {
+	var events []eventstream.Event
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var ev eventstream.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev))
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestEmitter(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := eventstream.NewEmitter(slog.Default(), eventstream.NewWriterSink(&buf), 16)
+	pk, _ := crypto.GenerateKey()
+
+	auctionEvents := make(chan auction.Event, 4)
+	bid := auction.MustCreateSignedBid(big.NewInt(5), big.NewInt(100), pk)
+	auctionEvents <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100), Timestamp: time.Now()}
+	auctionEvents <- auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: bid, Timestamp: time.Now()}
+	close(auctionEvents)
+	emitter.Watch(context.Background(), auctionEvents)
+	emitter.RecordBid(*bid, true, "")
+	emitter.RecordBid(*bid, false, "not on whitelist")
+	c := commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(101),
+		ExpiryBlock:     big.NewInt(101),
+		FeeWei:          big.NewInt(5),
+	}
+	emitter.CommitmentIssued(c)
+	attestations := []attestation.Attestation{{CommitmentHash: c.Hash(), Watcher: common.HexToAddress("0x01"), Signature: []byte{1}}}
+	emitter.CommitmentAttested(attestation.Attested{Commitment: c, Attestations: attestations, Quorum: true, Threshold: 1})
+	emitter.CommitmentMissed(c, commitment.MissReasonRelayFault, big.NewInt(102))
+	require.NoError(t, emitter.Close())
+	emitter.RecordBid(*bid, true, "") // discarded after close
+
+	events := decodeEvents(t, &buf)
+	require.Len(t, events, 6, "leaderChanged isn't a domain event")
+	var types []eventstream.Type
+	for _, ev := range events {
+		require.Equal(t, eventstream.SchemaVersion, ev.SchemaVersion)
+		require.False(t, ev.Time.IsZero())
+		types = append(types, ev.Type)
+	}
+	require.Equal(t, []eventstream.Type{
+		eventstream.TypeAuctionOpened,
+		eventstream.TypeBidAccepted,
+		eventstream.TypeBidRejected,
+		eventstream.TypeCommitmentIssued,
+		eventstream.TypeCommitmentAttested,
+		eventstream.TypeViolationDetected,
+	}, types)
+	require.Equal(t, uint64(100), events[0].L1Block)
+	require.Equal(t, *bid, *events[1].Bid)
+	require.Equal(t, "not on whitelist", events[2].Reason)
+	require.Equal(t, c.Hash(), *events[3].CommitmentHash)
+	require.Equal(t, attestations, events[4].Attestations)
+	require.Equal(t, c.Hash(), *events[4].CommitmentHash)
+	require.Equal(t, uint64(102), events[5].L1Block)
+	require.Equal(t, "relayFault", events[5].Reason)
+	require.Equal(t, c.Hash(), events[5].Commitment.Hash())
+}
+
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (s *blockingSink) Publish(ctx context.Context, key string, data []byte) error {
+	<-s.release
+	return nil
+}
+
+func (s *blockingSink) Close() error {
+	return nil
+}
+
+func TestEmitterDropsWhenFull(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	emitter := eventstream.NewEmitter(slog.Default(), sink, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			emitter.Emit(eventstream.Event{Type: eventstream.TypeAuctionOpened, L1Block: uint64(i)})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("emit blocked on a stuck sink")
+	}
+	close(sink.release)
+	require.NoError(t, emitter.Close())
+}
+
+// Fails the first publishes, recording the keys of accepted events
+type flakySink struct {
+	mu       sync.Mutex
+	failures int
+	keys     []string
+	events   []eventstream.Event
+}
+
+func (s *flakySink) Publish(ctx context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("broker unavailable")
+	}
+	var ev eventstream.Event
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return err
+	}
+	s.keys = append(s.keys, key)
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func (s *flakySink) Close() error {
+	return nil
+}
+
+func TestEmitterRetriesFailedPublishes(t *testing.T) {
+	sink := &flakySink{failures: 3}
+	emitter := eventstream.NewEmitter(slog.Default(), sink, 16)
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(5), big.NewInt(100), pk)
+	c := commitment.Commitment{TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(101), FeeWei: big.NewInt(5)}
+
+	emitter.Emit(eventstream.Event{Type: eventstream.TypeAuctionOpened, L1Block: 100})
+	emitter.RecordBid(*bid, true, "")
+	emitter.CommitmentIssued(c)
+	emitter.CommitmentMissed(c, commitment.MissReasonRelayFault, big.NewInt(102))
+	require.NoError(t, emitter.Close())
+
+	require.Len(t, sink.events, 4, "every event delivered despite failures")
+	require.Equal(t, eventstream.TypeAuctionOpened, sink.events[0].Type, "in order")
+	require.Equal(t, eventstream.TypeViolationDetected, sink.events[3].Type)
+	require.Equal(t, []string{"100", "100", "100", "100"}, sink.keys, "partitioned by the auction's slot")
+}
@@ -0,0 +1,53 @@
+package eventstream
+
+import (
+	"strconv"
+	"time"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Bumped on breaking changes to Event, so consumers can tell encodings apart
+const SchemaVersion = 1
+
+type Type string
+
+const (
+	TypeAuctionOpened      Type = "auction.opened"
+	TypeAuctionClosed      Type = "auction.closed"
+	TypeBidAccepted        Type = "bid.accepted"
+	TypeBidRejected        Type = "bid.rejected"
+	TypeCommitmentIssued   Type = "commitment.issued"
+	TypeCommitmentAttested Type = "commitment.attested"
+	TypeViolationDetected  Type = "violation.detected"
+)
+
+// Machine-readable domain event, one JSON object per event. Fields are set depending on the type.
+type Event struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	Type          Type      `json:"type"`
+	Time          time.Time `json:"time"`
+	// Auction block, or block a violation was detected at
+	L1Block uint64 `json:"l1Block,omitempty"`
+	// Accepted or rejected bid, or auction winner. Nil for auctions closed with no winner.
+	Bid *auction.SignedBid `json:"bid,omitempty"`
+	// Why a bid was rejected, or a commitment missed (see commitment.MissReason)
+	Reason         string                 `json:"reason,omitempty"`
+	CommitmentHash *common.Hash           `json:"commitmentHash,omitempty"`
+	Commitment     *commitment.Commitment `json:"commitment,omitempty"`
+	// Watchers' counter-signatures of a commitment reaching its quorum
+	Attestations []attestation.Attestation `json:"attestations,omitempty"`
+}
+
+// Partitioning key, the auction's L1 block, so events of one slot stay ordered in sinks that partition. Commitment
+// events are keyed by the commitment's target block, so a violation lands with the auction it was committed in.
+func (e Event) key() string {
+	if e.Commitment != nil && e.Commitment.TargetBlock != nil {
+		return e.Commitment.TargetBlock.String()
+	}
+	return strconv.FormatUint(e.L1Block, 10)
+}
@@ -0,0 +1,173 @@
+package eventstream
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+var ErrUnknownSink = errors.New("unknown event sink")
+
+// Destination of encoded events. Publish is called from a single goroutine, and returns once the event is
+// durably accepted, as failed events are published again.
+type Sink interface {
+	// key is the event's slot, as its L1 block, for sinks that partition
+	Publish(ctx context.Context, key string, data []byte) error
+	Close() error
+}
+
+type SinkConfig struct {
+	// stdout, file, nats or kafka
+	Type string `yaml:"type"`
+	// File to append to, for file
+	Path string `yaml:"path"`
+	// Server URL, e.g. nats://localhost:4222, for nats
+	URL string `yaml:"url"`
+	// Subject prefix for nats, topic for kafka
+	Subject string   `yaml:"subject"`
+	Brokers []string `yaml:"brokers"`
+}
+
+func NewSink(config SinkConfig) (Sink, error) {
+	switch config.Type {
+	case "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case "file":
+		return NewFileSink(config.Path)
+	case "nats":
+		return NewNATSSink(config.URL, config.Subject)
+	case "kafka":
+		return NewKafkaSink(config.Brokers, config.Subject)
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownSink, config.Type)
+}
+
+// Writes events as JSON lines, e.g. to stdout
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Publish(ctx context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(append(data, '\n'))
+	return err
+}
+
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// Appends events as JSON lines to a file
+type FileSink struct {
+	WriterSink
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event file: %w", err)
+	}
+	return &FileSink{WriterSink: WriterSink{w: file}, file: file}, nil
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// Publishes events to a NATS JetStream stream, on subject <subject>.<l1 block> so consumers can filter by slot.
+// Publishes wait for the stream's ack, and carry a message ID so the stream drops the duplicates of retries.
+// The stream must capture <subject>.>.
+type NATSSink struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	subject string
+}
+
+func NewNATSSink(url string, subject string) (*NATSSink, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("nats event sink requires a subject")
+	}
+	conn, err := nats.Connect(url, nats.Name("blob-preconfs auctioneer"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open nats jetstream: %w", err)
+	}
+	return &NATSSink{conn: conn, js: js, subject: subject}, nil
+}
+
+func (s *NATSSink) Publish(ctx context.Context, key string, data []byte) error {
+	_, err := s.js.Publish(s.subject+"."+key, data, nats.Context(ctx), nats.MsgId(messageID(data)))
+	return err
+}
+
+// Flushes buffered events before closing the connection
+func (s *NATSSink) Close() error {
+	return s.conn.Drain()
+}
+
+// Publishes events to a Kafka topic, keyed by slot so events of one auction land on one partition. Writes wait for
+// every in-sync replica, and carry an id header for consumers to drop the duplicates of retries.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(brokers []string, topic string) (*KafkaSink, error) {
+	if len(brokers) == 0 || topic == "" {
+		return nil, fmt.Errorf("kafka event sink requires brokers and a topic")
+	}
+	return &KafkaSink{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}}, nil
+}
+
+func (s *KafkaSink) Publish(ctx context.Context, key string, data []byte) error {
+	return s.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(key),
+		Value:   data,
+		Headers: []kafka.Header{{Key: "id", Value: []byte(messageID(data))}},
+	})
+}
+
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
+
+// Identifies an event across retries, as they publish the same encoding
+func messageID(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Brokers as a comma separated list, e.g. from a flag
+func ParseBrokers(s string) []string {
+	var brokers []string
+	for _, broker := range strings.Split(s, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	return brokers
+}
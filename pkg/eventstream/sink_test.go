@@ -0,0 +1,96 @@
+package eventstream_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/eventstream"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := eventstream.NewWriterSink(&buf)
+	require.NoError(t, sink.Publish(context.Background(), "1", []byte(`{"a":1}`)))
+	require.NoError(t, sink.Publish(context.Background(), "2", []byte(`{"a":2}`)))
+	require.Equal(t, "{\"a\":1}\n{\"a\":2}\n", buf.String())
+}
+
+func TestFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	for _, data := range []string{`{"a":1}`, `{"a":2}`} {
+		sink, err := eventstream.NewFileSink(path)
+		require.NoError(t, err)
+		require.NoError(t, sink.Publish(context.Background(), "", []byte(data)))
+		require.NoError(t, sink.Close())
+	}
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "{\"a\":1}\n{\"a\":2}\n", string(data))
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := eventstream.NewSink(eventstream.SinkConfig{Type: "file", Path: filepath.Join(t.TempDir(), "events.jsonl")})
+	require.NoError(t, err)
+	require.IsType(t, &eventstream.FileSink{}, sink)
+	require.NoError(t, sink.Close())
+
+	_, err = eventstream.NewSink(eventstream.SinkConfig{Type: "carrier-pigeon"})
+	require.ErrorIs(t, err, eventstream.ErrUnknownSink)
+	_, err = eventstream.NewSink(eventstream.SinkConfig{Type: "kafka", Subject: "events"})
+	require.Error(t, err, "kafka requires brokers")
+	require.Equal(t, []string{"a:9092", "b:9092"}, eventstream.ParseBrokers(" a:9092,,b:9092 "))
+}
+
+// Points to a NATS server to publish test events to
+const natsURLEnv = "NATS_TEST_URL"
+
+func TestNATSSink(t *testing.T) {
+	url := os.Getenv(natsURLEnv)
+	if url == "" {
+		t.Skipf("%s not set", natsURLEnv)
+	}
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+	js, err := conn.JetStream()
+	require.NoError(t, err)
+	js.DeleteStream("AUCTIONEER_TEST")
+	_, err = js.AddStream(&nats.StreamConfig{Name: "AUCTIONEER_TEST", Subjects: []string{"auctioneer.test.>"}})
+	require.NoError(t, err)
+	sub, err := conn.SubscribeSync("auctioneer.test.>")
+	require.NoError(t, err)
+	require.NoError(t, conn.Flush())
+
+	sink, err := eventstream.NewNATSSink(url, "auctioneer.test")
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), "1", []byte(`{"a":1}`)))
+	require.NoError(t, sink.Close())
+	msg, err := sub.NextMsg(5 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`, string(msg.Data))
+	require.Equal(t, "auctioneer.test.1", msg.Subject, "subject by slot")
+}
+
+// Comma separated Kafka brokers with topic auto-creation enabled
+const kafkaBrokersEnv = "KAFKA_TEST_BROKERS"
+
+func TestKafkaSink(t *testing.T) {
+	brokers := os.Getenv(kafkaBrokersEnv)
+	if brokers == "" {
+		t.Skipf("%s not set", kafkaBrokersEnv)
+	}
+	sink, err := eventstream.NewKafkaSink(strings.Split(brokers, ","), "auctioneer-test")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, sink.Publish(ctx, "1", []byte(`{"a":1}`)))
+	require.NoError(t, sink.Close())
+}
@@ -0,0 +1,248 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/store"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type Kind string
+
+const (
+	KindAuctions    Kind = "auctions"
+	KindBids        Kind = "bids"
+	KindSettlements Kind = "settlements"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+var ErrUnsupported = errors.New("unsupported export")
+
+func ParseKind(s string) (Kind, error) {
+	switch kind := Kind(s); kind {
+	case KindAuctions, KindBids, KindSettlements:
+		return kind, nil
+	}
+	return "", fmt.Errorf("%w: unknown kind %q", ErrUnsupported, s)
+}
+
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case FormatCSV, FormatParquet:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: unknown format %q", ErrUnsupported, s)
+}
+
+// Satisfied by store.Store implementations
+type History interface {
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+	ListBids(filter store.BidFilter, page store.Page) ([]store.BidRecord, string, error)
+}
+
+// Rows are flat for analysis tools. Wei amounts are decimal strings, as they overflow 64 bit integers.
+type AuctionRow struct {
+	L1Block      uint64     `parquet:"l1_block"`
+	Winner       string     `parquet:"winner,optional"`
+	AmountWei    string     `parquet:"amount_wei,optional"`
+	ClosedAt     time.Time  `parquet:"closed_at,timestamp"`
+	SettlementTx string     `parquet:"settlement_tx,optional"`
+	SettledAt    *time.Time `parquet:"settled_at,optional,timestamp"`
+}
+
+type BidRow struct {
+	L1Block    uint64    `parquet:"l1_block"`
+	Relay      string    `parquet:"relay"`
+	AmountWei  string    `parquet:"amount_wei"`
+	Signature  string    `parquet:"signature"`
+	ReceivedAt time.Time `parquet:"received_at,timestamp"`
+}
+
+type SettlementRow struct {
+	L1Block      uint64    `parquet:"l1_block"`
+	Winner       string    `parquet:"winner"`
+	AmountWei    string    `parquet:"amount_wei"`
+	SettlementTx string    `parquet:"settlement_tx"`
+	SettledAt    time.Time `parquet:"settled_at,timestamp"`
+}
+
+var (
+	auctionHeader    = []string{"l1_block", "winner", "amount_wei", "closed_at", "settlement_tx", "settled_at"}
+	bidHeader        = []string{"l1_block", "relay", "amount_wei", "signature", "received_at"}
+	settlementHeader = []string{"l1_block", "winner", "amount_wei", "settlement_tx", "settled_at"}
+)
+
+func (r AuctionRow) csvRecord() []string {
+	settledAt := ""
+	if r.SettledAt != nil {
+		settledAt = formatTime(*r.SettledAt)
+	}
+	return []string{formatBlock(r.L1Block), r.Winner, r.AmountWei, formatTime(r.ClosedAt), r.SettlementTx, settledAt}
+}
+
+func (r BidRow) csvRecord() []string {
+	return []string{formatBlock(r.L1Block), r.Relay, r.AmountWei, r.Signature, formatTime(r.ReceivedAt)}
+}
+
+func (r SettlementRow) csvRecord() []string {
+	return []string{formatBlock(r.L1Block), r.Winner, r.AmountWei, r.SettlementTx, formatTime(r.SettledAt)}
+}
+
+// Dumps auction history for offline economic analysis
+type Exporter struct {
+	history History
+}
+
+func NewExporter(history History) *Exporter {
+	return &Exporter{history: history}
+}
+
+// Writes records of kind for blocks to w in format, returning the number of rows written.
+// Records are streamed page by page from history, ordered by L1 block.
+func (e *Exporter) Export(w io.Writer, kind Kind, format Format, blocks store.BlockRange) (int, error) {
+	switch kind {
+	case KindAuctions:
+		return write(w, format, auctionHeader, pages(func(page store.Page) ([]AuctionRow, string, error) {
+			records, next, err := e.history.ListAuctions(store.AuctionFilter{Blocks: blocks}, page)
+			rows := make([]AuctionRow, len(records))
+			for i, record := range records {
+				rows[i] = auctionRow(record)
+			}
+			return rows, next, err
+		}))
+	case KindBids:
+		return write(w, format, bidHeader, pages(func(page store.Page) ([]BidRow, string, error) {
+			records, next, err := e.history.ListBids(store.BidFilter{Blocks: blocks}, page)
+			rows := make([]BidRow, len(records))
+			for i, record := range records {
+				rows[i] = BidRow{
+					L1Block:    record.Bid.L1Block.Uint64(),
+					Relay:      record.Bid.Address.Hex(),
+					AmountWei:  record.Bid.AmountWei.String(),
+					Signature:  record.Bid.Signature.String(),
+					ReceivedAt: record.ReceivedAt.UTC(),
+				}
+			}
+			return rows, next, err
+		}))
+	case KindSettlements:
+		return write(w, format, settlementHeader, pages(func(page store.Page) ([]SettlementRow, string, error) {
+			records, next, err := e.history.ListAuctions(store.AuctionFilter{Blocks: blocks}, page)
+			var rows []SettlementRow
+			for _, record := range records {
+				if record.SettlementTx == nil {
+					continue
+				}
+				rows = append(rows, SettlementRow{
+					L1Block:      record.L1Block,
+					Winner:       record.Winner.Address.Hex(),
+					AmountWei:    record.Winner.AmountWei.String(),
+					SettlementTx: record.SettlementTx.Hex(),
+					SettledAt:    record.SettledAt.UTC(),
+				})
+			}
+			return rows, next, err
+		}))
+	}
+	return 0, fmt.Errorf("%w: unknown kind %q", ErrUnsupported, kind)
+}
+
+func auctionRow(record store.AuctionRecord) AuctionRow {
+	row := AuctionRow{L1Block: record.L1Block, ClosedAt: record.ClosedAt.UTC()}
+	if record.Winner != nil {
+		row.Winner, row.AmountWei = record.Winner.Address.Hex(), winnerAmount(record.Winner)
+	}
+	if record.SettlementTx != nil {
+		row.SettlementTx = record.SettlementTx.Hex()
+	}
+	if record.SettledAt != nil {
+		settledAt := record.SettledAt.UTC()
+		row.SettledAt = &settledAt
+	}
+	return row
+}
+
+func winnerAmount(bid *auction.SignedBid) string {
+	if bid.AmountWei == nil {
+		return ""
+	}
+	return bid.AmountWei.String()
+}
+
+// Yields rows page by page until the last page, or an error
+func pages[T any](list func(page store.Page) ([]T, string, error)) func(yield func([]T) error) error {
+	return func(yield func([]T) error) error {
+		page := store.Page{Limit: store.MaxPageLimit}
+		for {
+			rows, next, err := list(page)
+			if err != nil {
+				return err
+			}
+			if err := yield(rows); err != nil {
+				return err
+			}
+			if next == "" {
+				return nil
+			}
+			page.Cursor = next
+		}
+	}
+}
+
+func write[T interface{ csvRecord() []string }](w io.Writer, format Format, header []string, each func(yield func([]T) error) error) (int, error) {
+	count := 0
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return 0, err
+		}
+		err := each(func(rows []T) error {
+			for _, row := range rows {
+				if err := writer.Write(row.csvRecord()); err != nil {
+					return err
+				}
+			}
+			count += len(rows)
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+		writer.Flush()
+		return count, writer.Error()
+	case FormatParquet:
+		writer := parquet.NewGenericWriter[T](w)
+		err := each(func(rows []T) error {
+			n, err := writer.Write(rows)
+			count += n
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+		return count, writer.Close()
+	}
+	return 0, fmt.Errorf("%w: unknown format %q", ErrUnsupported, format)
+}
+
+func formatBlock(block uint64) string {
+	return strconv.FormatUint(block, 10)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,88 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/export"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func seed(t *testing.T) (*store.MemoryStore, *auction.SignedBid) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	closedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var winner *auction.SignedBid
+	for block := int64(100); block < 104; block++ {
+		winner = auction.MustCreateSignedBid(big.NewInt(10_000_000_000_000_000*block), big.NewInt(block), pk)
+		require.NoError(t, history.SaveBid(*winner, closedAt))
+		require.NoError(t, history.SaveAuctionResult(uint64(block), winner, closedAt))
+	}
+	require.NoError(t, history.SaveAuctionResult(104, nil, closedAt))
+	require.NoError(t, history.SaveSettlement(101, common.Hash{0x01}, closedAt.Add(time.Minute)))
+	return history, winner
+}
+
+func TestExportCSV(t *testing.T) {
+	history, _ := seed(t)
+	exporter := export.NewExporter(history)
+
+	var buf bytes.Buffer
+	rows, err := exporter.Export(&buf, export.KindAuctions, export.FormatCSV, store.BlockRange{From: 101, To: 104})
+	require.NoError(t, err)
+	require.Equal(t, 4, rows)
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	require.Equal(t, []string{"l1_block", "winner", "amount_wei", "closed_at", "settlement_tx", "settled_at"}, records[0])
+	require.Equal(t, []string{"101", "1010000000000000000", common.Hash{0x01}.Hex(), "2024-01-01T00:01:00Z"},
+		[]string{records[1][0], records[1][2], records[1][4], records[1][5]})
+	require.Equal(t, []string{"104", "", "", "2024-01-01T00:00:00Z", "", ""}, records[4])
+
+	buf.Reset()
+	rows, err = exporter.Export(&buf, export.KindSettlements, export.FormatCSV, store.BlockRange{})
+	require.NoError(t, err)
+	require.Equal(t, 1, rows)
+
+	buf.Reset()
+	rows, err = exporter.Export(&buf, export.KindBids, export.FormatCSV, store.BlockRange{To: 101})
+	require.NoError(t, err)
+	require.Equal(t, 2, rows)
+}
+
+func TestExportParquet(t *testing.T) {
+	history, winner := seed(t)
+	var buf bytes.Buffer
+	rows, err := export.NewExporter(history).Export(&buf, export.KindBids, export.FormatParquet, store.BlockRange{From: 103})
+	require.NoError(t, err)
+	require.Equal(t, 1, rows)
+
+	read, err := parquet.Read[export.BidRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Equal(t, []export.BidRow{{
+		L1Block:    103,
+		Relay:      winner.Address.Hex(),
+		AmountWei:  "1030000000000000000",
+		Signature:  winner.Signature.String(),
+		ReceivedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}, read)
+}
+
+func TestParse(t *testing.T) {
+	kind, err := export.ParseKind("settlements")
+	require.NoError(t, err)
+	require.Equal(t, export.KindSettlements, kind)
+	_, err = export.ParseKind("relays")
+	require.ErrorIs(t, err, export.ErrUnsupported)
+	_, err = export.ParseFormat("xlsx")
+	require.ErrorIs(t, err, export.ErrUnsupported)
+}
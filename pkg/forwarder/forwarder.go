@@ -0,0 +1,202 @@
+package forwarder
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/jsonrpc"
+	"blob-preconfs/pkg/listener"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	forwardTimeout  = 2 * time.Second
+	eventBufferSize = 64
+)
+
+// Accepts bids locally and forwards them to an upstream auctioneer, for relays far from it.
+// Serves in place of the listener, with auction state mirrored from the upstream event stream.
+type Forwarder struct {
+	logger *slog.Logger
+	client *rpc.Client
+
+	eventFeed event.Feed
+
+	mu     sync.RWMutex // Protects access to fields below
+	open   *listener.AuctionState
+	closed *listener.AuctionState
+}
+
+// Connects to the upstream auctioneer's websocket JSON-RPC endpoint. Requests are signed with privateKey,
+// which the upstream must trust as a forwarder, if non-nil. The system roots are used for TLS if tlsConfig is nil.
+func NewForwarder(
+	ctx context.Context,
+	logger *slog.Logger,
+	upstream string,
+	privateKey *ecdsa.PrivateKey,
+	tlsConfig *tls.Config,
+) (*Forwarder, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream: %w", err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, fmt.Errorf("invalid upstream: websocket endpoint required to mirror auction events")
+	}
+	opts := []rpc.ClientOption{
+		rpc.WithWebsocketDialer(websocket.Dialer{TLSClientConfig: tlsConfig, HandshakeTimeout: 10 * time.Second}),
+	}
+	if privateKey != nil {
+		header, err := auth.Headers(u.Path, nil, privateKey)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, rpc.WithHeaders(header))
+	}
+	client, err := rpc.DialOptions(ctx, upstream, opts...)
+	if err != nil {
+		return nil, err
+	}
+	f := &Forwarder{logger: logger, client: client}
+	events := make(chan auction.Event, eventBufferSize)
+	sub, err := client.Subscribe(ctx, "auction", events, "events")
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	go f.mirror(events, sub)
+	logger.Info("forwarding bids to upstream auctioneer", "upstream", u.Redacted())
+	return f, nil
+}
+
+func (f *Forwarder) Close() {
+	f.client.Close()
+}
+
+// Validates the bid locally, so invalid bids are rejected without a round trip, then forwards it upstream
+func (f *Forwarder) SubmitBid(bid auction.SignedBid) error {
+	if err := bid.Validate(); err != nil {
+		return err
+	}
+	f.mu.RLock()
+	open := f.open
+	f.mu.RUnlock()
+	if open == nil {
+		return auction.ErrNoActiveAuction
+	}
+	if bid.L1Block.Uint64() != open.L1Block {
+		return auction.ErrWrongBlock
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), forwardTimeout)
+	defer cancel()
+	if err := f.client.CallContext(ctx, nil, "auction_submitBid", bid); err != nil {
+		return fmt.Errorf("upstream rejected bid: %w", jsonrpc.RejectionOf(err))
+	}
+	return nil
+}
+
+// Served from the mirrored state, without a round trip
+func (f *Forwarder) GetCurrentBid() (winningBid auction.SignedBid, found bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.open == nil {
+		return auction.SignedBid{}, false
+	}
+	if f.open.LeadingBid == nil {
+		return auction.SignedBid{}, true
+	}
+	return *f.open.LeadingBid, true
+}
+
+// Only the current and last concluded auctions are mirrored
+func (f *Forwarder) GetAuction(l1Block uint64) (state listener.AuctionState, found bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, s := range []*listener.AuctionState{f.open, f.closed} {
+		if s != nil && s.L1Block == l1Block {
+			return *s, true
+		}
+	}
+	return listener.AuctionState{}, false
+}
+
+// Upstream events, re-published to local subscribers. As with the listener, events are dropped for a subscriber
+// whose buffer is full, so a slow subscriber can't stall mirroring.
+func (f *Forwarder) SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription) {
+	in := make(chan auction.Event)
+	out := make(chan auction.Event, bufferSize)
+	sub := f.eventFeed.Subscribe(in)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case ev := <-in:
+				select {
+				case out <- ev:
+				default:
+					f.logger.Warn("dropping auction event for slow subscriber", "type", ev.Type, "l1Block", ev.L1Block)
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return out, sub
+}
+
+func (f *Forwarder) mirror(events <-chan auction.Event, sub *rpc.ClientSubscription) {
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case ev := <-events:
+			f.apply(ev)
+			f.eventFeed.Send(ev)
+		case err := <-sub.Err():
+			if err != nil {
+				f.logger.Error("upstream event subscription failed", "error", err)
+			}
+			f.mu.Lock()
+			f.open = nil
+			f.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (f *Forwarder) apply(ev auction.Event) {
+	block := uint64OrZero(ev.L1Block)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch ev.Type {
+	case auction.EventAuctionOpened:
+		f.open = &listener.AuctionState{L1Block: block, InProgress: true}
+	case auction.EventLeaderChanged:
+		if f.open != nil && f.open.L1Block == block {
+			f.open.LeadingBid = ev.Bid
+		}
+	case auction.EventAuctionClosed:
+		f.closed = &listener.AuctionState{L1Block: block, LeadingBid: ev.Bid}
+		if f.open != nil && f.open.L1Block == block {
+			f.open = nil
+		}
+	}
+}
+
+func uint64OrZero(n *big.Int) uint64 {
+	if n == nil {
+		return 0
+	}
+	return n.Uint64()
+}
@@ -0,0 +1,110 @@
+package forwarder_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/forwarder"
+	"blob-preconfs/pkg/jsonrpc"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUpstream struct {
+	mu        sync.Mutex
+	submitted []auction.SignedBid
+	feed      event.Feed
+}
+
+func (m *mockUpstream) SubmitBid(bid auction.SignedBid) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.submitted = append(m.submitted, bid)
+	return nil
+}
+
+func (m *mockUpstream) GetCurrentBid() (auction.SignedBid, bool) {
+	return auction.SignedBid{}, false
+}
+
+func (m *mockUpstream) SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription) {
+	ch := make(chan auction.Event, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+type noRelaysRegistry struct{}
+
+func (noRelaysRegistry) IsRegisteredOnSettlementLayer(common.Address) bool { return false }
+
+func TestForwarder(t *testing.T) {
+	forwarderKey, _ := crypto.GenerateKey()
+	verifier := auth.NewVerifier(noRelaysRegistry{}, 30*time.Second)
+	verifier.TrustForwarders(crypto.PubkeyToAddress(forwarderKey.PublicKey))
+	upstream := &mockUpstream{}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", upstream, []string{"*"}, nil, verifier, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+
+	_, err = forwarder.NewForwarder(context.Background(), slog.Default(), "http://"+server.Addr().String(), forwarderKey, nil)
+	require.Error(t, err, "websocket upstream required")
+	untrustedKey, _ := crypto.GenerateKey()
+	_, err = forwarder.NewForwarder(context.Background(), slog.Default(), "ws://"+server.Addr().String(), untrustedKey, nil)
+	require.Error(t, err, "untrusted forwarder")
+
+	f, err := forwarder.NewForwarder(context.Background(), slog.Default(), "ws://"+server.Addr().String(), forwarderKey, nil)
+	require.NoError(t, err)
+	defer f.Close()
+	events, sub := f.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+
+	relayKey, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), relayKey)
+	require.ErrorContains(t, f.SubmitBid(*bid), "no auction in progress")
+
+	require.Eventually(t, func() bool {
+		return upstream.feed.Send(auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)}) > 0
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case ev := <-events:
+		require.Equal(t, auction.EventAuctionOpened, ev.Type)
+	case <-time.After(time.Second):
+		t.Fatal("upstream event not mirrored")
+	}
+	_, found := f.GetCurrentBid()
+	require.True(t, found)
+
+	require.NoError(t, f.SubmitBid(*bid))
+	require.ErrorContains(t, f.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(101), relayKey)), "different block")
+	tampered := *bid
+	tampered.AmountWei = big.NewInt(44)
+	require.ErrorContains(t, f.SubmitBid(tampered), "signature does not match address")
+	upstream.mu.Lock()
+	require.Equal(t, []auction.SignedBid{*bid}, upstream.submitted)
+	upstream.mu.Unlock()
+
+	upstream.feed.Send(auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: bid})
+	upstream.feed.Send(auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: bid})
+	for i := 0; i < 2; i++ {
+		select {
+		case <-events:
+		case <-time.After(time.Second):
+			t.Fatal("upstream event not mirrored")
+		}
+	}
+	_, found = f.GetCurrentBid()
+	require.False(t, found)
+	state, found := f.GetAuction(100)
+	require.True(t, found)
+	require.False(t, state.InProgress)
+	require.Equal(t, *bid, *state.LeadingBid)
+}
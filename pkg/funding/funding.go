@@ -0,0 +1,180 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/gasoracle"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Per balance query
+const queryTimeout = 5 * time.Second
+
+type Config struct {
+	// Between balance checks, a minute if 0
+	Interval time.Duration
+	// Projected settlements are assumed to come every SlotTime, the most auctions are won at
+	SlotTime time.Duration
+	// Gas a settlement tx is projected to use
+	SettlementGas uint64
+	// The key is low below this balance, ignored if nil
+	MinBalanceWei *big.Int
+	// The key is low once its balance covers settlements for less than this long at the current max fee,
+	// ignored if 0
+	MinRunway time.Duration
+}
+
+// Satisfied by *ethclient.Client
+type Client interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Prices settlement txs, e.g. *gasoracle.Oracle
+type GasOracle interface {
+	Fees(ctx context.Context) (gasoracle.Fees, error)
+}
+
+// Alerted on every check the key is low, e.g. *alerting.Notifier
+type Alerter interface {
+	FundsLow(address common.Address, balanceWei *big.Int, runway time.Duration)
+}
+
+// Observes each checked balance, e.g. *metrics.Metrics
+type Metrics interface {
+	ObserveSettlementKey(balanceWei *big.Int, runway time.Duration)
+}
+
+// Settlement key's balance against projected gas spend, as of the last check
+type Status struct {
+	Address    common.Address `json:"address"`
+	BalanceWei *big.Int       `json:"balanceWei,omitempty"`
+	// Settlement txs are priced at, and the key must cover
+	MaxFeePerGasWei *big.Int `json:"maxFeePerGasWei,omitempty"`
+	// Projected cost of one settlement tx at the max fee
+	SettlementCostWei *big.Int `json:"settlementCostWei,omitempty"`
+	// Settlements the balance covers
+	Settlements uint64 `json:"settlements"`
+	// How long the balance covers settling every slot's auction
+	Runway time.Duration `json:"runwayNs"`
+	// Below the min balance or runway
+	Low       bool      `json:"low"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// Last failure to check, the figures above are from the check before
+	Error string `json:"error,omitempty"`
+}
+
+// Watches the ETH balance of the key settlement txs are sent from, so settlements don't silently start failing
+// once it runs dry
+type Watcher struct {
+	logger  *slog.Logger
+	config  Config
+	client  Client
+	oracle  GasOracle
+	address common.Address
+	alerter Alerter
+	metrics Metrics
+
+	mu     sync.Mutex // Protects status
+	status Status
+}
+
+func NewWatcher(logger *slog.Logger, config Config, client Client, oracle GasOracle, address common.Address) *Watcher {
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	return &Watcher{logger: logger, config: config, client: client, oracle: oracle, address: address, status: Status{Address: address}}
+}
+
+// A low key is alerted, if set before the watcher starts
+func (w *Watcher) SetAlerter(alerter Alerter) {
+	w.alerter = alerter
+}
+
+// Checked balances are observed, if set before the watcher starts
+func (w *Watcher) SetMetrics(metrics Metrics) {
+	w.metrics = metrics
+}
+
+// Checks the balance right away and then every Interval, until ctx is done
+func (w *Watcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+		for {
+			w.Check(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Reads the balance and settlement tx fees, and projects how many settlements and how long the balance lasts
+func (w *Watcher) Check(ctx context.Context) Status {
+	status, err := w.check(ctx)
+	w.mu.Lock()
+	if err != nil {
+		w.status.Error = err.Error()
+		status = w.status
+		w.mu.Unlock()
+		w.logger.Warn("failed to check settlement key balance", "address", status.Address, "error", err)
+		return status
+	}
+	w.status = status
+	w.mu.Unlock()
+
+	if w.metrics != nil {
+		w.metrics.ObserveSettlementKey(status.BalanceWei, status.Runway)
+	}
+	if status.Low {
+		w.logger.Warn("settlement key running low on funds", "address", status.Address, "balanceWei", status.BalanceWei,
+			"settlements", status.Settlements, "runway", status.Runway)
+		if w.alerter != nil {
+			w.alerter.FundsLow(status.Address, status.BalanceWei, status.Runway)
+		}
+	}
+	return status
+}
+
+func (w *Watcher) check(ctx context.Context) (Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	status := Status{Address: w.address, CheckedAt: time.Now()}
+	balance, err := w.client.BalanceAt(ctx, status.Address, nil)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to get balance: %w", err)
+	}
+	fees, err := w.oracle.Fees(ctx)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to price settlements: %w", err)
+	}
+	status.BalanceWei, status.MaxFeePerGasWei = balance, fees.MaxFeePerGas
+	status.SettlementCostWei = new(big.Int).Mul(fees.MaxFeePerGas, new(big.Int).SetUint64(w.config.SettlementGas))
+	status.Settlements, status.Runway = math.MaxUint64, math.MaxInt64
+	if status.SettlementCostWei.Sign() > 0 {
+		if settlements := new(big.Int).Quo(balance, status.SettlementCostWei); settlements.IsUint64() {
+			status.Settlements = settlements.Uint64()
+		}
+	}
+	if slot := uint64(w.config.SlotTime); slot > 0 && status.Settlements < math.MaxInt64/slot {
+		status.Runway = time.Duration(status.Settlements * slot)
+	}
+	status.Low = (w.config.MinBalanceWei != nil && balance.Cmp(w.config.MinBalanceWei) < 0) ||
+		(w.config.MinRunway > 0 && status.Runway < w.config.MinRunway)
+	return status, nil
+}
+
+func (w *Watcher) Status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
@@ -0,0 +1,84 @@
+package funding_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/funding"
+	"blob-preconfs/pkg/gasoracle"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClient struct {
+	balance *big.Int
+	maxFee  *big.Int
+	err     error
+}
+
+func (m *mockClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return m.balance, m.err
+}
+
+func (m *mockClient) Fees(ctx context.Context) (gasoracle.Fees, error) {
+	return gasoracle.Fees{MaxFeePerGas: m.maxFee}, nil
+}
+
+type mockAlerter struct {
+	mu       sync.Mutex
+	balances []*big.Int
+}
+
+func (m *mockAlerter) FundsLow(address common.Address, balanceWei *big.Int, runway time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balances = append(m.balances, balanceWei)
+}
+
+func TestCheck(t *testing.T) {
+	address := common.HexToAddress("0x1")
+	// 100k gas at 10 gwei costs 0.001 ETH a settlement
+	client := &mockClient{balance: big.NewInt(params.Ether), maxFee: big.NewInt(10 * params.GWei)}
+	alerter := &mockAlerter{}
+	w := funding.NewWatcher(slog.Default(), funding.Config{
+		SlotTime:      12 * time.Second,
+		SettlementGas: 100_000,
+		MinBalanceWei: big.NewInt(params.Ether / 10),
+		MinRunway:     time.Hour,
+	}, client, client, address)
+	w.SetAlerter(alerter)
+
+	status := w.Check(context.Background())
+	require.Equal(t, address, status.Address)
+	require.Equal(t, big.NewInt(params.Ether/1000), status.SettlementCostWei)
+	require.Equal(t, uint64(1000), status.Settlements)
+	require.Equal(t, 12000*time.Second, status.Runway)
+	require.False(t, status.Low)
+	require.Empty(t, alerter.balances)
+
+	// 0.2 ETH covers 200 settlements, 40 minutes of settling every slot
+	client.balance = big.NewInt(params.Ether / 5)
+	status = w.Check(context.Background())
+	require.Equal(t, 40*time.Minute, status.Runway)
+	require.True(t, status.Low, "runway below the min")
+	require.Equal(t, []*big.Int{client.balance}, alerter.balances)
+
+	client.balance, client.maxFee = big.NewInt(params.Ether/20), big.NewInt(0)
+	status = w.Check(context.Background())
+	require.True(t, status.Low, "balance below the min even if settlements are free")
+	require.Len(t, alerter.balances, 2)
+
+	client.err = errors.New("unavailable")
+	status = w.Check(context.Background())
+	require.Equal(t, "failed to get balance: unavailable", status.Error)
+	require.Equal(t, big.NewInt(params.Ether/20), status.BalanceWei, "figures of the last successful check")
+	require.Equal(t, status, w.Status())
+	require.Len(t, alerter.balances, 2, "failed checks aren't alerted as low")
+}
@@ -0,0 +1,152 @@
+package gasoracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+var ErrAboveCap = errors.New("fees above cap")
+
+// Fee history is read within it
+const queryTimeout = 5 * time.Second
+
+// Nodes accept a replacement tx paying at least 10% more, bumped by a margin over that
+const replacementBumpPercent = 12
+
+type Config struct {
+	// Recent blocks the priority fee is estimated from, 20 if 0
+	Blocks uint64
+	// Percentile of each block's priority fees the estimate takes, 50 if 0
+	Percentile float64
+	// The max fee covers the next block's base fee times this, so txs stay includable while the base fee rises
+	// over a few full blocks, 2 if 0
+	BaseFeeMultiplier uint64
+	// Bounds of the priority fee, none if nil
+	MinPriorityFeeWei *big.Int
+	MaxPriorityFeeWei *big.Int
+	// Cap of the max fee, none if nil. Fees fail with ErrAboveCap if it doesn't cover the next block's base fee.
+	MaxFeeWei *big.Int
+}
+
+// Satisfied by *ethclient.Client
+type FeeHistorySource interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// EIP-1559 fees of a tx
+type Fees struct {
+	MaxFeePerGas         *big.Int `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *big.Int `json:"maxPriorityFeePerGas"`
+	// Of the next block, which the fees were estimated for
+	BaseFee *big.Int `json:"baseFee"`
+}
+
+// Prices txs from eth_feeHistory: the priority fee is the median of recent blocks' priority fee percentile, and the
+// max fee covers a multiple of the next block's base fee on top, within caps
+type Oracle struct {
+	config Config
+	source FeeHistorySource
+}
+
+func New(config Config, source FeeHistorySource) *Oracle {
+	if config.Blocks == 0 {
+		config.Blocks = 20
+	}
+	if config.Percentile == 0 {
+		config.Percentile = 50
+	}
+	if config.BaseFeeMultiplier == 0 {
+		config.BaseFeeMultiplier = 2
+	}
+	return &Oracle{config: config, source: source}
+}
+
+// Fees for a tx to land within the next few blocks
+func (o *Oracle) Fees(ctx context.Context) (Fees, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	history, err := o.source.FeeHistory(ctx, o.config.Blocks, nil, []float64{o.config.Percentile})
+	if err != nil {
+		return Fees{}, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return Fees{}, errors.New("empty fee history")
+	}
+	// Base fees run one past the last block, to the next one
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	// Empty blocks report no priority fees, and aren't telling of what it takes to be included
+	var tips []*big.Int
+	for i, rewards := range history.Reward {
+		if len(rewards) > 0 && i < len(history.GasUsedRatio) && history.GasUsedRatio[i] > 0 {
+			tips = append(tips, rewards[0])
+		}
+	}
+	tip := new(big.Int)
+	if len(tips) > 0 {
+		slices.SortFunc(tips, func(a, b *big.Int) int { return a.Cmp(b) })
+		tip.Set(tips[len(tips)/2])
+	}
+	if o.config.MinPriorityFeeWei != nil && tip.Cmp(o.config.MinPriorityFeeWei) < 0 {
+		tip.Set(o.config.MinPriorityFeeWei)
+	}
+	if o.config.MaxPriorityFeeWei != nil && tip.Cmp(o.config.MaxPriorityFeeWei) > 0 {
+		tip.Set(o.config.MaxPriorityFeeWei)
+	}
+	maxFee := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(o.config.BaseFeeMultiplier))
+	maxFee.Add(maxFee, tip)
+	return o.capped(Fees{MaxFeePerGas: maxFee, MaxPriorityFeePerGas: tip, BaseFee: new(big.Int).Set(baseFee)})
+}
+
+// Fees for a tx replacing previous, e.g. one stuck in the mempool: current fees, raised to outbid previous by
+// enough for nodes to accept the replacement. Fails with ErrAboveCap if the caps don't allow it.
+func (o *Oracle) Replacement(ctx context.Context, previous Fees) (Fees, error) {
+	fees, err := o.Fees(ctx)
+	if err != nil && !errors.Is(err, ErrAboveCap) {
+		return Fees{}, err
+	}
+	minFee, minTip := bump(previous.MaxFeePerGas), bump(previous.MaxPriorityFeePerGas)
+	fees.MaxFeePerGas = maxBig(fees.MaxFeePerGas, minFee)
+	fees.MaxPriorityFeePerGas = maxBig(fees.MaxPriorityFeePerGas, minTip)
+	if fees, err = o.capped(fees); err != nil {
+		return fees, err
+	}
+	if fees.MaxFeePerGas.Cmp(minFee) < 0 || fees.MaxPriorityFeePerGas.Cmp(minTip) < 0 {
+		return fees, fmt.Errorf("%w: replacing max fee %v and priority fee %v", ErrAboveCap, previous.MaxFeePerGas, previous.MaxPriorityFeePerGas)
+	}
+	return fees, nil
+}
+
+// Fails if the cap doesn't cover the next block's base fee, as the tx would only get stuck
+func (o *Oracle) capped(fees Fees) (Fees, error) {
+	if o.config.MaxFeeWei == nil || fees.MaxFeePerGas.Cmp(o.config.MaxFeeWei) <= 0 {
+		return fees, nil
+	}
+	if o.config.MaxFeeWei.Cmp(fees.BaseFee) <= 0 {
+		return fees, fmt.Errorf("%w: next base fee %v, cap %v", ErrAboveCap, fees.BaseFee, o.config.MaxFeeWei)
+	}
+	fees.MaxFeePerGas = new(big.Int).Set(o.config.MaxFeeWei)
+	// The priority fee can't exceed the max fee, nor be paid beyond what's left over the base fee
+	if headroom := new(big.Int).Sub(fees.MaxFeePerGas, fees.BaseFee); fees.MaxPriorityFeePerGas.Cmp(headroom) > 0 {
+		fees.MaxPriorityFeePerGas = headroom
+	}
+	return fees, nil
+}
+
+func bump(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+replacementBumpPercent))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
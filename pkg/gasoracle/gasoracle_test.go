@@ -0,0 +1,79 @@
+package gasoracle_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/gasoracle"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFeeHistory struct {
+	history *ethereum.FeeHistory
+	err     error
+}
+
+func (m *mockFeeHistory) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return m.history, m.err
+}
+
+func gwei(n int64) *big.Int {
+	return big.NewInt(n * params.GWei)
+}
+
+// Blocks paying tips of 1, 3 and 2 gwei, an empty block, and a next base fee of 10 gwei
+func history() *ethereum.FeeHistory {
+	return &ethereum.FeeHistory{
+		OldestBlock:  big.NewInt(100),
+		Reward:       [][]*big.Int{{gwei(1)}, {gwei(3)}, {big.NewInt(0)}, {gwei(2)}},
+		BaseFee:      []*big.Int{gwei(8), gwei(9), gwei(10), gwei(9), gwei(10)},
+		GasUsedRatio: []float64{0.5, 0.9, 0, 0.6},
+	}
+}
+
+func TestFees(t *testing.T) {
+	source := &mockFeeHistory{history: history()}
+	fees, err := gasoracle.New(gasoracle.Config{}, source).Fees(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, gwei(2), fees.MaxPriorityFeePerGas, "median of non-empty blocks")
+	require.Equal(t, gwei(10), fees.BaseFee)
+	require.Equal(t, gwei(22), fees.MaxFeePerGas, "twice the next base fee plus the tip")
+
+	fees, err = gasoracle.New(gasoracle.Config{MaxPriorityFeeWei: gwei(1), MaxFeeWei: gwei(15)}, source).Fees(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, gwei(1), fees.MaxPriorityFeePerGas)
+	require.Equal(t, gwei(15), fees.MaxFeePerGas)
+
+	fees, err = gasoracle.New(gasoracle.Config{MaxFeeWei: gwei(11)}, source).Fees(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, gwei(1), fees.MaxPriorityFeePerGas, "tip limited to the headroom over the base fee")
+
+	_, err = gasoracle.New(gasoracle.Config{MaxFeeWei: gwei(10)}, source).Fees(context.Background())
+	require.ErrorIs(t, err, gasoracle.ErrAboveCap)
+
+	fees, err = gasoracle.New(gasoracle.Config{MinPriorityFeeWei: gwei(5)}, source).Fees(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, gwei(5), fees.MaxPriorityFeePerGas)
+
+	source.err = errors.New("unavailable")
+	_, err = gasoracle.New(gasoracle.Config{}, source).Fees(context.Background())
+	require.ErrorContains(t, err, "unavailable")
+}
+
+func TestReplacement(t *testing.T) {
+	source := &mockFeeHistory{history: history()}
+	previous := gasoracle.Fees{MaxFeePerGas: gwei(30), MaxPriorityFeePerGas: gwei(1)}
+
+	fees, err := gasoracle.New(gasoracle.Config{}, source).Replacement(context.Background(), previous)
+	require.NoError(t, err)
+	require.Equal(t, gwei(2), fees.MaxPriorityFeePerGas, "current tip outbids the previous")
+	require.Equal(t, big.NewInt(33_600_000_000), fees.MaxFeePerGas, "previous max fee bumped by 12%")
+
+	_, err = gasoracle.New(gasoracle.Config{MaxFeeWei: gwei(32)}, source).Replacement(context.Background(), previous)
+	require.ErrorIs(t, err, gasoracle.ErrAboveCap, "cap doesn't allow outbidding the previous tx")
+}
@@ -0,0 +1,333 @@
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/crosscheck"
+	"blob-preconfs/pkg/listener"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	libp2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+const (
+	BidsTopic   = "/blob-preconfs/bids/2"
+	StatesTopic = "/blob-preconfs/states/1"
+)
+
+// Bids are forgotten after this many, enough to span a few auctions
+const seenCacheSize = 16384
+
+// Satisfied by *listener.Listener
+type Backend interface {
+	SubmitBidAt(bid auction.SignedBid, receivedAt time.Time) error
+}
+
+// Bid with its arrival at the replica it reached, so replicas break ties the same way
+type message struct {
+	auction.SignedBid
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+// Satisfied by *crosscheck.Checker
+type StateReceiver interface {
+	ReceiveState(state crosscheck.State)
+}
+
+type Config struct {
+	// Multiaddrs to listen on, e.g. /ip4/0.0.0.0/tcp/9000
+	ListenAddrs []string
+	// Multiaddrs of other replicas, including their /p2p/ peer ID
+	Peers []string
+	// Stable identity of this replica, a random one is generated if nil
+	PrivateKey libp2pcrypto.PrivKey
+}
+
+// Gossips bids between auctioneer replicas so they converge on the same bid set, and thus the same winner
+type Gossip struct {
+	logger  *slog.Logger
+	backend Backend
+	host    host.Host
+	pubsub  *pubsub.PubSub
+	topic   *pubsub.Topic
+	sub     *pubsub.Subscription
+	// Nil until JoinStates
+	states    *pubsub.Topic
+	statesSub *pubsub.Subscription
+
+	mu        sync.Mutex
+	seen      map[common.Hash]struct{}
+	seenOrder []common.Hash
+}
+
+// Joins the bids topic and connects to configured peers. Bids received from peers are submitted to backend.
+func NewGossip(ctx context.Context, logger *slog.Logger, config Config, backend Backend) (*Gossip, error) {
+	opts := []libp2p.Option{libp2p.ListenAddrStrings(config.ListenAddrs...)}
+	if config.PrivateKey != nil {
+		opts = append(opts, libp2p.Identity(config.PrivateKey))
+	}
+	h, err := libp2p.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create libp2p host: %w", err)
+	}
+	g := &Gossip{
+		logger:  logger,
+		backend: backend,
+		host:    h,
+		seen:    make(map[common.Hash]struct{}),
+	}
+	if err := g.join(ctx, config.Peers); err != nil {
+		h.Close()
+		return nil, err
+	}
+	go g.readLoop(ctx)
+	logger.Info("bid gossip started", "peerID", h.ID(), "addrs", h.Addrs())
+	return g, nil
+}
+
+func (g *Gossip) join(ctx context.Context, peers []string) error {
+	ps, err := pubsub.NewGossipSub(ctx, g.host,
+		pubsub.WithMessageIdFn(messageID),
+		pubsub.WithMessageSignaturePolicy(pubsub.StrictSign),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create gossipsub: %w", err)
+	}
+	if err := ps.RegisterTopicValidator(BidsTopic, validate); err != nil {
+		return err
+	}
+	topic, err := ps.Join(BidsTopic)
+	if err != nil {
+		return err
+	}
+	sub, err := topic.Subscribe()
+	if err != nil {
+		return err
+	}
+	g.pubsub, g.topic, g.sub = ps, topic, sub
+	for _, addr := range peers {
+		if err := g.Connect(ctx, addr); err != nil {
+			// Peers may not be up yet, they'll connect to us when they are
+			g.logger.Warn("failed to connect to gossip peer", "addr", addr, "error", err)
+		}
+	}
+	return nil
+}
+
+// Connects to a replica at a multiaddr including its /p2p/ peer ID
+func (g *Gossip) Connect(ctx context.Context, addr string) error {
+	maddr, err := multiaddr.NewMultiaddr(addr)
+	if err != nil {
+		return err
+	}
+	info, err := peer.AddrInfoFromP2pAddr(maddr)
+	if err != nil {
+		return err
+	}
+	return g.host.Connect(ctx, *info)
+}
+
+// Multiaddrs other replicas can connect to this one at
+func (g *Gossip) Addrs() []string {
+	var addrs []string
+	for _, addr := range g.host.Addrs() {
+		addrs = append(addrs, fmt.Sprintf("%s/p2p/%s", addr, g.host.ID()))
+	}
+	return addrs
+}
+
+// Number of replicas this one currently exchanges bids with
+func (g *Gossip) Peers() int {
+	return len(g.topic.ListPeers())
+}
+
+// Gossips a bid accepted locally, which reached this replica at receivedAt, to the other replicas
+func (g *Gossip) Publish(ctx context.Context, bid auction.SignedBid, receivedAt time.Time) error {
+	if !g.markSeen(bid) {
+		return nil
+	}
+	data, err := json.Marshal(message{SignedBid: bid, ReceivedAt: receivedAt})
+	if err != nil {
+		return err
+	}
+	return g.topic.Publish(ctx, data)
+}
+
+// Joins the states topic replicas exchange their state hashes on, see crosscheck. States received from peers are
+// handed to receiver.
+func (g *Gossip) JoinStates(ctx context.Context, receiver StateReceiver) error {
+	if err := g.pubsub.RegisterTopicValidator(StatesTopic, validateState); err != nil {
+		return err
+	}
+	topic, err := g.pubsub.Join(StatesTopic)
+	if err != nil {
+		return err
+	}
+	sub, err := topic.Subscribe()
+	if err != nil {
+		topic.Close()
+		return err
+	}
+	g.states, g.statesSub = topic, sub
+	go g.readStates(ctx, receiver)
+	return nil
+}
+
+// Publishes this replica's state hash for an auction to the other replicas, once joined with JoinStates
+func (g *Gossip) PublishState(ctx context.Context, state crosscheck.State) error {
+	if g.states == nil {
+		return errors.New("states topic not joined")
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return g.states.Publish(ctx, data)
+}
+
+func (g *Gossip) Close() error {
+	if g.states != nil {
+		g.statesSub.Cancel()
+		g.states.Close()
+	}
+	g.sub.Cancel()
+	g.topic.Close()
+	return g.host.Close()
+}
+
+func (g *Gossip) readStates(ctx context.Context, receiver StateReceiver) {
+	for {
+		msg, err := g.statesSub.Next(ctx)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, pubsub.ErrSubscriptionCancelled) {
+				g.logger.Error("state gossip subscription failed", "error", err)
+			}
+			return
+		}
+		if msg.ReceivedFrom == g.host.ID() {
+			continue
+		}
+		receiver.ReceiveState(*msg.ValidatorData.(*crosscheck.State))
+	}
+}
+
+func (g *Gossip) readLoop(ctx context.Context) {
+	for {
+		msg, err := g.sub.Next(ctx)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, pubsub.ErrSubscriptionCancelled) {
+				g.logger.Error("bid gossip subscription failed", "error", err)
+			}
+			return
+		}
+		if msg.ReceivedFrom == g.host.ID() {
+			continue
+		}
+		// Validated by the topic validator before delivery
+		gossiped := msg.ValidatorData.(*message)
+		if !g.markSeen(gossiped.SignedBid) {
+			continue
+		}
+		if err := g.backend.SubmitBidAt(gossiped.SignedBid, gossiped.ReceivedAt); err != nil {
+			g.logger.Debug("gossiped bid rejected", "bid", gossiped.SignedBid, "from", msg.ReceivedFrom, "error", err)
+		}
+	}
+}
+
+// Returns false if the bid was already seen
+func (g *Gossip) markSeen(bid auction.SignedBid) bool {
+	id := crypto.Keccak256Hash(bid.Signature)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.seen[id]; ok {
+		return false
+	}
+	g.seen[id] = struct{}{}
+	g.seenOrder = append(g.seenOrder, id)
+	if len(g.seenOrder) > seenCacheSize {
+		delete(g.seen, g.seenOrder[0])
+		g.seenOrder = g.seenOrder[1:]
+	}
+	return true
+}
+
+// Listener whose locally accepted bids are gossiped to the other replicas, served in place of the listener
+type ReplicatedListener struct {
+	*listener.Listener
+	Gossip *Gossip
+}
+
+func (r *ReplicatedListener) SubmitBid(bid auction.SignedBid) error {
+	return r.submit(bid, r.Arrivals().Now())
+}
+
+// The receipt is signed before the bid is submitted, so it's gossiped with the arrival acknowledged
+func (r *ReplicatedListener) SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error) {
+	receivedAt := r.Arrivals().Now()
+	receipt, err := r.SignReceipt(bid, receivedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.submit(bid, receivedAt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+func (r *ReplicatedListener) submit(bid auction.SignedBid, receivedAt time.Time) error {
+	if err := r.Listener.SubmitBidAt(bid, receivedAt); err != nil {
+		return err
+	}
+	if err := r.Gossip.Publish(context.Background(), bid, receivedAt); err != nil {
+		r.Gossip.logger.Warn("failed to gossip bid", "bid", bid, "error", err)
+	}
+	return nil
+}
+
+// Rejects malformed and badly signed bids at ingestion, penalizing the forwarding peer
+func validate(ctx context.Context, from peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	var gossiped message
+	if err := json.Unmarshal(msg.Data, &gossiped); err != nil || gossiped.ReceivedAt.IsZero() {
+		return pubsub.ValidationReject
+	}
+	if err := gossiped.Validate(); err != nil {
+		return pubsub.ValidationReject
+	}
+	msg.ValidatorData = &gossiped
+	return pubsub.ValidationAccept
+}
+
+func validateState(ctx context.Context, from peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	var state crosscheck.State
+	if err := json.Unmarshal(msg.Data, &state); err != nil || state.Replica == "" {
+		return pubsub.ValidationReject
+	}
+	msg.ValidatorData = &state
+	return pubsub.ValidationAccept
+}
+
+// Bids are identified by signature, so the same bid gossiped by several replicas is delivered once
+func messageID(msg *pb.Message) string {
+	var bid struct {
+		Signature hexutil.Bytes `json:"signature"`
+	}
+	if err := json.Unmarshal(msg.Data, &bid); err != nil || len(bid.Signature) == 0 {
+		return string(crypto.Keccak256(msg.Data))
+	}
+	return string(crypto.Keccak256(bid.Signature))
+}
@@ -0,0 +1,151 @@
+package gossip_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/crosscheck"
+	"blob-preconfs/pkg/gossip"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackend struct {
+	mu        sync.Mutex
+	submitted []auction.ReceivedBid
+}
+
+func (m *mockBackend) SubmitBidAt(bid auction.SignedBid, receivedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.submitted = append(m.submitted, auction.ReceivedBid{SignedBid: bid, ReceivedAt: receivedAt})
+	return nil
+}
+
+func (m *mockBackend) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.submitted = nil
+}
+
+// Gossipsub peers only exchange messages once subscriptions propagate, so probe until from reaches to
+func waitForPropagation(t *testing.T, ctx context.Context, from *gossip.Gossip, to *mockBackend) {
+	pk, _ := crypto.GenerateKey()
+	amount := int64(1)
+	require.Eventually(t, func() bool {
+		amount++
+		require.NoError(t, from.Publish(ctx, *auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(1), pk), time.Now()))
+		return to.count() > 0
+	}, 10*time.Second, 100*time.Millisecond)
+	// Let earlier probes drain
+	time.Sleep(200 * time.Millisecond)
+	to.reset()
+}
+
+func (m *mockBackend) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.submitted)
+}
+
+func startReplica(t *testing.T, ctx context.Context, peers []string) (*gossip.Gossip, *mockBackend) {
+	backend := &mockBackend{}
+	g, err := gossip.NewGossip(ctx, slog.Default(), gossip.Config{
+		ListenAddrs: []string{"/ip4/127.0.0.1/tcp/0"},
+		Peers:       peers,
+	}, backend)
+	require.NoError(t, err)
+	t.Cleanup(func() { g.Close() })
+	return g, backend
+}
+
+func TestGossipBetweenReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a, backendA := startReplica(t, ctx, nil)
+	b, backendB := startReplica(t, ctx, a.Addrs())
+	c, backendC := startReplica(t, ctx, a.Addrs())
+	require.Eventually(t, func() bool { return a.Peers() == 2 && b.Peers() == 1 && c.Peers() == 1 }, 5*time.Second, 50*time.Millisecond)
+	waitForPropagation(t, ctx, b, backendA)
+	waitForPropagation(t, ctx, c, backendA)
+	backendB.reset()
+	backendC.reset()
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	receivedAt := time.Unix(1700000000, 123456789)
+	require.NoError(t, b.Publish(ctx, *bid, receivedAt))
+	// Receiving the same bid locally and from a peer, or publishing it twice, delivers it once
+	require.NoError(t, c.Publish(ctx, *bid, receivedAt.Add(time.Millisecond)))
+	require.NoError(t, b.Publish(ctx, *bid, receivedAt))
+
+	require.Eventually(t, func() bool { return backendA.count() == 1 }, 5*time.Second, 50*time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, 1, backendA.count())
+	require.Equal(t, *bid, backendA.submitted[0].SignedBid)
+	require.True(t, receivedAt.Equal(backendA.submitted[0].ReceivedAt), "submitted with its arrival at the replica it reached")
+	require.Zero(t, backendB.count()+backendC.count(), "replicas don't resubmit bids they published")
+}
+
+func TestGossipRejectsInvalidBids(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a, backendA := startReplica(t, ctx, nil)
+	b, _ := startReplica(t, ctx, a.Addrs())
+	require.Eventually(t, func() bool { return a.Peers() == 1 && b.Peers() == 1 }, 5*time.Second, 50*time.Millisecond)
+	waitForPropagation(t, ctx, b, backendA)
+
+	pk, _ := crypto.GenerateKey()
+	tampered := *auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	tampered.AmountWei = big.NewInt(44)
+	require.Error(t, b.Publish(ctx, tampered, time.Now()))
+	time.Sleep(500 * time.Millisecond)
+	require.Zero(t, backendA.count())
+}
+
+type mockReceiver struct {
+	mu       sync.Mutex
+	received []crosscheck.State
+}
+
+func (m *mockReceiver) ReceiveState(state crosscheck.State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.received = append(m.received, state)
+}
+
+func (m *mockReceiver) last() (crosscheck.State, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.received) == 0 {
+		return crosscheck.State{}, false
+	}
+	return m.received[len(m.received)-1], true
+}
+
+func TestGossipStates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a, _ := startReplica(t, ctx, nil)
+	b, _ := startReplica(t, ctx, a.Addrs())
+	require.Error(t, b.PublishState(ctx, crosscheck.State{Replica: "b", L1Block: 1}), "not joined yet")
+	receiverA, receiverB := &mockReceiver{}, &mockReceiver{}
+	require.NoError(t, a.JoinStates(ctx, receiverA))
+	require.NoError(t, b.JoinStates(ctx, receiverB))
+
+	state := crosscheck.State{Replica: "b", L1Block: 100, Hash: common.HexToHash("0x01")}
+	require.Eventually(t, func() bool {
+		require.NoError(t, b.PublishState(ctx, state))
+		received, ok := receiverA.last()
+		return ok && received == state
+	}, 10*time.Second, 100*time.Millisecond)
+	_, ok := receiverB.last()
+	require.False(t, ok, "replicas don't receive their own states")
+}
@@ -0,0 +1,431 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Unsigned 64 bit integer scalar, accepted as a number or a decimal or hex string
+type Long uint64
+
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		if input < 0 {
+			return fmt.Errorf("negative Long %d", input)
+		}
+		*l = Long(input)
+	case float64:
+		if input < 0 || input != float64(uint64(input)) {
+			return fmt.Errorf("invalid Long %v", input)
+		}
+		*l = Long(input)
+	case string:
+		value, err := strconv.ParseUint(input, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Long %q", input)
+		}
+		*l = Long(value)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return nil
+}
+
+func (l Long) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatUint(uint64(l), 10)), nil
+}
+
+// Arbitrary precision integer scalar, serialized as a decimal string
+type BigInt big.Int
+
+func (BigInt) ImplementsGraphQLType(name string) bool {
+	return name == "BigInt"
+}
+
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for BigInt", input)
+	}
+	if _, ok := (*big.Int)(b).SetString(s, 0); !ok {
+		return fmt.Errorf("invalid BigInt %q", s)
+	}
+	return nil
+}
+
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*big.Int)(&b).String())
+}
+
+type rootResolver struct {
+	history HistoryBackend
+}
+
+func (r *rootResolver) Auction(args struct{ L1Block Long }) (*auctionResolver, error) {
+	return r.findAuction(uint64(args.L1Block))
+}
+
+func (r *rootResolver) Auctions(args struct {
+	FromBlock *Long
+	ToBlock   *Long
+	Winner    *string
+	First     *int32
+	After     *string
+}) (*auctionConnection, error) {
+	winner, err := parseOptionalAddress(args.Winner)
+	if err != nil {
+		return nil, err
+	}
+	return r.listAuctions(store.AuctionFilter{Blocks: blockRange(args.FromBlock, args.ToBlock), Winner: winner}, page(args.First, args.After))
+}
+
+func (r *rootResolver) Bids(args struct {
+	FromBlock *Long
+	ToBlock   *Long
+	Relay     *string
+	First     *int32
+	After     *string
+}) (*bidConnection, error) {
+	relay, err := parseOptionalAddress(args.Relay)
+	if err != nil {
+		return nil, err
+	}
+	return r.listBids(store.BidFilter{Blocks: blockRange(args.FromBlock, args.ToBlock), Relay: relay}, page(args.First, args.After))
+}
+
+func (r *rootResolver) Relay(args struct{ Address string }) (*relayResolver, error) {
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &relayResolver{root: r, address: address}, nil
+}
+
+func (r *rootResolver) Commitments(args struct {
+	FromBlock *Long
+	ToBlock   *Long
+	State     *string
+	First     *int32
+	After     *string
+}) (*commitmentConnection, error) {
+	filter := store.CommitmentFilter{Blocks: blockRange(args.FromBlock, args.ToBlock)}
+	if args.State != nil {
+		state, err := commitment.ParseState(*args.State)
+		if err != nil {
+			return nil, err
+		}
+		filter.State = &state
+	}
+	records, next, err := r.history.ListCommitments(filter, page(args.First, args.After))
+	if err != nil {
+		return nil, err
+	}
+	connection := &commitmentConnection{next: next}
+	for _, record := range records {
+		connection.nodes = append(connection.nodes, &commitmentResolver{record: record})
+	}
+	return connection, nil
+}
+
+func (r *rootResolver) findAuction(l1Block uint64) (*auctionResolver, error) {
+	// To of 0 is unbounded, block 0 never has an auction
+	if l1Block == 0 {
+		return nil, nil
+	}
+	records, _, err := r.history.ListAuctions(store.AuctionFilter{Blocks: store.BlockRange{From: l1Block, To: l1Block}}, store.Page{Limit: 1})
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return &auctionResolver{root: r, record: records[0]}, nil
+}
+
+func (r *rootResolver) listAuctions(filter store.AuctionFilter, page store.Page) (*auctionConnection, error) {
+	records, next, err := r.history.ListAuctions(filter, page)
+	if err != nil {
+		return nil, err
+	}
+	connection := &auctionConnection{next: next}
+	for _, record := range records {
+		connection.nodes = append(connection.nodes, &auctionResolver{root: r, record: record})
+	}
+	return connection, nil
+}
+
+func (r *rootResolver) listBids(filter store.BidFilter, page store.Page) (*bidConnection, error) {
+	records, next, err := r.history.ListBids(filter, page)
+	if err != nil {
+		return nil, err
+	}
+	connection := &bidConnection{next: next}
+	for _, record := range records {
+		connection.nodes = append(connection.nodes, &bidResolver{root: r, record: record})
+	}
+	return connection, nil
+}
+
+type auctionResolver struct {
+	root   *rootResolver
+	record store.AuctionRecord
+}
+
+func (a *auctionResolver) L1Block() Long {
+	return Long(a.record.L1Block)
+}
+
+func (a *auctionResolver) ClosedAt() string {
+	return formatTime(a.record.ClosedAt)
+}
+
+func (a *auctionResolver) Winner() *bidResolver {
+	if a.record.Winner == nil {
+		return nil
+	}
+	// The winning bid's ReceivedAt is only known to the bid history
+	return &bidResolver{root: a.root, record: store.BidRecord{Bid: *a.record.Winner}}
+}
+
+func (a *auctionResolver) Bids(args struct {
+	First *int32
+	After *string
+}) (*bidConnection, error) {
+	blocks := store.BlockRange{From: a.record.L1Block, To: a.record.L1Block}
+	return a.root.listBids(store.BidFilter{Blocks: blocks}, page(args.First, args.After))
+}
+
+type bidResolver struct {
+	root   *rootResolver
+	record store.BidRecord
+}
+
+func (b *bidResolver) Relay() *relayResolver {
+	return &relayResolver{root: b.root, address: b.record.Bid.Address}
+}
+
+func (b *bidResolver) AmountWei() BigInt {
+	return BigInt(*b.record.Bid.AmountWei)
+}
+
+func (b *bidResolver) L1Block() Long {
+	return Long(b.record.Bid.L1Block.Uint64())
+}
+
+func (b *bidResolver) Signature() string {
+	return b.record.Bid.Signature.String()
+}
+
+func (b *bidResolver) ReceivedAt() string {
+	return formatTime(b.record.ReceivedAt)
+}
+
+func (b *bidResolver) Auction() (*auctionResolver, error) {
+	return b.root.findAuction(b.record.Bid.L1Block.Uint64())
+}
+
+func (b *bidResolver) Won() (bool, error) {
+	result, err := b.Auction()
+	if err != nil || result == nil || result.record.Winner == nil {
+		return false, err
+	}
+	return sameBid(*result.record.Winner, b.record.Bid), nil
+}
+
+type relayResolver struct {
+	root    *rootResolver
+	address common.Address
+}
+
+func (r *relayResolver) Address() string {
+	return r.address.Hex()
+}
+
+func (r *relayResolver) Bids(args struct {
+	FromBlock *Long
+	ToBlock   *Long
+	First     *int32
+	After     *string
+}) (*bidConnection, error) {
+	filter := store.BidFilter{Blocks: blockRange(args.FromBlock, args.ToBlock), Relay: &r.address}
+	return r.root.listBids(filter, page(args.First, args.After))
+}
+
+func (r *relayResolver) Wins(args struct {
+	FromBlock *Long
+	ToBlock   *Long
+	First     *int32
+	After     *string
+}) (*auctionConnection, error) {
+	filter := store.AuctionFilter{Blocks: blockRange(args.FromBlock, args.ToBlock), Winner: &r.address}
+	return r.root.listAuctions(filter, page(args.First, args.After))
+}
+
+type commitmentResolver struct {
+	record store.CommitmentRecord
+}
+
+func (c *commitmentResolver) Hash() string {
+	return c.record.Commitment.Hash().Hex()
+}
+
+func (c *commitmentResolver) RequestHash() string {
+	return c.record.Commitment.RequestHash.Hex()
+}
+
+func (c *commitmentResolver) VersionedHashes() []string {
+	hashes := make([]string, len(c.record.Commitment.VersionedHashes))
+	for i, hash := range c.record.Commitment.VersionedHashes {
+		hashes[i] = hash.Hex()
+	}
+	return hashes
+}
+
+func (c *commitmentResolver) Atomic() bool {
+	return c.record.Commitment.Atomic
+}
+
+func (c *commitmentResolver) TargetBlock() Long {
+	return Long(c.record.Commitment.TargetBlock.Uint64())
+}
+
+func (c *commitmentResolver) ExpiryBlock() Long {
+	return Long(c.record.Commitment.ExpiryBlock.Uint64())
+}
+
+func (c *commitmentResolver) FeeWei() BigInt {
+	return BigInt(*c.record.Commitment.FeeWei)
+}
+
+func (c *commitmentResolver) RenewalOf() *string {
+	if c.record.Commitment.RenewalOf == (common.Hash{}) {
+		return nil
+	}
+	hash := c.record.Commitment.RenewalOf.Hex()
+	return &hash
+}
+
+func (c *commitmentResolver) Escalations() Long {
+	return Long(c.record.Commitment.Escalations)
+}
+
+func (c *commitmentResolver) Committer() string {
+	return c.record.Commitment.Committer.Hex()
+}
+
+func (c *commitmentResolver) State() string {
+	return c.record.State.String()
+}
+
+func (c *commitmentResolver) UpdatedAt() string {
+	return formatTime(c.record.UpdatedAt)
+}
+
+type auctionConnection struct {
+	nodes []*auctionResolver
+	next  string
+}
+
+func (c *auctionConnection) Nodes() []*auctionResolver {
+	return c.nodes
+}
+
+func (c *auctionConnection) NextCursor() *string {
+	return optionalCursor(c.next)
+}
+
+type bidConnection struct {
+	nodes []*bidResolver
+	next  string
+}
+
+func (c *bidConnection) Nodes() []*bidResolver {
+	return c.nodes
+}
+
+func (c *bidConnection) NextCursor() *string {
+	return optionalCursor(c.next)
+}
+
+type commitmentConnection struct {
+	nodes []*commitmentResolver
+	next  string
+}
+
+func (c *commitmentConnection) Nodes() []*commitmentResolver {
+	return c.nodes
+}
+
+func (c *commitmentConnection) NextCursor() *string {
+	return optionalCursor(c.next)
+}
+
+func blockRange(from *Long, to *Long) store.BlockRange {
+	var blocks store.BlockRange
+	if from != nil {
+		blocks.From = uint64(*from)
+	}
+	if to != nil {
+		blocks.To = uint64(*to)
+	}
+	return blocks
+}
+
+func page(first *int32, after *string) store.Page {
+	var p store.Page
+	if first != nil {
+		p.Limit = int(*first)
+	}
+	if after != nil {
+		p.Cursor = *after
+	}
+	return p
+}
+
+func optionalCursor(next string) *string {
+	if next == "" {
+		return nil
+	}
+	return &next
+}
+
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address %q", s)
+	}
+	return common.HexToAddress(s), nil
+}
+
+func parseOptionalAddress(s *string) (*common.Address, error) {
+	if s == nil {
+		return nil, nil
+	}
+	address, err := parseAddress(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func sameBid(a auction.SignedBid, b auction.SignedBid) bool {
+	return bytes.Equal(a.Signature, b.Signature)
+}
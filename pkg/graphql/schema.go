@@ -0,0 +1,74 @@
+package graphql
+
+// Block numbers are Long as GraphQL Int is 32 bit, wei amounts are decimal BigInt strings
+const schema = `
+schema {
+	query: Query
+}
+
+scalar Long
+scalar BigInt
+
+type Query {
+	auction(l1Block: Long!): Auction
+	auctions(fromBlock: Long, toBlock: Long, winner: String, first: Int, after: String): AuctionConnection!
+	bids(fromBlock: Long, toBlock: Long, relay: String, first: Int, after: String): BidConnection!
+	relay(address: String!): Relay!
+	commitments(fromBlock: Long, toBlock: Long, state: String, first: Int, after: String): CommitmentConnection!
+}
+
+type Auction {
+	l1Block: Long!
+	closedAt: String!
+	# Null if the auction closed with no winner
+	winner: Bid
+	bids(first: Int, after: String): BidConnection!
+}
+
+type Bid {
+	relay: Relay!
+	amountWei: BigInt!
+	l1Block: Long!
+	signature: String!
+	receivedAt: String!
+	# Null while the auction is open
+	auction: Auction
+	won: Boolean!
+}
+
+type Relay {
+	address: String!
+	bids(fromBlock: Long, toBlock: Long, first: Int, after: String): BidConnection!
+	wins(fromBlock: Long, toBlock: Long, first: Int, after: String): AuctionConnection!
+}
+
+type Commitment {
+	hash: String!
+	requestHash: String!
+	versionedHashes: [String!]!
+	atomic: Boolean!
+	targetBlock: Long!
+	expiryBlock: Long!
+	feeWei: BigInt!
+	renewalOf: String
+	escalations: Long!
+	committer: String!
+	state: String!
+	updatedAt: String!
+}
+
+type AuctionConnection {
+	nodes: [Auction!]!
+	nextCursor: String
+}
+
+type BidConnection {
+	nodes: [Bid!]!
+	nextCursor: String
+}
+
+type CommitmentConnection {
+	nodes: [Commitment!]!
+	nextCursor: String
+}
+`
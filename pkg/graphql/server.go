@@ -0,0 +1,107 @@
+package graphql
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"blob-preconfs/pkg/store"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	maxRequestSize = 64 * 1024
+	// Bounds nested queries such as relay → bids → auction → bids
+	maxQueryDepth = 8
+)
+
+// Satisfied by store.Store implementations
+type HistoryBackend interface {
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+	ListBids(filter store.BidFilter, page store.Page) ([]store.BidRecord, string, error)
+	ListCommitments(filter store.CommitmentFilter, page store.Page) ([]store.CommitmentRecord, string, error)
+}
+
+type Server struct {
+	logger     *slog.Logger
+	schema     *gql.Schema
+	httpServer *http.Server
+	listener   net.Listener
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Serves GraphQL queries over auction history at POST /graphql. Served over TLS if tlsConfig is non-nil, see tlsconfig.
+func NewServer(logger *slog.Logger, addr string, history HistoryBackend, tlsConfig *tls.Config) *Server {
+	s := &Server{
+		logger: logger,
+		schema: gql.MustParseSchema(schema, &rootResolver{history: history}, gql.MaxDepth(maxQueryDepth)),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", s.handleQuery)
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		TLSConfig:         tlsConfig,
+	}
+	return s
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	if s.httpServer.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.httpServer.TLSConfig)
+	}
+	s.listener = listener
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("graphql server failed", "error", err)
+		}
+	}()
+	s.logger.Info("graphql server started", "addr", listener.Addr(), "tls", s.httpServer.TLSConfig != nil)
+	return nil
+}
+
+// Address the server is listening on, useful when started on port 0
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stops accepting new requests and waits for in-flight ones until ctx is done
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.logger.Info("graphql server stopped")
+	return err
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	response := s.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Warn("failed to write graphql response", "error", err)
+	}
+}
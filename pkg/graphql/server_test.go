@@ -0,0 +1,155 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/graphql"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func startServer(t *testing.T, history graphql.HistoryBackend) func(query string, variables map[string]any) response {
+	server := graphql.NewServer(slog.Default(), "127.0.0.1:0", history, nil)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	url := "http://" + server.Addr().String() + "/graphql"
+	return func(query string, variables map[string]any) response {
+		body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+		require.NoError(t, err)
+		resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var got response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		return got
+	}
+}
+
+func TestNestedRelayQuery(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	relay1 := crypto.PubkeyToAddress(pk1.PublicKey)
+	now := time.Now()
+	won := auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk1)
+	lost := auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(101), pk1)
+	winner101 := auction.MustCreateSignedBid(big.NewInt(45), big.NewInt(101), pk2)
+	open := auction.MustCreateSignedBid(big.NewInt(10), big.NewInt(102), pk1)
+	for _, bid := range []*auction.SignedBid{won, lost, winner101, open} {
+		require.NoError(t, history.SaveBid(*bid, now))
+	}
+	require.NoError(t, history.SaveAuctionResult(100, won, now))
+	require.NoError(t, history.SaveAuctionResult(101, winner101, now))
+	query := startServer(t, history)
+
+	got := query(`query($relay: String!) {
+		relay(address: $relay) {
+			bids {
+				nodes { amountWei l1Block won auction { l1Block winner { relay { address } } } }
+			}
+			wins { nodes { l1Block } }
+		}
+	}`, map[string]any{"relay": relay1.Hex()})
+	require.Empty(t, got.Errors)
+	require.JSONEq(t, `{"relay": {
+		"bids": {"nodes": [
+			{"amountWei": "50", "l1Block": 100, "won": true, "auction": {"l1Block": 100, "winner": {"relay": {"address": "`+relay1.Hex()+`"}}}},
+			{"amountWei": "40", "l1Block": 101, "won": false, "auction": {"l1Block": 101, "winner": {"relay": {"address": "`+crypto.PubkeyToAddress(pk2.PublicKey).Hex()+`"}}}},
+			{"amountWei": "10", "l1Block": 102, "won": false, "auction": null}
+		]},
+		"wins": {"nodes": [{"l1Block": 100}]}
+	}}`, string(got.Data))
+
+	got = query(`{ auction(l1Block: "101") { bids { nodes { amountWei } } } missing: auction(l1Block: 99) { l1Block } }`, nil)
+	require.Empty(t, got.Errors)
+	require.JSONEq(t, `{"auction": {"bids": {"nodes": [{"amountWei": "40"}, {"amountWei": "45"}]}}, "missing": null}`, string(got.Data))
+}
+
+func TestPagination(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	for block := int64(100); block < 105; block++ {
+		require.NoError(t, history.SaveBid(*auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(block), pk), time.Now()))
+	}
+	query := startServer(t, history)
+
+	var blocks []uint64
+	var after *string
+	for {
+		got := query(`query($after: String) { bids(fromBlock: 101, first: 2, after: $after) { nodes { l1Block } nextCursor } }`, map[string]any{"after": after})
+		require.Empty(t, got.Errors)
+		var data struct {
+			Bids struct {
+				Nodes []struct {
+					L1Block uint64 `json:"l1Block"`
+				} `json:"nodes"`
+				NextCursor *string `json:"nextCursor"`
+			} `json:"bids"`
+		}
+		require.NoError(t, json.Unmarshal(got.Data, &data))
+		for _, node := range data.Bids.Nodes {
+			blocks = append(blocks, node.L1Block)
+		}
+		if after = data.Bids.NextCursor; after == nil {
+			break
+		}
+	}
+	require.Equal(t, []uint64{101, 102, 103, 104}, blocks)
+
+	got := query(`{ bids(after: "invalid") { nodes { l1Block } } }`, nil)
+	require.Len(t, got.Errors, 1)
+	require.Contains(t, got.Errors[0].Message, store.ErrInvalidCursor.Error())
+	got = query(`{ relay(address: "0x1234") { address } }`, nil)
+	require.Len(t, got.Errors, 1)
+}
+
+func TestCommitments(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(102),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	require.NoError(t, history.SaveCommitment(*c, commitment.StateFulfilled))
+	query := startServer(t, history)
+
+	got := query(`{ commitments(state: "fulfilled") { nodes { hash targetBlock expiryBlock feeWei state renewalOf versionedHashes } } }`, nil)
+	require.Empty(t, got.Errors)
+	require.JSONEq(t, `{"commitments": {"nodes": [{
+		"hash": "`+c.Hash().Hex()+`", "targetBlock": 100, "expiryBlock": 102, "feeWei": "5",
+		"state": "fulfilled", "renewalOf": null, "versionedHashes": ["`+common.Hash{0x01}.Hex()+`"]
+	}]}}`, string(got.Data))
+
+	got = query(`{ commitments(state: "missed") { nodes { hash } } }`, nil)
+	require.Empty(t, got.Errors)
+	require.JSONEq(t, `{"commitments": {"nodes": []}}`, string(got.Data))
+}
+
+func TestQueryDepthLimited(t *testing.T) {
+	query := startServer(t, store.NewMemoryStore())
+	got := query(`{ bids { nodes { auction { bids { nodes { auction { bids { nodes { auction { l1Block } } } } } } } } } }`, nil)
+	require.NotEmpty(t, got.Errors)
+}
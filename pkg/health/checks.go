@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"blob-preconfs/pkg/store"
+)
+
+// Satisfied by *ethclient.Client
+type EthClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Satisfied by *listener.Listener
+type Auctions interface {
+	LastAuctionAt() time.Time
+	Paused() bool
+}
+
+// Satisfied by relay registries that sync registrations from the settlement layer
+type Registry interface {
+	LastSynced() time.Time
+}
+
+// Satisfied by store.Store
+type AuctionHistory interface {
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+}
+
+// Fails if the node doesn't answer eth_blockNumber
+func RPC(client EthClient) Check {
+	return func(ctx context.Context) error {
+		if _, err := client.BlockNumber(ctx); err != nil {
+			return fmt.Errorf("rpc unreachable: %w", err)
+		}
+		return nil
+	}
+}
+
+// Fails if no auction closed within maxAge, e.g. because block processing is stuck. Passes while
+// auctions are paused, and measures from creation until the first auction closes.
+func AuctionRecency(auctions Auctions, maxAge time.Duration) Check {
+	startedAt := time.Now()
+	return func(ctx context.Context) error {
+		if auctions.Paused() {
+			return nil
+		}
+		last := auctions.LastAuctionAt()
+		if last.IsZero() {
+			last = startedAt
+		}
+		if age := time.Since(last); age > maxAge {
+			return fmt.Errorf("no auction closed for %s, max %s", age.Round(time.Second), maxAge)
+		}
+		return nil
+	}
+}
+
+// Fails if registrations weren't synced from the settlement layer within maxAge, so bids may be
+// accepted from deregistered relays or rejected from new ones
+func RegistryFreshness(registry Registry, maxAge time.Duration) Check {
+	return func(ctx context.Context) error {
+		last := registry.LastSynced()
+		if last.IsZero() {
+			return fmt.Errorf("registry never synced")
+		}
+		if age := time.Since(last); age > maxAge {
+			return fmt.Errorf("registry last synced %s ago, max %s", age.Round(time.Second), maxAge)
+		}
+		return nil
+	}
+}
+
+// Fails if more than maxDepth won auctions are waiting to be settled. maxDepth must be below store.MaxPageLimit.
+func SettlementQueue(history AuctionHistory, maxDepth int) Check {
+	return func(ctx context.Context) error {
+		unsettled, _, err := history.ListAuctions(store.AuctionFilter{Unsettled: true}, store.Page{Limit: min(maxDepth+1, store.MaxPageLimit)})
+		if err != nil {
+			return fmt.Errorf("failed to list unsettled auctions: %w", err)
+		}
+		if len(unsettled) > maxDepth {
+			return fmt.Errorf("more than %d auctions awaiting settlement", maxDepth)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/health"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var _ health.Auctions = (*listener.Listener)(nil)
+var _ health.AuctionHistory = (store.Store)(nil)
+
+type ethClientFunc func(ctx context.Context) (uint64, error)
+
+func (f ethClientFunc) BlockNumber(ctx context.Context) (uint64, error) {
+	return f(ctx)
+}
+
+func TestRPC(t *testing.T) {
+	require.NoError(t, health.RPC(ethClientFunc(func(ctx context.Context) (uint64, error) { return 100, nil }))(context.Background()))
+	err := health.RPC(ethClientFunc(func(ctx context.Context) (uint64, error) { return 0, errors.New("connection refused") }))(context.Background())
+	require.ErrorContains(t, err, "connection refused")
+}
+
+type mockAuctions struct {
+	last   time.Time
+	paused bool
+}
+
+func (m *mockAuctions) LastAuctionAt() time.Time { return m.last }
+func (m *mockAuctions) Paused() bool             { return m.paused }
+
+func TestAuctionRecency(t *testing.T) {
+	auctions := &mockAuctions{}
+	check := health.AuctionRecency(auctions, time.Minute)
+	require.NoError(t, check(context.Background()), "grace period before the first auction")
+	auctions.last = time.Now().Add(-2 * time.Minute)
+	require.ErrorContains(t, check(context.Background()), "no auction closed for 2m0s")
+	auctions.paused = true
+	require.NoError(t, check(context.Background()))
+	auctions.paused, auctions.last = false, time.Now()
+	require.NoError(t, check(context.Background()))
+}
+
+type syncedFunc func() time.Time
+
+func (f syncedFunc) LastSynced() time.Time { return f() }
+
+func TestRegistryFreshness(t *testing.T) {
+	require.ErrorContains(t, health.RegistryFreshness(syncedFunc(func() time.Time { return time.Time{} }), time.Minute)(context.Background()), "never synced")
+	require.Error(t, health.RegistryFreshness(syncedFunc(func() time.Time { return time.Now().Add(-time.Hour) }), time.Minute)(context.Background()))
+	require.NoError(t, health.RegistryFreshness(syncedFunc(time.Now), time.Minute)(context.Background()))
+}
+
+func TestSettlementQueue(t *testing.T) {
+	s := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	check := health.SettlementQueue(s, 1)
+	require.NoError(t, s.SaveAuctionResult(100, auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(100), pk), time.Now()))
+	require.NoError(t, check(context.Background()))
+	require.NoError(t, s.SaveAuctionResult(101, auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(101), pk), time.Now()))
+	require.ErrorContains(t, check(context.Background()), "more than 1 auctions awaiting settlement")
+}
@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Bounds how long a probe waits for its checks, below typical orchestrator probe timeouts
+const checkTimeout = 3 * time.Second
+
+// Returns an error describing why the component is unhealthy, or nil
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Serves liveness and readiness probes from registered checks
+type Checker struct {
+	logger *slog.Logger
+
+	mu        sync.RWMutex // Protects access to fields below
+	liveness  []namedCheck
+	readiness []namedCheck
+}
+
+type Report struct {
+	Healthy bool `json:"healthy"`
+	// Check name to "ok" or the failure
+	Checks map[string]string `json:"checks"`
+}
+
+func NewChecker(logger *slog.Logger) *Checker {
+	return &Checker{logger: logger}
+}
+
+// Failing liveness checks mean the process is stuck and should be restarted. They're also readiness checks.
+func (c *Checker) AddLiveness(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveness = append(c.liveness, namedCheck{name, check})
+}
+
+// Failing readiness checks mean traffic should be held, e.g. until dependencies recover
+func (c *Checker) AddReadiness(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readiness = append(c.readiness, namedCheck{name, check})
+}
+
+// Runs liveness checks concurrently
+func (c *Checker) Live(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := c.liveness
+	c.mu.RUnlock()
+	return run(ctx, checks)
+}
+
+// Runs liveness and readiness checks concurrently
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append(append([]namedCheck{}, c.liveness...), c.readiness...)
+	c.mu.RUnlock()
+	return run(ctx, checks)
+}
+
+func run(ctx context.Context, checks []namedCheck) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	report := Report{Healthy: true, Checks: make(map[string]string, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+			err := c.check(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Healthy = false
+				report.Checks[c.name] = err.Error()
+				return
+			}
+			report.Checks[c.name] = "ok"
+		}(c)
+	}
+	wg.Wait()
+	return report
+}
+
+// Serves GET /healthz and /readyz, 200 if healthy and 503 otherwise, with the report
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", c.handle("liveness", c.Live))
+	mux.HandleFunc("/readyz", c.handle("readiness", c.Ready))
+}
+
+func (c *Checker) handle(probe string, run func(ctx context.Context) Report) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report := run(r.Context())
+		status := http.StatusOK
+		if !report.Healthy {
+			status = http.StatusServiceUnavailable
+			c.logger.Warn("health probe failing", "probe", probe, "checks", report.Checks)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,59 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blob-preconfs/pkg/health"
+
+	"github.com/stretchr/testify/require"
+)
+
+func probe(t *testing.T, handler http.Handler, path string) (int, health.Report) {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+func TestProbes(t *testing.T) {
+	checker := health.NewChecker(slog.Default())
+	var rpcErr error
+	checker.AddLiveness("auctions", func(ctx context.Context) error { return nil })
+	checker.AddReadiness("rpc", func(ctx context.Context) error { return rpcErr })
+	mux := http.NewServeMux()
+	checker.Register(mux)
+
+	code, report := probe(t, mux, "/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.Report{Healthy: true, Checks: map[string]string{"auctions": "ok", "rpc": "ok"}}, report)
+
+	rpcErr = errors.New("connection refused")
+	code, report = probe(t, mux, "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.False(t, report.Healthy)
+	require.Equal(t, "connection refused", report.Checks["rpc"])
+
+	code, report = probe(t, mux, "/healthz")
+	require.Equal(t, http.StatusOK, code, "readiness failures don't fail liveness")
+	require.Equal(t, map[string]string{"auctions": "ok"}, report.Checks)
+}
+
+func TestProbeTimesOut(t *testing.T) {
+	checker := health.NewChecker(slog.Default())
+	checker.AddLiveness("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	mux := http.NewServeMux()
+	checker.Register(mux)
+	code, report := probe(t, mux, "/healthz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks["stuck"])
+}
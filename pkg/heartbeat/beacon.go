@@ -0,0 +1,152 @@
+package heartbeat
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/crosscheck"
+	"blob-preconfs/pkg/version"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Config struct {
+	// Beacon chain genesis slots are counted from, the Unix epoch if zero
+	GenesisTime time.Time
+	// 12s if zero
+	SlotTime time.Duration
+}
+
+// Satisfied by *listener.Listener
+type Listener interface {
+	Ranking(l1Block uint64) []auction.SignedBid
+	Paused() bool
+	PublishEvent(ev auction.Event)
+}
+
+// Satisfied by *commitment.Coordinator
+type Commitments interface {
+	ForBlock(targetBlock *big.Int) []commitment.Commitment
+}
+
+// Publishes a signed heartbeat at the start of every slot on the listener's event feed, with the state hash of
+// the latest auction closed, for relays and watchers to prove the auctioneer's liveness with (see Monitor)
+type Beacon struct {
+	logger      *slog.Logger
+	config      Config
+	key         *ecdsa.PrivateKey
+	listener    Listener
+	commitments Commitments
+
+	mu sync.Mutex // Protects access to fields below
+	// Latest auction closed
+	l1Block   uint64
+	stateHash common.Hash
+	latest    *auction.Heartbeat
+}
+
+func NewBeacon(logger *slog.Logger, config Config, key *ecdsa.PrivateKey, listener Listener, commitments Commitments) *Beacon {
+	if config.SlotTime <= 0 {
+		config.SlotTime = 12 * time.Second
+	}
+	return &Beacon{logger: logger, config: config, key: key, listener: listener, commitments: commitments}
+}
+
+// Records the state of each auction as it closes, from the listener's event feed (see Listener.SubscribeEvents),
+// until ctx is done or events is closed
+func (b *Beacon) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type != auction.EventAuctionClosed {
+				continue
+			}
+			l1Block := ev.L1Block.Uint64()
+			hash := crosscheck.Hash(l1Block, b.listener.Ranking(l1Block), ev.Bid, b.commitments.ForBlock(ev.L1Block))
+			b.mu.Lock()
+			if l1Block >= b.l1Block {
+				b.l1Block, b.stateHash = l1Block, hash
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// Beats at the start of every slot until ctx is done
+func (b *Beacon) Start(ctx context.Context) {
+	go func() {
+		for {
+			slot := b.Slot(time.Now()) + 1
+			timer := time.NewTimer(time.Until(b.slotStart(slot)))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			if _, err := b.Beat(slot); err != nil {
+				b.logger.Error("failed to sign heartbeat", "slot", slot, "error", err)
+			}
+		}
+	}()
+}
+
+// Slot in progress at t
+func (b *Beacon) Slot(t time.Time) uint64 {
+	since := t.Sub(b.config.GenesisTime)
+	if b.config.GenesisTime.IsZero() {
+		since = time.Duration(t.UnixNano())
+	}
+	if since < 0 {
+		return 0
+	}
+	return uint64(since / b.config.SlotTime)
+}
+
+func (b *Beacon) slotStart(slot uint64) time.Time {
+	offset := time.Duration(slot) * b.config.SlotTime
+	if b.config.GenesisTime.IsZero() {
+		return time.Unix(0, 0).Add(offset)
+	}
+	return b.config.GenesisTime.Add(offset)
+}
+
+// Signs and publishes the slot's heartbeat
+func (b *Beacon) Beat(slot uint64) (*auction.Heartbeat, error) {
+	b.mu.Lock()
+	l1Block, stateHash := b.l1Block, b.stateHash
+	b.mu.Unlock()
+	now := time.Now()
+	h, err := auction.CreateSignedHeartbeat(slot, l1Block, stateHash, b.listener.Paused(), version.Get().Version, now, b.key)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.latest = h
+	b.mu.Unlock()
+	b.listener.PublishEvent(auction.Event{
+		Type:      auction.EventHeartbeat,
+		L1Block:   new(big.Int).SetUint64(l1Block),
+		Timestamp: now,
+		Heartbeat: h,
+	})
+	return h, nil
+}
+
+// Last heartbeat published, nil before the first
+func (b *Beacon) Latest() *auction.Heartbeat {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.latest
+}
@@ -0,0 +1,107 @@
+package heartbeat_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/crosscheck"
+	"blob-preconfs/pkg/heartbeat"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockListener struct {
+	ranking []auction.SignedBid
+	paused  bool
+
+	mu     sync.Mutex
+	events []auction.Event
+}
+
+func (m *mockListener) Ranking(l1Block uint64) []auction.SignedBid {
+	return m.ranking
+}
+
+func (m *mockListener) Paused() bool {
+	return m.paused
+}
+
+func (m *mockListener) PublishEvent(ev auction.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, ev)
+}
+
+func (m *mockListener) published() []auction.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]auction.Event(nil), m.events...)
+}
+
+type noCommitments struct{}
+
+func (noCommitments) ForBlock(targetBlock *big.Int) []commitment.Commitment {
+	return nil
+}
+
+func TestBeat(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bid := auction.MustCreateSignedBid(big.NewInt(100), big.NewInt(12), key)
+	l := &mockListener{ranking: []auction.SignedBid{*bid}}
+	b := heartbeat.NewBeacon(slog.Default(), heartbeat.Config{}, key, l, noCommitments{})
+
+	h, err := b.Beat(1)
+	require.NoError(t, err)
+	require.True(t, h.Verify())
+	require.Equal(t, uint64(0), h.L1Block, "no auction closed yet")
+	require.Equal(t, common.Hash{}, h.StateHash)
+
+	events := make(chan auction.Event, 1)
+	events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(12), Bid: bid}
+	close(events)
+	b.Watch(context.Background(), events)
+	l.paused = true
+	h, err = b.Beat(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), h.L1Block)
+	require.Equal(t, crosscheck.Hash(12, []auction.SignedBid{*bid}, bid, nil), h.StateHash)
+	require.True(t, h.Paused)
+	require.Equal(t, h, b.Latest())
+
+	published := l.published()
+	require.Len(t, published, 2)
+	require.Equal(t, auction.EventHeartbeat, published[1].Type)
+	require.Equal(t, big.NewInt(12), published[1].L1Block)
+	require.Equal(t, h, published[1].Heartbeat)
+}
+
+func TestSlot(t *testing.T) {
+	genesis := time.Unix(1606824023, 0)
+	b := heartbeat.NewBeacon(slog.Default(), heartbeat.Config{GenesisTime: genesis}, nil, &mockListener{}, noCommitments{})
+	require.Equal(t, uint64(0), b.Slot(genesis.Add(-time.Hour)))
+	require.Equal(t, uint64(0), b.Slot(genesis.Add(11*time.Second)))
+	require.Equal(t, uint64(2), b.Slot(genesis.Add(24*time.Second)))
+}
+
+func TestStart(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	l := &mockListener{}
+	b := heartbeat.NewBeacon(slog.Default(), heartbeat.Config{SlotTime: 20 * time.Millisecond}, key, l, noCommitments{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b.Start(ctx)
+
+	require.Eventually(t, func() bool { return len(l.published()) >= 2 }, time.Second, 5*time.Millisecond)
+	published := l.published()
+	require.Equal(t, published[0].Heartbeat.Slot+1, published[1].Heartbeat.Slot, "one heartbeat a slot")
+}
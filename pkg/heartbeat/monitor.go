@@ -0,0 +1,62 @@
+package heartbeat
+
+import (
+	"errors"
+	"sync"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrUntrusted = errors.New("heartbeat not signed by a trusted auctioneer")
+	ErrStale     = errors.New("heartbeat not after the previous one")
+)
+
+// What a heartbeat shows about the slots since the previous one
+type Observation struct {
+	// Slots between the two with no heartbeat: the auctioneer was down, or its heartbeats didn't arrive
+	MissedSlots uint64
+	// No auction closed between the two, although auctions weren't paused: the auctioneer withheld the auction,
+	// or no L1 block was proposed for it
+	Stalled bool
+}
+
+// Checks the heartbeats a relay or watcher receives from an auctioneer, see Beacon
+type Monitor struct {
+	trusted map[common.Address]bool
+
+	mu   sync.Mutex // Protects last
+	last *auction.Heartbeat
+}
+
+// Heartbeats must be signed by one of the trusted auctioneer keys
+func NewMonitor(trusted ...common.Address) *Monitor {
+	m := &Monitor{trusted: make(map[common.Address]bool, len(trusted))}
+	for _, address := range trusted {
+		m.trusted[address] = true
+	}
+	return m
+}
+
+// Verifies the heartbeat and compares it with the previous one observed. The first heartbeat shows nothing.
+func (m *Monitor) Observe(h auction.Heartbeat) (Observation, error) {
+	if !m.trusted[h.Auctioneer] || !h.Verify() {
+		return Observation{}, ErrUntrusted
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	last := m.last
+	if last != nil && h.Slot <= last.Slot {
+		return Observation{}, ErrStale
+	}
+	m.last = &h
+	if last == nil {
+		return Observation{}, nil
+	}
+	return Observation{
+		MissedSlots: h.Slot - last.Slot - 1,
+		Stalled:     !last.Paused && !h.Paused && h.L1Block <= last.L1Block,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package heartbeat_test
+
+import (
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/heartbeat"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserve(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	beat := func(slot uint64, l1Block uint64, paused bool) auction.Heartbeat {
+		h, err := auction.CreateSignedHeartbeat(slot, l1Block, common.Hash{}, paused, "dev", time.Now(), key)
+		require.NoError(t, err)
+		return *h
+	}
+	m := heartbeat.NewMonitor(crypto.PubkeyToAddress(key.PublicKey))
+
+	observation, err := m.Observe(beat(10, 100, false))
+	require.NoError(t, err)
+	require.Equal(t, heartbeat.Observation{}, observation)
+
+	observation, err = m.Observe(beat(11, 101, false))
+	require.NoError(t, err)
+	require.Equal(t, heartbeat.Observation{}, observation)
+
+	observation, err = m.Observe(beat(14, 102, false))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), observation.MissedSlots)
+
+	observation, err = m.Observe(beat(15, 102, false))
+	require.NoError(t, err)
+	require.True(t, observation.Stalled)
+
+	observation, err = m.Observe(beat(16, 102, true))
+	require.NoError(t, err)
+	require.False(t, observation.Stalled, "auctions paused")
+
+	_, err = m.Observe(beat(16, 102, true))
+	require.ErrorIs(t, err, heartbeat.ErrStale)
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	untrusted, err := auction.CreateSignedHeartbeat(17, 103, common.Hash{}, false, "dev", time.Now(), other)
+	require.NoError(t, err)
+	_, err = m.Observe(*untrusted)
+	require.ErrorIs(t, err, heartbeat.ErrUntrusted)
+
+	forged := beat(17, 103, false)
+	forged.Paused = true
+	_, err = m.Observe(forged)
+	require.ErrorIs(t, err, heartbeat.ErrUntrusted)
+}
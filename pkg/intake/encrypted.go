@@ -0,0 +1,175 @@
+package intake
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+const (
+	contentKeyLength    = 32
+	compressedKeyLength = 33
+	gcmTagLength        = 16
+	// Ephemeral public key, compressed, followed by the AES-GCM sealed content key
+	sealedKeyLength = compressedKeyLength + contentKeyLength + gcmTagLength
+)
+
+var (
+	ErrEncryptionDisabled = errors.New("encrypted blobs not accepted")
+	ErrBlobMismatch       = errors.New("decrypted blob doesn't match its versioned hash")
+)
+
+// Blob contents encrypted until the auction for their target block is awarded, so rollup batches can't be
+// frontrun while it's open. The content key is sealed to the auctioneer's escrow key, and released to the winning
+// relay only once it accepts its award, see Pool.ReleaseKeys.
+type EncryptedBlobs struct {
+	// Blobs in the order of the request's versioned hashes, AES-256-GCM sealed with the content key
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+	// Content key, ECIES encrypted to the escrow key
+	SealedKey hexutil.Bytes `json:"sealedKey"`
+}
+
+// Content key of an encrypted request, sealed to the relay it's released to
+type KeyRelease struct {
+	RequestID common.Hash   `json:"requestId"`
+	SealedKey hexutil.Bytes `json:"sealedKey"`
+}
+
+// Creates a request for the blobs, their contents encrypted with a fresh content key sealed to escrowKey
+func CreateSignedEncryptedRequest(
+	blobs []kzg4844.Blob,
+	atomic bool,
+	targetBlock *big.Int,
+	maxFeeWei *big.Int,
+	escrowKey *ecdsa.PublicKey,
+	privateKey *ecdsa.PrivateKey,
+) (*PreconfRequest, error) {
+	req := PreconfRequest{Atomic: atomic, TargetBlock: targetBlock, MaxFeeWei: maxFeeWei}
+	plaintext := make([]byte, 0, len(blobs)*len(kzg4844.Blob{}))
+	for _, blob := range blobs {
+		commitment, err := kzg4844.BlobToCommitment(blob)
+		if err != nil {
+			return nil, err
+		}
+		req.VersionedHashes = append(req.VersionedHashes, kzg4844.CalcBlobHashV1(sha256.New(), &commitment))
+		plaintext = append(plaintext, blob[:]...)
+	}
+	contentKey := make([]byte, contentKeyLength)
+	if _, err := rand.Read(contentKey); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := EncryptedBlobs{Ciphertext: aead.Seal(nil, make([]byte, aead.NonceSize()), plaintext, req.blobsData())}
+	if encrypted.SealedKey, err = sealKey(escrowKey, contentKey, nil); err != nil {
+		return nil, err
+	}
+	req.Encrypted = &encrypted
+	return signRequest(&req, privateKey)
+}
+
+// Decrypts the request's blobs with the content key released to the relay, checking they're the requested blobs
+func (r *PreconfRequest) Decrypt(contentKey []byte) ([]kzg4844.Blob, error) {
+	if r.Encrypted == nil {
+		return nil, fmt.Errorf("%w: blobs not encrypted", ErrInvalidRequest)
+	}
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), r.Encrypted.Ciphertext, r.blobsData())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	if len(plaintext) != len(r.VersionedHashes)*len(kzg4844.Blob{}) {
+		return nil, fmt.Errorf("%w: %d bytes of blobs", ErrBlobMismatch, len(plaintext))
+	}
+	blobs := make([]kzg4844.Blob, len(r.VersionedHashes))
+	for i, vh := range r.VersionedHashes {
+		copy(blobs[i][:], plaintext[i*len(blobs[i]):])
+		commitment, err := kzg4844.BlobToCommitment(blobs[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBlobMismatch, err)
+		}
+		if common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment)) != vh {
+			return nil, fmt.Errorf("%w: blob %d", ErrBlobMismatch, i)
+		}
+	}
+	return blobs, nil
+}
+
+// Decrypts the released content key with the key of the relay it was released to
+func (k *KeyRelease) Open(relayKey *ecdsa.PrivateKey) ([]byte, error) {
+	return openKey(relayKey, k.SealedKey, k.RequestID.Bytes())
+}
+
+// Whether the encrypted blobs are sized for the request's blobs
+func (r *PreconfRequest) validEncryption() bool {
+	return len(r.Encrypted.Ciphertext) == len(r.VersionedHashes)*len(kzg4844.Blob{})+gcmTagLength &&
+		len(r.Encrypted.SealedKey) == sealedKeyLength
+}
+
+// Associated data of the encrypted blobs, binding them to the versioned hashes
+func (r *PreconfRequest) blobsData() []byte {
+	data := make([]byte, 0, len(r.VersionedHashes)*common.HashLength)
+	for _, vh := range r.VersionedHashes {
+		data = append(data, vh.Bytes()...)
+	}
+	return data
+}
+
+// ECIES over secp256k1, as sealed bids are (see sealed): the shared secret of a fresh ephemeral key and the
+// recipient's key keys AES-256-GCM. Each key seals one message, so the nonce is fixed.
+func sealKey(recipient *ecdsa.PublicKey, contentKey []byte, associatedData []byte) ([]byte, error) {
+	ephemeral, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	x, _ := crypto.S256().ScalarMult(recipient.X, recipient.Y, ephemeral.D.Bytes())
+	ephemeralKey := crypto.CompressPubkey(&ephemeral.PublicKey)
+	aead, err := newAEAD(crypto.Keccak256(common.LeftPadBytes(x.Bytes(), 32), ephemeralKey))
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(ephemeralKey, make([]byte, aead.NonceSize()), contentKey, associatedData), nil
+}
+
+func openKey(key *ecdsa.PrivateKey, sealedKey []byte, associatedData []byte) ([]byte, error) {
+	if len(sealedKey) != sealedKeyLength {
+		return nil, fmt.Errorf("invalid sealed key length %d", len(sealedKey))
+	}
+	ephemeral, err := crypto.DecompressPubkey(sealedKey[:compressedKeyLength])
+	if err != nil {
+		return nil, err
+	}
+	x, _ := crypto.S256().ScalarMult(ephemeral.X, ephemeral.Y, key.D.Bytes())
+	aead, err := newAEAD(crypto.Keccak256(common.LeftPadBytes(x.Bytes(), 32), sealedKey[:compressedKeyLength]))
+	if err != nil {
+		return nil, err
+	}
+	contentKey, err := aead.Open(nil, make([]byte, aead.NonceSize()), sealedKey[compressedKeyLength:], associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt content key: %w", err)
+	}
+	return contentKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
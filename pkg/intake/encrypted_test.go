@@ -0,0 +1,76 @@
+package intake_test
+
+import (
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedBlobsReleasedToWinner(t *testing.T) {
+	userKey, _ := crypto.GenerateKey()
+	escrowKey, _ := crypto.GenerateKey()
+	winnerKey, _ := crypto.GenerateKey()
+	var blob kzg4844.Blob
+	copy(blob[1:], "rollup batch")
+	req, err := intake.CreateSignedEncryptedRequest([]kzg4844.Blob{blob}, true, big.NewInt(100), big.NewInt(1), &escrowKey.PublicKey, userKey)
+	require.NoError(t, err)
+	require.True(t, req.Verify())
+	require.NotContains(t, string(req.Encrypted.Ciphertext), "rollup batch")
+
+	_, err = mustNewPool(t, slog.Default(), intake.Config{}, nil).Submit(*req)
+	require.ErrorIs(t, err, intake.ErrEncryptionDisabled)
+
+	pool := mustNewPool(t, slog.Default(), intake.Config{}, nil)
+	otherKey, _ := crypto.GenerateKey()
+	pool.SetEscrowKey(otherKey)
+	_, err = pool.Submit(*req)
+	require.ErrorIs(t, err, intake.ErrInvalidRequest, "content key sealed to another escrow key")
+
+	pool = mustNewPool(t, slog.Default(), intake.Config{}, nil)
+	pool.SetEscrowKey(escrowKey)
+	id, err := pool.Submit(*req)
+	require.NoError(t, err)
+	_, err = pool.Submit(mustCreateRequest(t, userKey, 1, 100))
+	require.NoError(t, err)
+
+	releases, err := pool.ReleaseKeys(big.NewInt(100), &winnerKey.PublicKey)
+	require.NoError(t, err)
+	require.Len(t, releases, 1, "only encrypted requests have keys")
+	require.Equal(t, id, releases[0].RequestID)
+	_, err = releases[0].Open(otherKey)
+	require.Error(t, err, "keys open only with the winner's key")
+	contentKey, err := releases[0].Open(winnerKey)
+	require.NoError(t, err)
+	blobs, err := req.Decrypt(contentKey)
+	require.NoError(t, err)
+	require.Equal(t, []kzg4844.Blob{blob}, blobs)
+
+	releases, err = pool.ReleaseKeys(big.NewInt(101), &winnerKey.PublicKey)
+	require.NoError(t, err)
+	require.Empty(t, releases)
+}
+
+func TestEncryptedBlobsBoundToRequest(t *testing.T) {
+	userKey, _ := crypto.GenerateKey()
+	escrowKey, _ := crypto.GenerateKey()
+	var blob kzg4844.Blob
+	req, err := intake.CreateSignedEncryptedRequest([]kzg4844.Blob{blob}, false, big.NewInt(100), big.NewInt(1), &escrowKey.PublicKey, userKey)
+	require.NoError(t, err)
+
+	tampered := *req
+	encrypted := *req.Encrypted
+	encrypted.Ciphertext = append([]byte{}, encrypted.Ciphertext...)
+	encrypted.Ciphertext[0] ^= 1
+	tampered.Encrypted = &encrypted
+	require.False(t, tampered.Verify(), "the signature covers the ciphertext")
+
+	truncated := *req
+	truncated.Encrypted = &intake.EncryptedBlobs{Ciphertext: req.Encrypted.Ciphertext[:100], SealedKey: req.Encrypted.SealedKey}
+	require.False(t, truncated.Verify())
+}
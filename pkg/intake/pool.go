@@ -0,0 +1,263 @@
+package intake
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Upper bound on blobs an atomic bundle can contain, as it must fit in a single block
+const MaxBlobsPerBlock = params.MaxBlobGasPerBlock / params.BlobTxBlobGasPerBlob
+
+var (
+	ErrInvalidRequest      = errors.New("invalid preconf request")
+	ErrDuplicateRequest    = errors.New("duplicate preconf request")
+	ErrQuotaExceeded       = errors.New("preconf request quota exceeded")
+	ErrInsufficientDeposit = errors.New("insufficient anti-spam deposit")
+	ErrBundleTooLarge      = errors.New("atomic bundle exceeds max blobs per block")
+)
+
+// Deposits users lock on the settlement layer, forfeitable if a committed blob tx is never broadcast.
+type DepositRegistry interface {
+	DepositOf(address common.Address) *big.Int
+}
+
+type Config struct {
+	// Max requests an address may have pending at once. Zero disables the limit.
+	MaxPendingPerAddress int
+	// Max requests an address may submit within QuotaWindow. Zero disables the limit.
+	MaxRequestsPerWindow int
+	QuotaWindow          time.Duration
+	// Deposit each pending request reserves, so a sender's deposit covers all its pending requests. Zero disables
+	// the requirement.
+	MinDepositWei *big.Int
+}
+
+// Holds pending preconf requests until they're committed to, or their target block passes.
+type Pool struct {
+	logger   *slog.Logger
+	config   Config
+	deposits DepositRegistry
+	// Nil unless encrypted blobs are accepted
+	escrowKey *ecdsa.PrivateKey
+
+	mu           sync.Mutex
+	pending      map[common.Hash]PreconfRequest
+	pendingCount map[common.Address]int
+	submissions  map[common.Address][]time.Time
+}
+
+func NewPool(logger *slog.Logger, config Config, deposits DepositRegistry) (*Pool, error) {
+	if config.MaxPendingPerAddress < 0 || config.MaxRequestsPerWindow < 0 {
+		return nil, fmt.Errorf("negative request limit")
+	}
+	// A zero window would count no submissions, silently disabling the limit
+	if config.MaxRequestsPerWindow > 0 && config.QuotaWindow <= 0 {
+		return nil, fmt.Errorf("quota window %v must be positive to limit requests per window", config.QuotaWindow)
+	}
+	if config.MinDepositWei != nil && config.MinDepositWei.Sign() < 0 {
+		return nil, fmt.Errorf("negative min deposit %v", config.MinDepositWei)
+	}
+	return &Pool{
+		logger:       logger,
+		config:       config,
+		deposits:     deposits,
+		pending:      make(map[common.Hash]PreconfRequest),
+		pendingCount: make(map[common.Address]int),
+		submissions:  make(map[common.Address][]time.Time),
+	}, nil
+}
+
+// Requests with encrypted blobs are accepted, their content keys sealed to key, if set before requests are
+// submitted. Keys are released to the winning relay of their target block's auction with ReleaseKeys.
+func (p *Pool) SetEscrowKey(key *ecdsa.PrivateKey) {
+	p.escrowKey = key
+}
+
+func (p *Pool) Submit(req PreconfRequest) (common.Hash, error) {
+	if !req.Verify() {
+		return common.Hash{}, ErrInvalidRequest
+	}
+	if req.Encrypted != nil {
+		if p.escrowKey == nil {
+			return common.Hash{}, ErrEncryptionDisabled
+		}
+		if _, err := openKey(p.escrowKey, req.Encrypted.SealedKey, nil); err != nil {
+			return common.Hash{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+	}
+	if req.Atomic && len(req.VersionedHashes) > MaxBlobsPerBlock {
+		return common.Hash{}, fmt.Errorf("%w: %d blobs", ErrBundleTooLarge, len(req.VersionedHashes))
+	}
+	// Read before locking, as the registry may query the settlement layer
+	deposit := p.depositOf(req.Sender)
+
+	id := req.Hash()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pending[id]; ok {
+		return common.Hash{}, ErrDuplicateRequest
+	}
+	if err := p.checkDeposit(deposit, p.pendingCount[req.Sender]+1); err != nil {
+		return common.Hash{}, err
+	}
+	if p.config.MaxPendingPerAddress > 0 && p.pendingCount[req.Sender] >= p.config.MaxPendingPerAddress {
+		return common.Hash{}, fmt.Errorf("%w: %d requests pending", ErrQuotaExceeded, p.pendingCount[req.Sender])
+	}
+	now := time.Now()
+	recent := p.recentSubmissions(req.Sender, now)
+	if p.config.MaxRequestsPerWindow > 0 && len(recent) >= p.config.MaxRequestsPerWindow {
+		return common.Hash{}, fmt.Errorf("%w: %d requests within %v", ErrQuotaExceeded, len(recent), p.config.QuotaWindow)
+	}
+
+	p.submissions[req.Sender] = append(recent, now)
+	p.pending[id] = req
+	p.pendingCount[req.Sender]++
+	p.logger.Debug("preconf request accepted", "id", id, "sender", req.Sender, "targetBlock", req.TargetBlock)
+	return id, nil
+}
+
+// Pending requests targeting the given block
+func (p *Pool) Pending(targetBlock *big.Int) []PreconfRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var reqs []PreconfRequest
+	for _, req := range p.pending {
+		if req.TargetBlock.Cmp(targetBlock) == 0 {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// Selects pending requests for the target block, highest fee per blob first, without exceeding
+// blobCapacity. Requests are never split, so atomic bundles are either fully selected or skipped.
+func (p *Pool) SelectForBlock(targetBlock *big.Int, blobCapacity int) []PreconfRequest {
+	candidates := p.Pending(targetBlock)
+	sort.Slice(candidates, func(i, j int) bool {
+		// a/len(a) > b/len(b), cross-multiplied to avoid rounding
+		lhs := new(big.Int).Mul(candidates[i].MaxFeeWei, big.NewInt(int64(len(candidates[j].VersionedHashes))))
+		rhs := new(big.Int).Mul(candidates[j].MaxFeeWei, big.NewInt(int64(len(candidates[i].VersionedHashes))))
+		if cmp := lhs.Cmp(rhs); cmp != 0 {
+			return cmp > 0
+		}
+		return candidates[i].Hash().Cmp(candidates[j].Hash()) < 0
+	})
+
+	var selected []PreconfRequest
+	used := 0
+	for _, req := range candidates {
+		if used+len(req.VersionedHashes) > blobCapacity {
+			continue
+		}
+		used += len(req.VersionedHashes)
+		selected = append(selected, req)
+	}
+	return selected
+}
+
+// Releases the content keys of pending encrypted requests targeting the block to the winner of its auction, sealed
+// to its key, in request ID order. To be called only once the winner accepts its award.
+func (p *Pool) ReleaseKeys(targetBlock *big.Int, winner *ecdsa.PublicKey) ([]KeyRelease, error) {
+	if p.escrowKey == nil {
+		return nil, nil
+	}
+	var releases []KeyRelease
+	for _, req := range p.Pending(targetBlock) {
+		if req.Encrypted == nil {
+			continue
+		}
+		id := req.Hash()
+		contentKey, err := openKey(p.escrowKey, req.Encrypted.SealedKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("request %s: %w", id, err)
+		}
+		sealedKey, err := sealKey(winner, contentKey, id.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, KeyRelease{RequestID: id, SealedKey: sealedKey})
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].RequestID.Cmp(releases[j].RequestID) < 0 })
+	p.logger.Debug("content keys released", "targetBlock", targetBlock, "keys", len(releases))
+	return releases, nil
+}
+
+// Removes a request from the pool, once committed to or expired, releasing the deposit it reserved
+func (p *Pool) Remove(id common.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	req, ok := p.pending[id]
+	if !ok {
+		return
+	}
+	delete(p.pending, id)
+	p.pendingCount[req.Sender]--
+	if p.pendingCount[req.Sender] == 0 {
+		delete(p.pendingCount, req.Sender)
+	}
+}
+
+// Drops requests targeting blocks before the given one
+func (p *Pool) PruneBefore(block *big.Int) int {
+	p.mu.Lock()
+	var expired []common.Hash
+	for id, req := range p.pending {
+		if req.TargetBlock.Cmp(block) < 0 {
+			expired = append(expired, id)
+		}
+	}
+	p.mu.Unlock()
+	for _, id := range expired {
+		p.Remove(id)
+	}
+	return len(expired)
+}
+
+// Nil if no deposit is required
+func (p *Pool) depositOf(sender common.Address) *big.Int {
+	if p.deposits == nil || p.config.MinDepositWei == nil || p.config.MinDepositWei.Sign() == 0 {
+		return nil
+	}
+	deposit := p.deposits.DepositOf(sender)
+	if deposit == nil {
+		return new(big.Int)
+	}
+	return deposit
+}
+
+// Whether the deposit covers the given number of pending requests, each reserving MinDepositWei
+func (p *Pool) checkDeposit(deposit *big.Int, requests int) error {
+	if deposit == nil {
+		return nil
+	}
+	required := new(big.Int).Mul(p.config.MinDepositWei, big.NewInt(int64(requests)))
+	if deposit.Cmp(required) < 0 {
+		return fmt.Errorf("%w: have %v, need %v for %d pending requests", ErrInsufficientDeposit, deposit, required, requests)
+	}
+	return nil
+}
+
+// Must be called with mu held
+func (p *Pool) recentSubmissions(sender common.Address, now time.Time) []time.Time {
+	times := p.submissions[sender]
+	cutoff := now.Add(-p.config.QuotaWindow)
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	if i == len(times) {
+		delete(p.submissions, sender)
+		return nil
+	}
+	return times[i:]
+}
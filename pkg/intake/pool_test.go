@@ -0,0 +1,207 @@
+package intake_test
+
+import (
+	"crypto/ecdsa"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDepositRegistry struct {
+	deposits map[common.Address]*big.Int
+}
+
+func (m *mockDepositRegistry) DepositOf(address common.Address) *big.Int {
+	return m.deposits[address]
+}
+
+func mustNewPool(t *testing.T, logger *slog.Logger, config intake.Config, deposits intake.DepositRegistry) *intake.Pool {
+	pool, err := intake.NewPool(logger, config, deposits)
+	require.NoError(t, err)
+	return pool
+}
+
+func mustCreateRequest(t *testing.T, pk *ecdsa.PrivateKey, blob byte, targetBlock int64) intake.PreconfRequest {
+	req, err := intake.CreateSignedRequest([]common.Hash{{blob}}, big.NewInt(targetBlock), big.NewInt(1), pk)
+	require.NoError(t, err)
+	return *req
+}
+
+func TestPoolMaxPendingPerAddress(t *testing.T) {
+	pool := mustNewPool(t, slog.Default(), intake.Config{MaxPendingPerAddress: 2}, nil)
+	pk, _ := crypto.GenerateKey()
+
+	id, err := pool.Submit(mustCreateRequest(t, pk, 1, 100))
+	require.NoError(t, err)
+	_, err = pool.Submit(mustCreateRequest(t, pk, 2, 100))
+	require.NoError(t, err)
+	_, err = pool.Submit(mustCreateRequest(t, pk, 3, 100))
+	require.ErrorIs(t, err, intake.ErrQuotaExceeded)
+
+	otherPk, _ := crypto.GenerateKey()
+	_, err = pool.Submit(mustCreateRequest(t, otherPk, 3, 100))
+	require.NoError(t, err)
+
+	pool.Remove(id)
+	_, err = pool.Submit(mustCreateRequest(t, pk, 3, 100))
+	require.NoError(t, err)
+	require.Len(t, pool.Pending(big.NewInt(100)), 3)
+}
+
+func TestPoolRateLimit(t *testing.T) {
+	pool := mustNewPool(t, slog.Default(), intake.Config{
+		MaxRequestsPerWindow: 2,
+		QuotaWindow:          100 * time.Millisecond,
+	}, nil)
+	pk, _ := crypto.GenerateKey()
+
+	for i := byte(0); i < 2; i++ {
+		id, err := pool.Submit(mustCreateRequest(t, pk, i, 100))
+		require.NoError(t, err)
+		pool.Remove(id)
+	}
+	_, err := pool.Submit(mustCreateRequest(t, pk, 2, 100))
+	require.ErrorIs(t, err, intake.ErrQuotaExceeded)
+
+	time.Sleep(150 * time.Millisecond)
+	_, err = pool.Submit(mustCreateRequest(t, pk, 2, 100))
+	require.NoError(t, err)
+}
+
+func TestPoolDepositRequirement(t *testing.T) {
+	funded, _ := crypto.GenerateKey()
+	unfunded, _ := crypto.GenerateKey()
+	registry := &mockDepositRegistry{deposits: map[common.Address]*big.Int{
+		crypto.PubkeyToAddress(funded.PublicKey):   big.NewInt(1e16),
+		crypto.PubkeyToAddress(unfunded.PublicKey): big.NewInt(1e15),
+	}}
+	pool := mustNewPool(t, slog.Default(), intake.Config{MinDepositWei: big.NewInt(1e16)}, registry)
+
+	_, err := pool.Submit(mustCreateRequest(t, funded, 1, 100))
+	require.NoError(t, err)
+	_, err = pool.Submit(mustCreateRequest(t, unfunded, 1, 100))
+	require.ErrorIs(t, err, intake.ErrInsufficientDeposit)
+}
+
+func TestPoolDepositReserved(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	registry := &mockDepositRegistry{deposits: map[common.Address]*big.Int{
+		crypto.PubkeyToAddress(pk.PublicKey): big.NewInt(2e16),
+	}}
+	pool := mustNewPool(t, slog.Default(), intake.Config{MinDepositWei: big.NewInt(1e16)}, registry)
+
+	id, err := pool.Submit(mustCreateRequest(t, pk, 1, 100))
+	require.NoError(t, err)
+	_, err = pool.Submit(mustCreateRequest(t, pk, 2, 100))
+	require.NoError(t, err)
+	// Both requests pending reserve the whole deposit
+	_, err = pool.Submit(mustCreateRequest(t, pk, 3, 100))
+	require.ErrorIs(t, err, intake.ErrInsufficientDeposit)
+
+	pool.Remove(id)
+	_, err = pool.Submit(mustCreateRequest(t, pk, 3, 101))
+	require.NoError(t, err)
+	_, err = pool.Submit(mustCreateRequest(t, pk, 4, 101))
+	require.ErrorIs(t, err, intake.ErrInsufficientDeposit)
+
+	// Expired requests release theirs
+	require.Equal(t, 1, pool.PruneBefore(big.NewInt(101)))
+	_, err = pool.Submit(mustCreateRequest(t, pk, 4, 101))
+	require.NoError(t, err)
+}
+
+func TestPoolConfigValidated(t *testing.T) {
+	for name, config := range map[string]intake.Config{
+		"rate limit without window": {MaxRequestsPerWindow: 2},
+		"negative pending limit":    {MaxPendingPerAddress: -1},
+		"negative min deposit":      {MinDepositWei: big.NewInt(-1)},
+	} {
+		_, err := intake.NewPool(slog.Default(), config, nil)
+		require.Error(t, err, name)
+	}
+}
+
+func TestPoolRejectsInvalidAndDuplicateRequests(t *testing.T) {
+	pool := mustNewPool(t, slog.Default(), intake.Config{}, nil)
+	pk, _ := crypto.GenerateKey()
+
+	req := mustCreateRequest(t, pk, 1, 100)
+	_, err := pool.Submit(req)
+	require.NoError(t, err)
+	_, err = pool.Submit(req)
+	require.ErrorIs(t, err, intake.ErrDuplicateRequest)
+
+	req.TargetBlock = big.NewInt(101)
+	_, err = pool.Submit(req)
+	require.ErrorIs(t, err, intake.ErrInvalidRequest)
+}
+
+func TestPoolPruneBefore(t *testing.T) {
+	pool := mustNewPool(t, slog.Default(), intake.Config{MaxPendingPerAddress: 1}, nil)
+	pk, _ := crypto.GenerateKey()
+
+	_, err := pool.Submit(mustCreateRequest(t, pk, 1, 100))
+	require.NoError(t, err)
+	require.Equal(t, 1, pool.PruneBefore(big.NewInt(101)))
+	require.Empty(t, pool.Pending(big.NewInt(100)))
+
+	// Pruned requests no longer count towards quota
+	_, err = pool.Submit(mustCreateRequest(t, pk, 2, 101))
+	require.NoError(t, err)
+}
+
+func TestPoolAtomicBundles(t *testing.T) {
+	pool := mustNewPool(t, slog.Default(), intake.Config{}, nil)
+	pk, _ := crypto.GenerateKey()
+
+	blobs := make([]common.Hash, intake.MaxBlobsPerBlock+1)
+	for i := range blobs {
+		blobs[i][0] = byte(i)
+	}
+	tooLarge, err := intake.CreateSignedBundleRequest(blobs, big.NewInt(100), big.NewInt(1), pk)
+	require.NoError(t, err)
+	_, err = pool.Submit(*tooLarge)
+	require.ErrorIs(t, err, intake.ErrBundleTooLarge)
+
+	duplicates, err := intake.CreateSignedBundleRequest([]common.Hash{{0x01}, {0x01}}, big.NewInt(100), big.NewInt(1), pk)
+	require.NoError(t, err)
+	_, err = pool.Submit(*duplicates)
+	require.ErrorIs(t, err, intake.ErrInvalidRequest)
+}
+
+func TestPoolSelectForBlock(t *testing.T) {
+	pool := mustNewPool(t, slog.Default(), intake.Config{}, nil)
+	pk, _ := crypto.GenerateKey()
+
+	// 4 blob bundle paying 10 per blob
+	bundle, err := intake.CreateSignedBundleRequest(
+		[]common.Hash{{0x01}, {0x02}, {0x03}, {0x04}}, big.NewInt(100), big.NewInt(40), pk)
+	require.NoError(t, err)
+	// Loose requests paying 20 and 5 per blob
+	high, err := intake.CreateSignedRequest([]common.Hash{{0x05}, {0x06}}, big.NewInt(100), big.NewInt(40), pk)
+	require.NoError(t, err)
+	low, err := intake.CreateSignedRequest([]common.Hash{{0x07}}, big.NewInt(100), big.NewInt(5), pk)
+	require.NoError(t, err)
+	for _, req := range []*intake.PreconfRequest{bundle, high, low} {
+		_, err := pool.Submit(*req)
+		require.NoError(t, err)
+	}
+
+	selected := pool.SelectForBlock(big.NewInt(100), 6)
+	require.Len(t, selected, 2)
+	require.Equal(t, high.Hash(), selected[0].Hash())
+	require.Equal(t, bundle.Hash(), selected[1].Hash())
+
+	// Bundle doesn't fit, and is never split
+	selected = pool.SelectForBlock(big.NewInt(100), 5)
+	require.Len(t, selected, 2)
+	require.Equal(t, high.Hash(), selected[0].Hash())
+	require.Equal(t, low.Hash(), selected[1].Hash())
+}
@@ -0,0 +1,145 @@
+package intake
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// A user's request for the blobs to be preconfirmed for inclusion in TargetBlock
+type PreconfRequest struct {
+	VersionedHashes []common.Hash `json:"versionedHashes"`
+	// Atomic bundles must land together in one block, e.g. a rollup batch split across blobs
+	Atomic      bool           `json:"atomic"`
+	TargetBlock *big.Int       `json:"targetBlock"`
+	MaxFeeWei   *big.Int       `json:"maxFeeWei"`
+	Sender      common.Address `json:"sender"`
+	// Contents of the blobs, encrypted until the auction for TargetBlock is awarded, if given
+	Encrypted *EncryptedBlobs `json:"encrypted,omitempty"`
+	// Signed blob txs carrying the blobs, in order and without their sidecars, so they can be simulated before
+	// being committed to, if given
+	Txs       []*types.Transaction `json:"txs,omitempty"`
+	Signature hexutil.Bytes        `json:"signature"`
+}
+
+func CreateSignedRequest(
+	versionedHashes []common.Hash,
+	targetBlock *big.Int,
+	maxFeeWei *big.Int,
+	privateKey *ecdsa.PrivateKey,
+) (*PreconfRequest, error) {
+	return signRequest(&PreconfRequest{
+		VersionedHashes: versionedHashes,
+		TargetBlock:     targetBlock,
+		MaxFeeWei:       maxFeeWei,
+	}, privateKey)
+}
+
+// Creates a request for blobs that must all be included in the same block
+func CreateSignedBundleRequest(
+	versionedHashes []common.Hash,
+	targetBlock *big.Int,
+	maxFeeWei *big.Int,
+	privateKey *ecdsa.PrivateKey,
+) (*PreconfRequest, error) {
+	return signRequest(&PreconfRequest{
+		VersionedHashes: versionedHashes,
+		Atomic:          true,
+		TargetBlock:     targetBlock,
+		MaxFeeWei:       maxFeeWei,
+	}, privateKey)
+}
+
+// Creates a request for the blobs of the txs, as an atomic bundle, carrying the txs for simulation
+func CreateSignedTxRequest(
+	txs []*types.Transaction,
+	targetBlock *big.Int,
+	maxFeeWei *big.Int,
+	privateKey *ecdsa.PrivateKey,
+) (*PreconfRequest, error) {
+	req := PreconfRequest{Atomic: true, TargetBlock: targetBlock, MaxFeeWei: maxFeeWei}
+	for _, tx := range txs {
+		req.VersionedHashes = append(req.VersionedHashes, tx.BlobHashes()...)
+		req.Txs = append(req.Txs, tx.WithoutBlobTxSidecar())
+	}
+	return signRequest(&req, privateKey)
+}
+
+func signRequest(req *PreconfRequest, privateKey *ecdsa.PrivateKey) (*PreconfRequest, error) {
+	req.Sender = crypto.PubkeyToAddress(privateKey.PublicKey)
+	signature, err := crypto.Sign(req.Hash().Bytes(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	req.Signature = signature
+	return req, nil
+}
+
+// Hash of the signed fields, also used as the request ID
+func (r *PreconfRequest) Hash() common.Hash {
+	data := make([]byte, 0, common.AddressLength+65+len(r.VersionedHashes)*common.HashLength)
+	data = append(data, r.Sender.Bytes()...)
+	if r.Atomic {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	data = append(data, common.BigToHash(r.TargetBlock).Bytes()...)
+	data = append(data, common.BigToHash(r.MaxFeeWei).Bytes()...)
+	for _, vh := range r.VersionedHashes {
+		data = append(data, vh.Bytes()...)
+	}
+	if r.Encrypted != nil {
+		data = append(data, crypto.Keccak256(r.Encrypted.Ciphertext)...)
+		data = append(data, r.Encrypted.SealedKey...)
+	}
+	for _, tx := range r.Txs {
+		data = append(data, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+func (r *PreconfRequest) Verify() bool {
+	if r.TargetBlock == nil || r.MaxFeeWei == nil || len(r.VersionedHashes) == 0 {
+		return false
+	}
+	// The hash drops the sign, so a negative value would verify with the signature of its absolute value
+	if r.TargetBlock.Sign() < 0 || r.MaxFeeWei.Sign() < 0 {
+		return false
+	}
+	seen := make(map[common.Hash]struct{}, len(r.VersionedHashes))
+	for _, vh := range r.VersionedHashes {
+		if _, ok := seen[vh]; ok {
+			return false
+		}
+		seen[vh] = struct{}{}
+	}
+	if r.Encrypted != nil && !r.validEncryption() {
+		return false
+	}
+	if len(r.Txs) > 0 && !r.validTxs() {
+		return false
+	}
+	sigPublicKey, err := crypto.SigToPub(r.Hash().Bytes(), r.Signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*sigPublicKey) == r.Sender
+}
+
+// Whether the txs carry exactly the requested blobs, in order
+func (r *PreconfRequest) validTxs() bool {
+	var versionedHashes []common.Hash
+	for _, tx := range r.Txs {
+		if tx == nil || tx.Type() != types.BlobTxType {
+			return false
+		}
+		versionedHashes = append(versionedHashes, tx.BlobHashes()...)
+	}
+	return slices.Equal(versionedHashes, r.VersionedHashes)
+}
@@ -0,0 +1,69 @@
+package intake_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAndVerifySignedRequest(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	req, err := intake.CreateSignedRequest([]common.Hash{{0x01}}, big.NewInt(100), big.NewInt(5), pk)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(pk.PublicKey), req.Sender)
+	assert.True(t, req.Verify())
+
+	req.MaxFeeWei = big.NewInt(6)
+	assert.False(t, req.Verify())
+}
+
+func TestVerifyRejectsNegativeAndMissingValues(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	for name, mutate := range map[string]func(req *intake.PreconfRequest){
+		"nil target block":      func(req *intake.PreconfRequest) { req.TargetBlock = nil },
+		"nil max fee":           func(req *intake.PreconfRequest) { req.MaxFeeWei = nil },
+		"negative target block": func(req *intake.PreconfRequest) { req.TargetBlock = big.NewInt(-100) },
+		"negative max fee":      func(req *intake.PreconfRequest) { req.MaxFeeWei = big.NewInt(-5) },
+	} {
+		req, err := intake.CreateSignedRequest([]common.Hash{{0x01}}, big.NewInt(100), big.NewInt(5), pk)
+		assert.NoError(t, err)
+		mutate(req)
+		assert.False(t, req.Verify(), name)
+	}
+
+	// Signed as negative, the hash matches the absolute value's
+	req, err := intake.CreateSignedRequest([]common.Hash{{0x01}}, big.NewInt(100), big.NewInt(-5), pk)
+	assert.NoError(t, err)
+	assert.False(t, req.Verify())
+}
+
+func TestTxRequestCarriesRequestedBlobs(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	txs := []*types.Transaction{
+		types.NewTx(&types.BlobTx{Nonce: 0, BlobHashes: []common.Hash{{0x01}, {0x02}}}),
+		types.NewTx(&types.BlobTx{Nonce: 1, BlobHashes: []common.Hash{{0x03}}}),
+	}
+	req, err := intake.CreateSignedTxRequest(txs, big.NewInt(100), big.NewInt(5), pk)
+	assert.NoError(t, err)
+	assert.True(t, req.Atomic)
+	assert.Equal(t, []common.Hash{{0x01}, {0x02}, {0x03}}, req.VersionedHashes)
+	assert.True(t, req.Verify())
+
+	// Signed over the txs, and only carrying the requested blobs
+	swapped := *req
+	swapped.Txs = []*types.Transaction{txs[0], types.NewTx(&types.BlobTx{Nonce: 2, BlobHashes: []common.Hash{{0x03}}})}
+	assert.False(t, swapped.Verify())
+	mismatched, err := intake.CreateSignedTxRequest(txs, big.NewInt(100), big.NewInt(5), pk)
+	assert.NoError(t, err)
+	mismatched.VersionedHashes = mismatched.VersionedHashes[:2]
+	assert.False(t, mismatched.Verify())
+	dropped := *req
+	dropped.Txs = nil
+	assert.False(t, dropped.Verify())
+}
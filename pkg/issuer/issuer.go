@@ -0,0 +1,150 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var errNoTxs = errors.New("request carries no blob txs to simulate")
+
+// Bounds each request's simulation, so a slow node can't hold up the block's other requests for long
+const simulationTimeout = 2 * time.Second
+
+type Config struct {
+	// Blobs committed to per block, escalated commitments' blobs included
+	BlobsPerBlock int
+	// Blocks past the target block commitments stay valid for
+	ValidityBlocks uint64
+}
+
+// Satisfied by *intake.Pool
+type Pool interface {
+	SelectForBlock(targetBlock *big.Int, blobCapacity int) []intake.PreconfRequest
+	PruneBefore(block *big.Int) int
+}
+
+// Satisfied by *pricing.BlobQuoter
+type Quoter interface {
+	QuoteRequest(req intake.PreconfRequest) (*big.Int, error)
+}
+
+// Satisfied by *commitment.Coordinator
+type Coordinator interface {
+	Escalated(targetBlock *big.Int) []commitment.Commitment
+	Issue(req intake.PreconfRequest, feeWei *big.Int, expiryBlock *big.Int) (*commitment.Commitment, error)
+}
+
+// Satisfied by *simulation.Simulator
+type Simulator interface {
+	Simulate(ctx context.Context, txs []*types.Transaction) error
+}
+
+// Commits to the pool's requests targeting each block as its auction opens, binding the auction's winner to
+// include them. Commitments escalated to the block take their blobs first.
+type Issuer struct {
+	logger      *slog.Logger
+	config      Config
+	pool        Pool
+	quoter      Quoter
+	coordinator Coordinator
+	// Nil unless requests are simulated
+	simulator Simulator
+	// Last block committed to, so no block is committed to twice
+	last uint64
+}
+
+func NewIssuer(logger *slog.Logger, config Config, pool Pool, quoter Quoter, coordinator Coordinator) *Issuer {
+	return &Issuer{
+		logger:      logger,
+		config:      config,
+		pool:        pool,
+		quoter:      quoter,
+		coordinator: coordinator,
+	}
+}
+
+// Requests are committed to only once their blob txs simulate against latest state, if set before Watch. Requests
+// without their txs can't be simulated, so aren't committed to.
+func (i *Issuer) SetSimulator(simulator Simulator) {
+	i.simulator = simulator
+}
+
+// Commits to requests as each auction opens, until ctx is done or events is closed
+func (i *Issuer) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type != auction.EventAuctionOpened || ev.L1Block == nil || ev.L1Block.Uint64() <= i.last {
+				continue
+			}
+			i.last = ev.L1Block.Uint64()
+			i.Issue(ctx, ev.L1Block)
+		}
+	}
+}
+
+// Commits to the pool's requests targeting the block, best paying per blob first, within the capacity escalated
+// commitments leave. Requests that can no longer be committed to are dropped from the pool. Requests stay pending
+// once committed to, for their encrypted blobs' keys to be released to the auction's winner, until their target
+// block passes.
+func (i *Issuer) Issue(ctx context.Context, targetBlock *big.Int) []commitment.Commitment {
+	if pruned := i.pool.PruneBefore(targetBlock); pruned > 0 {
+		i.logger.Debug("expired preconf requests dropped", "targetBlock", targetBlock, "requests", pruned)
+	}
+	capacity := i.config.BlobsPerBlock
+	for _, escalated := range i.coordinator.Escalated(targetBlock) {
+		capacity -= len(escalated.VersionedHashes)
+	}
+	if capacity <= 0 {
+		return nil
+	}
+	expiryBlock := new(big.Int).Add(targetBlock, new(big.Int).SetUint64(i.config.ValidityBlocks))
+	var issued []commitment.Commitment
+	for _, req := range i.pool.SelectForBlock(targetBlock, capacity) {
+		fee, err := i.quoter.QuoteRequest(req)
+		if err != nil {
+			i.logger.Debug("preconf request not committed to", "id", req.Hash(), "error", err)
+			continue
+		}
+		if err := i.simulate(ctx, req); err != nil {
+			i.logger.Info("preconf request failed simulation", "id", req.Hash(), "error", err)
+			continue
+		}
+		c, err := i.coordinator.Issue(req, fee, expiryBlock)
+		if err != nil {
+			i.logger.Warn("failed to issue commitment", "id", req.Hash(), "error", err)
+			continue
+		}
+		issued = append(issued, *c)
+	}
+	if len(issued) > 0 {
+		i.logger.Info("commitments issued", "targetBlock", targetBlock, "commitments", len(issued))
+	}
+	return issued
+}
+
+func (i *Issuer) simulate(ctx context.Context, req intake.PreconfRequest) error {
+	if i.simulator == nil {
+		return nil
+	}
+	if len(req.Txs) == 0 {
+		return errNoTxs
+	}
+	ctx, cancel := context.WithTimeout(ctx, simulationTimeout)
+	defer cancel()
+	return i.simulator.Simulate(ctx, req.Txs)
+}
@@ -0,0 +1,131 @@
+package issuer_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/issuer"
+	"blob-preconfs/pkg/pricing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type proposerFaults struct{}
+
+// Fails bundles with a tx from the failing sender nonce
+type mockSimulator struct {
+	failingNonce uint64
+}
+
+func (s mockSimulator) Simulate(ctx context.Context, txs []*types.Transaction) error {
+	for _, tx := range txs {
+		if tx.Nonce() == s.failingNonce {
+			return fmt.Errorf("nonce %d already used", tx.Nonce())
+		}
+	}
+	return nil
+}
+
+func (proposerFaults) ClassifyMiss(c commitment.Commitment, block *big.Int) commitment.MissReason {
+	return commitment.MissReasonProposerFault
+}
+
+func submit(t *testing.T, pool *intake.Pool, blobs []common.Hash, targetBlock int64, maxFeeWei int64) intake.PreconfRequest {
+	pk, _ := crypto.GenerateKey()
+	req, err := intake.CreateSignedRequest(blobs, big.NewInt(targetBlock), big.NewInt(maxFeeWei), pk)
+	require.NoError(t, err)
+	_, err = pool.Submit(*req)
+	require.NoError(t, err)
+	return *req
+}
+
+func TestIssue(t *testing.T) {
+	pool, err := intake.NewPool(slog.Default(), intake.Config{}, nil)
+	require.NoError(t, err)
+	quoter := pricing.NewBlobQuoter(pricing.Config{FeePerBlobWei: big.NewInt(1000)})
+	relayKey, _ := crypto.GenerateKey()
+	coordinator := commitment.NewCoordinator(slog.Default(), commitment.Config{EscalateProposerFaults: true, MaxEscalations: 1},
+		proposerFaults{}, quoter, relayKey)
+	i := issuer.NewIssuer(slog.Default(), issuer.Config{BlobsPerBlock: 4, ValidityBlocks: 1}, pool, quoter, coordinator)
+
+	// Missed at its expiry block and escalated to block 101, taking one of its blobs
+	missed := submit(t, pool, []common.Hash{{0x01}}, 99, 1000)
+	require.Len(t, i.Issue(context.Background(), big.NewInt(99)), 1)
+	require.Len(t, coordinator.OnBlock(big.NewInt(100), nil), 1)
+
+	submit(t, pool, []common.Hash{{0x02}}, 100, 1000)
+	paying := submit(t, pool, []common.Hash{{0x03}, {0x04}}, 101, 2000)
+	submit(t, pool, []common.Hash{{0x05}}, 101, 999)
+	submit(t, pool, []common.Hash{{0x06}}, 102, 1000)
+
+	issued := i.Issue(context.Background(), big.NewInt(101))
+	require.Len(t, issued, 1, "underpaying request not committed to")
+	require.Equal(t, paying.Hash(), issued[0].RequestHash)
+	require.Equal(t, big.NewInt(2000), issued[0].FeeWei)
+	require.Equal(t, big.NewInt(102), issued[0].ExpiryBlock)
+	require.Equal(t, missed.Hash(), coordinator.Escalated(big.NewInt(101))[0].RequestHash)
+	require.Len(t, coordinator.ForBlock(big.NewInt(101)), 2, "with the escalated commitment")
+	require.Empty(t, pool.Pending(big.NewInt(100)), "expired requests dropped")
+	require.Len(t, pool.Pending(big.NewInt(101)), 2, "kept for their keys to be released")
+
+	// No capacity left beside the escalated blob
+	i = issuer.NewIssuer(slog.Default(), issuer.Config{BlobsPerBlock: 1, ValidityBlocks: 1}, pool, quoter, coordinator)
+	require.Empty(t, i.Issue(context.Background(), big.NewInt(101)))
+}
+
+func TestIssueSimulated(t *testing.T) {
+	pool, err := intake.NewPool(slog.Default(), intake.Config{}, nil)
+	require.NoError(t, err)
+	quoter := pricing.NewBlobQuoter(pricing.Config{FeePerBlobWei: big.NewInt(1000)})
+	relayKey, _ := crypto.GenerateKey()
+	coordinator := commitment.NewCoordinator(slog.Default(), commitment.Config{}, nil, quoter, relayKey)
+	i := issuer.NewIssuer(slog.Default(), issuer.Config{BlobsPerBlock: 6, ValidityBlocks: 1}, pool, quoter, coordinator)
+	i.SetSimulator(mockSimulator{failingNonce: 1})
+
+	pk, _ := crypto.GenerateKey()
+	var reqs []*intake.PreconfRequest
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		tx := types.NewTx(&types.BlobTx{Nonce: nonce, BlobHashes: []common.Hash{{byte(nonce + 1)}}})
+		req, err := intake.CreateSignedTxRequest([]*types.Transaction{tx}, big.NewInt(100), big.NewInt(1000), pk)
+		require.NoError(t, err)
+		_, err = pool.Submit(*req)
+		require.NoError(t, err)
+		reqs = append(reqs, req)
+	}
+	submit(t, pool, []common.Hash{{0x03}}, 100, 1000)
+
+	issued := i.Issue(context.Background(), big.NewInt(100))
+	require.Len(t, issued, 1, "failing and unsimulated requests not committed to")
+	require.Equal(t, reqs[0].Hash(), issued[0].RequestHash)
+}
+
+func TestWatchIssuesOncePerBlock(t *testing.T) {
+	pool, err := intake.NewPool(slog.Default(), intake.Config{}, nil)
+	require.NoError(t, err)
+	quoter := pricing.NewBlobQuoter(pricing.Config{FeePerBlobWei: big.NewInt(1000)})
+	relayKey, _ := crypto.GenerateKey()
+	coordinator := commitment.NewCoordinator(slog.Default(), commitment.Config{}, nil, quoter, relayKey)
+	submit(t, pool, []common.Hash{{0x01}}, 100, 1000)
+
+	events := make(chan auction.Event, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go issuer.NewIssuer(slog.Default(), issuer.Config{BlobsPerBlock: 6, ValidityBlocks: 1}, pool, quoter, coordinator).Watch(ctx, events)
+	events <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)}
+	events <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)}
+	require.Eventually(t, func() bool {
+		return len(coordinator.ForBlock(big.NewInt(100))) > 0
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, coordinator.ForBlock(big.NewInt(100)), 1)
+}
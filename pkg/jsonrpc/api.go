@@ -0,0 +1,332 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/ratelimit"
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const eventBufferSize = 64
+
+// Satisfied by *listener.Listener
+type AuctionBackend interface {
+	SubmitBid(bid auction.SignedBid) error
+	GetCurrentBid() (winningBid auction.SignedBid, found bool)
+	SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription)
+}
+
+// Satisfied by *escrow.Ledger
+type EscrowBackend interface {
+	Balance(relay common.Address) (escrow.Balance, error)
+}
+
+// Satisfied by *listener.Listener
+type SealedBidBackend interface {
+	SubmitSealedBid(bid sealed.EncryptedBid) error
+	SealingKey() *ecdsa.PublicKey
+}
+
+// Satisfied by *listener.Listener
+type ReceiptBackend interface {
+	SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error)
+}
+
+// Satisfied by *listener.Listener
+type RejectionBackend interface {
+	SubscribeRejections(bufferSize int) (<-chan auction.Rejection, event.Subscription)
+}
+
+// Observes how long leader changes take to reach relays, e.g. *metrics.Metrics
+type Metrics interface {
+	ObserveLeaderPropagation(transport string, latency time.Duration, leader auction.SignedBid)
+}
+
+// Served under the "auction" namespace, e.g. auction_submitBid
+type AuctionAPI struct {
+	logger     *slog.Logger
+	backend    AuctionBackend
+	limiter    *ratelimit.BidLimiter
+	metrics    Metrics
+	escrow     EscrowBackend
+	rejections RejectionBackend
+	sealed     SealedBidBackend
+	receipts   ReceiptBackend
+	// Handshake context of an authenticated websocket connection, carrying its relay, see Server.serveRelayWebsocket
+	authCtx context.Context
+}
+
+// Rate limited requests are returned with the conventional "limit exceeded" error code
+type limitExceededError struct{ error }
+
+func (e limitExceededError) ErrorCode() int { return -32005 }
+
+// Malformed bids are returned with the "invalid params" error code
+type invalidBidError struct{ error }
+
+func (e invalidBidError) ErrorCode() int { return -32602 }
+
+// Stable error codes of rejected bids, in the implementation-defined server error range
+var rejectErrorCodes = map[auction.RejectCode]int{
+	auction.RejectNoAuction:        -32010,
+	auction.RejectWrongBlock:       -32011,
+	auction.RejectInvalidSignature: -32012,
+	auction.RejectDenied:           -32013,
+	auction.RejectNotAllowed:       -32014,
+	auction.RejectNotRegistered:    -32015,
+	auction.RejectDuplicate:        -32016,
+	auction.RejectOutbid:           -32017,
+	auction.RejectBelowReserve:     -32018,
+	auction.RejectUncovered:        -32019,
+	auction.RejectOverQuota:        -32020,
+	auction.RejectOverBondCap:      -32021,
+}
+
+// Rejected bids are returned with their code's error code, and the code itself as error data
+type rejectedError struct {
+	error
+	code auction.RejectCode
+}
+
+func (e rejectedError) ErrorCode() int { return rejectErrorCodes[e.code] }
+
+func (e rejectedError) ErrorData() any { return e.code }
+
+// Rejection error carried by a call's error data, or err if it carries none. For the API's clients, so
+// errors.Is matches e.g. auction.ErrBelowReserve across the wire.
+func RejectionOf(err error) error {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err
+	}
+	data, _ := dataErr.ErrorData().(string)
+	if _, ok := rejectErrorCodes[auction.RejectCode(data)]; !ok {
+		return err
+	}
+	return auction.RejectCode(data).Err()
+}
+
+// Maps bid validation and backend errors to their error codes
+func bidError(err error) error {
+	if code, ok := auction.RejectCodeOf(err); ok {
+		return rejectedError{err, code}
+	}
+	if errors.Is(err, auction.ErrInvalidBid) {
+		return invalidBidError{err}
+	}
+	return err
+}
+
+func NewAuctionAPI(logger *slog.Logger, backend AuctionBackend, limiter *ratelimit.BidLimiter) *AuctionAPI {
+	return &AuctionAPI{
+		logger:  logger,
+		backend: backend,
+		limiter: limiter,
+		authCtx: context.Background(),
+	}
+}
+
+// Context authenticating the call: its own over HTTP, the handshake's on an authenticated websocket connection,
+// whose relay the rpc package doesn't pass on to calls
+func (api *AuctionAPI) authContext(ctx context.Context) context.Context {
+	if _, ok := auth.RelayFromContext(ctx); ok {
+		return ctx
+	}
+	return api.authCtx
+}
+
+func (api *AuctionAPI) SubmitBid(ctx context.Context, bid auction.SignedBid) error {
+	if err := api.checkBid(ctx, bid); err != nil {
+		return err
+	}
+	if err := api.backend.SubmitBid(bid); err != nil {
+		api.logger.Debug("bid submission rejected", "bid", bid, "error", err)
+		return bidError(err)
+	}
+	return nil
+}
+
+// Submits a bid like SubmitBid, returning the auctioneer's signed receipt of when it arrived
+func (api *AuctionAPI) SubmitBidWithReceipt(ctx context.Context, bid auction.SignedBid) (*auction.Receipt, error) {
+	if api.receipts == nil {
+		return nil, fmt.Errorf("bid receipts not available")
+	}
+	if err := api.checkBid(ctx, bid); err != nil {
+		return nil, err
+	}
+	receipt, err := api.receipts.SubmitBidWithReceipt(bid)
+	if err != nil {
+		api.logger.Debug("bid submission rejected", "bid", bid, "error", err)
+		return nil, bidError(err)
+	}
+	return receipt, nil
+}
+
+// Checks a bid is well formed, signed by the authenticated relay and within the rate limits
+func (api *AuctionAPI) checkBid(ctx context.Context, bid auction.SignedBid) error {
+	if err := api.limiter.AllowIP(rpc.PeerInfoFromContext(ctx).RemoteAddr); err != nil {
+		return limitExceededError{err}
+	}
+	if err := bid.Validate(); err != nil {
+		return bidError(err)
+	}
+	if err := auth.CheckSigner(api.authContext(ctx), bid.Address); err != nil {
+		return err
+	}
+	if err := api.limiter.AllowSigner(bid); err != nil {
+		return limitExceededError{err}
+	}
+	return nil
+}
+
+// Bid encrypted to the sealing key, opened as the auction closes
+func (api *AuctionAPI) SubmitSealedBid(ctx context.Context, bid sealed.EncryptedBid) error {
+	if api.sealed == nil {
+		return fmt.Errorf("sealed bids not available")
+	}
+	if err := api.limiter.AllowIP(rpc.PeerInfoFromContext(ctx).RemoteAddr); err != nil {
+		return limitExceededError{err}
+	}
+	if err := bid.Validate(); err != nil {
+		return bidError(err)
+	}
+	if err := auth.CheckSigner(api.authContext(ctx), bid.Address); err != nil {
+		return err
+	}
+	// Limited as the relay's bids, whose amount is sealed
+	if err := api.limiter.AllowSigner(auction.SignedBid{Address: bid.Address}); err != nil {
+		return limitExceededError{err}
+	}
+	if err := api.sealed.SubmitSealedBid(bid); err != nil {
+		api.logger.Debug("sealed bid submission rejected", "bidder", bid.Address, "error", err)
+		return bidError(err)
+	}
+	return nil
+}
+
+// Compressed public key bids are sealed to, see sealed.Seal
+func (api *AuctionAPI) GetSealingKey() (hexutil.Bytes, error) {
+	if api.sealed == nil || api.sealed.SealingKey() == nil {
+		return nil, fmt.Errorf("sealed bids not available")
+	}
+	return crypto.CompressPubkey(api.sealed.SealingKey()), nil
+}
+
+func (api *AuctionAPI) GetCurrentBid() (*auction.SignedBid, error) {
+	bid, found := api.backend.GetCurrentBid()
+	if !found {
+		return nil, bidError(auction.ErrNoActiveAuction)
+	}
+	return &bid, nil
+}
+
+// Relay's escrow balance, pending debits from unsettled wins and effective max bid
+func (api *AuctionAPI) GetEscrow(relay common.Address) (*escrow.Balance, error) {
+	if api.escrow == nil {
+		return nil, fmt.Errorf("escrow not available")
+	}
+	balance, err := api.escrow.Balance(relay)
+	if err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// Subscription to auction events over websocket, via auction_subscribe("events", relay). Bond events are only
+// delivered for relay, which defaults to the relay authenticated at the handshake, and which may only subscribe to
+// its own. Trusted forwarders get every relay's, to deliver to their own subscribers.
+func (api *AuctionAPI) Events(ctx context.Context, relay *common.Address) (*rpc.Subscription, error) {
+	authCtx := api.authContext(ctx)
+	if relay != nil {
+		if err := auth.CheckSigner(authCtx, *relay); err != nil {
+			return nil, err
+		}
+	} else if authenticated, ok := auth.RelayFromContext(authCtx); ok {
+		relay = &authenticated
+	}
+	forwarder := auth.IsForwarder(authCtx)
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events, sub := api.backend.SubscribeEvents(eventBufferSize)
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				if !forwarder && !ev.DeliverableTo(relay) {
+					continue
+				}
+				if err := notifier.Notify(rpcSub.ID, ev); err != nil {
+					api.logger.Debug("failed to notify auction event subscriber", "error", err)
+					return
+				}
+				if api.metrics != nil && ev.Type == auction.EventLeaderChanged {
+					api.metrics.ObserveLeaderPropagation("websocket", time.Since(ev.Timestamp), *ev.Bid)
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// Subscription to the relay's own rejected bids over websocket, via auction_subscribe("rejections", relay).
+// Connections authenticated at the handshake may only subscribe to their relay's.
+func (api *AuctionAPI) Rejections(ctx context.Context, relay common.Address) (*rpc.Subscription, error) {
+	if api.rejections == nil {
+		return nil, fmt.Errorf("bid rejections not available")
+	}
+	if err := auth.CheckSigner(api.authContext(ctx), relay); err != nil {
+		return nil, err
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		rejections, sub := api.rejections.SubscribeRejections(eventBufferSize)
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case rejection, ok := <-rejections:
+				if !ok {
+					return
+				}
+				if rejection.Bid.Address != relay {
+					continue
+				}
+				if err := notifier.Notify(rpcSub.ID, rejection); err != nil {
+					api.logger.Debug("failed to notify bid rejection subscriber", "error", err)
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
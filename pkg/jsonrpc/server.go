@@ -0,0 +1,213 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type Server struct {
+	logger         *slog.Logger
+	rpcServer      *rpc.Server
+	api            *AuctionAPI
+	allowedOrigins []string
+	httpServer     *http.Server
+	listener       net.Listener
+	limits         ratelimit.TransportConfig
+	// Nil unless limits have a per-connection rate
+	connLimiter *ratelimit.ConnLimiter
+
+	connsMu sync.Mutex // Protects conns
+	// Servers of websocket connections authenticated at the handshake, see serveRelayWebsocket
+	conns map[*rpc.Server]struct{}
+}
+
+// Serves HTTP and websocket connections on the same address. allowedOrigins applies to websocket connections.
+// Bid submissions are rate limited by limiter, if non-nil.
+// If verifier is non-nil, all requests must be signed by a registered relay, websocket connections at the handshake.
+// Served over TLS if tlsConfig is non-nil, see tlsconfig.
+func NewServer(
+	logger *slog.Logger,
+	addr string,
+	backend AuctionBackend,
+	allowedOrigins []string,
+	limiter *ratelimit.BidLimiter,
+	verifier *auth.Verifier,
+	tlsConfig *tls.Config,
+) (*Server, error) {
+	rpcServer := rpc.NewServer()
+	rpcServer.SetHTTPBodyLimit(ratelimit.DefaultMaxMessageSize)
+	api := NewAuctionAPI(logger, backend, limiter)
+	if err := rpcServer.RegisterName("auction", api); err != nil {
+		return nil, err
+	}
+	s := &Server{
+		logger:         logger,
+		rpcServer:      rpcServer,
+		api:            api,
+		allowedOrigins: allowedOrigins,
+		limits:         ratelimit.TransportConfig{MaxMessageSize: ratelimit.DefaultMaxMessageSize},
+		httpServer: &http.Server{
+			Addr:              addr,
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		conns: make(map[*rpc.Server]struct{}),
+	}
+	s.httpServer.Handler = s.handler()
+	if verifier != nil {
+		s.httpServer.Handler = verifier.Middleware(s.httpServer.Handler)
+	}
+	return s, nil
+}
+
+// Leader change propagation to websocket subscribers is observed, if set before the server starts
+func (s *Server) SetMetrics(metrics Metrics) {
+	s.api.metrics = metrics
+}
+
+// Serves relays' escrow balances with auction_getEscrow, if set before the server starts
+func (s *Server) SetEscrow(escrow EscrowBackend) {
+	s.api.escrow = escrow
+}
+
+// Serves relays' own rejected bids with auction_subscribe("rejections", relay), if set before the server starts
+func (s *Server) SetRejections(rejections RejectionBackend) {
+	s.api.rejections = rejections
+}
+
+// Accepts bids sealed to the backend's key with auction_submitSealedBid, and serves the key with
+// auction_getSealingKey, if set before the server starts
+func (s *Server) SetSealedBids(backend SealedBidBackend) {
+	s.api.sealed = backend
+}
+
+// Acknowledges bids submitted with auction_submitBidWithReceipt with the backend's signed receipt, if set before
+// the server starts
+func (s *Server) SetReceipts(backend ReceiptBackend) {
+	s.api.receipts = backend
+}
+
+// Limits the size of HTTP request bodies and websocket messages, and the messages each connection may send,
+// rejecting HTTP requests over the rate with 429 and closing websocket connections. Must be called before Start
+// and Mount. Messages are limited to ratelimit.DefaultMaxMessageSize if unset.
+func (s *Server) SetLimits(limits ratelimit.TransportConfig) {
+	s.limits = limits
+	s.rpcServer.SetHTTPBodyLimit(limits.MaxMessageSize)
+	if limits.PerConn.Rate <= 0 {
+		return
+	}
+	s.connLimiter = ratelimit.NewConnLimiter(limits.PerConn)
+	handler := s.httpServer.Handler
+	s.httpServer.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Websocket messages are limited once upgraded
+		if isWebsocket(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		if err := s.connLimiter.Allow(r.RemoteAddr); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	if s.httpServer.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.httpServer.TLSConfig)
+	}
+	s.listener = listener
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("json-rpc server failed", "error", err)
+		}
+	}()
+	s.logger.Info("json-rpc server started", "addr", listener.Addr(), "tls", s.httpServer.TLSConfig != nil)
+	return nil
+}
+
+// Address the server is listening on, useful when started on port 0
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serves other's API under prefix, e.g. /chains/holesky, alongside this server's, for another chain's auctions in
+// a multi-chain process. Must be called before Start. other is never started, but must be stopped with this server.
+func (s *Server) Mount(prefix string, other *Server) {
+	mounted := http.StripPrefix(prefix, other.httpServer.Handler)
+	mux := http.NewServeMux()
+	mux.Handle(prefix, mounted)
+	mux.Handle(prefix+"/", mounted)
+	mux.Handle("/", s.httpServer.Handler)
+	s.httpServer.Handler = mux
+}
+
+// Stops accepting new requests and waits for in-flight ones until ctx is done
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.rpcServer.Stop()
+	s.connsMu.Lock()
+	for conn := range s.conns {
+		conn.Stop()
+	}
+	s.connsMu.Unlock()
+	s.logger.Info("json-rpc server stopped")
+	return err
+}
+
+func (s *Server) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebsocket(r) {
+			if _, ok := auth.RelayFromContext(r.Context()); ok {
+				s.serveRelayWebsocket(w, r)
+				return
+			}
+			s.serveWebsocket(w, r, s.rpcServer)
+			return
+		}
+		s.rpcServer.ServeHTTP(w, r)
+	})
+}
+
+// Serves a websocket connection authenticated at the handshake with its own rpc server, whose API checks calls
+// against the handshake's relay. The rpc package doesn't pass the handshake's context on to calls.
+func (s *Server) serveRelayWebsocket(w http.ResponseWriter, r *http.Request) {
+	api := *s.api
+	api.authCtx = r.Context()
+	conn := rpc.NewServer()
+	if err := conn.RegisterName("auction", &api); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.connsMu.Lock()
+	s.conns[conn] = struct{}{}
+	s.connsMu.Unlock()
+	defer func() {
+		s.connsMu.Lock()
+		delete(s.conns, conn)
+		s.connsMu.Unlock()
+		conn.Stop()
+	}()
+	s.serveWebsocket(w, r, conn)
+}
+
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
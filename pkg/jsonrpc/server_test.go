@@ -0,0 +1,534 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/jsonrpc"
+	"blob-preconfs/pkg/ratelimit"
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackend struct {
+	submitted  []auction.SignedBid
+	currentBid *auction.SignedBid
+	submitErr  error
+	feed       event.Feed
+}
+
+func (m *mockBackend) SubmitBid(bid auction.SignedBid) error {
+	if m.submitErr != nil {
+		return m.submitErr
+	}
+	m.submitted = append(m.submitted, bid)
+	return nil
+}
+
+func (m *mockBackend) GetCurrentBid() (auction.SignedBid, bool) {
+	if m.currentBid == nil {
+		return auction.SignedBid{}, false
+	}
+	return *m.currentBid, true
+}
+
+func (m *mockBackend) SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription) {
+	ch := make(chan auction.Event, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+func startServer(t *testing.T, backend jsonrpc.AuctionBackend) *jsonrpc.Server {
+	return startServerWithLimiter(t, backend, nil)
+}
+
+func startServerWithLimiter(t *testing.T, backend jsonrpc.AuctionBackend, limiter *ratelimit.BidLimiter) *jsonrpc.Server {
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, []string{"*"}, limiter, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	return server
+}
+
+func dialHTTP(t *testing.T, backend jsonrpc.AuctionBackend) *rpc.Client {
+	server := startServer(t, backend)
+	client, err := rpc.DialHTTP("http://" + server.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestSubmitBid(t *testing.T) {
+	backend := &mockBackend{}
+	client := dialHTTP(t, backend)
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.NoError(t, client.Call(nil, "auction_submitBid", bid))
+	require.Len(t, backend.submitted, 1)
+	require.Equal(t, bid.Address, backend.submitted[0].Address)
+
+	tampered := *bid
+	tampered.AmountWei = big.NewInt(44)
+	require.ErrorContains(t, client.Call(nil, "auction_submitBid", tampered), "signature does not match address")
+
+	zero := auction.MustCreateSignedBid(big.NewInt(0), big.NewInt(100), pk)
+	require.ErrorContains(t, client.Call(nil, "auction_submitBid", zero), "invalid amountWei")
+
+	backend.submitErr = errors.New("no auction in progress")
+	require.ErrorContains(t, client.Call(nil, "auction_submitBid", bid), "no auction in progress")
+	require.Len(t, backend.submitted, 1)
+}
+
+func TestSubmitBidRejected(t *testing.T) {
+	backend := &mockBackend{}
+	client := dialHTTP(t, backend)
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+
+	backend.submitErr = auction.ErrBelowReserve
+	err := client.Call(nil, "auction_submitBid", bid)
+	var rpcErr rpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, -32018, rpcErr.ErrorCode())
+	require.ErrorIs(t, jsonrpc.RejectionOf(err), auction.ErrBelowReserve, "code carried in the error data")
+
+	backend.submitErr = fmt.Errorf("upstream rejected bid: %w", auction.ErrUnregisteredRelay)
+	require.ErrorIs(t, jsonrpc.RejectionOf(client.Call(nil, "auction_submitBid", bid)), auction.ErrUnregisteredRelay, "wrapped")
+
+	zero := auction.MustCreateSignedBid(big.NewInt(0), big.NewInt(100), pk)
+	require.ErrorAs(t, client.Call(nil, "auction_submitBid", zero), &rpcErr)
+	require.Equal(t, -32602, rpcErr.ErrorCode(), "malformed bids are invalid params")
+
+	backend.submitErr = errors.New("backend failed")
+	err = client.Call(nil, "auction_submitBid", bid)
+	require.Equal(t, err, jsonrpc.RejectionOf(err), "other errors are kept")
+}
+
+func TestSubmitBidRateLimited(t *testing.T) {
+	backend := &mockBackend{}
+	limiter := ratelimit.NewBidLimiter(ratelimit.Config{Rate: 1, Burst: 3}, ratelimit.Config{Rate: 1, Burst: 1})
+	server := startServerWithLimiter(t, backend, limiter)
+	client, err := rpc.DialHTTP("http://" + server.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	bid1 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)
+	bid2 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk2)
+
+	require.NoError(t, client.Call(nil, "auction_submitBid", bid1))
+	err = client.Call(nil, "auction_submitBid", bid1)
+	var rpcErr rpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, -32005, rpcErr.ErrorCode())
+	require.ErrorContains(t, err, "for relay")
+
+	require.NoError(t, client.Call(nil, "auction_submitBid", bid2))
+	require.ErrorContains(t, client.Call(nil, "auction_submitBid", bid2), "for ip")
+	require.Len(t, backend.submitted, 2)
+}
+
+func TestTransportLimits(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	backend := &mockBackend{currentBid: auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, []string{"*"}, nil, nil, nil)
+	require.NoError(t, err)
+	server.SetLimits(ratelimit.TransportConfig{MaxMessageSize: 1024, PerConn: ratelimit.Config{Rate: 0.1, Burst: 3}})
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	oversized := *backend.currentBid
+	oversized.Signature = make([]byte, 1024)
+
+	// Each client on its own connection
+	dial := func(url string) *rpc.Client {
+		client, err := rpc.DialOptions(context.Background(), url, rpc.WithHTTPClient(&http.Client{Transport: &http.Transport{}}))
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+		return client
+	}
+	for _, url := range []string{"http://" + server.Addr().String(), "ws://" + server.Addr().String()} {
+		require.Error(t, dial(url).Call(nil, "auction_submitBid", oversized), url)
+		require.Empty(t, backend.submitted, url)
+
+		client := dial(url)
+		var bid auction.SignedBid
+		for i := 0; i < 3; i++ {
+			require.NoError(t, client.Call(&bid, "auction_getCurrentBid"), url)
+		}
+		require.Error(t, client.Call(&bid, "auction_getCurrentBid"), "%s: connection's message rate exceeded", url)
+		require.NoError(t, dial(url).Call(&bid, "auction_getCurrentBid"), "%s: other connections unaffected", url)
+	}
+}
+
+type mockRegistry struct {
+	registered map[common.Address]bool
+}
+
+func (r *mockRegistry) IsRegisteredOnSettlementLayer(address common.Address) bool {
+	return r.registered[address]
+}
+
+func TestAuthenticatedRelays(t *testing.T) {
+	backend := &mockBackend{}
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	registry := &mockRegistry{registered: map[common.Address]bool{
+		crypto.PubkeyToAddress(pk1.PublicKey): true,
+		crypto.PubkeyToAddress(pk2.PublicKey): true,
+	}}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, []string{"*"}, nil, auth.NewVerifier(registry, 30*time.Second), nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	url := "http://" + server.Addr().String()
+	bid1 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)
+
+	anonymous, err := rpc.DialHTTP(url)
+	require.NoError(t, err)
+	defer anonymous.Close()
+	require.ErrorContains(t, anonymous.Call(nil, "auction_submitBid", bid1), "401")
+
+	signed, err := rpc.DialOptions(context.Background(), url, rpc.WithHTTPClient(&http.Client{Transport: &auth.Transport{PrivateKey: pk1}}))
+	require.NoError(t, err)
+	defer signed.Close()
+	require.NoError(t, signed.Call(nil, "auction_submitBid", bid1))
+	bid2 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk2)
+	require.ErrorContains(t, signed.Call(nil, "auction_submitBid", bid2), "does not match authenticated relay")
+	require.Len(t, backend.submitted, 1)
+
+	_, err = rpc.DialWebsocket(context.Background(), "ws://"+server.Addr().String(), "")
+	require.Error(t, err)
+	header, err := auth.Headers("/", nil, pk1)
+	require.NoError(t, err)
+	ws, err := rpc.DialOptions(context.Background(), "ws://"+server.Addr().String(), rpc.WithHeaders(header))
+	require.NoError(t, err)
+	defer ws.Close()
+	require.NoError(t, ws.Call(nil, "auction_submitBid", bid1))
+	require.ErrorContains(t, ws.Call(nil, "auction_submitBid", bid2), auth.ErrSignerMismatch.Error(),
+		"websocket calls are checked against the handshake's relay")
+	require.Len(t, backend.submitted, 2)
+}
+
+func TestGetCurrentBid(t *testing.T) {
+	backend := &mockBackend{}
+	client := dialHTTP(t, backend)
+
+	var bid auction.SignedBid
+	require.ErrorContains(t, client.Call(&bid, "auction_getCurrentBid"), "no auction in progress")
+
+	pk, _ := crypto.GenerateKey()
+	backend.currentBid = auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.NoError(t, client.Call(&bid, "auction_getCurrentBid"))
+	require.Equal(t, *backend.currentBid, bid)
+}
+
+type mockEscrow struct{ balance escrow.Balance }
+
+func (m *mockEscrow) Balance(relay common.Address) (escrow.Balance, error) {
+	if relay != m.balance.Relay {
+		return escrow.Balance{}, escrow.ErrNotBonded
+	}
+	return m.balance, nil
+}
+
+func TestGetEscrow(t *testing.T) {
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, nil, nil)
+	require.NoError(t, err)
+	relay := common.HexToAddress("0x01")
+	balance := escrow.Balance{Relay: relay, BalanceWei: big.NewInt(1000), PendingDebitsWei: big.NewInt(0), MaxBidWei: big.NewInt(1000)}
+	server.SetEscrow(&mockEscrow{balance: balance})
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	client, err := rpc.DialHTTP("http://" + server.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	var got escrow.Balance
+	require.NoError(t, client.Call(&got, "auction_getEscrow", relay))
+	require.Equal(t, balance, got)
+	require.ErrorContains(t, client.Call(&got, "auction_getEscrow", common.HexToAddress("0x02")), "relay not bonded")
+	require.ErrorContains(t, dialHTTP(t, &mockBackend{}).Call(&got, "auction_getEscrow", relay), "escrow not available")
+}
+
+func TestMount(t *testing.T) {
+	primary, holesky := &mockBackend{}, &mockBackend{}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", primary, []string{"*"}, nil, nil, nil)
+	require.NoError(t, err)
+	mounted, err := jsonrpc.NewServer(slog.Default(), "", holesky, []string{"*"}, nil, nil, nil)
+	require.NoError(t, err)
+	server.Mount("/chains/holesky", mounted)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		server.Stop(context.Background())
+		mounted.Stop(context.Background())
+	})
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	for url, backend := range map[string]*mockBackend{"": primary, "/chains/holesky": holesky} {
+		client, err := rpc.DialHTTP("http://" + server.Addr().String() + url)
+		require.NoError(t, err)
+		require.NoError(t, client.Call(nil, "auction_submitBid", bid))
+		client.Close()
+		require.Len(t, backend.submitted, 1, url)
+	}
+}
+
+type mockMetrics struct {
+	mu         sync.Mutex
+	transports []string
+}
+
+func (m *mockMetrics) ObserveLeaderPropagation(transport string, latency time.Duration, leader auction.SignedBid) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transports = append(m.transports, transport)
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	backend := &mockBackend{}
+	metrics := &mockMetrics{}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, []string{"*"}, nil, nil, nil)
+	require.NoError(t, err)
+	server.SetMetrics(metrics)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	client, err := rpc.DialWebsocket(context.Background(), "ws://"+server.Addr().String(), "")
+	require.NoError(t, err)
+	defer client.Close()
+
+	events := make(chan auction.Event, 1)
+	sub, err := client.Subscribe(context.Background(), "auction", events, "events")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	pk, _ := crypto.GenerateKey()
+	sent := auction.Event{
+		Type:      auction.EventLeaderChanged,
+		L1Block:   big.NewInt(100),
+		Bid:       auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk),
+		Timestamp: time.Now().UTC(),
+	}
+	require.Eventually(t, func() bool { return backend.feed.Send(sent) > 0 }, time.Second, 10*time.Millisecond)
+
+	select {
+	case ev := <-events:
+		require.Equal(t, sent.Type, ev.Type)
+		require.Equal(t, sent.L1Block, ev.L1Block)
+		require.Equal(t, *sent.Bid, *ev.Bid)
+		require.True(t, sent.Timestamp.Equal(ev.Timestamp))
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+	require.Eventually(t, func() bool {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return len(metrics.transports) == 1 && metrics.transports[0] == "websocket"
+	}, time.Second, 10*time.Millisecond)
+}
+
+type mockRejections struct{ feed event.Feed }
+
+func (m *mockRejections) SubscribeRejections(bufferSize int) (<-chan auction.Rejection, event.Subscription) {
+	ch := make(chan auction.Rejection, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+func TestSubscribeRejections(t *testing.T) {
+	rejections := &mockRejections{}
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	registry := &mockRegistry{registered: map[common.Address]bool{
+		crypto.PubkeyToAddress(pk1.PublicKey): true,
+		crypto.PubkeyToAddress(pk2.PublicKey): true,
+	}}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, auth.NewVerifier(registry, 30*time.Second), nil)
+	require.NoError(t, err)
+	server.SetRejections(rejections)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	header, err := auth.Headers("/", nil, pk1)
+	require.NoError(t, err)
+	client, err := rpc.DialOptions(context.Background(), "ws://"+server.Addr().String(), rpc.WithHeaders(header))
+	require.NoError(t, err)
+	defer client.Close()
+
+	received := make(chan auction.Rejection, 2)
+	_, err = client.Subscribe(context.Background(), "auction", received, "rejections", crypto.PubkeyToAddress(pk2.PublicKey))
+	require.ErrorContains(t, err, "does not match authenticated relay")
+	sub, err := client.Subscribe(context.Background(), "auction", received, "rejections", crypto.PubkeyToAddress(pk1.PublicKey))
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	other := auction.Rejection{Bid: *auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk2), Code: auction.RejectOutbid}
+	own := auction.Rejection{
+		Bid:       *auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1),
+		Code:      auction.RejectOutbid,
+		Reason:    auction.RejectOutbid.Reason(),
+		Leader:    auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk2),
+		Timestamp: time.Now().UTC(),
+	}
+	require.Eventually(t, func() bool { return rejections.feed.Send(other) > 0 }, time.Second, 10*time.Millisecond)
+	rejections.feed.Send(own)
+
+	select {
+	case rejection := <-received:
+		require.Equal(t, own.Bid, rejection.Bid)
+		require.Equal(t, own.Code, rejection.Code)
+		require.Equal(t, *own.Leader, *rejection.Leader)
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("rejection not received")
+	}
+	require.Empty(t, received)
+}
+
+func TestSubscribeBondEvents(t *testing.T) {
+	backend := &mockBackend{}
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	relay1, relay2 := crypto.PubkeyToAddress(pk1.PublicKey), crypto.PubkeyToAddress(pk2.PublicKey)
+	registry := &mockRegistry{registered: map[common.Address]bool{relay1: true, relay2: true}}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, []string{"*"}, nil, auth.NewVerifier(registry, 30*time.Second), nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	header, err := auth.Headers("/", nil, pk1)
+	require.NoError(t, err)
+	client, err := rpc.DialOptions(context.Background(), "ws://"+server.Addr().String(), rpc.WithHeaders(header))
+	require.NoError(t, err)
+	defer client.Close()
+
+	events := make(chan auction.Event, 2)
+	_, err = client.Subscribe(context.Background(), "auction", events, "events", relay2)
+	require.ErrorContains(t, err, "does not match authenticated relay")
+	// Defaults to the authenticated relay
+	sub, err := client.Subscribe(context.Background(), "auction", events, "events")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	bondLow := func(relay common.Address) auction.Event {
+		return auction.Event{
+			Type:    auction.EventBondLow,
+			L1Block: big.NewInt(100),
+			Bond:    &auction.BondStatus{Relay: relay, BalanceWei: big.NewInt(100), PendingDebitsWei: big.NewInt(90), CapWei: big.NewInt(10)},
+		}
+	}
+	require.Eventually(t, func() bool { return backend.feed.Send(bondLow(relay2)) > 0 }, time.Second, 10*time.Millisecond)
+	backend.feed.Send(bondLow(relay1))
+
+	select {
+	case ev := <-events:
+		require.Equal(t, auction.EventBondLow, ev.Type)
+		require.Equal(t, relay1, ev.Bond.Relay)
+		require.Equal(t, big.NewInt(10), ev.Bond.CapWei)
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+	require.Empty(t, events, "other relays' bond events withheld")
+}
+
+type mockSealed struct {
+	key       *ecdsa.PrivateKey
+	submitted []sealed.EncryptedBid
+}
+
+func (m *mockSealed) SubmitSealedBid(bid sealed.EncryptedBid) error {
+	m.submitted = append(m.submitted, bid)
+	return nil
+}
+
+func (m *mockSealed) SealingKey() *ecdsa.PublicKey {
+	return &m.key.PublicKey
+}
+
+func TestSubmitSealedBid(t *testing.T) {
+	sealingKey, _ := crypto.GenerateKey()
+	backend := &mockSealed{key: sealingKey}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, nil, nil)
+	require.NoError(t, err)
+	server.SetSealedBids(backend)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	client, err := rpc.DialHTTP("http://" + server.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	var key hexutil.Bytes
+	require.NoError(t, client.Call(&key, "auction_getSealingKey"))
+	pub, err := crypto.DecompressPubkey(key)
+	require.NoError(t, err)
+	pk, _ := crypto.GenerateKey()
+	bid, err := sealed.Seal(big.NewInt(43), big.NewInt(100), pub, pk)
+	require.NoError(t, err)
+	require.NoError(t, client.Call(nil, "auction_submitSealedBid", bid))
+	require.Len(t, backend.submitted, 1)
+	opened, err := sealed.Open(backend.submitted[0], sealingKey)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(43), opened.AmountWei)
+
+	bid.L1Block = big.NewInt(101)
+	require.Error(t, client.Call(nil, "auction_submitSealedBid", bid), "envelope signed for another block")
+	require.Len(t, backend.submitted, 1)
+	require.ErrorContains(t, dialHTTP(t, &mockBackend{}).Call(nil, "auction_submitSealedBid", bid), "sealed bids not available")
+}
+
+type mockReceipts struct {
+	key       *ecdsa.PrivateKey
+	submitErr error
+}
+
+func (m *mockReceipts) SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error) {
+	if m.submitErr != nil {
+		return nil, m.submitErr
+	}
+	return auction.CreateSignedReceipt(bid, time.Now(), m.key)
+}
+
+func TestSubmitBidWithReceipt(t *testing.T) {
+	auctioneerKey, _ := crypto.GenerateKey()
+	backend := &mockReceipts{key: auctioneerKey}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, nil, nil)
+	require.NoError(t, err)
+	server.SetReceipts(backend)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	client, err := rpc.DialHTTP("http://" + server.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	var receipt auction.Receipt
+	require.NoError(t, client.Call(&receipt, "auction_submitBidWithReceipt", bid))
+	require.True(t, receipt.Verify())
+	require.True(t, receipt.Covers(*bid))
+	require.Equal(t, crypto.PubkeyToAddress(auctioneerKey.PublicKey), receipt.Auctioneer)
+
+	backend.submitErr = auction.ErrOutbid
+	require.ErrorIs(t, jsonrpc.RejectionOf(client.Call(&receipt, "auction_submitBidWithReceipt", bid)), auction.ErrOutbid)
+	require.ErrorContains(t, dialHTTP(t, &mockBackend{}).Call(nil, "auction_submitBidWithReceipt", bid), "bid receipts not available")
+}
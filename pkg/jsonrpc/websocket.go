@@ -0,0 +1,104 @@
+package jsonrpc
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Connections are pinged when idle this long, and dropped if neither a message nor a pong arrives within
+	// wsPongTimeout after
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
+)
+
+// Serves a websocket connection with server, as rpc.Server.WebsocketHandler does, but reading messages up to
+// the server's message size limit, and closing connections exceeding their message rate. The rpc package
+// accepts 32MB websocket messages whatever the server's limits.
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request, server *rpc.Server) {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     s.checkOrigin,
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Debug("websocket upgrade failed", "error", err)
+		return
+	}
+	conn.SetReadLimit(int64(s.limits.MaxMessageSize))
+	ws := &wsConn{Conn: conn, remoteAddr: r.RemoteAddr, limiter: s.connLimiter, done: make(chan struct{})}
+	conn.SetReadDeadline(time.Now().Add(wsPingInterval + wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPingInterval + wsPongTimeout))
+	})
+	go ws.ping()
+	defer close(ws.done)
+	server.ServeCodec(rpc.NewFuncCodec(ws, ws.writeJSON, ws.readJSON), 0)
+}
+
+// Allows requests without an Origin, from non-browser clients, and those from allowedOrigins
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range s.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Websocket connection as an rpc codec's transport
+type wsConn struct {
+	*websocket.Conn
+	remoteAddr string
+	limiter    *ratelimit.ConnLimiter
+	done       chan struct{}
+}
+
+// Satisfies rpc.ConnRemoteAddr, so calls see the connection's address in their rpc.PeerInfo
+func (c *wsConn) RemoteAddr() string {
+	return c.remoteAddr
+}
+
+func (c *wsConn) readJSON(v any) error {
+	if err := c.ReadJSON(v); err != nil {
+		return err
+	}
+	if err := c.limiter.Allow(c.remoteAddr); err != nil {
+		c.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()),
+			time.Now().Add(wsWriteTimeout))
+		return err
+	}
+	return c.SetReadDeadline(time.Now().Add(wsPingInterval + wsPongTimeout))
+}
+
+// Called with the codec's write lock held, and its write deadline set
+func (c *wsConn) writeJSON(v any, isErrorResponse bool) error {
+	return c.WriteJSON(v)
+}
+
+func (c *wsConn) ping() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+// keys subcommands managing a Store, shared by the auctioneer and bidder CLIs
+func NewCommand() *cobra.Command {
+	var dir, passwordFile string
+	var lightKDF bool
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage signing keys, encrypted at rest in a keystore directory",
+	}
+	cmd.PersistentFlags().StringVar(&dir, "keystore-dir", "keystore", "Keystore directory")
+	cmd.PersistentFlags().StringVar(&passwordFile, "password-file", "", "File with the keystore password (required to store or rotate keys)")
+	cmd.PersistentFlags().BoolVar(&lightKDF, "light-kdf", false, "Encrypt keys with less memory and CPU, at the expense of brute force resistance")
+	open := func(needPassword bool) (*Store, string, error) {
+		var password string
+		if needPassword {
+			if passwordFile == "" {
+				return nil, "", fmt.Errorf("%w: --password-file required", ErrInvalidSource)
+			}
+			var err error
+			if password, err = ReadPassword(passwordFile); err != nil {
+				return nil, "", err
+			}
+		}
+		store, err := NewStore(dir)
+		if err != nil {
+			return nil, "", err
+		}
+		if lightKDF {
+			store.SetLightScrypt()
+		}
+		return store, password, nil
+	}
+
+	generate := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a key, which becomes active if there's none",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, password, err := open(true)
+			if err != nil {
+				return err
+			}
+			address, err := store.Generate(password)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), address.Hex())
+			return nil
+		},
+	}
+
+	var sourcePasswordFile string
+	importKey := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import a keystore file (e.g. from geth) or a raw hex private key file, which becomes active if there's none",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, password, err := open(true)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var address common.Address
+			if json.Valid(data) {
+				sourcePassword := password
+				if sourcePasswordFile != "" {
+					if sourcePassword, err = ReadPassword(sourcePasswordFile); err != nil {
+						return err
+					}
+				}
+				address, err = store.ImportKeystore(data, sourcePassword, password)
+			} else {
+				key, keyErr := crypto.HexToECDSA(string(bytes.TrimSpace(data)))
+				if keyErr != nil {
+					return fmt.Errorf("%s is neither a keystore file nor a hex private key: %w", args[0], keyErr)
+				}
+				address, err = store.Import(key, password)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), address.Hex())
+			return nil
+		},
+	}
+	importKey.Flags().StringVar(&sourcePasswordFile, "source-password-file", "", "Password of the imported keystore file, --password-file if unset")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List keys, active first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := open(false)
+			if err != nil {
+				return err
+			}
+			infos, err := store.List()
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ADDRESS\tSTATUS\tFILE")
+			for _, info := range infos {
+				status := "inactive"
+				switch {
+				case info.Active:
+					status = "active"
+				case info.Retired:
+					status = "retired"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", info.Address.Hex(), status, info.File)
+			}
+			return w.Flush()
+		},
+	}
+
+	rotate := &cobra.Command{
+		Use:   "rotate",
+		Short: "Generate a new active key, retiring the current one",
+		Long: `Generates a new active key, retiring the current one. Retired keys are kept, so what they signed still verifies.
+Processes pick up the new key on restart. The auctioneer announces the retired key as still valid for its signer.grace-period
+after the rotation. A relay's new address must be registered on the settlement layer before it can bid.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, password, err := open(true)
+			if err != nil {
+				return err
+			}
+			previous, next, err := store.Rotate(password)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "retired %s\nactive  %s\n", previous.Hex(), next.Hex())
+			return nil
+		},
+	}
+	cmd.AddCommand(generate, importKey, list, rotate)
+	return cmd
+}
@@ -0,0 +1,55 @@
+package keys_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"blob-preconfs/pkg/keys"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keystore")
+	passwordFile := writeFile(t, "password", "password\n")
+	run := func(args ...string) (string, error) {
+		cmd := keys.NewCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(append(args, "--keystore-dir", dir, "--password-file", passwordFile, "--light-kdf"))
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	generated, err := run("generate")
+	require.NoError(t, err)
+	pk, _ := crypto.GenerateKey()
+	keyFile := filepath.Join(t.TempDir(), "key.hex")
+	require.NoError(t, crypto.SaveECDSA(keyFile, pk))
+	imported, err := run("import", keyFile)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(pk.PublicKey).Hex()+"\n", imported)
+	_, err = run("import", writeFile(t, "garbage", "not a key"))
+	require.Error(t, err)
+
+	rotated, err := run("rotate")
+	require.NoError(t, err)
+	require.Contains(t, rotated, "retired "+strings.TrimSpace(generated))
+
+	list, err := run("list")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(list), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[1], "active")
+	require.Contains(t, list, strings.TrimSpace(generated)+"  retired")
+
+	cmd := keys.NewCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"generate", "--keystore-dir", dir})
+	require.ErrorIs(t, cmd.Execute(), keys.ErrInvalidSource, "storing keys requires a password")
+}
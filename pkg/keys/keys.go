@@ -0,0 +1,288 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound      = errors.New("key not found")
+	ErrExists        = errors.New("key already in keystore")
+	ErrNoActiveKey   = errors.New("no active key")
+	ErrWrongPassword = errors.New("wrong password")
+	ErrNotRotated    = errors.New("keystore never rotated")
+)
+
+const (
+	activeFile   = "active"
+	rotationFile = "rotation"
+	retiredDir   = "retired"
+)
+
+// Keys encrypted at rest in a directory, one go-ethereum keystore file per key, so they can also be
+// used with geth and other wallets. One key is active, the one the process signs with. Keys rotated
+// out are kept in retired/, since commitments and bids they signed still verify against them.
+type Store struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// Last rotation of a keystore's active key
+type Rotation struct {
+	Previous common.Address `json:"previous"`
+	Next     common.Address `json:"next"`
+	At       time.Time      `json:"at"`
+}
+
+type KeyInfo struct {
+	Address common.Address
+	File    string
+	Active  bool
+	Retired bool
+}
+
+// Creates dir if missing, readable only by the current user
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, retiredDir), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore: %w", err)
+	}
+	return &Store{dir: dir, scryptN: keystore.StandardScryptN, scryptP: keystore.StandardScryptP}, nil
+}
+
+// Encrypts with scrypt parameters cheap enough for tests, rather than the standard 256MB, if set before
+// keys are stored
+func (s *Store) SetLightScrypt() {
+	s.scryptN, s.scryptP = keystore.LightScryptN, keystore.LightScryptP
+}
+
+// Stores a new random key, returning its address. It becomes the active key if there's none.
+func (s *Store) Generate(password string) (common.Address, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return s.Import(key, password)
+}
+
+// Stores key encrypted with password. It becomes the active key if there's none.
+func (s *Store) Import(key *ecdsa.PrivateKey, password string) (common.Address, error) {
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	if _, err := s.find(address); err == nil {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrExists, address.Hex())
+	}
+	data, err := keystore.EncryptKey(&keystore.Key{Id: uuid.New(), Address: address, PrivateKey: key}, password, s.scryptN, s.scryptP)
+	if err != nil {
+		return common.Address{}, err
+	}
+	name := fmt.Sprintf("UTC--%s--%s", time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z"), hex.EncodeToString(address[:]))
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o600); err != nil {
+		return common.Address{}, fmt.Errorf("failed to write key: %w", err)
+	}
+	if _, err := s.Active(); errors.Is(err, ErrNoActiveKey) {
+		if err := s.activate(address); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return address, nil
+}
+
+// Stores the key in a keystore file (e.g. from geth) decrypted with password, re-encrypted with newPassword
+func (s *Store) ImportKeystore(data []byte, password, newPassword string) (common.Address, error) {
+	key, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return common.Address{}, decryptError(err)
+	}
+	return s.Import(key.PrivateKey, newPassword)
+}
+
+// Every key, active first, then by address
+func (s *Store) List() ([]KeyInfo, error) {
+	active, err := s.Active()
+	if err != nil && !errors.Is(err, ErrNoActiveKey) {
+		return nil, err
+	}
+	var infos []KeyInfo
+	for _, retired := range []bool{false, true} {
+		dir := s.dir
+		if retired {
+			dir = filepath.Join(s.dir, retiredDir)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || entry.Name() == activeFile || entry.Name() == rotationFile {
+				continue
+			}
+			address, err := keyFileAddress(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				// Not a keystore file
+				continue
+			}
+			infos = append(infos, KeyInfo{
+				Address: address,
+				File:    filepath.Join(dir, entry.Name()),
+				Active:  !retired && address == active,
+				Retired: retired,
+			})
+		}
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Active != infos[j].Active {
+			return infos[i].Active
+		}
+		if infos[i].Retired != infos[j].Retired {
+			return !infos[i].Retired
+		}
+		return infos[i].Address.Cmp(infos[j].Address) < 0
+	})
+	return infos, nil
+}
+
+// Address of the active key, ErrNoActiveKey if the keystore is empty
+func (s *Store) Active() (common.Address, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, activeFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return common.Address{}, ErrNoActiveKey
+	}
+	if err != nil {
+		return common.Address{}, err
+	}
+	address := strings.TrimSpace(string(data))
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid active key %q", address)
+	}
+	return common.HexToAddress(address), nil
+}
+
+// Decrypts the key with address, active or retired
+func (s *Store) Unlock(address common.Address, password string) (*ecdsa.PrivateKey, error) {
+	file, err := s.find(address)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, decryptError(err)
+	}
+	return key.PrivateKey, nil
+}
+
+// Generates a new active key, retiring the previous one, and records the rotation. Returns the previous and new
+// addresses. The previous key must be unlocked with password, so a keystore can't be rotated without it.
+func (s *Store) Rotate(password string) (common.Address, common.Address, error) {
+	previous, err := s.Active()
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	if _, err := s.Unlock(previous, password); err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	next, err := s.Generate(password)
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	if err := s.activate(next); err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	file, err := s.find(previous)
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	if err := os.Rename(file, filepath.Join(s.dir, retiredDir, filepath.Base(file))); err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("failed to retire key: %w", err)
+	}
+	data, err := json.Marshal(Rotation{Previous: previous, Next: next, At: time.Now().UTC()})
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	if err := s.write(rotationFile, data); err != nil {
+		return common.Address{}, common.Address{}, fmt.Errorf("failed to record rotation: %w", err)
+	}
+	return previous, next, nil
+}
+
+// Last rotation, ErrNotRotated if the active key was never rotated
+func (s *Store) LastRotation() (Rotation, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, rotationFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Rotation{}, ErrNotRotated
+	}
+	if err != nil {
+		return Rotation{}, err
+	}
+	var rotation Rotation
+	if err := json.Unmarshal(data, &rotation); err != nil {
+		return Rotation{}, fmt.Errorf("invalid rotation record: %w", err)
+	}
+	return rotation, nil
+}
+
+func (s *Store) activate(address common.Address) error {
+	return s.write(activeFile, []byte(address.Hex()+"\n"))
+}
+
+// Written to a temporary file then renamed, so a crash never leaves the keystore without an active key
+func (s *Store) write(name string, data []byte) error {
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, name))
+}
+
+func (s *Store) find(address common.Address) (string, error) {
+	for _, dir := range []string{s.dir, filepath.Join(s.dir, retiredDir)} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+		suffix := "--" + hex.EncodeToString(address[:])
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), suffix) {
+				return filepath.Join(dir, entry.Name()), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotFound, address.Hex())
+}
+
+func keyFileAddress(file string) (common.Address, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil || !common.IsHexAddress(key.Address) {
+		return common.Address{}, fmt.Errorf("not a keystore file: %s", file)
+	}
+	return common.HexToAddress(key.Address), nil
+}
+
+func decryptError(err error) error {
+	if errors.Is(err, keystore.ErrDecrypt) {
+		return ErrWrongPassword
+	}
+	return fmt.Errorf("failed to decrypt key: %w", err)
+}
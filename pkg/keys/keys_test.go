@@ -0,0 +1,118 @@
+package keys_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/keys"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) (*keys.Store, string) {
+	dir := filepath.Join(t.TempDir(), "keystore")
+	store, err := keys.NewStore(dir)
+	require.NoError(t, err)
+	store.SetLightScrypt()
+	return store, dir
+}
+
+func TestGenerateAndUnlock(t *testing.T) {
+	store, dir := newStore(t)
+	_, err := store.Active()
+	require.ErrorIs(t, err, keys.ErrNoActiveKey)
+
+	first, err := store.Generate("password")
+	require.NoError(t, err)
+	second, err := store.Generate("password")
+	require.NoError(t, err)
+	active, err := store.Active()
+	require.NoError(t, err)
+	require.Equal(t, first, active, "the first key becomes active")
+
+	key, err := store.Unlock(second, "password")
+	require.NoError(t, err)
+	require.Equal(t, second, crypto.PubkeyToAddress(key.PublicKey))
+	_, err = store.Unlock(second, "wrong")
+	require.ErrorIs(t, err, keys.ErrWrongPassword)
+	_, err = store.Unlock(common.Address{0x01}, "password")
+	require.ErrorIs(t, err, keys.ErrNotFound)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm(), entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "privatekey", "keys are encrypted at rest")
+	}
+}
+
+func TestImport(t *testing.T) {
+	store, _ := newStore(t)
+	pk, _ := crypto.GenerateKey()
+	address, err := store.Import(pk, "password")
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(pk.PublicKey), address)
+	_, err = store.Import(pk, "password")
+	require.ErrorIs(t, err, keys.ErrExists)
+
+	// e.g. exported from geth, with its own password
+	other, _ := crypto.GenerateKey()
+	otherAddress := crypto.PubkeyToAddress(other.PublicKey)
+	data, err := keystore.EncryptKey(&keystore.Key{Id: uuid.New(), Address: otherAddress, PrivateKey: other}, "geth", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	_, err = store.ImportKeystore(data, "wrong", "password")
+	require.ErrorIs(t, err, keys.ErrWrongPassword)
+	imported, err := store.ImportKeystore(data, "geth", "password")
+	require.NoError(t, err)
+	require.Equal(t, otherAddress, imported)
+	key, err := store.Unlock(imported, "password")
+	require.NoError(t, err, "re-encrypted with the keystore's password")
+	require.Equal(t, other.D, key.D)
+}
+
+func TestRotate(t *testing.T) {
+	store, _ := newStore(t)
+	_, _, err := store.Rotate("password")
+	require.ErrorIs(t, err, keys.ErrNoActiveKey)
+
+	first, err := store.Generate("password")
+	require.NoError(t, err)
+	_, err = store.LastRotation()
+	require.ErrorIs(t, err, keys.ErrNotRotated)
+	_, _, err = store.Rotate("wrong")
+	require.ErrorIs(t, err, keys.ErrWrongPassword)
+	previous, next, err := store.Rotate("password")
+	require.NoError(t, err)
+	require.Equal(t, first, previous)
+	rotation, err := store.LastRotation()
+	require.NoError(t, err)
+	require.Equal(t, first, rotation.Previous)
+	require.Equal(t, next, rotation.Next)
+	require.WithinDuration(t, time.Now(), rotation.At, time.Minute)
+	active, err := store.Active()
+	require.NoError(t, err)
+	require.Equal(t, next, active)
+
+	infos, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, next, infos[0].Address)
+	require.True(t, infos[0].Active)
+	require.Equal(t, first, infos[1].Address)
+	require.True(t, infos[1].Retired)
+	_, err = store.Unlock(first, "password")
+	require.NoError(t, err, "retired keys can still be unlocked")
+}
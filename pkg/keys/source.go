@@ -0,0 +1,120 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrInvalidSource = errors.New("invalid key source")
+
+// Where a process's signing key is loaded from, either a keystore or a raw key file
+type Source struct {
+	// Keystore directory, see Store
+	KeystoreDir string
+	// Key to unlock from the keystore, the active key if empty
+	Address string
+	// File with the keystore password, the first line of which is the password
+	PasswordFile string
+	// Unencrypted hex private key, prefer a keystore
+	KeyFile string
+}
+
+func (s Source) Validate() error {
+	switch {
+	case s.KeystoreDir != "" && s.KeyFile != "":
+		return fmt.Errorf("%w: keystore and key file are mutually exclusive", ErrInvalidSource)
+	case s.KeystoreDir == "" && s.KeyFile == "":
+		return fmt.Errorf("%w: keystore or key file required", ErrInvalidSource)
+	case s.KeystoreDir != "" && s.PasswordFile == "":
+		return fmt.Errorf("%w: password file required for the keystore", ErrInvalidSource)
+	case s.Address != "" && !common.IsHexAddress(s.Address):
+		return fmt.Errorf("%w: invalid address %q", ErrInvalidSource, s.Address)
+	}
+	return nil
+}
+
+func (s Source) Load() (*ecdsa.PrivateKey, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if s.KeyFile != "" {
+		key, err := crypto.LoadECDSA(s.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key file: %w", err)
+		}
+		return key, nil
+	}
+	password, err := ReadPassword(s.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+	store, err := NewStore(s.KeystoreDir)
+	if err != nil {
+		return nil, err
+	}
+	address := common.HexToAddress(s.Address)
+	if s.Address == "" {
+		if address, err = store.Active(); err != nil {
+			return nil, err
+		}
+	}
+	return store.Unlock(address, password)
+}
+
+// Keys verifiers accept signatures from: the active key, and after a rotation the previous key until the end of
+// its grace period, so what it signed before the switch-over still validates
+type Signers struct {
+	Active common.Address `json:"active"`
+	// Nil if the active key wasn't rotated in
+	Previous      *common.Address `json:"previous,omitempty"`
+	PreviousUntil *time.Time      `json:"previousUntil,omitempty"`
+}
+
+func (s Signers) Valid(address common.Address, at time.Time) bool {
+	if address == s.Active {
+		return true
+	}
+	return s.Previous != nil && address == *s.Previous && at.Before(*s.PreviousUntil)
+}
+
+// Signers with active, the address of the loaded key, and the key it was rotated from, valid for grace after the
+// rotation. Key files aren't rotated, and neither are keys rotated out again.
+func (s Source) Signers(active common.Address, grace time.Duration) (Signers, error) {
+	signers := Signers{Active: active}
+	if s.KeystoreDir == "" || grace <= 0 {
+		return signers, nil
+	}
+	store, err := NewStore(s.KeystoreDir)
+	if err != nil {
+		return Signers{}, err
+	}
+	rotation, err := store.LastRotation()
+	if errors.Is(err, ErrNotRotated) {
+		return signers, nil
+	}
+	if err != nil {
+		return Signers{}, err
+	}
+	if rotation.Next == active {
+		until := rotation.At.Add(grace)
+		signers.Previous, signers.PreviousUntil = &rotation.Previous, &until
+	}
+	return signers, nil
+}
+
+// Reads the password on the first line of file, without its line ending
+func ReadPassword(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	password, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSuffix(password, "\r"), nil
+}
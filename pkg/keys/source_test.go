@@ -0,0 +1,90 @@
+package keys_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/keys"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestSource(t *testing.T) {
+	store, dir := newStore(t)
+	active, err := store.Generate("password")
+	require.NoError(t, err)
+	other, err := store.Generate("password")
+	require.NoError(t, err)
+	passwordFile := writeFile(t, "password", "password\n")
+
+	key, err := keys.Source{KeystoreDir: dir, PasswordFile: passwordFile}.Load()
+	require.NoError(t, err)
+	require.Equal(t, active, crypto.PubkeyToAddress(key.PublicKey), "the active key by default")
+	key, err = keys.Source{KeystoreDir: dir, PasswordFile: passwordFile, Address: other.Hex()}.Load()
+	require.NoError(t, err)
+	require.Equal(t, other, crypto.PubkeyToAddress(key.PublicKey))
+	_, err = keys.Source{KeystoreDir: dir, PasswordFile: writeFile(t, "password", "wrong")}.Load()
+	require.ErrorIs(t, err, keys.ErrWrongPassword)
+
+	pk, _ := crypto.GenerateKey()
+	keyFile := filepath.Join(t.TempDir(), "key.hex")
+	require.NoError(t, crypto.SaveECDSA(keyFile, pk))
+	key, err = keys.Source{KeyFile: keyFile}.Load()
+	require.NoError(t, err)
+	require.Equal(t, pk.D, key.D)
+
+	for name, source := range map[string]keys.Source{
+		"none":            {},
+		"both":            {KeystoreDir: dir, PasswordFile: passwordFile, KeyFile: keyFile},
+		"no password":     {KeystoreDir: dir},
+		"invalid address": {KeystoreDir: dir, PasswordFile: passwordFile, Address: "0x01"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := source.Load()
+			require.ErrorIs(t, err, keys.ErrInvalidSource)
+		})
+	}
+}
+
+func TestSigners(t *testing.T) {
+	store, dir := newStore(t)
+	first, err := store.Generate("password")
+	require.NoError(t, err)
+	source := keys.Source{KeystoreDir: dir, PasswordFile: writeFile(t, "password", "password")}
+	signers, err := source.Signers(first, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, keys.Signers{Active: first}, signers, "never rotated")
+
+	_, next, err := store.Rotate("password")
+	require.NoError(t, err)
+	signers, err = source.Signers(next, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, first, *signers.Previous)
+	require.True(t, signers.Valid(next, time.Now().Add(2*time.Hour)))
+	require.True(t, signers.Valid(first, time.Now()), "both keys validate during the grace period")
+	require.False(t, signers.Valid(first, time.Now().Add(2*time.Hour)))
+	require.False(t, signers.Valid(common.Address{0x01}, time.Now()))
+
+	signers, err = source.Signers(first, time.Hour)
+	require.NoError(t, err)
+	require.Nil(t, signers.Previous, "the retired key wasn't rotated in")
+	signers, err = source.Signers(next, 0)
+	require.NoError(t, err)
+	require.Nil(t, signers.Previous, "no grace period")
+}
+
+func TestReadPassword(t *testing.T) {
+	password, err := keys.ReadPassword(writeFile(t, "password", "secret \r\nignored\n"))
+	require.NoError(t, err)
+	require.Equal(t, "secret ", password, "only the line ending is trimmed")
+}
@@ -0,0 +1,29 @@
+package listener
+
+import (
+	"math/big"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Caps relays' bids for a while, e.g. *escrow.Prompter while a relay's bond runs low with its pending wins
+type BidCaps interface {
+	// Highest bid accepted from the relay, uncapped if nil
+	BidCap(relay common.Address) *big.Int
+}
+
+// Bids above their relay's cap are rejected on submission, queued or revealed, if set before the listener starts
+func (l *Listener) SetBidCaps(caps BidCaps) {
+	l.caps = caps
+}
+
+// Whether the bid is within its relay's cap, if it has one
+func (l *Listener) withinCap(bid auction.SignedBid) bool {
+	if l.caps == nil {
+		return true
+	}
+	bidCap := l.caps.BidCap(bid.Address)
+	return bidCap == nil || bid.AmountWei.Cmp(bidCap) <= 0
+}
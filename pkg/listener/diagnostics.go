@@ -0,0 +1,65 @@
+package listener
+
+import (
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Buffered items against capacity
+type ChannelDepth struct {
+	Len int `json:"len"`
+	Cap int `json:"cap"`
+}
+
+// Point-in-time listener state, for debugging latency spikes during the auction window
+type Diagnostics struct {
+	Paused            bool       `json:"paused"`
+	AuctionInProgress bool       `json:"auctionInProgress"`
+	AuctionBlock      uint64     `json:"auctionBlock,omitempty"`
+	AuctionOpenedAt   *time.Time `json:"auctionOpenedAt,omitempty"`
+	AuctionBids       int64      `json:"auctionBids"`
+	// Bids submitted to the auction and not yet evaluated
+	AuctionBidQueue  int                `json:"auctionBidQueue"`
+	LeadingBid       *auction.SignedBid `json:"leadingBid,omitempty"`
+	LastAuctionBlock uint64             `json:"lastAuctionBlock,omitempty"`
+	LastAuctionAt    *time.Time         `json:"lastAuctionAt,omitempty"`
+	// Won auctions waiting to be picked up for settlement, and events queued per subscriber
+	AuctionWonQueue  ChannelDepth   `json:"auctionWonQueue"`
+	SubscriberQueues []ChannelDepth `json:"subscriberQueues"`
+}
+
+func (l *Listener) Diagnostics() Diagnostics {
+	d := Diagnostics{
+		Paused:          l.Paused(),
+		AuctionWonQueue: ChannelDepth{Len: len(l.AuctionWonChan), Cap: cap(l.AuctionWonChan)},
+	}
+	l.auctionMu.RLock()
+	if l.currentAuction != nil {
+		openedAt := l.currentAuctionAt
+		d.AuctionInProgress = true
+		d.AuctionBlock = l.currentAuctionBlock
+		d.AuctionOpenedAt = &openedAt
+		d.AuctionBids = l.auctionBids.Load()
+		d.AuctionBidQueue = l.currentAuction.QueueDepth()
+		if bid := l.currentAuction.GetCurrentBid(); bid.Address != (common.Address{}) {
+			d.LeadingBid = &bid
+		}
+	}
+	d.LastAuctionBlock = l.lastAuctionBlock
+	if !l.lastAuctionAt.IsZero() {
+		lastAt := l.lastAuctionAt
+		d.LastAuctionAt = &lastAt
+	}
+	l.auctionMu.RUnlock()
+
+	l.subscribersMu.Lock()
+	d.SubscriberQueues = make([]ChannelDepth, 0, len(l.subscribers))
+	for ch := range l.subscribers {
+		d.SubscriberQueues = append(d.SubscriberQueues, ChannelDepth{Len: len(ch), Cap: cap(ch)})
+	}
+	l.subscribersMu.Unlock()
+	return d
+}
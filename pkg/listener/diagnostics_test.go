@@ -0,0 +1,46 @@
+package listener_test
+
+import (
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/listener"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	events, sub := l.SubscribeEvents(16)
+	d := l.Diagnostics()
+	require.False(t, d.AuctionInProgress)
+	require.Equal(t, []listener.ChannelDepth{{Len: 0, Cap: 16}}, d.SubscriberQueues)
+
+	go l.FacilitateRelayAuction()
+	select {
+	case ev := <-events:
+		require.Equal(t, auction.EventAuctionOpened, ev.Type)
+	case <-time.After(time.Second):
+		t.Fatal("Test timed out waiting for auction to open")
+	}
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(0), pk)
+	require.NoError(t, l.SubmitBid(*bid))
+	require.Eventually(t, func() bool { return l.Diagnostics().LeadingBid != nil }, time.Second, 10*time.Millisecond)
+	d = l.Diagnostics()
+	require.True(t, d.AuctionInProgress)
+	require.Equal(t, int64(1), d.AuctionBids)
+	require.Equal(t, bid.Address, d.LeadingBid.Address)
+	require.WithinDuration(t, time.Now(), *d.AuctionOpenedAt, time.Second)
+	_, cancelled := l.CancelAuction()
+	require.True(t, cancelled)
+
+	sub.Unsubscribe()
+	for range events {
+	}
+	require.Empty(t, l.Diagnostics().SubscriberQueues)
+}
@@ -2,15 +2,21 @@ package listener
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/big"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/version"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
 )
 
 type Listener struct {
@@ -23,20 +29,174 @@ type Listener struct {
 	// To be subscribed to by routine that'll announce winner on SL, and start settlement process.
 	AuctionWonChan chan auction.SignedBid
 
-	currentBlockNum uint64
-	currentAuction  *auction.RelayAuction
+	currentBlockNum atomic.Uint64
+	pollInterval    time.Duration
+	auctionPeriod   time.Duration
+	bidVerifiers    int
+	bidShards       int
+	minOpen         time.Duration
+	quietPeriod     time.Duration
+	maxPollFailures int
+	// Auctions run from openOffset to closeOffset into the slot, unless closeOffset is 0
+	genesis     time.Time
+	slotTime    time.Duration
+	openOffset  time.Duration
+	closeOffset time.Duration
+	// Bids for the next auction are queued this long before it opens, see SetPreAuctionWindow
+	preAuctionWindow time.Duration
+
+	// Operational controls, e.g. from the admin API
+	paused     atomic.Bool
+	accessList *auction.AccessList
+
+	newAuction AuctionFactory
+
+	auctionMu           sync.RWMutex // Protects access to fields below
+	currentAuction      Auction
+	currentAuctionBlock uint64
+	currentAuctionAt    time.Time
+	currentReservePrice *big.Int
+	cancelAuction       context.CancelFunc
+	lastAuctionBlock    uint64
+	lastAuctionWinner   *auction.SignedBid
+	lastAuctionAt       time.Time
+	// Valid bids of recently won auctions, by L1 block, for falling back on runners-up
+	rankings map[uint64]*ranking
+
+	preAuctionMu sync.Mutex // Protects preAuction
+	preAuction   *preAuctionQueue
+
+	// Opens sealed bids as auctions close, see SetSealedBids
+	opener     SealedBidOpener
+	sealedMu   sync.Mutex // Protects sealedBids
+	sealedBids map[common.Address]sealedBid
+
+	eventFeed     event.Feed
+	subscribersMu sync.Mutex // Protects subscribers, event buffers reported by Diagnostics
+	subscribers   map[chan auction.Event]struct{}
+	rejectionFeed event.Feed
+	recorder      Recorder
+	auditor       auction.Auditor
+	metrics       Metrics
+	alerter       Alerter
+	clock         ClockGuard
+	escrow        EscrowChecker
+	caps          BidCaps
+	policy        AuctionPolicy
+	quotas        BidQuotas
+	quotaMu       sync.Mutex // Protects quotaUsed
+	// Signatures of the bids each relay submitted to the current auction, see SetBidQuotas
+	quotaUsed map[common.Address]map[string]struct{}
+	// Stamps bid arrivals, and signs receipts of them if set, see SetReceiptKey
+	arrivals   *auction.ArrivalClock
+	receiptKey *ecdsa.PrivateKey
+	// Bids submitted to the current auction
+	auctionBids atomic.Int64
+
+	// Won auctions restored unsettled after a restart, handed to AuctionWonChan once started
+	unsettled []auction.SignedBid
+
+	// Set by Start, for Stop
+	cancel        context.CancelFunc
+	processorDone chan struct{}
+}
+
+// Persists bid traffic, auction results and settlements, e.g. *store.MemoryStore
+type Recorder interface {
+	SaveBid(bid auction.SignedBid, receivedAt time.Time) error
+	SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error
+	SaveSettlement(l1Block uint64, tx common.Hash, settledAt time.Time) error
+}
+
+// Observes blocks, auctions, settlements and RPC calls, e.g. *metrics.Metrics
+type Metrics interface {
+	auction.Metrics
+	ObserveBlock(block uint64)
+	ObserveAuction(duration time.Duration, bids int, won bool)
+	ObserveSettlement(succeeded bool)
+	ObserveRPC(method string, err error)
+}
+
+// Alerted to failures the listener exits on, e.g. *alerting.Notifier. Calls must return once the alert is
+// delivered, before the process exits.
+type Alerter interface {
+	AuctionTimedOut(l1Block uint64, after time.Duration)
+	ObserveRPC(method string, err error)
+}
+
+// Checks the local clock auction windows run on, e.g. *timesync.Guard
+type ClockGuard interface {
+	// Called with each new head when it's first seen
+	ObserveHead(block uint64, seenAt time.Time)
+	// Fails while the clock has drifted too far to run auctions on
+	Check() error
+}
+
+// Checks relays' escrow backs their bids, e.g. *escrow.Cache
+type EscrowChecker interface {
+	// Fails if the relay's balance can't be read
+	Covers(relay common.Address, amountWei *big.Int) (bool, error)
+}
+
+// Parameters of one block's auction
+type AuctionParams struct {
+	// Bidding period, until the close offset if auctions are scheduled by slot
+	Period time.Duration
+	// Lowest bid accepted, none if nil
+	ReservePriceWei *big.Int
+}
+
+// Selects each auction's parameters instead of the listener's, e.g. a longer window for the block after a missed
+// slot, or a higher reserve when the blob base fee spikes. Called before each auction opens, so it must return
+// quickly.
+type AuctionPolicy interface {
+	// defaults are the parameters the auction runs with otherwise
+	Params(l1Block uint64, defaults AuctionParams) AuctionParams
+}
+
+// Auction run for one block, satisfied by *auction.RelayAuction
+type Auction interface {
+	// Submits a bid that reached the auctioneer at receivedAt, which breaks ties. Fails with
+	// auction.ErrNoActiveAuction once bidding is over, rather than blocking on a full queue.
+	SubmitBidAt(bid auction.SignedBid, receivedAt time.Time) error
+	// Current leader, the zero bid if none
+	GetCurrentBid() auction.SignedBid
+	// Runs the auction for the bidding period, then sends the winner, the zero bid if none, unless ctx is done or
+	// it's cancelled first
+	StartAsync(ctx context.Context, biddingPeriod time.Duration) <-chan auction.SignedBid
+	// Stops the auction, which then sends no winner
+	Cancel()
+	// Each relay's best valid bid, best first, for falling back on runners-up
+	Ranked() []auction.SignedBid
+	// Bids submitted and not yet evaluated
+	QueueDepth() int
+}
+
+// Creates the auction for a block with the parameters selected for it, e.g. to swap in a mock or another engine.
+// The listener checks bids before submitting them, records them and publishes the auction opening and closing;
+// the auction evaluates bids, and publishes its leader changes, e.g. with Listener.PublishEvent.
+type AuctionFactory func(l1Block uint64, params AuctionParams) Auction
+
+// Snapshot of the auction for an L1 block
+type AuctionState struct {
+	L1Block    uint64
+	InProgress bool
+	// Current leader if in progress, otherwise the winner. Nil if there's none.
+	LeadingBid *auction.SignedBid
 }
 
 type EthClient interface {
 	BlockNumber(ctx context.Context) (uint64, error)
 }
 
+// Auctions are created by newAuction, or are auction.RelayAuctions configured by the listener if nil
 func NewListener(
 	logger *slog.Logger,
 	client EthClient,
 	relayRegistry auction.RelayRegistry,
+	newAuction AuctionFactory,
 ) *Listener {
-	return &Listener{
+	l := &Listener{
 		logger:        logger,
 		ethClient:     client,
 		relayRegistry: relayRegistry,
@@ -45,9 +205,110 @@ func NewListener(
 		NewBlockChan:   make(chan *big.Int),
 		AuctionWonChan: make(chan auction.SignedBid),
 
-		currentBlockNum: 0,
+		pollInterval:    200 * time.Millisecond,
+		auctionPeriod:   5 * time.Second, // Adjust to whatever portion of L1 block time.
+		maxPollFailures: 1,
 		currentAuction:  nil,
+		accessList:      auction.DefaultAccessList(),
+		arrivals:        auction.NewArrivalClock(),
+		newAuction:      newAuction,
 	}
+	if l.newAuction == nil {
+		l.newAuction = l.newRelayAuction
+	}
+	return l
+}
+
+// Bids, auction results and settlements are recorded, if set before the listener starts
+func (l *Listener) SetRecorder(recorder Recorder) {
+	l.recorder = recorder
+}
+
+// Every bid submitted is recorded with its outcome, if set before the listener starts:
+// rejected by the listener, e.g. for another block, or evaluated by the auction
+func (l *Listener) SetAuditor(auditor auction.Auditor) {
+	l.auditor = auditor
+}
+
+// Blocks, auctions, settlements and RPC calls are observed, if set before the listener starts
+func (l *Listener) SetMetrics(metrics Metrics) {
+	l.metrics = metrics
+}
+
+// Auction timeouts and RPC calls are alerted on, if set before the listener starts
+func (l *Listener) SetAlerter(alerter Alerter) {
+	l.alerter = alerter
+}
+
+// Auctions aren't opened while the guard's check fails, if set before the listener starts
+func (l *Listener) SetClockGuard(guard ClockGuard) {
+	l.clock = guard
+}
+
+// Bids the bidder's escrow doesn't cover are rejected on submission, instead of failing at settlement, if set
+// before the listener starts. Bids are accepted if the balance can't be read.
+func (l *Listener) SetEscrowCheck(checker EscrowChecker) {
+	l.escrow = checker
+}
+
+// Each auction's parameters are selected by the policy, if set before the listener starts
+func (l *Listener) SetAuctionPolicy(policy AuctionPolicy) {
+	l.policy = policy
+}
+
+// Overrides the 200ms interval the L1 node is polled for new blocks in, if set before the listener starts
+func (l *Listener) SetPollInterval(interval time.Duration) {
+	l.pollInterval = interval
+}
+
+// Tolerates up to n-1 consecutive failed polls for new blocks, retried on the next tick, before the listener
+// exits, rather than exiting on the first one, if set before the listener starts
+func (l *Listener) SetMaxPollFailures(n int) {
+	l.maxPollFailures = max(n, 1)
+}
+
+// Overrides the 5s bidding period of each block's auction, if set before the listener starts
+func (l *Listener) SetAuctionPeriod(period time.Duration) {
+	l.auctionPeriod = period
+}
+
+// Overrides verifying each auction's bids on one worker per CPU, if set before the listener starts
+func (l *Listener) SetBidVerifiers(n int) {
+	l.bidVerifiers = n
+}
+
+// Shards each auction's bid intake by relay address across n goroutines, if set before the listener starts,
+// see auction.RelayAuction.SetShards
+func (l *Listener) SetBidShards(n int) {
+	l.bidShards = n
+}
+
+// Closes each auction early once there's no new leader for quietPeriod, after at least minOpen, if set before
+// the listener starts, see auction.RelayAuction.SetEarlyClose
+func (l *Listener) SetEarlyClose(minOpen time.Duration, quietPeriod time.Duration) {
+	l.minOpen = minOpen
+	l.quietPeriod = quietPeriod
+}
+
+// Opens each auction openOffset and closes it closeOffset into the slot its block is seen in, rather than on
+// seeing the block and after the auction period, if set before the listener starts. Slots start every slotTime
+// from genesis. Blocks seen after the close offset open no auction.
+func (l *Listener) SetSlotSchedule(genesis time.Time, slotTime time.Duration, openOffset time.Duration, closeOffset time.Duration) {
+	l.genesis = genesis
+	l.slotTime = slotTime
+	l.openOffset = openOffset
+	l.closeOffset = closeOffset
+}
+
+// Restores auction state persisted before a restart, see recovery. Must be called before Start.
+// The last concluded auction is served by GetAuction again, and unsettled won auctions are handed
+// to AuctionWonChan once started, so settlement resumes.
+func (l *Listener) Restore(lastAuctionBlock uint64, lastWinner *auction.SignedBid, unsettled []auction.SignedBid) {
+	l.auctionMu.Lock()
+	l.lastAuctionBlock = lastAuctionBlock
+	l.lastAuctionWinner = lastWinner
+	l.auctionMu.Unlock()
+	l.unsettled = unsettled
 }
 
 func (l *Listener) Start(ctx context.Context) (
@@ -57,21 +318,50 @@ func (l *Listener) Start(ctx context.Context) (
 ) {
 	l.DoneChan = make(chan struct{})
 	l.NewBlockChan = make(chan *big.Int)
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.processorDone = make(chan struct{})
 
 	go l.listenForBlocks(ctx)
-	go l.processNewBlocks(ctx)
+	go func() {
+		defer close(l.processorDone)
+		l.processNewBlocks(ctx)
+	}()
+	if len(l.unsettled) > 0 {
+		go l.resumeSettlements(ctx, l.unsettled)
+	}
 
 	return l.DoneChan, l.AuctionWonChan, nil
 }
 
+// Stops polling for blocks, and waits until the auction in progress closes, so its result is recorded rather
+// than lost on shutdown. A winner is still handed to AuctionWonChan, which must be received from until Stop returns.
+func (l *Listener) Stop(ctx context.Context) error {
+	if l.cancel == nil {
+		return nil
+	}
+	l.cancel()
+	select {
+	case <-l.processorDone:
+		return nil
+	default:
+	}
+	select {
+	case <-l.processorDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Listener POC is implemented with L1 RPC polling. Websocket may be more appropriate.
 func (l *Listener) listenForBlocks(ctx context.Context) {
 	defer close(l.DoneChan)
 	defer close(l.NewBlockChan)
 
-	ticker := time.NewTicker(200 * time.Millisecond)
+	ticker := time.NewTicker(l.pollInterval)
 	defer ticker.Stop()
 
+	failures := 0
 	for {
 		select {
 		case <-ctx.Done():
@@ -79,20 +369,57 @@ func (l *Listener) listenForBlocks(ctx context.Context) {
 			return
 		case <-ticker.C:
 		}
-		newBlockNum := l.MustGetBlockNum()
-		if newBlockNum > l.currentBlockNum {
-			l.logger.Info("new block. Signal to block processor will be sent",
-				"blockNumber", l.currentBlockNum)
-			l.NewBlockChan <- big.NewInt(int64(l.currentBlockNum))
-			l.currentBlockNum = newBlockNum
+		newBlockNum, err := l.getBlockNum(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			failures++
+			if failures >= l.maxPollFailures {
+				l.logger.Error("failed to get block number", "error", err, "failures", failures)
+				os.Exit(1)
+			}
+			l.logger.Warn("failed to get block number, retrying", "error", err, "failures", failures)
+			continue
+		}
+		failures = 0
+		// Heads at or below the current block, e.g. after a reorg or from a lagging node, open no auction
+		if current := l.currentBlockNum.Load(); newBlockNum > current {
+			if l.metrics != nil {
+				l.metrics.ObserveBlock(newBlockNum)
+			}
+			if l.clock != nil {
+				l.clock.ObserveHead(newBlockNum, time.Now())
+			}
+			if current != 0 && newBlockNum > current+1 {
+				// Only the latest block is auctioned, e.g. when blocks arrive faster than auctions run
+				l.logger.Warn("missed blocks", "from", current+1, "to", newBlockNum-1)
+			}
+			l.logger.Info("new block. Signal to block processor will be sent", "blockNumber", newBlockNum)
+			l.currentBlockNum.Store(newBlockNum)
+			select {
+			case l.NewBlockChan <- new(big.Int).SetUint64(newBlockNum):
+			case <-ctx.Done():
+			}
 		} else {
 			l.logger.Debug("no new block. Continuing...")
 		}
 	}
 }
 
+func (l *Listener) resumeSettlements(ctx context.Context, unsettled []auction.SignedBid) {
+	for _, bid := range unsettled {
+		l.logger.Info("resuming settlement of won auction", "blockNumber", bid.L1Block, "winner", bid.Address)
+		select {
+		case l.AuctionWonChan <- bid:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (l *Listener) MustGetBlockNum() uint64 {
-	blockNumber, err := l.ethClient.BlockNumber(context.Background())
+	blockNumber, err := l.getBlockNum(context.Background())
 	if err != nil {
 		l.logger.Error("failed to get block number", "error", err)
 		os.Exit(1)
@@ -100,64 +427,481 @@ func (l *Listener) MustGetBlockNum() uint64 {
 	return blockNumber
 }
 
+func (l *Listener) getBlockNum(ctx context.Context) (uint64, error) {
+	blockNumber, err := l.ethClient.BlockNumber(ctx)
+	if l.metrics != nil {
+		l.metrics.ObserveRPC("eth_blockNumber", err)
+	}
+	if l.alerter != nil {
+		l.alerter.ObserveRPC("eth_blockNumber", err)
+	}
+	return blockNumber, err
+}
+
 func (l *Listener) processNewBlocks(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
 			l.logger.Info("block processor stopped")
 			return
-		case <-l.NewBlockChan:
-			l.logger.Info("processing new block", "blockNumber", l.currentBlockNum)
+		case blockNum, ok := <-l.NewBlockChan:
+			if !ok {
+				// Closed once block polling stops
+				return
+			}
+			l.logger.Info("processing new block", "blockNumber", blockNum)
+			if openAt, _ := l.auctionWindow(time.Now()); time.Until(openAt) > 0 {
+				select {
+				case <-time.After(time.Until(openAt)):
+				case <-ctx.Done():
+					l.logger.Info("block processor stopped")
+					return
+				}
+			}
 			l.FacilitateRelayAuction()
 		}
 	}
 }
 
 func (l *Listener) FacilitateRelayAuction() {
+	if l.paused.Load() {
+		l.logger.Info("auctions paused, skipping block", "blockNumber", l.currentBlockNum.Load())
+		return
+	}
+	if l.clock != nil {
+		if err := l.clock.Check(); err != nil {
+			l.logger.Error("clock drifted, skipping block", "blockNumber", l.currentBlockNum.Load(), "error", err)
+			return
+		}
+	}
+	auctionPeriod := l.auctionPeriod
+	if _, closeAt := l.auctionWindow(time.Now()); !closeAt.IsZero() {
+		auctionPeriod = time.Until(closeAt)
+		if auctionPeriod <= 0 {
+			l.logger.Warn("block seen after the auction window, skipping block", "blockNumber", l.currentBlockNum.Load(), "closeAt", closeAt)
+			return
+		}
+	}
 
-	relayAuction := auction.NewRelayAuction(l.logger, l.relayRegistry)
+	params := AuctionParams{Period: auctionPeriod}
+	if l.policy != nil {
+		blockNum := l.currentBlockNum.Load()
+		if params = l.policy.Params(blockNum, params); params.Period <= 0 {
+			params.Period = auctionPeriod
+		}
+		if params.Period != auctionPeriod || params.ReservePriceWei != nil {
+			l.logger.Info("auction parameters selected by policy", "blockNumber", blockNum, "period", params.Period, "reservePrice", params.ReservePriceWei)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blockNum := l.currentBlockNum.Load()
+	relayAuction := l.newAuction(blockNum, params)
+	openedAt := time.Now()
+	l.auctionMu.Lock()
+	if l.opener != nil {
+		l.resetSealed()
+	}
 	l.currentAuction = relayAuction
+	l.currentAuctionBlock = blockNum
+	l.currentAuctionAt = openedAt
+	l.currentReservePrice = params.ReservePriceWei
+	l.cancelAuction = cancel
+	l.auctionBids.Store(0)
+	l.resetQuotas()
+	queued := l.takeQueued()
+	l.auctionMu.Unlock()
 	defer func() {
+		l.auctionMu.Lock()
 		l.currentAuction = nil
+		l.cancelAuction = nil
+		l.auctionMu.Unlock()
 	}()
+	closesAt := openedAt.Add(params.Period)
+	l.eventFeed.Send(auction.Event{Type: auction.EventAuctionOpened, L1Block: new(big.Int).SetUint64(blockNum), ReservePriceWei: params.ReservePriceWei,
+		ClosesAt: &closesAt, Timestamp: openedAt})
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	auctionPeriod := 5 * time.Second // Adjust to whatever portion of L1 block time.
-	auctionResultChan := relayAuction.StartAsync(ctx, auctionPeriod)
+	auctionResultChan := relayAuction.StartAsync(ctx, params.Period)
+	l.submitQueued(relayAuction, blockNum, queued)
 
 	select {
 	case bid := <-auctionResultChan:
 		zeroAddr := common.Address{}
-		if bid.Address == zeroAddr {
+		var winner *auction.SignedBid
+		if bid.Address != zeroAddr {
+			winner = &bid
+		}
+		l.closeAuction(blockNum, winner, relayAuction.Ranked(), openedAt)
+
+		if winner == nil {
 			l.logger.Info("relay auction ended with no winner. No action to take this block")
 			return
 		}
 		l.logger.Info("relay auction has been won", "winner", bid.Address, "amount", bid.AmountWei)
 		l.AuctionWonChan <- bid
-	case <-time.After(auctionPeriod + 1*time.Second):
+	case <-ctx.Done():
+		l.logger.Warn("relay auction cancelled, closing with no winner", "blockNumber", blockNum)
+		l.closeAuction(blockNum, nil, nil, openedAt)
+	case <-time.After(params.Period + 1*time.Second):
 		l.logger.Error("relay auction did not end before deadline", "error", "timeout")
+		if l.alerter != nil {
+			l.alerter.AuctionTimedOut(blockNum, params.Period+1*time.Second)
+		}
 		os.Exit(1)
 	}
 }
 
+// Default AuctionFactory, an auction.RelayAuction with the listener's settings. Sealed bids are revealed to it as
+// it closes.
+func (l *Listener) newRelayAuction(l1Block uint64, params AuctionParams) Auction {
+	relayAuction := auction.NewRelayAuction(l.logger, l.relayRegistry)
+	relayAuction.SetEventFeed(&l.eventFeed)
+	relayAuction.SetRejectionFeed(&l.rejectionFeed)
+	relayAuction.SetAccessList(l.accessList)
+	relayAuction.SetAuditor(l.auditor)
+	relayAuction.SetMetrics(l.metrics)
+	if l.bidVerifiers > 0 {
+		relayAuction.SetVerifiers(l.bidVerifiers)
+	}
+	relayAuction.SetShards(l.bidShards)
+	relayAuction.SetEarlyClose(l.minOpen, l.quietPeriod)
+	relayAuction.SetReservePrice(params.ReservePriceWei)
+	if l.opener != nil {
+		relayAuction.SetRevealer(&sealedRevealer{l: l, l1Block: l1Block})
+	}
+	return relayAuction
+}
+
+// Opening and closing times of the auction in the slot t falls in, zero unless scheduled by slot
+func (l *Listener) auctionWindow(t time.Time) (openAt time.Time, closeAt time.Time) {
+	if l.closeOffset <= 0 {
+		return time.Time{}, time.Time{}
+	}
+	slotStart := l.genesis.Add(t.Sub(l.genesis) / l.slotTime * l.slotTime)
+	return slotStart.Add(l.openOffset), slotStart.Add(l.closeOffset)
+}
+
+// Won auctions' rankings are saved before the auctionClosed event is sent, so are available to its subscribers
+func (l *Listener) closeAuction(blockNum uint64, winner *auction.SignedBid, ranked []auction.SignedBid, openedAt time.Time) {
+	closedAt := time.Now()
+	l.auctionMu.Lock()
+	l.lastAuctionBlock = blockNum
+	l.lastAuctionWinner = winner
+	l.lastAuctionAt = closedAt
+	l.auctionMu.Unlock()
+	if winner != nil {
+		l.saveRanking(blockNum, ranked, closedAt)
+	}
+	if l.metrics != nil {
+		l.metrics.ObserveAuction(closedAt.Sub(openedAt), int(l.auctionBids.Load()), winner != nil)
+	}
+	if l.recorder != nil {
+		if err := l.recorder.SaveAuctionResult(blockNum, winner, closedAt); err != nil {
+			l.logger.Error("failed to record auction result", "blockNumber", blockNum, "error", err)
+		}
+	}
+	l.eventFeed.Send(auction.Event{Type: auction.EventAuctionClosed, L1Block: new(big.Int).SetUint64(blockNum), Bid: winner, Timestamp: closedAt})
+}
+
+// Rankings of the most recent auctions kept for fallbacks, which happen within the slot
+const rankingRetention = 8
+
+var (
+	ErrNoFallbackBid = errors.New("no fallback bid")
+	ErrNotWinner     = errors.New("not the auction's current winner")
+	ErrSlotOver      = errors.New("slot is over")
+)
+
+// Valid bids of a won auction, best first, and the current winner's position among them
+type ranking struct {
+	bids   []auction.SignedBid
+	winner int
+	// End of the slot the auction closed in, after which there's no time left to fall back. Zero if slots aren't
+	// scheduled.
+	deadline time.Time
+}
+
+func (l *Listener) saveRanking(blockNum uint64, ranked []auction.SignedBid, closedAt time.Time) {
+	r := &ranking{bids: ranked}
+	if l.slotTime > 0 {
+		r.deadline = l.genesis.Add((closedAt.Sub(l.genesis)/l.slotTime + 1) * l.slotTime)
+	}
+	l.auctionMu.Lock()
+	defer l.auctionMu.Unlock()
+	if l.rankings == nil {
+		l.rankings = make(map[uint64]*ranking)
+	}
+	l.rankings[blockNum] = r
+	for block := range l.rankings {
+		if block+rankingRetention <= blockNum {
+			delete(l.rankings, block)
+		}
+	}
+}
+
+// Valid bids of a recent won auction, each relay's best, best first. Nil if the auction had no winner or is no
+// longer kept.
+func (l *Listener) Ranking(l1Block uint64) []auction.SignedBid {
+	l.auctionMu.RLock()
+	defer l.auctionMu.RUnlock()
+	r, ok := l.rankings[l1Block]
+	if !ok {
+		return nil
+	}
+	return append([]auction.SignedBid(nil), r.bids...)
+}
+
+// Replaces the current winner of a recent auction with the next highest valid bid of another relay when the
+// winner fails at a stage, e.g. declining its award or failing to pay. Fallbacks cascade down the ranking as each
+// new winner fails in turn, until the end of the slot the auction closed in. Each hand-off is recorded as the
+// auction's new result and published as a winnerFallback event. Fails with ErrNotWinner if failed isn't the
+// auction's current winner, ErrNoFallbackBid if there's no bid left and ErrSlotOver if the slot is over.
+func (l *Listener) FallBack(l1Block uint64, failed common.Address, stage auction.FallbackStage, reason string) (auction.SignedBid, error) {
+	now := time.Now()
+	l.auctionMu.Lock()
+	r, ok := l.rankings[l1Block]
+	var err error
+	switch {
+	case !ok || r.winner >= len(r.bids):
+		err = ErrNoFallbackBid
+	case r.bids[r.winner].Address != failed:
+		err = ErrNotWinner
+	case r.winner+1 >= len(r.bids):
+		err = ErrNoFallbackBid
+	case !r.deadline.IsZero() && !now.Before(r.deadline):
+		err = ErrSlotOver
+	}
+	if err != nil {
+		l.auctionMu.Unlock()
+		return auction.SignedBid{}, err
+	}
+	r.winner++
+	next := r.bids[r.winner]
+	if l1Block == l.lastAuctionBlock {
+		l.lastAuctionWinner = &next
+	}
+	l.auctionMu.Unlock()
+
+	l.logger.Warn("winner failed, falling back to the next bid", "blockNumber", l1Block, "failed", failed, "stage", stage,
+		"reason", reason, "winner", next.Address, "amount", next.AmountWei, "rank", r.winner+1)
+	if l.recorder != nil {
+		if err := l.recorder.SaveAuctionResult(l1Block, &next, now); err != nil {
+			l.logger.Error("failed to record auction result", "blockNumber", l1Block, "error", err)
+		}
+	}
+	l.eventFeed.Send(auction.Event{Type: auction.EventWinnerFallback, L1Block: new(big.Int).SetUint64(l1Block), Bid: &next,
+		Failed: &failed, Stage: stage, Error: reason, Timestamp: now})
+	return next, nil
+}
+
 // To satisfy bid submissions from relays
 func (l *Listener) SubmitBid(bid auction.SignedBid) error {
+	return l.SubmitBidAt(bid, l.arrivals.Now())
+}
+
+// Submits a bid that reached the auctioneer at receivedAt, e.g. one gossiped by the replica it reached, which
+// ties are broken by and it's recorded with
+func (l *Listener) SubmitBidAt(bid auction.SignedBid, receivedAt time.Time) error {
+	// Malformed rather than rejected, the auction reports bad signatures
+	if bid.AmountWei == nil || bid.L1Block == nil {
+		return fmt.Errorf("%w: missing amountWei or l1Block", auction.ErrInvalidBid)
+	}
+	l.auctionMu.RLock()
+	defer l.auctionMu.RUnlock()
+	if l.currentAuction == nil || bid.L1Block.Uint64() != l.currentAuctionBlock {
+		if next, ok := l.nextAuction(time.Now()); ok && bid.L1Block.Uint64() == next {
+			return l.queueBid(bid, next, receivedAt)
+		}
+	}
 	if l.currentAuction == nil {
-		return fmt.Errorf("no auction in progress")
+		return l.reject(bid, auction.RejectNoAuction)
+	}
+	if bid.L1Block.Uint64() != l.currentAuctionBlock {
+		return l.reject(bid, auction.RejectWrongBlock)
+	}
+	// Checked again as the auction evaluates the bid, but cheap enough to tell the relay why synchronously
+	if !l.relayRegistry.IsRegisteredOnSettlementLayer(bid.Address) {
+		return l.reject(bid, auction.RejectNotRegistered)
+	}
+	if l.currentReservePrice != nil && bid.AmountWei.Cmp(l.currentReservePrice) < 0 {
+		return l.reject(bid, auction.RejectBelowReserve)
+	}
+	// Before the escrow check, so a relay whose bond runs low is told why
+	if !l.withinCap(bid) {
+		return l.reject(bid, auction.RejectOverBondCap)
 	}
-	if bid.L1Block.Uint64() != l.currentBlockNum {
-		return fmt.Errorf("bid is for a different block")
+	if l.escrow != nil {
+		covered, err := l.escrow.Covers(bid.Address, bid.AmountWei)
+		if err != nil {
+			l.logger.Warn("failed to check escrow, accepting bid", "bid", bid, "error", err)
+		} else if !covered {
+			return l.reject(bid, auction.RejectUncovered)
+		}
+	}
+	if !l.withinQuota(bid) {
+		return l.reject(bid, auction.RejectOverQuota)
+	}
+	// Never blocks past the auction's close, which takes auctionMu to replace the auction
+	if err := l.currentAuction.SubmitBidAt(bid, receivedAt); err != nil {
+		return l.reject(bid, auction.RejectNoAuction)
+	}
+	l.auctionBids.Add(1)
+	if l.recorder != nil {
+		if err := l.recorder.SaveBid(bid, receivedAt); err != nil {
+			l.logger.Error("failed to record bid", "bid", bid, "error", err)
+		}
 	}
-	l.currentAuction.SubmitBid(bid)
 	return nil
 }
 
+func (l *Listener) reject(bid auction.SignedBid, code auction.RejectCode) error {
+	if l.auditor != nil {
+		l.auditor.RecordBid(bid, false, code.Reason())
+	}
+	l.rejectionFeed.Send(auction.Rejection{Bid: bid, Code: code, Reason: code.Reason(), Timestamp: time.Now()})
+	return code.Err()
+}
+
 // To satisfy RPC requests for current winning bid, enabling open auction.
 func (l *Listener) GetCurrentBid() (winningBid auction.SignedBid, found bool) {
+	l.auctionMu.RLock()
+	defer l.auctionMu.RUnlock()
 	if l.currentAuction == nil {
 		return auction.SignedBid{}, false
 	}
 	return l.currentAuction.GetCurrentBid(), true
 }
+
+// To satisfy queries for the auction of a given L1 block. Only the current and last concluded auctions are kept.
+func (l *Listener) GetAuction(l1Block uint64) (state AuctionState, found bool) {
+	l.auctionMu.RLock()
+	defer l.auctionMu.RUnlock()
+	if l.currentAuction != nil && l1Block == l.currentAuctionBlock {
+		var leader *auction.SignedBid
+		if bid := l.currentAuction.GetCurrentBid(); bid.Address != (common.Address{}) {
+			leader = &bid
+		}
+		return AuctionState{L1Block: l1Block, InProgress: true, LeadingBid: leader}, true
+	}
+	if l.lastAuctionBlock != 0 && l1Block == l.lastAuctionBlock {
+		return AuctionState{L1Block: l1Block, InProgress: false, LeadingBid: l.lastAuctionWinner}, true
+	}
+	return AuctionState{}, false
+}
+
+// When the last auction closed since the listener started, zero if none has
+func (l *Listener) LastAuctionAt() time.Time {
+	l.auctionMu.RLock()
+	defer l.auctionMu.RUnlock()
+	return l.lastAuctionAt
+}
+
+// Stops starting auctions for new blocks, an auction in progress runs to completion
+func (l *Listener) Pause() {
+	l.paused.Store(true)
+}
+
+func (l *Listener) Resume() {
+	l.paused.Store(false)
+}
+
+func (l *Listener) Paused() bool {
+	return l.paused.Load()
+}
+
+// Closes the auction in progress with no winner. Returns its L1 block, or false if there's none.
+func (l *Listener) CancelAuction() (l1Block uint64, cancelled bool) {
+	l.auctionMu.RLock()
+	defer l.auctionMu.RUnlock()
+	if l.cancelAuction == nil {
+		return 0, false
+	}
+	l.currentAuction.Cancel()
+	// Ends the wait for the auction's result, which a cancelled auction doesn't send
+	l.cancelAuction()
+	return l.currentAuctionBlock, true
+}
+
+// Relays allowed to bid, applied from the next bid evaluated
+func (l *Listener) AccessList() *auction.AccessList {
+	return l.accessList
+}
+
+// For other oracle workers to publish events on the feed, e.g. settlement of a won auction,
+// which is recorded so it isn't resumed after a restart. Settlement events are stamped with the build.
+func (l *Listener) PublishEvent(ev auction.Event) {
+	if (ev.Type == auction.EventSettlement || ev.Type == auction.EventSettlementFailed) && ev.Build == nil {
+		build := version.Get()
+		ev.Build = &build
+	}
+	if l.metrics != nil && (ev.Type == auction.EventSettlement || ev.Type == auction.EventSettlementFailed) {
+		l.metrics.ObserveSettlement(ev.Type == auction.EventSettlement)
+	}
+	if ev.Type == auction.EventSettlement && l.recorder != nil && ev.L1Block != nil && ev.SettlementTx != nil {
+		if err := l.recorder.SaveSettlement(ev.L1Block.Uint64(), *ev.SettlementTx, ev.Timestamp); err != nil {
+			l.logger.Error("failed to record settlement", "blockNumber", ev.L1Block, "error", err)
+		}
+	}
+	l.eventFeed.Send(ev)
+}
+
+// Subscribes to auction lifecycle events. Events are dropped for a subscriber whose buffer is full,
+// so a slow subscriber can't stall auctions.
+func (l *Listener) SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription) {
+	in := make(chan auction.Event)
+	out := make(chan auction.Event, bufferSize)
+	sub := l.eventFeed.Subscribe(in)
+	l.subscribersMu.Lock()
+	if l.subscribers == nil {
+		l.subscribers = make(map[chan auction.Event]struct{})
+	}
+	l.subscribers[out] = struct{}{}
+	l.subscribersMu.Unlock()
+	go func() {
+		defer close(out)
+		defer func() {
+			l.subscribersMu.Lock()
+			delete(l.subscribers, out)
+			l.subscribersMu.Unlock()
+		}()
+		for {
+			select {
+			case ev := <-in:
+				select {
+				case out <- ev:
+				default:
+					l.logger.Warn("dropping auction event for slow subscriber", "type", ev.Type, "l1Block", ev.L1Block)
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return out, sub
+}
+
+// Subscribes to rejected bids, whether rejected by the listener or evaluated by the auction. Rejections are
+// dropped for a subscriber whose buffer is full, so a slow subscriber can't stall auctions.
+func (l *Listener) SubscribeRejections(bufferSize int) (<-chan auction.Rejection, event.Subscription) {
+	in := make(chan auction.Rejection)
+	out := make(chan auction.Rejection, bufferSize)
+	sub := l.rejectionFeed.Subscribe(in)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case rejection := <-in:
+				select {
+				case out <- rejection:
+				default:
+					l.logger.Debug("dropping bid rejection for slow subscriber", "bid", rejection.Bid)
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return out, sub
+}
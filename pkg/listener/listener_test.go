@@ -2,6 +2,7 @@ package listener_test
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"math/big"
 	"sync"
@@ -9,7 +10,10 @@ import (
 	"time"
 
 	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/ethtest"
 	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/sealed"
+	"blob-preconfs/pkg/version"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -59,11 +63,14 @@ func TestListenerSimulation(t *testing.T) {
 	mockEthClient := NewMockEthClient(100)
 	mockRelayRegistry := &mockRelayRegistry{}
 
-	l := listener.NewListener(logger, mockEthClient, mockRelayRegistry)
+	l := listener.NewListener(logger, mockEthClient, mockRelayRegistry, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+
 	_, auctionWonChan, err := l.Start(ctx)
 	if err != nil {
 		t.Fatalf("Failed to start listener: %v", err)
@@ -82,6 +89,27 @@ func TestListenerSimulation(t *testing.T) {
 	case bid := <-auctionWonChan:
 		t.Logf("Auction won: %+v", bid)
 		require.EqualValues(t, *signedBid, bid)
+
+		state, found := l.GetAuction(blockNum)
+		require.True(t, found)
+		require.False(t, state.InProgress)
+		require.EqualValues(t, *signedBid, *state.LeadingBid)
+
+		var eventTypes []auction.EventType
+		for i := 0; i < 3; i++ {
+			select {
+			case ev := <-events:
+				require.EqualValues(t, blockNum, ev.L1Block.Uint64())
+				eventTypes = append(eventTypes, ev.Type)
+			case <-time.After(time.Second):
+				t.Fatal("Test timed out waiting for auction events")
+			}
+		}
+		require.Equal(t, []auction.EventType{
+			auction.EventAuctionOpened,
+			auction.EventLeaderChanged,
+			auction.EventAuctionClosed,
+		}, eventTypes)
 		return
 	case <-testTimeout:
 		t.Fatal("Test timed out waiting for auction win")
@@ -89,3 +117,1047 @@ func TestListenerSimulation(t *testing.T) {
 		t.Error("Context was canceled before auction win could be received")
 	}
 }
+
+type mockRecorder struct {
+	mu          sync.Mutex
+	bids        []auction.SignedBid
+	received    []time.Time
+	auctions    []uint64
+	settlements []uint64
+}
+
+func (m *mockRecorder) SaveBid(bid auction.SignedBid, receivedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bids = append(m.bids, bid)
+	m.received = append(m.received, receivedAt)
+	return nil
+}
+
+func (m *mockRecorder) SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctions = append(m.auctions, l1Block)
+	return nil
+}
+
+func (m *mockRecorder) SaveSettlement(l1Block uint64, tx common.Hash, settledAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settlements = append(m.settlements, l1Block)
+	return nil
+}
+
+func TestPauseAndCancelAuction(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	recorder := &mockRecorder{}
+	l.SetRecorder(recorder)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+
+	l.Pause()
+	require.True(t, l.Paused())
+	l.FacilitateRelayAuction()
+	_, cancelled := l.CancelAuction()
+	require.False(t, cancelled)
+	select {
+	case ev := <-events:
+		t.Fatalf("Unexpected event while paused: %v", ev.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.True(t, l.LastAuctionAt().IsZero())
+
+	l.Resume()
+	done := make(chan struct{})
+	go func() {
+		l.FacilitateRelayAuction()
+		close(done)
+	}()
+	select {
+	case ev := <-events:
+		require.Equal(t, auction.EventAuctionOpened, ev.Type)
+	case <-time.After(time.Second):
+		t.Fatal("Test timed out waiting for auction to open")
+	}
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(0), pk)))
+	_, cancelled = l.CancelAuction()
+	require.True(t, cancelled)
+	for closed := false; !closed; {
+		select {
+		case ev := <-events:
+			if ev.Type == auction.EventLeaderChanged {
+				continue
+			}
+			require.Equal(t, auction.EventAuctionClosed, ev.Type)
+			require.Nil(t, ev.Bid)
+			closed = true
+		case <-time.After(time.Second):
+			t.Fatal("Test timed out waiting for auction to close")
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Cancelled auction did not return")
+	}
+	_, found := l.GetCurrentBid()
+	require.False(t, found)
+	require.WithinDuration(t, time.Now(), l.LastAuctionAt(), time.Second)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Len(t, recorder.bids, 1)
+	require.Equal(t, []uint64{0}, recorder.auctions)
+}
+
+func TestRestoreResumesSettlement(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	recorder := &mockRecorder{}
+	l.SetRecorder(recorder)
+	pk, _ := crypto.GenerateKey()
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(99), pk)
+	l.Restore(99, winner, []auction.SignedBid{*winner})
+
+	state, found := l.GetAuction(99)
+	require.True(t, found)
+	require.Equal(t, listener.AuctionState{L1Block: 99, LeadingBid: winner}, state)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, auctionWonChan, err := l.Start(ctx)
+	require.NoError(t, err)
+	select {
+	case bid := <-auctionWonChan:
+		require.Equal(t, *winner, bid)
+	case <-time.After(time.Second):
+		t.Fatal("Test timed out waiting for unsettled auction")
+	}
+
+	tx := common.Hash{0x01}
+	l.PublishEvent(auction.Event{Type: auction.EventSettlement, L1Block: big.NewInt(99), Bid: winner, SettlementTx: &tx, Timestamp: time.Now()})
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Equal(t, []uint64{99}, recorder.settlements)
+}
+
+func TestSettlementEventsStamped(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	tx := common.Hash{0x01}
+	l.PublishEvent(auction.Event{Type: auction.EventSettlement, L1Block: big.NewInt(99), SettlementTx: &tx, Timestamp: time.Now()})
+	select {
+	case ev := <-events:
+		require.Equal(t, version.Get(), *ev.Build)
+	case <-time.After(time.Second):
+		t.Fatal("Test timed out waiting for settlement event")
+	}
+}
+
+type mockAuditor struct {
+	rejected []string
+}
+
+func (m *mockAuditor) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	if !accepted {
+		m.rejected = append(m.rejected, reason)
+	}
+}
+
+func TestRejectedBidsAudited(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	auditor := &mockAuditor{}
+	l.SetAuditor(auditor)
+	pk, _ := crypto.GenerateKey()
+	require.Error(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)))
+	require.Equal(t, []string{"no auction in progress"}, auditor.rejected)
+}
+
+func TestRejectionsSubscribed(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	rejections, sub := l.SubscribeRejections(1)
+	defer sub.Unsubscribe()
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.Error(t, l.SubmitBid(*bid))
+	select {
+	case rejection := <-rejections:
+		require.Equal(t, bid.Address, rejection.Bid.Address)
+		require.Equal(t, auction.RejectNoAuction, rejection.Code)
+		require.Nil(t, rejection.Leader)
+	case <-time.After(time.Second):
+		require.Fail(t, "no rejection received")
+	}
+}
+
+func TestMalformedBidsInvalid(t *testing.T) {
+	l := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, func(l1Block uint64, params listener.AuctionParams) listener.Auction {
+		return &mockAuction{cancelled: make(chan struct{})}
+	})
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetAuctionPeriod(100 * time.Millisecond)
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk)) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	noBlock := auction.MustCreateSignedBid(big.NewInt(40), nil, pk)
+	require.ErrorIs(t, l.SubmitBid(*noBlock), auction.ErrInvalidBid)
+	noAmount := auction.MustCreateSignedBid(nil, big.NewInt(100), pk)
+	require.ErrorIs(t, l.SubmitBid(*noAmount), auction.ErrInvalidBid)
+	<-auctionWon
+}
+
+type mockEscrow map[common.Address]int64
+
+func (m mockEscrow) Covers(relay common.Address, amountWei *big.Int) (bool, error) {
+	return amountWei.Cmp(big.NewInt(m[relay])) <= 0, nil
+}
+
+func TestEscrowCheck(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	l.SetEscrowCheck(mockEscrow{crypto.PubkeyToAddress(pk.PublicKey): 50})
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk)) == nil
+	},
+		time.Second, 10*time.Millisecond)
+	rejections, sub := l.SubscribeRejections(1)
+	defer sub.Unsubscribe()
+	require.EqualError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(51), big.NewInt(100), pk)), "bid exceeds the bidder's escrow balance")
+	require.Equal(t, auction.RejectUncovered, (<-rejections).Code)
+	require.Equal(t, big.NewInt(50), (<-auctionWon).AmountWei)
+}
+
+type mockQuotas int
+
+func (m mockQuotas) BidQuota(relay common.Address) int { return int(m) }
+
+func TestBidQuotas(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	l.SetBidQuotas(mockQuotas(2))
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk)) == nil
+	},
+		time.Second, 10*time.Millisecond)
+	second := *auction.MustCreateSignedBid(big.NewInt(60), big.NewInt(100), pk)
+	require.NoError(t, l.SubmitBid(second))
+	require.NoError(t, l.SubmitBid(second), "resubmitting a counted bid doesn't use up the quota")
+	require.ErrorIs(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(70), big.NewInt(100), pk)), auction.ErrOverQuota)
+	require.Equal(t, big.NewInt(60), (<-auctionWon).AmountWei)
+}
+
+// Slows evaluation down, so flooding submitters fill the auction's queue
+type slowAuditor struct{}
+
+func (slowAuditor) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	time.Sleep(time.Millisecond)
+}
+
+func TestSubmitAcrossClose(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	l.SetAuditor(slowAuditor{})
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+	require.Eventually(t, func() bool {
+		_, found := l.GetCurrentBid()
+		return found
+	}, time.Second, 10*time.Millisecond)
+
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+			for amount := i * 1_000_000; ; amount++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(100), pk))
+			}
+		}(int64(i + 1))
+	}
+	select {
+	case <-auctionWon:
+	case <-time.After(2 * time.Second):
+		t.Fatal("auction didn't close")
+	}
+	current := make(chan struct{})
+	go func() {
+		defer close(current)
+		l.GetCurrentBid()
+	}()
+	select {
+	case <-current:
+	case <-time.After(time.Second):
+		t.Fatal("reading the leader hung after the auction closed")
+	}
+	close(stop)
+	submitted := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(submitted)
+	}()
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		t.Fatal("submitters hung after the auction closed")
+	}
+	require.ErrorIs(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(100), pk)), auction.ErrNoActiveAuction)
+}
+
+type mockCaps map[common.Address]*big.Int
+
+func (m mockCaps) BidCap(relay common.Address) *big.Int { return m[relay] }
+
+func TestBidCaps(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	l.SetBidCaps(mockCaps{crypto.PubkeyToAddress(pk.PublicKey): big.NewInt(60)})
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(60), big.NewInt(100), pk)) == nil
+	},
+		time.Second, 10*time.Millisecond)
+	require.ErrorIs(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(61), big.NewInt(100), pk)), auction.ErrOverBondCap)
+	require.Equal(t, big.NewInt(60), (<-auctionWon).AmountWei)
+}
+
+type mockPolicy struct {
+	mu     sync.Mutex
+	blocks []uint64
+}
+
+func (m *mockPolicy) Params(l1Block uint64, defaults listener.AuctionParams) listener.AuctionParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blocks = append(m.blocks, l1Block)
+	return listener.AuctionParams{Period: defaults.Period / 2, ReservePriceWei: big.NewInt(45)}
+}
+
+func TestAuctionPolicy(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	l.SetAuctionPeriod(time.Second)
+	policy := &mockPolicy{}
+	l.SetAuctionPolicy(policy)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	opened := <-events
+	require.Equal(t, auction.EventAuctionOpened, opened.Type)
+	require.Equal(t, big.NewInt(45), opened.ReservePriceWei)
+	require.Equal(t, opened.Timestamp.Add(500*time.Millisecond), *opened.ClosesAt, "closes after the policy's period")
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk1)))
+	require.ErrorIs(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk2)), auction.ErrBelowReserve)
+	select {
+	case winner := <-auctionWon:
+		require.Equal(t, big.NewInt(50), winner.AmountWei)
+		require.WithinDuration(t, opened.Timestamp.Add(500*time.Millisecond), time.Now(), 200*time.Millisecond, "closed after the policy's period")
+	case <-time.After(time.Second):
+		t.Fatal("Test timed out waiting for the auction to close")
+	}
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	require.Equal(t, []uint64{100}, policy.blocks)
+}
+
+type mockMetrics struct {
+	mu            sync.Mutex
+	blocks        []uint64
+	auctionBids   []int
+	verifications int
+	bidStages     []auction.BidStage
+	settlements   []bool
+	rpcCalls      int
+	rpcErrors     int
+}
+
+func (m *mockMetrics) ObserveBlock(block uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blocks = append(m.blocks, block)
+}
+
+func (m *mockMetrics) ObserveAuction(duration time.Duration, bids int, won bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctionBids = append(m.auctionBids, bids)
+}
+
+func (m *mockMetrics) ObserveSettlement(succeeded bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settlements = append(m.settlements, succeeded)
+}
+
+func (m *mockMetrics) ObserveRPC(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rpcCalls++
+	if err != nil {
+		m.rpcErrors++
+	}
+}
+
+func (m *mockMetrics) ObserveBidVerification(duration time.Duration, valid bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verifications++
+}
+
+func (m *mockMetrics) ObserveBidLatency(stage auction.BidStage, latency time.Duration, bid auction.SignedBid) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bidStages = append(m.bidStages, stage)
+}
+
+func (m *mockMetrics) ObserveBidQueueDepth(depth int) {}
+
+func TestMetricsObserved(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	metrics := &mockMetrics{}
+	l.SetMetrics(metrics)
+	done := make(chan struct{})
+	go func() {
+		l.FacilitateRelayAuction()
+		close(done)
+	}()
+	pk, _ := crypto.GenerateKey()
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(0), pk)) == nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(44), big.NewInt(0), pk)))
+	require.Eventually(t, func() bool {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return metrics.verifications == 2
+	}, time.Second, 10*time.Millisecond)
+	l.CancelAuction()
+	<-done
+	l.MustGetBlockNum()
+
+	tx := common.Hash{0x01}
+	l.PublishEvent(auction.Event{Type: auction.EventSettlement, L1Block: big.NewInt(0), SettlementTx: &tx, Timestamp: time.Now()})
+	l.PublishEvent(auction.Event{Type: auction.EventSettlementFailed, L1Block: big.NewInt(0), Error: "reverted", Timestamp: time.Now()})
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	require.Equal(t, []int{2}, metrics.auctionBids)
+	require.Equal(t, []auction.BidStage{auction.BidStageVerified, auction.BidStageVerified}, metrics.bidStages, "bidder not whitelisted")
+	require.Equal(t, []bool{true, false}, metrics.settlements)
+	require.Equal(t, 1, metrics.rpcCalls)
+}
+
+type mockAlerter struct {
+	mu       sync.Mutex
+	rpcCalls []string
+}
+
+func (m *mockAlerter) AuctionTimedOut(l1Block uint64, after time.Duration) {}
+
+func (m *mockAlerter) ObserveRPC(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rpcCalls = append(m.rpcCalls, method)
+}
+
+func TestAlerterObservesRPC(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	alerter := &mockAlerter{}
+	l.SetAlerter(alerter)
+	require.Equal(t, uint64(100), l.MustGetBlockNum())
+	require.Equal(t, []string{"eth_blockNumber"}, alerter.rpcCalls)
+}
+
+func TestAuctionPeriod(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	l.SetAuctionPeriod(100 * time.Millisecond)
+	started := time.Now()
+	l.FacilitateRelayAuction()
+	require.Less(t, time.Since(started), time.Second, "auction closes after the configured period")
+}
+
+func TestStopWaitsForAuction(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	stopped := make(chan error)
+	go func() { stopped <- l.Stop(context.Background()) }()
+	select {
+	case bid := <-auctionWon:
+		require.Equal(t, big.NewInt(43), bid.AmountWei, "the auction in progress runs to completion")
+	case <-time.After(2 * time.Second):
+		t.Fatal("auction not won")
+	}
+	require.NoError(t, <-stopped)
+	state, found := l.GetAuction(100)
+	require.True(t, found)
+	require.False(t, state.InProgress)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, l.Stop(ctx), "stopping again returns immediately")
+}
+
+func TestFallBackCascade(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	recorder := &mockRecorder{}
+	l.SetRecorder(recorder)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	pk3, _ := crypto.GenerateKey()
+	l.AccessList().Allow(crypto.PubkeyToAddress(pk3.PublicKey))
+	third := auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk3)
+	require.Eventually(t, func() bool { return l.SubmitBid(*third) == nil }, time.Second, 10*time.Millisecond)
+	runnerUp := auction.MustCreateSignedBid(big.NewInt(41), big.NewInt(100), pk2)
+	require.NoError(t, l.SubmitBid(*runnerUp))
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)))
+
+	winner := <-auctionWon
+	require.Equal(t, []auction.SignedBid{winner, *runnerUp, *third}, l.Ranking(100))
+	require.Nil(t, l.Ranking(99))
+	_, err = l.FallBack(100, runnerUp.Address, auction.StageAcceptance, "declined")
+	require.ErrorIs(t, err, listener.ErrNotWinner, "only the current winner fails")
+	fallback, err := l.FallBack(100, winner.Address, auction.StageAcceptance, "declined")
+	require.NoError(t, err)
+	require.Equal(t, *runnerUp, fallback)
+	state, _ := l.GetAuction(100)
+	require.Equal(t, *runnerUp, *state.LeadingBid)
+	_, err = l.FallBack(100, winner.Address, auction.StageAcceptance, "declined")
+	require.ErrorIs(t, err, listener.ErrNotWinner, "the original winner no longer wins")
+	fallback, err = l.FallBack(100, runnerUp.Address, auction.StagePayment, "insufficient funds")
+	require.NoError(t, err, "fallbacks cascade")
+	require.Equal(t, *third, fallback)
+	_, err = l.FallBack(100, third.Address, auction.StagePayment, "insufficient funds")
+	require.ErrorIs(t, err, listener.ErrNoFallbackBid, "no bid left")
+	_, err = l.FallBack(99, winner.Address, auction.StageAcceptance, "declined")
+	require.ErrorIs(t, err, listener.ErrNoFallbackBid)
+
+	recorder.mu.Lock()
+	require.Equal(t, []uint64{100, 100, 100}, recorder.auctions, "each hand-off is recorded")
+	recorder.mu.Unlock()
+	var fallbacks []auction.Event
+	for ev := range events {
+		if ev.Type == auction.EventWinnerFallback {
+			if fallbacks = append(fallbacks, ev); len(fallbacks) == 2 {
+				break
+			}
+		}
+	}
+	require.Equal(t, *runnerUp, *fallbacks[0].Bid)
+	require.Equal(t, winner.Address, *fallbacks[0].Failed)
+	require.Equal(t, auction.StageAcceptance, fallbacks[0].Stage)
+	require.Equal(t, "declined", fallbacks[0].Error)
+	require.Equal(t, *third, *fallbacks[1].Bid)
+	require.Equal(t, runnerUp.Address, *fallbacks[1].Failed)
+	require.Equal(t, auction.StagePayment, fallbacks[1].Stage)
+}
+
+func TestNoFallBackAfterSlot(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	l.SetAuctionPeriod(100 * time.Millisecond)
+	// Slots of 400ms, the auction closes well within the current one
+	l.SetSlotSchedule(time.Now().Add(-time.Millisecond), 400*time.Millisecond, 0, 0)
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk2)) == nil
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)))
+
+	winner := <-auctionWon
+	time.Sleep(400 * time.Millisecond)
+	_, err = l.FallBack(100, winner.Address, auction.StageAcceptance, "timed out")
+	require.ErrorIs(t, err, listener.ErrSlotOver)
+}
+
+// Collects the blocks auctions open for, until the client's timeline is done and polling settles
+func openedAuctions(t *testing.T, client *ethtest.Client, configure func(l *listener.Listener)) []uint64 {
+	l := listener.NewListener(slog.Default(), client, &mockRelayRegistry{}, nil)
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetAuctionPeriod(20 * time.Millisecond)
+	if configure != nil {
+		configure(l)
+	}
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	_, _, err := l.Start(context.Background())
+	require.NoError(t, err)
+	require.Eventually(t, client.Done, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	require.NoError(t, l.Stop(context.Background()))
+
+	var blocks []uint64
+	for {
+		select {
+		case ev := <-events:
+			if ev.Type == auction.EventAuctionOpened {
+				blocks = append(blocks, ev.L1Block.Uint64())
+			}
+		default:
+			return blocks
+		}
+	}
+}
+
+func TestReorgOpensNoAuction(t *testing.T) {
+	client := ethtest.NewClient(100,
+		ethtest.At(100*time.Millisecond, ethtest.Head(101)),
+		ethtest.At(200*time.Millisecond, ethtest.Head(100)),
+		ethtest.At(300*time.Millisecond, ethtest.Head(101)),
+		ethtest.At(400*time.Millisecond, ethtest.Head(102)),
+	)
+	require.Equal(t, []uint64{100, 101, 102}, openedAuctions(t, client, nil), "blocks are auctioned once")
+}
+
+func TestMissedBlocksAuctionLatest(t *testing.T) {
+	client := ethtest.NewClient(100,
+		ethtest.At(100*time.Millisecond, ethtest.Head(104)),
+	)
+	require.Equal(t, []uint64{100, 104}, openedAuctions(t, client, nil))
+}
+
+func TestPollFailuresRetried(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	client := ethtest.NewClient(100,
+		ethtest.At(100*time.Millisecond, ethtest.Fail(errUnavailable)),
+		ethtest.At(200*time.Millisecond, ethtest.Recover(), ethtest.Head(101), ethtest.Latency(30*time.Millisecond)),
+		ethtest.At(300*time.Millisecond, ethtest.Head(102)),
+	)
+	metrics := &mockMetrics{}
+	blocks := openedAuctions(t, client, func(l *listener.Listener) {
+		l.SetMaxPollFailures(1000)
+		l.SetMetrics(metrics)
+	})
+	require.Equal(t, []uint64{100, 101, 102}, blocks, "polling resumes once the node recovers")
+
+	failed := 0
+	for _, call := range client.Calls() {
+		if call.Err != nil {
+			failed++
+		}
+	}
+	require.Positive(t, failed)
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	require.Equal(t, failed, metrics.rpcErrors)
+}
+
+type mockClockGuard struct {
+	mu    sync.Mutex
+	heads []uint64
+	err   error
+}
+
+func (m *mockClockGuard) ObserveHead(block uint64, seenAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heads = append(m.heads, block)
+}
+
+func (m *mockClockGuard) Check() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+func TestClockDriftOpensNoAuction(t *testing.T) {
+	client := ethtest.NewClient(100,
+		ethtest.At(100*time.Millisecond, ethtest.Head(101)),
+		ethtest.At(200*time.Millisecond, ethtest.Head(102)),
+	)
+	guard := &mockClockGuard{}
+	blocks := openedAuctions(t, client, func(l *listener.Listener) {
+		l.SetClockGuard(guard)
+		go func() {
+			time.Sleep(150 * time.Millisecond)
+			guard.mu.Lock()
+			guard.err = errors.New("clock drifted")
+			guard.mu.Unlock()
+		}()
+	})
+	require.Equal(t, []uint64{100, 101}, blocks, "no auction opens once the clock drifted")
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+	require.Equal(t, []uint64{100, 101, 102}, guard.heads, "heads are observed regardless")
+}
+
+func TestSlotSchedule(t *testing.T) {
+	genesis := time.Now().Add(-50 * time.Millisecond)
+	client := ethtest.NewClient(100)
+	l := listener.NewListener(slog.Default(), client, &mockRelayRegistry{}, nil)
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetSlotSchedule(genesis, time.Second, 200*time.Millisecond, 400*time.Millisecond)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	_, _, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	for _, expected := range []struct {
+		event  auction.EventType
+		offset time.Duration
+	}{{auction.EventAuctionOpened, 200 * time.Millisecond}, {auction.EventAuctionClosed, 400 * time.Millisecond}} {
+		select {
+		case ev := <-events:
+			require.Equal(t, expected.event, ev.Type)
+			require.GreaterOrEqual(t, ev.Timestamp.Sub(genesis), expected.offset)
+			require.Less(t, ev.Timestamp.Sub(genesis), expected.offset+100*time.Millisecond)
+		case <-time.After(time.Second):
+			t.Fatalf("Test timed out waiting for %s", expected.event)
+		}
+	}
+}
+
+func TestSlotScheduleSkipsLateBlocks(t *testing.T) {
+	l := listener.NewListener(slog.Default(), NewMockEthClient(100), &mockRelayRegistry{}, nil)
+	l.SetSlotSchedule(time.Now().Add(-700*time.Millisecond), time.Second, 0, 400*time.Millisecond)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	l.FacilitateRelayAuction()
+	select {
+	case ev := <-events:
+		t.Fatalf("Unexpected event after the auction window: %v", ev.Type)
+	default:
+	}
+}
+
+func TestPreAuctionQueue(t *testing.T) {
+	genesis := time.Now().Add(-50 * time.Millisecond)
+	l := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, nil)
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetSlotSchedule(genesis, time.Second, 300*time.Millisecond, 500*time.Millisecond)
+	l.SetPreAuctionWindow(400 * time.Millisecond)
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	other, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk)
+	require.Eventually(t, func() bool { return l.SubmitBid(*bid) == nil }, time.Second, 5*time.Millisecond,
+		"queued once the block is seen, before its auction opens")
+	_, found := l.GetAuction(100)
+	require.False(t, found)
+
+	require.EqualError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk)),
+		"bid does not beat the leading bid", "relay's best queued bid kept")
+	forged := *auction.MustCreateSignedBid(big.NewInt(60), big.NewInt(100), other)
+	forged.Address = crypto.PubkeyToAddress(pk.PublicKey)
+	require.EqualError(t, l.SubmitBid(forged), "invalid signature")
+	require.Error(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(60), big.NewInt(101), other)))
+
+	select {
+	case won := <-auctionWon:
+		require.Equal(t, *bid, won, "queued bid submitted as the auction opened")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Test timed out waiting for auction win")
+	}
+}
+
+func TestPreAuctionQueueBetweenAuctions(t *testing.T) {
+	client := ethtest.NewClient(100,
+		ethtest.At(300*time.Millisecond, ethtest.Head(101)),
+		ethtest.At(600*time.Millisecond, ethtest.Head(103)),
+	)
+	l := listener.NewListener(slog.Default(), client, &mockRelayRegistry{}, nil)
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetAuctionPeriod(50 * time.Millisecond)
+	l.SetPreAuctionWindow(time.Second)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	rejections, rejectionsSub := l.SubscribeRejections(4)
+	defer rejectionsSub.Unsubscribe()
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	for ev := range events {
+		if ev.Type == auction.EventAuctionClosed {
+			break
+		}
+	}
+	bid := auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(101), pk)
+	require.NoError(t, l.SubmitBid(*bid), "queued once the auction before closed")
+	select {
+	case won := <-auctionWon:
+		require.Equal(t, *bid, won)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Test timed out waiting for auction win")
+	}
+
+	stale := auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(102), pk)
+	require.Eventually(t, func() bool { return l.SubmitBid(*stale) == nil }, time.Second, 5*time.Millisecond)
+	select {
+	case rejection := <-rejections:
+		require.Equal(t, *stale, rejection.Bid)
+		require.Equal(t, auction.RejectWrongBlock, rejection.Code, "no auction opened for the block it was queued for")
+	case <-time.After(2 * time.Second):
+		t.Fatal("Test timed out waiting for rejection")
+	}
+}
+
+func TestSealedBids(t *testing.T) {
+	sealingKey, _ := crypto.GenerateKey()
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	seal := func(amount int64, l1Block int64) sealed.EncryptedBid {
+		b, err := sealed.Seal(big.NewInt(amount), big.NewInt(l1Block), &sealingKey.PublicKey, pk)
+		require.NoError(t, err)
+		return *b
+	}
+	disabled := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, nil)
+	require.ErrorIs(t, disabled.SubmitSealedBid(seal(80, 100)), listener.ErrSealedBidsDisabled)
+	require.Nil(t, disabled.SealingKey())
+
+	l := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, nil)
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	l.SetSealedBids(sealed.NewKeyOpener(sealingKey))
+	require.Equal(t, &sealingKey.PublicKey, l.SealingKey())
+	require.EqualError(t, l.SubmitSealedBid(seal(80, 100)), "no auction in progress")
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+
+	for ev := range events {
+		if ev.Type == auction.EventAuctionOpened {
+			break
+		}
+	}
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk)))
+	require.NoError(t, l.SubmitSealedBid(seal(90, 100)))
+	sealedBid := seal(80, 100)
+	require.NoError(t, l.SubmitSealedBid(sealedBid), "relay's last sealed bid replaces its earlier one")
+	require.Error(t, l.SubmitSealedBid(seal(100, 101)))
+	forged := seal(100, 100)
+	forged.Ciphertext[len(forged.Ciphertext)-1] ^= 1
+	require.Error(t, l.SubmitSealedBid(forged))
+	require.Eventually(t, func() bool {
+		bid, found := l.GetCurrentBid()
+		return found && bid.AmountWei.Cmp(big.NewInt(50)) == 0
+	}, 500*time.Millisecond, 10*time.Millisecond, "sealed bids hidden while the auction is open")
+
+	select {
+	case won := <-auctionWon:
+		require.Equal(t, big.NewInt(80), won.AmountWei, "sealed bid opened at close")
+		require.True(t, won.Verify())
+	case <-time.After(2 * time.Second):
+		t.Fatal("Test timed out waiting for auction win")
+	}
+}
+
+func TestBidReceipts(t *testing.T) {
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	auctioneerKey, _ := crypto.GenerateKey()
+	disabled := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, nil)
+	_, err := disabled.SubmitBidWithReceipt(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk1))
+	require.ErrorIs(t, err, listener.ErrReceiptsDisabled)
+
+	l := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, nil)
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	l.SetReceiptKey(auctioneerKey)
+	recorder := &mockRecorder{}
+	l.SetRecorder(recorder)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+	for ev := range events {
+		if ev.Type == auction.EventAuctionOpened {
+			break
+		}
+	}
+
+	bid := *auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk1)
+	receipt, err := l.SubmitBidWithReceipt(bid)
+	require.NoError(t, err)
+	require.True(t, receipt.Verify())
+	require.True(t, receipt.Covers(bid))
+	require.Equal(t, crypto.PubkeyToAddress(auctioneerKey.PublicKey), receipt.Auctioneer)
+	_, err = l.SubmitBidWithReceipt(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(101), pk1))
+	require.ErrorIs(t, err, auction.ErrWrongBlock, "rejected bids aren't acknowledged")
+
+	// A tied bid that reached another replica first wins, whichever address is lower
+	tied := *auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk2)
+	require.NoError(t, l.SubmitBidAt(tied, time.Unix(0, receipt.ReceivedAt-1)))
+
+	select {
+	case won := <-auctionWon:
+		require.Equal(t, tied.Address, won.Address)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Test timed out waiting for auction win")
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Equal(t, []time.Time{time.Unix(0, receipt.ReceivedAt), time.Unix(0, receipt.ReceivedAt-1)}, recorder.received, "recorded with their arrival")
+}
+
+// Leads with the first bid submitted, and wins with it unless cancelled
+type mockAuction struct {
+	mu        sync.Mutex
+	submitted []auction.SignedBid
+	cancelled chan struct{}
+}
+
+func (m *mockAuction) SubmitBidAt(bid auction.SignedBid, receivedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.submitted = append(m.submitted, bid)
+	return nil
+}
+
+func (m *mockAuction) GetCurrentBid() auction.SignedBid {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.submitted) == 0 {
+		return auction.SignedBid{}
+	}
+	return m.submitted[0]
+}
+
+func (m *mockAuction) StartAsync(ctx context.Context, biddingPeriod time.Duration) <-chan auction.SignedBid {
+	result := make(chan auction.SignedBid)
+	go func() {
+		select {
+		case <-time.After(biddingPeriod):
+			result <- m.GetCurrentBid()
+		case <-m.cancelled:
+		case <-ctx.Done():
+		}
+	}()
+	return result
+}
+
+func (m *mockAuction) Cancel() {
+	close(m.cancelled)
+}
+
+func (m *mockAuction) Ranked() []auction.SignedBid {
+	if bid := m.GetCurrentBid(); bid.Address != (common.Address{}) {
+		return []auction.SignedBid{bid}
+	}
+	return nil
+}
+
+func (m *mockAuction) QueueDepth() int {
+	return 0
+}
+
+func TestAuctionFactory(t *testing.T) {
+	var mu sync.Mutex
+	created := make(map[uint64]*mockAuction)
+	l := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, func(l1Block uint64, params listener.AuctionParams) listener.Auction {
+		mu.Lock()
+		defer mu.Unlock()
+		created[l1Block] = &mockAuction{cancelled: make(chan struct{})}
+		return created[l1Block]
+	})
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetAuctionPeriod(300 * time.Millisecond)
+	events, sub := l.SubscribeEvents(16)
+	defer sub.Unsubscribe()
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+	for ev := range events {
+		if ev.Type == auction.EventAuctionOpened {
+			break
+		}
+	}
+
+	pk1, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	pk2, _ := crypto.HexToECDSA("1a51d1c8b33281390cc59928fde876d0577fce196cb66edcf944c4e6b875e980")
+	first := *auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk1)
+	require.NoError(t, l.SubmitBid(first))
+	require.NoError(t, l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk2)))
+	leader, found := l.GetCurrentBid()
+	require.True(t, found)
+	require.Equal(t, first, leader, "the mock's leader, not the highest bid")
+
+	select {
+	case won := <-auctionWon:
+		require.Equal(t, first, won)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Test timed out waiting for auction win")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, created[100].submitted, 2)
+	require.Equal(t, []auction.SignedBid{first}, l.Ranking(100))
+}
+
+// Wins with no ranking of runners-up
+type unrankedAuction struct{ *mockAuction }
+
+func (unrankedAuction) Ranked() []auction.SignedBid {
+	return nil
+}
+
+func TestFallBackUnranked(t *testing.T) {
+	l := listener.NewListener(slog.Default(), ethtest.NewClient(100), &mockRelayRegistry{}, func(l1Block uint64, params listener.AuctionParams) listener.Auction {
+		return unrankedAuction{&mockAuction{cancelled: make(chan struct{})}}
+	})
+	l.SetPollInterval(10 * time.Millisecond)
+	l.SetAuctionPeriod(100 * time.Millisecond)
+	_, auctionWon, err := l.Start(context.Background())
+	require.NoError(t, err)
+	defer l.Stop(context.Background())
+	pk, _ := crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+	require.Eventually(t, func() bool {
+		return l.SubmitBid(*auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk)) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	winner := <-auctionWon
+	_, err = l.FallBack(100, winner.Address, auction.StageAcceptance, "declined")
+	require.ErrorIs(t, err, listener.ErrNoFallbackBid)
+}
@@ -0,0 +1,153 @@
+package listener
+
+import (
+	"sort"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Relays whose bids are held for the next auction at most, further bids are rejected until it opens
+const maxPreAuctionBids = 4096
+
+// Bids for the next auction's block, each relay's best, held until it opens
+type preAuctionQueue struct {
+	l1Block uint64
+	bids    map[common.Address]queuedBid
+}
+
+type queuedBid struct {
+	bid        auction.SignedBid
+	receivedAt time.Time
+}
+
+// Bids for the next block's auction submitted up to window before it opens are validated and queued, and
+// submitted to the auction as it opens, if set before the listener starts. Without a slot schedule, when the next
+// auction opens isn't known, so bids are queued from when the auction before closes. Disabled if 0.
+func (l *Listener) SetPreAuctionWindow(window time.Duration) {
+	l.preAuctionWindow = window
+}
+
+// Block of the next auction, if bids for it are queued at now. Must be called with auctionMu held.
+func (l *Listener) nextAuction(now time.Time) (uint64, bool) {
+	if l.preAuctionWindow <= 0 || l.paused.Load() {
+		return 0, false
+	}
+	head := l.currentBlockNum.Load()
+	// The head's auction is yet to open if it's seen before the slot's open offset
+	awaiting := l.currentAuction == nil && head > l.lastAuctionBlock
+	next := head + 1
+	if awaiting {
+		next = head
+	}
+	if l.closeOffset <= 0 {
+		return next, l.currentAuction == nil
+	}
+	openAt, _ := l.auctionWindow(now)
+	if !awaiting && !now.Before(openAt) {
+		openAt = openAt.Add(l.slotTime)
+	}
+	return next, openAt.Sub(now) <= l.preAuctionWindow
+}
+
+// Queues a bid for the next auction, validated as far as it can be before the auction opens. Each relay's best
+// bid is kept. Must be called with auctionMu held.
+func (l *Listener) queueBid(bid auction.SignedBid, l1Block uint64, receivedAt time.Time) error {
+	if bid.Validate() != nil {
+		return l.reject(bid, auction.RejectInvalidSignature)
+	}
+	if !l.withinCap(bid) {
+		return l.reject(bid, auction.RejectOverBondCap)
+	}
+	if l.escrow != nil {
+		covered, err := l.escrow.Covers(bid.Address, bid.AmountWei)
+		if err != nil {
+			l.logger.Warn("failed to check escrow, accepting bid", "bid", bid, "error", err)
+		} else if !covered {
+			return l.reject(bid, auction.RejectUncovered)
+		}
+	}
+	l.preAuctionMu.Lock()
+	var stale *preAuctionQueue
+	if l.preAuction == nil || l.preAuction.l1Block != l1Block {
+		stale = l.preAuction
+		l.preAuction = &preAuctionQueue{l1Block: l1Block, bids: make(map[common.Address]queuedBid)}
+	}
+	queued, ok := l.preAuction.bids[bid.Address]
+	if !ok && len(l.preAuction.bids) >= maxPreAuctionBids {
+		l.preAuctionMu.Unlock()
+		return l.reject(bid, auction.RejectNoAuction)
+	}
+	if ok && bid.AmountWei.Cmp(queued.bid.AmountWei) <= 0 {
+		l.preAuctionMu.Unlock()
+		return l.reject(bid, auction.RejectOutbid)
+	}
+	l.preAuction.bids[bid.Address] = queuedBid{bid: bid, receivedAt: receivedAt}
+	l.preAuctionMu.Unlock()
+	if stale != nil {
+		// No auction opened for the block they were queued for, e.g. it was missed
+		l.rejectQueued(stale)
+	}
+	l.logger.Debug("bid queued for the next auction", "blockNumber", l1Block, "bidder", bid.Address, "amount", bid.AmountWei)
+	return nil
+}
+
+// Takes the bids queued for the next auction as it opens. Must be called with auctionMu held, so no bid is
+// queued for the auction once it's open.
+func (l *Listener) takeQueued() *preAuctionQueue {
+	l.preAuctionMu.Lock()
+	defer l.preAuctionMu.Unlock()
+	queue := l.preAuction
+	l.preAuction = nil
+	return queue
+}
+
+// Submits the bids queued for the auction opened for l1Block, in the order they were received. Bids queued for
+// another block, e.g. when the block they targeted was missed, are rejected.
+func (l *Listener) submitQueued(relayAuction Auction, l1Block uint64, queue *preAuctionQueue) {
+	if queue == nil {
+		return
+	}
+	if queue.l1Block != l1Block {
+		l.logger.Warn("auction opened for another block than bids were queued for", "blockNumber", l1Block,
+			"queuedFor", queue.l1Block, "bids", len(queue.bids))
+		l.rejectQueued(queue)
+		return
+	}
+	bids := queue.sorted()
+	for _, queued := range bids {
+		if !l.withinQuota(queued.bid) {
+			l.reject(queued.bid, auction.RejectOverQuota)
+			continue
+		}
+		if err := relayAuction.SubmitBidAt(queued.bid, queued.receivedAt); err != nil {
+			l.reject(queued.bid, auction.RejectNoAuction)
+			continue
+		}
+		l.auctionBids.Add(1)
+		if l.recorder != nil {
+			if err := l.recorder.SaveBid(queued.bid, queued.receivedAt); err != nil {
+				l.logger.Error("failed to record bid", "bid", queued.bid, "error", err)
+			}
+		}
+	}
+	l.logger.Info("submitted bids queued before the auction opened", "blockNumber", l1Block, "bids", len(bids))
+}
+
+func (l *Listener) rejectQueued(queue *preAuctionQueue) {
+	for _, queued := range queue.sorted() {
+		l.reject(queued.bid, auction.RejectWrongBlock)
+	}
+}
+
+// Bids in the order they were received
+func (q *preAuctionQueue) sorted() []queuedBid {
+	bids := make([]queuedBid, 0, len(q.bids))
+	for _, queued := range q.bids {
+		bids = append(bids, queued)
+	}
+	sort.Slice(bids, func(i, j int) bool { return bids[i].receivedAt.Before(bids[j].receivedAt) })
+	return bids
+}
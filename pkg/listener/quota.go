@@ -0,0 +1,54 @@
+package listener
+
+import (
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Caps the bids each relay may submit to an auction, e.g. by the relay's tier in the registry, so no relay can use
+// the open auction as a price oracle by probing it at high frequency
+type BidQuotas interface {
+	// Most distinct bids the relay may submit to one auction, unlimited if 0
+	BidQuota(relay common.Address) int
+}
+
+// Bids beyond the relay's quota for the auction are rejected on submission, if set before the listener starts.
+// Bids queued before the auction opened count against it too.
+func (l *Listener) SetBidQuotas(quotas BidQuotas) {
+	l.quotas = quotas
+}
+
+// Counts the bid against its relay's quota for the current auction, false if the quota is used up. Resubmitting
+// a bid already counted is free, the auction rejects it as a duplicate.
+func (l *Listener) withinQuota(bid auction.SignedBid) bool {
+	if l.quotas == nil {
+		return true
+	}
+	quota := l.quotas.BidQuota(bid.Address)
+	if quota <= 0 {
+		return true
+	}
+	l.quotaMu.Lock()
+	defer l.quotaMu.Unlock()
+	submitted, ok := l.quotaUsed[bid.Address]
+	if !ok {
+		submitted = make(map[string]struct{})
+		l.quotaUsed[bid.Address] = submitted
+	}
+	if _, ok := submitted[string(bid.Signature)]; ok {
+		return true
+	}
+	if len(submitted) >= quota {
+		return false
+	}
+	submitted[string(bid.Signature)] = struct{}{}
+	return true
+}
+
+// Starts counting bids against quotas afresh as an auction opens. Must be called with auctionMu held.
+func (l *Listener) resetQuotas() {
+	l.quotaMu.Lock()
+	defer l.quotaMu.Unlock()
+	l.quotaUsed = make(map[common.Address]map[string]struct{})
+}
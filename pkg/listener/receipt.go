@@ -0,0 +1,43 @@
+package listener
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+)
+
+var ErrReceiptsDisabled = errors.New("bid receipts not enabled")
+
+// Bids submitted with SubmitBidWithReceipt are acknowledged with a receipt signed by key, e.g. the auctioneer's
+// signing key, if set before the listener starts
+func (l *Listener) SetReceiptKey(key *ecdsa.PrivateKey) {
+	l.receiptKey = key
+}
+
+// Clock bids are stamped with as they're submitted
+func (l *Listener) Arrivals() *auction.ArrivalClock {
+	return l.arrivals
+}
+
+// To satisfy bid submissions from relays wanting proof of when their bid arrived. The receipt is only returned
+// if the bid is accepted, and so recorded with its arrival.
+func (l *Listener) SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error) {
+	if l.receiptKey == nil {
+		return nil, ErrReceiptsDisabled
+	}
+	receivedAt := l.arrivals.Now()
+	if err := l.SubmitBidAt(bid, receivedAt); err != nil {
+		return nil, err
+	}
+	return l.SignReceipt(bid, receivedAt)
+}
+
+// Acknowledges a bid that reached the auctioneer at receivedAt, e.g. for a wrapper submitting it with SubmitBidAt
+func (l *Listener) SignReceipt(bid auction.SignedBid, receivedAt time.Time) (*auction.Receipt, error) {
+	if l.receiptKey == nil {
+		return nil, ErrReceiptsDisabled
+	}
+	return auction.CreateSignedReceipt(bid, receivedAt, l.receiptKey)
+}
@@ -0,0 +1,144 @@
+package listener
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// Relays whose sealed bids are held per auction at most
+	maxSealedBids = 4096
+	// Opening sealed bids delays the winner by this at most, well within the auction's deadline
+	revealTimeout = 500 * time.Millisecond
+)
+
+var ErrSealedBidsDisabled = errors.New("sealed bids not enabled")
+
+// Decrypts sealed bids as their auction closes, e.g. *sealed.KeyOpener or *sealed.Committee
+type SealedBidOpener interface {
+	SealingKey() *ecdsa.PublicKey
+	Open(ctx context.Context, l1Block uint64, bids []sealed.EncryptedBid) ([]auction.SignedBid, error)
+}
+
+// Bids sealed to the opener's key are accepted with SubmitSealedBid, and opened and evaluated as their auction
+// closes, if set before the listener starts
+func (l *Listener) SetSealedBids(opener SealedBidOpener) {
+	l.opener = opener
+}
+
+// Key bids are sealed to, nil unless sealed bids are enabled
+func (l *Listener) SealingKey() *ecdsa.PublicKey {
+	if l.opener == nil {
+		return nil
+	}
+	return l.opener.SealingKey()
+}
+
+// To satisfy sealed bid submissions from relays. Each relay's last sealed bid for the current auction is opened
+// as it closes, as it's not known which is best until then.
+func (l *Listener) SubmitSealedBid(bid sealed.EncryptedBid) error {
+	if l.opener == nil {
+		return ErrSealedBidsDisabled
+	}
+	if err := bid.Validate(); err != nil {
+		return auction.ErrInvalidSignature
+	}
+	if !l.relayRegistry.IsRegisteredOnSettlementLayer(bid.Address) {
+		return auction.ErrUnregisteredRelay
+	}
+	l.auctionMu.RLock()
+	defer l.auctionMu.RUnlock()
+	if l.currentAuction == nil {
+		return auction.ErrNoActiveAuction
+	}
+	if !bid.L1Block.IsUint64() || bid.L1Block.Uint64() != l.currentAuctionBlock {
+		return auction.ErrWrongBlock
+	}
+	l.sealedMu.Lock()
+	defer l.sealedMu.Unlock()
+	// Nil once the auction closed and its sealed bids were taken to open
+	if l.sealedBids == nil {
+		return auction.ErrNoActiveAuction
+	}
+	if _, ok := l.sealedBids[bid.Address]; !ok && len(l.sealedBids) >= maxSealedBids {
+		return auction.ErrNoActiveAuction
+	}
+	l.sealedBids[bid.Address] = sealedBid{bid: bid, receivedAt: l.arrivals.Now()}
+	l.logger.Debug("sealed bid received", "blockNumber", l.currentAuctionBlock, "bidder", bid.Address)
+	return nil
+}
+
+// Clears sealed bids as an auction opens. Must be called with auctionMu held.
+func (l *Listener) resetSealed() {
+	l.sealedMu.Lock()
+	defer l.sealedMu.Unlock()
+	l.sealedBids = make(map[common.Address]sealedBid)
+}
+
+// Sealed bid with the time it reached the auctioneer, which it keeps once opened
+type sealedBid struct {
+	bid        sealed.EncryptedBid
+	receivedAt time.Time
+}
+
+// Opens the auction's sealed bids as it closes, satisfying auction.Revealer
+type sealedRevealer struct {
+	l       *Listener
+	l1Block uint64
+}
+
+func (r *sealedRevealer) Reveal(ctx context.Context) []auction.ReceivedBid {
+	l := r.l
+	l.sealedMu.Lock()
+	bids := make([]sealed.EncryptedBid, 0, len(l.sealedBids))
+	for _, held := range l.sealedBids {
+		bids = append(bids, held.bid)
+	}
+	arrivals := l.sealedBids
+	l.sealedBids = nil
+	l.sealedMu.Unlock()
+	if len(bids) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, revealTimeout)
+	defer cancel()
+	opened, err := l.opener.Open(ctx, r.l1Block, bids)
+	if err != nil {
+		l.logger.Warn("failed to open sealed bids", "blockNumber", r.l1Block, "bids", len(bids), "opened", len(opened), "error", err)
+	}
+	revealed := make([]auction.ReceivedBid, 0, len(opened))
+	for _, bid := range opened {
+		// Opened bids are from their envelope's signer
+		receivedAt := arrivals[bid.Address].receivedAt
+		if !l.withinCap(bid) {
+			l.reject(bid, auction.RejectOverBondCap)
+			continue
+		}
+		if l.escrow != nil {
+			covered, err := l.escrow.Covers(bid.Address, bid.AmountWei)
+			if err != nil {
+				l.logger.Warn("failed to check escrow, accepting bid", "bid", bid, "error", err)
+			} else if !covered {
+				l.reject(bid, auction.RejectUncovered)
+				continue
+			}
+		}
+		revealed = append(revealed, auction.ReceivedBid{SignedBid: bid, ReceivedAt: receivedAt})
+		l.auctionBids.Add(1)
+		if l.recorder != nil {
+			if err := l.recorder.SaveBid(bid, receivedAt); err != nil {
+				l.logger.Error("failed to record bid", "bid", bid, "error", err)
+			}
+		}
+	}
+	l.logger.Info("opened sealed bids", "blockNumber", r.l1Block, "bids", len(bids), "opened", len(opened))
+	return revealed
+}
@@ -0,0 +1,102 @@
+package localnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	ChainAnvil = "anvil"
+	ChainGeth  = "geth"
+)
+
+// Chain ID of geth --dev, anvil's is the local network preset's
+const GethDevChainID = 1337
+
+// Dev chain process, anvil or geth --dev, mining a block every blockTime. Stopped when ctx is done.
+func StartChain(ctx context.Context, kind string, port int, blockTime time.Duration, dir string) (*exec.Cmd, error) {
+	seconds := strconv.Itoa(max(1, int(blockTime.Seconds())))
+	var cmd *exec.Cmd
+	switch kind {
+	case ChainAnvil:
+		cmd = exec.CommandContext(ctx, "anvil", "--port", strconv.Itoa(port), "--block-time", seconds, "--silent")
+	case ChainGeth:
+		cmd = exec.CommandContext(ctx, "geth", "--dev", "--dev.period", seconds,
+			"--datadir", filepath.Join(dir, "geth"), "--http", "--http.addr", "127.0.0.1", "--http.port", strconv.Itoa(port),
+			"--http.api", "eth,net,web3", "--verbosity", "2")
+	default:
+		return nil, fmt.Errorf("unknown chain %q, expected anvil or geth", kind)
+	}
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = 10 * time.Second
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s, is it installed? %w", kind, err)
+	}
+	return cmd, nil
+}
+
+// Polls eth_blockNumber until the chain answers
+func WaitForRPC(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		client, err := rpc.DialContext(ctx, url)
+		if err == nil {
+			var block hexutil.Uint64
+			err = client.CallContext(ctx, &block, "eth_blockNumber")
+			client.Close()
+			if err == nil {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not reachable: %w", url, err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// Sets each address's balance to wei on anvil, or transfers wei from the dev account on geth
+func Fund(ctx context.Context, kind string, url string, addresses []common.Address, wei *big.Int) error {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var from common.Address
+	if kind == ChainGeth {
+		var accounts []common.Address
+		if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+			return err
+		}
+		if len(accounts) == 0 {
+			return fmt.Errorf("geth has no dev account")
+		}
+		from = accounts[0]
+	}
+	for _, address := range addresses {
+		switch kind {
+		case ChainAnvil:
+			err = client.CallContext(ctx, nil, "anvil_setBalance", address, (*hexutil.Big)(wei))
+		case ChainGeth:
+			tx := map[string]any{"from": from, "to": address, "value": (*hexutil.Big)(wei)}
+			err = client.CallContext(ctx, nil, "eth_sendTransaction", tx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fund %s: %w", address.Hex(), err)
+		}
+	}
+	return nil
+}
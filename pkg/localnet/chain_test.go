@@ -0,0 +1,84 @@
+package localnet_test
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/localnet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+var devAccount = common.Address{0xde, 0x01}
+
+// Fake dev chain recording balance changes
+type fakeChain struct {
+	balances map[common.Address]*big.Int
+}
+
+type anvilAPI struct{ chain *fakeChain }
+
+func (a anvilAPI) SetBalance(address common.Address, wei *hexutil.Big) error {
+	a.chain.balances[address] = wei.ToInt()
+	return nil
+}
+
+type transaction struct {
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Value *hexutil.Big   `json:"value"`
+}
+
+type ethAPI struct{ chain *fakeChain }
+
+func (e ethAPI) BlockNumber() hexutil.Uint64 { return 1 }
+
+func (e ethAPI) Accounts() []common.Address { return []common.Address{devAccount} }
+
+func (e ethAPI) SendTransaction(tx transaction) (common.Hash, error) {
+	if tx.From != devAccount {
+		return common.Hash{}, rpc.ErrNoResult
+	}
+	e.chain.balances[tx.To] = tx.Value.ToInt()
+	return common.Hash{0x01}, nil
+}
+
+func newFakeChain(t *testing.T) (*fakeChain, string) {
+	chain := &fakeChain{balances: map[common.Address]*big.Int{}}
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("anvil", anvilAPI{chain}))
+	require.NoError(t, server.RegisterName("eth", ethAPI{chain}))
+	http := httptest.NewServer(server)
+	t.Cleanup(http.Close)
+	t.Cleanup(server.Stop)
+	return chain, http.URL
+}
+
+func TestWaitForRPC(t *testing.T) {
+	_, url := newFakeChain(t)
+	require.NoError(t, localnet.WaitForRPC(context.Background(), url, time.Second))
+	require.Error(t, localnet.WaitForRPC(context.Background(), "http://127.0.0.1:1", 300*time.Millisecond))
+}
+
+func TestFund(t *testing.T) {
+	addresses := []common.Address{{0x01}, {0x02}}
+	wei := big.NewInt(100)
+	for _, kind := range []string{localnet.ChainAnvil, localnet.ChainGeth} {
+		t.Run(kind, func(t *testing.T) {
+			chain, url := newFakeChain(t)
+			require.NoError(t, localnet.Fund(context.Background(), kind, url, addresses, wei))
+			require.Equal(t, map[common.Address]*big.Int{addresses[0]: wei, addresses[1]: wei}, chain.balances)
+		})
+	}
+}
+
+func TestStartChainUnknown(t *testing.T) {
+	_, err := localnet.StartChain(context.Background(), "hardhat", 8546, time.Second, t.TempDir())
+	require.ErrorContains(t, err, "unknown chain")
+}
@@ -0,0 +1,52 @@
+package localnet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Builds the auctioneer into dir, so it must run from within the module
+func BuildAuctioneer(ctx context.Context, dir string) (string, error) {
+	bin := filepath.Join(dir, "auctioneer")
+	build := exec.CommandContext(ctx, "go", "build", "-o", bin, "blob-preconfs/cmd/auctioneer")
+	build.Stdout, build.Stderr = os.Stderr, os.Stderr
+	if err := build.Run(); err != nil {
+		return "", fmt.Errorf("failed to build the auctioneer, run from within the repository: %w", err)
+	}
+	return bin, nil
+}
+
+// Auctioneer process running with the config at path, stopped with SIGINT when ctx is done
+func StartAuctioneer(ctx context.Context, bin string, path string) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, bin, "run", "--config", path)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = time.Minute
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start the auctioneer: %w", err)
+	}
+	return cmd, nil
+}
+
+// Waits until addr accepts connections
+func WaitForListener(ctx context.Context, addr string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not reachable: %w", addr, err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
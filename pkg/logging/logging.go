@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+var ErrInvalidConfig = errors.New("invalid logging config")
+
+type Config struct {
+	// debug, info, warn or error, defaults to info
+	Level string
+	// text or json, defaults to text
+	Format string
+	// Module name to level, overriding Level for loggers from Module, e.g. {"listener": "debug"}
+	Modules map[string]string
+	// Logs are appended to this file if set, otherwise written to stderr
+	File string
+	// Rotation of the log file. The file is rotated once it exceeds MaxSizeMB (100 if 0), and rotated
+	// files beyond MaxBackups or older than MaxAgeDays are removed (0 keeps them).
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+	Sampling   SamplingConfig
+}
+
+// Limits high-frequency debug records, e.g. "no new block" logged on every poll. Within each Interval,
+// the First records with a given message are logged, then every Thereafter-th. Disabled if Interval is 0.
+type SamplingConfig struct {
+	Interval time.Duration
+	First    int
+	// 0 drops every record beyond First
+	Thereafter int
+}
+
+// Root and per-module loggers built from a Config
+type Logging struct {
+	handler slog.Handler
+	closer  io.Closer
+
+	mu sync.Mutex // Protects the fields below
+	// Level of the root logger and modules without their own
+	level *slog.LevelVar
+	// Module levels, shared by the module's loggers so SetLevels applies to loggers already handed out
+	modules map[string]*slog.LevelVar
+	// Modules configured with their own level
+	overrides map[string]slog.Level
+}
+
+func New(config Config) (*Logging, error) {
+	level, overrides, err := parseLevels(config.Level, config.Modules)
+	if err != nil {
+		return nil, err
+	}
+	if config.Sampling.Interval < 0 || config.Sampling.First < 0 || config.Sampling.Thereafter < 0 {
+		return nil, fmt.Errorf("%w: negative sampling parameters", ErrInvalidConfig)
+	}
+
+	var w io.Writer = os.Stderr
+	var closer io.Closer
+	if config.File != "" {
+		file := &lumberjack.Logger{
+			Filename:   config.File,
+			MaxSize:    config.MaxSizeMB,
+			MaxBackups: config.MaxBackups,
+			MaxAge:     config.MaxAgeDays,
+			Compress:   config.Compress,
+		}
+		w, closer = file, file
+	}
+	// Levels are enforced per module by levelHandler, so the output handler passes everything
+	opts := &slog.HandlerOptions{Level: slog.Level(-8)}
+	var handler slog.Handler
+	switch config.Format {
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidConfig, config.Format)
+	}
+	if config.Sampling.Interval > 0 {
+		handler = newSamplingHandler(handler, config.Sampling)
+	}
+	l := &Logging{handler: handler, closer: closer, level: new(slog.LevelVar), modules: make(map[string]*slog.LevelVar)}
+	l.setLevels(level, overrides)
+	return l, nil
+}
+
+// Logs below Level are dropped
+func (l *Logging) Logger() *slog.Logger {
+	return slog.New(&levelHandler{inner: l.handler, level: l.level})
+}
+
+// Logs carry module=name, and are dropped below the module's level if configured, otherwise Level
+func (l *Logging) Module(name string) *slog.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	level, ok := l.modules[name]
+	if !ok {
+		level = new(slog.LevelVar)
+		level.Set(l.level.Level())
+		if override, ok := l.overrides[name]; ok {
+			level.Set(override)
+		}
+		l.modules[name] = level
+	}
+	return slog.New(&levelHandler{inner: l.handler, level: level}).With("module", name)
+}
+
+// Replaces Level and Modules, including for loggers already returned, e.g. on config reload.
+// Levels are unchanged if any is invalid.
+func (l *Logging) SetLevels(level string, modules map[string]string) error {
+	root, overrides, err := parseLevels(level, modules)
+	if err != nil {
+		return err
+	}
+	l.setLevels(root, overrides)
+	return nil
+}
+
+func (l *Logging) setLevels(root slog.Level, overrides map[string]slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level.Set(root)
+	l.overrides = overrides
+	for name, level := range l.modules {
+		if override, ok := overrides[name]; ok {
+			level.Set(override)
+		} else {
+			level.Set(root)
+		}
+	}
+}
+
+// Closes the log file, if any
+func (l *Logging) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+func parseLevels(level string, modules map[string]string) (slog.Level, map[string]slog.Level, error) {
+	root, err := parseLevel(level)
+	if err != nil {
+		return 0, nil, err
+	}
+	overrides := make(map[string]slog.Level, len(modules))
+	for module, s := range modules {
+		if overrides[module], err = parseLevel(s); err != nil {
+			return 0, nil, fmt.Errorf("module %s: %w", module, err)
+		}
+	}
+	return root, overrides, nil
+}
+
+func parseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return level, nil
+}
+
+type levelHandler struct {
+	inner slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.inner.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{inner: h.inner.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{inner: h.inner.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,87 @@
+package logging_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"blob-preconfs/pkg/logging"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newLogging(t *testing.T, config logging.Config) (*logging.Logging, func() []map[string]any) {
+	config.File = filepath.Join(t.TempDir(), "auctioneer.log")
+	config.Format = "json"
+	l, err := logging.New(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	return l, func() []map[string]any {
+		file, err := os.Open(config.File)
+		require.NoError(t, err)
+		defer file.Close()
+		var records []map[string]any
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		return records
+	}
+}
+
+func TestModuleLevels(t *testing.T) {
+	l, read := newLogging(t, logging.Config{Level: "warn", Modules: map[string]string{"listener": "debug"}})
+	l.Logger().Info("dropped")
+	l.Logger().Warn("root")
+	l.Module("listener").Debug("listener debug", "blockNumber", 100)
+	l.Module("auction").Info("dropped")
+	l.Module("auction").Error("auction error")
+
+	records := read()
+	require.Len(t, records, 3)
+	require.Equal(t, "root", records[0]["msg"])
+	require.NotContains(t, records[0], "module")
+	require.Equal(t, "listener debug", records[1]["msg"])
+	require.Equal(t, "listener", records[1]["module"])
+	require.Equal(t, float64(100), records[1]["blockNumber"])
+	require.Equal(t, "auction", records[2]["module"])
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, config := range []logging.Config{
+		{Level: "verbose"},
+		{Format: "xml"},
+		{Modules: map[string]string{"listener": "loud"}},
+		{Sampling: logging.SamplingConfig{First: -1}},
+	} {
+		_, err := logging.New(config)
+		require.ErrorIs(t, err, logging.ErrInvalidConfig, "%+v", config)
+	}
+	l, err := logging.New(logging.Config{})
+	require.NoError(t, err, "defaults to info text logs on stderr")
+	require.NoError(t, l.Close())
+}
+
+func TestSetLevels(t *testing.T) {
+	l, read := newLogging(t, logging.Config{Level: "warn", Modules: map[string]string{"listener": "debug"}})
+	listener, auction := l.Module("listener"), l.Module("auction")
+	require.ErrorIs(t, l.SetLevels("verbose", nil), logging.ErrInvalidConfig)
+	listener.Debug("listener debug")
+
+	require.NoError(t, l.SetLevels("info", map[string]string{"auction": "debug"}))
+	listener.Debug("dropped")
+	listener.Info("listener info")
+	auction.Debug("auction debug")
+	l.Module("rest").Debug("dropped")
+	l.Logger().Info("root info")
+
+	var messages []string
+	for _, record := range read() {
+		messages = append(messages, record["msg"].(string))
+	}
+	require.Equal(t, []string{"listener debug", "listener info", "auction debug", "root info"}, messages, "loggers already returned are updated")
+}
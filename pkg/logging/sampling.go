@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Counts debug records per message, shared by handlers derived with WithAttrs and WithGroup
+type sampler struct {
+	config SamplingConfig
+
+	mu          sync.Mutex // Protects access to fields below
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func (s *sampler) sample(message string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.windowStart) >= s.config.Interval {
+		s.windowStart = now
+		clear(s.counts)
+	}
+	s.counts[message]++
+	n := s.counts[message]
+	if n <= s.config.First {
+		return true
+	}
+	return s.config.Thereafter > 0 && (n-s.config.First)%s.config.Thereafter == 0
+}
+
+// Samples records below info level, passing others through
+type samplingHandler struct {
+	inner   slog.Handler
+	sampler *sampler
+}
+
+func newSamplingHandler(inner slog.Handler, config SamplingConfig) *samplingHandler {
+	return &samplingHandler{inner: inner, sampler: &sampler{config: config, counts: make(map[string]int)}}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelInfo && !h.sampler.sample(r.Message, r.Time) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{inner: h.inner.WithGroup(name), sampler: h.sampler}
+}
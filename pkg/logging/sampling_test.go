@@ -0,0 +1,38 @@
+package logging_test
+
+import (
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/logging"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampling(t *testing.T) {
+	l, read := newLogging(t, logging.Config{
+		Level:    "debug",
+		Sampling: logging.SamplingConfig{Interval: time.Hour, First: 2, Thereafter: 3},
+	})
+	logger := l.Module("listener")
+	for i := 0; i < 10; i++ {
+		logger.Debug("no new block. Continuing...", "i", i)
+		logger.With("poll", i).Debug("polling")
+		l.Logger().Info("new block", "i", i)
+	}
+
+	var sampled, polling, info []float64
+	for _, record := range read() {
+		switch record["msg"] {
+		case "no new block. Continuing...":
+			sampled = append(sampled, record["i"].(float64))
+		case "polling":
+			polling = append(polling, record["poll"].(float64))
+		case "new block":
+			info = append(info, record["i"].(float64))
+		}
+	}
+	require.Equal(t, []float64{0, 1, 4, 7}, sampled, "first 2, then every 3rd")
+	require.Equal(t, []float64{0, 1, 4, 7}, polling, "derived loggers share counts")
+	require.Len(t, info, 10, "info and above aren't sampled")
+}
@@ -0,0 +1,256 @@
+package market
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Statistics are computed from at most this many records, so a query over all of history can't stall the
+// auctioneer
+const maxRecords = 200_000
+
+var ErrTooManyRecords = errors.New("too many records, narrow the block range")
+
+// Satisfied by store.Store implementations
+type History interface {
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+	ListBids(filter store.BidFilter, page store.Page) ([]store.BidRecord, string, error)
+	ListCommitments(filter store.CommitmentFilter, page store.Page) ([]store.CommitmentRecord, string, error)
+}
+
+// Clearing prices of the auctions closed on a UTC day
+type DailyPrice struct {
+	// YYYY-MM-DD
+	Day      string `json:"day"`
+	Auctions int    `json:"auctions"`
+	Won      int    `json:"won"`
+	// Average winning bid of won auctions, nil if none was won
+	AverageClearingPriceWei *big.Int `json:"averageClearingPriceWei"`
+	MinClearingPriceWei     *big.Int `json:"minClearingPriceWei"`
+	MaxClearingPriceWei     *big.Int `json:"maxClearingPriceWei"`
+}
+
+// Bids received per auction
+type BidActivity struct {
+	Auctions int     `json:"auctions"`
+	Bids     int     `json:"bids"`
+	Average  float64 `json:"average"`
+	Median   int     `json:"median"`
+	Max      int     `json:"max"`
+}
+
+// Auctions a relay won
+type RelayWins struct {
+	Relay common.Address `json:"relay"`
+	Wins  int            `json:"wins"`
+	// Of won auctions in the range
+	Share    float64  `json:"share"`
+	TotalWei *big.Int `json:"totalWei"`
+}
+
+// Outcomes of commitments targeting blocks in the range
+type PreconfStats struct {
+	Active    int `json:"active"`
+	Fulfilled int `json:"fulfilled"`
+	Missed    int `json:"missed"`
+	Renewed   int `json:"renewed"`
+	Escalated int `json:"escalated"`
+	// Fulfilled commitments over those fulfilled or missed, nil if none concluded. Renewed and escalated
+	// commitments were missed for reasons outside the relay's control, so don't count.
+	HonorRate *float64 `json:"honorRate"`
+}
+
+// Aggregates history for researchers and relays doing price discovery. Auctions cancelled and rerun, or that
+// fell back to another bid, count once with their latest result.
+type Stats struct {
+	history History
+}
+
+func NewStats(history History) *Stats {
+	return &Stats{history: history}
+}
+
+// Ordered by day
+func (s *Stats) DailyPrices(blocks store.BlockRange) ([]DailyPrice, error) {
+	auctions, err := s.auctions(blocks)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string]*DailyPrice)
+	totals := make(map[string]*big.Int)
+	for _, a := range auctions {
+		day := a.ClosedAt.UTC().Format(time.DateOnly)
+		d, ok := byDay[day]
+		if !ok {
+			d = &DailyPrice{Day: day}
+			byDay[day] = d
+			totals[day] = new(big.Int)
+		}
+		d.Auctions++
+		if a.Winner == nil {
+			continue
+		}
+		d.Won++
+		price := a.Winner.AmountWei
+		totals[day].Add(totals[day], price)
+		if d.MinClearingPriceWei == nil || price.Cmp(d.MinClearingPriceWei) < 0 {
+			d.MinClearingPriceWei = price
+		}
+		if d.MaxClearingPriceWei == nil || price.Cmp(d.MaxClearingPriceWei) > 0 {
+			d.MaxClearingPriceWei = price
+		}
+	}
+	days := make([]DailyPrice, 0, len(byDay))
+	for day, d := range byDay {
+		if d.Won > 0 {
+			d.AverageClearingPriceWei = new(big.Int).Div(totals[day], big.NewInt(int64(d.Won)))
+		}
+		days = append(days, *d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+	return days, nil
+}
+
+func (s *Stats) BidActivity(blocks store.BlockRange) (BidActivity, error) {
+	auctions, err := s.auctions(blocks)
+	if err != nil {
+		return BidActivity{}, err
+	}
+	perBlock := make(map[uint64]int, len(auctions))
+	for _, a := range auctions {
+		perBlock[a.L1Block] = 0
+	}
+	err = scan(func(page store.Page) ([]store.BidRecord, string, error) {
+		return s.history.ListBids(store.BidFilter{Blocks: blocks}, page)
+	}, func(r store.BidRecord) {
+		// Bids for blocks without an auction, e.g. skipped while paused, aren't counted
+		if n, ok := perBlock[r.Bid.L1Block.Uint64()]; ok {
+			perBlock[r.Bid.L1Block.Uint64()] = n + 1
+		}
+	})
+	if err != nil {
+		return BidActivity{}, err
+	}
+	activity := BidActivity{Auctions: len(perBlock)}
+	if activity.Auctions == 0 {
+		return activity, nil
+	}
+	counts := make([]int, 0, len(perBlock))
+	for _, n := range perBlock {
+		counts = append(counts, n)
+		activity.Bids += n
+	}
+	sort.Ints(counts)
+	activity.Average = float64(activity.Bids) / float64(activity.Auctions)
+	activity.Median = counts[len(counts)/2]
+	activity.Max = counts[len(counts)-1]
+	return activity, nil
+}
+
+// Ordered by wins, most first
+func (s *Stats) Winners(blocks store.BlockRange) ([]RelayWins, error) {
+	auctions, err := s.auctions(blocks)
+	if err != nil {
+		return nil, err
+	}
+	byRelay := make(map[common.Address]*RelayWins)
+	won := 0
+	for _, a := range auctions {
+		if a.Winner == nil {
+			continue
+		}
+		won++
+		w, ok := byRelay[a.Winner.Address]
+		if !ok {
+			w = &RelayWins{Relay: a.Winner.Address, TotalWei: new(big.Int)}
+			byRelay[a.Winner.Address] = w
+		}
+		w.Wins++
+		w.TotalWei.Add(w.TotalWei, a.Winner.AmountWei)
+	}
+	winners := make([]RelayWins, 0, len(byRelay))
+	for _, w := range byRelay {
+		w.Share = float64(w.Wins) / float64(won)
+		winners = append(winners, *w)
+	}
+	sort.Slice(winners, func(i, j int) bool {
+		if winners[i].Wins != winners[j].Wins {
+			return winners[i].Wins > winners[j].Wins
+		}
+		return winners[i].Relay.Cmp(winners[j].Relay) < 0
+	})
+	return winners, nil
+}
+
+func (s *Stats) Preconfs(blocks store.BlockRange) (PreconfStats, error) {
+	var stats PreconfStats
+	err := scan(func(page store.Page) ([]store.CommitmentRecord, string, error) {
+		return s.history.ListCommitments(store.CommitmentFilter{Blocks: blocks}, page)
+	}, func(r store.CommitmentRecord) {
+		switch r.State {
+		case commitment.StateActive:
+			stats.Active++
+		case commitment.StateFulfilled:
+			stats.Fulfilled++
+		case commitment.StateMissed:
+			stats.Missed++
+		case commitment.StateRenewed:
+			stats.Renewed++
+		case commitment.StateEscalated:
+			stats.Escalated++
+		}
+	})
+	if err != nil {
+		return PreconfStats{}, err
+	}
+	if concluded := stats.Fulfilled + stats.Missed; concluded > 0 {
+		rate := float64(stats.Fulfilled) / float64(concluded)
+		stats.HonorRate = &rate
+	}
+	return stats, nil
+}
+
+// Latest result of each auction in the range, by block
+func (s *Stats) auctions(blocks store.BlockRange) ([]store.AuctionRecord, error) {
+	var auctions []store.AuctionRecord
+	err := scan(func(page store.Page) ([]store.AuctionRecord, string, error) {
+		return s.history.ListAuctions(store.AuctionFilter{Blocks: blocks}, page)
+	}, func(r store.AuctionRecord) {
+		// Results are ordered by block then insertion, so a block's later results replace earlier ones
+		if n := len(auctions); n > 0 && auctions[n-1].L1Block == r.L1Block {
+			auctions[n-1] = r
+			return
+		}
+		auctions = append(auctions, r)
+	})
+	return auctions, err
+}
+
+// Pages through records, failing once more than maxRecords were read
+func scan[T any](list func(page store.Page) ([]T, string, error), visit func(T)) error {
+	page := store.Page{Limit: store.MaxPageLimit}
+	read := 0
+	for {
+		records, next, err := list(page)
+		if err != nil {
+			return err
+		}
+		if read += len(records); read > maxRecords {
+			return ErrTooManyRecords
+		}
+		for _, r := range records {
+			visit(r)
+		}
+		if next == "" {
+			return nil
+		}
+		page.Cursor = next
+	}
+}
@@ -0,0 +1,79 @@
+package market_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/market"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	day1 := time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	relay1 := func(amount int64, block int64) *auction.SignedBid {
+		return auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(block), pk1)
+	}
+	relay2 := func(amount int64, block int64) *auction.SignedBid {
+		return auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(block), pk2)
+	}
+	won := func(block uint64, winner *auction.SignedBid, closedAt time.Time) {
+		require.NoError(t, history.SaveAuctionResult(block, winner, closedAt))
+	}
+
+	// Block 100 falls back from relay 1 to relay 2, so counts once with relay 2's bid
+	for _, b := range []*auction.SignedBid{relay1(30, 100), relay2(20, 100), relay1(10, 101), relay2(40, 102), relay1(5, 102), relay1(7, 102)} {
+		require.NoError(t, history.SaveBid(*b, day1))
+	}
+	won(100, relay1(30, 100), day1)
+	won(100, relay2(20, 100), day1)
+	won(101, relay1(10, 101), day1)
+	won(102, relay2(40, 102), day2)
+	won(103, nil, day2)
+
+	stats := market.NewStats(history)
+	days, err := stats.DailyPrices(store.BlockRange{})
+	require.NoError(t, err)
+	require.Equal(t, []market.DailyPrice{
+		{Day: "2026-10-13", Auctions: 2, Won: 2, AverageClearingPriceWei: big.NewInt(15), MinClearingPriceWei: big.NewInt(10), MaxClearingPriceWei: big.NewInt(20)},
+		{Day: "2026-10-14", Auctions: 2, Won: 1, AverageClearingPriceWei: big.NewInt(40), MinClearingPriceWei: big.NewInt(40), MaxClearingPriceWei: big.NewInt(40)},
+	}, days)
+
+	activity, err := stats.BidActivity(store.BlockRange{})
+	require.NoError(t, err)
+	require.Equal(t, market.BidActivity{Auctions: 4, Bids: 6, Average: 1.5, Median: 2, Max: 3}, activity)
+	activity, err = stats.BidActivity(store.BlockRange{From: 101, To: 101})
+	require.NoError(t, err)
+	require.Equal(t, market.BidActivity{Auctions: 1, Bids: 1, Average: 1, Median: 1, Max: 1}, activity)
+
+	winners, err := stats.Winners(store.BlockRange{})
+	require.NoError(t, err)
+	require.Len(t, winners, 2)
+	require.Equal(t, market.RelayWins{Relay: crypto.PubkeyToAddress(pk2.PublicKey), Wins: 2, Share: 2.0 / 3, TotalWei: big.NewInt(60)}, winners[0])
+	require.Equal(t, 1, winners[1].Wins)
+
+	for i, state := range []commitment.State{commitment.StateFulfilled, commitment.StateFulfilled, commitment.StateFulfilled, commitment.StateMissed, commitment.StateRenewed, commitment.StateActive} {
+		c, err := commitment.CreateSignedCommitment(commitment.Commitment{TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(int64(100 + i)), FeeWei: big.NewInt(1)}, pk1)
+		require.NoError(t, err)
+		require.NoError(t, history.SaveCommitment(*c, state))
+	}
+	preconfs, err := stats.Preconfs(store.BlockRange{})
+	require.NoError(t, err)
+	require.Equal(t, 3, preconfs.Fulfilled)
+	require.Equal(t, 1, preconfs.Missed)
+	require.Equal(t, 1, preconfs.Renewed)
+	require.Equal(t, 1, preconfs.Active)
+	require.Equal(t, 0.75, *preconfs.HonorRate, "renewals don't count against the relay")
+	preconfs, err = stats.Preconfs(store.BlockRange{From: 200})
+	require.NoError(t, err)
+	require.Nil(t, preconfs.HonorRate)
+}
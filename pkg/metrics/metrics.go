@@ -0,0 +1,236 @@
+package metrics
+
+import (
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "auctioneer"
+
+// From 50µs to ~3.3s, covering bids handled in the last moments of the 5 second auction window
+var latencyBuckets = prometheus.ExponentialBuckets(0.00005, 2, 17)
+
+// Prometheus collectors for the auctioneer's subsystems, on a registry of their own so tests
+// and multiple instances don't collide on the global one
+type Metrics struct {
+	registry *prometheus.Registry
+
+	blocks          prometheus.Counter
+	lastBlock       prometheus.Gauge
+	auctions        *prometheus.CounterVec
+	auctionDuration prometheus.Histogram
+	bidsPerAuction  prometheus.Histogram
+	bidVerification *prometheus.HistogramVec
+	bidLatency      *prometheus.HistogramVec
+	bidQueueDepth   prometheus.Gauge
+	propagation     *prometheus.HistogramVec
+	settlements     *prometheus.CounterVec
+	rpcRequests     *prometheus.CounterVec
+	rpcErrors       *prometheus.CounterVec
+	clockDrift      *prometheus.GaugeVec
+	keyBalance      prometheus.Gauge
+	keyRunway       prometheus.Gauge
+}
+
+func New() *Metrics {
+	return NewForChain(NewRegistry(), "")
+}
+
+// Registry with Go runtime and process collectors, shared by the engines of a multi-chain process
+func NewRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return registry
+}
+
+// Collectors of one chain's engine on a shared registry, labelled chain=<chain> unless it's empty. Engines sharing
+// a registry must all be labelled, as a metric can't be registered both with and without the label.
+func NewForChain(registry *prometheus.Registry, chain string) *Metrics {
+	var registerer prometheus.Registerer = registry
+	if chain != "" {
+		registerer = prometheus.WrapRegistererWith(prometheus.Labels{"chain": chain}, registry)
+	}
+	m := &Metrics{
+		registry: registry,
+		blocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "blocks_observed_total",
+			Help:      "L1 blocks observed by the listener.",
+		}),
+		lastBlock: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_block",
+			Help:      "Latest L1 block observed by the listener.",
+		}),
+		auctions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auctions_total",
+			Help:      "Auctions closed, by outcome.",
+		}, []string{"outcome"}),
+		auctionDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "auction_duration_seconds",
+			Help:      "Time from opening to closing an auction.",
+			Buckets:   []float64{0.5, 1, 2, 3, 4, 4.5, 5, 5.1, 5.25, 5.5, 6},
+		}),
+		bidsPerAuction: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "bids_per_auction",
+			Help:      "Bids submitted to each auction.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+		}),
+		bidVerification: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "bid_verification_seconds",
+			Help:      "Bid signature verification latency, by whether the signature was valid.",
+			Buckets:   prometheus.ExponentialBuckets(0.00001, 2, 12),
+		}, []string{"valid"}),
+		bidLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "bid_latency_seconds",
+			Help:      "Latency from bid submission to the auction to verification and to becoming the leader, by stage.",
+			Buckets:   latencyBuckets,
+		}, []string{"stage"}),
+		bidQueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "bid_queue_depth",
+			Help:      "Bids submitted to the auction in progress and not yet evaluated.",
+		}),
+		propagation: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "leader_propagation_seconds",
+			Help:      "Latency from a leader change to sending it to a subscribed relay, by transport.",
+			Buckets:   latencyBuckets,
+		}, []string{"transport"}),
+		settlements: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "settlements_total",
+			Help:      "Settlement txs of won auctions, by outcome.",
+		}, []string{"outcome"}),
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_requests_total",
+			Help:      "RPC requests to L1 and settlement layer nodes, by method.",
+		}, []string{"method"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_errors_total",
+			Help:      "Failed RPC requests to L1 and settlement layer nodes, by method.",
+		}, []string{"method"}),
+		clockDrift: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "clock_drift_seconds",
+			Help:      "Measured drift of the local clock, positive if ahead, by source.",
+		}, []string{"source"}),
+		keyBalance: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "settlement_key_balance_eth",
+			Help:      "ETH balance of the key settlement txs are sent from.",
+		}),
+		keyRunway: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "settlement_key_runway_seconds",
+			Help:      "How long the settlement key's balance covers settling every slot at the current max fee.",
+		}),
+	}
+	registerer.MustRegister(
+		m.blocks, m.lastBlock, m.auctions, m.auctionDuration, m.bidsPerAuction,
+		m.bidVerification, m.bidLatency, m.bidQueueDepth, m.propagation, m.settlements, m.rpcRequests, m.rpcErrors,
+		m.clockDrift, m.keyBalance, m.keyRunway,
+	)
+	return m
+}
+
+// For other subsystems to register their own collectors, exposed alongside these
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Serves the registry in the Prometheus exposition format, or OpenMetrics if negotiated, which carries exemplars
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry, EnableOpenMetrics: true})
+}
+
+// To satisfy listener.Metrics
+func (m *Metrics) ObserveBlock(block uint64) {
+	m.blocks.Inc()
+	m.lastBlock.Set(float64(block))
+}
+
+// To satisfy listener.Metrics
+func (m *Metrics) ObserveAuction(duration time.Duration, bids int, won bool) {
+	outcome := "no_winner"
+	if won {
+		outcome = "won"
+	}
+	m.auctions.WithLabelValues(outcome).Inc()
+	m.auctionDuration.Observe(duration.Seconds())
+	m.bidsPerAuction.Observe(float64(bids))
+}
+
+// To satisfy listener.Metrics
+func (m *Metrics) ObserveSettlement(succeeded bool) {
+	outcome := "failed"
+	if succeeded {
+		outcome = "settled"
+	}
+	m.settlements.WithLabelValues(outcome).Inc()
+}
+
+// To satisfy listener.Metrics, and for other RPC clients
+func (m *Metrics) ObserveRPC(method string, err error) {
+	m.rpcRequests.WithLabelValues(method).Inc()
+	if err != nil {
+		m.rpcErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// To satisfy auction.Metrics
+func (m *Metrics) ObserveBidVerification(duration time.Duration, valid bool) {
+	m.bidVerification.WithLabelValues(strconv.FormatBool(valid)).Observe(duration.Seconds())
+}
+
+// To satisfy auction.Metrics. Observations carry the bid's relay and block as an exemplar, so outliers
+// can be traced to the bid in the audit log or store.
+func (m *Metrics) ObserveBidLatency(stage auction.BidStage, latency time.Duration, bid auction.SignedBid) {
+	observeWithExemplar(m.bidLatency.WithLabelValues(string(stage)), latency, bid)
+}
+
+// To satisfy auction.Metrics
+func (m *Metrics) ObserveBidQueueDepth(depth int) {
+	m.bidQueueDepth.Set(float64(depth))
+}
+
+// To satisfy relaygrpc.Metrics and jsonrpc.Metrics
+func (m *Metrics) ObserveLeaderPropagation(transport string, latency time.Duration, leader auction.SignedBid) {
+	observeWithExemplar(m.propagation.WithLabelValues(transport), latency, leader)
+}
+
+// To satisfy timesync.Metrics
+func (m *Metrics) ObserveClockDrift(source string, drift time.Duration) {
+	m.clockDrift.WithLabelValues(source).Set(drift.Seconds())
+}
+
+// To satisfy funding.Metrics
+func (m *Metrics) ObserveSettlementKey(balanceWei *big.Int, runway time.Duration) {
+	balance, _ := new(big.Float).Quo(new(big.Float).SetInt(balanceWei), big.NewFloat(params.Ether)).Float64()
+	m.keyBalance.Set(balance)
+	m.keyRunway.Set(runway.Seconds())
+}
+
+func observeWithExemplar(observer prometheus.Observer, latency time.Duration, bid auction.SignedBid) {
+	labels := prometheus.Labels{"relay": bid.Address.Hex()}
+	if bid.L1Block != nil {
+		labels["l1Block"] = bid.L1Block.String()
+	}
+	observer.(prometheus.ExemplarObserver).ObserveWithExemplar(latency.Seconds(), labels)
+}
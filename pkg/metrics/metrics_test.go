@@ -0,0 +1,111 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/jsonrpc"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/metrics"
+	"blob-preconfs/pkg/relaygrpc"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var _ listener.Metrics = (*metrics.Metrics)(nil)
+var _ relaygrpc.Metrics = (*metrics.Metrics)(nil)
+var _ jsonrpc.Metrics = (*metrics.Metrics)(nil)
+
+func TestMetricsServed(t *testing.T) {
+	m := metrics.New()
+	m.ObserveBlock(100)
+	m.ObserveAuction(5*time.Second, 3, true)
+	m.ObserveBidVerification(50*time.Microsecond, true)
+	m.ObserveBidQueueDepth(7)
+	m.ObserveSettlement(false)
+	m.ObserveRPC("eth_blockNumber", nil)
+	m.ObserveRPC("eth_blockNumber", errors.New("connection refused"))
+	m.ObserveSettlementKey(big.NewInt(1_500_000_000_000_000_000), time.Hour)
+
+	server := metrics.NewServer(slog.Default(), "127.0.0.1:0", m, nil)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	resp, err := http.Get("http://" + server.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	for _, line := range []string{
+		"auctioneer_blocks_observed_total 1",
+		"auctioneer_last_block 100",
+		`auctioneer_auctions_total{outcome="won"} 1`,
+		"auctioneer_auction_duration_seconds_count 1",
+		"auctioneer_bids_per_auction_sum 3",
+		`auctioneer_bid_verification_seconds_count{valid="true"} 1`,
+		"auctioneer_bid_queue_depth 7",
+		`auctioneer_settlements_total{outcome="failed"} 1`,
+		`auctioneer_rpc_requests_total{method="eth_blockNumber"} 2`,
+		`auctioneer_rpc_errors_total{method="eth_blockNumber"} 1`,
+		"auctioneer_settlement_key_balance_eth 1.5",
+		"auctioneer_settlement_key_runway_seconds 3600",
+		"go_goroutines",
+	} {
+		require.Contains(t, string(body), line)
+	}
+}
+
+func TestLatencyExemplars(t *testing.T) {
+	m := metrics.New()
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	m.ObserveBidLatency(auction.BidStageAccepted, 2*time.Millisecond, *bid)
+	m.ObserveLeaderPropagation("grpc", 5*time.Millisecond, *bid)
+
+	server := metrics.NewServer(slog.Default(), "127.0.0.1:0", m, nil)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	req, _ := http.NewRequest(http.MethodGet, "http://"+server.Addr().String()+"/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	// Exemplar labels are in no particular order
+	for _, label := range []string{`relay="` + bid.Address.Hex() + `"`, `l1Block="100"`} {
+		exemplar := ` 1 # \{[^}]*` + regexp.QuoteMeta(label) + `[^}]*\} `
+		require.Regexp(t, `auctioneer_bid_latency_seconds_bucket\{stage="accepted",le="0.0032"\}`+exemplar+`0.002`, string(body))
+		require.Regexp(t, `auctioneer_leader_propagation_seconds_bucket\{transport="grpc",le="0.0064"\}`+exemplar+`0.005`, string(body))
+	}
+}
+
+func TestMetricsForChain(t *testing.T) {
+	registry := metrics.NewRegistry()
+	mainnet, holesky := metrics.NewForChain(registry, "mainnet"), metrics.NewForChain(registry, "holesky")
+	mainnet.ObserveBlock(100)
+	holesky.ObserveBlock(200)
+	holesky.ObserveAuction(time.Second, 1, false)
+
+	recorder := httptest.NewRecorder()
+	holesky.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+	for _, line := range []string{
+		`auctioneer_last_block{chain="mainnet"} 100`,
+		`auctioneer_last_block{chain="holesky"} 200`,
+		`auctioneer_auctions_total{chain="holesky",outcome="no_winner"} 1`,
+		"go_goroutines",
+	} {
+		require.Contains(t, body, line)
+	}
+}
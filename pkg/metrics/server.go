@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Serves GET /metrics for Prometheus to scrape
+type Server struct {
+	logger     *slog.Logger
+	mux        *http.ServeMux
+	httpServer *http.Server
+	listener   net.Listener
+}
+
+// Served over TLS if tlsConfig is non-nil, see tlsconfig
+func NewServer(logger *slog.Logger, addr string, metrics *Metrics, tlsConfig *tls.Config) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	return &Server{
+		logger: logger,
+		mux:    mux,
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Serves other operational endpoints alongside metrics, e.g. health probes. Must be called before Start.
+func (s *Server) Mux() *http.ServeMux {
+	return s.mux
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	if s.httpServer.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.httpServer.TLSConfig)
+	}
+	s.listener = listener
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("metrics server failed", "error", err)
+		}
+	}()
+	s.logger.Info("metrics server started", "addr", listener.Addr(), "tls", s.httpServer.TLSConfig != nil)
+	return nil
+}
+
+// Address the server is listening on, useful when started on port 0
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stops accepting new requests and waits for in-flight ones until ctx is done
+func (s *Server) Stop(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	s.logger.Info("metrics server stopped")
+	return err
+}
@@ -0,0 +1,193 @@
+package mevboost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/builder"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	ErrInvalidRelay = errors.New("invalid mev-boost relay")
+	ErrNotAwarded   = errors.New("slot not awarded")
+)
+
+// Awards are kept for this many slots, for late getHeader and getPayload calls
+const awardRetention = 32
+
+// mev-boost relay, configured the way mev-boost takes it, e.g. https://0xac6e77...@boost-relay.flashbots.net,
+// bidding in auctions with the Bidder key
+type Relay struct {
+	URL    string
+	Bidder common.Address
+}
+
+// Splits a mev-boost relay URL into its base URL and the relay's BLS public key
+func ParseRelayURL(raw string) (string, hexutil.Bytes, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", nil, fmt.Errorf("%w: url %q", ErrInvalidRelay, raw)
+	}
+	if u.User == nil {
+		return "", nil, fmt.Errorf("%w: %q has no public key", ErrInvalidRelay, raw)
+	}
+	pubkey, err := hexutil.Decode(u.User.Username())
+	if err != nil || len(pubkey) != 48 {
+		return "", nil, fmt.Errorf("%w: %q has an invalid public key", ErrInvalidRelay, raw)
+	}
+	u.User = nil
+	return strings.TrimSuffix(u.String(), "/"), pubkey, nil
+}
+
+type relay struct {
+	url    string
+	pubkey hexutil.Bytes
+	client *builder.Client
+}
+
+type award struct {
+	relay       *relay
+	constraints builder.BlobConstraints
+}
+
+// Lets existing mev-boost relays take part in auctions. Relays are registered, as an auction.RelayRegistry, while
+// they pass mev-boost's status check, and slots won are served through the builder-specs getHeader/getPayload
+// flow they already speak, constrained to the committed blobs (see builder).
+type Adapter struct {
+	logger *slog.Logger
+	relays map[common.Address]*relay
+
+	mu      sync.RWMutex // Protects access to fields below
+	healthy map[common.Address]bool
+	awards  map[uint64]award
+}
+
+func NewAdapter(logger *slog.Logger, relays []Relay, timeout time.Duration) (*Adapter, error) {
+	a := &Adapter{
+		logger:  logger,
+		relays:  make(map[common.Address]*relay, len(relays)),
+		healthy: make(map[common.Address]bool, len(relays)),
+		awards:  make(map[uint64]award),
+	}
+	for _, r := range relays {
+		baseURL, pubkey, err := ParseRelayURL(r.URL)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := a.relays[r.Bidder]; ok {
+			return nil, fmt.Errorf("%w: bidder %s has more than one relay", ErrInvalidRelay, r.Bidder.Hex())
+		}
+		a.relays[r.Bidder] = &relay{url: baseURL, pubkey: pubkey, client: builder.NewClient(logger, baseURL, timeout)}
+	}
+	return a, nil
+}
+
+// To satisfy auction.RelayRegistry. Bidders are registered while their relay passed its last status check.
+func (a *Adapter) IsRegisteredOnSettlementLayer(address common.Address) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.healthy[address]
+}
+
+// Checks every relay's status, registering those that are up. Returns the relays' errors, joined.
+// Satisfies admin.RegistryResyncer.
+func (a *Adapter) Resync(ctx context.Context) error {
+	var errs []error
+	healthy := make(map[common.Address]bool, len(a.relays))
+	for bidder, r := range a.relays {
+		if err := r.client.Status(ctx); err != nil {
+			a.logger.Warn("mev-boost relay status check failed", "relay", r.url, "bidder", bidder, "error", err)
+			errs = append(errs, fmt.Errorf("relay %s: %w", r.url, err))
+			continue
+		}
+		healthy[bidder] = true
+	}
+	a.mu.Lock()
+	a.healthy = healthy
+	a.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// Resyncs every interval until ctx is done
+func (a *Adapter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Resync(ctx)
+		}
+	}
+}
+
+// Awards the slot to the winning bid's relay, asking it to only build blocks including the committed blobs
+func (a *Adapter) Award(ctx context.Context, slot uint64, winner auction.SignedBid, versionedHashes []common.Hash) error {
+	r, ok := a.relays[winner.Address]
+	if !ok {
+		return fmt.Errorf("%w: bidder %s has no mev-boost relay", ErrInvalidRelay, winner.Address.Hex())
+	}
+	constraints := builder.BlobConstraints{Slot: slot, VersionedHashes: versionedHashes}
+	if err := r.client.SubmitConstraints(ctx, constraints); err != nil {
+		return fmt.Errorf("failed to submit constraints to %s: %w", r.url, err)
+	}
+	a.mu.Lock()
+	a.awards[slot] = award{relay: r, constraints: constraints}
+	for awarded := range a.awards {
+		if awarded+awardRetention < slot {
+			delete(a.awards, awarded)
+		}
+	}
+	a.mu.Unlock()
+	a.logger.Info("slot awarded to mev-boost relay", "slot", slot, "relay", r.url, "numBlobs", len(versionedHashes))
+	return nil
+}
+
+// Fetches the header for the slot from the relay awarded it, checked against the blob constraints and the
+// relay's public key from its URL. The BLS signature is left to the proposer.
+func (a *Adapter) GetHeader(ctx context.Context, slot uint64, parentHash common.Hash, proposerPubkey hexutil.Bytes) (*builder.SignedBuilderBid, error) {
+	awarded, err := a.award(slot)
+	if err != nil {
+		return nil, err
+	}
+	bid, err := awarded.relay.client.GetHeader(ctx, awarded.constraints, parentHash, proposerPubkey)
+	if err != nil {
+		return nil, err
+	}
+	if len(bid.Message.Pubkey) > 0 && !bytes.Equal(bid.Message.Pubkey, awarded.relay.pubkey) {
+		return nil, fmt.Errorf("header from %s has unexpected public key %s", awarded.relay.url, bid.Message.Pubkey)
+	}
+	return bid, nil
+}
+
+// Unblinds the slot's block through the relay awarded it, checked against the blob constraints
+func (a *Adapter) GetPayload(ctx context.Context, slot uint64, signedBlindedBlock json.RawMessage) (*builder.ExecutionPayloadAndBlobsBundle, error) {
+	awarded, err := a.award(slot)
+	if err != nil {
+		return nil, err
+	}
+	return awarded.relay.client.GetPayload(ctx, awarded.constraints, signedBlindedBlock)
+}
+
+func (a *Adapter) award(slot uint64) (award, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	awarded, ok := a.awards[slot]
+	if !ok {
+		return award{}, fmt.Errorf("%w: %d", ErrNotAwarded, slot)
+	}
+	return awarded, nil
+}
@@ -0,0 +1,143 @@
+package mevboost_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/builder"
+	"blob-preconfs/pkg/mevboost"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+var _ auction.RelayRegistry = (*mevboost.Adapter)(nil)
+
+var relayPubkey = "0x" + strings.Repeat("ab", 48)
+
+type mockRelay struct {
+	server      *httptest.Server
+	down        atomic.Bool
+	mu          sync.Mutex
+	constraints []builder.BlobConstraints
+	commitments []kzg4844.Commitment
+}
+
+func newMockRelay(t *testing.T) *mockRelay {
+	relay := &mockRelay{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/builder/status", func(w http.ResponseWriter, r *http.Request) {
+		if relay.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	mux.HandleFunc("/eth/v1/builder/constraints", func(w http.ResponseWriter, r *http.Request) {
+		var c builder.BlobConstraints
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&c))
+		relay.mu.Lock()
+		relay.constraints = append(relay.constraints, c)
+		relay.mu.Unlock()
+	})
+	mux.HandleFunc("/eth/v1/builder/header/", func(w http.ResponseWriter, r *http.Request) {
+		relay.mu.Lock()
+		defer relay.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]any{
+			"version": "deneb",
+			"data": builder.SignedBuilderBid{Message: builder.BuilderBid{
+				Header:             json.RawMessage(`{}`),
+				BlobKZGCommitments: relay.commitments,
+				Value:              "1",
+				Pubkey:             hexutil.MustDecode(relayPubkey),
+			}},
+		})
+	})
+	relay.server = httptest.NewServer(mux)
+	t.Cleanup(relay.server.Close)
+	return relay
+}
+
+// mev-boost style URL, with the relay's public key as the user
+func (r *mockRelay) url() string {
+	return strings.Replace(r.server.URL, "http://", "http://"+relayPubkey+"@", 1)
+}
+
+func TestParseRelayURL(t *testing.T) {
+	baseURL, pubkey, err := mevboost.ParseRelayURL("https://" + relayPubkey + "@boost-relay.example.com/")
+	require.NoError(t, err)
+	require.Equal(t, "https://boost-relay.example.com", baseURL)
+	require.Equal(t, relayPubkey, pubkey.String())
+
+	for _, raw := range []string{"boost-relay.example.com", "https://boost-relay.example.com", "https://0x1234@boost-relay.example.com", "ftp://" + relayPubkey + "@example.com"} {
+		_, _, err := mevboost.ParseRelayURL(raw)
+		require.ErrorIs(t, err, mevboost.ErrInvalidRelay, raw)
+	}
+}
+
+func TestRegistryFollowsRelayStatus(t *testing.T) {
+	up, down := newMockRelay(t), newMockRelay(t)
+	down.down.Store(true)
+	upBidder, downBidder := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	adapter, err := mevboost.NewAdapter(slog.Default(), []mevboost.Relay{{URL: up.url(), Bidder: upBidder}, {URL: down.url(), Bidder: downBidder}}, time.Second)
+	require.NoError(t, err)
+	require.False(t, adapter.IsRegisteredOnSettlementLayer(upBidder), "unregistered until checked")
+
+	require.ErrorContains(t, adapter.Resync(context.Background()), "status 503")
+	require.True(t, adapter.IsRegisteredOnSettlementLayer(upBidder))
+	require.False(t, adapter.IsRegisteredOnSettlementLayer(downBidder))
+	require.False(t, adapter.IsRegisteredOnSettlementLayer(common.HexToAddress("0x03")))
+
+	down.down.Store(false)
+	up.down.Store(true)
+	require.Error(t, adapter.Resync(context.Background()))
+	require.False(t, adapter.IsRegisteredOnSettlementLayer(upBidder))
+	require.True(t, adapter.IsRegisteredOnSettlementLayer(downBidder))
+}
+
+func TestDuplicateBidderRejected(t *testing.T) {
+	relay := newMockRelay(t)
+	bidder := common.HexToAddress("0x01")
+	_, err := mevboost.NewAdapter(slog.Default(), []mevboost.Relay{{URL: relay.url(), Bidder: bidder}, {URL: relay.url(), Bidder: bidder}}, time.Second)
+	require.ErrorIs(t, err, mevboost.ErrInvalidRelay)
+}
+
+func TestAwardServesHeaderFromWinner(t *testing.T) {
+	relay := newMockRelay(t)
+	bidder := common.HexToAddress("0x01")
+	adapter, err := mevboost.NewAdapter(slog.Default(), []mevboost.Relay{{URL: relay.url(), Bidder: bidder}}, time.Second)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = adapter.GetHeader(ctx, 10, common.Hash{}, hexutil.MustDecode(relayPubkey))
+	require.ErrorIs(t, err, mevboost.ErrNotAwarded)
+	err = adapter.Award(ctx, 10, auction.SignedBid{Address: common.HexToAddress("0x02")}, nil)
+	require.ErrorIs(t, err, mevboost.ErrInvalidRelay, "winner without a mev-boost relay")
+
+	var commitment kzg4844.Commitment
+	commitment[0] = 0xc0
+	versionedHash := kzg4844.CalcBlobHashV1(sha256.New(), &commitment)
+	require.NoError(t, adapter.Award(ctx, 10, auction.SignedBid{Address: bidder}, []common.Hash{versionedHash}))
+	relay.mu.Lock()
+	require.Equal(t, []builder.BlobConstraints{{Slot: 10, VersionedHashes: []common.Hash{versionedHash}}}, relay.constraints)
+	relay.mu.Unlock()
+
+	_, err = adapter.GetHeader(ctx, 10, common.Hash{}, hexutil.MustDecode(relayPubkey))
+	require.ErrorContains(t, err, "violates blob constraints", "header drops the committed blob")
+	relay.mu.Lock()
+	relay.commitments = []kzg4844.Commitment{commitment}
+	relay.mu.Unlock()
+	bid, err := adapter.GetHeader(ctx, 10, common.Hash{}, hexutil.MustDecode(relayPubkey))
+	require.NoError(t, err)
+	require.Equal(t, "1", bid.Message.Value)
+}
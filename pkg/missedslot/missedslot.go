@@ -0,0 +1,249 @@
+package missedslot
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// Per header query
+	queryTimeout = 5 * time.Second
+	// Blocks whose slot was missed are remembered for this many blocks, for classifying commitment misses
+	maxTracked = 1024
+)
+
+type Config struct {
+	SlotTime time.Duration
+	// What becomes of won auctions whose slot was missed
+	Outcome auction.SlotOutcome
+	// How long after the slot ends its block may still arrive, e.g. from a slow node, 2s if 0
+	Grace time.Duration
+}
+
+// Satisfied by *ethclient.Client
+type HeaderSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Satisfied by *listener.Listener
+type Publisher interface {
+	PublishEvent(ev auction.Event)
+}
+
+// Records refunded auctions' new result, with no winner, e.g. store.Store implementations
+type Recorder interface {
+	SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error
+}
+
+// Told of winners whose slot was missed, so it counts as the proposer's fault rather than the relay's, e.g.
+// *reputation.Tracker
+type Reputation interface {
+	RecordProposerFault(relay common.Address, l1Block uint64)
+}
+
+// Detects won auctions whose slot was missed, no block proposed in the slot after the auctioned block, and settles
+// their outcome: refunded or carried over, per Config.Outcome. Satisfies commitment.MissClassifier, attributing
+// commitments missed with their slot to the proposer and others to the relay.
+type Detector struct {
+	logger     *slog.Logger
+	config     Config
+	headers    HeaderSource
+	publisher  Publisher
+	recorder   Recorder
+	reputation Reputation
+
+	mu sync.Mutex // Protects winners, missed and latest
+	// Current winners of auctions whose slot is yet to be checked, by L1 block
+	winners map[uint64]auction.SignedBid
+	// Blocks that weren't proposed in their slot, the block after each auctioned one
+	missed map[uint64]struct{}
+	latest uint64
+}
+
+func NewDetector(logger *slog.Logger, config Config, headers HeaderSource, publisher Publisher) *Detector {
+	if config.Grace <= 0 {
+		config.Grace = 2 * time.Second
+	}
+	return &Detector{
+		logger:    logger,
+		config:    config,
+		headers:   headers,
+		publisher: publisher,
+		winners:   make(map[uint64]auction.SignedBid),
+		missed:    make(map[uint64]struct{}),
+	}
+}
+
+// Refunded auctions are recorded with no winner, so they're no longer owed, if set before Watch
+func (d *Detector) SetRecorder(recorder Recorder) {
+	d.recorder = recorder
+}
+
+// Winners whose slot was missed are recorded as proposer faults, if set before Watch
+func (d *Detector) SetReputation(reputation Reputation) {
+	d.reputation = reputation
+}
+
+// Checks the slot of each auction closed with a winner once it's over, until ctx is done or events is closed.
+// A winner the auction fell back to meanwhile is the one whose slot was missed.
+func (d *Detector) Watch(ctx context.Context, events <-chan auction.Event) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if (ev.Type != auction.EventAuctionClosed && ev.Type != auction.EventWinnerFallback) || ev.Bid == nil || ev.L1Block == nil {
+				continue
+			}
+			d.mu.Lock()
+			d.winners[ev.L1Block.Uint64()] = *ev.Bid
+			d.mu.Unlock()
+			if ev.Type == auction.EventWinnerFallback {
+				continue
+			}
+			wg.Add(1)
+			go func(l1Block uint64) {
+				defer wg.Done()
+				d.await(ctx, l1Block)
+			}(ev.L1Block.Uint64())
+		}
+	}
+}
+
+// Waits for the auctioned block's next slot to end, then checks whether it was missed
+func (d *Detector) await(ctx context.Context, l1Block uint64) {
+	defer func() {
+		d.mu.Lock()
+		delete(d.winners, l1Block)
+		d.mu.Unlock()
+	}()
+	header, err := d.header(ctx, l1Block)
+	if err != nil {
+		d.logger.Warn("failed to read auctioned block header, not checking its slot", "blockNumber", l1Block, "error", err)
+		return
+	}
+	slotEnd := time.Unix(int64(header.Time), 0).Add(2 * d.config.SlotTime)
+	timer := time.NewTimer(time.Until(slotEnd.Add(d.config.Grace)))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+	missed, err := d.Check(ctx, header)
+	if err != nil {
+		d.logger.Warn("failed to check slot", "blockNumber", l1Block, "error", err)
+		return
+	}
+	if missed {
+		d.settle(l1Block)
+	}
+}
+
+// Whether the slot after the block's was missed: the next block is yet to be proposed, or was proposed in a
+// later slot. Only meaningful once the slot is over.
+func (d *Detector) Check(ctx context.Context, parent *types.Header) (bool, error) {
+	next, err := d.header(ctx, parent.Number.Uint64()+1)
+	if errors.Is(err, ethereum.NotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return d.late(parent, next), nil
+}
+
+func (d *Detector) late(parent *types.Header, next *types.Header) bool {
+	return time.Duration(next.Time-parent.Time)*time.Second > d.config.SlotTime
+}
+
+// Settles the outcome of the auction for l1Block, whose winner's slot was missed
+func (d *Detector) settle(l1Block uint64) {
+	d.mu.Lock()
+	winner := d.winners[l1Block]
+	d.missed[l1Block+1] = struct{}{}
+	if l1Block+1 > d.latest {
+		d.latest = l1Block + 1
+		for block := range d.missed {
+			if block+maxTracked <= d.latest {
+				delete(d.missed, block)
+			}
+		}
+	}
+	d.mu.Unlock()
+
+	d.logger.Warn("slot missed, no block proposed for the won auction", "blockNumber", l1Block, "winner", winner.Address,
+		"outcome", d.config.Outcome)
+	if d.reputation != nil {
+		d.reputation.RecordProposerFault(winner.Address, l1Block)
+	}
+	now := time.Now()
+	if d.config.Outcome == auction.SlotRefund && d.recorder != nil {
+		if err := d.recorder.SaveAuctionResult(l1Block, nil, now); err != nil {
+			d.logger.Error("failed to record refunded auction", "blockNumber", l1Block, "error", err)
+		}
+	}
+	d.publisher.PublishEvent(auction.Event{Type: auction.EventSlotMissed, L1Block: new(big.Int).SetUint64(l1Block), Bid: &winner,
+		Outcome: d.config.Outcome, Timestamp: now})
+}
+
+// Whether the block wasn't proposed in its slot, as detected after the auction for the block before
+func (d *Detector) Missed(l1Block uint64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.missed[l1Block]
+	return ok
+}
+
+// To satisfy commitment.MissClassifier. Commitments targeting a block whose slot was missed are the proposer's
+// fault, others the relay's. A target block yet to be checked, as the miss is observed before its slot check is
+// due, is checked from its header and its parent's.
+func (d *Detector) ClassifyMiss(c commitment.Commitment, block *big.Int) commitment.MissReason {
+	if c.TargetBlock == nil || c.TargetBlock.Sign() <= 0 {
+		return commitment.MissReasonRelayFault
+	}
+	target := c.TargetBlock.Uint64()
+	if d.Missed(target) || d.proposedLate(target) {
+		return commitment.MissReasonProposerFault
+	}
+	return commitment.MissReasonRelayFault
+}
+
+// Whether the block was proposed after its slot. Blocks yet to be proposed aren't, as their slot may not be over.
+func (d *Detector) proposedLate(number uint64) bool {
+	parent, err := d.header(context.Background(), number-1)
+	if err != nil {
+		d.logger.Warn("failed to read header, attributing miss to the relay", "blockNumber", number-1, "error", err)
+		return false
+	}
+	next, err := d.header(context.Background(), number)
+	if err != nil {
+		if !errors.Is(err, ethereum.NotFound) {
+			d.logger.Warn("failed to read header, attributing miss to the relay", "blockNumber", number, "error", err)
+		}
+		return false
+	}
+	return d.late(parent, next)
+}
+
+func (d *Detector) header(ctx context.Context, number uint64) (*types.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	return d.headers.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+}
@@ -0,0 +1,184 @@
+package missedslot_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/missedslot"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// Headers by block number
+type mockHeaders map[uint64]*types.Header
+
+func (m mockHeaders) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, ok := m[number.Uint64()]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return header, nil
+}
+
+type mockPublisher struct{ events chan auction.Event }
+
+func (m mockPublisher) PublishEvent(ev auction.Event) { m.events <- ev }
+
+type mockRecorder struct {
+	mu      sync.Mutex
+	results map[uint64]*auction.SignedBid
+}
+
+func (m *mockRecorder) SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[l1Block] = winner
+	return nil
+}
+
+type mockReputation struct{ faults chan common.Address }
+
+func (m mockReputation) RecordProposerFault(relay common.Address, l1Block uint64) { m.faults <- relay }
+
+func header(number, timestamp uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Time: timestamp}
+}
+
+func TestCheck(t *testing.T) {
+	headers := mockHeaders{
+		100: header(100, 1200),
+		101: header(101, 1212),
+		// Slot missed before 102
+		102: header(102, 1236),
+	}
+	detector := missedslot.NewDetector(slog.Default(), missedslot.Config{SlotTime: 12 * time.Second}, headers, mockPublisher{})
+	for block, want := range map[uint64]bool{100: false, 101: true, 102: true} {
+		missed, err := detector.Check(context.Background(), headers[block])
+		require.NoError(t, err)
+		require.Equal(t, want, missed, block)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	// Long past, so each slot is over as the auction closes
+	headers := mockHeaders{
+		100: header(100, 1200),
+		101: header(101, 1212),
+		102: header(102, 1224),
+		// Slot missed before 103
+		103: header(103, 1248),
+	}
+	relay1, relay2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	for _, outcome := range []auction.SlotOutcome{auction.SlotRefund, auction.SlotCarryOver} {
+		t.Run(string(outcome), func(t *testing.T) {
+			published := make(chan auction.Event, 4)
+			recorder := &mockRecorder{results: make(map[uint64]*auction.SignedBid)}
+			faults := make(chan common.Address, 4)
+			detector := missedslot.NewDetector(slog.Default(), missedslot.Config{SlotTime: 12 * time.Second, Outcome: outcome},
+				headers, mockPublisher{published})
+			detector.SetRecorder(recorder)
+			detector.SetReputation(mockReputation{faults})
+
+			events := make(chan auction.Event, 4)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go detector.Watch(ctx, events)
+			winner := func(l1Block uint64, relay common.Address) *auction.SignedBid {
+				return &auction.SignedBid{L1Block: new(big.Int).SetUint64(l1Block), AmountWei: big.NewInt(1), Address: relay}
+			}
+			events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: winner(100, relay1)}
+			events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(101)}
+			events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(102), Bid: winner(102, relay1)}
+
+			select {
+			case ev := <-published:
+				require.Equal(t, auction.EventSlotMissed, ev.Type)
+				require.Equal(t, uint64(102), ev.L1Block.Uint64())
+				require.Equal(t, relay1, ev.Bid.Address)
+				require.Equal(t, outcome, ev.Outcome)
+			case <-time.After(time.Second):
+				t.Fatal("missed slot not published")
+			}
+			require.Equal(t, relay1, <-faults, "the proposer's fault")
+			recorder.mu.Lock()
+			refunded, ok := recorder.results[102]
+			recorder.mu.Unlock()
+			if outcome == auction.SlotRefund {
+				require.True(t, ok)
+				require.Nil(t, refunded, "recorded with no winner")
+			} else {
+				require.False(t, ok, "the win still stands")
+			}
+			require.True(t, detector.Missed(103))
+			require.False(t, detector.Missed(101))
+
+			missed := commitment.Commitment{TargetBlock: big.NewInt(103), Committer: relay2}
+			require.Equal(t, commitment.MissReasonProposerFault, detector.ClassifyMiss(missed, big.NewInt(103)))
+			missed.TargetBlock = big.NewInt(101)
+			require.Equal(t, commitment.MissReasonRelayFault, detector.ClassifyMiss(missed, big.NewInt(101)))
+			select {
+			case ev := <-published:
+				t.Fatalf("unexpected event %v", ev)
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestWatchFallback(t *testing.T) {
+	headers := mockHeaders{100: header(100, 1200)}
+	relay1, relay2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	published := make(chan auction.Event, 1)
+	faults := make(chan common.Address, 1)
+	// The slot isn't over until the fallback is seen
+	slotTime := (time.Since(time.Unix(1200, 0)) + 500*time.Millisecond) / 2
+	detector := missedslot.NewDetector(slog.Default(), missedslot.Config{
+		SlotTime: slotTime,
+		Outcome:  auction.SlotCarryOver,
+		Grace:    time.Millisecond,
+	}, headers, mockPublisher{published})
+	detector.SetReputation(mockReputation{faults})
+
+	events := make(chan auction.Event, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go detector.Watch(ctx, events)
+	events <- auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: &auction.SignedBid{Address: relay1}}
+	events <- auction.Event{Type: auction.EventWinnerFallback, L1Block: big.NewInt(100), Bid: &auction.SignedBid{Address: relay2}}
+	select {
+	case ev := <-published:
+		require.Equal(t, relay2, ev.Bid.Address, "the winner it fell back to missed the slot")
+	case <-time.After(2 * time.Second):
+		t.Fatal("missed slot not published")
+	}
+	require.Equal(t, relay2, <-faults)
+}
+
+func TestClassifyMissBeforeCheck(t *testing.T) {
+	headers := mockHeaders{
+		100: header(100, 1200),
+		101: header(101, 1212),
+		// Slot missed before 102
+		102: header(102, 1236),
+	}
+	// Never watching, as a miss may be observed before the slot is checked
+	detector := missedslot.NewDetector(slog.Default(), missedslot.Config{SlotTime: 12 * time.Second}, headers, mockPublisher{})
+	for target, want := range map[int64]commitment.MissReason{
+		101: commitment.MissReasonRelayFault,
+		102: commitment.MissReasonProposerFault,
+		// Yet to be proposed
+		103: commitment.MissReasonRelayFault,
+	} {
+		missed := commitment.Commitment{TargetBlock: big.NewInt(target)}
+		require.Equal(t, want, detector.ClassifyMiss(missed, big.NewInt(target)), target)
+	}
+}
@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/big"
+	"slices"
+	"sync"
+
+	"blob-preconfs/pkg/auction"
+)
+
+type ControllerConfig struct {
+	// Reserve price until the controller adjusts it
+	InitialWei *big.Int
+	// Bounds the reserve price is kept within, unbounded above if MaxWei is nil
+	MinWei *big.Int
+	MaxWei *big.Int
+	// Clearing prices of the last Window auctions are tracked, 32 if 0
+	Window int
+	// The reserve price tracks this percent of the median recent clearing price, 80 if 0
+	TargetPercent uint64
+	// The reserve price tracks at least the blob base fee times this, disabled if 0
+	BlobFeeMultiple uint64
+	// Gains, in percent, on the target's distance from the reserve price, its sum over slots, and its change since
+	// the last slot
+	ProportionalPercent uint64
+	IntegralPercent     uint64
+	DerivativePercent   uint64
+	// Most the reserve price moves per slot, unbounded if nil
+	MaxStepWei *big.Int
+}
+
+// Adjusts the reserve price each slot towards a target tracking recent clearing prices and the blob base fee, with
+// a bounded PID controller, so it follows demand without retuning. Auctions closing without a winner clear at 0,
+// pulling the reserve price down when it's priced relays out.
+type Controller struct {
+	logger *slog.Logger
+	config ControllerConfig
+
+	mu sync.Mutex // Protects access to fields below
+	// Clearing prices of recent auctions, oldest first
+	prices    []float64
+	reserve   float64
+	integral  float64
+	lastError float64
+}
+
+func NewController(logger *slog.Logger, config ControllerConfig) *Controller {
+	if config.Window <= 0 {
+		config.Window = 32
+	}
+	if config.TargetPercent == 0 {
+		config.TargetPercent = 80
+	}
+	c := &Controller{logger: logger, config: config}
+	if config.InitialWei != nil {
+		c.reserve = toFloat(config.InitialWei)
+	}
+	c.reserve = c.bound(c.reserve)
+	return c
+}
+
+// Records the clearing price of an auction, its winning bid, or 0 if it closed without one
+func (c *Controller) Observe(winner *auction.SignedBid) {
+	price := 0.0
+	if winner != nil && winner.AmountWei != nil {
+		price = toFloat(winner.AmountWei)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prices = append(c.prices, price)
+	if len(c.prices) > c.config.Window {
+		c.prices = c.prices[len(c.prices)-c.config.Window:]
+	}
+}
+
+// Records clearing prices from auctionClosed events until ctx is done or events is closed
+func (c *Controller) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type == auction.EventAuctionClosed {
+				c.Observe(ev.Bid)
+			}
+		}
+	}
+}
+
+// Current reserve price, without adjusting it
+func (c *Controller) Reserve() *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return toInt(c.reserve)
+}
+
+// Adjusts the reserve price for the next slot, given its blob base fee, nil if unknown, and returns it. The target
+// holds the current reserve price until a clearing price is observed.
+func (c *Controller) Update(blobBaseFee *big.Int) *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	target := c.reserve
+	if len(c.prices) > 0 {
+		target = median(c.prices) * float64(c.config.TargetPercent) / 100
+	}
+	if blobBaseFee != nil && c.config.BlobFeeMultiple > 0 {
+		target = max(target, toFloat(blobBaseFee)*float64(c.config.BlobFeeMultiple))
+	}
+
+	err := target - c.reserve
+	integral := c.integral + err
+	output := (float64(c.config.ProportionalPercent)*err +
+		float64(c.config.IntegralPercent)*integral +
+		float64(c.config.DerivativePercent)*(err-c.lastError)) / 100
+	step := output
+	if c.config.MaxStepWei != nil {
+		maxStep := toFloat(c.config.MaxStepWei)
+		step = max(-maxStep, min(step, maxStep))
+	}
+	reserve := c.bound(c.reserve + step)
+	// The error is only integrated while the reserve price moves freely, so it doesn't wind up while held at a
+	// bound or the step limit
+	if step == output && reserve == c.reserve+step {
+		c.integral = integral
+	}
+	c.lastError = err
+	c.reserve = reserve
+	c.logger.Debug("reserve price adjusted", "target", toInt(target), "reserve", toInt(reserve), "blobBaseFee", blobBaseFee)
+	return toInt(reserve)
+}
+
+func (c *Controller) bound(reserve float64) float64 {
+	reserve = max(reserve, 0)
+	if c.config.MinWei != nil {
+		reserve = max(reserve, toFloat(c.config.MinWei))
+	}
+	if c.config.MaxWei != nil {
+		reserve = min(reserve, toFloat(c.config.MaxWei))
+	}
+	return reserve
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func toFloat(wei *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(wei).Float64()
+	return f
+}
+
+func toInt(wei float64) *big.Int {
+	i, _ := big.NewFloat(math.Round(wei)).Int(nil)
+	return i
+}
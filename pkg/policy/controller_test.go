@@ -0,0 +1,127 @@
+package policy_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/policy"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func clearing(t *testing.T, amountWei int64) *auction.SignedBid {
+	pk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return auction.MustCreateSignedBid(big.NewInt(amountWei), big.NewInt(100), pk)
+}
+
+func TestControllerTracksClearingPrices(t *testing.T) {
+	c := policy.NewController(slog.Default(), policy.ControllerConfig{
+		InitialWei:          big.NewInt(100),
+		Window:              3,
+		TargetPercent:       50,
+		ProportionalPercent: 50,
+		IntegralPercent:     10,
+	})
+	require.Equal(t, big.NewInt(100), c.Update(nil), "held until a clearing price is observed")
+
+	for _, price := range []int64{1000, 1000, 1000} {
+		c.Observe(clearing(t, price))
+	}
+	// Target 500
+	require.Greater(t, c.Update(nil).Int64(), int64(100))
+	for i := 0; i < 50; i++ {
+		c.Update(nil)
+	}
+	require.InDelta(t, 500, c.Reserve().Int64(), 5, "settles on the target")
+
+	// Auctions without a winner clear at 0, pulling the reserve price down
+	for i := 0; i < 3; i++ {
+		c.Observe(nil)
+	}
+	for i := 0; i < 50; i++ {
+		c.Update(nil)
+	}
+	require.Less(t, c.Reserve().Int64(), int64(5))
+}
+
+func TestControllerBounds(t *testing.T) {
+	c := policy.NewController(slog.Default(), policy.ControllerConfig{
+		InitialWei:          big.NewInt(100),
+		MinWei:              big.NewInt(80),
+		MaxWei:              big.NewInt(300),
+		TargetPercent:       100,
+		ProportionalPercent: 100,
+		MaxStepWei:          big.NewInt(50),
+	})
+	c.Observe(clearing(t, 1000))
+	require.Equal(t, big.NewInt(150), c.Update(nil), "moves at most the max step")
+	require.Equal(t, big.NewInt(200), c.Update(nil))
+	for i := 0; i < 5; i++ {
+		c.Update(nil)
+	}
+	require.Equal(t, big.NewInt(300), c.Reserve(), "held at the max")
+
+	c.Observe(nil)
+	c.Observe(nil)
+	for i := 0; i < 10; i++ {
+		c.Update(nil)
+	}
+	require.Equal(t, big.NewInt(80), c.Reserve(), "held at the min")
+
+	require.Equal(t, big.NewInt(80), policy.NewController(slog.Default(), policy.ControllerConfig{MinWei: big.NewInt(80)}).Reserve(),
+		"starts within bounds")
+}
+
+func TestControllerBlobFeeFloor(t *testing.T) {
+	c := policy.NewController(slog.Default(), policy.ControllerConfig{
+		TargetPercent:       100,
+		BlobFeeMultiple:     10,
+		ProportionalPercent: 100,
+	})
+	c.Observe(clearing(t, 100))
+	require.Equal(t, big.NewInt(100), c.Update(big.NewInt(1)))
+	require.Equal(t, big.NewInt(500), c.Update(big.NewInt(50)), "at least the blob base fee times the multiple")
+	require.Equal(t, big.NewInt(100), c.Update(nil))
+}
+
+func TestControllerWatch(t *testing.T) {
+	c := policy.NewController(slog.Default(), policy.ControllerConfig{TargetPercent: 100, ProportionalPercent: 100})
+	events := make(chan auction.Event, 2)
+	events <- auction.Event{Type: auction.EventLeaderChanged, Bid: clearing(t, 50)}
+	events <- auction.Event{Type: auction.EventAuctionClosed, Bid: clearing(t, 200)}
+	close(events)
+	c.Watch(context.Background(), events)
+	require.Equal(t, big.NewInt(200), c.Update(nil), "only auctionClosed events clear")
+}
+
+func TestParamsWithController(t *testing.T) {
+	headers := mockHeaders{
+		100: header(1200, 0),
+		// Blob base fee of about 8000 wei
+		101: header(1212, 30_000_000),
+	}
+	p := policy.New(slog.Default(), policy.Config{
+		ReservePriceWei:      big.NewInt(10),
+		BlobFeeSpikeWei:      big.NewInt(1000),
+		SpikeReservePriceWei: big.NewInt(50),
+	}, headers)
+	c := policy.NewController(slog.Default(), policy.ControllerConfig{InitialWei: big.NewInt(20), TargetPercent: 100, ProportionalPercent: 100})
+	p.SetReserveController(c)
+	defaults := listener.AuctionParams{Period: 5 * time.Second}
+
+	c.Observe(clearing(t, 30))
+	require.Equal(t, listener.AuctionParams{Period: 5 * time.Second, ReservePriceWei: big.NewInt(30)}, p.Params(100, defaults),
+		"the controller's reserve price replaces the configured one")
+	require.Equal(t, listener.AuctionParams{Period: 5 * time.Second, ReservePriceWei: big.NewInt(50)}, p.Params(101, defaults),
+		"raised to the spike reserve price")
+	c.Observe(clearing(t, 100))
+	require.Equal(t, listener.AuctionParams{Period: 5 * time.Second, ReservePriceWei: big.NewInt(30)}, p.Params(102, defaults),
+		"not adjusted if the header can't be read")
+}
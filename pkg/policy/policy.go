@@ -0,0 +1,99 @@
+package policy
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"blob-preconfs/pkg/listener"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Headers are read within it, so a slow node doesn't hold up the auction
+const headerTimeout = 500 * time.Millisecond
+
+type Config struct {
+	SlotTime time.Duration
+	// Bidding period of the auction for the block after one or more missed slots, when relays may have more blobs
+	// queued. Disabled if 0.
+	MissedSlotPeriod time.Duration
+	// Reserve price of every auction, none if nil
+	ReservePriceWei *big.Int
+	// Reserve price while the block's blob base fee is at least BlobFeeSpikeWei. Disabled if either is nil.
+	BlobFeeSpikeWei      *big.Int
+	SpikeReservePriceWei *big.Int
+}
+
+// Satisfied by *ethclient.Client
+type HeaderSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Selects each auction's parameters from the block it's for and the one before. Satisfies listener.AuctionPolicy.
+type Policy struct {
+	logger  *slog.Logger
+	config  Config
+	headers HeaderSource
+	// Nil unless the reserve price is adjusted dynamically
+	controller *Controller
+}
+
+func New(logger *slog.Logger, config Config, headers HeaderSource) *Policy {
+	return &Policy{logger: logger, config: config, headers: headers}
+}
+
+// The reserve price is adjusted by controller each auction, instead of the configured ReservePriceWei, if set
+// before the listener starts. The spike reserve price still applies, if higher.
+func (p *Policy) SetReserveController(controller *Controller) {
+	p.controller = controller
+}
+
+// Falls back to the defaults, besides the reserve price, if the headers can't be read. Without the header, the
+// controller's reserve price isn't adjusted.
+func (p *Policy) Params(l1Block uint64, defaults listener.AuctionParams) listener.AuctionParams {
+	params := defaults
+	if p.config.ReservePriceWei != nil {
+		params.ReservePriceWei = p.config.ReservePriceWei
+	}
+	if p.controller != nil {
+		params.ReservePriceWei = p.controller.Reserve()
+	}
+	if p.config.MissedSlotPeriod <= 0 && (p.config.BlobFeeSpikeWei == nil || p.config.SpikeReservePriceWei == nil) && p.controller == nil {
+		return params
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), headerTimeout)
+	defer cancel()
+	header, err := p.headers.HeaderByNumber(ctx, new(big.Int).SetUint64(l1Block))
+	if err != nil {
+		p.logger.Warn("failed to read block header, using default auction parameters", "blockNumber", l1Block, "error", err)
+		return params
+	}
+
+	var blobFee *big.Int
+	if header.ExcessBlobGas != nil {
+		blobFee = eip4844.CalcBlobFee(*header.ExcessBlobGas)
+	}
+	if p.controller != nil {
+		params.ReservePriceWei = p.controller.Update(blobFee)
+	}
+	if p.config.BlobFeeSpikeWei != nil && p.config.SpikeReservePriceWei != nil && blobFee != nil && blobFee.Cmp(p.config.BlobFeeSpikeWei) >= 0 &&
+		(params.ReservePriceWei == nil || params.ReservePriceWei.Cmp(p.config.SpikeReservePriceWei) < 0) {
+		p.logger.Info("blob base fee spiked, raising the reserve price", "blockNumber", l1Block, "blobBaseFee", blobFee)
+		params.ReservePriceWei = p.config.SpikeReservePriceWei
+	}
+	if p.config.MissedSlotPeriod > 0 && p.config.SlotTime > 0 && l1Block > 0 {
+		parent, err := p.headers.HeaderByNumber(ctx, new(big.Int).SetUint64(l1Block-1))
+		if err != nil {
+			p.logger.Warn("failed to read parent header, using default auction period", "blockNumber", l1Block, "error", err)
+			return params
+		}
+		if gap := time.Duration(header.Time-parent.Time) * time.Second; gap > p.config.SlotTime {
+			p.logger.Info("slot missed before block, extending the auction", "blockNumber", l1Block, "missedSlots", gap/p.config.SlotTime-1)
+			params.Period = p.config.MissedSlotPeriod
+		}
+	}
+	return params
+}
@@ -0,0 +1,55 @@
+package policy_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/policy"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// Headers by block number
+type mockHeaders map[uint64]*types.Header
+
+func (m mockHeaders) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, ok := m[number.Uint64()]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return header, nil
+}
+
+func header(timestamp uint64, excessBlobGas uint64) *types.Header {
+	return &types.Header{Time: timestamp, ExcessBlobGas: &excessBlobGas}
+}
+
+func TestParams(t *testing.T) {
+	headers := mockHeaders{
+		99:  header(1200, 0),
+		100: header(1212, 0),
+		// Slot missed before 101
+		101: header(1236, 0),
+		// Blob base fee of 1 wei at no excess blob gas, about 8000 wei at this excess
+		102: header(1248, 30_000_000),
+	}
+	p := policy.New(slog.Default(), policy.Config{
+		SlotTime:             12 * time.Second,
+		MissedSlotPeriod:     8 * time.Second,
+		ReservePriceWei:      big.NewInt(10),
+		BlobFeeSpikeWei:      big.NewInt(1000),
+		SpikeReservePriceWei: big.NewInt(50),
+	}, headers)
+	defaults := listener.AuctionParams{Period: 5 * time.Second}
+
+	require.Equal(t, listener.AuctionParams{Period: 5 * time.Second, ReservePriceWei: big.NewInt(10)}, p.Params(100, defaults))
+	require.Equal(t, listener.AuctionParams{Period: 8 * time.Second, ReservePriceWei: big.NewInt(10)}, p.Params(101, defaults), "longer after a missed slot")
+	require.Equal(t, listener.AuctionParams{Period: 5 * time.Second, ReservePriceWei: big.NewInt(50)}, p.Params(102, defaults), "higher reserve on a blob fee spike")
+	require.Equal(t, listener.AuctionParams{Period: 5 * time.Second, ReservePriceWei: big.NewInt(10)}, p.Params(103, defaults), "defaults if the header can't be read")
+}
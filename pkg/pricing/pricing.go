@@ -0,0 +1,47 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+)
+
+type Config struct {
+	FeePerBlobWei *big.Int
+	// Premium on atomic bundles, in basis points, as they constrain block building more than loose blobs
+	AtomicPremiumBps uint64
+}
+
+// Quotes preconf fees per blob, pricing atomic bundles as a single unit
+type BlobQuoter struct {
+	config Config
+}
+
+func NewBlobQuoter(config Config) *BlobQuoter {
+	return &BlobQuoter{config: config}
+}
+
+func (q *BlobQuoter) QuoteBlobs(numBlobs int, atomic bool) *big.Int {
+	fee := new(big.Int).Mul(q.config.FeePerBlobWei, big.NewInt(int64(numBlobs)))
+	if atomic && numBlobs > 1 {
+		premium := new(big.Int).Mul(fee, new(big.Int).SetUint64(q.config.AtomicPremiumBps))
+		fee.Add(fee, premium.Div(premium, big.NewInt(10_000)))
+	}
+	return fee
+}
+
+// Quotes the request as a whole, failing if it exceeds the user's max fee
+func (q *BlobQuoter) QuoteRequest(req intake.PreconfRequest) (*big.Int, error) {
+	fee := q.QuoteBlobs(len(req.VersionedHashes), req.Atomic)
+	if fee.Cmp(req.MaxFeeWei) > 0 {
+		return nil, fmt.Errorf("quoted fee %v exceeds max fee %v", fee, req.MaxFeeWei)
+	}
+	return fee, nil
+}
+
+// Satisfies commitment.Quoter, for renewals
+func (q *BlobQuoter) Quote(c commitment.Commitment, targetBlock *big.Int) (*big.Int, error) {
+	return q.QuoteBlobs(len(c.VersionedHashes), c.Atomic), nil
+}
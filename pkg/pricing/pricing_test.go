@@ -0,0 +1,43 @@
+package pricing_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/pricing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteBlobs(t *testing.T) {
+	quoter := pricing.NewBlobQuoter(pricing.Config{FeePerBlobWei: big.NewInt(1000), AtomicPremiumBps: 500})
+	require.Equal(t, big.NewInt(3000), quoter.QuoteBlobs(3, false))
+	require.Equal(t, big.NewInt(3150), quoter.QuoteBlobs(3, true))
+	// Single blob bundles are trivially atomic
+	require.Equal(t, big.NewInt(1000), quoter.QuoteBlobs(1, true))
+}
+
+func TestQuoteRequest(t *testing.T) {
+	quoter := pricing.NewBlobQuoter(pricing.Config{FeePerBlobWei: big.NewInt(1000), AtomicPremiumBps: 500})
+	pk, _ := crypto.GenerateKey()
+	blobs := []common.Hash{{0x01}, {0x02}}
+
+	loose, err := intake.CreateSignedRequest(blobs, big.NewInt(100), big.NewInt(2000), pk)
+	require.NoError(t, err)
+	fee, err := quoter.QuoteRequest(*loose)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2000), fee)
+
+	bundle, err := intake.CreateSignedBundleRequest(blobs, big.NewInt(100), big.NewInt(2000), pk)
+	require.NoError(t, err)
+	_, err = quoter.QuoteRequest(*bundle)
+	require.Error(t, err)
+
+	fee, err = quoter.Quote(commitment.Commitment{VersionedHashes: blobs, Atomic: true}, big.NewInt(101))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2100), fee)
+}
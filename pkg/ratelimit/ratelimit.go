@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"golang.org/x/time/rate"
+)
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Buckets unused for this long are dropped, so the limiter doesn't grow with every key ever seen
+const idleBucketTTL = 10 * time.Minute
+
+type Config struct {
+	// Sustained requests per second allowed per key. Zero disables the limit.
+	Rate float64
+	// Requests allowed in a burst above the sustained rate
+	Burst int
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Token bucket rate limiter keyed by an arbitrary string
+type Limiter struct {
+	config Config
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+func NewLimiter(config Config) *Limiter {
+	return &Limiter{
+		config:    config,
+		buckets:   make(map[string]*bucket),
+		lastPrune: time.Now(),
+	}
+}
+
+func (l *Limiter) Allow(key string) bool {
+	if l.config.Rate <= 0 {
+		return true
+	}
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastPrune) > idleBucketTTL {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > idleBucketTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastPrune = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(l.config.Rate), l.config.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter.AllowN(now, 1)
+}
+
+// Rate limits bid submissions by client IP and by recovered bid signer, on all bid intake paths
+type BidLimiter struct {
+	byIP     *Limiter
+	bySigner *Limiter
+}
+
+func NewBidLimiter(perIP Config, perSigner Config) *BidLimiter {
+	return &BidLimiter{
+		byIP:     NewLimiter(perIP),
+		bySigner: NewLimiter(perSigner),
+	}
+}
+
+// Checked before bid validation, so a flood of bids doesn't cost signature recovery
+func (b *BidLimiter) AllowIP(remoteAddr string) error {
+	if b == nil {
+		return nil
+	}
+	ip := HostFromAddr(remoteAddr)
+	if !b.byIP.Allow(ip) {
+		return fmt.Errorf("%w for ip %s", ErrRateLimited, ip)
+	}
+	return nil
+}
+
+// Must only be checked once the bid is validated, so its address is the recovered signer
+func (b *BidLimiter) AllowSigner(bid auction.SignedBid) error {
+	if b == nil {
+		return nil
+	}
+	if !b.bySigner.Allow(bid.Address.Hex()) {
+		return fmt.Errorf("%w for relay %s", ErrRateLimited, bid.Address.Hex())
+	}
+	return nil
+}
+
+// Strips the port from a host:port address, if any
+func HostFromAddr(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// Default largest message, ample for any bid while bounding what a client can make a server buffer
+const DefaultMaxMessageSize = 32 * 1024
+
+// Transport-level limits on each client connection, enforced before messages are decoded, so oversized or chatty
+// clients can't load the auction's hot path
+type TransportConfig struct {
+	// Largest message accepted in bytes: an HTTP request body, websocket message or gRPC message
+	MaxMessageSize int
+	// Messages allowed per connection: requests on one HTTP keep-alive connection, or websocket or gRPC messages
+	PerConn Config
+}
+
+// Rate limits messages by connection, keyed by its remote ip:port
+type ConnLimiter struct {
+	limiter *Limiter
+}
+
+func NewConnLimiter(config Config) *ConnLimiter {
+	return &ConnLimiter{limiter: NewLimiter(config)}
+}
+
+func (c *ConnLimiter) Allow(remoteAddr string) error {
+	if c == nil {
+		return nil
+	}
+	if !c.limiter.Allow(remoteAddr) {
+		return fmt.Errorf("%w for connection %s", ErrRateLimited, remoteAddr)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package ratelimit_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.Config{Rate: 10, Burst: 2})
+	require.True(t, limiter.Allow("a"))
+	require.True(t, limiter.Allow("a"))
+	require.False(t, limiter.Allow("a"))
+	require.True(t, limiter.Allow("b"), "keys have independent buckets")
+
+	time.Sleep(150 * time.Millisecond)
+	require.True(t, limiter.Allow("a"), "bucket refills over time")
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.Config{})
+	for i := 0; i < 100; i++ {
+		require.True(t, limiter.Allow("a"))
+	}
+}
+
+func TestBidLimiter(t *testing.T) {
+	limiter := ratelimit.NewBidLimiter(ratelimit.Config{Rate: 1, Burst: 2}, ratelimit.Config{Rate: 1, Burst: 1})
+
+	require.NoError(t, limiter.AllowIP("10.0.0.1:1234"))
+	require.NoError(t, limiter.AllowIP("10.0.0.1:5678"))
+	require.ErrorIs(t, limiter.AllowIP("10.0.0.1:9999"), ratelimit.ErrRateLimited, "ports share the ip's bucket")
+	require.NoError(t, limiter.AllowIP("10.0.0.2:1234"))
+
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	bid1 := auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(100), pk1)
+	bid2 := auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(100), pk2)
+	require.NoError(t, limiter.AllowSigner(*bid1))
+	require.ErrorIs(t, limiter.AllowSigner(*bid1), ratelimit.ErrRateLimited)
+	require.NoError(t, limiter.AllowSigner(*bid2))
+
+	var disabled *ratelimit.BidLimiter
+	require.NoError(t, disabled.AllowIP("10.0.0.1:1234"))
+	require.NoError(t, disabled.AllowSigner(*bid1))
+}
+
+func TestConnLimiter(t *testing.T) {
+	limiter := ratelimit.NewConnLimiter(ratelimit.Config{Rate: 1, Burst: 2})
+	require.NoError(t, limiter.Allow("10.0.0.1:1234"))
+	require.NoError(t, limiter.Allow("10.0.0.1:1234"))
+	require.ErrorIs(t, limiter.Allow("10.0.0.1:1234"), ratelimit.ErrRateLimited)
+	require.NoError(t, limiter.Allow("10.0.0.1:5678"), "connections from one ip have independent buckets")
+
+	var disabled *ratelimit.ConnLimiter
+	require.NoError(t, disabled.Allow("10.0.0.1:1234"))
+}
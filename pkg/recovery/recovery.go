@@ -0,0 +1,134 @@
+package recovery
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+)
+
+// Satisfied by store.Store implementations
+type History interface {
+	SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+	ListBids(filter store.BidFilter, page store.Page) ([]store.BidRecord, string, error)
+	ListCommitments(filter store.CommitmentFilter, page store.Page) ([]store.CommitmentRecord, string, error)
+}
+
+// Satisfied by *listener.Listener
+type AuctionRestorer interface {
+	Restore(lastAuctionBlock uint64, lastWinner *auction.SignedBid, unsettled []auction.SignedBid)
+}
+
+// Satisfied by *commitment.Coordinator
+type CommitmentRestorer interface {
+	Restore(c commitment.Commitment, state commitment.State)
+}
+
+type Config struct {
+	// History before this L1 block isn't scanned, e.g. the current block minus the settlement window. 0 scans all history.
+	FromBlock uint64
+}
+
+type Result struct {
+	// 0 if no auction concluded since FromBlock
+	LastAuctionBlock uint64
+	// Auction interrupted by the crash, closed with no winner. 0 if there was none.
+	InterruptedAuctionBlock uint64
+	UnsettledAuctions       int
+	Commitments             int
+}
+
+// Reloads in-flight state from history after a restart. Must be called before the listener starts.
+// coordinator may be nil, in which case commitments aren't restored.
+func Recover(logger *slog.Logger, config Config, history History, auctions AuctionRestorer, coordinator CommitmentRestorer) (Result, error) {
+	var result Result
+	blocks := store.BlockRange{From: config.FromBlock}
+
+	var last *store.AuctionRecord
+	err := each(func(page store.Page) ([]store.AuctionRecord, string, error) {
+		return history.ListAuctions(store.AuctionFilter{Blocks: blocks}, page)
+	}, func(record store.AuctionRecord) {
+		last = &record
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to load last auction: %w", err)
+	}
+	var lastWinner *auction.SignedBid
+	if last != nil {
+		result.LastAuctionBlock, lastWinner = last.L1Block, last.Winner
+	}
+
+	// Bids for a later block than the last result were received by an auction the crash interrupted
+	var interrupted uint64
+	err = each(func(page store.Page) ([]store.BidRecord, string, error) {
+		return history.ListBids(store.BidFilter{Blocks: store.BlockRange{From: max(config.FromBlock, result.LastAuctionBlock+1)}}, page)
+	}, func(record store.BidRecord) {
+		interrupted = max(interrupted, record.Bid.L1Block.Uint64())
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to load bids: %w", err)
+	}
+	if interrupted > 0 {
+		logger.Warn("closing auction interrupted by restart with no winner", "blockNumber", interrupted)
+		if err := history.SaveAuctionResult(interrupted, nil, time.Now()); err != nil {
+			return result, fmt.Errorf("failed to close interrupted auction: %w", err)
+		}
+		result.InterruptedAuctionBlock = interrupted
+		result.LastAuctionBlock, lastWinner = interrupted, nil
+	}
+
+	var unsettled []auction.SignedBid
+	err = each(func(page store.Page) ([]store.AuctionRecord, string, error) {
+		return history.ListAuctions(store.AuctionFilter{Blocks: blocks, Unsettled: true}, page)
+	}, func(record store.AuctionRecord) {
+		unsettled = append(unsettled, *record.Winner)
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to load unsettled auctions: %w", err)
+	}
+	result.UnsettledAuctions = len(unsettled)
+	auctions.Restore(result.LastAuctionBlock, lastWinner, unsettled)
+
+	if coordinator != nil {
+		// Listed by target block, so commitments are restored after the ones they renew
+		err = each(func(page store.Page) ([]store.CommitmentRecord, string, error) {
+			return history.ListCommitments(store.CommitmentFilter{Blocks: blocks}, page)
+		}, func(record store.CommitmentRecord) {
+			coordinator.Restore(record.Commitment, record.State)
+			result.Commitments++
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to load commitments: %w", err)
+		}
+	}
+
+	logger.Info("recovered state from history",
+		"lastAuctionBlock", result.LastAuctionBlock,
+		"interruptedAuctionBlock", result.InterruptedAuctionBlock,
+		"unsettledAuctions", result.UnsettledAuctions,
+		"commitments", result.Commitments,
+	)
+	return result, nil
+}
+
+// Visits every record of a paginated listing
+func each[T any](list func(page store.Page) ([]T, string, error), visit func(T)) error {
+	page := store.Page{Limit: store.MaxPageLimit}
+	for {
+		records, next, err := list(page)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			visit(record)
+		}
+		if next == "" {
+			return nil
+		}
+		page.Cursor = next
+	}
+}
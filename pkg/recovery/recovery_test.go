@@ -0,0 +1,93 @@
+package recovery_test
+
+import (
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/recovery"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuctionRestorer struct {
+	lastAuctionBlock uint64
+	lastWinner       *auction.SignedBid
+	unsettled        []auction.SignedBid
+}
+
+func (m *mockAuctionRestorer) Restore(lastAuctionBlock uint64, lastWinner *auction.SignedBid, unsettled []auction.SignedBid) {
+	m.lastAuctionBlock, m.lastWinner, m.unsettled = lastAuctionBlock, lastWinner, unsettled
+}
+
+type mockCommitmentRestorer struct {
+	restored []commitment.State
+}
+
+func (m *mockCommitmentRestorer) Restore(c commitment.Commitment, state commitment.State) {
+	m.restored = append(m.restored, state)
+}
+
+func TestRecover(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	settled := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	unsettled := auction.MustCreateSignedBid(big.NewInt(44), big.NewInt(101), pk)
+	require.NoError(t, history.SaveAuctionResult(100, settled, time.Now()))
+	require.NoError(t, history.SaveSettlement(100, common.Hash{0x01}, time.Now()))
+	require.NoError(t, history.SaveAuctionResult(101, unsettled, time.Now()))
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(102),
+		ExpiryBlock:     big.NewInt(103),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	require.NoError(t, history.SaveCommitment(*c, commitment.StateActive))
+
+	auctions := &mockAuctionRestorer{}
+	commitments := &mockCommitmentRestorer{}
+	result, err := recovery.Recover(slog.Default(), recovery.Config{}, history, auctions, commitments)
+	require.NoError(t, err)
+	require.Equal(t, recovery.Result{LastAuctionBlock: 101, UnsettledAuctions: 1, Commitments: 1}, result)
+	require.Equal(t, uint64(101), auctions.lastAuctionBlock)
+	require.Equal(t, unsettled, auctions.lastWinner)
+	require.Equal(t, []auction.SignedBid{*unsettled}, auctions.unsettled)
+	require.Equal(t, []commitment.State{commitment.StateActive}, commitments.restored)
+
+	// History before FromBlock isn't scanned
+	result, err = recovery.Recover(slog.Default(), recovery.Config{FromBlock: 102}, history, auctions, nil)
+	require.NoError(t, err)
+	require.Equal(t, recovery.Result{}, result)
+}
+
+func TestRecoverClosesInterruptedAuction(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	require.NoError(t, history.SaveAuctionResult(100, nil, time.Now()))
+	// The auction for block 101 received bids, but the crash happened before it closed
+	require.NoError(t, history.SaveBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(101), pk), time.Now()))
+
+	auctions := &mockAuctionRestorer{}
+	result, err := recovery.Recover(slog.Default(), recovery.Config{}, history, auctions, nil)
+	require.NoError(t, err)
+	require.Equal(t, recovery.Result{LastAuctionBlock: 101, InterruptedAuctionBlock: 101}, result)
+	require.Nil(t, auctions.lastWinner)
+	require.Empty(t, auctions.unsettled)
+
+	records, _, err := history.ListAuctions(store.AuctionFilter{Blocks: store.BlockRange{From: 101}}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Nil(t, records[0].Winner)
+
+	// Recovering again finds nothing interrupted
+	result, err = recovery.Recover(slog.Default(), recovery.Config{}, history, auctions, nil)
+	require.NoError(t, err)
+	require.Zero(t, result.InterruptedAuctionBlock)
+}
@@ -0,0 +1,108 @@
+package relayclient
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"blob-preconfs/pkg/award"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Largest award or key release accepted, well above a JSON encoded award or a block's released keys
+const maxAwardSize = 1 << 20
+
+// Receives awards the auctioneer posts to the relay's callback endpoint (see award.Notifier), asking accept
+// whether to take on each verified award of one of the relay's bids signed by one of auctioneers, then
+// counter-signing the answer with relayKey. The auctioneer falls back to the runner-up if the relay declines, or
+// doesn't answer in time, so accept must return quickly. Awards are delivered at least once, so accept may be
+// asked again for the same award.
+func AwardHandler(relayKey *ecdsa.PrivateKey, auctioneers []common.Address, accept func(*award.Award) bool) http.Handler {
+	return AwardHandlerWithKeys(relayKey, auctioneers, accept, nil)
+}
+
+// Like AwardHandler, also receiving the content keys of encrypted blobs targeting a block the relay accepted the
+// award of (see award.Release), which are opened with relayKey and passed to released by request ID. Releases are
+// delivered at least once, so released may be called again for the same block.
+func AwardHandlerWithKeys(relayKey *ecdsa.PrivateKey, auctioneers []common.Address, accept func(*award.Award) bool, released func(l1Block uint64, keys map[common.Hash][]byte)) http.Handler {
+	relay := crypto.PubkeyToAddress(relayKey.PublicKey)
+	trusted := make(map[common.Address]bool, len(auctioneers))
+	for _, auctioneer := range auctioneers {
+		trusted[auctioneer] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxAwardSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var kind struct {
+			Keys json.RawMessage `json:"keys"`
+		}
+		if json.Unmarshal(body, &kind) == nil && kind.Keys != nil {
+			serveRelease(w, body, relayKey, trusted, released)
+			return
+		}
+		var a award.Award
+		if err := json.Unmarshal(body, &a); err != nil {
+			http.Error(w, "invalid award: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case !trusted[a.Auctioneer]:
+			http.Error(w, "untrusted auctioneer", http.StatusForbidden)
+			return
+		case !a.Verify() || !a.Bid.Verify():
+			http.Error(w, "invalid signature", http.StatusBadRequest)
+			return
+		case a.Bid.Address != relay:
+			http.Error(w, "bid of another relay", http.StatusBadRequest)
+			return
+		}
+		ack, err := award.CreateSignedAck(a.Hash(), accept(&a), relayKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ack)
+	})
+}
+
+func serveRelease(w http.ResponseWriter, body []byte, relayKey *ecdsa.PrivateKey, trusted map[common.Address]bool, released func(uint64, map[common.Hash][]byte)) {
+	if released == nil {
+		http.Error(w, "key releases not accepted", http.StatusNotFound)
+		return
+	}
+	var release award.Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		http.Error(w, "invalid release: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch {
+	case !trusted[release.Auctioneer]:
+		http.Error(w, "untrusted auctioneer", http.StatusForbidden)
+		return
+	case !release.Verify():
+		http.Error(w, "invalid signature", http.StatusBadRequest)
+		return
+	}
+	keys := make(map[common.Hash][]byte, len(release.Keys))
+	for _, key := range release.Keys {
+		contentKey, err := key.Open(relayKey)
+		if err != nil {
+			http.Error(w, "key of request "+key.RequestID.Hex()+" not sealed to the relay", http.StatusBadRequest)
+			return
+		}
+		keys[key.RequestID] = contentKey
+	}
+	released(release.L1Block, keys)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,124 @@
+package relayclient_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/award"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/relayclient"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwardHandler(t *testing.T) {
+	relayKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auctioneerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	relay := crypto.PubkeyToAddress(relayKey.PublicKey)
+
+	var received []*award.Award
+	handler := relayclient.AwardHandler(relayKey, []common.Address{crypto.PubkeyToAddress(auctioneerKey.PublicKey)}, func(a *award.Award) bool {
+		received = append(received, a)
+		return a.Bid.L1Block.Int64() == 100
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	notifier, err := award.NewNotifier(slog.Default(), award.Config{Endpoints: map[common.Address]string{relay: server.URL}}, auctioneerKey)
+	require.NoError(t, err)
+	notifier.Notify(*auction.MustCreateSignedBid(big.NewInt(1000), big.NewInt(100), relayKey))
+	notifier.Notify(*auction.MustCreateSignedBid(big.NewInt(1000), big.NewInt(101), relayKey))
+	notifier.Close()
+	require.Len(t, received, 2)
+	d, _ := notifier.Delivery(100)
+	require.Equal(t, award.StatusAccepted, d.Status)
+	d, _ = notifier.Delivery(101)
+	require.Equal(t, award.StatusDefaulted, d.Status)
+	require.Equal(t, award.ReasonDeclined, d.Reason)
+
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	untrusted, err := award.CreateSignedAward(*auction.MustCreateSignedBid(big.NewInt(1000), big.NewInt(102), relayKey), time.Now(), otherKey)
+	require.NoError(t, err)
+	data, err := json.Marshal(untrusted)
+	require.NoError(t, err)
+	resp, err := http.Post(server.URL, "application/json", bytes.NewReader(data))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode, "awards signed by other auctioneers aren't acknowledged")
+	require.Len(t, received, 2)
+}
+
+func TestAwardHandlerReceivesReleasedKeys(t *testing.T) {
+	relayKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auctioneerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	userKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	relay := crypto.PubkeyToAddress(relayKey.PublicKey)
+
+	pool, err := intake.NewPool(slog.Default(), intake.Config{}, nil)
+	require.NoError(t, err)
+	pool.SetEscrowKey(auctioneerKey)
+	var blob kzg4844.Blob
+	copy(blob[1:], "rollup batch")
+	req, err := intake.CreateSignedEncryptedRequest([]kzg4844.Blob{blob}, true, big.NewInt(100), big.NewInt(1), &auctioneerKey.PublicKey, userKey)
+	require.NoError(t, err)
+	id, err := pool.Submit(*req)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	released := make(map[uint64]map[common.Hash][]byte)
+	handler := relayclient.AwardHandlerWithKeys(relayKey, []common.Address{crypto.PubkeyToAddress(auctioneerKey.PublicKey)},
+		func(a *award.Award) bool { return true },
+		func(l1Block uint64, keys map[common.Hash][]byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			released[l1Block] = keys
+		})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	notifier, err := award.NewNotifier(slog.Default(), award.Config{Endpoints: map[common.Address]string{relay: server.URL}}, auctioneerKey)
+	require.NoError(t, err)
+	notifier.SetKeyReleaser(pool)
+	notifier.Notify(*auction.MustCreateSignedBid(big.NewInt(1000), big.NewInt(100), relayKey))
+	notifier.Close()
+	d, _ := notifier.Delivery(100)
+	require.Equal(t, award.StatusAccepted, d.Status)
+	require.Equal(t, 1, d.KeysReleased)
+
+	require.Contains(t, released[100], id)
+	blobs, err := req.Decrypt(released[100][id])
+	require.NoError(t, err)
+	require.Equal(t, []kzg4844.Blob{blob}, blobs)
+
+	// Relays without a callback for keys don't acknowledge releases
+	withoutKeys := httptest.NewServer(relayclient.AwardHandler(relayKey, []common.Address{crypto.PubkeyToAddress(auctioneerKey.PublicKey)}, func(a *award.Award) bool { return true }))
+	defer withoutKeys.Close()
+	a, err := award.CreateSignedAward(*auction.MustCreateSignedBid(big.NewInt(1000), big.NewInt(100), relayKey), time.Now(), auctioneerKey)
+	require.NoError(t, err)
+	release, err := award.CreateSignedRelease(a, []intake.KeyRelease{}, auctioneerKey)
+	require.NoError(t, err)
+	require.True(t, release.Verify())
+	data, err := json.Marshal(release)
+	require.NoError(t, err)
+	resp, err := http.Post(withoutKeys.URL, "application/json", bytes.NewReader(data))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
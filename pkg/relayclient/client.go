@@ -0,0 +1,312 @@
+package relayclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/jsonrpc"
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrNoAuction            = auction.ErrNoActiveAuction
+	ErrStreamingUnsupported = errors.New("event streaming requires a websocket endpoint")
+	ErrInvalidReceipt       = errors.New("invalid bid receipt")
+)
+
+// Callbacks for auction events, any of which may be nil
+type Handlers struct {
+	// Called with every event before the handlers below, e.g. to drive a strategy.Bidder
+	OnEvent         func(ev auction.Event)
+	OnAuctionOpened func(l1Block *big.Int)
+	OnLeaderChanged func(leader *auction.SignedBid)
+	// Called when an auction closes with this relay's bid winning, or falls back to it after the winner defaulted
+	OnWon func(winner *auction.SignedBid)
+	// Called when an auction closes with another relay's bid winning, or no winner (nil)
+	OnLost func(l1Block *big.Int, winner *auction.SignedBid)
+	// Called when this relay's winning bid is settled on the settlement layer
+	OnSettled func(winner *auction.SignedBid, settlementTx common.Hash)
+	// Called when no block was proposed in the slot this relay won, with whether its win is refunded or carried over
+	OnSlotMissed func(winner *auction.SignedBid, outcome auction.SlotOutcome)
+	// Called when this relay's bond runs low with its pending wins, with the cap on its bids until it tops up, and
+	// when the cap lifts
+	OnBondLow      func(bond *auction.BondStatus)
+	OnBondRestored func(bond *auction.BondStatus)
+	// Called with the auctioneer's heartbeat every slot, unverified, see heartbeat.Monitor
+	OnHeartbeat func(h *auction.Heartbeat)
+}
+
+// Bids in the relay auction on behalf of one relay, over the auctioneer's JSON-RPC API
+type BidderClient struct {
+	logger     *slog.Logger
+	client     *rpc.Client
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+	websocket  bool
+}
+
+// Connects to endpoint, either http(s):// or ws(s)://. Event streaming needs a websocket endpoint.
+// Requests are signed with privateKey, which must be registered on the settlement layer.
+// The system roots and no client certificate are used for TLS if tlsConfig is nil.
+func NewBidderClient(
+	ctx context.Context,
+	logger *slog.Logger,
+	endpoint string,
+	privateKey *ecdsa.PrivateKey,
+	tlsConfig *tls.Config,
+) (*BidderClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	var opts []rpc.ClientOption
+	isWebsocket := u.Scheme == "ws" || u.Scheme == "wss"
+	switch {
+	case isWebsocket:
+		header, err := auth.Headers(u.Path, nil, privateKey)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			rpc.WithHeaders(header),
+			rpc.WithWebsocketDialer(websocket.Dialer{TLSClientConfig: tlsConfig, HandshakeTimeout: 10 * time.Second}),
+		)
+	case u.Scheme == "http" || u.Scheme == "https":
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.TLSClientConfig = tlsConfig
+		opts = append(opts, rpc.WithHTTPClient(&http.Client{Transport: &auth.Transport{PrivateKey: privateKey, Base: base}}))
+	default:
+		return nil, fmt.Errorf("invalid endpoint: unsupported scheme %q", u.Scheme)
+	}
+	client, err := rpc.DialOptions(ctx, endpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &BidderClient{
+		logger:     logger,
+		client:     client,
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		websocket:  isWebsocket,
+	}, nil
+}
+
+func (c *BidderClient) Close() {
+	c.client.Close()
+}
+
+// Address bids are signed with
+func (c *BidderClient) Address() common.Address {
+	return c.address
+}
+
+// Signs and submits a bid for the auction of l1Block, returning the submitted bid. Rejected bids fail with their
+// auction.RejectError, e.g. auction.ErrBelowReserve.
+func (c *BidderClient) Bid(ctx context.Context, amountWei *big.Int, l1Block *big.Int) (*auction.SignedBid, error) {
+	bid, err := auction.CreateSignedBid(amountWei, l1Block, c.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.client.CallContext(ctx, nil, "auction_submitBid", bid); err != nil {
+		return nil, jsonrpc.RejectionOf(err)
+	}
+	return bid, nil
+}
+
+// Signs and submits a bid like Bid, returning the auctioneer's receipt of when it arrived, proof the bid reached
+// it in time. Receipts not signed or not covering the bid fail with ErrInvalidReceipt, callers should check the
+// receipt's auctioneer is one they trust.
+func (c *BidderClient) BidWithReceipt(ctx context.Context, amountWei *big.Int, l1Block *big.Int) (*auction.SignedBid, *auction.Receipt, error) {
+	bid, err := auction.CreateSignedBid(amountWei, l1Block, c.privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	var receipt auction.Receipt
+	if err := c.client.CallContext(ctx, &receipt, "auction_submitBidWithReceipt", bid); err != nil {
+		return nil, nil, jsonrpc.RejectionOf(err)
+	}
+	if !receipt.Verify() || !receipt.Covers(*bid) {
+		return bid, nil, ErrInvalidReceipt
+	}
+	return bid, &receipt, nil
+}
+
+// Key the auctioneer opens sealed bids with, see BidSealed
+func (c *BidderClient) SealingKey(ctx context.Context) (*ecdsa.PublicKey, error) {
+	var key hexutil.Bytes
+	if err := c.client.CallContext(ctx, &key, "auction_getSealingKey"); err != nil {
+		return nil, err
+	}
+	return crypto.DecompressPubkey(key)
+}
+
+// Signs a bid for the auction of l1Block and submits it sealed to sealingKey, hidden from other relays and, with a
+// decryption committee, from the operator until the auction closes. Only the relay's last sealed bid for an
+// auction counts, and it never leads before the auction closes.
+func (c *BidderClient) BidSealed(ctx context.Context, amountWei *big.Int, l1Block *big.Int, sealingKey *ecdsa.PublicKey) (*sealed.EncryptedBid, error) {
+	bid, err := sealed.Seal(amountWei, l1Block, sealingKey, c.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.client.CallContext(ctx, nil, "auction_submitSealedBid", bid); err != nil {
+		return nil, jsonrpc.RejectionOf(err)
+	}
+	return bid, nil
+}
+
+// Current leading bid, ErrNoAuction if no auction is in progress
+func (c *BidderClient) Leader(ctx context.Context) (*auction.SignedBid, error) {
+	var leader auction.SignedBid
+	if err := c.client.CallContext(ctx, &leader, "auction_getCurrentBid"); err != nil {
+		return nil, jsonrpc.RejectionOf(err)
+	}
+	return &leader, nil
+}
+
+// Relay's escrow balance, pending debits and effective max bid. Bids above the max bid lack backing.
+func (c *BidderClient) Escrow(ctx context.Context) (*escrow.Balance, error) {
+	var balance escrow.Balance
+	if err := c.client.CallContext(ctx, &balance, "auction_getEscrow", c.address); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// Polls the leading bid every interval, calling handle when it changes, until ctx is done.
+// For endpoints without websocket support, otherwise prefer Run.
+func (c *BidderClient) PollLeader(ctx context.Context, interval time.Duration, handle func(leader *auction.SignedBid)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last *auction.SignedBid
+	for {
+		leader, err := c.Leader(ctx)
+		switch {
+		case errors.Is(err, ErrNoAuction):
+		case err != nil:
+			c.logger.Debug("failed to poll leader", "error", err)
+		case last == nil || !sameBid(last, leader):
+			last = leader
+			handle(leader)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Streams auction events to handlers until ctx is done or the subscription fails
+func (c *BidderClient) Run(ctx context.Context, handlers Handlers) error {
+	if !c.websocket {
+		return ErrStreamingUnsupported
+	}
+	events := make(chan auction.Event, 64)
+	sub, err := c.client.Subscribe(ctx, "auction", events, "events", c.address)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case ev := <-events:
+			c.dispatch(ev, handlers)
+		}
+	}
+}
+
+// Streams the relay's own rejected bids to handle until ctx is done or the subscription fails
+func (c *BidderClient) Rejections(ctx context.Context, handle func(auction.Rejection)) error {
+	if !c.websocket {
+		return ErrStreamingUnsupported
+	}
+	rejections := make(chan auction.Rejection, 64)
+	sub, err := c.client.Subscribe(ctx, "auction", rejections, "rejections", c.address)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case rejection := <-rejections:
+			handle(rejection)
+		}
+	}
+}
+
+func (c *BidderClient) dispatch(ev auction.Event, handlers Handlers) {
+	won := ev.Bid != nil && ev.Bid.Address == c.address
+	if handlers.OnEvent != nil {
+		handlers.OnEvent(ev)
+	}
+	switch ev.Type {
+	case auction.EventAuctionOpened:
+		if handlers.OnAuctionOpened != nil {
+			handlers.OnAuctionOpened(ev.L1Block)
+		}
+	case auction.EventLeaderChanged:
+		if handlers.OnLeaderChanged != nil {
+			handlers.OnLeaderChanged(ev.Bid)
+		}
+	case auction.EventAuctionClosed:
+		if won && handlers.OnWon != nil {
+			handlers.OnWon(ev.Bid)
+		}
+		if !won && handlers.OnLost != nil {
+			handlers.OnLost(ev.L1Block, ev.Bid)
+		}
+	case auction.EventWinnerFallback:
+		if won && handlers.OnWon != nil {
+			handlers.OnWon(ev.Bid)
+		}
+	case auction.EventSettlement:
+		if won && ev.SettlementTx != nil && handlers.OnSettled != nil {
+			handlers.OnSettled(ev.Bid, *ev.SettlementTx)
+		}
+	case auction.EventSlotMissed:
+		if won && handlers.OnSlotMissed != nil {
+			handlers.OnSlotMissed(ev.Bid, ev.Outcome)
+		}
+	case auction.EventBondLow:
+		if ev.Bond != nil && ev.Bond.Relay == c.address && handlers.OnBondLow != nil {
+			handlers.OnBondLow(ev.Bond)
+		}
+	case auction.EventBondRestored:
+		if ev.Bond != nil && ev.Bond.Relay == c.address && handlers.OnBondRestored != nil {
+			handlers.OnBondRestored(ev.Bond)
+		}
+	case auction.EventHeartbeat:
+		if ev.Heartbeat != nil && handlers.OnHeartbeat != nil {
+			handlers.OnHeartbeat(ev.Heartbeat)
+		}
+	}
+}
+
+func sameBid(a, b *auction.SignedBid) bool {
+	return a.Address == b.Address && a.AmountWei.Cmp(b.AmountWei) == 0 && a.L1Block.Cmp(b.L1Block) == 0
+}
@@ -0,0 +1,315 @@
+package relayclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/jsonrpc"
+	"blob-preconfs/pkg/relayclient"
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackend struct {
+	mu         sync.Mutex
+	currentBid *auction.SignedBid
+	feed       event.Feed
+}
+
+func (m *mockBackend) SubmitBid(bid auction.SignedBid) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.currentBid = &bid
+	return nil
+}
+
+func (m *mockBackend) GetCurrentBid() (auction.SignedBid, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.currentBid == nil {
+		return auction.SignedBid{}, false
+	}
+	return *m.currentBid, true
+}
+
+func (m *mockBackend) SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription) {
+	ch := make(chan auction.Event, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+type mockRegistry struct{}
+
+func (mockRegistry) IsRegisteredOnSettlementLayer(common.Address) bool { return true }
+
+func startServer(t *testing.T, backend *mockBackend) string {
+	verifier := auth.NewVerifier(mockRegistry{}, 30*time.Second)
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, []string{"*"}, nil, verifier, nil)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	return server.Addr().String()
+}
+
+func TestBidAndLeader(t *testing.T) {
+	backend := &mockBackend{}
+	addr := startServer(t, backend)
+	pk, _ := crypto.GenerateKey()
+	client, err := relayclient.NewBidderClient(context.Background(), slog.Default(), "http://"+addr, pk, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Leader(context.Background())
+	require.ErrorIs(t, err, relayclient.ErrNoAuction)
+
+	bid, err := client.Bid(context.Background(), big.NewInt(43), big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, client.Address(), bid.Address)
+	leader, err := client.Leader(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, *bid, *leader)
+
+	require.ErrorIs(t, client.Run(context.Background(), relayclient.Handlers{}), relayclient.ErrStreamingUnsupported)
+}
+
+type mockEscrow struct{}
+
+func (mockEscrow) Balance(relay common.Address) (escrow.Balance, error) {
+	return escrow.Balance{Relay: relay, BalanceWei: big.NewInt(1000), PendingDebitsWei: big.NewInt(400), UnsettledWins: 1, MaxBidWei: big.NewInt(600)}, nil
+}
+
+func TestEscrow(t *testing.T) {
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, auth.NewVerifier(mockRegistry{}, 30*time.Second), nil)
+	require.NoError(t, err)
+	server.SetEscrow(mockEscrow{})
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	pk, _ := crypto.GenerateKey()
+	client, err := relayclient.NewBidderClient(context.Background(), slog.Default(), "http://"+server.Addr().String(), pk, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	balance, err := client.Escrow(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, client.Address(), balance.Relay, "the client's own balance")
+	require.Equal(t, big.NewInt(600), balance.MaxBidWei)
+}
+
+type mockSealed struct {
+	key       *ecdsa.PrivateKey
+	submitted []sealed.EncryptedBid
+}
+
+func (m *mockSealed) SubmitSealedBid(bid sealed.EncryptedBid) error {
+	m.submitted = append(m.submitted, bid)
+	return nil
+}
+
+func (m *mockSealed) SealingKey() *ecdsa.PublicKey {
+	return &m.key.PublicKey
+}
+
+func TestBidSealed(t *testing.T) {
+	sealingKey, _ := crypto.GenerateKey()
+	backend := &mockSealed{key: sealingKey}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, auth.NewVerifier(mockRegistry{}, 30*time.Second), nil)
+	require.NoError(t, err)
+	server.SetSealedBids(backend)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	pk, _ := crypto.GenerateKey()
+	client, err := relayclient.NewBidderClient(context.Background(), slog.Default(), "http://"+server.Addr().String(), pk, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	key, err := client.SealingKey(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, sealingKey.PublicKey, *key)
+	_, err = client.BidSealed(context.Background(), big.NewInt(42), big.NewInt(100), key)
+	require.NoError(t, err)
+	require.Len(t, backend.submitted, 1)
+	bid, err := sealed.Open(backend.submitted[0], sealingKey)
+	require.NoError(t, err)
+	require.Equal(t, client.Address(), bid.Address)
+	require.Equal(t, big.NewInt(42), bid.AmountWei)
+}
+
+type mockReceipts struct {
+	key *ecdsa.PrivateKey
+	// Receipts are signed for another bid if set
+	forge bool
+}
+
+func (m *mockReceipts) SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error) {
+	if m.forge {
+		bid.Signature = append([]byte{}, bid.Signature...)
+		bid.Signature[0] ^= 1
+	}
+	return auction.CreateSignedReceipt(bid, time.Now(), m.key)
+}
+
+func TestBidWithReceipt(t *testing.T) {
+	auctioneerKey, _ := crypto.GenerateKey()
+	backend := &mockReceipts{key: auctioneerKey}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, auth.NewVerifier(mockRegistry{}, 30*time.Second), nil)
+	require.NoError(t, err)
+	server.SetReceipts(backend)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	pk, _ := crypto.GenerateKey()
+	client, err := relayclient.NewBidderClient(context.Background(), slog.Default(), "http://"+server.Addr().String(), pk, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	bid, receipt, err := client.BidWithReceipt(context.Background(), big.NewInt(42), big.NewInt(100))
+	require.NoError(t, err)
+	require.True(t, receipt.Covers(*bid))
+	require.Equal(t, crypto.PubkeyToAddress(auctioneerKey.PublicKey), receipt.Auctioneer)
+
+	backend.forge = true
+	_, _, err = client.BidWithReceipt(context.Background(), big.NewInt(43), big.NewInt(100))
+	require.ErrorIs(t, err, relayclient.ErrInvalidReceipt)
+}
+
+func TestPollLeader(t *testing.T) {
+	backend := &mockBackend{}
+	addr := startServer(t, backend)
+	pk, _ := crypto.GenerateKey()
+	client, err := relayclient.NewBidderClient(context.Background(), slog.Default(), "http://"+addr, pk, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leaders := make(chan *auction.SignedBid, 10)
+	go client.PollLeader(ctx, 10*time.Millisecond, func(leader *auction.SignedBid) { leaders <- leader })
+
+	bid, err := client.Bid(context.Background(), big.NewInt(43), big.NewInt(100))
+	require.NoError(t, err)
+	select {
+	case leader := <-leaders:
+		require.Equal(t, *bid, *leader)
+	case <-time.After(time.Second):
+		t.Fatal("leader not polled")
+	}
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, leaders, "unchanged leader reported again")
+}
+
+func TestRun(t *testing.T) {
+	backend := &mockBackend{}
+	addr := startServer(t, backend)
+	pk, _ := crypto.GenerateKey()
+	client, err := relayclient.NewBidderClient(context.Background(), slog.Default(), "ws://"+addr, pk, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	other, _ := crypto.GenerateKey()
+	ours := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	theirs := auction.MustCreateSignedBid(big.NewInt(44), big.NewInt(101), other)
+	settlementTx := common.HexToHash("0x1234")
+
+	type result struct {
+		kind string
+		bid  *auction.SignedBid
+	}
+	results := make(chan result, 10)
+	var seen atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go client.Run(ctx, relayclient.Handlers{
+		OnEvent:         func(auction.Event) { seen.Add(1) },
+		OnAuctionOpened: func(*big.Int) { results <- result{kind: "opened"} },
+		OnLeaderChanged: func(leader *auction.SignedBid) { results <- result{"leader", leader} },
+		OnWon:           func(winner *auction.SignedBid) { results <- result{"won", winner} },
+		OnLost:          func(_ *big.Int, winner *auction.SignedBid) { results <- result{"lost", winner} },
+		OnSettled: func(winner *auction.SignedBid, tx common.Hash) {
+			require.Equal(t, settlementTx, tx)
+			results <- result{"settled", winner}
+		},
+		OnSlotMissed: func(winner *auction.SignedBid, outcome auction.SlotOutcome) {
+			require.Equal(t, auction.SlotRefund, outcome)
+			results <- result{"missed", winner}
+		},
+		OnBondLow:      func(*auction.BondStatus) { results <- result{kind: "bondLow"} },
+		OnBondRestored: func(*auction.BondStatus) { results <- result{kind: "bondRestored"} },
+	})
+	require.Eventually(t, func() bool {
+		return backend.feed.Send(auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)}) > 0
+	}, time.Second, 10*time.Millisecond)
+	for _, ev := range []auction.Event{
+		{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: ours},
+		{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: ours},
+		{Type: auction.EventSettlement, L1Block: big.NewInt(100), Bid: ours, SettlementTx: &settlementTx},
+		{Type: auction.EventAuctionClosed, L1Block: big.NewInt(101), Bid: theirs},
+		{Type: auction.EventSettlement, L1Block: big.NewInt(101), Bid: theirs, SettlementTx: &settlementTx},
+		{Type: auction.EventSlotMissed, L1Block: big.NewInt(101), Bid: theirs, Outcome: auction.SlotRefund},
+		{Type: auction.EventSlotMissed, L1Block: big.NewInt(102), Bid: ours, Outcome: auction.SlotRefund},
+		{Type: auction.EventBondLow, L1Block: big.NewInt(102), Bond: &auction.BondStatus{Relay: theirs.Address}},
+		{Type: auction.EventBondLow, L1Block: big.NewInt(102), Bond: &auction.BondStatus{Relay: ours.Address}},
+		{Type: auction.EventBondRestored, L1Block: big.NewInt(103), Bond: &auction.BondStatus{Relay: ours.Address}},
+	} {
+		backend.feed.Send(ev)
+	}
+
+	var got []string
+	for len(got) < 8 {
+		select {
+		case r := <-results:
+			got = append(got, r.kind)
+		case <-time.After(time.Second):
+			t.Fatalf("events not handled, got %v", got)
+		}
+	}
+	require.Equal(t, []string{"opened", "leader", "won", "settled", "lost", "missed", "bondLow", "bondRestored"}, got)
+	// Other relays' bond events aren't streamed
+	require.Eventually(t, func() bool { return seen.Load() == 10 }, time.Second, 10*time.Millisecond, "every event is passed on")
+}
+
+type mockRejections struct{ feed event.Feed }
+
+func (m *mockRejections) SubscribeRejections(bufferSize int) (<-chan auction.Rejection, event.Subscription) {
+	ch := make(chan auction.Rejection, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+func TestRejections(t *testing.T) {
+	rejections := &mockRejections{}
+	server, err := jsonrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, []string{"*"}, nil, auth.NewVerifier(mockRegistry{}, 30*time.Second), nil)
+	require.NoError(t, err)
+	server.SetRejections(rejections)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	pk, _ := crypto.GenerateKey()
+	client, err := relayclient.NewBidderClient(context.Background(), slog.Default(), "ws://"+server.Addr().String(), pk, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	received := make(chan auction.Rejection, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go client.Rejections(ctx, func(rejection auction.Rejection) { received <- rejection })
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.Eventually(t, func() bool {
+		return rejections.feed.Send(auction.Rejection{Bid: *bid, Code: auction.RejectWrongBlock}) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	select {
+	case rejection := <-received:
+		require.Equal(t, auction.RejectWrongBlock, rejection.Code)
+	case <-time.After(time.Second):
+		t.Fatal("rejection not received")
+	}
+}
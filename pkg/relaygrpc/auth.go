@@ -0,0 +1,111 @@
+package relaygrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+
+	"blob-preconfs/pkg/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata keys carrying the relay signature, the gRPC equivalent of the auth HTTP headers
+const (
+	timestampMetadataKey = "x-relay-timestamp"
+	signatureMetadataKey = "x-relay-signature"
+)
+
+// Unary requests are signed over their deterministic proto encoding, streams over the method only
+func signedBody(req any) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, nil
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
+
+func authenticate(ctx context.Context, verifier *auth.Verifier, method string, body []byte) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx, err := verifier.Authenticate(ctx, first(md.Get(timestampMetadataKey)), first(md.Get(signatureMetadataKey)), method, body)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return ctx, nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func unaryAuthInterceptor(verifier *auth.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		body, err := signedBody(req)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		ctx, err = authenticate(ctx, verifier, info.FullMethod, body)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamAuthInterceptor(verifier *auth.Verifier) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), verifier, info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func withSignature(ctx context.Context, method string, body []byte, privateKey *ecdsa.PrivateKey) (context.Context, error) {
+	header, err := auth.Headers(method, body, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx,
+		timestampMetadataKey, header.Get(auth.TimestampHeader),
+		signatureMetadataKey, header.Get(auth.SignatureHeader),
+	), nil
+}
+
+// Dial options signing every call with privateKey, for servers started with a verifier
+func WithRelayKey(privateKey *ecdsa.PrivateKey) []grpc.DialOption {
+	unary := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		body, err := signedBody(req)
+		if err != nil {
+			return err
+		}
+		if ctx, err = withSignature(ctx, method, body, privateKey); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := withSignature(ctx, method, nil, privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(unary), grpc.WithChainStreamInterceptor(stream)}
+}
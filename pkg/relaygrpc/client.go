@@ -0,0 +1,128 @@
+package relaygrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/listener"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+)
+
+// Wraps the generated client, converting to and from auction types
+type Client struct {
+	conn   *grpc.ClientConn
+	client RelayServiceClient
+}
+
+func NewClient(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn:   conn,
+		client: NewRelayServiceClient(conn),
+	}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Rejected bids fail with their auction.RejectError, e.g. auction.ErrBelowReserve
+func (c *Client) SubmitBid(ctx context.Context, bid *auction.SignedBid) error {
+	if _, err := c.client.SubmitBid(ctx, &SubmitBidRequest{Bid: bidToProto(bid)}); err != nil {
+		return submitError(err)
+	}
+	return nil
+}
+
+// Submits a bid, returning the auctioneer's signed receipt of when it arrived, which the caller should check is
+// signed by an auctioneer it trusts. Fails if the server doesn't acknowledge bids, see Server.SetReceipts.
+func (c *Client) SubmitBidWithReceipt(ctx context.Context, bid *auction.SignedBid) (*auction.Receipt, error) {
+	resp, err := c.client.SubmitBid(ctx, &SubmitBidRequest{Bid: bidToProto(bid)})
+	if err != nil {
+		return nil, submitError(err)
+	}
+	if resp.Receipt == nil {
+		return nil, errors.New("bid accepted without a receipt")
+	}
+	receipt, err := receiptFromProto(resp.Receipt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+func (c *Client) GetAuction(ctx context.Context, l1Block uint64) (listener.AuctionState, error) {
+	resp, err := c.client.GetAuction(ctx, &GetAuctionRequest{L1Block: l1Block})
+	if err != nil {
+		return listener.AuctionState{}, err
+	}
+	state := listener.AuctionState{L1Block: resp.L1Block, InProgress: resp.InProgress}
+	if resp.LeadingBid != nil {
+		if state.LeadingBid, err = bidFromProto(resp.LeadingBid); err != nil {
+			return listener.AuctionState{}, err
+		}
+	}
+	return state, nil
+}
+
+// Calls handle for each auction event, until ctx is cancelled or the stream fails. Bond events are only streamed for
+// relay, and a zero relay streams the authenticated relay's.
+func (c *Client) StreamAuctionEvents(ctx context.Context, relay common.Address, handle func(auction.Event)) error {
+	req := &StreamAuctionEventsRequest{}
+	if relay != (common.Address{}) {
+		req.Relay = relay.Bytes()
+	}
+	stream, err := c.client.StreamAuctionEvents(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+		ev, err := eventFromProto(msg)
+		if err != nil {
+			return err
+		}
+		handle(ev)
+	}
+}
+
+// Calls handle for each of relay's rejected bids, until ctx is cancelled or the stream fails. A zero relay
+// streams the authenticated relay's.
+func (c *Client) StreamBidRejections(ctx context.Context, relay common.Address, handle func(auction.Rejection)) error {
+	req := &StreamBidRejectionsRequest{}
+	if relay != (common.Address{}) {
+		req.Relay = relay.Bytes()
+	}
+	stream, err := c.client.StreamBidRejections(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return err
+		}
+		rejection, err := rejectionFromProto(msg)
+		if err != nil {
+			return err
+		}
+		handle(rejection)
+	}
+}
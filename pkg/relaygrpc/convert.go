@@ -0,0 +1,348 @@
+package relaygrpc
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func bidToProto(bid *auction.SignedBid) *SignedBid {
+	if bid == nil {
+		return nil
+	}
+	return &SignedBid{
+		AmountWei: bid.AmountWei.String(),
+		L1Block:   bid.L1Block.Uint64(),
+		Address:   bid.Address.Bytes(),
+		Signature: bid.Signature,
+	}
+}
+
+func bidFromProto(bid *SignedBid) (*auction.SignedBid, error) {
+	if bid == nil {
+		return nil, fmt.Errorf("missing bid")
+	}
+	amount, ok := new(big.Int).SetString(bid.AmountWei, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amountWei %q", bid.AmountWei)
+	}
+	if len(bid.Address) != common.AddressLength {
+		return nil, fmt.Errorf("invalid address length %d", len(bid.Address))
+	}
+	return &auction.SignedBid{
+		AmountWei: amount,
+		L1Block:   new(big.Int).SetUint64(bid.L1Block),
+		Address:   common.BytesToAddress(bid.Address),
+		Signature: bid.Signature,
+	}, nil
+}
+
+var eventTypes = map[auction.EventType]EventType{
+	auction.EventAuctionOpened:  EventType_EVENT_TYPE_AUCTION_OPENED,
+	auction.EventLeaderChanged:  EventType_EVENT_TYPE_LEADER_CHANGED,
+	auction.EventAuctionClosed:  EventType_EVENT_TYPE_AUCTION_CLOSED,
+	auction.EventSettlement:     EventType_EVENT_TYPE_SETTLEMENT,
+	auction.EventWinnerFallback: EventType_EVENT_TYPE_WINNER_FALLBACK,
+	auction.EventHeartbeat:      EventType_EVENT_TYPE_HEARTBEAT,
+	auction.EventBondLow:        EventType_EVENT_TYPE_BOND_LOW,
+	auction.EventBondRestored:   EventType_EVENT_TYPE_BOND_RESTORED,
+}
+
+var eventTypesFromProto = map[EventType]auction.EventType{
+	EventType_EVENT_TYPE_AUCTION_OPENED:  auction.EventAuctionOpened,
+	EventType_EVENT_TYPE_LEADER_CHANGED:  auction.EventLeaderChanged,
+	EventType_EVENT_TYPE_AUCTION_CLOSED:  auction.EventAuctionClosed,
+	EventType_EVENT_TYPE_SETTLEMENT:      auction.EventSettlement,
+	EventType_EVENT_TYPE_WINNER_FALLBACK: auction.EventWinnerFallback,
+	EventType_EVENT_TYPE_HEARTBEAT:       auction.EventHeartbeat,
+	EventType_EVENT_TYPE_BOND_LOW:        auction.EventBondLow,
+	EventType_EVENT_TYPE_BOND_RESTORED:   auction.EventBondRestored,
+}
+
+var fallbackStages = map[auction.FallbackStage]FallbackStage{
+	auction.StageAcceptance: FallbackStage_FALLBACK_STAGE_ACCEPTANCE,
+	auction.StagePayment:    FallbackStage_FALLBACK_STAGE_PAYMENT,
+}
+
+var fallbackStagesFromProto = map[FallbackStage]auction.FallbackStage{
+	FallbackStage_FALLBACK_STAGE_ACCEPTANCE: auction.StageAcceptance,
+	FallbackStage_FALLBACK_STAGE_PAYMENT:    auction.StagePayment,
+}
+
+func eventToProto(ev auction.Event) *AuctionEvent {
+	msg := &AuctionEvent{
+		Type:               eventTypes[ev.Type],
+		L1Block:            ev.L1Block.Uint64(),
+		Bid:                bidToProto(ev.Bid),
+		TimestampUnixMilli: ev.Timestamp.UnixMilli(),
+		Stage:              fallbackStages[ev.Stage],
+		Error:              ev.Error,
+		Heartbeat:          heartbeatToProto(ev.Heartbeat),
+		Bond:               bondToProto(ev.Bond),
+	}
+	if ev.SettlementTx != nil {
+		msg.SettlementTx = ev.SettlementTx.Bytes()
+	}
+	if ev.Failed != nil {
+		msg.Failed = ev.Failed.Bytes()
+	}
+	return msg
+}
+
+func eventFromProto(ev *AuctionEvent) (auction.Event, error) {
+	event := auction.Event{
+		Type:      eventTypesFromProto[ev.Type],
+		L1Block:   new(big.Int).SetUint64(ev.L1Block),
+		Timestamp: time.UnixMilli(ev.TimestampUnixMilli),
+		Stage:     fallbackStagesFromProto[ev.Stage],
+		Error:     ev.Error,
+	}
+	if len(ev.SettlementTx) > 0 {
+		tx := common.BytesToHash(ev.SettlementTx)
+		event.SettlementTx = &tx
+	}
+	if len(ev.Failed) > 0 {
+		if len(ev.Failed) != common.AddressLength {
+			return auction.Event{}, fmt.Errorf("invalid failed address length %d", len(ev.Failed))
+		}
+		failed := common.BytesToAddress(ev.Failed)
+		event.Failed = &failed
+	}
+	if ev.Bond != nil {
+		bond, err := bondFromProto(ev.Bond)
+		if err != nil {
+			return auction.Event{}, err
+		}
+		event.Bond = bond
+	}
+	if ev.Heartbeat != nil {
+		heartbeat, err := heartbeatFromProto(ev.Heartbeat)
+		if err != nil {
+			return auction.Event{}, err
+		}
+		event.Heartbeat = heartbeat
+	}
+	if ev.Bid != nil {
+		bid, err := bidFromProto(ev.Bid)
+		if err != nil {
+			return auction.Event{}, err
+		}
+		event.Bid = bid
+	}
+	return event, nil
+}
+
+func heartbeatToProto(h *auction.Heartbeat) *Heartbeat {
+	if h == nil {
+		return nil
+	}
+	return &Heartbeat{
+		Slot:            h.Slot,
+		L1Block:         h.L1Block,
+		StateHash:       h.StateHash.Bytes(),
+		Paused:          h.Paused,
+		Version:         h.Version,
+		SentAtUnixMilli: h.SentAt,
+		Auctioneer:      h.Auctioneer.Bytes(),
+		Signature:       h.Signature,
+	}
+}
+
+func heartbeatFromProto(h *Heartbeat) (*auction.Heartbeat, error) {
+	if len(h.StateHash) != common.HashLength {
+		return nil, fmt.Errorf("invalid state hash length %d", len(h.StateHash))
+	}
+	if len(h.Auctioneer) != common.AddressLength {
+		return nil, fmt.Errorf("invalid auctioneer length %d", len(h.Auctioneer))
+	}
+	return &auction.Heartbeat{
+		Slot:       h.Slot,
+		L1Block:    h.L1Block,
+		StateHash:  common.BytesToHash(h.StateHash),
+		Paused:     h.Paused,
+		Version:    h.Version,
+		SentAt:     h.SentAtUnixMilli,
+		Auctioneer: common.BytesToAddress(h.Auctioneer),
+		Signature:  h.Signature,
+	}, nil
+}
+
+func bondToProto(bond *auction.BondStatus) *BondStatus {
+	if bond == nil {
+		return nil
+	}
+	return &BondStatus{
+		Relay:            bond.Relay.Bytes(),
+		BalanceWei:       amountToProto(bond.BalanceWei),
+		PendingDebitsWei: amountToProto(bond.PendingDebitsWei),
+		UnsettledWins:    uint64(bond.UnsettledWins),
+		CapWei:           amountToProto(bond.CapWei),
+	}
+}
+
+func bondFromProto(bond *BondStatus) (*auction.BondStatus, error) {
+	if len(bond.Relay) != common.AddressLength {
+		return nil, fmt.Errorf("invalid relay length %d", len(bond.Relay))
+	}
+	status := &auction.BondStatus{Relay: common.BytesToAddress(bond.Relay), UnsettledWins: int(bond.UnsettledWins)}
+	var err error
+	if status.BalanceWei, err = amountFromProto("balanceWei", bond.BalanceWei); err != nil {
+		return nil, err
+	}
+	if status.PendingDebitsWei, err = amountFromProto("pendingDebitsWei", bond.PendingDebitsWei); err != nil {
+		return nil, err
+	}
+	if status.CapWei, err = amountFromProto("capWei", bond.CapWei); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Decimal string of an optional amount, empty if nil
+func amountToProto(amount *big.Int) string {
+	if amount == nil {
+		return ""
+	}
+	return amount.String()
+}
+
+func amountFromProto(name string, amount string) (*big.Int, error) {
+	if amount == "" {
+		return nil, nil
+	}
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s %q", name, amount)
+	}
+	return value, nil
+}
+
+func receiptToProto(r *auction.Receipt) *BidReceipt {
+	return &BidReceipt{
+		BidHash:            r.BidHash.Bytes(),
+		L1Block:            r.L1Block,
+		ReceivedAtUnixNano: r.ReceivedAt,
+		Auctioneer:         r.Auctioneer.Bytes(),
+		Signature:          r.Signature,
+	}
+}
+
+func receiptFromProto(r *BidReceipt) (*auction.Receipt, error) {
+	if len(r.BidHash) != common.HashLength {
+		return nil, fmt.Errorf("invalid bid hash length %d", len(r.BidHash))
+	}
+	if len(r.Auctioneer) != common.AddressLength {
+		return nil, fmt.Errorf("invalid auctioneer length %d", len(r.Auctioneer))
+	}
+	return &auction.Receipt{
+		BidHash:    common.BytesToHash(r.BidHash),
+		L1Block:    r.L1Block,
+		ReceivedAt: r.ReceivedAtUnixNano,
+		Auctioneer: common.BytesToAddress(r.Auctioneer),
+		Signature:  r.Signature,
+	}, nil
+}
+
+var rejectCodes = map[auction.RejectCode]RejectCode{
+	auction.RejectNoAuction:        RejectCode_REJECT_CODE_NO_AUCTION,
+	auction.RejectWrongBlock:       RejectCode_REJECT_CODE_WRONG_BLOCK,
+	auction.RejectInvalidSignature: RejectCode_REJECT_CODE_INVALID_SIGNATURE,
+	auction.RejectDenied:           RejectCode_REJECT_CODE_DENIED,
+	auction.RejectNotAllowed:       RejectCode_REJECT_CODE_NOT_ALLOWED,
+	auction.RejectNotRegistered:    RejectCode_REJECT_CODE_NOT_REGISTERED,
+	auction.RejectDuplicate:        RejectCode_REJECT_CODE_DUPLICATE,
+	auction.RejectOutbid:           RejectCode_REJECT_CODE_OUTBID,
+	auction.RejectUncovered:        RejectCode_REJECT_CODE_UNCOVERED,
+	auction.RejectBelowReserve:     RejectCode_REJECT_CODE_BELOW_RESERVE,
+	auction.RejectOverQuota:        RejectCode_REJECT_CODE_OVER_QUOTA,
+	auction.RejectOverBondCap:      RejectCode_REJECT_CODE_OVER_BOND_CAP,
+}
+
+var rejectCodesFromProto = map[RejectCode]auction.RejectCode{
+	RejectCode_REJECT_CODE_NO_AUCTION:        auction.RejectNoAuction,
+	RejectCode_REJECT_CODE_WRONG_BLOCK:       auction.RejectWrongBlock,
+	RejectCode_REJECT_CODE_INVALID_SIGNATURE: auction.RejectInvalidSignature,
+	RejectCode_REJECT_CODE_DENIED:            auction.RejectDenied,
+	RejectCode_REJECT_CODE_NOT_ALLOWED:       auction.RejectNotAllowed,
+	RejectCode_REJECT_CODE_NOT_REGISTERED:    auction.RejectNotRegistered,
+	RejectCode_REJECT_CODE_DUPLICATE:         auction.RejectDuplicate,
+	RejectCode_REJECT_CODE_OUTBID:            auction.RejectOutbid,
+	RejectCode_REJECT_CODE_UNCOVERED:         auction.RejectUncovered,
+	RejectCode_REJECT_CODE_BELOW_RESERVE:     auction.RejectBelowReserve,
+	RejectCode_REJECT_CODE_OVER_QUOTA:        auction.RejectOverQuota,
+	RejectCode_REJECT_CODE_OVER_BOND_CAP:     auction.RejectOverBondCap,
+}
+
+func rejectionToProto(rejection auction.Rejection) *BidRejection {
+	return &BidRejection{
+		Bid:                bidToProto(&rejection.Bid),
+		Code:               rejectCodes[rejection.Code],
+		Reason:             rejection.Reason,
+		Leader:             bidToProto(rejection.Leader),
+		TimestampUnixMilli: rejection.Timestamp.UnixMilli(),
+	}
+}
+
+func rejectionFromProto(msg *BidRejection) (auction.Rejection, error) {
+	bid, err := bidFromProto(msg.Bid)
+	if err != nil {
+		return auction.Rejection{}, err
+	}
+	rejection := auction.Rejection{
+		Bid:       *bid,
+		Code:      rejectCodesFromProto[msg.Code],
+		Reason:    msg.Reason,
+		Timestamp: time.UnixMilli(msg.TimestampUnixMilli),
+	}
+	if msg.Leader != nil {
+		if rejection.Leader, err = bidFromProto(msg.Leader); err != nil {
+			return auction.Rejection{}, err
+		}
+	}
+	return rejection, nil
+}
+
+// Domain of the google.rpc.ErrorInfo detail carrying a rejected bid's code, e.g. "belowReserve", as its reason
+const rejectDomain = "auction.blob-preconfs"
+
+var rejectStatusCodes = map[auction.RejectCode]codes.Code{
+	auction.RejectInvalidSignature: codes.InvalidArgument,
+	auction.RejectDenied:           codes.PermissionDenied,
+	auction.RejectNotAllowed:       codes.PermissionDenied,
+	auction.RejectNotRegistered:    codes.PermissionDenied,
+}
+
+// Status of a bid submission error. Rejected bids carry their code in an ErrorInfo detail, and fail with
+// FailedPrecondition unless the bidder may not bid at all.
+func submitStatus(err error) error {
+	code, ok := auction.RejectCodeOf(err)
+	if !ok {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	statusCode, ok := rejectStatusCodes[code]
+	if !ok {
+		statusCode = codes.FailedPrecondition
+	}
+	st, detailErr := status.New(statusCode, err.Error()).WithDetails(&errdetails.ErrorInfo{Reason: string(code), Domain: rejectDomain})
+	if detailErr != nil {
+		return status.Error(statusCode, err.Error())
+	}
+	return st.Err()
+}
+
+// Rejection error of a bid submission's status, or err if it isn't one
+func submitError(err error) error {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == rejectDomain {
+			return auction.RejectCode(info.Reason).Err()
+		}
+	}
+	return err
+}
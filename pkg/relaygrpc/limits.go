@@ -0,0 +1,58 @@
+package relaygrpc
+
+import (
+	"context"
+
+	"blob-preconfs/pkg/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Server options for NewServer enforcing limits on every connection: messages larger than MaxMessageSize are
+// refused, and calls and streamed messages beyond the connection's rate fail with RESOURCE_EXHAUSTED. Checked
+// before calls are authenticated.
+func WithLimits(limits ratelimit.TransportConfig) []grpc.ServerOption {
+	opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(limits.MaxMessageSize)}
+	if limits.PerConn.Rate <= 0 {
+		return opts
+	}
+	limiter := ratelimit.NewConnLimiter(limits.PerConn)
+	return append(opts,
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := allowMessage(ctx, limiter); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, &limitedStream{ServerStream: ss, limiter: limiter})
+		}),
+	)
+}
+
+func allowMessage(ctx context.Context, limiter *ratelimit.ConnLimiter) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if err := limiter.Allow(p.Addr.String()); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return nil
+}
+
+// Counts each message received on the stream, its request included, against the connection's rate
+type limitedStream struct {
+	grpc.ServerStream
+	limiter *ratelimit.ConnLimiter
+}
+
+func (s *limitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return allowMessage(s.Context(), s.limiter)
+}
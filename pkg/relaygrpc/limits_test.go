@@ -0,0 +1,50 @@
+package relaygrpc_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/ratelimit"
+	"blob-preconfs/pkg/relaygrpc"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestLimits(t *testing.T) {
+	backend := &mockBackend{auctions: map[uint64]listener.AuctionState{100: {L1Block: 100, InProgress: true}}}
+	limits := ratelimit.TransportConfig{MaxMessageSize: 1024, PerConn: ratelimit.Config{Rate: 0.1, Burst: 3}}
+	server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, nil, nil, nil, relaygrpc.WithLimits(limits)...)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	dial := func() *relaygrpc.Client {
+		client, err := relaygrpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	pk, _ := crypto.GenerateKey()
+	oversized := *auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	oversized.Signature = make([]byte, 1024)
+	require.Equal(t, codes.ResourceExhausted, status.Code(dial().SubmitBid(context.Background(), &oversized)))
+	require.Empty(t, backend.submitted)
+
+	client := dial()
+	for i := 0; i < 3; i++ {
+		_, err := client.GetAuction(context.Background(), 100)
+		require.NoError(t, err)
+	}
+	_, err := client.GetAuction(context.Background(), 100)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), "connection's message rate exceeded")
+	_, err = dial().GetAuction(context.Background(), 100)
+	require.NoError(t, err, "other connections unaffected")
+}
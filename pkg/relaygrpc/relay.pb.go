@@ -0,0 +1,1503 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: relay.proto
+
+package relaygrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED    EventType = 0
+	EventType_EVENT_TYPE_AUCTION_OPENED EventType = 1
+	EventType_EVENT_TYPE_LEADER_CHANGED EventType = 2
+	EventType_EVENT_TYPE_AUCTION_CLOSED EventType = 3
+	EventType_EVENT_TYPE_SETTLEMENT     EventType = 4
+	// The winner failed and the auction fell back to the next highest bid
+	EventType_EVENT_TYPE_WINNER_FALLBACK EventType = 5
+	// The auctioneer's signed heartbeat, once per beacon chain slot
+	EventType_EVENT_TYPE_HEARTBEAT EventType = 6
+	// The relay's bond runs low with its pending wins, and its bids are capped until it tops up
+	EventType_EVENT_TYPE_BOND_LOW EventType = 7
+	// The relay's bids are no longer capped
+	EventType_EVENT_TYPE_BOND_RESTORED EventType = 8
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_AUCTION_OPENED",
+		2: "EVENT_TYPE_LEADER_CHANGED",
+		3: "EVENT_TYPE_AUCTION_CLOSED",
+		4: "EVENT_TYPE_SETTLEMENT",
+		5: "EVENT_TYPE_WINNER_FALLBACK",
+		6: "EVENT_TYPE_HEARTBEAT",
+		7: "EVENT_TYPE_BOND_LOW",
+		8: "EVENT_TYPE_BOND_RESTORED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":     0,
+		"EVENT_TYPE_AUCTION_OPENED":  1,
+		"EVENT_TYPE_LEADER_CHANGED":  2,
+		"EVENT_TYPE_AUCTION_CLOSED":  3,
+		"EVENT_TYPE_SETTLEMENT":      4,
+		"EVENT_TYPE_WINNER_FALLBACK": 5,
+		"EVENT_TYPE_HEARTBEAT":       6,
+		"EVENT_TYPE_BOND_LOW":        7,
+		"EVENT_TYPE_BOND_RESTORED":   8,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_relay_proto_enumTypes[0].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_relay_proto_enumTypes[0]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{0}
+}
+
+// Stage of the hand-off to settlement a winner failed at
+type FallbackStage int32
+
+const (
+	FallbackStage_FALLBACK_STAGE_UNSPECIFIED FallbackStage = 0
+	// The winner declined its award or didn't accept it in time
+	FallbackStage_FALLBACK_STAGE_ACCEPTANCE FallbackStage = 1
+	FallbackStage_FALLBACK_STAGE_PAYMENT    FallbackStage = 2
+)
+
+// Enum value maps for FallbackStage.
+var (
+	FallbackStage_name = map[int32]string{
+		0: "FALLBACK_STAGE_UNSPECIFIED",
+		1: "FALLBACK_STAGE_ACCEPTANCE",
+		2: "FALLBACK_STAGE_PAYMENT",
+	}
+	FallbackStage_value = map[string]int32{
+		"FALLBACK_STAGE_UNSPECIFIED": 0,
+		"FALLBACK_STAGE_ACCEPTANCE":  1,
+		"FALLBACK_STAGE_PAYMENT":     2,
+	}
+)
+
+func (x FallbackStage) Enum() *FallbackStage {
+	p := new(FallbackStage)
+	*p = x
+	return p
+}
+
+func (x FallbackStage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FallbackStage) Descriptor() protoreflect.EnumDescriptor {
+	return file_relay_proto_enumTypes[1].Descriptor()
+}
+
+func (FallbackStage) Type() protoreflect.EnumType {
+	return &file_relay_proto_enumTypes[1]
+}
+
+func (x FallbackStage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FallbackStage.Descriptor instead.
+func (FallbackStage) EnumDescriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{1}
+}
+
+type RejectCode int32
+
+const (
+	RejectCode_REJECT_CODE_UNSPECIFIED       RejectCode = 0
+	RejectCode_REJECT_CODE_NO_AUCTION        RejectCode = 1
+	RejectCode_REJECT_CODE_WRONG_BLOCK       RejectCode = 2
+	RejectCode_REJECT_CODE_INVALID_SIGNATURE RejectCode = 3
+	RejectCode_REJECT_CODE_DENIED            RejectCode = 4
+	RejectCode_REJECT_CODE_NOT_ALLOWED       RejectCode = 5
+	RejectCode_REJECT_CODE_NOT_REGISTERED    RejectCode = 6
+	RejectCode_REJECT_CODE_DUPLICATE         RejectCode = 7
+	RejectCode_REJECT_CODE_OUTBID            RejectCode = 8
+	RejectCode_REJECT_CODE_UNCOVERED         RejectCode = 9
+	RejectCode_REJECT_CODE_BELOW_RESERVE     RejectCode = 10
+	RejectCode_REJECT_CODE_OVER_QUOTA        RejectCode = 11
+	RejectCode_REJECT_CODE_OVER_BOND_CAP     RejectCode = 12
+)
+
+// Enum value maps for RejectCode.
+var (
+	RejectCode_name = map[int32]string{
+		0:  "REJECT_CODE_UNSPECIFIED",
+		1:  "REJECT_CODE_NO_AUCTION",
+		2:  "REJECT_CODE_WRONG_BLOCK",
+		3:  "REJECT_CODE_INVALID_SIGNATURE",
+		4:  "REJECT_CODE_DENIED",
+		5:  "REJECT_CODE_NOT_ALLOWED",
+		6:  "REJECT_CODE_NOT_REGISTERED",
+		7:  "REJECT_CODE_DUPLICATE",
+		8:  "REJECT_CODE_OUTBID",
+		9:  "REJECT_CODE_UNCOVERED",
+		10: "REJECT_CODE_BELOW_RESERVE",
+		11: "REJECT_CODE_OVER_QUOTA",
+		12: "REJECT_CODE_OVER_BOND_CAP",
+	}
+	RejectCode_value = map[string]int32{
+		"REJECT_CODE_UNSPECIFIED":       0,
+		"REJECT_CODE_NO_AUCTION":        1,
+		"REJECT_CODE_WRONG_BLOCK":       2,
+		"REJECT_CODE_INVALID_SIGNATURE": 3,
+		"REJECT_CODE_DENIED":            4,
+		"REJECT_CODE_NOT_ALLOWED":       5,
+		"REJECT_CODE_NOT_REGISTERED":    6,
+		"REJECT_CODE_DUPLICATE":         7,
+		"REJECT_CODE_OUTBID":            8,
+		"REJECT_CODE_UNCOVERED":         9,
+		"REJECT_CODE_BELOW_RESERVE":     10,
+		"REJECT_CODE_OVER_QUOTA":        11,
+		"REJECT_CODE_OVER_BOND_CAP":     12,
+	}
+)
+
+func (x RejectCode) Enum() *RejectCode {
+	p := new(RejectCode)
+	*p = x
+	return p
+}
+
+func (x RejectCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RejectCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_relay_proto_enumTypes[2].Descriptor()
+}
+
+func (RejectCode) Type() protoreflect.EnumType {
+	return &file_relay_proto_enumTypes[2]
+}
+
+func (x RejectCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RejectCode.Descriptor instead.
+func (RejectCode) EnumDescriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{2}
+}
+
+type SignedBid struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Decimal string, as amounts can exceed 64 bits
+	AmountWei string `protobuf:"bytes,1,opt,name=amount_wei,json=amountWei,proto3" json:"amount_wei,omitempty"`
+	L1Block   uint64 `protobuf:"varint,2,opt,name=l1_block,json=l1Block,proto3" json:"l1_block,omitempty"`
+	// 20 byte address of the bidding relay
+	Address []byte `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	// 65 byte secp256k1 signature, see auction.CreateSignedBid
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *SignedBid) Reset() {
+	*x = SignedBid{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignedBid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedBid) ProtoMessage() {}
+
+func (x *SignedBid) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedBid.ProtoReflect.Descriptor instead.
+func (*SignedBid) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SignedBid) GetAmountWei() string {
+	if x != nil {
+		return x.AmountWei
+	}
+	return ""
+}
+
+func (x *SignedBid) GetL1Block() uint64 {
+	if x != nil {
+		return x.L1Block
+	}
+	return 0
+}
+
+func (x *SignedBid) GetAddress() []byte {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *SignedBid) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type SubmitBidRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bid *SignedBid `protobuf:"bytes,1,opt,name=bid,proto3" json:"bid,omitempty"`
+}
+
+func (x *SubmitBidRequest) Reset() {
+	*x = SubmitBidRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitBidRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBidRequest) ProtoMessage() {}
+
+func (x *SubmitBidRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBidRequest.ProtoReflect.Descriptor instead.
+func (*SubmitBidRequest) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitBidRequest) GetBid() *SignedBid {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+type SubmitBidResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Unset unless the auctioneer acknowledges bids
+	Receipt *BidReceipt `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+}
+
+func (x *SubmitBidResponse) Reset() {
+	*x = SubmitBidResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitBidResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBidResponse) ProtoMessage() {}
+
+func (x *SubmitBidResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBidResponse.ProtoReflect.Descriptor instead.
+func (*SubmitBidResponse) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitBidResponse) GetReceipt() *BidReceipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+// The auctioneer's signed receipt of when a bid arrived, see auction.Receipt
+type BidReceipt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Bid's hash, see auction.SignedBid.Hash
+	BidHash            []byte `protobuf:"bytes,1,opt,name=bid_hash,json=bidHash,proto3" json:"bid_hash,omitempty"`
+	L1Block            uint64 `protobuf:"varint,2,opt,name=l1_block,json=l1Block,proto3" json:"l1_block,omitempty"`
+	ReceivedAtUnixNano int64  `protobuf:"varint,3,opt,name=received_at_unix_nano,json=receivedAtUnixNano,proto3" json:"received_at_unix_nano,omitempty"`
+	// 20 byte address of the auctioneer
+	Auctioneer []byte `protobuf:"bytes,4,opt,name=auctioneer,proto3" json:"auctioneer,omitempty"`
+	// 65 byte secp256k1 signature, see auction.CreateSignedReceipt
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *BidReceipt) Reset() {
+	*x = BidReceipt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BidReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BidReceipt) ProtoMessage() {}
+
+func (x *BidReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BidReceipt.ProtoReflect.Descriptor instead.
+func (*BidReceipt) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BidReceipt) GetBidHash() []byte {
+	if x != nil {
+		return x.BidHash
+	}
+	return nil
+}
+
+func (x *BidReceipt) GetL1Block() uint64 {
+	if x != nil {
+		return x.L1Block
+	}
+	return 0
+}
+
+func (x *BidReceipt) GetReceivedAtUnixNano() int64 {
+	if x != nil {
+		return x.ReceivedAtUnixNano
+	}
+	return 0
+}
+
+func (x *BidReceipt) GetAuctioneer() []byte {
+	if x != nil {
+		return x.Auctioneer
+	}
+	return nil
+}
+
+func (x *BidReceipt) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type StreamAuctionEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 20 byte address of the relay whose bond events to stream, as they reveal its balance. Defaults to the
+	// authenticated relay, which may only stream its own. Anonymous streams get no bond events.
+	Relay []byte `protobuf:"bytes,1,opt,name=relay,proto3" json:"relay,omitempty"`
+}
+
+func (x *StreamAuctionEventsRequest) Reset() {
+	*x = StreamAuctionEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamAuctionEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAuctionEventsRequest) ProtoMessage() {}
+
+func (x *StreamAuctionEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAuctionEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamAuctionEventsRequest) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamAuctionEventsRequest) GetRelay() []byte {
+	if x != nil {
+		return x.Relay
+	}
+	return nil
+}
+
+type AuctionEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    EventType `protobuf:"varint,1,opt,name=type,proto3,enum=relaygrpc.v1.EventType" json:"type,omitempty"`
+	L1Block uint64    `protobuf:"varint,2,opt,name=l1_block,json=l1Block,proto3" json:"l1_block,omitempty"`
+	// New leading bid for leader changes, winning bid for closed auctions, winner fallbacks and settlements. Unset
+	// if none.
+	Bid                *SignedBid `protobuf:"bytes,3,opt,name=bid,proto3" json:"bid,omitempty"`
+	TimestampUnixMilli int64      `protobuf:"varint,4,opt,name=timestamp_unix_milli,json=timestampUnixMilli,proto3" json:"timestamp_unix_milli,omitempty"`
+	// Settlement layer tx hash, for settlement events
+	SettlementTx []byte `protobuf:"bytes,5,opt,name=settlement_tx,json=settlementTx,proto3" json:"settlement_tx,omitempty"`
+	// 20 byte address of the winner that failed, the stage it failed at and why, for winner fallbacks
+	Failed []byte        `protobuf:"bytes,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	Stage  FallbackStage `protobuf:"varint,7,opt,name=stage,proto3,enum=relaygrpc.v1.FallbackStage" json:"stage,omitempty"`
+	Error  string        `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	// For heartbeats, whose l1_block is the heartbeat's latest auction
+	Heartbeat *Heartbeat `protobuf:"bytes,9,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+	// For bond events, whose l1_block is the auction the bond was checked at
+	Bond *BondStatus `protobuf:"bytes,10,opt,name=bond,proto3" json:"bond,omitempty"`
+}
+
+func (x *AuctionEvent) Reset() {
+	*x = AuctionEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuctionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuctionEvent) ProtoMessage() {}
+
+func (x *AuctionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuctionEvent.ProtoReflect.Descriptor instead.
+func (*AuctionEvent) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AuctionEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *AuctionEvent) GetL1Block() uint64 {
+	if x != nil {
+		return x.L1Block
+	}
+	return 0
+}
+
+func (x *AuctionEvent) GetBid() *SignedBid {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+func (x *AuctionEvent) GetTimestampUnixMilli() int64 {
+	if x != nil {
+		return x.TimestampUnixMilli
+	}
+	return 0
+}
+
+func (x *AuctionEvent) GetSettlementTx() []byte {
+	if x != nil {
+		return x.SettlementTx
+	}
+	return nil
+}
+
+func (x *AuctionEvent) GetFailed() []byte {
+	if x != nil {
+		return x.Failed
+	}
+	return nil
+}
+
+func (x *AuctionEvent) GetStage() FallbackStage {
+	if x != nil {
+		return x.Stage
+	}
+	return FallbackStage_FALLBACK_STAGE_UNSPECIFIED
+}
+
+func (x *AuctionEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuctionEvent) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+func (x *AuctionEvent) GetBond() *BondStatus {
+	if x != nil {
+		return x.Bond
+	}
+	return nil
+}
+
+// See auction.BondStatus
+type BondStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 20 byte address of the relay
+	Relay []byte `protobuf:"bytes,1,opt,name=relay,proto3" json:"relay,omitempty"`
+	// Decimal strings, as amounts can exceed 64 bits
+	BalanceWei       string `protobuf:"bytes,2,opt,name=balance_wei,json=balanceWei,proto3" json:"balance_wei,omitempty"`
+	PendingDebitsWei string `protobuf:"bytes,3,opt,name=pending_debits_wei,json=pendingDebitsWei,proto3" json:"pending_debits_wei,omitempty"`
+	UnsettledWins    uint64 `protobuf:"varint,4,opt,name=unsettled_wins,json=unsettledWins,proto3" json:"unsettled_wins,omitempty"`
+	// Highest bid accepted from the relay until it tops up, empty once restored
+	CapWei string `protobuf:"bytes,5,opt,name=cap_wei,json=capWei,proto3" json:"cap_wei,omitempty"`
+}
+
+func (x *BondStatus) Reset() {
+	*x = BondStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BondStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BondStatus) ProtoMessage() {}
+
+func (x *BondStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BondStatus.ProtoReflect.Descriptor instead.
+func (*BondStatus) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BondStatus) GetRelay() []byte {
+	if x != nil {
+		return x.Relay
+	}
+	return nil
+}
+
+func (x *BondStatus) GetBalanceWei() string {
+	if x != nil {
+		return x.BalanceWei
+	}
+	return ""
+}
+
+func (x *BondStatus) GetPendingDebitsWei() string {
+	if x != nil {
+		return x.PendingDebitsWei
+	}
+	return ""
+}
+
+func (x *BondStatus) GetUnsettledWins() uint64 {
+	if x != nil {
+		return x.UnsettledWins
+	}
+	return 0
+}
+
+func (x *BondStatus) GetCapWei() string {
+	if x != nil {
+		return x.CapWei
+	}
+	return ""
+}
+
+// See auction.Heartbeat
+type Heartbeat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Slot uint64 `protobuf:"varint,1,opt,name=slot,proto3" json:"slot,omitempty"`
+	// Latest auction closed and the hash of its bids, winner and commitments, zero if none has closed since the
+	// auctioneer started
+	L1Block         uint64 `protobuf:"varint,2,opt,name=l1_block,json=l1Block,proto3" json:"l1_block,omitempty"`
+	StateHash       []byte `protobuf:"bytes,3,opt,name=state_hash,json=stateHash,proto3" json:"state_hash,omitempty"`
+	Paused          bool   `protobuf:"varint,4,opt,name=paused,proto3" json:"paused,omitempty"`
+	Version         string `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	SentAtUnixMilli int64  `protobuf:"varint,6,opt,name=sent_at_unix_milli,json=sentAtUnixMilli,proto3" json:"sent_at_unix_milli,omitempty"`
+	// 20 byte address of the auctioneer
+	Auctioneer []byte `protobuf:"bytes,7,opt,name=auctioneer,proto3" json:"auctioneer,omitempty"`
+	// 65 byte secp256k1 signature, see auction.CreateSignedHeartbeat
+	Signature []byte `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Heartbeat) GetSlot() uint64 {
+	if x != nil {
+		return x.Slot
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetL1Block() uint64 {
+	if x != nil {
+		return x.L1Block
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetStateHash() []byte {
+	if x != nil {
+		return x.StateHash
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *Heartbeat) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetSentAtUnixMilli() int64 {
+	if x != nil {
+		return x.SentAtUnixMilli
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetAuctioneer() []byte {
+	if x != nil {
+		return x.Auctioneer
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type GetAuctionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	L1Block uint64 `protobuf:"varint,1,opt,name=l1_block,json=l1Block,proto3" json:"l1_block,omitempty"`
+}
+
+func (x *GetAuctionRequest) Reset() {
+	*x = GetAuctionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAuctionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuctionRequest) ProtoMessage() {}
+
+func (x *GetAuctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuctionRequest.ProtoReflect.Descriptor instead.
+func (*GetAuctionRequest) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetAuctionRequest) GetL1Block() uint64 {
+	if x != nil {
+		return x.L1Block
+	}
+	return 0
+}
+
+type GetAuctionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	L1Block    uint64 `protobuf:"varint,1,opt,name=l1_block,json=l1Block,proto3" json:"l1_block,omitempty"`
+	InProgress bool   `protobuf:"varint,2,opt,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"`
+	// Current leader if in progress, otherwise the winner. Unset if none.
+	LeadingBid *SignedBid `protobuf:"bytes,3,opt,name=leading_bid,json=leadingBid,proto3" json:"leading_bid,omitempty"`
+}
+
+func (x *GetAuctionResponse) Reset() {
+	*x = GetAuctionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAuctionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuctionResponse) ProtoMessage() {}
+
+func (x *GetAuctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuctionResponse.ProtoReflect.Descriptor instead.
+func (*GetAuctionResponse) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetAuctionResponse) GetL1Block() uint64 {
+	if x != nil {
+		return x.L1Block
+	}
+	return 0
+}
+
+func (x *GetAuctionResponse) GetInProgress() bool {
+	if x != nil {
+		return x.InProgress
+	}
+	return false
+}
+
+func (x *GetAuctionResponse) GetLeadingBid() *SignedBid {
+	if x != nil {
+		return x.LeadingBid
+	}
+	return nil
+}
+
+type StreamBidRejectionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 20 byte address of the relay. Defaults to the authenticated relay, which may only stream its own.
+	Relay []byte `protobuf:"bytes,1,opt,name=relay,proto3" json:"relay,omitempty"`
+}
+
+func (x *StreamBidRejectionsRequest) Reset() {
+	*x = StreamBidRejectionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamBidRejectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBidRejectionsRequest) ProtoMessage() {}
+
+func (x *StreamBidRejectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBidRejectionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamBidRejectionsRequest) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamBidRejectionsRequest) GetRelay() []byte {
+	if x != nil {
+		return x.Relay
+	}
+	return nil
+}
+
+type BidRejection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bid  *SignedBid `protobuf:"bytes,1,opt,name=bid,proto3" json:"bid,omitempty"`
+	Code RejectCode `protobuf:"varint,2,opt,name=code,proto3,enum=relaygrpc.v1.RejectCode" json:"code,omitempty"`
+	// Human readable reason, as recorded in the audit log
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Leading bid when the bid was rejected. Unset if none.
+	Leader             *SignedBid `protobuf:"bytes,4,opt,name=leader,proto3" json:"leader,omitempty"`
+	TimestampUnixMilli int64      `protobuf:"varint,5,opt,name=timestamp_unix_milli,json=timestampUnixMilli,proto3" json:"timestamp_unix_milli,omitempty"`
+}
+
+func (x *BidRejection) Reset() {
+	*x = BidRejection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_relay_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BidRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BidRejection) ProtoMessage() {}
+
+func (x *BidRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_relay_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BidRejection.ProtoReflect.Descriptor instead.
+func (*BidRejection) Descriptor() ([]byte, []int) {
+	return file_relay_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BidRejection) GetBid() *SignedBid {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+func (x *BidRejection) GetCode() RejectCode {
+	if x != nil {
+		return x.Code
+	}
+	return RejectCode_REJECT_CODE_UNSPECIFIED
+}
+
+func (x *BidRejection) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BidRejection) GetLeader() *SignedBid {
+	if x != nil {
+		return x.Leader
+	}
+	return nil
+}
+
+func (x *BidRejection) GetTimestampUnixMilli() int64 {
+	if x != nil {
+		return x.TimestampUnixMilli
+	}
+	return 0
+}
+
+var File_relay_proto protoreflect.FileDescriptor
+
+var file_relay_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x72,
+	0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x22, 0x7d, 0x0a, 0x09, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x42, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x77, 0x65, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x57, 0x65, 0x69, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x31, 0x5f, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x31, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x3d, 0x0a, 0x10, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x42, 0x69, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29,
+	0x0a, 0x03, 0x62, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x65,
+	0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x42, 0x69, 0x64, 0x52, 0x03, 0x62, 0x69, 0x64, 0x22, 0x47, 0x0a, 0x11, 0x53, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x42, 0x69, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32,
+	0x0a, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x69, 0x64, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x52, 0x07, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x70, 0x74, 0x22, 0xb3, 0x01, 0x0a, 0x0a, 0x42, 0x69, 0x64, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x69, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x62, 0x69, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x19, 0x0a, 0x08,
+	0x6c, 0x31, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
+	0x6c, 0x31, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x31, 0x0a, 0x15, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
+	0x41, 0x74, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x75,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x61, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x32, 0x0a, 0x1a, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x22, 0x9e, 0x03, 0x0a,
+	0x0c, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x72, 0x65,
+	0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x31,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x31,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x29, 0x0a, 0x03, 0x62, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x42, 0x69, 0x64, 0x52, 0x03, 0x62, 0x69, 0x64,
+	0x12, 0x30, 0x0a, 0x14, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x75, 0x6e,
+	0x69, 0x78, 0x5f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x55, 0x6e, 0x69, 0x78, 0x4d, 0x69, 0x6c,
+	0x6c, 0x69, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x74, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12,
+	0x31, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b,
+	0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61,
+	0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x35, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x72,
+	0x74, 0x62, 0x65, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x65,
+	0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74,
+	0x62, 0x65, 0x61, 0x74, 0x52, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12,
+	0x2c, 0x0a, 0x04, 0x62, 0x6f, 0x6e, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6f, 0x6e,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x04, 0x62, 0x6f, 0x6e, 0x64, 0x22, 0xb1, 0x01,
+	0x0a, 0x0a, 0x42, 0x6f, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x72, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x72, 0x65, 0x6c,
+	0x61, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x77, 0x65,
+	0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x57, 0x65, 0x69, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x64,
+	0x65, 0x62, 0x69, 0x74, 0x73, 0x5f, 0x77, 0x65, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x62, 0x69, 0x74, 0x73, 0x57, 0x65,
+	0x69, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x6e, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x64, 0x5f, 0x77,
+	0x69, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x75, 0x6e, 0x73, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x64, 0x57, 0x69, 0x6e, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x70, 0x5f,
+	0x77, 0x65, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x70, 0x57, 0x65,
+	0x69, 0x22, 0xf6, 0x01, 0x0a, 0x09, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x6c, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73,
+	0x6c, 0x6f, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x31, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x31, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x48, 0x61, 0x73, 0x68, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x2b, 0x0a, 0x12, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f,
+	0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x65, 0x6e,
+	0x74, 0x41, 0x74, 0x55, 0x6e, 0x69, 0x78, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x12, 0x1e, 0x0a, 0x0a,
+	0x61, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x61, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x2e, 0x0a, 0x11, 0x47, 0x65,
+	0x74, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x6c, 0x31, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x6c, 0x31, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x8a, 0x01, 0x0a, 0x12, 0x47,
+	0x65, 0x74, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x31, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x31, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1f, 0x0a, 0x0b,
+	0x69, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0a, 0x69, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x38, 0x0a,
+	0x0b, 0x6c, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x42, 0x69, 0x64, 0x52, 0x0a, 0x6c, 0x65, 0x61,
+	0x64, 0x69, 0x6e, 0x67, 0x42, 0x69, 0x64, 0x22, 0x32, 0x0a, 0x1a, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x42, 0x69, 0x64, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x22, 0xe2, 0x01, 0x0a, 0x0c,
+	0x42, 0x69, 0x64, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x03,
+	0x62, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x65, 0x6c, 0x61,
+	0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x42,
+	0x69, 0x64, 0x52, 0x03, 0x62, 0x69, 0x64, 0x12, 0x2c, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x2f, 0x0a,
+	0x06, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x42, 0x69, 0x64, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x30,
+	0x0a, 0x14, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x75, 0x6e, 0x69, 0x78,
+	0x5f, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x55, 0x6e, 0x69, 0x78, 0x4d, 0x69, 0x6c, 0x6c, 0x69,
+	0x2a, 0x90, 0x02, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a,
+	0x0a, 0x16, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x45, 0x56,
+	0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x55, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4c, 0x45, 0x41, 0x44, 0x45, 0x52, 0x5f, 0x43,
+	0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x55, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43,
+	0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x45, 0x56, 0x45, 0x4e, 0x54,
+	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x45, 0x54, 0x54, 0x4c, 0x45, 0x4d, 0x45, 0x4e, 0x54,
+	0x10, 0x04, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x57, 0x49, 0x4e, 0x4e, 0x45, 0x52, 0x5f, 0x46, 0x41, 0x4c, 0x4c, 0x42, 0x41, 0x43, 0x4b,
+	0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x48, 0x45, 0x41, 0x52, 0x54, 0x42, 0x45, 0x41, 0x54, 0x10, 0x06, 0x12, 0x17, 0x0a, 0x13,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x42, 0x4f, 0x4e, 0x44, 0x5f,
+	0x4c, 0x4f, 0x57, 0x10, 0x07, 0x12, 0x1c, 0x0a, 0x18, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x42, 0x4f, 0x4e, 0x44, 0x5f, 0x52, 0x45, 0x53, 0x54, 0x4f, 0x52, 0x45,
+	0x44, 0x10, 0x08, 0x2a, 0x6a, 0x0a, 0x0d, 0x46, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x53,
+	0x74, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x1a, 0x46, 0x41, 0x4c, 0x4c, 0x42, 0x41, 0x43, 0x4b,
+	0x5f, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x46, 0x41, 0x4c, 0x4c, 0x42, 0x41, 0x43, 0x4b,
+	0x5f, 0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x41, 0x4e, 0x43,
+	0x45, 0x10, 0x01, 0x12, 0x1a, 0x0a, 0x16, 0x46, 0x41, 0x4c, 0x4c, 0x42, 0x41, 0x43, 0x4b, 0x5f,
+	0x53, 0x54, 0x41, 0x47, 0x45, 0x5f, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x2a,
+	0x82, 0x03, 0x0a, 0x0a, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b,
+	0x0a, 0x17, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x52,
+	0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4e, 0x4f, 0x5f, 0x41, 0x55,
+	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x4a, 0x45, 0x43,
+	0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x42, 0x4c, 0x4f,
+	0x43, 0x4b, 0x10, 0x02, 0x12, 0x21, 0x0a, 0x1d, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43,
+	0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x53, 0x49, 0x47, 0x4e,
+	0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x4a, 0x45, 0x43,
+	0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10, 0x04, 0x12,
+	0x1b, 0x0a, 0x17, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4e,
+	0x4f, 0x54, 0x5f, 0x41, 0x4c, 0x4c, 0x4f, 0x57, 0x45, 0x44, 0x10, 0x05, 0x12, 0x1e, 0x0a, 0x1a,
+	0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f,
+	0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52, 0x45, 0x44, 0x10, 0x06, 0x12, 0x19, 0x0a, 0x15,
+	0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x55, 0x50, 0x4c,
+	0x49, 0x43, 0x41, 0x54, 0x45, 0x10, 0x07, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x4a, 0x45, 0x43,
+	0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4f, 0x55, 0x54, 0x42, 0x49, 0x44, 0x10, 0x08, 0x12,
+	0x19, 0x0a, 0x15, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x55,
+	0x4e, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x45, 0x44, 0x10, 0x09, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x45,
+	0x4a, 0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x42, 0x45, 0x4c, 0x4f, 0x57, 0x5f,
+	0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x10, 0x0a, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x45, 0x4a,
+	0x45, 0x43, 0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4f, 0x56, 0x45, 0x52, 0x5f, 0x51, 0x55,
+	0x4f, 0x54, 0x41, 0x10, 0x0b, 0x12, 0x1d, 0x0a, 0x19, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x5f,
+	0x43, 0x4f, 0x44, 0x45, 0x5f, 0x4f, 0x56, 0x45, 0x52, 0x5f, 0x42, 0x4f, 0x4e, 0x44, 0x5f, 0x43,
+	0x41, 0x50, 0x10, 0x0c, 0x32, 0xeb, 0x02, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x42,
+	0x69, 0x64, 0x12, 0x1e, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x42, 0x69, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x42, 0x69, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x13, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x41, 0x75, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x28, 0x2e, 0x72, 0x65, 0x6c,
+	0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x30, 0x01, 0x12, 0x4f, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1f, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x20, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x41, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x13, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x69, 0x64,
+	0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x28, 0x2e, 0x72, 0x65, 0x6c,
+	0x61, 0x79, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x42, 0x69, 0x64, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x69, 0x64, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x30, 0x01, 0x42, 0x1d, 0x5a, 0x1b, 0x62, 0x6c, 0x6f, 0x62, 0x2d, 0x70, 0x72, 0x65, 0x63, 0x6f,
+	0x6e, 0x66, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x67, 0x72, 0x70,
+	0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_relay_proto_rawDescOnce sync.Once
+	file_relay_proto_rawDescData = file_relay_proto_rawDesc
+)
+
+func file_relay_proto_rawDescGZIP() []byte {
+	file_relay_proto_rawDescOnce.Do(func() {
+		file_relay_proto_rawDescData = protoimpl.X.CompressGZIP(file_relay_proto_rawDescData)
+	})
+	return file_relay_proto_rawDescData
+}
+
+var file_relay_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_relay_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_relay_proto_goTypes = []any{
+	(EventType)(0),                     // 0: relaygrpc.v1.EventType
+	(FallbackStage)(0),                 // 1: relaygrpc.v1.FallbackStage
+	(RejectCode)(0),                    // 2: relaygrpc.v1.RejectCode
+	(*SignedBid)(nil),                  // 3: relaygrpc.v1.SignedBid
+	(*SubmitBidRequest)(nil),           // 4: relaygrpc.v1.SubmitBidRequest
+	(*SubmitBidResponse)(nil),          // 5: relaygrpc.v1.SubmitBidResponse
+	(*BidReceipt)(nil),                 // 6: relaygrpc.v1.BidReceipt
+	(*StreamAuctionEventsRequest)(nil), // 7: relaygrpc.v1.StreamAuctionEventsRequest
+	(*AuctionEvent)(nil),               // 8: relaygrpc.v1.AuctionEvent
+	(*BondStatus)(nil),                 // 9: relaygrpc.v1.BondStatus
+	(*Heartbeat)(nil),                  // 10: relaygrpc.v1.Heartbeat
+	(*GetAuctionRequest)(nil),          // 11: relaygrpc.v1.GetAuctionRequest
+	(*GetAuctionResponse)(nil),         // 12: relaygrpc.v1.GetAuctionResponse
+	(*StreamBidRejectionsRequest)(nil), // 13: relaygrpc.v1.StreamBidRejectionsRequest
+	(*BidRejection)(nil),               // 14: relaygrpc.v1.BidRejection
+}
+var file_relay_proto_depIdxs = []int32{
+	3,  // 0: relaygrpc.v1.SubmitBidRequest.bid:type_name -> relaygrpc.v1.SignedBid
+	6,  // 1: relaygrpc.v1.SubmitBidResponse.receipt:type_name -> relaygrpc.v1.BidReceipt
+	0,  // 2: relaygrpc.v1.AuctionEvent.type:type_name -> relaygrpc.v1.EventType
+	3,  // 3: relaygrpc.v1.AuctionEvent.bid:type_name -> relaygrpc.v1.SignedBid
+	1,  // 4: relaygrpc.v1.AuctionEvent.stage:type_name -> relaygrpc.v1.FallbackStage
+	10, // 5: relaygrpc.v1.AuctionEvent.heartbeat:type_name -> relaygrpc.v1.Heartbeat
+	9,  // 6: relaygrpc.v1.AuctionEvent.bond:type_name -> relaygrpc.v1.BondStatus
+	3,  // 7: relaygrpc.v1.GetAuctionResponse.leading_bid:type_name -> relaygrpc.v1.SignedBid
+	3,  // 8: relaygrpc.v1.BidRejection.bid:type_name -> relaygrpc.v1.SignedBid
+	2,  // 9: relaygrpc.v1.BidRejection.code:type_name -> relaygrpc.v1.RejectCode
+	3,  // 10: relaygrpc.v1.BidRejection.leader:type_name -> relaygrpc.v1.SignedBid
+	4,  // 11: relaygrpc.v1.RelayService.SubmitBid:input_type -> relaygrpc.v1.SubmitBidRequest
+	7,  // 12: relaygrpc.v1.RelayService.StreamAuctionEvents:input_type -> relaygrpc.v1.StreamAuctionEventsRequest
+	11, // 13: relaygrpc.v1.RelayService.GetAuction:input_type -> relaygrpc.v1.GetAuctionRequest
+	13, // 14: relaygrpc.v1.RelayService.StreamBidRejections:input_type -> relaygrpc.v1.StreamBidRejectionsRequest
+	5,  // 15: relaygrpc.v1.RelayService.SubmitBid:output_type -> relaygrpc.v1.SubmitBidResponse
+	8,  // 16: relaygrpc.v1.RelayService.StreamAuctionEvents:output_type -> relaygrpc.v1.AuctionEvent
+	12, // 17: relaygrpc.v1.RelayService.GetAuction:output_type -> relaygrpc.v1.GetAuctionResponse
+	14, // 18: relaygrpc.v1.RelayService.StreamBidRejections:output_type -> relaygrpc.v1.BidRejection
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_relay_proto_init() }
+func file_relay_proto_init() {
+	if File_relay_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_relay_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SignedBid); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitBidRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitBidResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*BidReceipt); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamAuctionEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*AuctionEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*BondStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Heartbeat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*GetAuctionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*GetAuctionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamBidRejectionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_relay_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*BidRejection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_relay_proto_rawDesc,
+			NumEnums:      3,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_relay_proto_goTypes,
+		DependencyIndexes: file_relay_proto_depIdxs,
+		EnumInfos:         file_relay_proto_enumTypes,
+		MessageInfos:      file_relay_proto_msgTypes,
+	}.Build()
+	File_relay_proto = out.File
+	file_relay_proto_rawDesc = nil
+	file_relay_proto_goTypes = nil
+	file_relay_proto_depIdxs = nil
+}
@@ -0,0 +1,279 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: relay.proto
+
+package relaygrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	RelayService_SubmitBid_FullMethodName           = "/relaygrpc.v1.RelayService/SubmitBid"
+	RelayService_StreamAuctionEvents_FullMethodName = "/relaygrpc.v1.RelayService/StreamAuctionEvents"
+	RelayService_GetAuction_FullMethodName          = "/relaygrpc.v1.RelayService/GetAuction"
+	RelayService_StreamBidRejections_FullMethodName = "/relaygrpc.v1.RelayService/StreamBidRejections"
+)
+
+// RelayServiceClient is the client API for RelayService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RelayServiceClient interface {
+	SubmitBid(ctx context.Context, in *SubmitBidRequest, opts ...grpc.CallOption) (*SubmitBidResponse, error)
+	// Streams auction lifecycle events until the client cancels
+	StreamAuctionEvents(ctx context.Context, in *StreamAuctionEventsRequest, opts ...grpc.CallOption) (RelayService_StreamAuctionEventsClient, error)
+	GetAuction(ctx context.Context, in *GetAuctionRequest, opts ...grpc.CallOption) (*GetAuctionResponse, error)
+	// Streams the relay's own rejected bids until the client cancels
+	StreamBidRejections(ctx context.Context, in *StreamBidRejectionsRequest, opts ...grpc.CallOption) (RelayService_StreamBidRejectionsClient, error)
+}
+
+type relayServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRelayServiceClient(cc grpc.ClientConnInterface) RelayServiceClient {
+	return &relayServiceClient{cc}
+}
+
+func (c *relayServiceClient) SubmitBid(ctx context.Context, in *SubmitBidRequest, opts ...grpc.CallOption) (*SubmitBidResponse, error) {
+	out := new(SubmitBidResponse)
+	err := c.cc.Invoke(ctx, RelayService_SubmitBid_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayServiceClient) StreamAuctionEvents(ctx context.Context, in *StreamAuctionEventsRequest, opts ...grpc.CallOption) (RelayService_StreamAuctionEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RelayService_ServiceDesc.Streams[0], RelayService_StreamAuctionEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &relayServiceStreamAuctionEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RelayService_StreamAuctionEventsClient interface {
+	Recv() (*AuctionEvent, error)
+	grpc.ClientStream
+}
+
+type relayServiceStreamAuctionEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *relayServiceStreamAuctionEventsClient) Recv() (*AuctionEvent, error) {
+	m := new(AuctionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *relayServiceClient) GetAuction(ctx context.Context, in *GetAuctionRequest, opts ...grpc.CallOption) (*GetAuctionResponse, error) {
+	out := new(GetAuctionResponse)
+	err := c.cc.Invoke(ctx, RelayService_GetAuction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayServiceClient) StreamBidRejections(ctx context.Context, in *StreamBidRejectionsRequest, opts ...grpc.CallOption) (RelayService_StreamBidRejectionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &RelayService_ServiceDesc.Streams[1], RelayService_StreamBidRejections_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &relayServiceStreamBidRejectionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RelayService_StreamBidRejectionsClient interface {
+	Recv() (*BidRejection, error)
+	grpc.ClientStream
+}
+
+type relayServiceStreamBidRejectionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *relayServiceStreamBidRejectionsClient) Recv() (*BidRejection, error) {
+	m := new(BidRejection)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RelayServiceServer is the server API for RelayService service.
+// All implementations must embed UnimplementedRelayServiceServer
+// for forward compatibility
+type RelayServiceServer interface {
+	SubmitBid(context.Context, *SubmitBidRequest) (*SubmitBidResponse, error)
+	// Streams auction lifecycle events until the client cancels
+	StreamAuctionEvents(*StreamAuctionEventsRequest, RelayService_StreamAuctionEventsServer) error
+	GetAuction(context.Context, *GetAuctionRequest) (*GetAuctionResponse, error)
+	// Streams the relay's own rejected bids until the client cancels
+	StreamBidRejections(*StreamBidRejectionsRequest, RelayService_StreamBidRejectionsServer) error
+	mustEmbedUnimplementedRelayServiceServer()
+}
+
+// UnimplementedRelayServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRelayServiceServer struct {
+}
+
+func (UnimplementedRelayServiceServer) SubmitBid(context.Context, *SubmitBidRequest) (*SubmitBidResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitBid not implemented")
+}
+func (UnimplementedRelayServiceServer) StreamAuctionEvents(*StreamAuctionEventsRequest, RelayService_StreamAuctionEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAuctionEvents not implemented")
+}
+func (UnimplementedRelayServiceServer) GetAuction(context.Context, *GetAuctionRequest) (*GetAuctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuction not implemented")
+}
+func (UnimplementedRelayServiceServer) StreamBidRejections(*StreamBidRejectionsRequest, RelayService_StreamBidRejectionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBidRejections not implemented")
+}
+func (UnimplementedRelayServiceServer) mustEmbedUnimplementedRelayServiceServer() {}
+
+// UnsafeRelayServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RelayServiceServer will
+// result in compilation errors.
+type UnsafeRelayServiceServer interface {
+	mustEmbedUnimplementedRelayServiceServer()
+}
+
+func RegisterRelayServiceServer(s grpc.ServiceRegistrar, srv RelayServiceServer) {
+	s.RegisterService(&RelayService_ServiceDesc, srv)
+}
+
+func _RelayService_SubmitBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayServiceServer).SubmitBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelayService_SubmitBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayServiceServer).SubmitBid(ctx, req.(*SubmitBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelayService_StreamAuctionEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamAuctionEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RelayServiceServer).StreamAuctionEvents(m, &relayServiceStreamAuctionEventsServer{stream})
+}
+
+type RelayService_StreamAuctionEventsServer interface {
+	Send(*AuctionEvent) error
+	grpc.ServerStream
+}
+
+type relayServiceStreamAuctionEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *relayServiceStreamAuctionEventsServer) Send(m *AuctionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RelayService_GetAuction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayServiceServer).GetAuction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RelayService_GetAuction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayServiceServer).GetAuction(ctx, req.(*GetAuctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RelayService_StreamBidRejections_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBidRejectionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RelayServiceServer).StreamBidRejections(m, &relayServiceStreamBidRejectionsServer{stream})
+}
+
+type RelayService_StreamBidRejectionsServer interface {
+	Send(*BidRejection) error
+	grpc.ServerStream
+}
+
+type relayServiceStreamBidRejectionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *relayServiceStreamBidRejectionsServer) Send(m *BidRejection) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// RelayService_ServiceDesc is the grpc.ServiceDesc for RelayService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RelayService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "relaygrpc.v1.RelayService",
+	HandlerType: (*RelayServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitBid",
+			Handler:    _RelayService_SubmitBid_Handler,
+		},
+		{
+			MethodName: "GetAuction",
+			Handler:    _RelayService_GetAuction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAuctionEvents",
+			Handler:       _RelayService_StreamAuctionEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamBidRejections",
+			Handler:       _RelayService_StreamBidRejections_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "relay.proto",
+}
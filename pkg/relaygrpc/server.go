@@ -0,0 +1,268 @@
+package relaygrpc
+
+//go:generate buf generate --template buf.gen.yaml .
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const eventBufferSize = 64
+
+// Satisfied by *listener.Listener
+type Backend interface {
+	SubmitBid(bid auction.SignedBid) error
+	GetAuction(l1Block uint64) (state listener.AuctionState, found bool)
+	SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription)
+}
+
+// Satisfied by *listener.Listener
+type ReceiptBackend interface {
+	SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error)
+}
+
+// Satisfied by *listener.Listener
+type RejectionBackend interface {
+	SubscribeRejections(bufferSize int) (<-chan auction.Rejection, event.Subscription)
+}
+
+// Observes how long leader changes take to reach relays, e.g. *metrics.Metrics
+type Metrics interface {
+	ObserveLeaderPropagation(transport string, latency time.Duration, leader auction.SignedBid)
+}
+
+type Server struct {
+	UnimplementedRelayServiceServer
+
+	logger     *slog.Logger
+	backend    Backend
+	limiter    *ratelimit.BidLimiter
+	metrics    Metrics
+	rejections RejectionBackend
+	receipts   ReceiptBackend
+	addr       string
+	grpcServer *grpc.Server
+	listener   net.Listener
+}
+
+// Bid submissions are rate limited by limiter, if non-nil.
+// If verifier is non-nil, all calls must be signed by a registered relay, see WithRelayKey.
+// Served over TLS if tlsConfig is non-nil, see tlsconfig.
+func NewServer(
+	logger *slog.Logger,
+	addr string,
+	backend Backend,
+	limiter *ratelimit.BidLimiter,
+	verifier *auth.Verifier,
+	tlsConfig *tls.Config,
+	opts ...grpc.ServerOption,
+) *Server {
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if verifier != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(unaryAuthInterceptor(verifier)),
+			grpc.ChainStreamInterceptor(streamAuthInterceptor(verifier)),
+		)
+	}
+	s := &Server{
+		logger:     logger,
+		backend:    backend,
+		limiter:    limiter,
+		addr:       addr,
+		grpcServer: grpc.NewServer(opts...),
+	}
+	RegisterRelayServiceServer(s.grpcServer, s)
+	return s
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil {
+			s.logger.Error("grpc server failed", "error", err)
+		}
+	}()
+	s.logger.Info("grpc server started", "addr", listener.Addr())
+	return nil
+}
+
+// Address the server is listening on, useful when started on port 0
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Waits for in-flight RPCs until ctx is done, then closes remaining streams
+func (s *Server) Stop(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+	s.logger.Info("grpc server stopped")
+}
+
+func (s *Server) SubmitBid(ctx context.Context, req *SubmitBidRequest) (*SubmitBidResponse, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if err := s.limiter.AllowIP(p.Addr.String()); err != nil {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+	bid, err := bidFromProto(req.Bid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := bid.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := auth.CheckSigner(ctx, bid.Address); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err := s.limiter.AllowSigner(*bid); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if s.receipts != nil {
+		receipt, err := s.receipts.SubmitBidWithReceipt(*bid)
+		if err != nil {
+			return nil, submitStatus(err)
+		}
+		return &SubmitBidResponse{Receipt: receiptToProto(receipt)}, nil
+	}
+	if err := s.backend.SubmitBid(*bid); err != nil {
+		return nil, submitStatus(err)
+	}
+	return &SubmitBidResponse{}, nil
+}
+
+// Acknowledges accepted bids with the backend's signed receipt in the response, if set before the server starts.
+// Read by Client.SubmitBidWithReceipt.
+func (s *Server) SetReceipts(receipts ReceiptBackend) {
+	s.receipts = receipts
+}
+
+func (s *Server) GetAuction(ctx context.Context, req *GetAuctionRequest) (*GetAuctionResponse, error) {
+	state, found := s.backend.GetAuction(req.L1Block)
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "no auction for l1 block %d", req.L1Block)
+	}
+	return &GetAuctionResponse{
+		L1Block:    state.L1Block,
+		InProgress: state.InProgress,
+		LeadingBid: bidToProto(state.LeadingBid),
+	}, nil
+}
+
+// Leader change propagation to streaming relays is observed, if set before the server starts
+func (s *Server) SetMetrics(metrics Metrics) {
+	s.metrics = metrics
+}
+
+func (s *Server) StreamAuctionEvents(req *StreamAuctionEventsRequest, stream RelayService_StreamAuctionEventsServer) error {
+	// Bond events are only streamed to the relay they concern, or to trusted forwarders to stream to theirs
+	var relay *common.Address
+	if len(req.Relay) > 0 {
+		if len(req.Relay) != common.AddressLength {
+			return status.Errorf(codes.InvalidArgument, "invalid relay length %d", len(req.Relay))
+		}
+		if err := auth.CheckSigner(stream.Context(), common.BytesToAddress(req.Relay)); err != nil {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		named := common.BytesToAddress(req.Relay)
+		relay = &named
+	} else if authenticated, ok := auth.RelayFromContext(stream.Context()); ok {
+		relay = &authenticated
+	}
+	forwarder := auth.IsForwarder(stream.Context())
+	events, sub := s.backend.SubscribeEvents(eventBufferSize)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "event subscription closed")
+			}
+			// Event types without a wire type, e.g. settlement failures, are internal to the oracle
+			if _, ok := eventTypes[ev.Type]; !ok {
+				continue
+			}
+			if !forwarder && !ev.DeliverableTo(relay) {
+				continue
+			}
+			if err := stream.Send(eventToProto(ev)); err != nil {
+				return err
+			}
+			if s.metrics != nil && ev.Type == auction.EventLeaderChanged {
+				s.metrics.ObserveLeaderPropagation("grpc", time.Since(ev.Timestamp), *ev.Bid)
+			}
+		}
+	}
+}
+
+// Streams relays' own rejected bids with StreamBidRejections, if set before the server starts
+func (s *Server) SetRejections(rejections RejectionBackend) {
+	s.rejections = rejections
+}
+
+func (s *Server) StreamBidRejections(req *StreamBidRejectionsRequest, stream RelayService_StreamBidRejectionsServer) error {
+	if s.rejections == nil {
+		return status.Error(codes.Unimplemented, "bid rejections not available")
+	}
+	relay, ok := auth.RelayFromContext(stream.Context())
+	if len(req.Relay) > 0 {
+		if len(req.Relay) != common.AddressLength {
+			return status.Errorf(codes.InvalidArgument, "invalid relay length %d", len(req.Relay))
+		}
+		if err := auth.CheckSigner(stream.Context(), common.BytesToAddress(req.Relay)); err != nil {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		relay = common.BytesToAddress(req.Relay)
+	} else if !ok {
+		return status.Error(codes.InvalidArgument, "missing relay")
+	}
+	rejections, sub := s.rejections.SubscribeRejections(eventBufferSize)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case rejection, ok := <-rejections:
+			if !ok {
+				return status.Error(codes.Unavailable, "rejection subscription closed")
+			}
+			if rejection.Bid.Address != relay {
+				continue
+			}
+			if err := stream.Send(rejectionToProto(rejection)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,524 @@
+package relaygrpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/ratelimit"
+	"blob-preconfs/pkg/relaygrpc"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type mockBackend struct {
+	submitted []auction.SignedBid
+	submitErr error
+	auctions  map[uint64]listener.AuctionState
+	feed      event.Feed
+}
+
+func (m *mockBackend) SubmitBid(bid auction.SignedBid) error {
+	if m.submitErr != nil {
+		return m.submitErr
+	}
+	m.submitted = append(m.submitted, bid)
+	return nil
+}
+
+func (m *mockBackend) GetAuction(l1Block uint64) (listener.AuctionState, bool) {
+	state, ok := m.auctions[l1Block]
+	return state, ok
+}
+
+func (m *mockBackend) SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription) {
+	ch := make(chan auction.Event, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+func startServer(t *testing.T, backend relaygrpc.Backend) *relaygrpc.Client {
+	return startServerWithLimiter(t, backend, nil)
+}
+
+func startServerWithLimiter(t *testing.T, backend relaygrpc.Backend, limiter *ratelimit.BidLimiter) *relaygrpc.Client {
+	server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, limiter, nil, nil)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	client, err := relaygrpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestSubmitBid(t *testing.T) {
+	backend := &mockBackend{}
+	client := startServer(t, backend)
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.NoError(t, client.SubmitBid(context.Background(), bid))
+	require.Equal(t, []auction.SignedBid{*bid}, backend.submitted)
+
+	tampered := *bid
+	tampered.AmountWei = big.NewInt(44)
+	err := client.SubmitBid(context.Background(), &tampered)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Len(t, backend.submitted, 1)
+}
+
+func TestSubmitBidRejected(t *testing.T) {
+	backend := &mockBackend{}
+	client := startServer(t, backend)
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+
+	backend.submitErr = auction.ErrBelowReserve
+	require.ErrorIs(t, client.SubmitBid(context.Background(), bid), auction.ErrBelowReserve, "code carried in the status details")
+
+	backend.submitErr = auction.ErrUnregisteredRelay
+	require.ErrorIs(t, client.SubmitBid(context.Background(), bid), auction.ErrUnregisteredRelay)
+
+	backend.submitErr = errors.New("backend failed")
+	err := client.SubmitBid(context.Background(), bid)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, rejected := auction.RejectCodeOf(err)
+	require.False(t, rejected)
+}
+
+// Bids decoded from untrusted protobuf are only handed to the backend if well formed
+func FuzzSubmitBid(f *testing.F) {
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	for _, req := range []*relaygrpc.SubmitBidRequest{
+		{Bid: &relaygrpc.SignedBid{AmountWei: bid.AmountWei.String(), L1Block: 100, Address: bid.Address.Bytes(), Signature: bid.Signature}},
+		{Bid: &relaygrpc.SignedBid{AmountWei: "-1", Address: []byte{0x01}}},
+		{},
+	} {
+		data, err := proto.Marshal(req)
+		require.NoError(f, err)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var req relaygrpc.SubmitBidRequest
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return
+		}
+		backend := &mockBackend{}
+		server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, nil, nil, nil)
+		if _, err := server.SubmitBid(context.Background(), &req); err != nil {
+			require.Equal(t, codes.InvalidArgument, status.Code(err), err.Error())
+			require.Empty(t, backend.submitted)
+			return
+		}
+		require.Len(t, backend.submitted, 1)
+		submitted := backend.submitted[0]
+		require.NoError(t, submitted.Validate())
+		amount, _ := new(big.Int).SetString(req.Bid.AmountWei, 10)
+		require.Zero(t, amount.Cmp(submitted.AmountWei))
+		require.Equal(t, req.Bid.L1Block, submitted.L1Block.Uint64())
+	})
+}
+
+type mockReceipts struct {
+	*mockBackend
+	key *ecdsa.PrivateKey
+}
+
+func (m *mockReceipts) SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error) {
+	if err := m.SubmitBid(bid); err != nil {
+		return nil, err
+	}
+	return auction.CreateSignedReceipt(bid, time.Now(), m.key)
+}
+
+func TestSubmitBidWithReceipt(t *testing.T) {
+	auctioneerKey, _ := crypto.GenerateKey()
+	backend := &mockReceipts{mockBackend: &mockBackend{}, key: auctioneerKey}
+	server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, nil, nil, nil)
+	server.SetReceipts(backend)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	client, err := relaygrpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	receipt, err := client.SubmitBidWithReceipt(context.Background(), bid)
+	require.NoError(t, err)
+	require.True(t, receipt.Verify())
+	require.True(t, receipt.Covers(*bid))
+	require.Equal(t, crypto.PubkeyToAddress(auctioneerKey.PublicKey), receipt.Auctioneer)
+
+	backend.submitErr = auction.ErrOutbid
+	_, err = client.SubmitBidWithReceipt(context.Background(), bid)
+	require.ErrorIs(t, err, auction.ErrOutbid)
+
+	_, err = startServer(t, &mockBackend{}).SubmitBidWithReceipt(context.Background(), bid)
+	require.ErrorContains(t, err, "without a receipt")
+}
+
+func TestSubmitBidRateLimited(t *testing.T) {
+	backend := &mockBackend{}
+	limiter := ratelimit.NewBidLimiter(ratelimit.Config{Rate: 1, Burst: 10}, ratelimit.Config{Rate: 1, Burst: 1})
+	client := startServerWithLimiter(t, backend, limiter)
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.NoError(t, client.SubmitBid(context.Background(), bid))
+	err := client.SubmitBid(context.Background(), bid)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Len(t, backend.submitted, 1)
+}
+
+type mockRegistry struct {
+	registered map[common.Address]bool
+}
+
+func (r *mockRegistry) IsRegisteredOnSettlementLayer(address common.Address) bool {
+	return r.registered[address]
+}
+
+func TestAuthenticatedRelays(t *testing.T) {
+	backend := &mockBackend{auctions: map[uint64]listener.AuctionState{100: {L1Block: 100, InProgress: true}}}
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	registry := &mockRegistry{registered: map[common.Address]bool{
+		crypto.PubkeyToAddress(pk1.PublicKey): true,
+		crypto.PubkeyToAddress(pk2.PublicKey): true,
+	}}
+	server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, nil, auth.NewVerifier(registry, 30*time.Second), nil)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	bid1 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)
+
+	anonymous, err := relaygrpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer anonymous.Close()
+	require.Equal(t, codes.Unauthenticated, status.Code(anonymous.SubmitBid(context.Background(), bid1)))
+	_, err = anonymous.GetAuction(context.Background(), 100)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	err = anonymous.StreamAuctionEvents(context.Background(), common.Address{}, func(auction.Event) {})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	opts := append(relaygrpc.WithRelayKey(pk1), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	signed, err := relaygrpc.NewClient(server.Addr().String(), opts...)
+	require.NoError(t, err)
+	defer signed.Close()
+	require.NoError(t, signed.SubmitBid(context.Background(), bid1))
+	_, err = signed.GetAuction(context.Background(), 100)
+	require.NoError(t, err)
+	bid2 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk2)
+	require.Equal(t, codes.PermissionDenied, status.Code(signed.SubmitBid(context.Background(), bid2)))
+	require.Len(t, backend.submitted, 1)
+}
+
+func TestGetAuction(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	backend := &mockBackend{auctions: map[uint64]listener.AuctionState{
+		100: {L1Block: 100, InProgress: false, LeadingBid: winner},
+		101: {L1Block: 101, InProgress: true},
+	}}
+	client := startServer(t, backend)
+
+	state, err := client.GetAuction(context.Background(), 100)
+	require.NoError(t, err)
+	require.Equal(t, backend.auctions[100], state)
+
+	state, err = client.GetAuction(context.Background(), 101)
+	require.NoError(t, err)
+	require.True(t, state.InProgress)
+	require.Nil(t, state.LeadingBid)
+
+	_, err = client.GetAuction(context.Background(), 99)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+type mockMetrics struct {
+	mu         sync.Mutex
+	transports []string
+}
+
+func (m *mockMetrics) ObserveLeaderPropagation(transport string, latency time.Duration, leader auction.SignedBid) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transports = append(m.transports, transport)
+}
+
+func TestStreamAuctionEvents(t *testing.T) {
+	backend := &mockBackend{}
+	metrics := &mockMetrics{}
+	server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", backend, nil, nil, nil)
+	server.SetMetrics(metrics)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	client, err := relaygrpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan auction.Event, 1)
+	go client.StreamAuctionEvents(ctx, common.Address{}, func(ev auction.Event) { received <- ev })
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	sent := auction.Event{
+		Type:      auction.EventLeaderChanged,
+		L1Block:   big.NewInt(100),
+		Bid:       bid,
+		Timestamp: time.UnixMilli(time.Now().UnixMilli()),
+	}
+	require.Eventually(t, func() bool { return backend.feed.Send(sent) > 0 }, time.Second, 10*time.Millisecond)
+
+	select {
+	case ev := <-received:
+		require.Equal(t, sent.Type, ev.Type)
+		require.Equal(t, sent.L1Block, ev.L1Block)
+		require.Equal(t, *sent.Bid, *ev.Bid)
+		require.True(t, sent.Timestamp.Equal(ev.Timestamp))
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+	require.Eventually(t, func() bool {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return len(metrics.transports) == 1 && metrics.transports[0] == "grpc"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStreamWinnerFallback(t *testing.T) {
+	backend := &mockBackend{}
+	client := startServer(t, backend)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan auction.Event, 1)
+	go client.StreamAuctionEvents(ctx, common.Address{}, func(ev auction.Event) { received <- ev })
+
+	pk, _ := crypto.GenerateKey()
+	runnerUp := auction.MustCreateSignedBid(big.NewInt(42), big.NewInt(100), pk)
+	failed := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	require.Eventually(t, func() bool {
+		// Internal to the oracle, not streamed
+		return backend.feed.Send(auction.Event{Type: auction.EventSettlementFailed, L1Block: big.NewInt(99), Error: "reverted"}) > 0
+	}, time.Second, 10*time.Millisecond)
+	sent := auction.Event{
+		Type:      auction.EventWinnerFallback,
+		L1Block:   big.NewInt(100),
+		Bid:       runnerUp,
+		Timestamp: time.UnixMilli(time.Now().UnixMilli()),
+		Failed:    &failed,
+		Stage:     auction.StageAcceptance,
+		Error:     "award declined",
+	}
+	backend.feed.Send(sent)
+
+	select {
+	case ev := <-received:
+		require.Equal(t, sent.Type, ev.Type)
+		require.Equal(t, *sent.Bid, *ev.Bid)
+		require.Equal(t, failed, *ev.Failed)
+		require.Equal(t, sent.Stage, ev.Stage)
+		require.Equal(t, sent.Error, ev.Error)
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+}
+
+func TestStreamHeartbeat(t *testing.T) {
+	backend := &mockBackend{}
+	client := startServer(t, backend)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan auction.Event, 1)
+	go client.StreamAuctionEvents(ctx, common.Address{}, func(ev auction.Event) { received <- ev })
+
+	auctioneerKey, _ := crypto.GenerateKey()
+	h, err := auction.CreateSignedHeartbeat(10, 100, common.Hash{0x01}, true, "v1.2.3", time.Now(), auctioneerKey)
+	require.NoError(t, err)
+	sent := auction.Event{Type: auction.EventHeartbeat, L1Block: big.NewInt(100), Timestamp: time.Now(), Heartbeat: h}
+	require.Eventually(t, func() bool { return backend.feed.Send(sent) > 0 }, time.Second, 10*time.Millisecond)
+
+	select {
+	case ev := <-received:
+		require.Equal(t, auction.EventHeartbeat, ev.Type)
+		require.Equal(t, *h, *ev.Heartbeat)
+		require.True(t, ev.Heartbeat.Verify())
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+}
+
+func TestStreamBondEvents(t *testing.T) {
+	backend := &mockBackend{}
+	client := startServer(t, backend)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	relay1, relay2 := common.Address{0x01}, common.Address{0x02}
+	received := make(chan auction.Event, 2)
+	go client.StreamAuctionEvents(ctx, relay1, func(ev auction.Event) { received <- ev })
+	anonymous := make(chan auction.Event, 2)
+	go client.StreamAuctionEvents(ctx, common.Address{}, func(ev auction.Event) { anonymous <- ev })
+
+	bondLow := func(relay common.Address) auction.Event {
+		return auction.Event{
+			Type:      auction.EventBondLow,
+			L1Block:   big.NewInt(100),
+			Timestamp: time.UnixMilli(time.Now().UnixMilli()),
+			Bond: &auction.BondStatus{
+				Relay:            relay,
+				BalanceWei:       big.NewInt(100),
+				PendingDebitsWei: big.NewInt(90),
+				UnsettledWins:    2,
+				CapWei:           big.NewInt(10),
+			},
+		}
+	}
+	require.Eventually(t, func() bool { return backend.feed.Send(bondLow(relay2)) > 1 }, time.Second, 10*time.Millisecond)
+	sent := bondLow(relay1)
+	backend.feed.Send(sent)
+	restored := auction.Event{
+		Type:    auction.EventBondRestored,
+		L1Block: big.NewInt(101),
+		Bond:    &auction.BondStatus{Relay: relay1, BalanceWei: big.NewInt(100), PendingDebitsWei: big.NewInt(0)},
+	}
+	backend.feed.Send(restored)
+	backend.feed.Send(auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(102)})
+
+	for _, want := range []auction.Event{sent, restored} {
+		select {
+		case ev := <-received:
+			require.Equal(t, want.Type, ev.Type)
+			require.Equal(t, *want.Bond, *ev.Bond)
+		case <-time.After(time.Second):
+			t.Fatal("event not received")
+		}
+	}
+	select {
+	case ev := <-anonymous:
+		require.Equal(t, auction.EventAuctionOpened, ev.Type, "bond events withheld from anonymous streams")
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+}
+
+type mockRejections struct{ feed event.Feed }
+
+func (m *mockRejections) SubscribeRejections(bufferSize int) (<-chan auction.Rejection, event.Subscription) {
+	ch := make(chan auction.Rejection, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+func TestStreamBidRejections(t *testing.T) {
+	rejections := &mockRejections{}
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	registry := &mockRegistry{registered: map[common.Address]bool{
+		crypto.PubkeyToAddress(pk1.PublicKey): true,
+		crypto.PubkeyToAddress(pk2.PublicKey): true,
+	}}
+	server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, nil, auth.NewVerifier(registry, 30*time.Second), nil)
+	server.SetRejections(rejections)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	opts := append(relaygrpc.WithRelayKey(pk1), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	client, err := relaygrpc.NewClient(server.Addr().String(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	opts = append(relaygrpc.WithRelayKey(pk2), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	impersonator, err := relaygrpc.NewClient(server.Addr().String(), opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { impersonator.Close() })
+	err = impersonator.StreamBidRejections(context.Background(), crypto.PubkeyToAddress(pk1.PublicKey), func(auction.Rejection) {})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan auction.Rejection, 2)
+	go client.StreamBidRejections(ctx, common.Address{}, func(rejection auction.Rejection) { received <- rejection })
+
+	leader := auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), pk2)
+	other := auction.Rejection{Bid: *auction.MustCreateSignedBid(big.NewInt(40), big.NewInt(100), pk2), Code: auction.RejectOutbid}
+	own := auction.Rejection{
+		Bid:       *auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1),
+		Code:      auction.RejectOutbid,
+		Reason:    auction.RejectOutbid.Reason(),
+		Leader:    leader,
+		Timestamp: time.UnixMilli(time.Now().UnixMilli()),
+	}
+	require.Eventually(t, func() bool { return rejections.feed.Send(other) > 0 }, time.Second, 10*time.Millisecond)
+	rejections.feed.Send(own)
+
+	select {
+	case rejection := <-received:
+		require.Equal(t, own.Bid, rejection.Bid)
+		require.Equal(t, own.Code, rejection.Code)
+		require.Equal(t, own.Reason, rejection.Reason)
+		require.Equal(t, *own.Leader, *rejection.Leader)
+		require.True(t, own.Timestamp.Equal(rejection.Timestamp))
+	case <-time.After(time.Second):
+		t.Fatal("rejection not received")
+	}
+	require.Empty(t, received)
+}
+
+func TestRejectCodes(t *testing.T) {
+	rejections := &mockRejections{}
+	server := relaygrpc.NewServer(slog.Default(), "127.0.0.1:0", &mockBackend{}, nil, nil, nil)
+	server.SetRejections(rejections)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	client, err := relaygrpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	pk, _ := crypto.GenerateKey()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan auction.Rejection, 1)
+	go client.StreamBidRejections(ctx, crypto.PubkeyToAddress(pk.PublicKey), func(rejection auction.Rejection) { received <- rejection })
+
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	for _, code := range []auction.RejectCode{
+		auction.RejectNoAuction,
+		auction.RejectWrongBlock,
+		auction.RejectInvalidSignature,
+		auction.RejectDenied,
+		auction.RejectNotAllowed,
+		auction.RejectNotRegistered,
+		auction.RejectDuplicate,
+		auction.RejectOutbid,
+		auction.RejectUncovered,
+		auction.RejectBelowReserve,
+		auction.RejectOverQuota,
+		auction.RejectOverBondCap,
+	} {
+		rejection := auction.Rejection{Bid: *bid, Code: code}
+		require.Eventually(t, func() bool { return rejections.feed.Send(rejection) > 0 }, time.Second, 10*time.Millisecond)
+		select {
+		case got := <-received:
+			require.Equal(t, code, got.Code)
+		case <-time.After(time.Second):
+			t.Fatalf("%s rejection not received", code)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+)
+
+// Sized for entries, which hold a single bid
+const maxEntrySize = 64 * 1024
+
+type EntryType string
+
+const (
+	EntryAuctionOpened EntryType = "auctionOpened"
+	EntryBid           EntryType = "bid"
+)
+
+// One line of a recording: an auction opening, or a bid received with its outcome
+type Entry struct {
+	Type     EntryType          `json:"type"`
+	At       time.Time          `json:"at"`
+	L1Block  uint64             `json:"l1Block"`
+	Bid      *auction.SignedBid `json:"bid,omitempty"`
+	Accepted bool               `json:"accepted,omitempty"`
+	Reason   string             `json:"reason,omitempty"`
+}
+
+// Records inbound bid traffic with timestamps, one JSON entry per line, for Replay. Satisfies auction.Auditor,
+// and records auction openings from the listener's event feed with Watch.
+type Recorder struct {
+	logger *slog.Logger
+
+	mu   sync.Mutex // Protects file
+	file *os.File
+}
+
+// Opens the recording at path, creating it if missing, and appends to it
+func NewRecorder(logger *slog.Logger, path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{logger: logger, file: file}, nil
+}
+
+// To satisfy auction.Auditor. Failing to write is logged, as bid processing must not depend on the recording.
+func (r *Recorder) RecordBid(bid auction.SignedBid, accepted bool, reason string) {
+	entry := Entry{Type: EntryBid, At: time.Now().UTC(), Bid: &bid, Accepted: accepted, Reason: reason}
+	if bid.L1Block != nil {
+		entry.L1Block = bid.L1Block.Uint64()
+	}
+	if err := r.write(entry); err != nil {
+		r.logger.Error("failed to write recording entry", "bid", bid, "error", err)
+	}
+}
+
+// Records auction openings from the listener's event feed (see Listener.SubscribeEvents),
+// until ctx is done or events is closed
+func (r *Recorder) Watch(ctx context.Context, events <-chan auction.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if ev.Type != auction.EventAuctionOpened || ev.L1Block == nil {
+				continue
+			}
+			entry := Entry{Type: EntryAuctionOpened, At: ev.Timestamp.UTC(), L1Block: ev.L1Block.Uint64()}
+			if err := r.write(entry); err != nil {
+				r.logger.Error("failed to write recording entry", "l1Block", entry.L1Block, "error", err)
+			}
+		}
+	}
+}
+
+func (r *Recorder) write(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.file.Write(append(data, '\n'))
+	return err
+}
+
+// Flushes the recording to disk and closes it
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Sync(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// Reads a recording, ordered by time. Bids are recorded once evaluated while openings are recorded
+// asynchronously, so lines aren't necessarily in order.
+func Read(r io.Reader) ([]Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxEntrySize)
+	var entries []Entry
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRecording, line, err)
+		}
+		if entry.Type == EntryBid && entry.Bid == nil {
+			return nil, fmt.Errorf("%w: line %d: bid missing", ErrInvalidRecording, line)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+	return entries, nil
+}
@@ -0,0 +1,61 @@
+package replay_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/replay"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bids.jsonl")
+	recorder, err := replay.NewRecorder(slog.Default(), path)
+	require.NoError(t, err)
+
+	events := make(chan auction.Event, 1)
+	events <- auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100), Timestamp: time.Now()}
+	close(events)
+	recorder.Watch(context.Background(), events)
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	recorder.RecordBid(*bid, false, "bidder not on whitelist")
+	require.NoError(t, recorder.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	entries, err := replay.Read(file)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, replay.EntryAuctionOpened, entries[0].Type)
+	require.Equal(t, uint64(100), entries[0].L1Block)
+	require.Equal(t, replay.EntryBid, entries[1].Type)
+	require.Equal(t, uint64(100), entries[1].L1Block)
+	require.Equal(t, *bid, *entries[1].Bid)
+	require.False(t, entries[1].Accepted)
+	require.Equal(t, "bidder not on whitelist", entries[1].Reason)
+}
+
+func TestReadOrdersByTime(t *testing.T) {
+	entries, err := replay.Read(strings.NewReader(
+		`{"type":"auctionOpened","at":"2024-01-01T00:00:02Z","l1Block":101}` + "\n" +
+			`{"type":"auctionOpened","at":"2024-01-01T00:00:01Z","l1Block":100}` + "\n"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), entries[0].L1Block)
+	require.Equal(t, uint64(101), entries[1].L1Block)
+
+	_, err = replay.Read(strings.NewReader("{\n"))
+	require.ErrorIs(t, err, replay.ErrInvalidRecording)
+	_, err = replay.Read(strings.NewReader(`{"type":"bid","at":"2024-01-01T00:00:01Z","l1Block":100}`))
+	require.ErrorIs(t, err, replay.ErrInvalidRecording)
+}
@@ -0,0 +1,142 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrInvalidRecording = errors.New("invalid recording")
+	ErrInvalidConfig    = errors.New("invalid replay config")
+)
+
+type Config struct {
+	// Playback speed relative to the recording, e.g. 10 to replay 10x faster. Defaults to 1.
+	Speed float64
+	// Bidding period of each auction at original speed, defaults to the listener's 5s
+	Period time.Duration
+	// Relays registered on the settlement layer, defaults to every relay, as registrations aren't recorded
+	Registry auction.RelayRegistry
+	// Bidders are checked against the access list, defaults to the auction's built-in whitelist
+	AccessList *auction.AccessList
+}
+
+// Result of replaying one block's auction
+type Outcome struct {
+	L1Block uint64
+	// Bids replayed to the auction
+	Bids   int
+	Winner *auction.SignedBid
+	// Last bid accepted for the block in the recording
+	RecordedWinner *auction.SignedBid
+}
+
+// Whether the replayed auction picked a different winner or price than recorded
+func (o Outcome) Changed() bool {
+	if o.Winner == nil || o.RecordedWinner == nil {
+		return o.Winner != o.RecordedWinner
+	}
+	return o.Winner.Address != o.RecordedWinner.Address || o.Winner.AmountWei.Cmp(o.RecordedWinner.AmountWei) != 0
+}
+
+// Feeds recorded bid traffic back through auction.RelayAuction, so auction changes can be evaluated
+// against historical traffic
+type Replayer struct {
+	logger *slog.Logger
+	config Config
+}
+
+func NewReplayer(logger *slog.Logger, config Config) (*Replayer, error) {
+	if config.Speed < 0 {
+		return nil, ErrInvalidConfig
+	}
+	if config.Speed == 0 {
+		config.Speed = 1
+	}
+	if config.Period <= 0 {
+		config.Period = 5 * time.Second
+	}
+	if config.Registry == nil {
+		config.Registry = anyRelay{}
+	}
+	return &Replayer{logger: logger, config: config}, nil
+}
+
+// Replays each recorded auction in turn, from its opening. Bids are submitted at their recorded offset from the
+// opening, scaled by the speed, and bids received before the opening or after the period are dropped, as the
+// listener rejects them. Time between auctions isn't replayed.
+func (r *Replayer) Replay(ctx context.Context, entries []Entry) ([]Outcome, error) {
+	var outcomes []Outcome
+	for i, opened := range entries {
+		if opened.Type != EntryAuctionOpened {
+			continue
+		}
+		outcome, err := r.replayAuction(ctx, opened, entries[i+1:])
+		if err != nil {
+			return outcomes, err
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, nil
+}
+
+func (r *Replayer) replayAuction(ctx context.Context, opened Entry, later []Entry) (Outcome, error) {
+	outcome := Outcome{L1Block: opened.L1Block}
+	var bids []Entry
+	for _, entry := range later {
+		if entry.Type != EntryBid || entry.L1Block != opened.L1Block {
+			continue
+		}
+		if entry.Accepted {
+			outcome.RecordedWinner = entry.Bid
+		}
+		if entry.At.Sub(opened.At) < r.config.Period {
+			bids = append(bids, entry)
+		}
+	}
+	outcome.Bids = len(bids)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	relayAuction := auction.NewRelayAuction(r.logger, r.config.Registry)
+	relayAuction.SetAccessList(r.config.AccessList)
+	started := time.Now()
+	results := relayAuction.StartAsync(ctx, r.scale(r.config.Period))
+	for _, bid := range bids {
+		timer := time.NewTimer(time.Until(started.Add(r.scale(bid.At.Sub(opened.At)))))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return outcome, ctx.Err()
+		}
+		// At its recorded time, so ties go as they did
+		// Bids recorded after the auction closed are dropped, as they were
+		relayAuction.SubmitBidAt(*bid.Bid, bid.At)
+	}
+	select {
+	case winner := <-results:
+		if winner.Address != (common.Address{}) {
+			outcome.Winner = &winner
+		}
+		return outcome, nil
+	case <-ctx.Done():
+		return outcome, ctx.Err()
+	}
+}
+
+func (r *Replayer) scale(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / r.config.Speed)
+}
+
+type anyRelay struct{}
+
+func (anyRelay) IsRegisteredOnSettlementLayer(common.Address) bool {
+	return true
+}
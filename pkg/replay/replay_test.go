@@ -0,0 +1,68 @@
+package replay_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/replay"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	relay1, relay2 := crypto.PubkeyToAddress(pk1.PublicKey), crypto.PubkeyToAddress(pk2.PublicKey)
+	opened := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bid := func(block, amount int64, pk *ecdsa.PrivateKey, after time.Duration, accepted bool) replay.Entry {
+		signed := auction.MustCreateSignedBid(big.NewInt(amount), big.NewInt(block), pk)
+		return replay.Entry{Type: replay.EntryBid, At: opened.Add(after), L1Block: uint64(block), Bid: signed, Accepted: accepted}
+	}
+	entries := []replay.Entry{
+		bid(100, 50, pk1, -time.Second, false), // before the auction opened
+		{Type: replay.EntryAuctionOpened, At: opened, L1Block: 100},
+		bid(100, 10, pk1, time.Second, true),
+		bid(100, 20, pk2, 2*time.Second, true),
+		bid(100, 90, pk1, 6*time.Second, false), // after the auction closed
+		{Type: replay.EntryAuctionOpened, At: opened.Add(12 * time.Second), L1Block: 101},
+	}
+
+	// relay2 denied since recording
+	replayer, err := replay.NewReplayer(slog.Default(), replay.Config{
+		Speed:      50,
+		AccessList: auction.NewAccessList([]common.Address{relay1, relay2}, []common.Address{relay2}),
+	})
+	require.NoError(t, err)
+	started := time.Now()
+	outcomes, err := replayer.Replay(context.Background(), entries)
+	require.NoError(t, err)
+	require.Less(t, time.Since(started), time.Second, "replayed at 50x")
+
+	require.Len(t, outcomes, 2)
+	require.Equal(t, uint64(100), outcomes[0].L1Block)
+	require.Equal(t, 2, outcomes[0].Bids)
+	require.Equal(t, relay1, outcomes[0].Winner.Address)
+	require.Equal(t, relay2, outcomes[0].RecordedWinner.Address)
+	require.True(t, outcomes[0].Changed())
+	require.Equal(t, replay.Outcome{L1Block: 101}, outcomes[1])
+	require.False(t, outcomes[1].Changed())
+}
+
+func TestReplayCancelled(t *testing.T) {
+	replayer, err := replay.NewReplayer(slog.Default(), replay.Config{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = replayer.Replay(ctx, []replay.Entry{{Type: replay.EntryAuctionOpened, L1Block: 100}})
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = replay.NewReplayer(slog.Default(), replay.Config{Speed: -1})
+	require.ErrorIs(t, err, replay.ErrInvalidConfig)
+}
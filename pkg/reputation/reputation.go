@@ -0,0 +1,165 @@
+package reputation
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Violations kept for Violations, oldest dropped first
+const maxViolations = 256
+
+// Relay's track record of honoring the auctions it won
+type Record struct {
+	Relay    common.Address `json:"relay"`
+	Accepted int            `json:"accepted"`
+	Defaults int            `json:"defaults"`
+	// Commitments the relay issued and missed through its own fault
+	Misses int `json:"misses"`
+	// Won auctions whose slot the proposer missed, which aren't held against the relay
+	ProposerFaults int `json:"proposerFaults"`
+	// Most recent default, nil if the relay never defaulted
+	LastDefault *Default `json:"lastDefault,omitempty"`
+}
+
+// Won auction the relay defaulted on, e.g. by declining its award or not accepting it in time
+type Default struct {
+	L1Block uint64    `json:"l1Block"`
+	Reason  string    `json:"reason"`
+	At      time.Time `json:"at"`
+}
+
+type ViolationKind string
+
+const (
+	// The relay defaulted on an auction it won
+	ViolationDefault ViolationKind = "default"
+	// The relay missed a commitment it issued, see commitment.MissReasonRelayFault
+	ViolationMissedCommitment ViolationKind = "missedCommitment"
+)
+
+// Relay failing to honor an auction it won or a commitment it issued
+type Violation struct {
+	Relay   common.Address `json:"relay"`
+	Kind    ViolationKind  `json:"kind"`
+	L1Block uint64         `json:"l1Block"`
+	Reason  string         `json:"reason"`
+	// Commitment missed, for missedCommitment violations
+	Commitment *common.Hash `json:"commitment,omitempty"`
+	At         time.Time    `json:"at"`
+}
+
+// Tracks relays' records since the auctioneer started. Satisfies commitment.Observer, to count relays' misses.
+type Tracker struct {
+	mu      sync.Mutex // Protects records and violations
+	records map[common.Address]*Record
+	// Most recent violations, oldest first
+	violations []Violation
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{records: make(map[common.Address]*Record)}
+}
+
+func (t *Tracker) RecordAccepted(relay common.Address) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.record(relay).Accepted++
+}
+
+func (t *Tracker) RecordDefault(relay common.Address, l1Block uint64, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r := t.record(relay)
+	r.Defaults++
+	r.LastDefault = &Default{L1Block: l1Block, Reason: reason, At: time.Now()}
+	t.violate(Violation{Relay: relay, Kind: ViolationDefault, L1Block: l1Block, Reason: reason, At: r.LastDefault.At})
+}
+
+// Records a won auction whose slot was missed. It's the proposer's fault, so isn't a default or a violation.
+func (t *Tracker) RecordProposerFault(relay common.Address, l1Block uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.record(relay).ProposerFaults++
+}
+
+// To satisfy commitment.Observer
+func (t *Tracker) CommitmentIssued(c commitment.Commitment) {}
+
+// To satisfy commitment.Observer. Misses for external reasons or proposer faults aren't the relay's.
+func (t *Tracker) CommitmentMissed(c commitment.Commitment, reason commitment.MissReason, block *big.Int) {
+	if reason != commitment.MissReasonRelayFault {
+		return
+	}
+	hash := c.Hash()
+	var l1Block uint64
+	if block != nil {
+		l1Block = block.Uint64()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.record(c.Committer).Misses++
+	t.violate(Violation{Relay: c.Committer, Kind: ViolationMissedCommitment, L1Block: l1Block, Reason: reason.String(),
+		Commitment: &hash, At: time.Now()})
+}
+
+// Must be called with mu held
+func (t *Tracker) violate(v Violation) {
+	t.violations = append(t.violations, v)
+	if len(t.violations) > maxViolations {
+		t.violations = t.violations[len(t.violations)-maxViolations:]
+	}
+}
+
+// Must be called with mu held
+func (t *Tracker) record(relay common.Address) *Record {
+	r, ok := t.records[relay]
+	if !ok {
+		r = &Record{Relay: relay}
+		t.records[relay] = r
+	}
+	return r
+}
+
+// Relay's record, empty if it never won
+func (t *Tracker) Get(relay common.Address) Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r, ok := t.records[relay]; ok {
+		return *r
+	}
+	return Record{Relay: relay}
+}
+
+// Records of every relay that won, by address
+func (t *Tracker) All() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	records := make([]Record, 0, len(t.records))
+	for _, r := range t.records {
+		records = append(records, *r)
+	}
+	sort.Slice(records, func(i, j int) bool { return bytes.Compare(records[i].Relay[:], records[j].Relay[:]) < 0 })
+	return records
+}
+
+// Most recent violations across relays, newest first, at most limit of them if it's positive
+func (t *Tracker) Violations(limit int) []Violation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := len(t.violations)
+	if limit > 0 {
+		n = min(n, limit)
+	}
+	violations := make([]Violation, 0, n)
+	for i := len(t.violations) - 1; len(violations) < n; i-- {
+		violations = append(violations, t.violations[i])
+	}
+	return violations
+}
@@ -0,0 +1,69 @@
+package reputation_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/reputation"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	relay1, relay2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	tracker := reputation.NewTracker()
+	require.Equal(t, reputation.Record{Relay: relay1}, tracker.Get(relay1))
+
+	tracker.RecordDefault(relay2, 100, "declined")
+	tracker.RecordAccepted(relay1)
+	tracker.RecordAccepted(relay2)
+	tracker.RecordDefault(relay2, 102, "timed out")
+
+	r := tracker.Get(relay2)
+	require.Equal(t, 1, r.Accepted)
+	require.Equal(t, 2, r.Defaults)
+	require.Equal(t, uint64(102), r.LastDefault.L1Block)
+	require.Equal(t, "timed out", r.LastDefault.Reason)
+	all := tracker.All()
+	require.Len(t, all, 2)
+	require.Equal(t, relay1, all[0].Relay)
+	require.Nil(t, all[0].LastDefault)
+}
+
+func TestViolations(t *testing.T) {
+	relayKey, _ := crypto.GenerateKey()
+	relay := crypto.PubkeyToAddress(relayKey.PublicKey)
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{TargetBlock: big.NewInt(100), ExpiryBlock: big.NewInt(101), FeeWei: big.NewInt(1)}, relayKey)
+	require.NoError(t, err)
+	tracker := reputation.NewTracker()
+
+	tracker.RecordDefault(relay, 100, "declined")
+	tracker.CommitmentMissed(*c, commitment.MissReasonExternal, big.NewInt(102))
+	tracker.CommitmentMissed(*c, commitment.MissReasonProposerFault, big.NewInt(102))
+	require.Len(t, tracker.Violations(0), 1, "misses for other reasons aren't the relay's")
+	tracker.CommitmentMissed(*c, commitment.MissReasonRelayFault, big.NewInt(102))
+
+	violations := tracker.Violations(0)
+	require.Len(t, violations, 2)
+	require.Equal(t, reputation.ViolationMissedCommitment, violations[0].Kind, "newest first")
+	require.Equal(t, uint64(102), violations[0].L1Block)
+	require.Equal(t, c.Hash(), *violations[0].Commitment)
+	require.Equal(t, reputation.ViolationDefault, violations[1].Kind)
+	require.Len(t, tracker.Violations(1), 1)
+	tracker.RecordProposerFault(relay, 103)
+	require.Len(t, tracker.Violations(0), 2, "proposer faults aren't violations")
+	r := tracker.Get(relay)
+	require.Equal(t, 1, r.Defaults)
+	require.Equal(t, 1, r.Misses)
+	require.Equal(t, 1, r.ProposerFaults)
+
+	for i := 0; i < 300; i++ {
+		tracker.RecordDefault(relay, uint64(i), "declined")
+	}
+	violations = tracker.Violations(0)
+	require.Len(t, violations, 256, "oldest violations are dropped")
+	require.Equal(t, uint64(299), violations[0].L1Block)
+}
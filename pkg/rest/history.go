@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Satisfied by store.Store implementations
+type HistoryBackend interface {
+	ListAuctions(filter store.AuctionFilter, page store.Page) ([]store.AuctionRecord, string, error)
+	ListBids(filter store.BidFilter, page store.Page) ([]store.BidRecord, string, error)
+	ListCommitments(filter store.CommitmentFilter, page store.Page) ([]store.CommitmentRecord, string, error)
+}
+
+type AuctionsPage struct {
+	Auctions   []store.AuctionRecord `json:"auctions"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+}
+
+type BidsPage struct {
+	Bids       []store.BidRecord `json:"bids"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+type CommitmentsPage struct {
+	Commitments []CommitmentResponse `json:"commitments"`
+	NextCursor  string               `json:"nextCursor,omitempty"`
+}
+
+func (s *Server) handleListAuctions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	blocks, page, err := parseRangeAndPage(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	winner, err := parseAddress(query, "winner")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	auctions, next, err := s.history.ListAuctions(store.AuctionFilter{Blocks: blocks, Winner: winner}, page)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, AuctionsPage{Auctions: nonNil(auctions), NextCursor: next})
+}
+
+func (s *Server) handleListBids(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	blocks, page, err := parseRangeAndPage(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	relay, err := parseAddress(query, "relay")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	bids, next, err := s.history.ListBids(store.BidFilter{Blocks: blocks, Relay: relay}, page)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, BidsPage{Bids: nonNil(bids), NextCursor: next})
+}
+
+func (s *Server) handleListCommitments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	blocks, page, err := parseRangeAndPage(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	filter := store.CommitmentFilter{Blocks: blocks}
+	if stateStr := query.Get("state"); stateStr != "" {
+		state, err := commitment.ParseState(stateStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		filter.State = &state
+	}
+	records, next, err := s.history.ListCommitments(filter, page)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	commitments := make([]CommitmentResponse, 0, len(records))
+	for _, record := range records {
+		commitments = append(commitments, CommitmentResponse{Commitment: record.Commitment, State: record.State.String()})
+	}
+	writeJSON(w, http.StatusOK, CommitmentsPage{Commitments: commitments, NextCursor: next})
+}
+
+func parseRangeAndPage(query url.Values) (store.BlockRange, store.Page, error) {
+	var blocks store.BlockRange
+	var page store.Page
+	var err error
+	if blocks.From, err = parseUint(query, "fromBlock"); err != nil {
+		return blocks, page, err
+	}
+	if blocks.To, err = parseUint(query, "toBlock"); err != nil {
+		return blocks, page, err
+	}
+	limit, err := parseUint(query, "limit")
+	if err != nil {
+		return blocks, page, err
+	}
+	page.Limit = int(min(limit, store.MaxPageLimit))
+	page.Cursor = query.Get("cursor")
+	return blocks, page, nil
+}
+
+func parseUint(query url.Values, key string) (uint64, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return n, nil
+}
+
+func parseAddress(query url.Values, key string) (*common.Address, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(value) {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	address := common.HexToAddress(value)
+	return &address, nil
+}
+
+func parseHash(query url.Values, key string) (common.Hash, error) {
+	hash, err := hexutil.Decode(query.Get(key))
+	if err != nil || len(hash) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid %s", key)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+func writeListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, store.ErrInvalidCursor) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+// Empty pages are encoded as [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,80 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/rest"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func startHistoryServer(t *testing.T, history rest.HistoryBackend) string {
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", &mockAuctionBackend{}, &mockCommitmentBackend{}, history, nil, nil, nil)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	return "http://" + server.Addr().String()
+}
+
+func getJSON(t *testing.T, url string, body any) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(body))
+	}
+	return resp.StatusCode
+}
+
+func TestListBids(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	for block := int64(100); block < 105; block++ {
+		require.NoError(t, history.SaveBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(block), pk), time.Now()))
+	}
+	url := startHistoryServer(t, history)
+
+	var page rest.BidsPage
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/bids?fromBlock=101&toBlock=103&limit=2", &page))
+	require.Len(t, page.Bids, 2)
+	require.NotEmpty(t, page.NextCursor)
+
+	var next rest.BidsPage
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/bids?fromBlock=101&toBlock=103&limit=2&cursor="+page.NextCursor, &next))
+	require.Len(t, next.Bids, 1)
+	require.EqualValues(t, 103, next.Bids[0].Bid.L1Block.Uint64())
+	require.Empty(t, next.NextCursor)
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, url+"/v1/bids?relay=0x12", &page))
+	require.Equal(t, http.StatusBadRequest, getJSON(t, url+"/v1/bids?cursor=invalid", &page))
+}
+
+func TestListAuctionsAndCommitments(t *testing.T) {
+	history := store.NewMemoryStore()
+	require.NoError(t, history.SaveAuctionResult(100, nil, time.Now()))
+	url := startHistoryServer(t, history)
+
+	var auctions rest.AuctionsPage
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/auctions", &auctions))
+	require.Len(t, auctions.Auctions, 1)
+
+	var commitments rest.CommitmentsPage
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/commitments?state=fulfilled", &commitments))
+	require.NotNil(t, commitments.Commitments)
+	require.Empty(t, commitments.Commitments)
+	require.Equal(t, http.StatusBadRequest, getJSON(t, url+"/v1/commitments?state=bogus", &commitments))
+}
+
+func TestListWithoutHistory(t *testing.T) {
+	url := startHistoryServer(t, nil)
+	var page rest.AuctionsPage
+	require.Equal(t, http.StatusNotImplemented, getJSON(t, url+"/v1/auctions", &page))
+}
@@ -0,0 +1,560 @@
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/market"
+	"blob-preconfs/pkg/ratelimit"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Served at /v1/openapi.yaml, kept in sync with the routes below by tests
+//
+//go:embed openapi.yaml
+var OpenAPISpec []byte
+
+const maxRequestBodySize = 32 * 1024
+
+// Preconf requests may carry their blobs' encrypted contents, 128KiB per blob before hex encoding
+const maxPreconfRequestSize = 4 * 1024 * 1024
+
+// Satisfied by *listener.Listener
+type AuctionBackend interface {
+	SubmitBid(bid auction.SignedBid) error
+	GetAuction(l1Block uint64) (state listener.AuctionState, found bool)
+	SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription)
+}
+
+// Satisfied by *commitment.Coordinator
+type CommitmentBackend interface {
+	Get(hash common.Hash) (commitment.Commitment, commitment.State, bool)
+	ForVersionedHash(versionedHash common.Hash) []commitment.Commitment
+	ForTx(txHash common.Hash) []commitment.Commitment
+	Escalated(targetBlock *big.Int) []commitment.Commitment
+}
+
+// Satisfied by *escrow.Ledger
+type EscrowBackend interface {
+	Balance(relay common.Address) (escrow.Balance, error)
+}
+
+// Satisfied by *attestation.Quorum
+type AttestationBackend interface {
+	Add(a attestation.Attestation) error
+	Get(hash common.Hash) (attestation.Attested, bool)
+}
+
+// Satisfied by *listener.Listener
+type ReceiptBackend interface {
+	SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error)
+}
+
+// Satisfied by *heartbeat.Beacon
+type HeartbeatBackend interface {
+	Latest() *auction.Heartbeat
+}
+
+// Satisfied by *intake.Pool
+type IntakeBackend interface {
+	Submit(req intake.PreconfRequest) (common.Hash, error)
+}
+
+type Server struct {
+	logger      *slog.Logger
+	auctions    AuctionBackend
+	commitments CommitmentBackend
+	history     HistoryBackend
+	// Nil without history
+	stats      *market.Stats
+	escrow     EscrowBackend
+	heartbeats HeartbeatBackend
+	receipts   ReceiptBackend
+	limiter    *ratelimit.BidLimiter
+	httpServer *http.Server
+	listener   net.Listener
+	// Nil unless watchers attest commitments
+	attestations AttestationBackend
+	// Nil unless preconf requests are accepted
+	intake IntakeBackend
+	// Closed on Stop, to end long-lived event streams that would otherwise block shutdown
+	done chan struct{}
+}
+
+type AuctionResponse struct {
+	L1Block    uint64             `json:"l1Block"`
+	InProgress bool               `json:"inProgress"`
+	LeadingBid *auction.SignedBid `json:"leadingBid"`
+}
+
+type CommitmentResponse struct {
+	Commitment commitment.Commitment `json:"commitment"`
+	State      string                `json:"state"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	// Rejected bid's code, e.g. "belowReserve"
+	Code auction.RejectCode `json:"code,omitempty"`
+}
+
+// History listing routes respond 501 if history is nil. Bid submissions are rate limited by limiter, if non-nil.
+// If verifier is non-nil, bid submissions must be signed by a registered relay, other routes stay public.
+// Served over TLS if tlsConfig is non-nil, see tlsconfig.
+func NewServer(
+	logger *slog.Logger,
+	addr string,
+	auctions AuctionBackend,
+	commitments CommitmentBackend,
+	history HistoryBackend,
+	limiter *ratelimit.BidLimiter,
+	verifier *auth.Verifier,
+	tlsConfig *tls.Config,
+) *Server {
+	s := &Server{
+		logger:      logger,
+		auctions:    auctions,
+		commitments: commitments,
+		history:     history,
+		limiter:     limiter,
+		done:        make(chan struct{}),
+	}
+	if history != nil {
+		s.stats = market.NewStats(history)
+	}
+	mux := http.NewServeMux()
+	var submitBid http.Handler = http.HandlerFunc(s.handleSubmitBid)
+	if verifier != nil {
+		submitBid = verifier.Middleware(submitBid)
+	}
+	mux.HandleFunc("/v1/bids", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			submitBid.ServeHTTP(w, r)
+		case http.MethodGet:
+			s.requireHistory(s.handleListBids)(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		}
+	})
+	mux.HandleFunc("/v1/auctions", s.requireHistory(s.handleListAuctions))
+	mux.HandleFunc("/v1/auctions/", s.handleAuction)
+	mux.HandleFunc("/v1/commitments", s.handleCommitments)
+	mux.HandleFunc("/v1/commitments/", s.handleCommitment)
+	mux.HandleFunc("/v1/stats/prices", s.requireHistory(s.handleDailyPrices))
+	mux.HandleFunc("/v1/stats/bids", s.requireHistory(s.handleBidActivity))
+	mux.HandleFunc("/v1/stats/winners", s.requireHistory(s.handleWinners))
+	mux.HandleFunc("/v1/stats/preconfs", s.requireHistory(s.handlePreconfStats))
+	mux.HandleFunc("/v1/attestations", s.handleSubmitAttestation)
+	mux.HandleFunc("/v1/attestations/", s.handleAttestations)
+	mux.HandleFunc("/v1/relays/", s.handleEscrow)
+	mux.HandleFunc("/v1/events/winners", s.handleWinnerEvents)
+	mux.HandleFunc("/v1/heartbeat", s.handleHeartbeat)
+	mux.HandleFunc("/v1/requests", s.handleSubmitRequest)
+	mux.HandleFunc("/v1/openapi.yaml", s.handleOpenAPI)
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Serves relays' escrow balances, which respond 501 if unset. Must be called before Start.
+func (s *Server) SetEscrow(escrow EscrowBackend) {
+	s.escrow = escrow
+}
+
+// Serves the latest heartbeat, which responds 501 if unset. Must be called before Start.
+func (s *Server) SetHeartbeats(heartbeats HeartbeatBackend) {
+	s.heartbeats = heartbeats
+}
+
+// Accepts watchers' attestations of issued commitments and serves them, which respond 501 if unset. Must be
+// called before Start.
+func (s *Server) SetAttestations(attestations AttestationBackend) {
+	s.attestations = attestations
+}
+
+// Accepts users' preconf requests into the pool, committed to as their target block's auction opens, which
+// responds 501 if unset. Must be called before Start.
+func (s *Server) SetIntake(intake IntakeBackend) {
+	s.intake = intake
+}
+
+// Accepted bids are responded with the backend's signed receipt of when they arrived, rather than an empty body.
+// Must be called before Start.
+func (s *Server) SetReceipts(receipts ReceiptBackend) {
+	s.receipts = receipts
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	if s.httpServer.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.httpServer.TLSConfig)
+	}
+	s.listener = listener
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("rest server failed", "error", err)
+		}
+	}()
+	s.logger.Info("rest server started", "addr", listener.Addr(), "tls", s.httpServer.TLSConfig != nil)
+	return nil
+}
+
+// Address the server is listening on, useful when started on port 0
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serves other's API under prefix, e.g. /chains/holesky, alongside this server's, for another chain's auctions in
+// a multi-chain process. Must be called before Start. other is never started, but must be stopped with this server.
+func (s *Server) Mount(prefix string, other *Server) {
+	mounted := http.StripPrefix(prefix, other.httpServer.Handler)
+	mux := http.NewServeMux()
+	mux.Handle(prefix, mounted)
+	mux.Handle(prefix+"/", mounted)
+	mux.Handle("/", s.httpServer.Handler)
+	s.httpServer.Handler = mux
+}
+
+// Stops accepting new requests and waits for in-flight ones until ctx is done
+func (s *Server) Stop(ctx context.Context) error {
+	close(s.done)
+	err := s.httpServer.Shutdown(ctx)
+	s.logger.Info("rest server stopped")
+	return err
+}
+
+func (s *Server) handleSubmitBid(w http.ResponseWriter, r *http.Request) {
+	if err := s.limiter.AllowIP(r.RemoteAddr); err != nil {
+		writeError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	var bid auction.SignedBid
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBodySize)).Decode(&bid); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid bid: %w", err))
+		return
+	}
+	if err := bid.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := auth.CheckSigner(r.Context(), bid.Address); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	if err := s.limiter.AllowSigner(bid); err != nil {
+		writeError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	if s.receipts != nil {
+		receipt, err := s.receipts.SubmitBidWithReceipt(bid)
+		if err != nil {
+			writeRejection(w, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, receipt)
+		return
+	}
+	if err := s.auctions.SubmitBid(bid); err != nil {
+		writeRejection(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleAuction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	block, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/v1/auctions/"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid block number"))
+		return
+	}
+	state, found := s.auctions.GetAuction(block)
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no auction for l1 block %d", block))
+		return
+	}
+	writeJSON(w, http.StatusOK, AuctionResponse{
+		L1Block:    state.L1Block,
+		InProgress: state.InProgress,
+		LeadingBid: state.LeadingBid,
+	})
+}
+
+func (s *Server) handleCommitment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	hashHex := strings.TrimPrefix(r.URL.Path, "/v1/commitments/")
+	hash, err := hexutil.Decode(hashHex)
+	if err != nil || len(hash) != common.HashLength {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid commitment hash"))
+		return
+	}
+	c, state, found := s.commitments.Get(common.BytesToHash(hash))
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown commitment %s", hashHex))
+		return
+	}
+	writeJSON(w, http.StatusOK, CommitmentResponse{Commitment: c, State: state.String()})
+}
+
+// Looks up commitments by blob, from the coordinator, or lists them from history
+func (s *Server) handleCommitments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var lookups int
+	for _, key := range []string{"versionedHash", "txHash", "escalatedTo"} {
+		if query.Has(key) {
+			lookups++
+		}
+	}
+	if lookups == 0 {
+		s.requireHistory(s.handleListCommitments)(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	if lookups > 1 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("versionedHash, txHash and escalatedTo are exclusive"))
+		return
+	}
+	var commitments []commitment.Commitment
+	switch {
+	case query.Has("versionedHash"):
+		versionedHash, err := parseHash(query, "versionedHash")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		commitments = s.commitments.ForVersionedHash(versionedHash)
+	case query.Has("txHash"):
+		txHash, err := parseHash(query, "txHash")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		commitments = s.commitments.ForTx(txHash)
+	default:
+		block, err := strconv.ParseUint(query.Get("escalatedTo"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid escalatedTo"))
+			return
+		}
+		commitments = s.commitments.Escalated(new(big.Int).SetUint64(block))
+	}
+	responses := make([]CommitmentResponse, 0, len(commitments))
+	for _, c := range commitments {
+		_, state, _ := s.commitments.Get(c.Hash())
+		responses = append(responses, CommitmentResponse{Commitment: c, State: state.String()})
+	}
+	writeJSON(w, http.StatusOK, CommitmentsPage{Commitments: responses})
+}
+
+func (s *Server) handleEscrow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	relay, found := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/relays/"), "/escrow")
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found"))
+		return
+	}
+	if !common.IsHexAddress(relay) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid relay address"))
+		return
+	}
+	if s.escrow == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("escrow not available"))
+		return
+	}
+	balance, err := s.escrow.Balance(common.HexToAddress(relay))
+	if errors.Is(err, escrow.ErrNotBonded) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, balance)
+}
+
+func (s *Server) handleSubmitAttestation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	if s.attestations == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("attestations not available"))
+		return
+	}
+	var a attestation.Attestation
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBodySize)).Decode(&a); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid attestation: %w", err))
+		return
+	}
+	if err := s.attestations.Add(a); err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, attestation.ErrUnknownWatcher):
+			status = http.StatusForbidden
+		case errors.Is(err, attestation.ErrUnknownCommitment):
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleAttestations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	if s.attestations == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("attestations not available"))
+		return
+	}
+	hashHex := strings.TrimPrefix(r.URL.Path, "/v1/attestations/")
+	hash, err := hexutil.Decode(hashHex)
+	if err != nil || len(hash) != common.HashLength {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid commitment hash"))
+		return
+	}
+	attested, found := s.attestations.Get(common.BytesToHash(hash))
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown commitment %s", hashHex))
+		return
+	}
+	writeJSON(w, http.StatusOK, attested)
+}
+
+type submitRequestResponse struct {
+	ID common.Hash `json:"id"`
+}
+
+func (s *Server) handleSubmitRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	if s.intake == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("preconf requests not accepted"))
+		return
+	}
+	var req intake.PreconfRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxPreconfRequestSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid preconf request: %w", err))
+		return
+	}
+	id, err := s.intake.Submit(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, intake.ErrQuotaExceeded):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, intake.ErrDuplicateRequest):
+			status = http.StatusConflict
+		case errors.Is(err, intake.ErrInsufficientDeposit):
+			status = http.StatusPaymentRequired
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, submitRequestResponse{ID: id})
+}
+
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	if s.heartbeats == nil {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("heartbeat not available"))
+		return
+	}
+	h := s.heartbeats.Latest()
+	if h == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no heartbeat yet"))
+		return
+	}
+	writeJSON(w, http.StatusOK, h)
+}
+
+// Responds 501 for listing routes if the server has no history backend, and only allows GET
+func (s *Server) requireHistory(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+			return
+		}
+		if s.history == nil {
+			writeError(w, http.StatusNotImplemented, fmt.Errorf("history not available"))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(OpenAPISpec)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// Bidders that may not bid at all are refused with 403, other rejected bids conflict with the auction's state
+func writeRejection(w http.ResponseWriter, err error) {
+	code, _ := auction.RejectCodeOf(err)
+	status := http.StatusConflict
+	switch code {
+	case auction.RejectDenied, auction.RejectNotAllowed, auction.RejectNotRegistered:
+		status = http.StatusForbidden
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error(), Code: code})
+}
@@ -0,0 +1,601 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/attestation"
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/auth"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/escrow"
+	"blob-preconfs/pkg/intake"
+	"blob-preconfs/pkg/listener"
+	"blob-preconfs/pkg/ratelimit"
+	"blob-preconfs/pkg/rest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type mockAuctionBackend struct {
+	submitted []auction.SignedBid
+	auctions  map[uint64]listener.AuctionState
+	feed      event.Feed
+}
+
+func (m *mockAuctionBackend) SubscribeEvents(bufferSize int) (<-chan auction.Event, event.Subscription) {
+	ch := make(chan auction.Event, bufferSize)
+	return ch, m.feed.Subscribe(ch)
+}
+
+func (m *mockAuctionBackend) SubmitBid(bid auction.SignedBid) error {
+	if bid.L1Block.Uint64() != 100 {
+		return fmt.Errorf("bid is for a different block")
+	}
+	m.submitted = append(m.submitted, bid)
+	return nil
+}
+
+func (m *mockAuctionBackend) GetAuction(l1Block uint64) (listener.AuctionState, bool) {
+	state, ok := m.auctions[l1Block]
+	return state, ok
+}
+
+type mockCommitmentBackend struct {
+	commitments map[common.Hash]commitment.Commitment
+	// Versioned hashes of included blob txs' blobs, by tx hash
+	txs map[common.Hash][]common.Hash
+	// Commitments carried into each block's auction, by target block
+	escalated map[uint64][]commitment.Commitment
+}
+
+func (m *mockCommitmentBackend) Get(hash common.Hash) (commitment.Commitment, commitment.State, bool) {
+	c, ok := m.commitments[hash]
+	return c, commitment.StateFulfilled, ok
+}
+
+func (m *mockCommitmentBackend) ForVersionedHash(versionedHash common.Hash) []commitment.Commitment {
+	var commitments []commitment.Commitment
+	for _, c := range m.commitments {
+		if slices.Contains(c.VersionedHashes, versionedHash) {
+			commitments = append(commitments, c)
+		}
+	}
+	return commitments
+}
+
+func (m *mockCommitmentBackend) ForTx(txHash common.Hash) []commitment.Commitment {
+	var commitments []commitment.Commitment
+	for _, vh := range m.txs[txHash] {
+		commitments = append(commitments, m.ForVersionedHash(vh)...)
+	}
+	return commitments
+}
+
+func (m *mockCommitmentBackend) Escalated(targetBlock *big.Int) []commitment.Commitment {
+	return m.escalated[targetBlock.Uint64()]
+}
+
+func startServer(t *testing.T, auctions rest.AuctionBackend, commitments rest.CommitmentBackend) string {
+	return startServerWithLimiter(t, auctions, commitments, nil)
+}
+
+func startServerWithLimiter(
+	t *testing.T,
+	auctions rest.AuctionBackend,
+	commitments rest.CommitmentBackend,
+	limiter *ratelimit.BidLimiter,
+) string {
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", auctions, commitments, nil, limiter, nil, nil)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	return "http://" + server.Addr().String()
+}
+
+func TestPostBid(t *testing.T) {
+	backend := &mockAuctionBackend{}
+	url := startServer(t, backend, &mockCommitmentBackend{})
+
+	pk, _ := crypto.GenerateKey()
+	post := func(bid *auction.SignedBid) int {
+		resp, err := http.Post(url+"/v1/bids", "application/json", bytes.NewBufferString(auction.EncodeSignedBid(bid)))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusAccepted, post(auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)))
+	require.Equal(t, http.StatusConflict, post(auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(99), pk)))
+	require.Equal(t, http.StatusBadRequest, post(auction.MustCreateSignedBid(big.NewInt(0), big.NewInt(100), pk)))
+	require.Len(t, backend.submitted, 1)
+
+	req, _ := http.NewRequest(http.MethodDelete, url+"/v1/bids", nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestPostBidRateLimited(t *testing.T) {
+	backend := &mockAuctionBackend{}
+	limiter := ratelimit.NewBidLimiter(ratelimit.Config{Rate: 1, Burst: 2}, ratelimit.Config{})
+	url := startServerWithLimiter(t, backend, &mockCommitmentBackend{}, limiter)
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.EncodeSignedBid(auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk))
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		resp, err := http.Post(url+"/v1/bids", "application/json", bytes.NewBufferString(bid))
+		require.NoError(t, err)
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+	require.Equal(t, []int{http.StatusAccepted, http.StatusAccepted, http.StatusTooManyRequests}, statuses)
+}
+
+type mockRegistry struct {
+	registered map[common.Address]bool
+}
+
+func (r *mockRegistry) IsRegisteredOnSettlementLayer(address common.Address) bool {
+	return r.registered[address]
+}
+
+func TestPostBidAuthenticated(t *testing.T) {
+	backend := &mockAuctionBackend{auctions: map[uint64]listener.AuctionState{100: {L1Block: 100}}}
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	registry := &mockRegistry{registered: map[common.Address]bool{
+		crypto.PubkeyToAddress(pk1.PublicKey): true,
+		crypto.PubkeyToAddress(pk2.PublicKey): true,
+	}}
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", backend, &mockCommitmentBackend{}, nil, nil, auth.NewVerifier(registry, 30*time.Second), nil)
+	require.NoError(t, server.Start())
+	defer server.Stop(context.Background())
+	url := "http://" + server.Addr().String()
+
+	post := func(client *http.Client, bid *auction.SignedBid) int {
+		resp, err := client.Post(url+"/v1/bids", "application/json", bytes.NewBufferString(auction.EncodeSignedBid(bid)))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	bid1 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)
+	bid2 := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk2)
+	signed := &http.Client{Transport: &auth.Transport{PrivateKey: pk1}}
+	require.Equal(t, http.StatusUnauthorized, post(http.DefaultClient, bid1))
+	require.Equal(t, http.StatusAccepted, post(signed, bid1))
+	require.Equal(t, http.StatusForbidden, post(signed, bid2))
+	require.Len(t, backend.submitted, 1)
+
+	// Reads stay public
+	resp, err := http.Get(url + "/v1/auctions/100")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+type mockReceiptBackend struct {
+	*mockAuctionBackend
+	key *ecdsa.PrivateKey
+}
+
+func (m *mockReceiptBackend) SubmitBidWithReceipt(bid auction.SignedBid) (*auction.Receipt, error) {
+	if err := m.SubmitBid(bid); err != nil {
+		return nil, err
+	}
+	return auction.CreateSignedReceipt(bid, time.Now(), m.key)
+}
+
+func TestPostBidWithReceipt(t *testing.T) {
+	auctioneerKey, _ := crypto.GenerateKey()
+	backend := &mockReceiptBackend{mockAuctionBackend: &mockAuctionBackend{}, key: auctioneerKey}
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", backend, &mockCommitmentBackend{}, nil, nil, nil, nil)
+	server.SetReceipts(backend)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	url := "http://" + server.Addr().String()
+
+	pk, _ := crypto.GenerateKey()
+	bid := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	resp, err := http.Post(url+"/v1/bids", "application/json", bytes.NewBufferString(auction.EncodeSignedBid(bid)))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	var receipt auction.Receipt
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&receipt))
+	require.True(t, receipt.Verify())
+	require.True(t, receipt.Covers(*bid))
+	require.Equal(t, crypto.PubkeyToAddress(auctioneerKey.PublicKey), receipt.Auctioneer)
+
+	resp, err = http.Post(url+"/v1/bids", "application/json", bytes.NewBufferString(auction.EncodeSignedBid(auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(99), pk))))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	require.Len(t, backend.submitted, 1)
+}
+
+func TestGetAuction(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	backend := &mockAuctionBackend{auctions: map[uint64]listener.AuctionState{
+		100: {L1Block: 100, LeadingBid: winner},
+	}}
+	url := startServer(t, backend, &mockCommitmentBackend{})
+
+	resp, err := http.Get(url + "/v1/auctions/100")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got rest.AuctionResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, rest.AuctionResponse{L1Block: 100, LeadingBid: winner}, got)
+
+	for path, status := range map[string]int{"/v1/auctions/101": http.StatusNotFound, "/v1/auctions/abc": http.StatusBadRequest} {
+		resp, err := http.Get(url + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode, path)
+	}
+}
+
+func TestMount(t *testing.T) {
+	primary := &mockAuctionBackend{auctions: map[uint64]listener.AuctionState{100: {L1Block: 100}}}
+	holesky := &mockAuctionBackend{auctions: map[uint64]listener.AuctionState{200: {L1Block: 200}}}
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", primary, &mockCommitmentBackend{}, nil, nil, nil, nil)
+	mounted := rest.NewServer(slog.Default(), "", holesky, &mockCommitmentBackend{}, nil, nil, nil, nil)
+	server.Mount("/chains/holesky", mounted)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		server.Stop(context.Background())
+		mounted.Stop(context.Background())
+	})
+	url := "http://" + server.Addr().String()
+
+	for path, status := range map[string]int{
+		"/v1/auctions/100":                http.StatusOK,
+		"/v1/auctions/200":                http.StatusNotFound,
+		"/chains/holesky/v1/auctions/200": http.StatusOK,
+		"/chains/holesky/v1/auctions/100": http.StatusNotFound,
+	} {
+		resp, err := http.Get(url + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode, path)
+	}
+}
+
+type mockEscrowBackend map[common.Address]escrow.Balance
+
+func (m mockEscrowBackend) Balance(relay common.Address) (escrow.Balance, error) {
+	if balance, ok := m[relay]; ok {
+		return balance, nil
+	}
+	return escrow.Balance{}, escrow.ErrNotBonded
+}
+
+func TestGetEscrow(t *testing.T) {
+	relay := common.HexToAddress("0x01")
+	balance := escrow.Balance{Relay: relay, BalanceWei: big.NewInt(1000), PendingDebitsWei: big.NewInt(400), UnsettledWins: 1, MaxBidWei: big.NewInt(600)}
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", &mockAuctionBackend{}, &mockCommitmentBackend{}, nil, nil, nil, nil)
+	server.SetEscrow(mockEscrowBackend{relay: balance})
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	url := "http://" + server.Addr().String()
+
+	resp, err := http.Get(url + "/v1/relays/" + relay.Hex() + "/escrow")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got escrow.Balance
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, balance, got)
+
+	for path, status := range map[string]int{
+		"/v1/relays/0x02/escrow": http.StatusBadRequest,
+		"/v1/relays/" + common.HexToAddress("0x02").Hex() + "/escrow": http.StatusNotFound,
+		"/v1/relays/" + relay.Hex():                                   http.StatusNotFound,
+	} {
+		resp, err := http.Get(url + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode, path)
+	}
+
+	resp, err = http.Get(startServer(t, &mockAuctionBackend{}, &mockCommitmentBackend{}) + "/v1/relays/" + relay.Hex() + "/escrow")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+type mockHeartbeatBackend struct {
+	latest *auction.Heartbeat
+}
+
+func (m *mockHeartbeatBackend) Latest() *auction.Heartbeat {
+	return m.latest
+}
+
+func TestGetHeartbeat(t *testing.T) {
+	backend := &mockHeartbeatBackend{}
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", &mockAuctionBackend{}, &mockCommitmentBackend{}, nil, nil, nil, nil)
+	server.SetHeartbeats(backend)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	url := "http://" + server.Addr().String()
+
+	resp, err := http.Get(url + "/v1/heartbeat")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode, "none yet")
+
+	pk, _ := crypto.GenerateKey()
+	backend.latest, err = auction.CreateSignedHeartbeat(10, 100, common.Hash{0x01}, false, "dev", time.Now(), pk)
+	require.NoError(t, err)
+	resp, err = http.Get(url + "/v1/heartbeat")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got auction.Heartbeat
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, *backend.latest, got)
+	require.True(t, got.Verify())
+
+	resp, err = http.Get(startServer(t, &mockAuctionBackend{}, &mockCommitmentBackend{}) + "/v1/heartbeat")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestAttestations(t *testing.T) {
+	relay, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(100),
+		FeeWei:          big.NewInt(5),
+	}, relay)
+	require.NoError(t, err)
+	commitments := &mockCommitmentBackend{commitments: map[common.Hash]commitment.Commitment{c.Hash(): *c}}
+	watcher, _ := crypto.GenerateKey()
+	stranger, _ := crypto.GenerateKey()
+	quorum, err := attestation.NewQuorum(slog.Default(), attestation.Config{
+		Watchers:  []common.Address{crypto.PubkeyToAddress(watcher.PublicKey)},
+		Threshold: 1,
+	}, commitments)
+	require.NoError(t, err)
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", &mockAuctionBackend{}, commitments, nil, nil, nil, nil)
+	server.SetAttestations(quorum)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	url := "http://" + server.Addr().String()
+
+	post := func(c commitment.Commitment, key *ecdsa.PrivateKey) int {
+		a, err := attestation.CreateSignedAttestation(c, key)
+		require.NoError(t, err)
+		body, _ := json.Marshal(a)
+		resp, err := http.Post(url+"/v1/attestations", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusForbidden, post(*c, stranger))
+	require.Equal(t, http.StatusNotFound, post(commitment.Commitment{TargetBlock: big.NewInt(1), ExpiryBlock: big.NewInt(1), FeeWei: big.NewInt(1)}, watcher))
+	require.Equal(t, http.StatusAccepted, post(*c, watcher))
+
+	resp, err := http.Get(url + "/v1/attestations/" + c.Hash().Hex())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var attested attestation.Attested
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&attested))
+	require.True(t, attested.Quorum)
+	require.Len(t, attested.Attestations, 1)
+	require.True(t, attested.Attestations[0].Verify())
+
+	resp, err = http.Get(startServer(t, &mockAuctionBackend{}, commitments) + "/v1/attestations/" + c.Hash().Hex())
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestSubmitRequest(t *testing.T) {
+	pool, err := intake.NewPool(slog.Default(), intake.Config{MaxPendingPerAddress: 1}, nil)
+	require.NoError(t, err)
+	server := rest.NewServer(slog.Default(), "127.0.0.1:0", &mockAuctionBackend{}, &mockCommitmentBackend{}, nil, nil, nil, nil)
+	server.SetIntake(pool)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop(context.Background()) })
+	url := "http://" + server.Addr().String()
+
+	post := func(url string, req *intake.PreconfRequest) *http.Response {
+		body, _ := json.Marshal(req)
+		resp, err := http.Post(url+"/v1/requests", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		return resp
+	}
+	pk, _ := crypto.GenerateKey()
+	req, err := intake.CreateSignedRequest([]common.Hash{{0x01}}, big.NewInt(100), big.NewInt(1000), pk)
+	require.NoError(t, err)
+	resp := post(url, req)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	var accepted struct {
+		ID common.Hash `json:"id"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&accepted))
+	require.Equal(t, req.Hash(), accepted.ID)
+	require.Len(t, pool.Pending(big.NewInt(100)), 1)
+
+	resp = post(url, req)
+	resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+	other, err := intake.CreateSignedRequest([]common.Hash{{0x02}}, big.NewInt(100), big.NewInt(1000), pk)
+	require.NoError(t, err)
+	resp = post(url, other)
+	resp.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "max pending per address")
+	other.MaxFeeWei = big.NewInt(2000)
+	resp = post(url, other)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "signature no longer matches")
+
+	resp = post(startServer(t, &mockAuctionBackend{}, &mockCommitmentBackend{}), req)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestGetCommitment(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(100),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	backend := &mockCommitmentBackend{commitments: map[common.Hash]commitment.Commitment{c.Hash(): *c}}
+	url := startServer(t, &mockAuctionBackend{}, backend)
+
+	resp, err := http.Get(url + "/v1/commitments/" + c.Hash().Hex())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got rest.CommitmentResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, "fulfilled", got.State)
+	require.Equal(t, c.Hash(), got.Commitment.Hash())
+
+	for path, status := range map[string]int{
+		"/v1/commitments/" + common.Hash{0x02}.Hex(): http.StatusNotFound,
+		"/v1/commitments/0x1234":                     http.StatusBadRequest,
+	} {
+		resp, err := http.Get(url + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode, path)
+	}
+}
+
+func TestLookupCommitmentsByBlob(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}, {0x02}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(100),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	backend := &mockCommitmentBackend{
+		commitments: map[common.Hash]commitment.Commitment{c.Hash(): *c},
+		txs:         map[common.Hash][]common.Hash{{0xaa}: {{0x02}}},
+	}
+	url := startServer(t, &mockAuctionBackend{}, backend)
+
+	for _, query := range []string{"versionedHash=" + common.Hash{0x01}.Hex(), "txHash=" + common.Hash{0xaa}.Hex()} {
+		var page rest.CommitmentsPage
+		require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/commitments?"+query, &page), query)
+		require.Len(t, page.Commitments, 1, query)
+		require.Equal(t, c.Hash(), page.Commitments[0].Commitment.Hash())
+		require.Equal(t, "fulfilled", page.Commitments[0].State)
+	}
+
+	var page rest.CommitmentsPage
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/commitments?versionedHash="+common.Hash{0x03}.Hex(), &page))
+	require.NotNil(t, page.Commitments, "no commitments are encoded as []")
+	require.Empty(t, page.Commitments)
+	for _, query := range []string{
+		"versionedHash=0x1234",
+		"txHash=",
+		"versionedHash=" + common.Hash{0x01}.Hex() + "&txHash=" + common.Hash{0xaa}.Hex(),
+	} {
+		require.Equal(t, http.StatusBadRequest, getJSON(t, url+"/v1/commitments?"+query, &page), query)
+	}
+	// Listing without history still isn't available
+	require.Equal(t, http.StatusNotImplemented, getJSON(t, url+"/v1/commitments", &page))
+}
+
+func TestEscalatedCommitments(t *testing.T) {
+	pk, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(101),
+		ExpiryBlock:     big.NewInt(101),
+		FeeWei:          big.NewInt(5),
+		Escalations:     1,
+	}, pk)
+	require.NoError(t, err)
+	backend := &mockCommitmentBackend{
+		commitments: map[common.Hash]commitment.Commitment{c.Hash(): *c},
+		escalated:   map[uint64][]commitment.Commitment{101: {*c}},
+	}
+	url := startServer(t, &mockAuctionBackend{}, backend)
+
+	var page rest.CommitmentsPage
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/commitments?escalatedTo=101", &page))
+	require.Len(t, page.Commitments, 1)
+	require.Equal(t, c.Hash(), page.Commitments[0].Commitment.Hash())
+	require.Equal(t, uint64(1), page.Commitments[0].Commitment.Escalations)
+
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/commitments?escalatedTo=102", &page))
+	require.NotNil(t, page.Commitments)
+	require.Empty(t, page.Commitments)
+	for _, query := range []string{"escalatedTo=", "escalatedTo=-1", "escalatedTo=101&txHash=" + common.Hash{0xaa}.Hex()} {
+		require.Equal(t, http.StatusBadRequest, getJSON(t, url+"/v1/commitments?"+query, &page), query)
+	}
+}
+
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	url := startServer(t, &mockAuctionBackend{}, &mockCommitmentBackend{})
+
+	resp, err := http.Get(url + "/v1/openapi.yaml")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var spec struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(body, &spec))
+	var routes []string
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			routes = append(routes, method+" "+path)
+		}
+	}
+	sort.Strings(routes)
+	require.Equal(t, []string{
+		"get /v1/attestations/{hash}",
+		"get /v1/auctions",
+		"get /v1/auctions/{block}",
+		"get /v1/bids",
+		"get /v1/commitments",
+		"get /v1/commitments/{hash}",
+		"get /v1/events/winners",
+		"get /v1/heartbeat",
+		"get /v1/openapi.yaml",
+		"get /v1/relays/{address}/escrow",
+		"get /v1/stats/bids",
+		"get /v1/stats/preconfs",
+		"get /v1/stats/prices",
+		"get /v1/stats/winners",
+		"post /v1/attestations",
+		"post /v1/bids",
+		"post /v1/requests",
+	}, routes)
+}
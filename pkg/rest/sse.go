@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+)
+
+const (
+	eventBufferSize   = 64
+	keepAliveInterval = 15 * time.Second
+)
+
+// Server-sent events feed of auction winners and their settlement, for lightweight consumers
+// that don't want to maintain websocket connections.
+func (s *Server) handleWinnerEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+		return
+	}
+	events, sub := s.auctions.SubscribeEvents(eventBufferSize)
+	defer sub.Unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if !isWinnerEvent(ev) {
+				continue
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				s.logger.Error("failed to encode auction event", "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+func isWinnerEvent(ev auction.Event) bool {
+	return (ev.Type == auction.EventAuctionClosed && ev.Bid != nil) || ev.Type == auction.EventWinnerFallback || ev.Type == auction.EventSettlement ||
+		ev.Type == auction.EventSlotMissed
+}
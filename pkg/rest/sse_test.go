@@ -0,0 +1,61 @@
+package rest_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWinnerEvents(t *testing.T) {
+	backend := &mockAuctionBackend{}
+	url := startServer(t, backend, &mockCommitmentBackend{})
+
+	resp, err := http.Get(url + "/v1/events/winners")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	pk, _ := crypto.GenerateKey()
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	settlementTx := common.Hash{0x01}
+	sent := []auction.Event{
+		{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)},
+		{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: winner},
+		{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: winner},
+		{Type: auction.EventAuctionClosed, L1Block: big.NewInt(101)},
+		{Type: auction.EventSettlement, L1Block: big.NewInt(100), Bid: winner, SettlementTx: &settlementTx},
+	}
+	require.Eventually(t, func() bool { return backend.feed.Send(sent[0]) > 0 }, time.Second, 10*time.Millisecond)
+	for _, ev := range sent[1:] {
+		backend.feed.Send(ev)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024)
+	var received []auction.Event
+	var eventNames []string
+	for len(received) < 2 && scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			eventNames = append(eventNames, name)
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var ev auction.Event
+			require.NoError(t, json.Unmarshal([]byte(data), &ev))
+			received = append(received, ev)
+		}
+	}
+	require.Equal(t, []string{"auctionClosed", "settlement"}, eventNames)
+	require.Equal(t, *winner, *received[0].Bid)
+	require.Equal(t, settlementTx, *received[1].SettlementTx)
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"blob-preconfs/pkg/market"
+	"blob-preconfs/pkg/store"
+)
+
+type DailyPricesResponse struct {
+	Days []market.DailyPrice `json:"days"`
+}
+
+type WinnersResponse struct {
+	Relays []market.RelayWins `json:"relays"`
+}
+
+func (s *Server) handleDailyPrices(w http.ResponseWriter, r *http.Request) {
+	blocks, ok := parseStatsRange(w, r.URL.Query())
+	if !ok {
+		return
+	}
+	days, err := s.stats.DailyPrices(blocks)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, DailyPricesResponse{Days: days})
+}
+
+func (s *Server) handleBidActivity(w http.ResponseWriter, r *http.Request) {
+	blocks, ok := parseStatsRange(w, r.URL.Query())
+	if !ok {
+		return
+	}
+	activity, err := s.stats.BidActivity(blocks)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, activity)
+}
+
+func (s *Server) handleWinners(w http.ResponseWriter, r *http.Request) {
+	blocks, ok := parseStatsRange(w, r.URL.Query())
+	if !ok {
+		return
+	}
+	winners, err := s.stats.Winners(blocks)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, WinnersResponse{Relays: winners})
+}
+
+func (s *Server) handlePreconfStats(w http.ResponseWriter, r *http.Request) {
+	blocks, ok := parseStatsRange(w, r.URL.Query())
+	if !ok {
+		return
+	}
+	stats, err := s.stats.Preconfs(blocks)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// Writes a 400 and returns false if the range is invalid
+func parseStatsRange(w http.ResponseWriter, query url.Values) (store.BlockRange, bool) {
+	var blocks store.BlockRange
+	var err error
+	if blocks.From, err = parseUint(query, "fromBlock"); err == nil {
+		blocks.To, err = parseUint(query, "toBlock")
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return blocks, false
+	}
+	return blocks, true
+}
+
+func writeStatsError(w http.ResponseWriter, err error) {
+	if errors.Is(err, market.ErrTooManyRecords) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err)
+}
@@ -0,0 +1,51 @@
+package rest_test
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/market"
+	"blob-preconfs/pkg/rest"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	closedAt := time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC)
+	for block := int64(100); block < 104; block++ {
+		bid := auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk)
+		require.NoError(t, history.SaveBid(*bid, closedAt))
+		require.NoError(t, history.SaveAuctionResult(uint64(block), bid, closedAt))
+	}
+	url := startHistoryServer(t, history)
+
+	var prices rest.DailyPricesResponse
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/stats/prices?fromBlock=101&toBlock=102", &prices))
+	require.Equal(t, []market.DailyPrice{{
+		Day: "2026-10-13", Auctions: 2, Won: 2,
+		AverageClearingPriceWei: big.NewInt(101), MinClearingPriceWei: big.NewInt(101), MaxClearingPriceWei: big.NewInt(102),
+	}}, prices.Days)
+
+	var activity market.BidActivity
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/stats/bids", &activity))
+	require.Equal(t, 4, activity.Bids)
+
+	var winners rest.WinnersResponse
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/stats/winners?fromBlock=103", &winners))
+	require.Len(t, winners.Relays, 1)
+	require.Equal(t, 1, winners.Relays[0].Wins)
+
+	var preconfs market.PreconfStats
+	require.Equal(t, http.StatusOK, getJSON(t, url+"/v1/stats/preconfs", &preconfs))
+	require.Nil(t, preconfs.HonorRate)
+
+	require.Equal(t, http.StatusBadRequest, getJSON(t, url+"/v1/stats/prices?fromBlock=x", &prices))
+	require.Equal(t, http.StatusNotImplemented, getJSON(t, startHistoryServer(t, nil)+"/v1/stats/winners", &winners))
+}
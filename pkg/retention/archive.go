@@ -0,0 +1,81 @@
+package retention
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+)
+
+// Commitment state isn't part of CommitmentRecord's JSON
+type ArchivedCommitment struct {
+	Commitment commitment.Commitment `json:"commitment"`
+	State      string                `json:"state"`
+	UpdatedAt  time.Time             `json:"updatedAt"`
+}
+
+// Archives records to gzipped JSON lines files in a directory, e.g. a mounted cold storage bucket.
+// Each call writes a new file named after the record kind and the time it was written.
+type FileArchiver struct {
+	dir string
+
+	mu  sync.Mutex // Protects seq
+	seq uint64
+}
+
+func NewFileArchiver(dir string) (*FileArchiver, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileArchiver{dir: dir}, nil
+}
+
+func (a *FileArchiver) ArchiveBids(records []store.BidRecord) error {
+	return write(a.nextPath("bids"), records)
+}
+
+func (a *FileArchiver) ArchiveCommitments(records []store.CommitmentRecord) error {
+	archived := make([]ArchivedCommitment, len(records))
+	for i, record := range records {
+		archived[i] = ArchivedCommitment{Commitment: record.Commitment, State: record.State.String(), UpdatedAt: record.UpdatedAt}
+	}
+	return write(a.nextPath("commitments"), archived)
+}
+
+func write[T any](path string, records []T) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(file)
+	encoder := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Suffixed with a sequence, as several pages may be archived within the same clock tick
+func (a *FileArchiver) nextPath(kind string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.seq++
+	return filepath.Join(a.dir, fmt.Sprintf("%s-%d-%d.jsonl.gz", kind, time.Now().UnixNano(), a.seq))
+}
@@ -0,0 +1,158 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+)
+
+// Commitment states that no longer change
+var finalStates = []commitment.State{
+	commitment.StateFulfilled,
+	commitment.StateMissed,
+	commitment.StateRenewed,
+	commitment.StateEscalated,
+}
+
+// Satisfied by store.Store implementations
+type Store interface {
+	ListBids(filter store.BidFilter, page store.Page) ([]store.BidRecord, string, error)
+	ListCommitments(filter store.CommitmentFilter, page store.Page) ([]store.CommitmentRecord, string, error)
+	DeleteBids(filter store.BidFilter) (int, error)
+	DeleteCommitments(filter store.CommitmentFilter) (int, error)
+}
+
+// Receives records before they're pruned, e.g. to move them to cold storage. Satisfied by *FileArchiver.
+type Archiver interface {
+	ArchiveBids(records []store.BidRecord) error
+	ArchiveCommitments(records []store.CommitmentRecord) error
+}
+
+type EthClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+type Config struct {
+	// Bids received longer ago are pruned, 0 keeps bids forever
+	BidRetention time.Duration
+	// Commitments in a final state are pruned once their target block is this many blocks old, 0 keeps them forever
+	CommitmentFinality uint64
+	// Between background prune runs
+	Interval time.Duration
+}
+
+type Result struct {
+	Bids        int
+	Commitments int
+}
+
+// Prunes history according to the retention policy, archiving records first when an archiver is set
+type Pruner struct {
+	logger    *slog.Logger
+	config    Config
+	history   Store
+	ethClient EthClient
+	archiver  Archiver
+}
+
+// archiver may be nil, in which case pruned records are discarded
+func NewPruner(logger *slog.Logger, config Config, history Store, ethClient EthClient, archiver Archiver) *Pruner {
+	return &Pruner{
+		logger:    logger,
+		config:    config,
+		history:   history,
+		ethClient: ethClient,
+		archiver:  archiver,
+	}
+}
+
+// Prunes every Interval until ctx is done
+func (p *Pruner) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			result, err := p.Prune(ctx)
+			if err != nil {
+				p.logger.Error("failed to prune history", "error", err)
+				continue
+			}
+			p.logger.Info("pruned history", "bids", result.Bids, "commitments", result.Commitments)
+		}
+	}()
+}
+
+func (p *Pruner) Prune(ctx context.Context) (Result, error) {
+	var result Result
+	if p.config.BidRetention > 0 {
+		filter := store.BidFilter{ReceivedBefore: time.Now().Add(-p.config.BidRetention)}
+		if err := archive(p.archiver, func(page store.Page) ([]store.BidRecord, string, error) {
+			return p.history.ListBids(filter, page)
+		}, Archiver.ArchiveBids); err != nil {
+			return result, fmt.Errorf("failed to archive bids: %w", err)
+		}
+		deleted, err := p.history.DeleteBids(filter)
+		if err != nil {
+			return result, fmt.Errorf("failed to prune bids: %w", err)
+		}
+		result.Bids = deleted
+	}
+
+	if p.config.CommitmentFinality > 0 {
+		blockNumber, err := p.ethClient.BlockNumber(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to get block number: %w", err)
+		}
+		if blockNumber <= p.config.CommitmentFinality {
+			return result, nil
+		}
+		// Block 0 never has commitments, and To of 0 would be unbounded
+		for _, state := range finalStates {
+			state := state
+			filter := store.CommitmentFilter{Blocks: store.BlockRange{From: 1, To: blockNumber - p.config.CommitmentFinality}, State: &state}
+			if err := archive(p.archiver, func(page store.Page) ([]store.CommitmentRecord, string, error) {
+				return p.history.ListCommitments(filter, page)
+			}, Archiver.ArchiveCommitments); err != nil {
+				return result, fmt.Errorf("failed to archive commitments: %w", err)
+			}
+			deleted, err := p.history.DeleteCommitments(filter)
+			if err != nil {
+				return result, fmt.Errorf("failed to prune commitments: %w", err)
+			}
+			result.Commitments += deleted
+		}
+	}
+	return result, nil
+}
+
+// Hands every record of a paginated listing to the archiver, page by page
+func archive[T any](archiver Archiver, list func(page store.Page) ([]T, string, error), write func(Archiver, []T) error) error {
+	if archiver == nil {
+		return nil
+	}
+	page := store.Page{Limit: store.MaxPageLimit}
+	for {
+		records, next, err := list(page)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			if err := write(archiver, records); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		page.Cursor = next
+	}
+}
@@ -0,0 +1,113 @@
+package retention_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/retention"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockEthClient struct {
+	blockNumber uint64
+}
+
+func (m *mockEthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return m.blockNumber, nil
+}
+
+func newCommitment(t *testing.T, targetBlock int64) commitment.Commitment {
+	pk, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(targetBlock),
+		ExpiryBlock:     big.NewInt(targetBlock),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	return *c
+}
+
+func readArchive[T any](t *testing.T, pattern string) []T {
+	paths, err := filepath.Glob(pattern)
+	require.NoError(t, err)
+	var records []T
+	for _, path := range paths {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(gz)
+		for scanner.Scan() {
+			var record T
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		require.NoError(t, scanner.Err())
+		file.Close()
+	}
+	return records
+}
+
+func TestPrune(t *testing.T) {
+	history := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	old := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	recent := auction.MustCreateSignedBid(big.NewInt(44), big.NewInt(101), pk)
+	require.NoError(t, history.SaveBid(*old, time.Now().Add(-48*time.Hour)))
+	require.NoError(t, history.SaveBid(*recent, time.Now()))
+
+	final := newCommitment(t, 100)
+	active := newCommitment(t, 100)
+	recentFinal := newCommitment(t, 190)
+	require.NoError(t, history.SaveCommitment(final, commitment.StateFulfilled))
+	require.NoError(t, history.SaveCommitment(active, commitment.StateActive))
+	require.NoError(t, history.SaveCommitment(recentFinal, commitment.StateMissed))
+
+	dir := t.TempDir()
+	archiver, err := retention.NewFileArchiver(dir)
+	require.NoError(t, err)
+	pruner := retention.NewPruner(slog.Default(), retention.Config{
+		BidRetention:       24 * time.Hour,
+		CommitmentFinality: 64,
+	}, history, &mockEthClient{blockNumber: 200}, archiver)
+
+	result, err := pruner.Prune(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, retention.Result{Bids: 1, Commitments: 1}, result)
+
+	bids, _, err := history.ListBids(store.BidFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, bids, 1)
+	require.Equal(t, *recent, bids[0].Bid)
+	commitments, _, err := history.ListCommitments(store.CommitmentFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, commitments, 2, "active and not yet final commitments are kept")
+
+	archivedBids := readArchive[store.BidRecord](t, filepath.Join(dir, "bids-*.jsonl.gz"))
+	require.Len(t, archivedBids, 1)
+	require.Equal(t, *old, archivedBids[0].Bid)
+	archivedCommitments := readArchive[retention.ArchivedCommitment](t, filepath.Join(dir, "commitments-*.jsonl.gz"))
+	require.Len(t, archivedCommitments, 1)
+	require.Equal(t, final.Hash(), archivedCommitments[0].Commitment.Hash())
+	require.Equal(t, "fulfilled", archivedCommitments[0].State)
+
+	// Nothing left to prune
+	result, err = pruner.Prune(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, retention.Result{}, result)
+}
@@ -0,0 +1,144 @@
+package sealed
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	compressedKeyLength = 33
+	// Signed bids encode to around 250 bytes
+	maxCiphertextLength = 1024
+)
+
+var ErrMismatch = errors.New("sealed bid doesn't match the relay or block it was signed for")
+
+// Relay's signed bid encrypted to a sealing key, held by the auctioneer or split among a committee (see Split), so
+// its amount stays hidden, from the operator too, until the auction closes. The relay signs the envelope, so the
+// auction knows who it's from and which block it's for without decrypting it.
+type EncryptedBid struct {
+	L1Block *big.Int       `json:"l1Block"`
+	Address common.Address `json:"address"`
+	// Ephemeral public key, compressed, followed by the AES-GCM sealed JSON bid
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+	Signature  hexutil.Bytes `json:"signature"`
+}
+
+// Signs a bid for the block and encrypts it to sealingKey, in a signed envelope
+func Seal(amountWei *big.Int, l1Block *big.Int, sealingKey *ecdsa.PublicKey, privateKey *ecdsa.PrivateKey) (*EncryptedBid, error) {
+	bid, err := auction.CreateSignedBid(amountWei, l1Block, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(bid)
+	if err != nil {
+		return nil, err
+	}
+	b := EncryptedBid{L1Block: l1Block, Address: bid.Address}
+	if b.Ciphertext, err = encrypt(sealingKey, plaintext, b.associatedData()); err != nil {
+		return nil, err
+	}
+	if b.Signature, err = crypto.Sign(b.Hash().Bytes(), privateKey); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Hash of the envelope the relay signs
+func (b *EncryptedBid) Hash() common.Hash {
+	return crypto.Keccak256Hash(b.associatedData(), crypto.Keccak256(b.Ciphertext))
+}
+
+func (b *EncryptedBid) associatedData() []byte {
+	return append(common.BigToHash(b.L1Block).Bytes(), b.Address.Bytes()...)
+}
+
+// Checks the envelope is well formed and signed by its address
+func (b *EncryptedBid) Validate() error {
+	if b.L1Block == nil || b.L1Block.Sign() < 0 {
+		return fmt.Errorf("%w: invalid l1Block", auction.ErrInvalidBid)
+	}
+	if len(b.Ciphertext) <= compressedKeyLength || len(b.Ciphertext) > maxCiphertextLength {
+		return fmt.Errorf("%w: invalid ciphertext length %d", auction.ErrInvalidBid, len(b.Ciphertext))
+	}
+	if len(b.Signature) != crypto.SignatureLength {
+		return fmt.Errorf("%w: invalid signature length %d", auction.ErrInvalidBid, len(b.Signature))
+	}
+	sigPublicKey, err := crypto.SigToPub(b.Hash().Bytes(), b.Signature)
+	if err != nil || crypto.PubkeyToAddress(*sigPublicKey) != b.Address {
+		return fmt.Errorf("%w: signature does not match address", auction.ErrInvalidBid)
+	}
+	return nil
+}
+
+// Decrypts the bid with the whole sealing key
+func Open(b EncryptedBid, key *ecdsa.PrivateKey) (auction.SignedBid, error) {
+	ephemeral, err := b.ephemeral()
+	if err != nil {
+		return auction.SignedBid{}, err
+	}
+	x, _ := crypto.S256().ScalarMult(ephemeral.X, ephemeral.Y, key.D.Bytes())
+	return b.open(x)
+}
+
+func (b *EncryptedBid) ephemeral() (*ecdsa.PublicKey, error) {
+	if len(b.Ciphertext) <= compressedKeyLength {
+		return nil, fmt.Errorf("invalid ciphertext length %d", len(b.Ciphertext))
+	}
+	return crypto.DecompressPubkey(b.Ciphertext[:compressedKeyLength])
+}
+
+// Decrypts the bid with the shared secret's x coordinate, and checks it's the relay's bid for the envelope's block
+func (b *EncryptedBid) open(secretX *big.Int) (auction.SignedBid, error) {
+	aead, err := newAEAD(secretX, b.Ciphertext[:compressedKeyLength])
+	if err != nil {
+		return auction.SignedBid{}, err
+	}
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), b.Ciphertext[compressedKeyLength:], b.associatedData())
+	if err != nil {
+		return auction.SignedBid{}, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	var bid auction.SignedBid
+	if err := json.Unmarshal(plaintext, &bid); err != nil {
+		return auction.SignedBid{}, fmt.Errorf("invalid bid: %w", err)
+	}
+	if bid.Address != b.Address || bid.L1Block == nil || bid.L1Block.Cmp(b.L1Block) != 0 {
+		return auction.SignedBid{}, ErrMismatch
+	}
+	return bid, nil
+}
+
+// ECIES over secp256k1: the shared secret of a fresh ephemeral key and the sealing key keys AES-256-GCM. Each key
+// seals one message, so the nonce is fixed.
+func encrypt(sealingKey *ecdsa.PublicKey, plaintext []byte, associatedData []byte) ([]byte, error) {
+	ephemeral, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	x, _ := crypto.S256().ScalarMult(sealingKey.X, sealingKey.Y, ephemeral.D.Bytes())
+	ephemeralKey := crypto.CompressPubkey(&ephemeral.PublicKey)
+	aead, err := newAEAD(x, ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(ephemeralKey, make([]byte, aead.NonceSize()), plaintext, associatedData), nil
+}
+
+func newAEAD(secretX *big.Int, ephemeralKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(crypto.Keccak256(common.LeftPadBytes(secretX.Bytes(), 32), ephemeralKey))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,56 @@
+package sealed_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealAndOpen(t *testing.T) {
+	sealingKey, _ := crypto.GenerateKey()
+	relay, _ := crypto.GenerateKey()
+	b, err := sealed.Seal(big.NewInt(123), big.NewInt(10), &sealingKey.PublicKey, relay)
+	require.NoError(t, err)
+	require.NoError(t, b.Validate())
+	require.Equal(t, crypto.PubkeyToAddress(relay.PublicKey), b.Address)
+	require.NotContains(t, string(b.Ciphertext), "123")
+
+	bid, err := sealed.Open(*b, sealingKey)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(123), bid.AmountWei)
+	require.Equal(t, b.Address, bid.Address)
+	require.True(t, bid.Verify())
+
+	other, _ := crypto.GenerateKey()
+	_, err = sealed.Open(*b, other)
+	require.Error(t, err, "sealed to another key")
+
+	moved := *b
+	moved.L1Block = big.NewInt(11)
+	require.Error(t, moved.Validate(), "envelope signed for another block")
+	_, err = sealed.Open(moved, sealingKey)
+	require.Error(t, err, "ciphertext bound to its block")
+
+	tampered := *b
+	tampered.Ciphertext = append([]byte(nil), b.Ciphertext...)
+	tampered.Ciphertext[40] ^= 1
+	require.Error(t, tampered.Validate())
+
+	// Relay sealing another relay's bid under its own envelope
+	theirs, err := sealed.Seal(big.NewInt(1), big.NewInt(10), &sealingKey.PublicKey, other)
+	require.NoError(t, err)
+	stolen := *theirs
+	stolen.Address = b.Address
+	stolen.Signature, err = crypto.Sign(stolen.Hash().Bytes(), relay)
+	require.NoError(t, err)
+	require.NoError(t, stolen.Validate())
+	_, err = sealed.Open(stolen, sealingKey)
+	require.Error(t, err)
+
+	require.Error(t, (&sealed.EncryptedBid{L1Block: big.NewInt(10), Address: common.Address{1}}).Validate())
+}
@@ -0,0 +1,129 @@
+package sealed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// sealed subcommands setting up a decryption committee and running a member's share server
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sealed",
+		Short: "Manage the key sealed bids are encrypted to, and its decryption committee",
+	}
+
+	var keyFile, outDir string
+	var threshold, members int
+	split := &cobra.Command{
+		Use:   "split",
+		Short: "Split a sealing key into committee members' key shares, and print the sealing key",
+		Long: `Splits a sealing key into one key share file per committee member, any threshold of which decrypt sealed bids.
+A key is generated if --key-file is unset. Hand each member its share file and destroy the key, then configure the
+auctioneer's sealed.public-key with the printed sealing key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := crypto.GenerateKey()
+			if keyFile != "" {
+				key, err = crypto.LoadECDSA(keyFile)
+			}
+			if err != nil {
+				return err
+			}
+			shares, err := Split(key, threshold, members)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(outDir, 0o700); err != nil {
+				return err
+			}
+			for _, share := range shares {
+				data, err := json.MarshalIndent(share, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(filepath.Join(outDir, fmt.Sprintf("share-%d.json", share.Index)), data, 0o600); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)))
+			return nil
+		},
+	}
+	split.Flags().StringVar(&keyFile, "key-file", "", "Hex private key file to split, generated if unset")
+	split.Flags().StringVar(&outDir, "out-dir", "shares", "Directory share files are written to")
+	split.Flags().IntVar(&threshold, "threshold", 2, "Shares needed to decrypt")
+	split.Flags().IntVar(&members, "members", 3, "Committee members")
+
+	var shareFile, rpcURL, addr string
+	var closeAfter time.Duration
+	serve := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a committee member's decryption shares of each auction's bids once it closes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			share, err := ReadKeyShare(shareFile)
+			if err != nil {
+				return err
+			}
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			logger := slog.Default()
+			server := &http.Server{Addr: addr, Handler: NewShareServer(logger, share, client, closeAfter)}
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				server.Shutdown(shutdownCtx)
+			}()
+			logger.Info("serving decryption shares", "addr", addr, "index", share.Index)
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+	serve.Flags().StringVar(&shareFile, "share-file", "", "Member's key share file, from split")
+	serve.Flags().StringVar(&rpcURL, "l1-rpc-url", "", "L1 node the member tells when blocks were proposed by")
+	serve.Flags().StringVar(&addr, "addr", ":8650", "Listen address")
+	serve.Flags().DurationVar(&closeAfter, "close-after", 12*time.Second, "Shares of a block's bids are released this long after its timestamp, at least the auctioneer's auction.close-offset")
+	serve.MarkFlagRequired("share-file")
+	serve.MarkFlagRequired("l1-rpc-url")
+
+	cmd.AddCommand(split, serve)
+	return cmd
+}
+
+// Reads a key share file written by split
+func ReadKeyShare(file string) (KeyShare, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return KeyShare{}, err
+	}
+	var share KeyShare
+	if err := json.Unmarshal(data, &share); err != nil {
+		return KeyShare{}, fmt.Errorf("invalid key share file %s: %w", file, err)
+	}
+	if share.Index == 0 || share.Key == nil {
+		return KeyShare{}, fmt.Errorf("invalid key share file %s", file)
+	}
+	return share, nil
+}
@@ -0,0 +1,36 @@
+package sealed_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommand(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keyFile := filepath.Join(t.TempDir(), "key.hex")
+	require.NoError(t, crypto.SaveECDSA(keyFile, key))
+	outDir := filepath.Join(t.TempDir(), "shares")
+
+	cmd := sealed.NewCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"split", "--key-file", keyFile, "--out-dir", outDir, "--threshold", "2", "--members", "3"})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)), strings.TrimSpace(out.String()))
+
+	share, err := sealed.ReadKeyShare(filepath.Join(outDir, "share-3.json"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), share.Index)
+	require.Equal(t, 2, share.Threshold)
+	_, err = sealed.ReadKeyShare(filepath.Join(outDir, "share-4.json"))
+	require.Error(t, err)
+}
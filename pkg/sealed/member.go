@@ -0,0 +1,99 @@
+package sealed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	SharesPath = "/v1/decryption-shares"
+	// Bids in one share request at most
+	maxShareRequestBids = 4096
+)
+
+// Satisfied by *ethclient.Client
+type HeaderSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Committee member's server releasing its decryption shares of a block's sealed bids once the block's auction
+// closed, closeAfter after the block's timestamp, as told by the member's own L1 node rather than the auctioneer
+type ShareServer struct {
+	logger     *slog.Logger
+	share      KeyShare
+	headers    HeaderSource
+	closeAfter time.Duration
+}
+
+func NewShareServer(logger *slog.Logger, share KeyShare, headers HeaderSource, closeAfter time.Duration) *ShareServer {
+	return &ShareServer{logger: logger, share: share, headers: headers, closeAfter: closeAfter}
+}
+
+func (s *ShareServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != SharesPath {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	var req ShareRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if len(req.Bids) > maxShareRequestBids {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("more than %d bids", maxShareRequestBids))
+		return
+	}
+	header, err := s.headers.HeaderByNumber(r.Context(), new(big.Int).SetUint64(req.L1Block))
+	if err != nil {
+		// Possibly not seen yet
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("failed to get block %d: %w", req.L1Block, err))
+		return
+	}
+	if closesAt := time.Unix(int64(header.Time), 0).Add(s.closeAfter); time.Now().Before(closesAt) {
+		writeError(w, http.StatusForbidden, fmt.Errorf("auction for block %d closes at %s", req.L1Block, closesAt.UTC().Format(time.RFC3339)))
+		return
+	}
+	shares := make([]DecryptionShare, len(req.Bids))
+	for i, b := range req.Bids {
+		if err := b.Validate(); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("bid %d: %w", i, err))
+			return
+		}
+		if !b.L1Block.IsUint64() || b.L1Block.Uint64() != req.L1Block {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("bid %d is for block %s", i, b.L1Block))
+			return
+		}
+		if shares[i], err = s.share.Decrypt(b); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("bid %d: %w", i, err))
+			return
+		}
+	}
+	s.logger.Debug("released decryption shares", "blockNumber", req.L1Block, "bids", len(shares))
+	writeJSON(w, http.StatusOK, ShareResponse{Shares: shares})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
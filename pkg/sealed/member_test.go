@@ -0,0 +1,46 @@
+package sealed_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareServer(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	shares, err := sealed.Split(key, 1, 1)
+	require.NoError(t, err)
+	bids := sealBids(t, &key.PublicKey, 9, 10)
+	request := func(headers mockHeaders, req sealed.ShareRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		sealed.NewShareServer(slog.Default(), shares[0], headers, 4*time.Second).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodPost, sealed.SharesPath, bytes.NewReader(body)))
+		return rec
+	}
+
+	open := mockHeaders{time: uint64(time.Now().Unix())}
+	require.Equal(t, http.StatusForbidden, request(open, sealed.ShareRequest{L1Block: 9, Bids: bids}).Code, "auction still open")
+
+	closed := mockHeaders{time: uint64(time.Now().Add(-5 * time.Second).Unix())}
+	require.Equal(t, http.StatusBadRequest, request(closed, sealed.ShareRequest{L1Block: 8, Bids: bids}).Code, "bid for another block")
+
+	rec := request(closed, sealed.ShareRequest{L1Block: 9, Bids: bids})
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp sealed.ShareResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Shares, 1)
+	bid, err := sealed.OpenShares(bids[0], resp.Shares, 1)
+	require.NoError(t, err)
+	require.Equal(t, bids[0].Address, bid.Address)
+}
@@ -0,0 +1,164 @@
+package sealed
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+)
+
+// Shares are requested from every member at once, each within it
+const shareTimeout = 2 * time.Second
+
+// Decrypts an auction's sealed bids once it closes
+type Opener interface {
+	// Key bids are sealed to
+	SealingKey() *ecdsa.PublicKey
+	// Bids that decrypted, and an error joining why the others didn't
+	Open(ctx context.Context, l1Block uint64, bids []EncryptedBid) ([]auction.SignedBid, error)
+}
+
+// Opens bids with the whole sealing key, held by the auctioneer. Keeps bids from relays and anyone watching the
+// auction, but not from the operator, for which see Committee.
+type KeyOpener struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewKeyOpener(key *ecdsa.PrivateKey) *KeyOpener {
+	return &KeyOpener{key: key}
+}
+
+func (o *KeyOpener) SealingKey() *ecdsa.PublicKey {
+	return &o.key.PublicKey
+}
+
+func (o *KeyOpener) Open(ctx context.Context, l1Block uint64, bids []EncryptedBid) ([]auction.SignedBid, error) {
+	var opened []auction.SignedBid
+	var errs []error
+	for _, b := range bids {
+		bid, err := Open(b, o.key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("bid from %s: %w", b.Address, err))
+			continue
+		}
+		opened = append(opened, bid)
+	}
+	return opened, errors.Join(errs...)
+}
+
+// Request for members' decryption shares of a closed auction's bids
+type ShareRequest struct {
+	L1Block uint64         `json:"l1Block"`
+	Bids    []EncryptedBid `json:"bids"`
+}
+
+// Member's decryption shares, one per bid requested, in order
+type ShareResponse struct {
+	Shares []DecryptionShare `json:"shares"`
+}
+
+// Opens bids with decryption shares from a committee holding shares of the sealing key (see Split and
+// ShareServer), none of which releases its shares before the auction closes. The operator alone can't decrypt bids.
+type Committee struct {
+	httpClient *http.Client
+	sealingKey *ecdsa.PublicKey
+	threshold  int
+	members    []string
+}
+
+// Members are the base URLs of the members' share servers
+func NewCommittee(sealingKey *ecdsa.PublicKey, threshold int, members []string) (*Committee, error) {
+	if threshold < 1 || threshold > len(members) {
+		return nil, fmt.Errorf("threshold must be between 1 and %d members", len(members))
+	}
+	return &Committee{
+		httpClient: &http.Client{Timeout: shareTimeout},
+		sealingKey: sealingKey,
+		threshold:  threshold,
+		members:    members,
+	}, nil
+}
+
+func (c *Committee) SealingKey() *ecdsa.PublicKey {
+	return c.sealingKey
+}
+
+func (c *Committee) Open(ctx context.Context, l1Block uint64, bids []EncryptedBid) ([]auction.SignedBid, error) {
+	if len(bids) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(ShareRequest{L1Block: l1Block, Bids: bids})
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	shares := make([][]DecryptionShare, len(bids))
+	var memberErrs []error
+	for _, member := range c.members {
+		wg.Add(1)
+		go func(member string) {
+			defer wg.Done()
+			resp, err := c.requestShares(ctx, member, body)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil && len(resp.Shares) != len(bids) {
+				err = fmt.Errorf("%d shares for %d bids", len(resp.Shares), len(bids))
+			}
+			if err != nil {
+				memberErrs = append(memberErrs, fmt.Errorf("member %s: %w", member, err))
+				return
+			}
+			for i, share := range resp.Shares {
+				shares[i] = append(shares[i], share)
+			}
+		}(member)
+	}
+	wg.Wait()
+
+	var opened []auction.SignedBid
+	var errs []error
+	for i, b := range bids {
+		bid, err := OpenShares(b, shares[i], c.threshold)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("bid from %s: %w", b.Address, err))
+			continue
+		}
+		opened = append(opened, bid)
+	}
+	if len(errs) > 0 {
+		errs = append(errs, memberErrs...)
+	}
+	return opened, errors.Join(errs...)
+}
+
+func (c *Committee) requestShares(ctx context.Context, member string, body []byte) (*ShareResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(member, "/")+SharesPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	var shares ShareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&shares); err != nil {
+		return nil, err
+	}
+	return &shares, nil
+}
@@ -0,0 +1,82 @@
+package sealed_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log/slog"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHeaders struct {
+	time uint64
+}
+
+func (m mockHeaders) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: number, Time: m.time}, nil
+}
+
+func sealBids(t *testing.T, sealingKey *ecdsa.PublicKey, l1Block int64, amounts ...int64) []sealed.EncryptedBid {
+	bids := make([]sealed.EncryptedBid, len(amounts))
+	for i, amount := range amounts {
+		relay, _ := crypto.GenerateKey()
+		b, err := sealed.Seal(big.NewInt(amount), big.NewInt(l1Block), sealingKey, relay)
+		require.NoError(t, err)
+		bids[i] = *b
+	}
+	return bids
+}
+
+func TestKeyOpener(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	o := sealed.NewKeyOpener(key)
+	require.Equal(t, &key.PublicKey, o.SealingKey())
+	other, _ := crypto.GenerateKey()
+	bids := append(sealBids(t, o.SealingKey(), 9, 10, 20), sealBids(t, &other.PublicKey, 9, 30)...)
+
+	opened, err := o.Open(context.Background(), 9, bids)
+	require.Error(t, err, "bid sealed to another key")
+	require.Len(t, opened, 2)
+	require.Equal(t, big.NewInt(10), opened[0].AmountWei)
+	require.Equal(t, big.NewInt(20), opened[1].AmountWei)
+}
+
+func TestCommittee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	shares, err := sealed.Split(key, 2, 3)
+	require.NoError(t, err)
+	closed := mockHeaders{time: uint64(time.Now().Add(-time.Minute).Unix())}
+	var members []string
+	for _, share := range shares[:2] {
+		server := httptest.NewServer(sealed.NewShareServer(slog.Default(), share, closed, 12*time.Second))
+		defer server.Close()
+		members = append(members, server.URL)
+	}
+	// Third member unreachable
+	members = append(members, "http://127.0.0.1:1")
+
+	committee, err := sealed.NewCommittee(&key.PublicKey, 2, members)
+	require.NoError(t, err)
+	bids := sealBids(t, committee.SealingKey(), 9, 10, 20)
+	opened, err := committee.Open(context.Background(), 9, bids)
+	require.NoError(t, err, "threshold of members reachable")
+	require.Len(t, opened, 2)
+	require.Equal(t, big.NewInt(20), opened[1].AmountWei)
+
+	strict, err := sealed.NewCommittee(&key.PublicKey, 3, members)
+	require.NoError(t, err)
+	opened, err = strict.Open(context.Background(), 9, bids)
+	require.ErrorIs(t, err, sealed.ErrNotEnoughShares)
+	require.Empty(t, opened)
+
+	_, err = sealed.NewCommittee(&key.PublicKey, 4, members)
+	require.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package sealed
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrNotEnoughShares = errors.New("not enough decryption shares")
+
+// Committee member's Shamir share of a sealing key, see Split. Members release decryption shares of bids with
+// it, never the share itself, so no one holds the sealing key once it's split.
+type KeyShare struct {
+	// Member's x coordinate, from 1
+	Index uint64       `json:"index"`
+	Key   *hexutil.Big `json:"key"`
+	// Shares needed to decrypt, and the sealing key bids are encrypted to, compressed
+	Threshold  int           `json:"threshold"`
+	SealingKey hexutil.Bytes `json:"sealingKey"`
+}
+
+// Member's share of a sealed bid's shared secret, the bid's ephemeral key times the member's key share
+type DecryptionShare struct {
+	Index uint64        `json:"index"`
+	Point hexutil.Bytes `json:"point"`
+}
+
+// Splits key into n shares, any threshold of which decrypt bids sealed to it. The key should then be destroyed.
+func Split(key *ecdsa.PrivateKey, threshold int, n int) ([]KeyShare, error) {
+	if threshold < 1 || threshold > n {
+		return nil, fmt.Errorf("threshold must be between 1 and %d", n)
+	}
+	order := crypto.S256().Params().N
+	coefficients := []*big.Int{key.D}
+	for i := 1; i < threshold; i++ {
+		c, err := rand.Int(rand.Reader, order)
+		if err != nil {
+			return nil, err
+		}
+		coefficients = append(coefficients, c)
+	}
+	sealingKey := crypto.CompressPubkey(&key.PublicKey)
+	shares := make([]KeyShare, n)
+	for i := range shares {
+		x := big.NewInt(int64(i + 1))
+		// Horner's method, highest coefficient first
+		y := new(big.Int)
+		for j := len(coefficients) - 1; j >= 0; j-- {
+			y.Mul(y, x).Add(y, coefficients[j]).Mod(y, order)
+		}
+		shares[i] = KeyShare{Index: uint64(i + 1), Key: (*hexutil.Big)(y), Threshold: threshold, SealingKey: sealingKey}
+	}
+	return shares, nil
+}
+
+// Sealing key the share is of
+func (s KeyShare) PublicKey() (*ecdsa.PublicKey, error) {
+	return crypto.DecompressPubkey(s.SealingKey)
+}
+
+// Member's decryption share of the bid. Only to be released once the bid's auction closed.
+func (s KeyShare) Decrypt(b EncryptedBid) (DecryptionShare, error) {
+	ephemeral, err := b.ephemeral()
+	if err != nil {
+		return DecryptionShare{}, err
+	}
+	x, y := crypto.S256().ScalarMult(ephemeral.X, ephemeral.Y, s.Key.ToInt().Bytes())
+	return DecryptionShare{Index: s.Index, Point: crypto.CompressPubkey(&ecdsa.PublicKey{Curve: crypto.S256(), X: x, Y: y})}, nil
+}
+
+// Decrypts the bid from the decryption shares of at least threshold distinct members. A wrong share fails
+// decryption, rather than yielding another bid.
+func OpenShares(b EncryptedBid, shares []DecryptionShare, threshold int) (auction.SignedBid, error) {
+	points := make(map[uint64]*ecdsa.PublicKey)
+	for _, share := range shares {
+		if _, ok := points[share.Index]; ok || share.Index == 0 {
+			continue
+		}
+		point, err := crypto.DecompressPubkey(share.Point)
+		if err != nil {
+			return auction.SignedBid{}, fmt.Errorf("invalid decryption share %d: %w", share.Index, err)
+		}
+		points[share.Index] = point
+		if len(points) == threshold {
+			break
+		}
+	}
+	if len(points) < threshold {
+		return auction.SignedBid{}, fmt.Errorf("%w: %d of %d", ErrNotEnoughShares, len(points), threshold)
+	}
+
+	// Lagrange interpolation of the shared secret at 0
+	curve, order := crypto.S256(), crypto.S256().Params().N
+	var sx, sy *big.Int
+	for i, point := range points {
+		coefficient := big.NewInt(1)
+		for j := range points {
+			if j == i {
+				continue
+			}
+			xi, xj := new(big.Int).SetUint64(i), new(big.Int).SetUint64(j)
+			denominator := new(big.Int).Sub(xj, xi)
+			denominator.Mod(denominator, order).ModInverse(denominator, order)
+			coefficient.Mul(coefficient, xj).Mul(coefficient, denominator).Mod(coefficient, order)
+		}
+		x, y := curve.ScalarMult(point.X, point.Y, coefficient.Bytes())
+		if sx == nil {
+			sx, sy = x, y
+		} else {
+			sx, sy = curve.Add(sx, sy, x, y)
+		}
+	}
+	return b.open(sx)
+}
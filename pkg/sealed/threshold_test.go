@@ -0,0 +1,51 @@
+package sealed_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/sealed"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAndOpenShares(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	relay, _ := crypto.GenerateKey()
+	shares, err := sealed.Split(key, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+	sealingKey, err := shares[0].PublicKey()
+	require.NoError(t, err)
+	require.Equal(t, key.PublicKey, *sealingKey)
+
+	b, err := sealed.Seal(big.NewInt(77), big.NewInt(5), sealingKey, relay)
+	require.NoError(t, err)
+	decryption := make([]sealed.DecryptionShare, len(shares))
+	for i, share := range shares {
+		decryption[i], err = share.Decrypt(*b)
+		require.NoError(t, err)
+	}
+
+	for _, members := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var subset []sealed.DecryptionShare
+		for _, i := range members {
+			subset = append(subset, decryption[i])
+		}
+		bid, err := sealed.OpenShares(*b, subset, 3)
+		require.NoError(t, err, "members %v", members)
+		require.Equal(t, big.NewInt(77), bid.AmountWei)
+	}
+
+	_, err = sealed.OpenShares(*b, []sealed.DecryptionShare{decryption[0], decryption[1], decryption[1]}, 3)
+	require.ErrorIs(t, err, sealed.ErrNotEnoughShares, "duplicate shares count once")
+
+	wrong := decryption[2]
+	wrong.Point = decryption[3].Point
+	_, err = sealed.OpenShares(*b, []sealed.DecryptionShare{decryption[0], decryption[1], wrong}, 3)
+	require.Error(t, err)
+
+	_, err = sealed.Split(key, 4, 3)
+	require.Error(t, err)
+}
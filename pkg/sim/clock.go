@@ -0,0 +1,82 @@
+package sim
+
+import (
+	"container/heap"
+	"time"
+)
+
+// Fake clock driving a simulation. Scheduled functions run in time order, ties in the order they were scheduled,
+// so a run is reproducible.
+type Clock struct {
+	now    time.Time
+	seq    uint64
+	events eventQueue
+}
+
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+func (c *Clock) Now() time.Time {
+	return c.now
+}
+
+// Schedules f to run after d, or at the current time if d isn't positive
+func (c *Clock) After(d time.Duration, f func()) {
+	c.At(c.now.Add(max(d, 0)), f)
+}
+
+// Schedules f to run at t, or at the current time if t has passed
+func (c *Clock) At(t time.Time, f func()) {
+	if t.Before(c.now) {
+		t = c.now
+	}
+	c.seq++
+	heap.Push(&c.events, &scheduled{at: t, seq: c.seq, f: f})
+}
+
+// Runs scheduled functions, including those they schedule, until the next is after until.
+// The clock is left at until.
+func (c *Clock) RunUntil(until time.Time) {
+	for len(c.events) > 0 && !c.events[0].at.After(until) {
+		next := heap.Pop(&c.events).(*scheduled)
+		c.now = next.at
+		next.f()
+	}
+	if until.After(c.now) {
+		c.now = until
+	}
+}
+
+// Number of scheduled functions yet to run
+func (c *Clock) Pending() int {
+	return len(c.events)
+}
+
+type scheduled struct {
+	at  time.Time
+	seq uint64
+	f   func()
+}
+
+type eventQueue []*scheduled
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) { *q = append(*q, x.(*scheduled)) }
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
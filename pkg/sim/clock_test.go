@@ -0,0 +1,33 @@
+package sim_test
+
+import (
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/sim"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := sim.NewClock(start)
+	var order []string
+	clock.After(2*time.Second, func() { order = append(order, "b") })
+	clock.After(time.Second, func() {
+		order = append(order, "a")
+		clock.After(time.Second, func() { order = append(order, "a2") })
+	})
+	clock.After(2*time.Second, func() { order = append(order, "c") })
+	clock.At(start.Add(-time.Second), func() { order = append(order, "now") })
+
+	clock.RunUntil(start.Add(1500 * time.Millisecond))
+	require.Equal(t, []string{"now", "a"}, order)
+	require.Equal(t, start.Add(1500*time.Millisecond), clock.Now())
+	require.Equal(t, 3, clock.Pending())
+
+	clock.RunUntil(start.Add(10 * time.Second))
+	require.Equal(t, []string{"now", "a", "b", "c", "a2"}, order, "ties run in the order they were scheduled")
+	require.Equal(t, start.Add(10*time.Second), clock.Now())
+	require.Zero(t, clock.Pending())
+}
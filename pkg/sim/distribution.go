@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"time"
+)
+
+// Samples one-way network latency between a relay and the auctioneer
+type Latency interface {
+	Sample(rng *rand.Rand) time.Duration
+}
+
+type ConstantLatency time.Duration
+
+func (l ConstantLatency) Sample(rng *rand.Rand) time.Duration {
+	return time.Duration(l)
+}
+
+// Uniformly distributed in [Min, Max]
+type UniformLatency struct {
+	Min, Max time.Duration
+}
+
+func (l UniformLatency) Sample(rng *rand.Rand) time.Duration {
+	if l.Max <= l.Min {
+		return l.Min
+	}
+	return l.Min + time.Duration(rng.Int63n(int64(l.Max-l.Min)+1))
+}
+
+// Log-normally distributed around Median, the usual shape of network latency with a long tail.
+// Sigma is the standard deviation of the log, e.g. 0.5.
+type LogNormalLatency struct {
+	Median time.Duration
+	Sigma  float64
+}
+
+func (l LogNormalLatency) Sample(rng *rand.Rand) time.Duration {
+	return time.Duration(float64(l.Median) * math.Exp(l.Sigma*rng.NormFloat64()))
+}
+
+// Samples a relay's private value of winning a slot's auction, in wei
+type Value interface {
+	Sample(rng *rand.Rand) *big.Int
+}
+
+type ConstantValue int64
+
+func (v ConstantValue) Sample(rng *rand.Rand) *big.Int {
+	return big.NewInt(int64(v))
+}
+
+// Uniformly distributed in [Min, Max] wei
+type UniformValue struct {
+	Min, Max int64
+}
+
+func (v UniformValue) Sample(rng *rand.Rand) *big.Int {
+	if v.Max <= v.Min {
+		return big.NewInt(v.Min)
+	}
+	return big.NewInt(v.Min + rng.Int63n(v.Max-v.Min+1))
+}
@@ -0,0 +1,99 @@
+package sim
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Bid received by the simulated auctioneer
+type Bid struct {
+	Relay      string
+	Address    common.Address
+	AmountWei  *big.Int
+	ReceivedAt time.Time
+}
+
+// Auction mechanism under simulation
+type Mechanism interface {
+	Name() string
+	// Whether relays are told of leader changes while the auction runs, so they can outbid
+	Open() bool
+	// Winner and price paid, from the bids received before the auction closed in order of receipt.
+	// nil if there were no bids.
+	Settle(bids []Bid) (winner *Bid, priceWei *big.Int)
+}
+
+// Whether a beats b: higher amount, ties to the earlier received, then the lower address, as the auctioneer's open
+// auction decides
+func Beats(a Bid, b Bid) bool {
+	if c := a.AmountWei.Cmp(b.AmountWei); c != 0 {
+		return c > 0
+	}
+	if !a.ReceivedAt.Equal(b.ReceivedAt) {
+		return a.ReceivedAt.Before(b.ReceivedAt)
+	}
+	return a.Address.Cmp(b.Address) < 0
+}
+
+// Orders bids best first by Beats
+func rank(bids []Bid) (best *Bid, second *Bid) {
+	for i := range bids {
+		switch bid := &bids[i]; {
+		case best == nil || Beats(*bid, *best):
+			best, second = bid, best
+		case second == nil || Beats(*bid, *second):
+			second = bid
+		}
+	}
+	return best, second
+}
+
+// English auction the auctioneer runs: leader changes are streamed, and the leader pays its bid
+type OpenAscending struct{}
+
+func (OpenAscending) Name() string { return "open ascending" }
+
+func (OpenAscending) Open() bool { return true }
+
+func (OpenAscending) Settle(bids []Bid) (*Bid, *big.Int) {
+	return firstPrice(bids)
+}
+
+// Sealed bids, the highest pays its bid
+type FirstPrice struct{}
+
+func (FirstPrice) Name() string { return "first price" }
+
+func (FirstPrice) Open() bool { return false }
+
+func (FirstPrice) Settle(bids []Bid) (*Bid, *big.Int) {
+	return firstPrice(bids)
+}
+
+// Sealed bids, the highest pays the second highest bid, or its own if it's the only bidder
+type SecondPrice struct{}
+
+func (SecondPrice) Name() string { return "second price" }
+
+func (SecondPrice) Open() bool { return false }
+
+func (SecondPrice) Settle(bids []Bid) (*Bid, *big.Int) {
+	best, second := rank(bids)
+	if best == nil {
+		return nil, nil
+	}
+	if second == nil {
+		return best, best.AmountWei
+	}
+	return best, second.AmountWei
+}
+
+func firstPrice(bids []Bid) (*Bid, *big.Int) {
+	best, _ := rank(bids)
+	if best == nil {
+		return nil, nil
+	}
+	return best, best.AmountWei
+}
@@ -0,0 +1,38 @@
+package sim_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/sim"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMechanisms(t *testing.T) {
+	bids := []sim.Bid{
+		{Relay: "a", Address: common.Address{0x02}, AmountWei: big.NewInt(10)},
+		{Relay: "b", Address: common.Address{0x03}, AmountWei: big.NewInt(30)},
+		{Relay: "c", Address: common.Address{0x01}, AmountWei: big.NewInt(30)},
+		{Relay: "d", Address: common.Address{0x04}, AmountWei: big.NewInt(20)},
+	}
+	for _, test := range []struct {
+		mechanism sim.Mechanism
+		price     int64
+	}{{sim.OpenAscending{}, 30}, {sim.FirstPrice{}, 30}, {sim.SecondPrice{}, 30}} {
+		winner, price := test.mechanism.Settle(bids)
+		require.Equal(t, "c", winner.Relay, "%s: ties go to the lower address", test.mechanism.Name())
+		require.Equal(t, big.NewInt(test.price), price)
+	}
+
+	winner, price := sim.SecondPrice{}.Settle(append(bids[:1:1], bids[3]))
+	require.Equal(t, "d", winner.Relay)
+	require.Equal(t, big.NewInt(10), price, "the winner pays the second highest bid")
+	winner, price = sim.SecondPrice{}.Settle(bids[:1])
+	require.Equal(t, "a", winner.Relay)
+	require.Equal(t, big.NewInt(10), price, "a lone bidder pays its bid")
+	winner, price = sim.FirstPrice{}.Settle(nil)
+	require.Nil(t, winner)
+	require.Nil(t, price)
+}
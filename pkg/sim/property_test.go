@@ -0,0 +1,135 @@
+package sim_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/sim"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"pgregory.net/rapid"
+)
+
+var mechanisms = []sim.Mechanism{sim.OpenAscending{}, sim.FirstPrice{}, sim.SecondPrice{}}
+
+// Few addresses, amounts and arrivals, so sets often have ties
+func bidsGen() *rapid.Generator[[]sim.Bid] {
+	return rapid.SliceOfN(rapid.Custom(func(t *rapid.T) sim.Bid {
+		return sim.Bid{
+			Address:    common.Address{rapid.ByteRange(1, 5).Draw(t, "address")},
+			AmountWei:  big.NewInt(rapid.Int64Range(1, 20).Draw(t, "amount")),
+			ReceivedAt: time.Unix(rapid.Int64Range(0, 3).Draw(t, "arrival"), 0),
+		}
+	}), 1, 8)
+}
+
+func TestWinnerIsBestBid(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		bids := bidsGen().Draw(t, "bids")
+		for _, mechanism := range mechanisms {
+			winner, price := mechanism.Settle(bids)
+			for _, bid := range bids {
+				if sim.Beats(bid, *winner) {
+					t.Fatalf("%s: %v beats winner %v", mechanism.Name(), bid, winner)
+				}
+			}
+			if price.Sign() <= 0 || price.Cmp(winner.AmountWei) > 0 {
+				t.Fatalf("%s: price %s outside (0, %s]", mechanism.Name(), price, winner.AmountWei)
+			}
+		}
+	})
+}
+
+func TestTieBreakIsDeterministic(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		bids := bidsGen().Draw(t, "bids")
+		permuted := rapid.Permutation(bids).Draw(t, "permuted")
+		for _, mechanism := range mechanisms {
+			winner, price := mechanism.Settle(bids)
+			permutedWinner, permutedPrice := mechanism.Settle(permuted)
+			if winner.Address != permutedWinner.Address || winner.AmountWei.Cmp(permutedWinner.AmountWei) != 0 || price.Cmp(permutedPrice) != 0 {
+				t.Fatalf("%s: %v at %s, reordered %v at %s", mechanism.Name(), winner, price, permutedWinner, permutedPrice)
+			}
+		}
+	})
+}
+
+func TestWinnerMonotonicity(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		bids := bidsGen().Draw(t, "bids")
+		raise := big.NewInt(rapid.Int64Range(1, 20).Draw(t, "raise"))
+		for _, mechanism := range mechanisms {
+			winner, price := mechanism.Settle(bids)
+			raised := make([]sim.Bid, len(bids))
+			copy(raised, bids)
+			for i := range raised {
+				if raised[i].Address == winner.Address && raised[i].AmountWei.Cmp(winner.AmountWei) == 0 {
+					raised[i].AmountWei = new(big.Int).Add(raised[i].AmountWei, raise)
+					break
+				}
+			}
+			raisedWinner, raisedPrice := mechanism.Settle(raised)
+			if raisedWinner.Address != winner.Address {
+				t.Fatalf("%s: raising the winning bid lost to %v", mechanism.Name(), raisedWinner)
+			}
+			if raisedPrice.Cmp(price) < 0 {
+				t.Fatalf("%s: raising the winning bid lowered the price from %s to %s", mechanism.Name(), price, raisedPrice)
+			}
+		}
+	})
+}
+
+func TestRevenueOrdering(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		bids := bidsGen().Draw(t, "bids")
+		_, open := sim.OpenAscending{}.Settle(bids)
+		_, first := sim.FirstPrice{}.Settle(bids)
+		_, second := sim.SecondPrice{}.Settle(bids)
+		if open.Cmp(first) != 0 || first.Cmp(second) < 0 {
+			t.Fatalf("for the same bids open %s, first %s and second price %s revenue", open, first, second)
+		}
+	})
+}
+
+type registry struct{}
+
+func (registry) IsRegisteredOnSettlementLayer(address common.Address) bool { return true }
+
+// The simulated open auction picks the same winner as the auctioneer's
+func TestOpenAscendingMatchesRelayAuction(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 5)
+	addresses := make([]common.Address, len(keys))
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addresses[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rapid.Check(t, func(t *rapid.T) {
+		n := rapid.IntRange(1, 8).Draw(t, "n")
+		r := auction.NewRelayAuction(logger, registry{})
+		r.SetAccessList(auction.NewAccessList(addresses, nil))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		result := r.StartAsync(ctx, 30*time.Millisecond)
+		var bids []sim.Bid
+		for i := 0; i < n; i++ {
+			key := rapid.IntRange(0, len(keys)-1).Draw(t, "key")
+			amount := big.NewInt(rapid.Int64Range(1, 20).Draw(t, "amount"))
+			receivedAt := time.Unix(rapid.Int64Range(0, 3).Draw(t, "arrival"), 0)
+			r.SubmitBidAt(*auction.MustCreateSignedBid(amount, big.NewInt(100), keys[key]), receivedAt)
+			bids = append(bids, sim.Bid{Address: addresses[key], AmountWei: amount, ReceivedAt: receivedAt})
+		}
+		won := <-result
+		winner, _ := sim.OpenAscending{}.Settle(bids)
+		if won.Address != winner.Address || won.AmountWei.Cmp(winner.AmountWei) != 0 {
+			t.Fatalf("relay auction won by %s at %s, simulated by %s at %s", won.Address, won.AmountWei, winner.Address, winner.AmountWei)
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var ErrInvalidConfig = errors.New("invalid simulation config")
+
+// Decides whether the L1 block of each slot is produced, false for a missed slot
+type Blocks func(slot uint64, rng *rand.Rand) bool
+
+// Produces a block every slot
+func EverySlot() Blocks {
+	return func(slot uint64, rng *rand.Rand) bool { return true }
+}
+
+// Misses the given slots
+func MissSlots(slots ...uint64) Blocks {
+	missed := make(map[uint64]bool, len(slots))
+	for _, slot := range slots {
+		missed[slot] = true
+	}
+	return func(slot uint64, rng *rand.Rand) bool { return !missed[slot] }
+}
+
+// Misses each slot with the given probability
+func MissRandomly(probability float64) Blocks {
+	return func(slot uint64, rng *rand.Rand) bool { return rng.Float64() >= probability }
+}
+
+// Simulated relay. Latency applies both ways, to auction events reaching it and to its bids reaching the auctioneer.
+type Relay struct {
+	Name string
+	// Breaks ties between equal bids, derived from Name if zero
+	Address  common.Address
+	Latency  Latency
+	Value    Value
+	Strategy Strategy
+}
+
+type Config struct {
+	// Seeds every random draw, so runs with the same config have the same result
+	Seed  int64
+	Slots uint64
+	// Each produced block opens an auction lasting Period, which must end within SlotTime
+	SlotTime  time.Duration
+	Period    time.Duration
+	Blocks    Blocks
+	Relays    []Relay
+	Mechanism Mechanism
+}
+
+type Result struct {
+	Mechanism   string
+	Slots       uint64
+	MissedSlots uint64
+	// Auctions without bids received in time
+	Unsold uint64
+	Bids   uint64
+	// Bids received after their auction closed
+	LateBids   uint64
+	RevenueWei *big.Int
+	// Sum of each winner's value less the price it paid
+	WinnerSurplusWei *big.Int
+	// Auctions won by a relay valuing the slot the most
+	Efficient uint64
+	// Auctions won, by relay name
+	Wins map[string]uint64
+}
+
+// Auctions held, one per produced block
+func (r Result) Auctions() uint64 {
+	return r.Slots - r.MissedSlots
+}
+
+// Share of sold auctions won by a relay valuing the slot the most
+func (r Result) Efficiency() float64 {
+	sold := r.Auctions() - r.Unsold
+	if sold == 0 {
+		return 0
+	}
+	return float64(r.Efficient) / float64(sold)
+}
+
+// Runs the simulation from a fixed start time on a fake clock
+func Run(config Config) (Result, error) {
+	if err := config.validate(); err != nil {
+		return Result{}, err
+	}
+	relays := make([]Relay, len(config.Relays))
+	for i, relay := range config.Relays {
+		if relay.Address == (common.Address{}) {
+			relay.Address = common.BytesToAddress(crypto.Keccak256([]byte(relay.Name)))
+		}
+		relays[i] = relay
+	}
+	s := &simulation{
+		config: config,
+		relays: relays,
+		clock:  NewClock(time.Unix(0, 0).UTC()),
+		rng:    rand.New(rand.NewSource(config.Seed)),
+		result: Result{
+			Mechanism:        config.Mechanism.Name(),
+			Slots:            config.Slots,
+			RevenueWei:       new(big.Int),
+			WinnerSurplusWei: new(big.Int),
+			Wins:             make(map[string]uint64),
+		},
+	}
+	blocks := config.Blocks
+	if blocks == nil {
+		blocks = EverySlot()
+	}
+	start := s.clock.Now()
+	for slot := uint64(0); slot < config.Slots; slot++ {
+		slot := slot
+		s.clock.At(start.Add(time.Duration(slot)*config.SlotTime), func() {
+			if !blocks(slot, s.rng) {
+				s.result.MissedSlots++
+				return
+			}
+			s.openAuction()
+		})
+	}
+	// Late bids of the last auction still arrive
+	s.clock.RunUntil(start.Add(time.Duration(config.Slots+1) * config.SlotTime))
+	return s.result, nil
+}
+
+// Runs the simulation once per mechanism, with the same seed so relays see the same values and latencies
+func Compare(config Config, mechanisms ...Mechanism) ([]Result, error) {
+	results := make([]Result, len(mechanisms))
+	for i, mechanism := range mechanisms {
+		config.Mechanism = mechanism
+		result, err := Run(config)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.SlotTime <= 0:
+		return fmt.Errorf("%w: slot time must be positive", ErrInvalidConfig)
+	case c.Period <= 0 || c.Period > c.SlotTime:
+		return fmt.Errorf("%w: period must be positive and within the slot time", ErrInvalidConfig)
+	case len(c.Relays) == 0:
+		return fmt.Errorf("%w: no relays", ErrInvalidConfig)
+	case c.Mechanism == nil:
+		return fmt.Errorf("%w: no mechanism", ErrInvalidConfig)
+	}
+	names := make(map[string]bool, len(c.Relays))
+	for _, relay := range c.Relays {
+		if names[relay.Name] {
+			return fmt.Errorf("%w: duplicate relay %q", ErrInvalidConfig, relay.Name)
+		}
+		names[relay.Name] = true
+		if relay.Latency == nil || relay.Value == nil || relay.Strategy == nil {
+			return fmt.Errorf("%w: relay %q needs a latency, value and strategy", ErrInvalidConfig, relay.Name)
+		}
+	}
+	return nil
+}
+
+type simulation struct {
+	config Config
+	relays []Relay
+	clock  *Clock
+	rng    *rand.Rand
+	result Result
+}
+
+type auction struct {
+	values []*big.Int
+	bids   []Bid
+	leader *Bid
+	closed bool
+}
+
+func (s *simulation) openAuction() {
+	a := &auction{values: make([]*big.Int, len(s.relays))}
+	for i, relay := range s.relays {
+		i, relay := i, relay
+		a.values[i] = relay.Value.Sample(s.rng)
+		s.clock.After(relay.Latency.Sample(s.rng), func() {
+			if amount := relay.Strategy.Open(a.values[i], s.rng); amount != nil {
+				s.submit(a, i, amount)
+			}
+		})
+	}
+	s.clock.After(s.config.Period, func() { s.closeAuction(a) })
+}
+
+// Sends relay i's bid, arriving after its latency
+func (s *simulation) submit(a *auction, i int, amount *big.Int) {
+	relay := s.relays[i]
+	s.clock.After(relay.Latency.Sample(s.rng), func() {
+		s.result.Bids++
+		if a.closed {
+			s.result.LateBids++
+			return
+		}
+		bid := Bid{Relay: relay.Name, Address: relay.Address, AmountWei: amount, ReceivedAt: s.clock.Now()}
+		a.bids = append(a.bids, bid)
+		if !s.config.Mechanism.Open() || (a.leader != nil && !Beats(bid, *a.leader)) {
+			return
+		}
+		a.leader = &bid
+		for j, other := range s.relays {
+			if j == i {
+				continue
+			}
+			j, other := j, other
+			s.clock.After(other.Latency.Sample(s.rng), func() {
+				if amount := other.Strategy.Outbid(a.values[j], bid.AmountWei, s.rng); amount != nil {
+					s.submit(a, j, amount)
+				}
+			})
+		}
+	})
+}
+
+func (s *simulation) closeAuction(a *auction) {
+	a.closed = true
+	winner, price := s.config.Mechanism.Settle(a.bids)
+	if winner == nil {
+		s.result.Unsold++
+		return
+	}
+	s.result.RevenueWei.Add(s.result.RevenueWei, price)
+	s.result.Wins[winner.Relay]++
+	highest := new(big.Int)
+	var value *big.Int
+	for i, relay := range s.relays {
+		if a.values[i].Cmp(highest) > 0 {
+			highest = a.values[i]
+		}
+		if relay.Name == winner.Relay {
+			value = a.values[i]
+		}
+	}
+	s.result.WinnerSurplusWei.Add(s.result.WinnerSurplusWei, new(big.Int).Sub(value, price))
+	if value.Cmp(highest) == 0 {
+		s.result.Efficient++
+	}
+}
@@ -0,0 +1,102 @@
+package sim_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/sim"
+
+	"github.com/stretchr/testify/require"
+)
+
+func relays(strategy sim.Strategy) []sim.Relay {
+	var relays []sim.Relay
+	for _, name := range []string{"alpha", "bravo", "charlie", "delta"} {
+		relays = append(relays, sim.Relay{
+			Name:     name,
+			Latency:  sim.LogNormalLatency{Median: 20 * time.Millisecond, Sigma: 0.5},
+			Value:    sim.UniformValue{Min: 1e8, Max: 1e9},
+			Strategy: strategy,
+		})
+	}
+	return relays
+}
+
+func simConfig(strategy sim.Strategy) sim.Config {
+	return sim.Config{
+		Seed:     1,
+		Slots:    2000,
+		SlotTime: 12 * time.Second,
+		Period:   4 * time.Second,
+		Relays:   relays(strategy),
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	config := simConfig(sim.Incremental{Increment: big.NewInt(5e7)})
+	config.Mechanism = sim.OpenAscending{}
+	config.Blocks = sim.MissRandomly(0.05)
+	first, err := sim.Run(config)
+	require.NoError(t, err)
+	second, err := sim.Run(config)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.NotZero(t, first.MissedSlots)
+
+	config.Seed = 2
+	other, err := sim.Run(config)
+	require.NoError(t, err)
+	require.NotEqual(t, first.RevenueWei, other.RevenueWei)
+}
+
+func TestCompareMechanisms(t *testing.T) {
+	increment := big.NewInt(5e7)
+	open, err := sim.Run(withMechanism(simConfig(sim.Incremental{Increment: increment}), sim.OpenAscending{}))
+	require.NoError(t, err)
+	sealed, err := sim.Compare(simConfig(sim.Truthful{}), sim.FirstPrice{}, sim.SecondPrice{})
+	require.NoError(t, err)
+	firstPrice, secondPrice := sealed[0], sealed[1]
+	shaded, err := sim.Run(withMechanism(simConfig(sim.Shaded{Fraction: 0.75}), sim.FirstPrice{}))
+	require.NoError(t, err)
+
+	for _, result := range []sim.Result{open, firstPrice, secondPrice, shaded} {
+		require.Equal(t, uint64(2000), result.Auctions(), result.Mechanism)
+		require.Zero(t, result.Unsold, result.Mechanism)
+		require.Len(t, result.Wins, 4, "%s: every relay wins some slots", result.Mechanism)
+	}
+	require.Equal(t, 1.0, secondPrice.Efficiency(), "truthful bidding in a second price auction is efficient")
+	require.Equal(t, 1.0, shaded.Efficiency(), "uniform shading keeps the ranking")
+	require.Greater(t, open.Efficiency(), 0.9, "the open auction ends within an increment of the best value")
+	require.Zero(t, firstPrice.WinnerSurplusWei.Sign(), "truthful first price bidders pay their value")
+	require.Equal(t, 1, firstPrice.RevenueWei.Cmp(secondPrice.RevenueWei))
+
+	// Incremental bidders drive the open auction to about the second highest value, as a second price auction
+	gap := new(big.Int).Sub(open.RevenueWei, secondPrice.RevenueWei)
+	require.LessOrEqual(t, gap.CmpAbs(new(big.Int).Mul(increment, big.NewInt(2000))), 0, "revenue gap %s", gap)
+}
+
+func withMechanism(config sim.Config, mechanism sim.Mechanism) sim.Config {
+	config.Mechanism = mechanism
+	return config
+}
+
+func TestLateBidsAndMissedSlots(t *testing.T) {
+	config := simConfig(sim.Truthful{})
+	config.Slots = 100
+	config.Mechanism = sim.SecondPrice{}
+	config.Blocks = sim.MissSlots(3, 50)
+	config.Relays[0].Latency = sim.ConstantLatency(3 * time.Second)
+	config.Relays[0].Value = sim.ConstantValue(1e18)
+	result, err := sim.Run(config)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), result.MissedSlots)
+	require.Equal(t, uint64(98), result.LateBids, "the far relay's bids arrive after the auction closes")
+	require.Equal(t, uint64(98*4), result.Bids)
+	require.Zero(t, result.Wins["alpha"])
+	require.Zero(t, result.Efficient, "the relay valuing slots the most never wins")
+
+	config.Period = time.Minute
+	_, err = sim.Run(config)
+	require.ErrorIs(t, err, sim.ErrInvalidConfig)
+}
@@ -0,0 +1,59 @@
+package sim
+
+import (
+	"math/big"
+	"math/rand"
+)
+
+// Decides a simulated relay's bids, given its private value of winning the slot
+type Strategy interface {
+	// Bid when the auction opens, nil for none
+	Open(value *big.Int, rng *rand.Rand) *big.Int
+	// Bid when another relay takes the lead in an open auction, nil for none
+	Outbid(value *big.Int, leader *big.Int, rng *rand.Rand) *big.Int
+}
+
+// Bids its value once, which is optimal in a second price auction
+type Truthful struct{}
+
+func (Truthful) Open(value *big.Int, rng *rand.Rand) *big.Int {
+	return new(big.Int).Set(value)
+}
+
+func (Truthful) Outbid(value *big.Int, leader *big.Int, rng *rand.Rand) *big.Int {
+	return nil
+}
+
+// Bids a fraction of its value once, as bidders do in first price auctions
+type Shaded struct {
+	Fraction float64
+}
+
+func (s Shaded) Open(value *big.Int, rng *rand.Rand) *big.Int {
+	amount, _ := new(big.Float).Mul(new(big.Float).SetInt(value), big.NewFloat(s.Fraction)).Int(nil)
+	return amount
+}
+
+func (s Shaded) Outbid(value *big.Int, leader *big.Int, rng *rand.Rand) *big.Int {
+	return nil
+}
+
+// Opens with Increment, then outbids the leader by Increment while that stays within its value,
+// like the bidder CLI (cmd/bidder)
+type Incremental struct {
+	Increment *big.Int
+}
+
+func (s Incremental) Open(value *big.Int, rng *rand.Rand) *big.Int {
+	if s.Increment.Cmp(value) > 0 {
+		return nil
+	}
+	return new(big.Int).Set(s.Increment)
+}
+
+func (s Incremental) Outbid(value *big.Int, leader *big.Int, rng *rand.Rand) *big.Int {
+	if amount := new(big.Int).Add(leader, s.Increment); amount.Cmp(value) <= 0 {
+		return amount
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package sim_test
+
+import (
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/sim"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategies(t *testing.T) {
+	value := big.NewInt(100)
+	require.Equal(t, value, sim.Truthful{}.Open(value, nil))
+	require.Nil(t, sim.Truthful{}.Outbid(value, big.NewInt(50), nil))
+	require.Equal(t, big.NewInt(75), sim.Shaded{Fraction: 0.75}.Open(value, nil))
+
+	incremental := sim.Incremental{Increment: big.NewInt(30)}
+	require.Equal(t, big.NewInt(30), incremental.Open(value, nil))
+	require.Equal(t, big.NewInt(100), incremental.Outbid(value, big.NewInt(70), nil))
+	require.Nil(t, incremental.Outbid(value, big.NewInt(71), nil), "never bids above its value")
+	require.Nil(t, incremental.Open(big.NewInt(10), nil))
+}
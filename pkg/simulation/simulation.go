@@ -0,0 +1,129 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Satisfied by *ethclient.Client
+type StateClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+type Simulator struct {
+	logger *slog.Logger
+	client StateClient
+}
+
+func NewSimulator(logger *slog.Logger, client StateClient) *Simulator {
+	return &Simulator{
+		logger: logger,
+		client: client,
+	}
+}
+
+type SimulationError struct {
+	TxIndex int
+	TxHash  common.Hash
+	Reason  string
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("blob tx %d (%s) cannot be included: %s", e.TxIndex, e.TxHash.Hex(), e.Reason)
+}
+
+// Simulates candidate blob txs, in order, against latest state. Relays must not
+// commit to a bundle unless this returns nil.
+func (s *Simulator) Simulate(ctx context.Context, txs []*types.Transaction) error {
+	header, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if header.ExcessBlobGas == nil || header.BlobGasUsed == nil {
+		return fmt.Errorf("latest header is pre-cancun")
+	}
+	nextBlobFee := eip4844.CalcBlobFee(eip4844.CalcExcessBlobGas(*header.ExcessBlobGas, *header.BlobGasUsed))
+
+	nonces := make(map[common.Address]uint64)
+	balances := make(map[common.Address]*big.Int)
+	var totalBlobGas uint64
+
+	for i, tx := range txs {
+		fail := func(reason string, args ...any) error {
+			return &SimulationError{TxIndex: i, TxHash: tx.Hash(), Reason: fmt.Sprintf(reason, args...)}
+		}
+		if tx.Type() != types.BlobTxType {
+			return fail("not a blob tx")
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return fail("invalid signature: %v", err)
+		}
+
+		totalBlobGas += tx.BlobGas()
+		if totalBlobGas > params.MaxBlobGasPerBlock {
+			return fail("bundle exceeds max blob gas per block")
+		}
+		if tx.BlobGasFeeCapIntCmp(nextBlobFee) < 0 {
+			return fail("blob fee cap %v below next blob base fee %v", tx.BlobGasFeeCap(), nextBlobFee)
+		}
+		if header.BaseFee != nil && tx.GasFeeCapIntCmp(header.BaseFee) < 0 {
+			return fail("gas fee cap %v below base fee %v", tx.GasFeeCap(), header.BaseFee)
+		}
+
+		if _, ok := nonces[from]; !ok {
+			nonce, err := s.client.NonceAt(ctx, from, nil)
+			if err != nil {
+				return fmt.Errorf("failed to get nonce of %s: %w", from.Hex(), err)
+			}
+			balance, err := s.client.BalanceAt(ctx, from, nil)
+			if err != nil {
+				return fmt.Errorf("failed to get balance of %s: %w", from.Hex(), err)
+			}
+			nonces[from], balances[from] = nonce, balance
+		}
+		if tx.Nonce() != nonces[from] {
+			return fail("nonce %d, expected %d", tx.Nonce(), nonces[from])
+		}
+		if balances[from].Cmp(tx.Cost()) < 0 {
+			return fail("insufficient balance %v for cost %v", balances[from], tx.Cost())
+		}
+
+		// eth_call runs against latest state, so for later txs of the same sender this only catches unconditional reverts.
+		if _, err := s.client.CallContract(ctx, callMsg(from, tx), nil); err != nil {
+			return fail("execution reverted: %v", err)
+		}
+
+		nonces[from]++
+		balances[from] = new(big.Int).Sub(balances[from], tx.Cost())
+	}
+
+	s.logger.Debug("blob bundle simulation succeeded", "numTxs", len(txs), "blobGas", totalBlobGas)
+	return nil
+}
+
+func callMsg(from common.Address, tx *types.Transaction) ethereum.CallMsg {
+	return ethereum.CallMsg{
+		From:          from,
+		To:            tx.To(),
+		Gas:           tx.Gas(),
+		GasFeeCap:     tx.GasFeeCap(),
+		GasTipCap:     tx.GasTipCap(),
+		Value:         tx.Value(),
+		Data:          tx.Data(),
+		AccessList:    tx.AccessList(),
+		BlobGasFeeCap: tx.BlobGasFeeCap(),
+		BlobHashes:    tx.BlobHashes(),
+	}
+}
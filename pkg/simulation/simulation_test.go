@@ -0,0 +1,134 @@
+package simulation_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/simulation"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	chainID       = big.NewInt(17000)
+	privateKey, _ = crypto.HexToECDSA("27ba389e95214192690a05d46716c5e8a1a91922441f29da3bdfbf5c57bcb494")
+)
+
+type mockStateClient struct {
+	nonce    uint64
+	balance  *big.Int
+	callErr  error
+	excess   uint64
+	baseFee  *big.Int
+	numCalls int
+}
+
+func (m *mockStateClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	blobGasUsed := uint64(0)
+	return &types.Header{BaseFee: m.baseFee, ExcessBlobGas: &m.excess, BlobGasUsed: &blobGasUsed}, nil
+}
+
+func (m *mockStateClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return m.nonce, nil
+}
+
+func (m *mockStateClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return m.balance, nil
+}
+
+func (m *mockStateClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	m.numCalls++
+	return nil, m.callErr
+}
+
+func newBlobTx(t *testing.T, nonce uint64, blobFeeCap uint64, numBlobs int) *types.Transaction {
+	hashes := make([]common.Hash, numBlobs)
+	for i := range hashes {
+		hashes[i][0] = 0x01
+	}
+	tx, err := types.SignNewTx(privateKey, types.NewCancunSigner(chainID), &types.BlobTx{
+		ChainID:    uint256.MustFromBig(chainID),
+		Nonce:      nonce,
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(10),
+		Gas:        21000,
+		Value:      uint256.NewInt(0),
+		BlobFeeCap: uint256.NewInt(blobFeeCap),
+		BlobHashes: hashes,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func newClient() *mockStateClient {
+	return &mockStateClient{nonce: 5, balance: big.NewInt(1e18), baseFee: big.NewInt(7)}
+}
+
+func TestSimulateValidBundle(t *testing.T) {
+	client := newClient()
+	sim := simulation.NewSimulator(slog.Default(), client)
+	txs := []*types.Transaction{newBlobTx(t, 5, 1, 2), newBlobTx(t, 6, 1, 1)}
+	require.NoError(t, sim.Simulate(context.Background(), txs))
+	require.Equal(t, 2, client.numCalls)
+}
+
+func TestSimulateRejectsUnincludableBundles(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*mockStateClient)
+		txs    func(t *testing.T) []*types.Transaction
+		reason string
+	}{
+		{
+			name:   "nonce gap",
+			txs:    func(t *testing.T) []*types.Transaction { return []*types.Transaction{newBlobTx(t, 7, 1, 1)} },
+			reason: "nonce 7, expected 5",
+		},
+		{
+			name:   "insufficient balance",
+			modify: func(m *mockStateClient) { m.balance = big.NewInt(1) },
+			txs:    func(t *testing.T) []*types.Transaction { return []*types.Transaction{newBlobTx(t, 5, 1, 1)} },
+			reason: "insufficient balance",
+		},
+		{
+			name:   "blob fee cap too low",
+			modify: func(m *mockStateClient) { m.excess = 100_000_000 },
+			txs:    func(t *testing.T) []*types.Transaction { return []*types.Transaction{newBlobTx(t, 5, 1, 1)} },
+			reason: "blob fee cap 1 below next blob base fee",
+		},
+		{
+			name: "too many blobs",
+			txs: func(t *testing.T) []*types.Transaction {
+				return []*types.Transaction{newBlobTx(t, 5, 1, 4), newBlobTx(t, 6, 1, 3)}
+			},
+			reason: "bundle exceeds max blob gas per block",
+		},
+		{
+			name:   "reverts",
+			modify: func(m *mockStateClient) { m.callErr = errors.New("execution reverted") },
+			txs:    func(t *testing.T) []*types.Transaction { return []*types.Transaction{newBlobTx(t, 5, 1, 1)} },
+			reason: "execution reverted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient()
+			if tt.modify != nil {
+				tt.modify(client)
+			}
+			sim := simulation.NewSimulator(slog.Default(), client)
+			err := sim.Simulate(context.Background(), tt.txs(t))
+			var simErr *simulation.SimulationError
+			require.ErrorAs(t, err, &simErr)
+			require.Contains(t, simErr.Reason, tt.reason)
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Key prefixes. Records are keyed by prefix || block || seq, big endian, so iteration follows result order.
+var (
+	bidPrefix             = []byte("b")
+	auctionPrefix         = []byte("a")
+	commitmentPrefix      = []byte("c")
+	commitmentIndexPrefix = []byte("h") // Commitment hash to its record key
+	seqKey                = []byte("seq")
+)
+
+// Commitment state isn't part of CommitmentRecord's JSON
+type storedCommitment struct {
+	Commitment commitment.Commitment `json:"commitment"`
+	State      commitment.State      `json:"state"`
+	UpdatedAt  time.Time             `json:"updatedAt"`
+}
+
+// History in an embedded LevelDB database, for single binary deployments without external infrastructure
+type LevelDBStore struct {
+	db *leveldb.DB
+
+	mu  sync.Mutex // Serializes writes, protects seq
+	seq uint64
+}
+
+// Opens or creates the database in the directory at path
+func NewLevelDBStore(path string) (*LevelDBStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open leveldb: %w", err)
+	}
+	s := &LevelDBStore{db: db}
+	value, err := db.Get(seqKey, nil)
+	switch {
+	case err == nil:
+		s.seq = binary.BigEndian.Uint64(value)
+	case !errors.Is(err, leveldb.ErrNotFound):
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *LevelDBStore) SaveBid(bid auction.SignedBid, receivedAt time.Time) error {
+	return s.put(bidPrefix, bid.L1Block.Uint64(), BidRecord{Bid: bid, ReceivedAt: receivedAt})
+}
+
+func (s *LevelDBStore) SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error {
+	return s.put(auctionPrefix, l1Block, AuctionRecord{L1Block: l1Block, Winner: winner, ClosedAt: closedAt})
+}
+
+// The latest won result for the block is settled, as an auction cancelled and rerun for the same block has several
+func (s *LevelDBStore) SaveSettlement(l1Block uint64, tx common.Hash, settledAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keyRange := &util.Range{
+		Start: recordKey(auctionPrefix, cursor{block: l1Block}),
+		Limit: recordKey(auctionPrefix, cursor{block: l1Block, seq: ^uint64(0)}),
+	}
+	iter := s.db.NewIterator(keyRange, nil)
+	defer iter.Release()
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		var record AuctionRecord
+		if err := json.Unmarshal(iter.Value(), &record); err != nil {
+			return err
+		}
+		if record.Winner == nil {
+			continue
+		}
+		record.SettlementTx = &tx
+		record.SettledAt = &settledAt
+		value, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return s.db.Put(append([]byte{}, iter.Key()...), value, nil)
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: no won auction for block %d", ErrNotFound, l1Block)
+}
+
+// Saving a known commitment updates its state, keeping its position in results
+func (s *LevelDBStore) SaveCommitment(c commitment.Commitment, state commitment.State) error {
+	value, err := json.Marshal(storedCommitment{Commitment: c, State: state, UpdatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	indexKey := append(append([]byte{}, commitmentIndexPrefix...), c.Hash().Bytes()...)
+	key, err := s.db.Get(indexKey, nil)
+	if err == nil {
+		return s.db.Put(key, value, nil)
+	}
+	if !errors.Is(err, leveldb.ErrNotFound) {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	key = s.nextKey(batch, commitmentPrefix, c.TargetBlock.Uint64())
+	batch.Put(key, value)
+	batch.Put(indexKey, key)
+	return s.db.Write(batch, nil)
+}
+
+func (s *LevelDBStore) ListBids(filter BidFilter, page Page) ([]BidRecord, string, error) {
+	return listKeys(s, bidPrefix, filter.Blocks, page, func(value []byte) (BidRecord, bool, error) {
+		var record BidRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return record, false, err
+		}
+		return record, filter.matches(record), nil
+	})
+}
+
+func (s *LevelDBStore) ListAuctions(filter AuctionFilter, page Page) ([]AuctionRecord, string, error) {
+	return listKeys(s, auctionPrefix, filter.Blocks, page, func(value []byte) (AuctionRecord, bool, error) {
+		var record AuctionRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return record, false, err
+		}
+		return record, filter.matches(record), nil
+	})
+}
+
+func (s *LevelDBStore) ListCommitments(filter CommitmentFilter, page Page) ([]CommitmentRecord, string, error) {
+	return listKeys(s, commitmentPrefix, filter.Blocks, page, decodeCommitment(filter))
+}
+
+func (s *LevelDBStore) DeleteBids(filter BidFilter) (int, error) {
+	return deleteKeys(s, bidPrefix, filter.Blocks, func(value []byte) (BidRecord, bool, error) {
+		var record BidRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return record, false, err
+		}
+		return record, filter.matches(record), nil
+	}, nil)
+}
+
+func (s *LevelDBStore) DeleteCommitments(filter CommitmentFilter) (int, error) {
+	return deleteKeys(s, commitmentPrefix, filter.Blocks, decodeCommitment(filter), func(batch *leveldb.Batch, record CommitmentRecord) {
+		batch.Delete(append(append([]byte{}, commitmentIndexPrefix...), record.Commitment.Hash().Bytes()...))
+	})
+}
+
+func decodeCommitment(filter CommitmentFilter) func(value []byte) (CommitmentRecord, bool, error) {
+	return func(value []byte) (CommitmentRecord, bool, error) {
+		var stored storedCommitment
+		if err := json.Unmarshal(value, &stored); err != nil {
+			return CommitmentRecord{}, false, err
+		}
+		record := CommitmentRecord{Commitment: stored.Commitment, State: stored.State, UpdatedAt: stored.UpdatedAt}
+		return record, filter.matches(record), nil
+	}
+}
+
+// Reads from a LevelDB snapshot, so writes continue while it's written
+func (s *LevelDBStore) Snapshot(w io.Writer) error {
+	dbSnapshot, err := s.db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer dbSnapshot.Release()
+	snapshot, err := newSnapshotWriter(w)
+	if err != nil {
+		return err
+	}
+	each := func(prefix []byte, write func(value []byte) error) error {
+		iter := dbSnapshot.NewIterator(util.BytesPrefix(prefix), nil)
+		defer iter.Release()
+		for iter.Next() {
+			if err := write(iter.Value()); err != nil {
+				return err
+			}
+		}
+		return iter.Error()
+	}
+	err = each(bidPrefix, func(value []byte) error {
+		var record BidRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return err
+		}
+		return snapshot.bid(record)
+	})
+	if err != nil {
+		return err
+	}
+	err = each(auctionPrefix, func(value []byte) error {
+		var record AuctionRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return err
+		}
+		return snapshot.auction(record)
+	})
+	if err != nil {
+		return err
+	}
+	err = each(commitmentPrefix, func(value []byte) error {
+		record, _, err := decodeCommitment(CommitmentFilter{})(value)
+		if err != nil {
+			return err
+		}
+		return snapshot.commitment(record)
+	})
+	if err != nil {
+		return err
+	}
+	return snapshot.close()
+}
+
+// Replaces history with the snapshot read from r, in a single batch with deleting existing records,
+// so history is unchanged if the snapshot is invalid
+func (s *LevelDBStore) Restore(r io.Reader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := new(leveldb.Batch)
+	for _, prefix := range [][]byte{bidPrefix, auctionPrefix, commitmentPrefix, commitmentIndexPrefix} {
+		iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+		for iter.Next() {
+			batch.Delete(append([]byte{}, iter.Key()...))
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return err
+		}
+	}
+	err := readSnapshot(r, func(e snapshotEntry) error {
+		switch {
+		case e.Bid != nil:
+			return s.putBatch(batch, bidPrefix, e.Bid.Bid.L1Block.Uint64(), e.Bid)
+		case e.Auction != nil:
+			return s.putBatch(batch, auctionPrefix, e.Auction.L1Block, e.Auction)
+		}
+		value, err := json.Marshal(e.Commitment)
+		if err != nil {
+			return err
+		}
+		key := s.nextKey(batch, commitmentPrefix, e.Commitment.Commitment.TargetBlock.Uint64())
+		batch.Put(key, value)
+		batch.Put(append(append([]byte{}, commitmentIndexPrefix...), e.Commitment.Commitment.Hash().Bytes()...), key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.db.Write(batch, nil)
+}
+
+func (s *LevelDBStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *LevelDBStore) put(prefix []byte, block uint64, record any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := new(leveldb.Batch)
+	if err := s.putBatch(batch, prefix, block, record); err != nil {
+		return err
+	}
+	return s.db.Write(batch, nil)
+}
+
+// Adds a new record to batch. Must be called with mu held.
+func (s *LevelDBStore) putBatch(batch *leveldb.Batch, prefix []byte, block uint64, record any) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	batch.Put(s.nextKey(batch, prefix, block), value)
+	return nil
+}
+
+// Allocates the key of a new record, persisting the sequence in batch. Must be called with mu held.
+func (s *LevelDBStore) nextKey(batch *leveldb.Batch, prefix []byte, block uint64) []byte {
+	s.seq++
+	batch.Put(seqKey, binary.BigEndian.AppendUint64(nil, s.seq))
+	return recordKey(prefix, cursor{block: block, seq: s.seq})
+}
+
+func recordKey(prefix []byte, pos cursor) []byte {
+	key := append([]byte{}, prefix...)
+	key = binary.BigEndian.AppendUint64(key, pos.block)
+	return binary.BigEndian.AppendUint64(key, pos.seq)
+}
+
+// Deletes matching records in a single batch, onDelete adds related deletions such as index entries
+func deleteKeys[T any](
+	s *LevelDBStore,
+	prefix []byte,
+	blocks BlockRange,
+	decode func(value []byte) (T, bool, error),
+	onDelete func(batch *leveldb.Batch, record T),
+) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	iter := s.db.NewIterator(blockKeyRange(prefix, blocks), nil)
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	deleted := 0
+	for iter.Next() {
+		record, match, err := decode(iter.Value())
+		if err != nil {
+			return 0, err
+		}
+		if !match {
+			continue
+		}
+		deleted++
+		batch.Delete(append([]byte{}, iter.Key()...))
+		if onDelete != nil {
+			onDelete(batch, record)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+	return deleted, s.db.Write(batch, nil)
+}
+
+func blockKeyRange(prefix []byte, blocks BlockRange) *util.Range {
+	keyRange := util.BytesPrefix(prefix)
+	keyRange.Start = recordKey(prefix, cursor{block: blocks.From})
+	if blocks.To > 0 && blocks.To < ^uint64(0) {
+		keyRange.Limit = recordKey(prefix, cursor{block: blocks.To + 1})
+	}
+	return keyRange
+}
+
+func listKeys[T any](s *LevelDBStore, prefix []byte, blocks BlockRange, page Page, decode func(value []byte) (T, bool, error)) ([]T, string, error) {
+	after, err := parseCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	keyRange := blockKeyRange(prefix, blocks)
+	if after != nil && !after.before(blocks.From, 0) {
+		keyRange.Start = recordKey(prefix, cursor{block: after.block, seq: after.seq + 1})
+	}
+	iter := s.db.NewIterator(keyRange, nil)
+	defer iter.Release()
+
+	limit := page.EffectiveLimit()
+	var results []T
+	var last cursor
+	for iter.Next() {
+		record, match, err := decode(iter.Value())
+		if err != nil {
+			return nil, "", err
+		}
+		if !match {
+			continue
+		}
+		if len(results) == limit {
+			// There's at least one more result, continue after the last one returned
+			return results, last.String(), nil
+		}
+		key := iter.Key()[len(prefix):]
+		results = append(results, record)
+		last = cursor{block: binary.BigEndian.Uint64(key), seq: binary.BigEndian.Uint64(key[8:])}
+	}
+	return results, "", iter.Error()
+}
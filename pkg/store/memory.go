@@ -0,0 +1,247 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type entry[T any] struct {
+	block  uint64
+	seq    uint64
+	record T
+}
+
+// In-memory history, lost on restart
+type MemoryStore struct {
+	mu          sync.RWMutex
+	seq         uint64
+	bids        []entry[BidRecord]
+	auctions    []entry[AuctionRecord]
+	commitments []entry[CommitmentRecord]
+	// Position of each commitment, as commitments are saved again on each state transition
+	commitmentIndex map[common.Hash]cursor
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{commitmentIndex: make(map[common.Hash]cursor)}
+}
+
+// To satisfy listener.Recorder
+func (m *MemoryStore) SaveBid(bid auction.SignedBid, receivedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bids = insert(m.bids, entry[BidRecord]{block: bid.L1Block.Uint64(), seq: m.nextSeq(), record: BidRecord{Bid: bid, ReceivedAt: receivedAt}})
+	return nil
+}
+
+// To satisfy listener.Recorder
+func (m *MemoryStore) SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctions = insert(m.auctions, entry[AuctionRecord]{block: l1Block, seq: m.nextSeq(), record: AuctionRecord{L1Block: l1Block, Winner: winner, ClosedAt: closedAt}})
+	return nil
+}
+
+// To satisfy listener.Recorder
+func (m *MemoryStore) SaveSettlement(l1Block uint64, tx common.Hash, settledAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// An auction cancelled and rerun for the same block has several results, the latest won one is settled
+	for i := len(m.auctions) - 1; i >= 0; i-- {
+		record := &m.auctions[i].record
+		if record.L1Block == l1Block && record.Winner != nil {
+			record.SettlementTx = &tx
+			record.SettledAt = &settledAt
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no won auction for block %d", ErrNotFound, l1Block)
+}
+
+// To satisfy commitment.Recorder. Saving a known commitment updates its state.
+func (m *MemoryStore) SaveCommitment(c commitment.Commitment, state commitment.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saveCommitment(CommitmentRecord{Commitment: c, State: state, UpdatedAt: time.Now()})
+	return nil
+}
+
+// Must be called with mu held
+func (m *MemoryStore) saveCommitment(record CommitmentRecord) {
+	hash := record.Commitment.Hash()
+	if pos, ok := m.commitmentIndex[hash]; ok {
+		m.commitments[find(m.commitments, pos)].record = record
+		return
+	}
+	pos := cursor{block: record.Commitment.TargetBlock.Uint64(), seq: m.nextSeq()}
+	m.commitments = insert(m.commitments, entry[CommitmentRecord]{block: pos.block, seq: pos.seq, record: record})
+	m.commitmentIndex[hash] = pos
+}
+
+func (m *MemoryStore) ListBids(filter BidFilter, page Page) ([]BidRecord, string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return list(m.bids, page, filter.matches)
+}
+
+func (m *MemoryStore) ListAuctions(filter AuctionFilter, page Page) ([]AuctionRecord, string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return list(m.auctions, page, filter.matches)
+}
+
+func (m *MemoryStore) ListCommitments(filter CommitmentFilter, page Page) ([]CommitmentRecord, string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return list(m.commitments, page, filter.matches)
+}
+
+func (m *MemoryStore) DeleteBids(filter BidFilter) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted []BidRecord
+	m.bids, deleted = remove(m.bids, filter.matches)
+	return len(deleted), nil
+}
+
+func (m *MemoryStore) DeleteCommitments(filter CommitmentFilter) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted []CommitmentRecord
+	m.commitments, deleted = remove(m.commitments, filter.matches)
+	for _, record := range deleted {
+		delete(m.commitmentIndex, record.Commitment.Hash())
+	}
+	return len(deleted), nil
+}
+
+// History is copied under the read lock, then written without holding it
+func (m *MemoryStore) Snapshot(w io.Writer) error {
+	m.mu.RLock()
+	bids, auctions, commitments := slices.Clone(m.bids), slices.Clone(m.auctions), slices.Clone(m.commitments)
+	m.mu.RUnlock()
+	snapshot, err := newSnapshotWriter(w)
+	if err != nil {
+		return err
+	}
+	for _, e := range bids {
+		if err := snapshot.bid(e.record); err != nil {
+			return err
+		}
+	}
+	for _, e := range auctions {
+		if err := snapshot.auction(e.record); err != nil {
+			return err
+		}
+	}
+	for _, e := range commitments {
+		if err := snapshot.commitment(e.record); err != nil {
+			return err
+		}
+	}
+	return snapshot.close()
+}
+
+// Replaces history with the snapshot read from r. The snapshot is read into a new store first,
+// so history is unchanged if it's invalid.
+func (m *MemoryStore) Restore(r io.Reader) error {
+	restored := NewMemoryStore()
+	err := readSnapshot(r, func(e snapshotEntry) error {
+		switch {
+		case e.Bid != nil:
+			restored.bids = insert(restored.bids, entry[BidRecord]{block: e.Bid.Bid.L1Block.Uint64(), seq: restored.nextSeq(), record: *e.Bid})
+		case e.Auction != nil:
+			restored.auctions = insert(restored.auctions, entry[AuctionRecord]{block: e.Auction.L1Block, seq: restored.nextSeq(), record: *e.Auction})
+		case e.Commitment != nil:
+			restored.saveCommitment(CommitmentRecord{Commitment: e.Commitment.Commitment, State: e.Commitment.State, UpdatedAt: e.Commitment.UpdatedAt})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bids, m.auctions, m.commitments, m.commitmentIndex = restored.bids, restored.auctions, restored.commitments, restored.commitmentIndex
+	// Records saved from now on must sort after restored ones in the same block
+	m.seq = max(m.seq, restored.seq)
+	return nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// Must be called with mu held
+func (m *MemoryStore) nextSeq() uint64 {
+	m.seq++
+	return m.seq
+}
+
+// Keeps entries ordered by block then seq. Records mostly arrive in block order, so this is usually an append.
+func insert[T any](entries []entry[T], e entry[T]) []entry[T] {
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].block > e.block || (entries[i].block == e.block && entries[i].seq > e.seq)
+	})
+	entries = append(entries, entry[T]{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = e
+	return entries
+}
+
+// Removes matching entries in place, keeping order
+func remove[T any](entries []entry[T], match func(T) bool) ([]entry[T], []T) {
+	kept := entries[:0]
+	var removed []T
+	for _, e := range entries {
+		if match(e.record) {
+			removed = append(removed, e.record)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	clear(entries[len(kept):])
+	return kept, removed
+}
+
+// Index of the entry at pos, which must exist
+func find[T any](entries []entry[T], pos cursor) int {
+	return sort.Search(len(entries), func(i int) bool {
+		return entries[i].block > pos.block || (entries[i].block == pos.block && entries[i].seq >= pos.seq)
+	})
+}
+
+func list[T any](entries []entry[T], page Page, match func(T) bool) ([]T, string, error) {
+	after, err := parseCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := page.EffectiveLimit()
+	start := 0
+	if after != nil {
+		start = sort.Search(len(entries), func(i int) bool { return after.before(entries[i].block, entries[i].seq) })
+	}
+	var results []T
+	var last entry[T]
+	for _, e := range entries[start:] {
+		if !match(e.record) {
+			continue
+		}
+		if len(results) == limit {
+			// There's at least one more result, continue after the last one returned
+			return results, cursor{block: last.block, seq: last.seq}.String(), nil
+		}
+		results = append(results, e.record)
+		last = e
+	}
+	return results, "", nil
+}
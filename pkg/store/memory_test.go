@@ -0,0 +1,148 @@
+package store_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListBidsPaginated(t *testing.T) {
+	s := store.NewMemoryStore()
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	relay1 := crypto.PubkeyToAddress(pk1.PublicKey)
+	// Saved out of block order, listed in block order
+	for _, block := range []int64{102, 100, 101, 100, 103} {
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk1), time.Now()))
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk2), time.Now()))
+	}
+
+	var blocks []uint64
+	page := store.Page{Limit: 3}
+	for {
+		bids, next, err := s.ListBids(store.BidFilter{Blocks: store.BlockRange{From: 100, To: 102}, Relay: &relay1}, page)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(bids), 3)
+		for _, bid := range bids {
+			require.Equal(t, relay1, bid.Bid.Address)
+			blocks = append(blocks, bid.Bid.L1Block.Uint64())
+		}
+		if next == "" {
+			break
+		}
+		page.Cursor = next
+	}
+	require.Equal(t, []uint64{100, 100, 101, 102}, blocks)
+
+	_, _, err := s.ListBids(store.BidFilter{}, store.Page{Cursor: "invalid"})
+	require.ErrorIs(t, err, store.ErrInvalidCursor)
+}
+
+func TestListAuctions(t *testing.T) {
+	s := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.NoError(t, s.SaveAuctionResult(100, winner, time.Now()))
+	require.NoError(t, s.SaveAuctionResult(101, nil, time.Now()))
+
+	auctions, next, err := s.ListAuctions(store.AuctionFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Empty(t, next)
+	require.Len(t, auctions, 2)
+
+	auctions, _, err = s.ListAuctions(store.AuctionFilter{Winner: &winner.Address}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, auctions, 1)
+	require.Equal(t, uint64(100), auctions[0].L1Block)
+}
+
+func TestListCommitmentsUpdatesState(t *testing.T) {
+	s := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(101),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateActive))
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateFulfilled))
+
+	active := commitment.StateActive
+	commitments, _, err := s.ListCommitments(store.CommitmentFilter{State: &active}, store.Page{})
+	require.NoError(t, err)
+	require.Empty(t, commitments)
+
+	commitments, _, err = s.ListCommitments(store.CommitmentFilter{Blocks: store.BlockRange{From: 100, To: 100}}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, commitments, 1)
+	require.Equal(t, commitment.StateFulfilled, commitments[0].State)
+}
+
+func TestPageLimit(t *testing.T) {
+	require.Equal(t, store.DefaultPageLimit, store.Page{}.EffectiveLimit())
+	require.Equal(t, store.MaxPageLimit, store.Page{Limit: 1 << 20}.EffectiveLimit())
+	require.Equal(t, 5, store.Page{Limit: 5}.EffectiveLimit())
+}
+
+func TestSaveSettlement(t *testing.T) {
+	s := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	require.NoError(t, s.SaveAuctionResult(100, winner, time.Now()))
+	require.NoError(t, s.SaveAuctionResult(101, nil, time.Now()))
+	require.ErrorIs(t, s.SaveSettlement(101, common.Hash{0x01}, time.Now()), store.ErrNotFound, "no winner to settle")
+
+	unsettled, _, err := s.ListAuctions(store.AuctionFilter{Unsettled: true}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, unsettled, 1)
+	require.NoError(t, s.SaveSettlement(100, common.Hash{0x01}, time.Now()))
+	unsettled, _, err = s.ListAuctions(store.AuctionFilter{Unsettled: true}, store.Page{})
+	require.NoError(t, err)
+	require.Empty(t, unsettled)
+	auctions, _, err := s.ListAuctions(store.AuctionFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{0x01}, *auctions[0].SettlementTx)
+}
+
+func TestDelete(t *testing.T) {
+	s := store.NewMemoryStore()
+	pk, _ := crypto.GenerateKey()
+	now := time.Now()
+	for _, block := range []int64{100, 101, 102} {
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(block), pk), now.Add(time.Duration(block-103)*time.Hour)))
+	}
+	deleted, err := s.DeleteBids(store.BidFilter{ReceivedBefore: now.Add(-90 * time.Minute)})
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+	bids, _, err := s.ListBids(store.BidFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, bids, 1)
+	require.Equal(t, uint64(102), bids[0].Bid.L1Block.Uint64())
+
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(101),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateFulfilled))
+	deleted, err = s.DeleteCommitments(store.CommitmentFilter{Blocks: store.BlockRange{From: 100, To: 100}})
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	// Saved again after deletion, it's a new record
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateFulfilled))
+	commitments, _, err := s.ListCommitments(store.CommitmentFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, commitments, 1)
+}
@@ -0,0 +1,105 @@
+package store_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Points to a disposable database, whose history tables are truncated
+const postgresURLEnv = "POSTGRES_TEST_URL"
+
+func newPostgresStore(t *testing.T) *store.PostgresStore {
+	url := os.Getenv(postgresURLEnv)
+	if url == "" {
+		t.Skipf("%s not set", postgresURLEnv)
+	}
+	s, err := store.NewPostgresStore(context.Background(), url)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	conn, err := pgx.Connect(context.Background(), url)
+	require.NoError(t, err)
+	defer conn.Close(context.Background())
+	_, err = conn.Exec(context.Background(), "TRUNCATE bids, auctions, commitments RESTART IDENTITY")
+	require.NoError(t, err)
+	return s
+}
+
+func TestPostgresStore(t *testing.T) {
+	s := newPostgresStore(t)
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	relay1 := crypto.PubkeyToAddress(pk1.PublicKey)
+	for _, block := range []int64{102, 100, 101, 100, 103} {
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk1), time.Now()))
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk2), time.Now()))
+	}
+	var blocks []uint64
+	page := store.Page{Limit: 3}
+	for {
+		bids, next, err := s.ListBids(store.BidFilter{Blocks: store.BlockRange{From: 100, To: 102}, Relay: &relay1}, page)
+		require.NoError(t, err)
+		for _, bid := range bids {
+			require.Equal(t, relay1, bid.Bid.Address)
+			require.NoError(t, bid.Bid.Validate(), "bids round trip unchanged")
+			blocks = append(blocks, bid.Bid.L1Block.Uint64())
+		}
+		if next == "" {
+			break
+		}
+		page.Cursor = next
+	}
+	require.Equal(t, []uint64{100, 100, 101, 102}, blocks)
+	_, _, err := s.ListBids(store.BidFilter{}, store.Page{Cursor: "invalid"})
+	require.ErrorIs(t, err, store.ErrInvalidCursor)
+
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)
+	require.NoError(t, s.SaveAuctionResult(100, winner, time.Now()))
+	require.NoError(t, s.SaveAuctionResult(101, nil, time.Now()))
+	auctions, _, err := s.ListAuctions(store.AuctionFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, auctions, 2)
+	require.Equal(t, *winner, *auctions[0].Winner)
+	require.Nil(t, auctions[1].Winner)
+	auctions, _, err = s.ListAuctions(store.AuctionFilter{Winner: &relay1}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, auctions, 1)
+	require.ErrorIs(t, s.SaveSettlement(101, common.Hash{0x01}, time.Now()), store.ErrNotFound)
+	require.NoError(t, s.SaveSettlement(100, common.Hash{0x01}, time.Now()))
+	auctions, _, err = s.ListAuctions(store.AuctionFilter{Unsettled: true}, store.Page{})
+	require.NoError(t, err)
+	require.Empty(t, auctions)
+
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(101),
+		FeeWei:          big.NewInt(5),
+	}, pk1)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateActive))
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateFulfilled))
+	fulfilled := commitment.StateFulfilled
+	commitments, _, err := s.ListCommitments(store.CommitmentFilter{State: &fulfilled}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, commitments, 1)
+	require.Equal(t, c.Hash(), commitments[0].Commitment.Hash())
+
+	deleted, err := s.DeleteCommitments(store.CommitmentFilter{State: &fulfilled})
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	deleted, err = s.DeleteBids(store.BidFilter{Relay: &relay1, ReceivedBefore: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, 5, deleted)
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Snapshots are gzipped JSON lines: a header, then one record per line, each kind in result order.
+// The format is the same for every backend, so a snapshot also migrates history between backends.
+const snapshotVersion = 1
+
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+type snapshotHeader struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Exactly one field is set
+type snapshotEntry struct {
+	Bid        *BidRecord        `json:"bid,omitempty"`
+	Auction    *AuctionRecord    `json:"auction,omitempty"`
+	Commitment *storedCommitment `json:"commitment,omitempty"`
+}
+
+type snapshotWriter struct {
+	gz  *gzip.Writer
+	enc *json.Encoder
+}
+
+func newSnapshotWriter(w io.Writer) (*snapshotWriter, error) {
+	gz := gzip.NewWriter(w)
+	s := &snapshotWriter{gz: gz, enc: json.NewEncoder(gz)}
+	return s, s.enc.Encode(snapshotHeader{Version: snapshotVersion, CreatedAt: time.Now()})
+}
+
+func (s *snapshotWriter) bid(record BidRecord) error {
+	return s.enc.Encode(snapshotEntry{Bid: &record})
+}
+
+func (s *snapshotWriter) auction(record AuctionRecord) error {
+	return s.enc.Encode(snapshotEntry{Auction: &record})
+}
+
+func (s *snapshotWriter) commitment(record CommitmentRecord) error {
+	return s.enc.Encode(snapshotEntry{Commitment: &storedCommitment{
+		Commitment: record.Commitment,
+		State:      record.State,
+		UpdatedAt:  record.UpdatedAt,
+	}})
+}
+
+// Flushes the snapshot, which is only complete once closed
+func (s *snapshotWriter) close() error {
+	return s.gz.Close()
+}
+
+// Calls apply with each record in the snapshot read from r, in order. Backends apply records
+// to a transaction or copy and only commit once the whole snapshot was read, so a truncated or
+// corrupt snapshot leaves history unchanged.
+func readSnapshot(r io.Reader, apply func(entry snapshotEntry) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(bufio.NewReader(gz))
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, header.Version)
+	}
+	for {
+		var entry snapshotEntry
+		err := dec.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+		if entry.Bid == nil && entry.Auction == nil && entry.Commitment == nil {
+			return fmt.Errorf("%w: empty record", ErrInvalidSnapshot)
+		}
+		if err := apply(entry); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package store_test
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var backends = map[string]func(t *testing.T) store.Store{
+	"memory": func(t *testing.T) store.Store { return store.NewMemoryStore() },
+	"leveldb": func(t *testing.T) store.Store {
+		s, err := store.NewLevelDBStore(t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return s
+	},
+	"sqlite": func(t *testing.T) store.Store {
+		s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "history.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return s
+	},
+	"postgres": func(t *testing.T) store.Store { return newPostgresStore(t) },
+}
+
+type history struct {
+	bids        []store.BidRecord
+	auctions    []store.AuctionRecord
+	commitments []store.CommitmentRecord
+}
+
+func dump(t *testing.T, s store.Store) history {
+	var h history
+	var err error
+	h.bids, _, err = s.ListBids(store.BidFilter{}, store.Page{Limit: store.MaxPageLimit})
+	require.NoError(t, err)
+	h.auctions, _, err = s.ListAuctions(store.AuctionFilter{}, store.Page{Limit: store.MaxPageLimit})
+	require.NoError(t, err)
+	h.commitments, _, err = s.ListCommitments(store.CommitmentFilter{}, store.Page{Limit: store.MaxPageLimit})
+	require.NoError(t, err)
+	// Backends differ in time zone and precision, compare instants
+	for i := range h.bids {
+		h.bids[i].ReceivedAt = h.bids[i].ReceivedAt.UTC().Truncate(time.Microsecond)
+	}
+	for i := range h.auctions {
+		h.auctions[i].ClosedAt = h.auctions[i].ClosedAt.UTC().Truncate(time.Microsecond)
+		if settledAt := h.auctions[i].SettledAt; settledAt != nil {
+			utc := settledAt.UTC().Truncate(time.Microsecond)
+			h.auctions[i].SettledAt = &utc
+		}
+	}
+	for i := range h.commitments {
+		h.commitments[i].UpdatedAt = h.commitments[i].UpdatedAt.UTC().Truncate(time.Microsecond)
+	}
+	return h
+}
+
+func seed(t *testing.T, s store.Store) {
+	pk, _ := crypto.GenerateKey()
+	for _, block := range []int64{101, 100, 102} {
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk), time.Now()))
+	}
+	require.NoError(t, s.SaveAuctionResult(100, auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk), time.Now()))
+	require.NoError(t, s.SaveAuctionResult(101, nil, time.Now()))
+	require.NoError(t, s.SaveSettlement(100, common.Hash{0x01}, time.Now()))
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(101),
+		FeeWei:          big.NewInt(5),
+	}, pk)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateFulfilled))
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	for name, newStore := range backends {
+		t.Run(name, func(t *testing.T) {
+			if name == "postgres" && os.Getenv(postgresURLEnv) == "" {
+				t.Skipf("%s not set", postgresURLEnv)
+			}
+			s := newStore(t)
+			seed(t, s)
+			want := dump(t, s)
+			var snapshot bytes.Buffer
+			require.NoError(t, s.Snapshot(&snapshot))
+
+			// Restoring replaces history written since
+			pk, _ := crypto.GenerateKey()
+			require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(1), big.NewInt(103), pk), time.Now()))
+			require.NoError(t, s.Restore(bytes.NewReader(snapshot.Bytes())))
+			require.Equal(t, want, dump(t, s))
+
+			// An invalid snapshot leaves history unchanged
+			truncated := snapshot.Bytes()[:snapshot.Len()-10]
+			require.ErrorIs(t, s.Restore(bytes.NewReader(truncated)), store.ErrInvalidSnapshot)
+			require.ErrorIs(t, s.Restore(bytes.NewReader([]byte("not a snapshot"))), store.ErrInvalidSnapshot)
+			require.Equal(t, want, dump(t, s))
+
+			// Snapshots are backend independent
+			migrated := store.NewMemoryStore()
+			require.NoError(t, migrated.Restore(bytes.NewReader(snapshot.Bytes())))
+			require.Equal(t, want, dump(t, migrated))
+		})
+	}
+}
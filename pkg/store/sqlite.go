@@ -0,0 +1,407 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+	_ "modernc.org/sqlite"
+)
+
+// Applied on startup, statements must be idempotent. Times are unix nanoseconds, wei amounts decimal text.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS bids (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	l1_block INTEGER NOT NULL,
+	relay BLOB NOT NULL,
+	amount_wei TEXT NOT NULL,
+	signature BLOB NOT NULL,
+	received_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS bids_block_seq ON bids (l1_block, seq);
+CREATE INDEX IF NOT EXISTS bids_relay_block_seq ON bids (relay, l1_block, seq);
+
+CREATE TABLE IF NOT EXISTS auctions (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	l1_block INTEGER NOT NULL,
+	-- Winning bid, null if the auction closed with no winner
+	winner BLOB,
+	winner_amount_wei TEXT,
+	winner_signature BLOB,
+	closed_at INTEGER NOT NULL,
+	settlement_tx BLOB,
+	settled_at INTEGER
+);
+CREATE INDEX IF NOT EXISTS auctions_block_seq ON auctions (l1_block, seq);
+CREATE INDEX IF NOT EXISTS auctions_winner_block_seq ON auctions (winner, l1_block, seq);
+
+CREATE TABLE IF NOT EXISTS commitments (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	hash BLOB NOT NULL UNIQUE,
+	target_block INTEGER NOT NULL,
+	state INTEGER NOT NULL,
+	commitment TEXT NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS commitments_block_seq ON commitments (target_block, seq);
+`
+
+const (
+	sqliteBidSelect        = "SELECT l1_block, seq, relay, amount_wei, signature, received_at FROM bids"
+	sqliteAuctionSelect    = "SELECT l1_block, seq, winner, winner_amount_wei, winner_signature, closed_at, settlement_tx, settled_at FROM auctions"
+	sqliteCommitmentSelect = "SELECT target_block, seq, commitment, state, updated_at FROM commitments"
+)
+
+// History in a SQLite database file, for test environments and small deployments without Postgres.
+// Uses a pure-Go driver, so no cgo is needed.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// Opens or creates the database at path, and creates the schema if missing
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	// WAL lets reads, such as snapshots, run alongside writes. Concurrent writers wait for each other up to the busy timeout.
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) SaveBid(bid auction.SignedBid, receivedAt time.Time) error {
+	_, err := s.exec(
+		`INSERT INTO bids (l1_block, relay, amount_wei, signature, received_at) VALUES ($1, $2, $3, $4, $5)`,
+		int64(bid.L1Block.Uint64()), bid.Address.Bytes(), bid.AmountWei.String(), []byte(bid.Signature), receivedAt.UnixNano(),
+	)
+	return err
+}
+
+func (s *SQLiteStore) SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error {
+	var relay, signature []byte
+	var amount *string
+	if winner != nil {
+		relay, signature = winner.Address.Bytes(), winner.Signature
+		amountStr := winner.AmountWei.String()
+		amount = &amountStr
+	}
+	_, err := s.exec(
+		`INSERT INTO auctions (l1_block, winner, winner_amount_wei, winner_signature, closed_at) VALUES ($1, $2, $3, $4, $5)`,
+		int64(l1Block), relay, amount, signature, closedAt.UnixNano(),
+	)
+	return err
+}
+
+// The latest won result for the block is settled, as an auction cancelled and rerun for the same block has several
+func (s *SQLiteStore) SaveSettlement(l1Block uint64, tx common.Hash, settledAt time.Time) error {
+	affected, err := s.exec(
+		`UPDATE auctions SET settlement_tx = $2, settled_at = $3
+		WHERE seq = (SELECT max(seq) FROM auctions WHERE l1_block = $1 AND winner IS NOT NULL)`,
+		int64(l1Block), tx.Bytes(), settledAt.UnixNano(),
+	)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: no won auction for block %d", ErrNotFound, l1Block)
+	}
+	return nil
+}
+
+// Saving a known commitment updates its state, keeping its position in results
+func (s *SQLiteStore) SaveCommitment(c commitment.Commitment, state commitment.State) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = s.exec(
+		`INSERT INTO commitments (hash, target_block, state, commitment, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (hash) DO UPDATE SET state = excluded.state, commitment = excluded.commitment, updated_at = excluded.updated_at`,
+		c.Hash().Bytes(), int64(c.TargetBlock.Uint64()), int(state), string(data), time.Now().UnixNano(),
+	)
+	return err
+}
+
+func (s *SQLiteStore) ListBids(filter BidFilter, page Page) ([]BidRecord, string, error) {
+	return listSQLiteRows(s, sqliteBidSelect, "l1_block", sqliteBidQuery(filter), page, scanSQLiteBid)
+}
+
+func (s *SQLiteStore) ListAuctions(filter AuctionFilter, page Page) ([]AuctionRecord, string, error) {
+	var q query
+	q.blocks("l1_block", filter.Blocks)
+	if filter.Winner != nil {
+		q.where("winner = %s", filter.Winner.Bytes())
+	}
+	if filter.Unsettled {
+		q.conditions = append(q.conditions, "winner IS NOT NULL AND settlement_tx IS NULL")
+	}
+	return listSQLiteRows(s, sqliteAuctionSelect, "l1_block", q, page, scanSQLiteAuction)
+}
+
+func (s *SQLiteStore) ListCommitments(filter CommitmentFilter, page Page) ([]CommitmentRecord, string, error) {
+	return listSQLiteRows(s, sqliteCommitmentSelect, "target_block", commitmentQuery(filter), page, scanSQLiteCommitment)
+}
+
+func (s *SQLiteStore) DeleteBids(filter BidFilter) (int, error) {
+	return s.delete("bids", sqliteBidQuery(filter))
+}
+
+func (s *SQLiteStore) DeleteCommitments(filter CommitmentFilter) (int, error) {
+	return s.delete("commitments", commitmentQuery(filter))
+}
+
+func (s *SQLiteStore) delete(table string, q query) (int, error) {
+	sql := "DELETE FROM " + table
+	if len(q.conditions) > 0 {
+		sql += " WHERE " + strings.Join(q.conditions, " AND ")
+	}
+	affected, err := s.exec(sql, q.args...)
+	return int(affected), err
+}
+
+// Reads in a single transaction, which sees the database as of its first read while writes continue
+func (s *SQLiteStore) Snapshot(w io.Writer) error {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	snapshot, err := newSnapshotWriter(w)
+	if err != nil {
+		return err
+	}
+	if err := snapshotSQLiteRows(ctx, tx, sqliteBidSelect+" ORDER BY l1_block, seq", scanSQLiteBid, snapshot.bid); err != nil {
+		return err
+	}
+	if err := snapshotSQLiteRows(ctx, tx, sqliteAuctionSelect+" ORDER BY l1_block, seq", scanSQLiteAuction, snapshot.auction); err != nil {
+		return err
+	}
+	if err := snapshotSQLiteRows(ctx, tx, sqliteCommitmentSelect+" ORDER BY target_block, seq", scanSQLiteCommitment, snapshot.commitment); err != nil {
+		return err
+	}
+	return snapshot.close()
+}
+
+// Replaces history with the snapshot read from r in a single transaction, so history is unchanged
+// if the snapshot is invalid. Writes made meanwhile wait for the transaction, up to the busy timeout.
+func (s *SQLiteStore) Restore(r io.Reader) error {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range []string{"bids", "auctions", "commitments"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return err
+		}
+	}
+	err = readSnapshot(r, func(e snapshotEntry) error {
+		var err error
+		switch {
+		case e.Bid != nil:
+			bid := e.Bid.Bid
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO bids (l1_block, relay, amount_wei, signature, received_at) VALUES ($1, $2, $3, $4, $5)`,
+				int64(bid.L1Block.Uint64()), bid.Address.Bytes(), bid.AmountWei.String(), []byte(bid.Signature), e.Bid.ReceivedAt.UnixNano(),
+			)
+		case e.Auction != nil:
+			var relay, signature, settlementTx []byte
+			var amount *string
+			var settledAt *int64
+			if winner := e.Auction.Winner; winner != nil {
+				relay, signature = winner.Address.Bytes(), winner.Signature
+				amountStr := winner.AmountWei.String()
+				amount = &amountStr
+			}
+			if e.Auction.SettlementTx != nil {
+				settlementTx = e.Auction.SettlementTx.Bytes()
+			}
+			if e.Auction.SettledAt != nil {
+				nanos := e.Auction.SettledAt.UnixNano()
+				settledAt = &nanos
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO auctions (l1_block, winner, winner_amount_wei, winner_signature, closed_at, settlement_tx, settled_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				int64(e.Auction.L1Block), relay, amount, signature, e.Auction.ClosedAt.UnixNano(), settlementTx, settledAt,
+			)
+		case e.Commitment != nil:
+			var data []byte
+			if data, err = json.Marshal(e.Commitment.Commitment); err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO commitments (hash, target_block, state, commitment, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+				e.Commitment.Commitment.Hash().Bytes(), int64(e.Commitment.Commitment.TargetBlock.Uint64()), int(e.Commitment.State), string(data), e.Commitment.UpdatedAt.UnixNano(),
+			)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) exec(sql string, args ...any) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	result, err := s.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// As bidQuery, with times in unix nanoseconds
+func sqliteBidQuery(filter BidFilter) query {
+	var q query
+	q.blocks("l1_block", filter.Blocks)
+	if filter.Relay != nil {
+		q.where("relay = %s", filter.Relay.Bytes())
+	}
+	if !filter.ReceivedBefore.IsZero() {
+		q.where("received_at < %s", filter.ReceivedBefore.UnixNano())
+	}
+	return q
+}
+
+func snapshotSQLiteRows[T any](ctx context.Context, tx *sql.Tx, query string, scan func(rows *sql.Rows) (T, cursor, error), write func(T) error) error {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		record, _, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanSQLiteBid(rows *sql.Rows) (BidRecord, cursor, error) {
+	var pos cursor
+	var relay, signature []byte
+	var amount string
+	var receivedAt int64
+	var record BidRecord
+	if err := rows.Scan(&pos.block, &pos.seq, &relay, &amount, &signature, &receivedAt); err != nil {
+		return record, pos, err
+	}
+	bid, err := scanBid(pos.block, relay, amount, signature)
+	record.Bid, record.ReceivedAt = bid, time.Unix(0, receivedAt)
+	return record, pos, err
+}
+
+func scanSQLiteAuction(rows *sql.Rows) (AuctionRecord, cursor, error) {
+	var pos cursor
+	var relay, signature, settlementTx []byte
+	var amount *string
+	var closedAt int64
+	var settledAt *int64
+	var record AuctionRecord
+	if err := rows.Scan(&pos.block, &pos.seq, &relay, &amount, &signature, &closedAt, &settlementTx, &settledAt); err != nil {
+		return record, pos, err
+	}
+	record.L1Block, record.ClosedAt = pos.block, time.Unix(0, closedAt)
+	if settlementTx != nil {
+		tx := common.BytesToHash(settlementTx)
+		record.SettlementTx = &tx
+	}
+	if settledAt != nil {
+		t := time.Unix(0, *settledAt)
+		record.SettledAt = &t
+	}
+	if amount != nil {
+		bid, err := scanBid(pos.block, relay, *amount, signature)
+		if err != nil {
+			return record, pos, err
+		}
+		record.Winner = &bid
+	}
+	return record, pos, nil
+}
+
+func scanSQLiteCommitment(rows *sql.Rows) (CommitmentRecord, cursor, error) {
+	var pos cursor
+	var data string
+	var state int16
+	var updatedAt int64
+	var record CommitmentRecord
+	if err := rows.Scan(&pos.block, &pos.seq, &data, &state, &updatedAt); err != nil {
+		return record, pos, err
+	}
+	record.State, record.UpdatedAt = commitment.State(state), time.Unix(0, updatedAt)
+	return record, pos, json.Unmarshal([]byte(data), &record.Commitment)
+}
+
+// Fetches one row past the page limit to tell whether there's a next page, as listRows
+func listSQLiteRows[T any](
+	s *SQLiteStore,
+	selectFrom string,
+	blockColumn string,
+	q query,
+	page Page,
+	scan func(rows *sql.Rows) (T, cursor, error),
+) ([]T, string, error) {
+	after, err := parseCursor(page.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if after != nil {
+		q.args = append(q.args, int64(after.block), int64(after.seq))
+		q.conditions = append(q.conditions, fmt.Sprintf("(%s, seq) > ($%d, $%d)", blockColumn, len(q.args)-1, len(q.args)))
+	}
+	sql := selectFrom
+	if len(q.conditions) > 0 {
+		sql += " WHERE " + strings.Join(q.conditions, " AND ")
+	}
+	limit := page.EffectiveLimit()
+	sql += fmt.Sprintf(" ORDER BY %s, seq LIMIT %d", blockColumn, limit+1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	rows, err := s.db.QueryContext(ctx, sql, q.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+	var results []T
+	var last cursor
+	for rows.Next() {
+		if len(results) == limit {
+			return results, last.String(), nil
+		}
+		record, pos, err := scan(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, record)
+		last = pos
+	}
+	return results, "", rows.Err()
+}
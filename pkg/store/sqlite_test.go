@@ -0,0 +1,109 @@
+package store_test
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+	"blob-preconfs/pkg/store"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	defer s.Close()
+	pk1, _ := crypto.GenerateKey()
+	pk2, _ := crypto.GenerateKey()
+	relay1 := crypto.PubkeyToAddress(pk1.PublicKey)
+	for _, block := range []int64{102, 100, 101, 100, 103} {
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk1), time.Now()))
+		require.NoError(t, s.SaveBid(*auction.MustCreateSignedBid(big.NewInt(block), big.NewInt(block), pk2), time.Now()))
+	}
+	var blocks []uint64
+	page := store.Page{Limit: 3}
+	for {
+		bids, next, err := s.ListBids(store.BidFilter{Blocks: store.BlockRange{From: 100, To: 102}, Relay: &relay1}, page)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(bids), 3)
+		for _, bid := range bids {
+			require.Equal(t, relay1, bid.Bid.Address)
+			require.NoError(t, bid.Bid.Validate(), "bids round trip unchanged")
+			blocks = append(blocks, bid.Bid.L1Block.Uint64())
+		}
+		if next == "" {
+			break
+		}
+		page.Cursor = next
+	}
+	require.Equal(t, []uint64{100, 100, 101, 102}, blocks)
+	_, _, err = s.ListBids(store.BidFilter{}, store.Page{Cursor: "invalid"})
+	require.ErrorIs(t, err, store.ErrInvalidCursor)
+
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk1)
+	require.NoError(t, s.SaveAuctionResult(100, winner, time.Now()))
+	require.NoError(t, s.SaveAuctionResult(101, nil, time.Now()))
+	auctions, _, err := s.ListAuctions(store.AuctionFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, auctions, 2)
+	require.Equal(t, *winner, *auctions[0].Winner)
+	require.Nil(t, auctions[1].Winner)
+	auctions, _, err = s.ListAuctions(store.AuctionFilter{Winner: &relay1}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, auctions, 1)
+	require.ErrorIs(t, s.SaveSettlement(101, common.Hash{0x01}, time.Now()), store.ErrNotFound)
+	require.NoError(t, s.SaveSettlement(100, common.Hash{0x01}, time.Now()))
+	auctions, _, err = s.ListAuctions(store.AuctionFilter{Unsettled: true}, store.Page{})
+	require.NoError(t, err)
+	require.Empty(t, auctions)
+
+	c, err := commitment.CreateSignedCommitment(commitment.Commitment{
+		VersionedHashes: []common.Hash{{0x01}},
+		TargetBlock:     big.NewInt(100),
+		ExpiryBlock:     big.NewInt(101),
+		FeeWei:          big.NewInt(5),
+	}, pk1)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateActive))
+	require.NoError(t, s.SaveCommitment(*c, commitment.StateFulfilled))
+	fulfilled := commitment.StateFulfilled
+	commitments, _, err := s.ListCommitments(store.CommitmentFilter{State: &fulfilled}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, commitments, 1)
+	require.Equal(t, c.Hash(), commitments[0].Commitment.Hash())
+
+	deleted, err := s.DeleteCommitments(store.CommitmentFilter{State: &fulfilled})
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	deleted, err = s.DeleteBids(store.BidFilter{Relay: &relay1, ReceivedBefore: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, 5, deleted)
+}
+
+func TestSQLitePersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	s, err := store.NewSQLiteStore(path)
+	require.NoError(t, err)
+	pk, _ := crypto.GenerateKey()
+	winner := auction.MustCreateSignedBid(big.NewInt(43), big.NewInt(100), pk)
+	settledAt := time.Now()
+	require.NoError(t, s.SaveAuctionResult(100, winner, time.Now()))
+	require.NoError(t, s.SaveSettlement(100, common.Hash{0x01}, settledAt))
+	require.NoError(t, s.Close())
+
+	s, err = store.NewSQLiteStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+	auctions, _, err := s.ListAuctions(store.AuctionFilter{}, store.Page{})
+	require.NoError(t, err)
+	require.Len(t, auctions, 1)
+	require.Equal(t, *winner, *auctions[0].Winner)
+	require.Equal(t, common.Hash{0x01}, *auctions[0].SettlementTx)
+	require.True(t, settledAt.Equal(*auctions[0].SettledAt))
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/commitment"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrNotFound      = errors.New("not found")
+)
+
+// Durable or in-memory history of bids, auction results and commitments.
+// Satisfied by *MemoryStore, *PostgresStore, *LevelDBStore and *SQLiteStore.
+type Store interface {
+	SaveBid(bid auction.SignedBid, receivedAt time.Time) error
+	SaveAuctionResult(l1Block uint64, winner *auction.SignedBid, closedAt time.Time) error
+	// Marks the won auction for l1Block settled, ErrNotFound if there's none
+	SaveSettlement(l1Block uint64, tx common.Hash, settledAt time.Time) error
+	// Saving a known commitment updates its state
+	SaveCommitment(c commitment.Commitment, state commitment.State) error
+	ListBids(filter BidFilter, page Page) ([]BidRecord, string, error)
+	ListAuctions(filter AuctionFilter, page Page) ([]AuctionRecord, string, error)
+	ListCommitments(filter CommitmentFilter, page Page) ([]CommitmentRecord, string, error)
+	// Delete records matching the filter, returning how many were deleted, e.g. for retention
+	DeleteBids(filter BidFilter) (int, error)
+	DeleteCommitments(filter CommitmentFilter) (int, error)
+	// Writes a consistent point-in-time copy of history to w, while writes continue
+	Snapshot(w io.Writer) error
+	// Replaces history with a snapshot, leaving it unchanged if the snapshot is invalid
+	Restore(r io.Reader) error
+	Close() error
+}
+
+type BidRecord struct {
+	Bid        auction.SignedBid `json:"bid"`
+	ReceivedAt time.Time         `json:"receivedAt"`
+}
+
+type AuctionRecord struct {
+	L1Block uint64 `json:"l1Block"`
+	// Nil if the auction closed with no winner
+	Winner   *auction.SignedBid `json:"winner"`
+	ClosedAt time.Time          `json:"closedAt"`
+	// Settlement layer tx finalizing a won auction, nil until settled
+	SettlementTx *common.Hash `json:"settlementTx,omitempty"`
+	SettledAt    *time.Time   `json:"settledAt,omitempty"`
+}
+
+func (r AuctionRecord) Unsettled() bool {
+	return r.Winner != nil && r.SettlementTx == nil
+}
+
+type CommitmentRecord struct {
+	Commitment commitment.Commitment `json:"commitment"`
+	State      commitment.State      `json:"-"`
+	UpdatedAt  time.Time             `json:"updatedAt"`
+}
+
+// Inclusive L1 block range, To of 0 is unbounded
+type BlockRange struct {
+	From uint64
+	To   uint64
+}
+
+func (r BlockRange) Contains(block uint64) bool {
+	return block >= r.From && (r.To == 0 || block <= r.To)
+}
+
+type AuctionFilter struct {
+	Blocks BlockRange
+	// Only auctions won by this relay
+	Winner *common.Address
+	// Only won auctions not yet settled, e.g. to resume settlement after a restart
+	Unsettled bool
+}
+
+func (f AuctionFilter) matches(r AuctionRecord) bool {
+	return f.Blocks.Contains(r.L1Block) &&
+		(f.Winner == nil || (r.Winner != nil && *f.Winner == r.Winner.Address)) &&
+		(!f.Unsettled || r.Unsettled())
+}
+
+type BidFilter struct {
+	Blocks BlockRange
+	Relay  *common.Address
+	// Only bids received before this time, zero is unbounded
+	ReceivedBefore time.Time
+}
+
+func (f BidFilter) matches(r BidRecord) bool {
+	return f.Blocks.Contains(r.Bid.L1Block.Uint64()) &&
+		(f.Relay == nil || *f.Relay == r.Bid.Address) &&
+		(f.ReceivedBefore.IsZero() || r.ReceivedAt.Before(f.ReceivedBefore))
+}
+
+type CommitmentFilter struct {
+	// Range of target blocks
+	Blocks BlockRange
+	State  *commitment.State
+}
+
+func (f CommitmentFilter) matches(r CommitmentRecord) bool {
+	return f.Blocks.Contains(r.Commitment.TargetBlock.Uint64()) && (f.State == nil || *f.State == r.State)
+}
+
+// Results are ordered by L1 block, then insertion. Cursor is the NextCursor of the previous page, empty for the first.
+type Page struct {
+	Cursor string
+	Limit  int
+}
+
+// Limit clamped to (0, MaxPageLimit], DefaultPageLimit if unset
+func (p Page) EffectiveLimit() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageLimit
+	case p.Limit > MaxPageLimit:
+		return MaxPageLimit
+	}
+	return p.Limit
+}
+
+// Position of a record in result order, opaque to clients
+type cursor struct {
+	block uint64
+	seq   uint64
+}
+
+func (c cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.block, c.seq)))
+}
+
+func (c cursor) before(block, seq uint64) bool {
+	return c.block < block || (c.block == block && c.seq < seq)
+}
+
+func parseCursor(s string) (*cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	blockStr, seqStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	block, err := strconv.ParseUint(blockStr, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor{block: block, seq: seq}, nil
+}
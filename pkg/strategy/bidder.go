@@ -0,0 +1,105 @@
+package strategy
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Satisfied by *relayclient.BidderClient
+type Client interface {
+	Address() common.Address
+	Bid(ctx context.Context, amountWei *big.Int, l1Block *big.Int) (*auction.SignedBid, error)
+}
+
+// Bids in one auction at a time according to a strategy, as auction events arrive, e.g. from
+// relayclient.Handlers.OnEvent
+type Bidder struct {
+	logger   *slog.Logger
+	client   Client
+	strategy Strategy
+
+	mu sync.Mutex // Protects access to fields below
+	// Auction being bid in, nil between auctions, so leader changes from a closed auction aren't bid against
+	current *Auction
+	closing *time.Timer
+}
+
+func NewBidder(logger *slog.Logger, client Client, strategy Strategy) *Bidder {
+	return &Bidder{logger: logger, client: client, strategy: strategy}
+}
+
+// Bids as the strategy decides on an auction event. Closing triggers fire in the background, with ctx.
+func (b *Bidder) HandleEvent(ctx context.Context, ev auction.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch ev.Type {
+	case auction.EventAuctionOpened:
+		b.stopClosing()
+		a := &Auction{L1Block: ev.L1Block, OpenedAt: ev.Timestamp, ReservePriceWei: ev.ReservePriceWei}
+		if ev.ClosesAt != nil {
+			a.ClosesAt = *ev.ClosesAt
+		}
+		b.current = a
+		b.bid(ctx, TriggerOpened)
+		if before := b.strategy.Closing(); before > 0 {
+			if a.ClosesAt.IsZero() {
+				b.logger.Warn("auction didn't announce when it closes, not bidding before it does", "l1Block", a.L1Block)
+				return
+			}
+			b.closing = time.AfterFunc(time.Until(a.ClosesAt.Add(-before)), func() {
+				b.mu.Lock()
+				defer b.mu.Unlock()
+				if b.current == a {
+					b.bid(ctx, TriggerClosing)
+				}
+			})
+		}
+	case auction.EventLeaderChanged:
+		if b.current == nil || ev.Bid == nil || ev.Bid.L1Block.Cmp(b.current.L1Block) != 0 {
+			return
+		}
+		b.current.Leader = ev.Bid
+		b.current.Leading = ev.Bid.Address == b.client.Address()
+		if !b.current.Leading {
+			b.bid(ctx, TriggerOutbid)
+		}
+	case auction.EventAuctionClosed:
+		if b.current != nil && ev.L1Block.Cmp(b.current.L1Block) == 0 {
+			b.stopClosing()
+			b.current = nil
+		}
+	}
+}
+
+// Must be called with mu held
+func (b *Bidder) bid(ctx context.Context, trigger Trigger) {
+	a := b.current
+	amount, ok := b.strategy.Bid(ctx, *a, trigger)
+	if !ok {
+		if a.Leader != nil && !a.Leading {
+			b.logger.Info("strategy not outbidding leader", "l1Block", a.L1Block, "trigger", trigger, "leaderWei", a.Leader.AmountWei)
+		}
+		return
+	}
+	if _, err := b.client.Bid(ctx, amount, a.L1Block); err != nil {
+		b.logger.Warn("failed to submit bid", "l1Block", a.L1Block, "amountWei", amount, "error", err)
+		return
+	}
+	a.OwnBidWei = amount
+	b.logger.Info("submitted bid", "l1Block", a.L1Block, "trigger", trigger, "amountWei", amount)
+}
+
+// Must be called with mu held
+func (b *Bidder) stopClosing() {
+	if b.closing != nil {
+		b.closing.Stop()
+		b.closing = nil
+	}
+}
@@ -0,0 +1,101 @@
+package strategy_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io"
+	"log/slog"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/strategy"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClient struct {
+	privateKey *ecdsa.PrivateKey
+	mu         sync.Mutex
+	bids       []*auction.SignedBid
+}
+
+func (m *mockClient) Address() common.Address {
+	return crypto.PubkeyToAddress(m.privateKey.PublicKey)
+}
+
+func (m *mockClient) Bid(ctx context.Context, amountWei *big.Int, l1Block *big.Int) (*auction.SignedBid, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bid := auction.MustCreateSignedBid(amountWei, l1Block, m.privateKey)
+	m.bids = append(m.bids, bid)
+	return bid, nil
+}
+
+func (m *mockClient) submitted() []*auction.SignedBid {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*auction.SignedBid(nil), m.bids...)
+}
+
+func newClient(t *testing.T) *mockClient {
+	pk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &mockClient{privateKey: pk}
+}
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestBidderIncremental(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+	other, _ := crypto.GenerateKey()
+	b := strategy.NewBidder(discard, client, &strategy.Incremental{MaxPriceWei: big.NewInt(100), IncrementWei: big.NewInt(10), OpeningBidWei: big.NewInt(5)})
+
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(99), Bid: auction.MustCreateSignedBid(big.NewInt(20), big.NewInt(99), other)})
+	require.Empty(t, client.submitted(), "no bids before an auction opens")
+
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100)})
+	bids := client.submitted()
+	require.Len(t, bids, 1)
+	require.Equal(t, big.NewInt(5), bids[0].AmountWei)
+	require.Equal(t, big.NewInt(100), bids[0].L1Block)
+
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: bids[0]})
+	require.Len(t, client.submitted(), 1, "doesn't outbid itself")
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), other)})
+	bids = client.submitted()
+	require.Len(t, bids, 2)
+	require.Equal(t, big.NewInt(60), bids[1].AmountWei)
+
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(100), Bid: bids[1]})
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: auction.MustCreateSignedBid(big.NewInt(70), big.NewInt(100), other)})
+	require.Len(t, client.submitted(), 2, "doesn't bid in closed auctions")
+}
+
+func TestBidderSnipes(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t)
+	other, _ := crypto.GenerateKey()
+	b := strategy.NewBidder(discard, client, &strategy.Snipe{MaxPriceWei: big.NewInt(100), IncrementWei: big.NewInt(10), Before: 100 * time.Millisecond})
+
+	opened := time.Now()
+	closesAt := opened.Add(300 * time.Millisecond)
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(100), ClosesAt: &closesAt, Timestamp: opened})
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventLeaderChanged, L1Block: big.NewInt(100), Bid: auction.MustCreateSignedBid(big.NewInt(50), big.NewInt(100), other)})
+	require.Empty(t, client.submitted())
+	require.Eventually(t, func() bool { return len(client.submitted()) == 1 }, time.Second, 10*time.Millisecond)
+	require.WithinDuration(t, closesAt.Add(-100*time.Millisecond), time.Now(), 50*time.Millisecond, "bids shortly before the close")
+	require.Equal(t, big.NewInt(60), client.submitted()[0].AmountWei)
+
+	// Auctions closing early cancel the snipe
+	closesAt = time.Now().Add(200 * time.Millisecond)
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventAuctionOpened, L1Block: big.NewInt(101), ClosesAt: &closesAt, Timestamp: time.Now()})
+	b.HandleEvent(ctx, auction.Event{Type: auction.EventAuctionClosed, L1Block: big.NewInt(101)})
+	time.Sleep(200 * time.Millisecond)
+	require.Len(t, client.submitted(), 1)
+}
@@ -0,0 +1,57 @@
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Each request is made within it, so a slow endpoint doesn't hold up bidding
+const demandTimeout = 500 * time.Millisecond
+
+// Reads the value of a relay's queued blobs from an HTTP endpoint of its own, e.g. its mempool, at
+// URL?l1Block=N. The endpoint returns {"valueWei": "N"}.
+type HTTPDemand struct {
+	URL    string
+	Client *http.Client
+}
+
+func (d *HTTPDemand) ValueWei(ctx context.Context, l1Block *big.Int) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, demandTimeout)
+	defer cancel()
+	separator := "?"
+	if strings.Contains(d.URL, "?") {
+		separator = "&"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.URL+separator+"l1Block="+l1Block.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("demand endpoint returned %s", resp.Status)
+	}
+	var body struct {
+		ValueWei string `json:"valueWei"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid demand response: %w", err)
+	}
+	value, ok := new(big.Int).SetString(body.ValueWei, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid demand value %q", body.ValueWei)
+	}
+	return value, nil
+}
@@ -0,0 +1,36 @@
+package strategy_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blob-preconfs/pkg/strategy"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPDemand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("l1Block") {
+		case "100":
+			w.Write([]byte(`{"valueWei": "100000000000000000000"}`))
+		case "101":
+			w.Write([]byte(`{"valueWei": "lots"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	demand := &strategy.HTTPDemand{URL: server.URL}
+
+	value, err := demand.ValueWei(context.Background(), big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, "100000000000000000000", value.String())
+	_, err = demand.ValueWei(context.Background(), big.NewInt(101))
+	require.Error(t, err)
+	_, err = demand.ValueWei(context.Background(), big.NewInt(102))
+	require.Error(t, err)
+}
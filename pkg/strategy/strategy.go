@@ -0,0 +1,146 @@
+package strategy
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+)
+
+// What a strategy is asked to bid on
+type Trigger string
+
+const (
+	// The auction opened, with no leader yet
+	TriggerOpened Trigger = "opened"
+	// Another relay took the lead
+	TriggerOutbid Trigger = "outbid"
+	// The auction closes within the strategy's Closing duration
+	TriggerClosing Trigger = "closing"
+)
+
+// Auction being bid in, as seen from the event stream
+type Auction struct {
+	L1Block  *big.Int
+	OpenedAt time.Time
+	// Zero if the auctioneer didn't announce it
+	ClosesAt time.Time
+	// Lowest bid accepted, nil if there's none
+	ReservePriceWei *big.Int
+	// Current leading bid, nil if there's none
+	Leader *auction.SignedBid
+	// Whether Leader is this relay's bid
+	Leading bool
+	// This relay's highest bid so far, nil if it hasn't bid
+	OwnBidWei *big.Int
+}
+
+// Decides a relay's bids, driven by a Bidder. Strategies are asked for a bid when an auction opens and when
+// another relay takes the lead, and, if Closing is positive, once that long before the auction closes.
+type Strategy interface {
+	// Amount to bid, false to not bid
+	Bid(ctx context.Context, a Auction, trigger Trigger) (*big.Int, bool)
+	// How long before the auction closes to be triggered, 0 if never
+	Closing() time.Duration
+}
+
+// Bids the opening bid when an auction opens, then outbids other relays by the increment up to the max price
+type Incremental struct {
+	MaxPriceWei   *big.Int
+	IncrementWei  *big.Int
+	OpeningBidWei *big.Int
+}
+
+func (s *Incremental) Bid(ctx context.Context, a Auction, trigger Trigger) (*big.Int, bool) {
+	return incremental(a, s.OpeningBidWei, s.IncrementWei, s.MaxPriceWei)
+}
+
+func (s *Incremental) Closing() time.Duration {
+	return 0
+}
+
+// Stays out of the auction until Before its close, then outbids the leader by the increment once, up to the max
+// price, leaving other relays little time to respond. Needs auctions to announce when they close.
+type Snipe struct {
+	MaxPriceWei  *big.Int
+	IncrementWei *big.Int
+	Before       time.Duration
+}
+
+func (s *Snipe) Bid(ctx context.Context, a Auction, trigger Trigger) (*big.Int, bool) {
+	if trigger != TriggerClosing {
+		return nil, false
+	}
+	return incremental(a, s.IncrementWei, s.IncrementWei, s.MaxPriceWei)
+}
+
+func (s *Snipe) Closing() time.Duration {
+	return s.Before
+}
+
+// Value of the blobs a relay has queued for a block, e.g. from its mempool
+type Demand interface {
+	ValueWei(ctx context.Context, l1Block *big.Int) (*big.Int, error)
+}
+
+// Bids incrementally like Incremental, up to a max price derived from each block's blob demand: its value less
+// MarginPercent, so the relay keeps at least that share. Doesn't bid if the demand can't be read.
+type ValueBased struct {
+	Demand        Demand
+	MarginPercent uint64
+	IncrementWei  *big.Int
+
+	mu          sync.Mutex // Protects the max price cached for the current block
+	l1Block     *big.Int
+	maxPriceWei *big.Int
+}
+
+func (s *ValueBased) Bid(ctx context.Context, a Auction, trigger Trigger) (*big.Int, bool) {
+	maxPrice, ok := s.maxPrice(ctx, a.L1Block)
+	if !ok {
+		return nil, false
+	}
+	return incremental(a, s.IncrementWei, s.IncrementWei, maxPrice)
+}
+
+func (s *ValueBased) Closing() time.Duration {
+	return 0
+}
+
+// Read once per block
+func (s *ValueBased) maxPrice(ctx context.Context, l1Block *big.Int) (*big.Int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.l1Block != nil && s.l1Block.Cmp(l1Block) == 0 {
+		return s.maxPriceWei, s.maxPriceWei != nil
+	}
+	s.l1Block, s.maxPriceWei = l1Block, nil
+	value, err := s.Demand.ValueWei(ctx, l1Block)
+	if err != nil || value.Sign() <= 0 {
+		return nil, false
+	}
+	s.maxPriceWei = new(big.Int).Mul(value, new(big.Int).SetUint64(100-min(s.MarginPercent, 100)))
+	s.maxPriceWei.Div(s.maxPriceWei, big.NewInt(100))
+	return s.maxPriceWei, true
+}
+
+// Opening bid if there's no leader, otherwise the leader's bid plus increment, raised to the reserve price and
+// capped at the max price. Doesn't bid against itself.
+func incremental(a Auction, opening *big.Int, increment *big.Int, maxPrice *big.Int) (*big.Int, bool) {
+	if a.Leading {
+		return nil, false
+	}
+	amount := opening
+	if a.Leader != nil {
+		amount = new(big.Int).Add(a.Leader.AmountWei, increment)
+	}
+	if a.ReservePriceWei != nil && amount.Cmp(a.ReservePriceWei) < 0 {
+		amount = a.ReservePriceWei
+	}
+	if amount.Cmp(maxPrice) > 0 {
+		return nil, false
+	}
+	return amount, true
+}
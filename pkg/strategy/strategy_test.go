@@ -0,0 +1,90 @@
+package strategy_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/auction"
+	"blob-preconfs/pkg/strategy"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func leading(t *testing.T, amountWei int64) *auction.SignedBid {
+	pk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return auction.MustCreateSignedBid(big.NewInt(amountWei), big.NewInt(100), pk)
+}
+
+func TestIncremental(t *testing.T) {
+	ctx := context.Background()
+	s := &strategy.Incremental{MaxPriceWei: big.NewInt(100), IncrementWei: big.NewInt(10), OpeningBidWei: big.NewInt(5)}
+	require.Zero(t, s.Closing())
+
+	amount, ok := s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100)}, strategy.TriggerOpened)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(5), amount)
+	amount, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), ReservePriceWei: big.NewInt(20)}, strategy.TriggerOpened)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(20), amount, "opens at the reserve price")
+	amount, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), Leader: leading(t, 90)}, strategy.TriggerOutbid)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(100), amount, "bids up to max price")
+	_, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), Leader: leading(t, 91)}, strategy.TriggerOutbid)
+	require.False(t, ok)
+	_, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), Leader: leading(t, 50), Leading: true}, strategy.TriggerOutbid)
+	require.False(t, ok, "doesn't outbid itself")
+}
+
+func TestSnipe(t *testing.T) {
+	ctx := context.Background()
+	s := &strategy.Snipe{MaxPriceWei: big.NewInt(100), IncrementWei: big.NewInt(10), Before: 200 * time.Millisecond}
+	require.Equal(t, 200*time.Millisecond, s.Closing())
+
+	_, ok := s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100)}, strategy.TriggerOpened)
+	require.False(t, ok, "stays out until the close")
+	_, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), Leader: leading(t, 50)}, strategy.TriggerOutbid)
+	require.False(t, ok)
+	amount, ok := s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), Leader: leading(t, 50)}, strategy.TriggerClosing)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(60), amount)
+	amount, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100)}, strategy.TriggerClosing)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(10), amount, "bids the increment if there's no leader")
+}
+
+type mockDemand struct {
+	values map[int64]*big.Int
+	reads  int
+}
+
+func (m *mockDemand) ValueWei(ctx context.Context, l1Block *big.Int) (*big.Int, error) {
+	m.reads++
+	value, ok := m.values[l1Block.Int64()]
+	if !ok {
+		return nil, errors.New("unknown block")
+	}
+	return value, nil
+}
+
+func TestValueBased(t *testing.T) {
+	ctx := context.Background()
+	demand := &mockDemand{values: map[int64]*big.Int{100: big.NewInt(200), 101: big.NewInt(50)}}
+	s := &strategy.ValueBased{Demand: demand, MarginPercent: 25, IncrementWei: big.NewInt(10)}
+
+	amount, ok := s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), Leader: leading(t, 140)}, strategy.TriggerOutbid)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(150), amount, "bids up to the demand less the margin")
+	_, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(100), Leader: leading(t, 141)}, strategy.TriggerOutbid)
+	require.False(t, ok)
+	require.Equal(t, 1, demand.reads, "demand read once per block")
+
+	_, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(101), Leader: leading(t, 40)}, strategy.TriggerOutbid)
+	require.False(t, ok, "max price follows the block's demand")
+	_, ok = s.Bid(ctx, strategy.Auction{L1Block: big.NewInt(102)}, strategy.TriggerOpened)
+	require.False(t, ok, "doesn't bid without demand")
+}
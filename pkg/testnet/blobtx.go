@@ -0,0 +1,113 @@
+package testnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"blob-preconfs/pkg/intake"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+var (
+	ErrMainnet   = errors.New("refusing to send test txs on mainnet")
+	ErrNoCancun  = errors.New("chain doesn't run cancun")
+	ErrBlobCount = errors.New("invalid blob count")
+)
+
+// L1 node the txs are built against and sent to, e.g. *ethclient.Client
+type Client interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// Sidecar of blobs filled with random data from rng, e.g. crypto/rand.Reader, with their commitments and proofs
+func RandomSidecar(rng io.Reader, blobs int) (*types.BlobTxSidecar, error) {
+	if blobs <= 0 || blobs > intake.MaxBlobsPerBlock {
+		return nil, fmt.Errorf("%w: %d", ErrBlobCount, blobs)
+	}
+	sidecar := &types.BlobTxSidecar{}
+	for i := 0; i < blobs; i++ {
+		var blob kzg4844.Blob
+		if _, err := io.ReadFull(rng, blob[:]); err != nil {
+			return nil, err
+		}
+		// Field elements are big endian and must be below the BLS modulus, clearing the top byte keeps them so
+		for j := 0; j < len(blob); j += 32 {
+			blob[j] = 0
+		}
+		commitment, err := kzg4844.BlobToCommitment(blob)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := kzg4844.ComputeBlobProof(blob, commitment)
+		if err != nil {
+			return nil, err
+		}
+		sidecar.Blobs = append(sidecar.Blobs, blob)
+		sidecar.Commitments = append(sidecar.Commitments, commitment)
+		sidecar.Proofs = append(sidecar.Proofs, proof)
+	}
+	return sidecar, nil
+}
+
+// Signs a blob tx carrying the sidecar, sent by key to itself, with fee caps at twice the current base and blob
+// fees so it stays includable for a few blocks. Fails on mainnet.
+func NewBlobTx(ctx context.Context, client Client, key *ecdsa.PrivateKey, sidecar *types.BlobTxSidecar) (*types.Transaction, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chainID.Cmp(params.MainnetChainConfig.ChainID) == 0 {
+		return nil, ErrMainnet
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if head.BaseFee == nil || head.ExcessBlobGas == nil {
+		return nil, ErrNoCancun
+	}
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	blobFeeCap := new(big.Int).Mul(eip4844.CalcBlobFee(*head.ExcessBlobGas), big.NewInt(2))
+	return types.SignNewTx(key, types.NewCancunSigner(chainID), &types.BlobTx{
+		ChainID:    uint256.MustFromBig(chainID),
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(tip),
+		GasFeeCap:  uint256.MustFromBig(feeCap),
+		Gas:        params.TxGas,
+		To:         from,
+		Value:      uint256.NewInt(0),
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+}
+
+// Preconf request for the tx's blobs to land in targetBlock, signed by key, to submit to the intake pool before
+// sending the tx
+func PreconfRequest(tx *types.Transaction, targetBlock *big.Int, maxFeeWei *big.Int, key *ecdsa.PrivateKey) (*intake.PreconfRequest, error) {
+	return intake.CreateSignedBundleRequest(tx.BlobHashes(), targetBlock, maxFeeWei, key)
+}
@@ -0,0 +1,92 @@
+package testnet_test
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/testnet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClient struct {
+	chainID *big.Int
+	head    *types.Header
+	sent    []*types.Transaction
+}
+
+func newMockClient(chainID int64) *mockClient {
+	excessBlobGas := uint64(0)
+	return &mockClient{chainID: big.NewInt(chainID), head: &types.Header{BaseFee: big.NewInt(params.GWei), ExcessBlobGas: &excessBlobGas}}
+}
+
+func (c *mockClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.chainID, nil
+}
+
+func (c *mockClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 3, nil
+}
+
+func (c *mockClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.head, nil
+}
+
+func (c *mockClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(params.GWei), nil
+}
+
+func (c *mockClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func TestRandomSidecar(t *testing.T) {
+	sidecar, err := testnet.RandomSidecar(rand.Reader, 2)
+	require.NoError(t, err)
+	require.Len(t, sidecar.Blobs, 2)
+	require.NotEqual(t, sidecar.Blobs[0], sidecar.Blobs[1])
+	for i, blob := range sidecar.Blobs {
+		require.NoError(t, kzg4844.VerifyBlobProof(blob, sidecar.Commitments[i], sidecar.Proofs[i]))
+	}
+
+	_, err = testnet.RandomSidecar(rand.Reader, 0)
+	require.ErrorIs(t, err, testnet.ErrBlobCount)
+}
+
+func TestNewBlobTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sidecar, err := testnet.RandomSidecar(rand.Reader, 1)
+	require.NoError(t, err)
+	client := newMockClient(17000)
+
+	tx, err := testnet.NewBlobTx(context.Background(), client, key, sidecar)
+	require.NoError(t, err)
+	require.Equal(t, uint8(types.BlobTxType), tx.Type())
+	require.Equal(t, uint64(3), tx.Nonce())
+	require.Equal(t, sidecar.BlobHashes(), tx.BlobHashes())
+	require.Equal(t, sidecar, tx.BlobTxSidecar())
+	require.Equal(t, big.NewInt(3*params.GWei), tx.GasFeeCap())
+	sender, err := types.Sender(types.NewCancunSigner(client.chainID), tx)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender)
+
+	req, err := testnet.PreconfRequest(tx, big.NewInt(100), big.NewInt(1e9), key)
+	require.NoError(t, err)
+	require.True(t, req.Verify())
+	require.True(t, req.Atomic)
+	require.Equal(t, tx.BlobHashes(), req.VersionedHashes)
+
+	_, err = testnet.NewBlobTx(context.Background(), newMockClient(1), key, sidecar)
+	require.ErrorIs(t, err, testnet.ErrMainnet)
+	client.head.ExcessBlobGas = nil
+	_, err = testnet.NewBlobTx(context.Background(), client, key, sidecar)
+	require.ErrorIs(t, err, testnet.ErrNoCancun)
+}
@@ -0,0 +1,75 @@
+package testnet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Funds test accounts from an account holding testnet ether, e.g. topped up from a public Holesky or Sepolia
+// faucet, so each integrator doesn't need to
+type Faucet struct {
+	logger *slog.Logger
+	client Client
+	key    *ecdsa.PrivateKey
+}
+
+func NewFaucet(logger *slog.Logger, client Client, key *ecdsa.PrivateKey) *Faucet {
+	return &Faucet{logger: logger, client: client, key: key}
+}
+
+// Sends wei to each address, returning the transfers once sent. Nonces are consecutive, so the transfers don't
+// wait on each other's inclusion. Fails on mainnet.
+func (f *Faucet) Fund(ctx context.Context, addresses []common.Address, wei *big.Int) ([]*types.Transaction, error) {
+	chainID, err := f.client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chainID.Cmp(params.MainnetChainConfig.ChainID) == 0 {
+		return nil, ErrMainnet
+	}
+	from := crypto.PubkeyToAddress(f.key.PublicKey)
+	nonce, err := f.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	head, err := f.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := f.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), tip)
+	signer := types.LatestSignerForChainID(chainID)
+	txs := make([]*types.Transaction, 0, len(addresses))
+	for i, address := range addresses {
+		to := address
+		tx, err := types.SignNewTx(f.key, signer, &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce + uint64(i),
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       params.TxGas,
+			To:        &to,
+			Value:     wei,
+		})
+		if err != nil {
+			return txs, err
+		}
+		if err := f.client.SendTransaction(ctx, tx); err != nil {
+			return txs, fmt.Errorf("failed to fund %s: %w", address.Hex(), err)
+		}
+		f.logger.Info("test account funded", "address", address, "wei", wei, "tx", tx.Hash())
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
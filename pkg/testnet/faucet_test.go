@@ -0,0 +1,33 @@
+package testnet_test
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"blob-preconfs/pkg/testnet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFund(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	client := newMockClient(11155111)
+	faucet := testnet.NewFaucet(slog.Default(), client, key)
+	addresses := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+
+	txs, err := faucet.Fund(context.Background(), addresses, big.NewInt(1e18))
+	require.NoError(t, err)
+	require.Equal(t, txs, client.sent)
+	for i, tx := range txs {
+		require.Equal(t, addresses[i], *tx.To())
+		require.Equal(t, big.NewInt(1e18), tx.Value())
+		require.Equal(t, uint64(3+i), tx.Nonce(), "consecutive nonces")
+	}
+
+	_, err = testnet.NewFaucet(slog.Default(), newMockClient(1), key).Fund(context.Background(), addresses, big.NewInt(1e18))
+	require.ErrorIs(t, err, testnet.ErrMainnet)
+}
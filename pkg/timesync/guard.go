@@ -0,0 +1,227 @@
+package timesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrDrift = errors.New("clock drifted")
+
+// What drift was measured against
+type Source string
+
+const (
+	SourceNTP Source = "ntp"
+	// L1 block timestamps, which are slot boundaries, against when the heads were first seen
+	SourceSlot Source = "slot"
+	// Wall clock steps against the monotonic clock between checks
+	SourceStep Source = "step"
+)
+
+// Heads whose ages are kept, the youngest estimating drift from slot boundaries
+const headWindow = 32
+
+type Config struct {
+	// Auctions are refused while NTP or slot drift exceeds it
+	MaxDrift time.Duration
+	// SNTP server, e.g. pool.ntp.org:123, queried every Interval. NTP drift isn't measured if empty.
+	NTPServer string
+	// Between NTP queries and clock step checks, 30s if 0
+	Interval time.Duration
+}
+
+// Satisfied by *ethclient.Client
+type HeaderSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Alerted when a source's drift first exceeds the max, e.g. *alerting.Notifier
+type Alerter interface {
+	ClockDrifted(source string, drift time.Duration, max time.Duration)
+}
+
+// Observes measured drift, e.g. *metrics.Metrics
+type Metrics interface {
+	ObserveClockDrift(source string, drift time.Duration)
+}
+
+// Checks the local clock auction windows run on against NTP, L1 slot boundaries and the monotonic clock.
+// Satisfies listener.ClockGuard.
+type Guard struct {
+	logger  *slog.Logger
+	config  Config
+	headers HeaderSource
+	alerter Alerter
+	metrics Metrics
+	heads   chan observedHead
+
+	mu sync.Mutex // Protects access to fields below
+	// Latest drift by source, positive if the local clock is ahead
+	drift map[Source]time.Duration
+	// Ages of the latest heads when first seen, oldest first
+	ages []time.Duration
+}
+
+type observedHead struct {
+	block  uint64
+	seenAt time.Time
+}
+
+func NewGuard(logger *slog.Logger, config Config, headers HeaderSource) *Guard {
+	if config.Interval <= 0 {
+		config.Interval = 30 * time.Second
+	}
+	return &Guard{
+		logger:  logger,
+		config:  config,
+		headers: headers,
+		heads:   make(chan observedHead, headWindow),
+		drift:   make(map[Source]time.Duration),
+	}
+}
+
+// Drift beyond the max is alerted, if set before the guard starts
+func (g *Guard) SetAlerter(alerter Alerter) {
+	g.alerter = alerter
+}
+
+// Measured drift is observed, if set before the guard starts
+func (g *Guard) SetMetrics(metrics Metrics) {
+	g.metrics = metrics
+}
+
+// Checks the clock every Interval, and heads' timestamps as they're observed, until ctx is done
+func (g *Guard) Start(ctx context.Context) {
+	go g.run(ctx)
+}
+
+// To satisfy listener.ClockGuard. The head's timestamp is fetched asynchronously, and the head dropped if the
+// guard is behind.
+func (g *Guard) ObserveHead(block uint64, seenAt time.Time) {
+	select {
+	case g.heads <- observedHead{block: block, seenAt: seenAt}:
+	default:
+	}
+}
+
+// To satisfy listener.ClockGuard. Fails with ErrDrift while NTP or slot drift exceeds the max. Clock steps are
+// only alerted, as the stepped clock may well be right, which NTP and slot drift tell.
+func (g *Guard) Check() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, source := range []Source{SourceNTP, SourceSlot} {
+		if drift, ok := g.drift[source]; ok && abs(drift) > g.config.MaxDrift {
+			return fmt.Errorf("%w: %s drift %s exceeds %s", ErrDrift, source, drift, g.config.MaxDrift)
+		}
+	}
+	return nil
+}
+
+// Latest drift measured by each source, positive if the local clock is ahead
+func (g *Guard) Drift() map[Source]time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	drift := make(map[Source]time.Duration, len(g.drift))
+	for source, d := range g.drift {
+		drift[source] = d
+	}
+	return drift
+}
+
+func (g *Guard) run(ctx context.Context) {
+	ticker := time.NewTicker(g.config.Interval)
+	defer ticker.Stop()
+	last := time.Now()
+	g.checkNTP(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case head := <-g.heads:
+			g.checkHead(ctx, head)
+		case <-ticker.C:
+			now := time.Now()
+			g.checkStep(last, now)
+			last = now
+			g.checkNTP(ctx)
+		}
+	}
+}
+
+func (g *Guard) checkNTP(ctx context.Context) {
+	if g.config.NTPServer == "" {
+		return
+	}
+	offset, err := queryNTP(ctx, g.config.NTPServer)
+	if err != nil {
+		g.logger.Warn("failed to query ntp server", "server", g.config.NTPServer, "error", err)
+		return
+	}
+	g.record(SourceNTP, offset)
+}
+
+// A block's timestamp is the start of its slot, and it's first seen at least its propagation delay later. The
+// youngest of the latest heads bounds how far ahead the local clock is, or tells how far behind it is if negative.
+func (g *Guard) checkHead(ctx context.Context, head observedHead) {
+	header, err := g.headers.HeaderByNumber(ctx, new(big.Int).SetUint64(head.block))
+	if err != nil {
+		g.logger.Debug("failed to get head for clock check", "blockNumber", head.block, "error", err)
+		return
+	}
+	age := head.seenAt.Sub(time.Unix(int64(header.Time), 0))
+	g.mu.Lock()
+	g.ages = append(g.ages, age)
+	if len(g.ages) > headWindow {
+		g.ages = g.ages[1:]
+	}
+	drift := slices.Min(g.ages)
+	g.mu.Unlock()
+	g.record(SourceSlot, drift)
+}
+
+// Wall clock time elapsed beyond the monotonic clock's, e.g. from an NTP step or an operator setting the time.
+// Heads seen before a step beyond the max are forgotten, as their ages are off by the step.
+func (g *Guard) checkStep(last time.Time, now time.Time) {
+	step := now.Round(0).Sub(last.Round(0)) - now.Sub(last)
+	if abs(step) > g.config.MaxDrift {
+		g.mu.Lock()
+		g.ages = nil
+		g.mu.Unlock()
+	}
+	g.record(SourceStep, step)
+}
+
+func (g *Guard) record(source Source, drift time.Duration) {
+	g.mu.Lock()
+	previous, measured := g.drift[source]
+	g.drift[source] = drift
+	g.mu.Unlock()
+	if g.metrics != nil {
+		g.metrics.ObserveClockDrift(string(source), drift)
+	}
+	exceeded := abs(drift) > g.config.MaxDrift
+	switch {
+	case exceeded && (source == SourceStep || !measured || abs(previous) <= g.config.MaxDrift):
+		g.logger.Error("clock drift exceeds max", "source", source, "drift", drift, "max", g.config.MaxDrift)
+		if g.alerter != nil {
+			g.alerter.ClockDrifted(string(source), drift, g.config.MaxDrift)
+		}
+	case !exceeded && measured && abs(previous) > g.config.MaxDrift && source != SourceStep:
+		g.logger.Info("clock drift back within max", "source", source, "drift", drift, "max", g.config.MaxDrift)
+	}
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,147 @@
+package timesync_test
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/timesync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// Answers SNTP queries with its clock offset from the local one
+func startNTPServer(t *testing.T, offset time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		req := make([]byte, 48)
+		for {
+			_, addr, err := conn.ReadFrom(req)
+			if err != nil {
+				return
+			}
+			resp := make([]byte, 48)
+			resp[0] = 0x24 // Version 4, server mode
+			resp[1] = 1
+			now := time.Now().Add(offset)
+			putNTPTime(resp[32:40], now)
+			putNTPTime(resp[40:48], now)
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func putNTPTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b[0:4], uint32(t.Unix()+2208988800))
+	binary.BigEndian.PutUint32(b[4:8], uint32((uint64(t.Nanosecond())<<32)/uint64(time.Second)))
+}
+
+type mockHeaderSource struct {
+	mu    sync.Mutex
+	times map[uint64]uint64
+}
+
+func (m *mockHeaderSource) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &types.Header{Number: number, Time: m.times[number.Uint64()]}, nil
+}
+
+type mockAlerter struct {
+	mu      sync.Mutex
+	sources []string
+}
+
+func (m *mockAlerter) ClockDrifted(source string, drift time.Duration, max time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sources = append(m.sources, source)
+}
+
+func (m *mockAlerter) alerted() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.sources...)
+}
+
+func TestNTPDrift(t *testing.T) {
+	for name, test := range map[string]struct {
+		serverOffset time.Duration
+		drifted      bool
+	}{
+		"in sync":      {serverOffset: 0},
+		"local ahead":  {serverOffset: -5 * time.Second, drifted: true},
+		"local behind": {serverOffset: 5 * time.Second, drifted: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			guard := timesync.NewGuard(slog.Default(), timesync.Config{
+				MaxDrift:  2 * time.Second,
+				NTPServer: startNTPServer(t, test.serverOffset),
+			}, &mockHeaderSource{})
+			alerter := &mockAlerter{}
+			guard.SetAlerter(alerter)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			guard.Start(ctx)
+			require.Eventually(t, func() bool {
+				_, ok := guard.Drift()[timesync.SourceNTP]
+				return ok
+			}, time.Second, 10*time.Millisecond)
+			drift := guard.Drift()[timesync.SourceNTP]
+			require.InDelta(t, float64(-test.serverOffset), float64(drift), float64(100*time.Millisecond))
+			if test.drifted {
+				require.ErrorIs(t, guard.Check(), timesync.ErrDrift)
+				require.Equal(t, []string{"ntp"}, alerter.alerted())
+			} else {
+				require.NoError(t, guard.Check())
+				require.Empty(t, alerter.alerted())
+			}
+		})
+	}
+}
+
+func TestSlotDrift(t *testing.T) {
+	headers := &mockHeaderSource{times: map[uint64]uint64{}}
+	guard := timesync.NewGuard(slog.Default(), timesync.Config{MaxDrift: 2 * time.Second}, headers)
+	alerter := &mockAlerter{}
+	guard.SetAlerter(alerter)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	guard.Start(ctx)
+
+	observe := func(block uint64, age time.Duration) {
+		seenAt := time.Now().Truncate(time.Second)
+		headers.mu.Lock()
+		headers.times[block] = uint64(seenAt.Add(-age).Unix())
+		headers.mu.Unlock()
+		guard.ObserveHead(block, seenAt)
+		require.Eventually(t, func() bool {
+			_, ok := guard.Drift()[timesync.SourceSlot]
+			return ok
+		}, time.Second, 10*time.Millisecond)
+	}
+	// Late heads, e.g. after a missed slot, don't read as drift while a recent one was seen in time
+	observe(100, time.Second)
+	observe(101, 13*time.Second)
+	require.Eventually(t, func() bool {
+		return guard.Drift()[timesync.SourceSlot] == time.Second
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, guard.Check())
+
+	// Seen before the slot started, so the local clock is behind
+	observe(102, -3*time.Second)
+	require.Eventually(t, func() bool {
+		return guard.Drift()[timesync.SourceSlot] == -3*time.Second
+	}, time.Second, 10*time.Millisecond)
+	require.ErrorIs(t, guard.Check(), timesync.ErrDrift)
+	require.Equal(t, []string{"slot"}, alerter.alerted())
+}
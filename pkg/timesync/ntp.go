@@ -0,0 +1,68 @@
+package timesync
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// Seconds from the NTP epoch, 1900, to the Unix epoch
+	ntpEpochOffset = 2208988800
+	ntpPacketSize  = 48
+	ntpTimeout     = 5 * time.Second
+)
+
+// Offset of the local clock from the SNTP server's, positive if local is ahead (RFC 4330)
+func queryNTP(ctx context.Context, server string) (time.Duration, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(ntpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	req := make([]byte, ntpPacketSize)
+	// Leap indicator 0, version 4, client mode
+	req[0] = 0x23
+	sentAt := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, ntpPacketSize)
+	n, err := conn.Read(resp)
+	receivedAt := time.Now()
+	if err != nil {
+		return 0, err
+	}
+	if n < ntpPacketSize {
+		return 0, fmt.Errorf("short ntp response of %d bytes", n)
+	}
+	if mode := resp[0] & 0x7; mode != 4 {
+		return 0, fmt.Errorf("unexpected ntp mode %d", mode)
+	}
+	if stratum := resp[1]; stratum == 0 {
+		return 0, fmt.Errorf("ntp server sent kiss of death %q", resp[12:16])
+	}
+	serverReceived := ntpTime(resp[32:40])
+	serverSent := ntpTime(resp[40:48])
+	// Wall clock readings, as the server's can't be compared to the monotonic clock
+	offset := (serverReceived.Sub(sentAt.Round(0)) + serverSent.Sub(receivedAt.Round(0))) / 2
+	return -offset, nil
+}
+
+func ntpTime(b []byte) time.Time {
+	seconds := binary.BigEndian.Uint32(b[0:4])
+	fraction := binary.BigEndian.Uint32(b[4:8])
+	nanos := (uint64(fraction) * uint64(time.Second)) >> 32
+	return time.Unix(int64(seconds)-ntpEpochOffset, int64(nanos))
+}
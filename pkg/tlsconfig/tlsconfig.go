@@ -0,0 +1,122 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var ErrInvalidConfig = errors.New("invalid tls config")
+
+type Config struct {
+	// Static certificate, mutually exclusive with ACME
+	CertFile string
+	KeyFile  string
+	// Domains to obtain certificates for from an ACME CA (Let's Encrypt by default), via the TLS-ALPN-01 challenge
+	ACMEDomains  []string
+	ACMECacheDir string
+	ACMEEmail    string
+	// Client certificates signed by these CAs are verified if presented, and required if RequireClientCert is set
+	ClientCAFile      string
+	RequireClientCert bool
+}
+
+// Whether TLS is configured at all, servers run plaintext otherwise
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || len(c.ACMEDomains) > 0
+}
+
+// Returns nil if TLS isn't enabled
+func (c Config) Build() (*tls.Config, error) {
+	if !c.Enabled() {
+		if c.ClientCAFile != "" || c.RequireClientCert {
+			return nil, fmt.Errorf("%w: client certificate verification requires a server certificate", ErrInvalidConfig)
+		}
+		return nil, nil
+	}
+	var tlsConfig *tls.Config
+	switch {
+	case len(c.ACMEDomains) > 0 && (c.CertFile != "" || c.KeyFile != ""):
+		return nil, fmt.Errorf("%w: cert files and ACME are mutually exclusive", ErrInvalidConfig)
+	case len(c.ACMEDomains) > 0:
+		if c.ACMECacheDir == "" {
+			return nil, fmt.Errorf("%w: ACME requires a cache dir", ErrInvalidConfig)
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(c.ACMEDomains...),
+			Cache:      autocert.DirCache(c.ACMECacheDir),
+			Email:      c.ACMEEmail,
+		}
+		tlsConfig = manager.TLSConfig()
+	default:
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	if c.ClientCAFile != "" {
+		pool, err := loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if c.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if c.RequireClientCert {
+		return nil, fmt.Errorf("%w: requiring client certificates needs a client CA file", ErrInvalidConfig)
+	}
+	return tlsConfig, nil
+}
+
+type ClientConfig struct {
+	// CAs to verify the server with, the system roots if empty
+	CAFile string
+	// Client certificate for servers requiring mutual TLS
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+func (c ClientConfig) Build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: no certificates in %s", ErrInvalidConfig, file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,136 @@
+package tlsconfig_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"blob-preconfs/pkg/tlsconfig"
+
+	"github.com/stretchr/testify/require"
+)
+
+type certFiles struct {
+	cert string
+	key  string
+}
+
+// Writes a certificate signed by parent (self-signed if nil) to dir
+func writeCert(t *testing.T, dir, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (certFiles, *x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	files := certFiles{cert: filepath.Join(dir, name+".crt"), key: filepath.Join(dir, name+".key")}
+	require.NoError(t, os.WriteFile(files.cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(files.key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return files, cert, key
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	server, _, _ := writeCert(t, dir, "server", false, nil, nil)
+
+	tlsConfig, err := tlsconfig.Config{}.Build()
+	require.NoError(t, err)
+	require.Nil(t, tlsConfig)
+
+	tlsConfig, err = tlsconfig.Config{CertFile: server.cert, KeyFile: server.key}.Build()
+	require.NoError(t, err)
+	require.Len(t, tlsConfig.Certificates, 1)
+	require.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+
+	for _, invalid := range []tlsconfig.Config{
+		{RequireClientCert: true},
+		{CertFile: server.cert, KeyFile: server.key, RequireClientCert: true},
+		{CertFile: server.cert, KeyFile: server.key, ACMEDomains: []string{"example.com"}},
+		{ACMEDomains: []string{"example.com"}},
+		{CertFile: server.cert},
+		{CertFile: server.cert, KeyFile: server.key, ClientCAFile: server.key},
+	} {
+		_, err := invalid.Build()
+		require.ErrorIs(t, err, tlsconfig.ErrInvalidConfig, "%+v", invalid)
+	}
+
+	tlsConfig, err = tlsconfig.Config{ACMEDomains: []string{"example.com"}, ACMECacheDir: dir}.Build()
+	require.NoError(t, err)
+	require.NotNil(t, tlsConfig.GetCertificate)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caCert, caKey := writeCert(t, dir, "ca", true, nil, nil)
+	server, _, _ := writeCert(t, dir, "server", false, caCert, caKey)
+	client, _, _ := writeCert(t, dir, "client", false, caCert, caKey)
+	untrusted, _, _ := writeCert(t, dir, "untrusted", false, nil, nil)
+
+	serverConfig, err := tlsconfig.Config{
+		CertFile:          server.cert,
+		KeyFile:           server.key,
+		ClientCAFile:      ca.cert,
+		RequireClientCert: true,
+	}.Build()
+	require.NoError(t, err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte("ok"))
+			}()
+		}
+	}()
+
+	dial := func(clientConfig tlsconfig.ClientConfig) error {
+		tlsConfig, err := clientConfig.Build()
+		require.NoError(t, err)
+		conn, err := tls.Dial("tcp", listener.Addr().String(), tlsConfig)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		_, err = io.ReadAll(conn)
+		return err
+	}
+	require.NoError(t, dial(tlsconfig.ClientConfig{CAFile: ca.cert, CertFile: client.cert, KeyFile: client.key}))
+	require.Error(t, dial(tlsconfig.ClientConfig{CAFile: ca.cert}), "no client certificate")
+	require.Error(t, dial(tlsconfig.ClientConfig{CAFile: ca.cert, CertFile: untrusted.cert, KeyFile: untrusted.key}), "untrusted client certificate")
+	require.Error(t, dial(tlsconfig.ClientConfig{CertFile: client.cert, KeyFile: client.key}), "untrusted server certificate")
+}
@@ -0,0 +1,30 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// version subcommand printing the build, shared by the auctioneer and bidder CLIs
+func NewCommand() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version, commit and build time",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := Get()
+			if !asJSON {
+				fmt.Fprintln(cmd.OutOrStdout(), info)
+				return nil
+			}
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(info)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print as JSON")
+	return cmd
+}
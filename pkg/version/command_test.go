@@ -0,0 +1,27 @@
+package version_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"blob-preconfs/pkg/version"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	var out bytes.Buffer
+	cmd := version.NewCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs(nil)
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, version.Get().String()+"\n", out.String())
+
+	out.Reset()
+	cmd.SetArgs([]string{"--json"})
+	require.NoError(t, cmd.Execute())
+	var info version.Info
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info))
+	require.Equal(t, version.Get(), info)
+}
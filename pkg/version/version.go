@@ -0,0 +1,53 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X blob-preconfs/pkg/version.Version=v1.2.0 -X blob-preconfs/pkg/version.Commit=$(git rev-parse HEAD) -X blob-preconfs/pkg/version.BuildTime=$(date -u +%FT%TZ)"
+//
+// Commit and BuildTime fall back to the VCS info go build embeds, if unset.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Build that's running, recorded with settlements for provenance
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+func (i Info) String() string {
+	s := i.Version
+	if i.Commit != "" {
+		s += " (" + i.Commit
+		if i.BuildTime != "" {
+			s += ", built " + i.BuildTime
+		}
+		s += ")"
+	}
+	return fmt.Sprintf("%s %s", s, i.GoVersion)
+}
@@ -0,0 +1,19 @@
+package version_test
+
+import (
+	"runtime"
+	"testing"
+
+	"blob-preconfs/pkg/version"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	defer func(v, c, b string) { version.Version, version.Commit, version.BuildTime = v, c, b }(version.Version, version.Commit, version.BuildTime)
+	version.Version, version.Commit, version.BuildTime = "v1.2.0", "abc123", "2024-01-02T03:04:05Z"
+	info := version.Get()
+	require.Equal(t, version.Info{Version: "v1.2.0", Commit: "abc123", BuildTime: "2024-01-02T03:04:05Z", GoVersion: runtime.Version()}, info)
+	require.Equal(t, "v1.2.0 (abc123, built 2024-01-02T03:04:05Z) "+runtime.Version(), info.String())
+	require.Equal(t, "dev "+runtime.Version(), version.Info{Version: "dev", GoVersion: runtime.Version()}.String())
+}